@@ -14,9 +14,13 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
+	"github.com/zenteam/nextevent-go/internal/config"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/email"
 	"github.com/zenteam/nextevent-go/internal/infrastructure/repositories"
+	infraServices "github.com/zenteam/nextevent-go/internal/infrastructure/services"
 	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
 	"github.com/zenteam/nextevent-go/internal/jobs"
+	"github.com/zenteam/nextevent-go/pkg/storage"
 )
 
 func main() {
@@ -29,6 +33,12 @@ func main() {
 
 	logger.Info("Starting NextEvent Worker Service")
 
+	// Load the configuration shared with the API
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Failed to load configuration", zap.Error(err))
+	}
+
 	// Initialize database connection with hardcoded config for now
 	db, err := initDatabase(logger)
 	if err != nil {
@@ -45,20 +55,28 @@ func main() {
 	// Initialize repositories
 	newsRepo := repositories.NewGormNewsRepository(db)
 
-	// Initialize WeChat workflow (optional - only if WeChat is enabled)
+	// Initialize the WeChat client from the same configuration as the API, which queues follower
+	// syncs, template campaigns and WeChat notifications whenever Redis is configured
+	publicAccount := cfg.WeChat.PublicAccount
+	wechatConfig := &wechat.Config{
+		AppID:            publicAccount.AppID,
+		AppSecret:        publicAccount.AppSecret,
+		Token:            publicAccount.Token,
+		EncodingAESKey:   publicAccount.AESKey,
+		VerifySignature:  true,
+		CacheAccessToken: true,
+		APIBaseURL:       publicAccount.APIBaseURL,
+	}
+	wechatService := infraServices.NewWeChatServiceImpl(
+		wechat.NewService(wechatConfig, redisClient, logger),
+		repositories.NewGormWeChatUserRepository(db),
+		db,
+		logger,
+	)
+
+	// Initialize WeChat news publishing (optional - only if WeChat is enabled)
 	var newsPublisher *wechat.NewsPublisher
 	if getEnv("WECHAT_ENABLED", "false") == "true" {
-		wechatConfig := &wechat.Config{
-			AppID:                 getEnv("WECHAT_APP_ID", ""),
-			AppSecret:             getEnv("WECHAT_APP_SECRET", ""),
-			Token:                 getEnv("WECHAT_TOKEN", ""),
-			EncodingAESKey:        getEnv("WECHAT_ENCODING_AES_KEY", ""),
-			VerifySignature:       true,
-			CacheAccessToken:      true,
-			AccessTokenCacheKey:   "wechat:access_token",
-			AccessTokenExpireTime: 7200 * time.Second,
-		}
-
 		workflowConfig := &wechat.WorkflowConfig{
 			HostURL:         getEnv("HOST_URL", "http://localhost:8080"),
 			WechatServerURL: getEnv("WECHAT_SERVER_URL", "http://localhost:8080"),
@@ -66,10 +84,20 @@ func main() {
 			WeChat:          *wechatConfig,
 		}
 
+		// Covers and article images go through the media library, so each image is uploaded once
+		mediaService := infraServices.NewWeChatMediaService(
+			repositories.NewGormWeChatMediaRepository(db),
+			repositories.NewGormSiteImageRepository(db),
+			wechatService,
+			storage.NewLocalStorage("./uploads", "/uploads"),
+			logger,
+		)
+
 		workflow, err := wechat.NewWorkflow(db, redisClient, workflowConfig, logger)
 		if err != nil {
 			logger.Warn("Failed to initialize WeChat workflow, continuing without WeChat integration", zap.Error(err))
 		} else {
+			workflow.SetMediaLibrary(mediaService)
 			newsPublisher = workflow.GetNewsPublisher()
 			logger.Info("WeChat integration initialized successfully")
 		}
@@ -85,8 +113,88 @@ func main() {
 	// Initialize worker server
 	workerServer := jobs.NewWorkerServer(redisClient, jobHandler, logger)
 
+	// Survey exports and respondent uploads are kept in private storage
+	privateStorage := storage.NewLocalStorage(cfg.Server.PrivateStoragePath, "")
+
+	// Initialize survey export processing
+	surveyExportService := infraServices.NewSurveyExportService(
+		repositories.NewGormSurveyRepository(db),
+		repositories.NewGormSurveyQuestionRepository(db),
+		repositories.NewGormSurveyVersionRepository(db),
+		repositories.NewGormSurveyResponseRepository(db),
+		repositories.NewGormSurveyExportRepository(db),
+		privateStorage,
+		jobScheduler,
+		logger,
+	)
+
+	// Initialize survey notifications, delivered by the worker and retried by the cron scheduler
+	emailSender := email.NewSMTPSender(cfg.Email, logger)
+	surveyNotificationService := infraServices.NewSurveyNotificationService(
+		repositories.NewGormSurveyNotificationRepository(db),
+		repositories.NewGormSurveyNotificationDeliveryRepository(db),
+		repositories.NewGormSurveyRepository(db),
+		repositories.NewGormSurveyResponseRepository(db),
+		infraServices.NewSurveyNotificationChannels(emailSender, wechatService, publicAccount.SurveyNotificationTemplateID),
+		jobScheduler,
+		logger,
+	)
+	jobs.NewSurveyJobHandler(surveyExportService, surveyNotificationService, logger).Register(workerServer)
+
+	// Initialize the WeChat follower sync, started daily by the cron scheduler, and the template
+	// campaigns, sent batch by batch by the worker
+	followerSyncService := infraServices.NewWeChatFollowerSyncService(
+		repositories.NewGormWeChatFollowerSyncRepository(db),
+		repositories.NewGormWeChatUserRepository(db),
+		wechatService,
+		jobScheduler,
+		logger,
+	)
+	campaignService := infraServices.NewWeChatTemplateCampaignService(
+		repositories.NewGormWeChatTemplateCampaignRepository(db),
+		repositories.NewGormWeChatTemplateDeliveryRepository(db),
+		repositories.NewGormEventAttendeeRepository(db),
+		repositories.NewGormSiteEventRepository(db),
+		repositories.NewGormSurveyRepository(db),
+		repositories.NewGormSurveyInvitationRepository(db),
+		repositories.NewGormWeChatUserRepository(db),
+		wechatService,
+		jobScheduler,
+		infraServices.DefaultWeChatTemplateCampaignConfig(),
+		logger,
+	)
+	jobs.NewWeChatJobHandler(followerSyncService, campaignService, logger).Register(workerServer)
+
+	// Initialize survey analytics, refreshed by the cron scheduler
+	surveyAnalyticsService := infraServices.NewSurveyAnalyticsService(
+		repositories.NewGormSurveyRepository(db),
+		repositories.NewGormSurveyResponseRepository(db),
+		repositories.NewGormSurveyAnalyticsRepository(db),
+		repositories.NewGormSurveyVersionRepository(db),
+		logger,
+	)
+
+	// Initialize survey invitations, whose reminders are sent by the cron scheduler
+	surveyInvitationService := infraServices.NewSurveyInvitationService(
+		repositories.NewGormSurveyInvitationRepository(db),
+		repositories.NewGormSurveyRepository(db),
+		repositories.NewGormWeChatUserRepository(db),
+		wechatService,
+		emailSender,
+		infraServices.DefaultSurveyInvitationConfig(cfg.Server.BaseURL, publicAccount.SurveyInvitationTemplateID),
+		logger,
+	)
+
+	// Initialize the cleanup of files uploaded for abandoned survey responses
+	surveyFileService := infraServices.NewSurveyFileService(
+		repositories.NewGormSurveyResponseRepository(db),
+		repositories.NewGormSurveySessionRepository(db),
+		privateStorage,
+		logger,
+	)
+
 	// Initialize cron scheduler
-	cronScheduler := jobs.NewCronScheduler(jobScheduler, newsRepo, newsPublisher, logger)
+	cronScheduler := jobs.NewCronScheduler(jobScheduler, newsRepo, newsPublisher, surveyAnalyticsService, surveyInvitationService, surveyNotificationService, surveyExportService, surveyFileService, followerSyncService, campaignService, logger)
 
 	// Initialize worker manager
 	workerManager := jobs.NewWorkerManager(logger)
@@ -181,7 +181,7 @@ func sendTemplateMessages(ctx context.Context, client *wechat.WeChatAPIClient) e
 	}
 
 	fmt.Printf("Sending template message to %s...\n", openID)
-	if err := client.SendTemplateMessage(ctx, templateMsg); err != nil {
+	if _, err := client.SendTemplateMessage(ctx, templateMsg); err != nil {
 		return fmt.Errorf("failed to send template message: %w", err)
 	}
 
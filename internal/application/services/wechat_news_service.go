@@ -668,11 +668,8 @@ func (s *WeChatNewsServiceImpl) uploadImageToWeChatAndGetURL(ctx context.Context
 		return "", fmt.Errorf("failed to upload permanent image to WeChat: %w", err)
 	}
 
-	// Get permanent URL
-	permanentURL, err := s.wechatService.GetMediaURL(ctx, permanentMedia.MediaID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get permanent URL: %w", err)
-	}
+	// Permanent images get their URL on upload
+	permanentURL := permanentMedia.URL
 
 	s.logger.Info("Uploaded image to WeChat and got permanent URL",
 		zap.String("originalURL", imageURL),
@@ -17,6 +17,7 @@ type Config struct {
 	Security SecurityConfig `mapstructure:"security"`
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	AliCloud AliCloudConfig `mapstructure:"ali_cloud"`
+	Email    EmailConfig    `mapstructure:"email"`
 }
 
 // AppConfig represents application-level configuration
@@ -39,6 +40,9 @@ type ServerConfig struct {
 	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout    time.Duration `mapstructure:"idle_timeout"`
 	MaxHeaderBytes int           `mapstructure:"max_header_bytes"`
+	// PrivateStoragePath holds files that are only served through authorized endpoints, such as
+	// survey exports. It must not be inside the publicly served ./uploads directory.
+	PrivateStoragePath string `mapstructure:"private_storage_path"`
 }
 
 type DatabaseConfig struct {
@@ -92,6 +96,14 @@ type PublicAccountConfig struct {
 	AppSecret string `mapstructure:"app_secret"`
 	Token     string `mapstructure:"token"`
 	AESKey    string `mapstructure:"aes_key"`
+	// APIBaseURL overrides the WeChat API host, e.g. for a proxy; empty uses the public API
+	APIBaseURL string `mapstructure:"api_base_url"`
+
+	WelcomeMessage string `mapstructure:"welcome_message"`
+	// SurveyInvitationTemplateID is the template message used for survey invitations and reminders
+	SurveyInvitationTemplateID string `mapstructure:"survey_invitation_template_id"`
+	// SurveyNotificationTemplateID is the template message used to notify organizers of survey events
+	SurveyNotificationTemplateID string `mapstructure:"survey_notification_template_id"`
 }
 
 type MiniProgramConfig struct {
@@ -105,6 +117,17 @@ type EnterpriseConfig struct {
 	AgentID    string `mapstructure:"agent_id"`
 }
 
+// EmailConfig represents the SMTP server used for outgoing email. The defaults point at a local
+// mail catcher such as MailHog, so nothing leaves the machine in development.
+type EmailConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	FromName string `mapstructure:"from_name"`
+}
+
 type JWTConfig struct {
 	Secret     string
 	Expiration int
@@ -116,6 +139,11 @@ type QRCodeConfig struct {
 	DefaultExpiration int    `mapstructure:"default_expiration_hours"`
 	StoragePath       string `mapstructure:"storage_path"`
 	EnableAnalytics   bool   `mapstructure:"enable_analytics"`
+	// TicketSigningKey is the base64-encoded 32-byte Ed25519 seed used to sign attendee tickets.
+	// When empty, attendee tickets are disabled.
+	TicketSigningKey string `mapstructure:"ticket_signing_key"`
+	// TicketGraceHours keeps tickets valid for this many hours after the event ends
+	TicketGraceHours int `mapstructure:"ticket_grace_hours"`
 }
 
 type SecurityConfig struct {
@@ -159,6 +187,8 @@ func Load() (*Config, error) {
 			WriteTimeout:   30 * time.Second,
 			IdleTimeout:    120 * time.Second,
 			MaxHeaderBytes: 1048576,
+
+			PrivateStoragePath: getEnv("SERVER_PRIVATE_STORAGE_PATH", "./storage"),
 		},
 		Database: DatabaseConfig{
 			Driver:               getEnv("DB_DRIVER", "mysql"),
@@ -202,6 +232,8 @@ func Load() (*Config, error) {
 			DefaultExpiration: getEnvAsInt("QR_CODE_DEFAULT_EXPIRATION_HOURS", 24),
 			StoragePath:       getEnv("QR_CODE_STORAGE_PATH", "qrcodes"),
 			EnableAnalytics:   getEnvAsBool("QR_CODE_ENABLE_ANALYTICS", false),
+			TicketSigningKey:  getEnv("QR_CODE_TICKET_SIGNING_KEY", ""),
+			TicketGraceHours:  getEnvAsInt("QR_CODE_TICKET_GRACE_HOURS", 24),
 		},
 		WeChat: WeChatConfig{
 			PublicAccount: PublicAccountConfig{
@@ -209,6 +241,11 @@ func Load() (*Config, error) {
 				AppSecret: getEnv("WECHAT_PUBLIC_ACCOUNT_APP_SECRET", "5e12e8a8f9b4e25e934b41c2a71b030b"),
 				Token:     getEnv("WECHAT_PUBLIC_ACCOUNT_TOKEN", "brook1226"),
 				AESKey:    getEnv("WECHAT_PUBLIC_ACCOUNT_AES_KEY", "2q4bzKQpWMywVriwWjPrnFGMlDzn5F2awp1QSCxSs3h"),
+
+				APIBaseURL:                   getEnv("WECHAT_PUBLIC_ACCOUNT_API_BASE_URL", ""),
+				WelcomeMessage:               getEnv("WECHAT_PUBLIC_ACCOUNT_WELCOME_MESSAGE", ""),
+				SurveyInvitationTemplateID:   getEnv("WECHAT_PUBLIC_ACCOUNT_SURVEY_INVITATION_TEMPLATE_ID", ""),
+				SurveyNotificationTemplateID: getEnv("WECHAT_PUBLIC_ACCOUNT_SURVEY_NOTIFICATION_TEMPLATE_ID", ""),
 			},
 			MiniProgram: MiniProgramConfig{
 				AppID:     getEnv("WECHAT_MINI_PROGRAM_APP_ID", "wxc320e6056994506c"),
@@ -236,6 +273,14 @@ func Load() (*Config, error) {
 				Endpoint: getEnv("ALI_CLOUD_VOD_ENDPOINT", "vod.cn-shanghai.aliyuncs.com"),
 			},
 		},
+		Email: EmailConfig{
+			Host:     getEnv("EMAIL_SMTP_HOST", "localhost"),
+			Port:     getEnvAsInt("EMAIL_SMTP_PORT", 1025),
+			Username: getEnv("EMAIL_SMTP_USERNAME", ""),
+			Password: getEnv("EMAIL_SMTP_PASSWORD", ""),
+			From:     getEnv("EMAIL_FROM", "noreply@nextevent.local"),
+			FromName: getEnv("EMAIL_FROM_NAME", "NextEvent"),
+		},
 	}
 	return cfg, nil
 }
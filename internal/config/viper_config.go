@@ -70,6 +70,7 @@ func setDefaults() {
 	viper.SetDefault("qrcode.default_expiration_hours", 24)
 	viper.SetDefault("qrcode.storage_path", "qrcodes")
 	viper.SetDefault("qrcode.enable_analytics", false)
+	viper.SetDefault("qrcode.ticket_grace_hours", 24)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -79,4 +80,10 @@ func setDefaults() {
 	viper.SetDefault("wechat.public_account.app_secret", "")
 	viper.SetDefault("wechat.public_account.token", "")
 	viper.SetDefault("wechat.public_account.aes_key", "")
+
+	// Email defaults: a local SMTP mail catcher
+	viper.SetDefault("email.host", "localhost")
+	viper.SetDefault("email.port", 1025)
+	viper.SetDefault("email.from", "noreply@nextevent.local")
+	viper.SetDefault("email.from_name", "NextEvent")
 }
@@ -7,16 +7,41 @@ import (
 	"gorm.io/gorm"
 )
 
+// Attendee registration statuses
+const (
+	AttendeeStatusRegistered = "registered" // Holds a confirmed place
+	AttendeeStatusWaitlisted = "waitlisted" // Waiting for a place to free up
+	AttendeeStatusCancelled  = "cancelled"  // Registration cancelled
+)
+
+// Attendee check-in methods
+const (
+	CheckInMethodWeChatScan = "wechat_scan" // Attendee scanned the event's WeChat QR code
+	CheckInMethodTicketScan = "ticket_scan" // Staff scanned the attendee's signed ticket
+	CheckInMethodQRCode     = "qr_code"     // Staff scanned the attendee's QR code
+	CheckInMethodManual     = "manual"      // Checked in by hand through the API
+)
+
 // EventAttendee represents the relationship between events and attendees (maps to EventAttendances table)
 type EventAttendee struct {
-	ID                      uuid.UUID `gorm:"type:char(36);primaryKey;column:Id" json:"id"`
-	Mobile                  string    `gorm:"type:longtext;column:Mobile" json:"mobile"`
-	EventID                 uuid.UUID `gorm:"type:char(36);not null;column:EventId" json:"event_id"`
-	OnSiteScanned           bool      `gorm:"type:tinyint(1);column:OnSiteScanned" json:"on_site_scanned"`
-	InteractionCodeReceived bool      `gorm:"type:tinyint(1);column:InteractionCodeReceived" json:"interaction_code_received"`
+	ID                      uuid.UUID  `gorm:"type:char(36);primaryKey;column:Id" json:"id"`
+	Mobile                  string     `gorm:"type:longtext;column:Mobile" json:"mobile"`
+	Name                    string     `gorm:"type:varchar(100);column:Name" json:"name,omitempty"`
+	Company                 string     `gorm:"type:varchar(200);column:Company" json:"company,omitempty"`
+	EventID                 uuid.UUID  `gorm:"type:char(36);not null;column:EventId" json:"event_id"`
+	UserID                  *uuid.UUID `gorm:"type:char(36);column:UserId;index" json:"user_id,omitempty"` // WeChat follower (WeiChatUsers.Id)
+	OnSiteScanned           bool       `gorm:"type:tinyint(1);column:OnSiteScanned" json:"on_site_scanned"`
+	InteractionCodeReceived bool       `gorm:"type:tinyint(1);column:InteractionCodeReceived" json:"interaction_code_received"`
+	CheckInDate             *time.Time `gorm:"type:datetime(6);column:CheckInDate" json:"check_in_date,omitempty"`
+	CheckInMethod           string     `gorm:"type:varchar(20);column:CheckInMethod" json:"check_in_method,omitempty"` // wechat_scan, ticket_scan, qr_code, manual
+	Status                  string     `gorm:"type:varchar(20);column:Status;default:registered;index" json:"status"`
+	Source                  string     `gorm:"type:varchar(20);column:Source" json:"source,omitempty"` // wechat, web, api
+	Notes                   string     `gorm:"type:longtext;column:Notes" json:"notes,omitempty"`
+	CancelledAt             *time.Time `gorm:"type:datetime(6);column:CancelledAt" json:"cancelled_at,omitempty"`
 
 	// Relationships
-	Event *SiteEvent `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Event *SiteEvent  `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	User  *WeChatUser `gorm:"foreignKey:UserID" json:"user,omitempty"`
 
 	// Audit fields
 	CreatedAt time.Time  `gorm:"type:datetime(6);column:CreationTime" json:"created_at"`
@@ -51,16 +76,56 @@ func (e *EventAttendee) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
-// CheckIn marks the attendee as scanned on site
-func (e *EventAttendee) CheckIn() {
+// CheckIn marks the attendee as scanned on site, keeping the time and method of the first check-in
+func (e *EventAttendee) CheckIn(method string) {
 	e.OnSiteScanned = true
 	e.InteractionCodeReceived = true
+	if e.CheckInDate == nil {
+		now := time.Now()
+		e.CheckInDate = &now
+	}
+	if e.CheckInMethod == "" {
+		e.CheckInMethod = method
+	}
+}
+
+// IsRegistered checks if the attendee holds a confirmed place. Legacy rows without a status count as registered.
+func (e *EventAttendee) IsRegistered() bool {
+	return e.Status == AttendeeStatusRegistered || e.Status == ""
+}
+
+// IsWaitlisted checks if the attendee is on the waitlist
+func (e *EventAttendee) IsWaitlisted() bool {
+	return e.Status == AttendeeStatusWaitlisted
 }
 
-// QRCodeData represents the data structure for QR code generation
+// IsCancelled checks if the registration has been cancelled
+func (e *EventAttendee) IsCancelled() bool {
+	return e.Status == AttendeeStatusCancelled
+}
+
+// Cancel marks the registration as cancelled
+func (e *EventAttendee) Cancel() {
+	now := time.Now()
+	e.Status = AttendeeStatusCancelled
+	e.CancelledAt = &now
+}
+
+// IsCheckedIn checks if the attendee has already been scanned on site
+func (e *EventAttendee) IsCheckedIn() bool {
+	return e.OnSiteScanned
+}
+
+// QRCodeData represents the data structure for QR code generation. It is the payload of a signed attendee ticket.
 type QRCodeData struct {
 	EventID    uuid.UUID `json:"event_id"`
 	UserID     uuid.UUID `json:"user_id"`
 	AttendeeID uuid.UUID `json:"attendee_id"`
-	Timestamp  time.Time `json:"timestamp"`
+	Timestamp  time.Time `json:"timestamp"`  // When the ticket was issued
+	ExpiresAt  time.Time `json:"expires_at"` // When the ticket stops being accepted
+}
+
+// IsExpired checks if the ticket is no longer accepted at the given time
+func (d *QRCodeData) IsExpired(now time.Time) bool {
+	return !d.ExpiresAt.IsZero() && now.After(d.ExpiresAt)
 }
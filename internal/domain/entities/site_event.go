@@ -33,6 +33,7 @@ type SiteEvent struct {
 	ScanNewsID      uuid.UUID `gorm:"type:char(36);column:ScanNewsId" json:"scanNewsId"`
 	Tags            string    `gorm:"type:longtext;column:Tags" json:"tags"`
 	CategoryID      uuid.UUID `gorm:"type:char(36);column:CategoryId;default:00000000-0000-0000-0000-000000000000" json:"categoryId"`
+	Capacity        int       `gorm:"column:Capacity;default:0" json:"capacity"` // Maximum registered attendees, 0 means unlimited
 
 	// Audit fields matching ABP Framework patterns
 	CreatedAt time.Time  `gorm:"type:datetime(6);column:CreationTime" json:"createdAt"`
@@ -104,6 +105,11 @@ func (e *SiteEvent) GetStatus() string {
 	return "draft"
 }
 
+// HasCapacityLimit checks if registrations beyond Capacity go to the waitlist
+func (e *SiteEvent) HasCapacityLimit() bool {
+	return e.Capacity > 0
+}
+
 // HasResource checks if the event has a specific resource type
 func (e *SiteEvent) HasResource(resourceType string) bool {
 	switch resourceType {
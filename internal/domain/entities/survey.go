@@ -29,6 +29,7 @@ type Survey struct {
 	CreatedAt         time.Time        `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt         time.Time        `json:"updatedAt" gorm:"autoUpdateTime"`
 	PublishedAt       *time.Time       `json:"publishedAt"`
+	Version           int              `json:"version" gorm:"not null;default:1"` // Content version, raised by each revision once published
 	
 	// Relationships
 	Questions         []SurveyQuestion `json:"questions,omitempty" gorm:"foreignKey:SurveyID;constraint:OnDelete:CASCADE"`
@@ -68,6 +69,7 @@ type SurveyQuestion struct {
 	Options         pq.StringArray      `json:"options" gorm:"type:text[]"` // For multiple choice, checkboxes, etc.
 	Validation      string              `json:"validation" gorm:"type:jsonb"` // JSON validation rules
 	Metadata        string              `json:"metadata" gorm:"type:jsonb"` // Additional question metadata
+	RemovedInVersion *int               `json:"removedInVersion,omitempty" gorm:"index"` // Revision that removed the question; its answers are kept
 	CreatedAt       time.Time           `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time           `json:"updatedAt" gorm:"autoUpdateTime"`
 	
@@ -114,6 +116,8 @@ type SurveyResponse struct {
 	Survey          *Survey        `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
 	RespondentID    *uuid.UUID     `json:"respondentId" gorm:"type:uuid;index"` // null for anonymous
 	SessionID       string         `json:"sessionId" gorm:"not null;size:255;index"` // for tracking anonymous users
+	CollectorID     *uuid.UUID     `json:"collectorId" gorm:"type:uuid;index"` // Collector the response came through
+	SurveyVersion   int            `json:"surveyVersion" gorm:"not null;default:1"` // Survey version the response was answered against
 	Status          ResponseStatus `json:"status" gorm:"not null;default:'in_progress';index"`
 	StartedAt       time.Time      `json:"startedAt" gorm:"autoCreateTime"`
 	CompletedAt     *time.Time     `json:"completedAt"`
@@ -135,10 +139,11 @@ type SurveyResponse struct {
 type ResponseStatus string
 
 const (
-	ResponseStatusInProgress ResponseStatus = "in_progress"
-	ResponseStatusCompleted  ResponseStatus = "completed"
-	ResponseStatusSubmitted  ResponseStatus = "submitted"
-	ResponseStatusAbandoned  ResponseStatus = "abandoned"
+	ResponseStatusInProgress  ResponseStatus = "in_progress"
+	ResponseStatusCompleted   ResponseStatus = "completed"
+	ResponseStatusSubmitted   ResponseStatus = "submitted"
+	ResponseStatusAbandoned   ResponseStatus = "abandoned"
+	ResponseStatusScreenedOut ResponseStatus = "screened_out" // Rejected by a full quota
 )
 
 // TableName returns the table name for SurveyResponse
@@ -181,9 +186,13 @@ type SurveyAnalytics struct {
 	AverageTime       float64   `json:"averageTime" gorm:"default:0"` // in minutes
 	CompletionRate    float64   `json:"completionRate" gorm:"default:0"`
 	DropoffRate       float64   `json:"dropoffRate" gorm:"default:0"`
+	QuestionStats     string    `json:"-" gorm:"type:jsonb"` // Per-question analytics, see GetQuestionStats
 	LastCalculated    time.Time `json:"lastCalculated" gorm:"autoUpdateTime"`
 	CreatedAt         time.Time `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt         time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	
+	// Computed fields
+	Questions         []SurveyQuestionAnalytics `json:"questions" gorm:"-"`
 }
 
 // TableName returns the table name for SurveyAnalytics
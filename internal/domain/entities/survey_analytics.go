@@ -0,0 +1,603 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SurveyChoiceCount is the number of answers that selected a choice
+type SurveyChoiceCount struct {
+	Value      string  `json:"value"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"` // Of the answers to the question
+}
+
+// SurveyNumericSummary summarises the answers to a number, rating or scale question
+type SurveyNumericSummary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stdDev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// SurveyNPS is the Net Promoter Score of a 0-10 rating or scale question
+type SurveyNPS struct {
+	Promoters  int     `json:"promoters"`  // Rated 9 or 10
+	Passives   int     `json:"passives"`   // Rated 7 or 8
+	Detractors int     `json:"detractors"` // Rated 0 to 6
+	Score      float64 `json:"score"`      // Percentage of promoters minus percentage of detractors
+}
+
+// SurveyMatrixRowAnalytics is the distribution of the columns chosen for a matrix row
+type SurveyMatrixRowAnalytics struct {
+	Row      string              `json:"row"`
+	Answered int                 `json:"answered"`
+	Choices  []SurveyChoiceCount `json:"choices"` // Percentages are of the answers to the row
+}
+
+// SurveyRankingItemAnalytics summarises the ranks given to an item of a ranking question
+type SurveyRankingItemAnalytics struct {
+	Item        string  `json:"item"`
+	Ranked      int     `json:"ranked"`      // Answers that ranked the item
+	AverageRank float64 `json:"averageRank"` // 1 is best; 0 when the item was never ranked
+	RankCounts  []int   `json:"rankCounts"`  // RankCounts[i] answers ranked the item at position i+1
+}
+
+// SurveyFileAnalytics summarises the files uploaded for a file question
+type SurveyFileAnalytics struct {
+	Files     int                 `json:"files"`
+	TotalSize int64               `json:"totalSize"` // In bytes
+	Types     []SurveyChoiceCount `json:"types"`     // Content types, percentages are of the files
+}
+
+// SurveyQuestionAnalytics holds the answer distribution and dropoff of a question. Answers are
+// only counted for finished responses; unfinished responses count towards the dropoff.
+type SurveyQuestionAnalytics struct {
+	QuestionID   uuid.UUID                    `json:"questionId"`
+	QuestionText string                       `json:"questionText"`
+	QuestionType QuestionType                 `json:"questionType"`
+	Order        int                          `json:"order"`
+	Answered     int                          `json:"answered"`
+	Skipped      int                          `json:"skipped"`     // Finished responses without an answer
+	Reached      int                          `json:"reached"`     // Responses that got as far as the question
+	Dropoffs     int                          `json:"dropoffs"`    // Unfinished responses that stopped at the question
+	DropoffRate  float64                      `json:"dropoffRate"` // Dropoffs as a percentage of Reached
+	Choices      []SurveyChoiceCount          `json:"choices,omitempty"`
+	Numeric      *SurveyNumericSummary        `json:"numeric,omitempty"`
+	NPS          *SurveyNPS                   `json:"nps,omitempty"`
+	Matrix       []SurveyMatrixRowAnalytics   `json:"matrix,omitempty"`
+	Ranking      []SurveyRankingItemAnalytics `json:"ranking,omitempty"` // Best average rank first
+	Files        *SurveyFileAnalytics         `json:"files,omitempty"`
+}
+
+// GetQuestionStats parses the per-question analytics
+func (a *SurveyAnalytics) GetQuestionStats() ([]SurveyQuestionAnalytics, error) {
+	var stats []SurveyQuestionAnalytics
+	if strings.TrimSpace(a.QuestionStats) == "" {
+		return stats, nil
+	}
+	if err := json.Unmarshal([]byte(a.QuestionStats), &stats); err != nil {
+		return nil, fmt.Errorf("invalid question analytics: %w", err)
+	}
+	return stats, nil
+}
+
+// SetQuestionStats stores the per-question analytics as JSON
+func (a *SurveyAnalytics) SetQuestionStats(stats []SurveyQuestionAnalytics) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to encode question analytics: %w", err)
+	}
+	a.QuestionStats = string(data)
+	a.Questions = stats
+	return nil
+}
+
+// SurveyAnalyticsAggregator calculates the analytics of a survey from its responses, one response at a time
+type SurveyAnalyticsAggregator struct {
+	questions []SurveyQuestion
+	stats     []SurveyQuestionAnalytics
+	choices   []map[string]int
+	numbers   [][]float64
+	matrix    []map[string]map[string]int // Per matrix row, the count of each column
+	ranks     []map[string][]int          // Per ranking item, the count of each rank
+	files     []*SurveyFileAnalytics
+	fileTypes []map[string]int
+
+	starts      int
+	completions int
+	submissions int
+	timeSpent   int // Total seconds of the finished responses that recorded their time
+	timed       int
+}
+
+// NewSurveyAnalyticsAggregator creates an aggregator for the questions of a survey
+func NewSurveyAnalyticsAggregator(questions []SurveyQuestion) *SurveyAnalyticsAggregator {
+	ordered := make([]SurveyQuestion, len(questions))
+	copy(ordered, questions)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	a := &SurveyAnalyticsAggregator{
+		questions: ordered,
+		stats:     make([]SurveyQuestionAnalytics, len(ordered)),
+		choices:   make([]map[string]int, len(ordered)),
+		numbers:   make([][]float64, len(ordered)),
+		matrix:    make([]map[string]map[string]int, len(ordered)),
+		ranks:     make([]map[string][]int, len(ordered)),
+		files:     make([]*SurveyFileAnalytics, len(ordered)),
+		fileTypes: make([]map[string]int, len(ordered)),
+	}
+	for i, question := range ordered {
+		a.stats[i] = SurveyQuestionAnalytics{
+			QuestionID:   question.ID,
+			QuestionText: question.QuestionText,
+			QuestionType: question.QuestionType,
+			Order:        question.Order,
+		}
+		a.choices[i] = make(map[string]int)
+		switch question.QuestionType {
+		case QuestionTypeMatrix:
+			a.matrix[i] = make(map[string]map[string]int)
+		case QuestionTypeRanking:
+			a.ranks[i] = make(map[string][]int)
+		case QuestionTypeFile:
+			a.files[i] = &SurveyFileAnalytics{}
+			a.fileTypes[i] = make(map[string]int)
+		}
+	}
+	return a
+}
+
+// Add counts a response with its answers. Responses screened out by a quota are left out.
+func (a *SurveyAnalyticsAggregator) Add(response *SurveyResponse) {
+	if response.Status == ResponseStatusScreenedOut {
+		return
+	}
+	a.starts++
+
+	answers := make(map[uuid.UUID]*SurveyAnswer, len(response.Answers))
+	for i := range response.Answers {
+		answers[response.Answers[i].QuestionID] = &response.Answers[i]
+	}
+
+	if response.IsCompleted() {
+		a.completions++
+		if response.Status == ResponseStatusSubmitted {
+			a.submissions++
+		}
+		if response.TimeSpent != nil {
+			a.timeSpent += *response.TimeSpent
+			a.timed++
+		}
+		for i := range a.questions {
+			a.stats[i].Reached++
+			a.count(i, answers[a.questions[i].ID])
+		}
+		return
+	}
+
+	if len(a.questions) == 0 {
+		return
+	}
+	// An unfinished response stopped at the question after the last one it answered
+	stop := 0
+	for i, question := range a.questions {
+		if _, ok := answers[question.ID]; ok {
+			stop = i + 1
+		}
+	}
+	if stop == len(a.questions) {
+		stop--
+	}
+	for i := 0; i <= stop; i++ {
+		a.stats[i].Reached++
+	}
+	a.stats[stop].Dropoffs++
+}
+
+// count adds the answer of a finished response to the distribution of question i
+func (a *SurveyAnalyticsAggregator) count(i int, answer *SurveyAnswer) {
+	if answer == nil || !answer.HasValue() {
+		a.stats[i].Skipped++
+		return
+	}
+	a.stats[i].Answered++
+
+	switch a.questions[i].QuestionType {
+	case QuestionTypeRadio, QuestionTypeDropdown, QuestionTypeCheckbox, QuestionTypeYesNo:
+		for _, value := range uniqueValues(answerValues(answer)) {
+			a.choices[i][value]++
+		}
+	case QuestionTypeRating, QuestionTypeScale:
+		if number, ok := answerNumber(answer); ok {
+			a.choices[i][strconv.FormatFloat(number, 'f', -1, 64)]++
+			a.numbers[i] = append(a.numbers[i], number)
+		}
+	case QuestionTypeNumber:
+		if number, ok := answerNumber(answer); ok {
+			a.numbers[i] = append(a.numbers[i], number)
+		}
+	case QuestionTypeMatrix:
+		matrix, err := answer.GetMatrix()
+		if err != nil {
+			return
+		}
+		for row, columns := range matrix {
+			if a.matrix[i][row] == nil {
+				a.matrix[i][row] = make(map[string]int)
+			}
+			for _, column := range uniqueValues(columns) {
+				a.matrix[i][row][column]++
+			}
+		}
+	case QuestionTypeRanking:
+		for rank, item := range uniqueValues(rankingItems(answer)) {
+			counts := a.ranks[i][item]
+			for len(counts) <= rank {
+				counts = append(counts, 0)
+			}
+			counts[rank]++
+			a.ranks[i][item] = counts
+		}
+	case QuestionTypeFile:
+		files, err := answer.GetFiles()
+		if err != nil {
+			return
+		}
+		for _, file := range files {
+			a.files[i].Files++
+			a.files[i].TotalSize += file.Size
+			contentType := file.ContentType
+			if contentType == "" {
+				contentType = "unknown"
+			}
+			a.fileTypes[i][contentType]++
+		}
+	}
+}
+
+// Apply stores the calculated figures in the survey analytics. Views are tracked elsewhere and kept.
+func (a *SurveyAnalyticsAggregator) Apply(analytics *SurveyAnalytics) error {
+	analytics.TotalStarts = a.starts
+	analytics.TotalCompletions = a.completions
+	analytics.TotalSubmissions = a.submissions
+	analytics.CompletionRate = percentage(a.completions, a.starts)
+	analytics.DropoffRate = percentage(a.starts-a.completions, a.starts)
+	analytics.AverageTime = 0
+	if a.timed > 0 {
+		analytics.AverageTime = float64(a.timeSpent) / float64(a.timed) / 60
+	}
+	analytics.LastCalculated = time.Now()
+
+	stats := make([]SurveyQuestionAnalytics, len(a.stats))
+	for i, question := range a.questions {
+		stats[i] = a.stats[i]
+		stats[i].DropoffRate = percentage(stats[i].Dropoffs, stats[i].Reached)
+		if len(a.choices[i]) > 0 || hasChoiceOptions(&question) {
+			stats[i].Choices = choiceCounts(&question, a.choices[i], stats[i].Answered)
+		}
+		if len(a.numbers[i]) > 0 {
+			stats[i].Numeric = summarizeNumbers(a.numbers[i])
+			if isNPSQuestion(&question) {
+				stats[i].NPS = netPromoterScore(a.numbers[i])
+			}
+		}
+		switch question.QuestionType {
+		case QuestionTypeMatrix:
+			stats[i].Matrix = matrixAnalytics(&question, a.matrix[i])
+		case QuestionTypeRanking:
+			stats[i].Ranking = rankingAnalytics(&question, a.ranks[i])
+		case QuestionTypeFile:
+			files := *a.files[i]
+			files.Types = choiceCounts(&question, a.fileTypes[i], files.Files)
+			stats[i].Files = &files
+		}
+	}
+	return analytics.SetQuestionStats(stats)
+}
+
+// matrixAnalytics lists the rows of a matrix question with the count of each column, rows and
+// columns first in their defined order and then any others that were answered
+func matrixAnalytics(question *SurveyQuestion, counts map[string]map[string]int) []SurveyMatrixRowAnalytics {
+	var columns []string
+	if settings, err := question.GetMatrixSettings(); err == nil {
+		columns = settings.Columns
+	}
+	rows := uniqueValues(question.Options)
+	var others []string
+	for row := range counts {
+		if !containsAny(rows, []string{row}) {
+			others = append(others, row)
+		}
+	}
+	sort.Strings(others)
+
+	result := make([]SurveyMatrixRowAnalytics, 0, len(rows)+len(others))
+	for _, row := range append(rows, others...) {
+		answered := 0
+		for _, count := range counts[row] {
+			answered += count
+		}
+		choices := choiceCounts(&SurveyQuestion{QuestionType: QuestionTypeRadio, Options: columns}, counts[row], 0)
+		for j := range choices {
+			choices[j].Percentage = percentage(choices[j].Count, answered)
+		}
+		result = append(result, SurveyMatrixRowAnalytics{Row: row, Answered: answered, Choices: choices})
+	}
+	return result
+}
+
+// rankingAnalytics summarises the ranks of the items of a ranking question, best average rank
+// first and items that were never ranked last
+func rankingAnalytics(question *SurveyQuestion, ranks map[string][]int) []SurveyRankingItemAnalytics {
+	items := uniqueValues(question.Options)
+	for item := range ranks {
+		if !containsAny(items, []string{item}) {
+			items = append(items, item)
+		}
+	}
+
+	result := make([]SurveyRankingItemAnalytics, 0, len(items))
+	for _, item := range items {
+		analytics := SurveyRankingItemAnalytics{Item: item, RankCounts: make([]int, len(items))}
+		total := 0
+		for rank, count := range ranks[item] {
+			if rank < len(analytics.RankCounts) {
+				analytics.RankCounts[rank] = count
+			}
+			analytics.Ranked += count
+			total += (rank + 1) * count
+		}
+		if analytics.Ranked > 0 {
+			analytics.AverageRank = float64(total) / float64(analytics.Ranked)
+		}
+		result = append(result, analytics)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if (result[i].Ranked == 0) != (result[j].Ranked == 0) {
+			return result[j].Ranked == 0
+		}
+		return result[i].AverageRank < result[j].AverageRank
+	})
+	return result
+}
+
+// hasChoiceOptions checks if the options of a question are choices, rather than a rating range
+func hasChoiceOptions(question *SurveyQuestion) bool {
+	switch question.QuestionType {
+	case QuestionTypeRadio, QuestionTypeDropdown, QuestionTypeCheckbox:
+		return len(question.Options) > 0
+	}
+	return false
+}
+
+// choiceCounts lists the choices of a question, options first in their order and then any other
+// answers. Ratings are listed from low to high.
+func choiceCounts(question *SurveyQuestion, counts map[string]int, answered int) []SurveyChoiceCount {
+	var choices []SurveyChoiceCount
+	listed := make(map[string]bool)
+	if hasChoiceOptions(question) {
+		for _, option := range question.Options {
+			if listed[option] {
+				continue
+			}
+			listed[option] = true
+			choices = append(choices, SurveyChoiceCount{Value: option, Count: counts[option]})
+		}
+	}
+
+	var others []SurveyChoiceCount
+	for value, count := range counts {
+		if !listed[value] {
+			others = append(others, SurveyChoiceCount{Value: value, Count: count})
+		}
+	}
+	numeric := question.QuestionType == QuestionTypeRating || question.QuestionType == QuestionTypeScale
+	sort.Slice(others, func(i, j int) bool {
+		if numeric {
+			x, _ := strconv.ParseFloat(others[i].Value, 64)
+			y, _ := strconv.ParseFloat(others[j].Value, 64)
+			return x < y
+		}
+		if others[i].Count != others[j].Count {
+			return others[i].Count > others[j].Count
+		}
+		return others[i].Value < others[j].Value
+	})
+	choices = append(choices, others...)
+
+	for i := range choices {
+		choices[i].Percentage = percentage(choices[i].Count, answered)
+	}
+	return choices
+}
+
+// summarizeNumbers calculates the mean, median and population standard deviation of the values
+func summarizeNumbers(values []float64) *SurveyNumericSummary {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	summary := &SurveyNumericSummary{Count: len(sorted), Min: sorted[0], Max: sorted[len(sorted)-1]}
+	var sum float64
+	for _, value := range sorted {
+		sum += value
+	}
+	summary.Mean = sum / float64(len(sorted))
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		summary.Median = (sorted[middle-1] + sorted[middle]) / 2
+	} else {
+		summary.Median = sorted[middle]
+	}
+
+	var squares float64
+	for _, value := range sorted {
+		squares += (value - summary.Mean) * (value - summary.Mean)
+	}
+	summary.StdDev = math.Sqrt(squares / float64(len(sorted)))
+	return summary
+}
+
+// isNPSQuestion checks if a rating or scale question is rated from 0 to 10. Like answer validation,
+// the first two options hold the range.
+func isNPSQuestion(question *SurveyQuestion) bool {
+	if question.QuestionType != QuestionTypeRating && question.QuestionType != QuestionTypeScale {
+		return false
+	}
+	if len(question.Options) < 2 {
+		return false
+	}
+	min, err := strconv.ParseFloat(question.Options[0], 64)
+	if err != nil {
+		return false
+	}
+	max, err := strconv.ParseFloat(question.Options[1], 64)
+	return err == nil && min == 0 && max == 10
+}
+
+// netPromoterScore calculates the NPS of 0-10 ratings
+func netPromoterScore(ratings []float64) *SurveyNPS {
+	nps := &SurveyNPS{}
+	for _, rating := range ratings {
+		switch {
+		case rating >= 9:
+			nps.Promoters++
+		case rating >= 7:
+			nps.Passives++
+		default:
+			nps.Detractors++
+		}
+	}
+	nps.Score = percentage(nps.Promoters, len(ratings)) - percentage(nps.Detractors, len(ratings))
+	return nps
+}
+
+// percentage returns part as a percentage of total, or 0 when there is no total
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// uniqueValues drops repeated and empty values
+func uniqueValues(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// SurveyCrossTab cross-tabulates the answers to two questions. Every selection of a multiple choice
+// answer is counted, so the cells of a row can add up to more than its total.
+type SurveyCrossTab struct {
+	RowQuestionID    uuid.UUID `json:"rowQuestionId"`
+	ColumnQuestionID uuid.UUID `json:"columnQuestionId"`
+	Rows             []string  `json:"rows"`
+	Columns          []string  `json:"columns"`
+	Counts           [][]int   `json:"counts"` // Counts[row][column]
+	RowTotals        []int     `json:"rowTotals"`
+	ColumnTotals     []int     `json:"columnTotals"`
+	Total            int       `json:"total"` // Responses that answered both questions
+}
+
+// NewSurveyCrossTab creates an empty cross-tabulation, listing the options of choice questions up front
+func NewSurveyCrossTab(row, column *SurveyQuestion) *SurveyCrossTab {
+	t := &SurveyCrossTab{
+		RowQuestionID:    row.ID,
+		ColumnQuestionID: column.ID,
+		Rows:             []string{},
+		Columns:          []string{},
+		Counts:           [][]int{},
+		RowTotals:        []int{},
+		ColumnTotals:     []int{},
+	}
+	if hasChoiceOptions(column) {
+		for _, option := range uniqueValues(column.Options) {
+			t.columnIndex(option)
+		}
+	}
+	if hasChoiceOptions(row) {
+		for _, option := range uniqueValues(row.Options) {
+			t.rowIndex(option)
+		}
+	}
+	return t
+}
+
+// Add counts the answers of a response
+func (t *SurveyCrossTab) Add(answers []SurveyAnswer) {
+	var rowAnswer, columnAnswer *SurveyAnswer
+	for i := range answers {
+		switch answers[i].QuestionID {
+		case t.RowQuestionID:
+			rowAnswer = &answers[i]
+		case t.ColumnQuestionID:
+			columnAnswer = &answers[i]
+		}
+	}
+	if rowAnswer == nil || columnAnswer == nil || !rowAnswer.HasValue() || !columnAnswer.HasValue() {
+		return
+	}
+	rowValues := uniqueValues(answerValues(rowAnswer))
+	columnValues := uniqueValues(answerValues(columnAnswer))
+	if len(rowValues) == 0 || len(columnValues) == 0 {
+		return
+	}
+
+	t.Total++
+	for _, column := range columnValues {
+		t.ColumnTotals[t.columnIndex(column)]++
+	}
+	for _, row := range rowValues {
+		r := t.rowIndex(row)
+		t.RowTotals[r]++
+		for _, column := range columnValues {
+			t.Counts[r][t.columnIndex(column)]++
+		}
+	}
+}
+
+// rowIndex returns the index of a row, adding it when it is new
+func (t *SurveyCrossTab) rowIndex(value string) int {
+	for i, row := range t.Rows {
+		if row == value {
+			return i
+		}
+	}
+	t.Rows = append(t.Rows, value)
+	t.Counts = append(t.Counts, make([]int, len(t.Columns)))
+	t.RowTotals = append(t.RowTotals, 0)
+	return len(t.Rows) - 1
+}
+
+// columnIndex returns the index of a column, adding it when it is new
+func (t *SurveyCrossTab) columnIndex(value string) int {
+	for i, column := range t.Columns {
+		if column == value {
+			return i
+		}
+	}
+	t.Columns = append(t.Columns, value)
+	for i := range t.Counts {
+		t.Counts[i] = append(t.Counts[i], 0)
+	}
+	t.ColumnTotals = append(t.ColumnTotals, 0)
+	return len(t.Columns) - 1
+}
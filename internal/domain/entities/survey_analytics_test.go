@@ -0,0 +1,199 @@
+package entities
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestSurveyAnalyticsAggregator(t *testing.T) {
+	questions := []SurveyQuestion{
+		{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeRating, QuestionText: "Recommend", Options: []string{"0", "10"}},
+		{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeRadio, QuestionText: "Role", Options: []string{"Speaker", "Attendee"}},
+		{ID: uuid.New(), Order: 3, QuestionType: QuestionTypeCheckbox, QuestionText: "Topics", Options: []string{"AI", "Cloud"}},
+	}
+	rating, role, topics := questions[0].ID, questions[1].ID, questions[2].ID
+	number := func(value float64) *float64 { return &value }
+	seconds := func(value int) *int { return &value }
+
+	aggregator := NewSurveyAnalyticsAggregator(questions)
+	responses := []SurveyResponse{
+		{Status: ResponseStatusSubmitted, TimeSpent: seconds(120), Answers: []SurveyAnswer{
+			{QuestionID: role, AnswerText: "Speaker"},
+			{QuestionID: rating, AnswerNumber: number(10)},
+			{QuestionID: topics, AnswerArray: pq.StringArray{"AI", "Cloud"}},
+		}},
+		{Status: ResponseStatusSubmitted, TimeSpent: seconds(240), Answers: []SurveyAnswer{
+			{QuestionID: role, AnswerText: "Attendee"},
+			{QuestionID: rating, AnswerNumber: number(6)},
+			{QuestionID: topics, IsSkipped: true},
+		}},
+		{Status: ResponseStatusCompleted, Answers: []SurveyAnswer{
+			{QuestionID: role, AnswerText: "Attendee"},
+			{QuestionID: rating, AnswerNumber: number(8)},
+		}},
+		// Stopped at the rating question
+		{Status: ResponseStatusAbandoned, Answers: []SurveyAnswer{{QuestionID: role, AnswerText: "Speaker"}}},
+		// Stopped at the first question
+		{Status: ResponseStatusInProgress},
+		// Left out altogether
+		{Status: ResponseStatusScreenedOut, Answers: []SurveyAnswer{{QuestionID: role, AnswerText: "Speaker"}}},
+	}
+	for i := range responses {
+		aggregator.Add(&responses[i])
+	}
+
+	var analytics SurveyAnalytics
+	if err := aggregator.Apply(&analytics); err != nil {
+		t.Fatal(err)
+	}
+	if analytics.TotalStarts != 5 || analytics.TotalCompletions != 3 || analytics.TotalSubmissions != 2 {
+		t.Errorf("starts/completions/submissions = %d/%d/%d, want 5/3/2",
+			analytics.TotalStarts, analytics.TotalCompletions, analytics.TotalSubmissions)
+	}
+	if analytics.CompletionRate != 60 || analytics.DropoffRate != 40 {
+		t.Errorf("completion/dropoff rate = %v/%v, want 60/40", analytics.CompletionRate, analytics.DropoffRate)
+	}
+	if analytics.AverageTime != 3 {
+		t.Errorf("average time = %v minutes, want 3", analytics.AverageTime)
+	}
+
+	stats, err := analytics.GetQuestionStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 3 || stats[0].QuestionID != role || stats[1].QuestionID != rating {
+		t.Fatalf("questions are not in survey order: %+v", stats)
+	}
+
+	roleStats := stats[0]
+	if roleStats.Reached != 5 || roleStats.Dropoffs != 1 || roleStats.DropoffRate != 20 {
+		t.Errorf("role reached/dropoffs/rate = %d/%d/%v, want 5/1/20", roleStats.Reached, roleStats.Dropoffs, roleStats.DropoffRate)
+	}
+	if len(roleStats.Choices) != 2 || roleStats.Choices[0].Value != "Speaker" || roleStats.Choices[0].Count != 1 ||
+		roleStats.Choices[1].Count != 2 || math.Abs(roleStats.Choices[1].Percentage-200.0/3) > 1e-9 {
+		t.Errorf("role choices = %+v", roleStats.Choices)
+	}
+
+	ratingStats := stats[1]
+	if ratingStats.Reached != 4 || ratingStats.Dropoffs != 1 {
+		t.Errorf("rating reached/dropoffs = %d/%d, want 4/1", ratingStats.Reached, ratingStats.Dropoffs)
+	}
+	numeric := ratingStats.Numeric
+	if numeric == nil || numeric.Count != 3 || numeric.Mean != 8 || numeric.Median != 8 || numeric.Min != 6 || numeric.Max != 10 ||
+		math.Abs(numeric.StdDev-math.Sqrt(8.0/3)) > 1e-9 {
+		t.Errorf("rating summary = %+v", numeric)
+	}
+	nps := ratingStats.NPS
+	if nps == nil || nps.Promoters != 1 || nps.Passives != 1 || nps.Detractors != 1 || nps.Score != 0 {
+		t.Errorf("NPS = %+v", nps)
+	}
+	if len(ratingStats.Choices) != 3 || ratingStats.Choices[0].Value != "6" || ratingStats.Choices[2].Value != "10" {
+		t.Errorf("rating choices = %+v", ratingStats.Choices)
+	}
+
+	topicStats := stats[2]
+	if topicStats.Answered != 1 || topicStats.Skipped != 2 || topicStats.Choices[0].Count != 1 || topicStats.Choices[1].Count != 1 {
+		t.Errorf("topics = %+v", topicStats)
+	}
+	if topicStats.NPS != nil || stats[0].Numeric != nil {
+		t.Error("expected NPS and numeric summaries only for the rating question")
+	}
+}
+
+func TestSurveyAnalyticsAggregatorStructuredQuestions(t *testing.T) {
+	questions := []SurveyQuestion{
+		{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeMatrix, QuestionText: "Rate", Options: []string{"Venue", "Food"},
+			Metadata: `{"columns":["Poor","Good"]}`},
+		{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeRanking, QuestionText: "Rank", Options: []string{"Keynote", "Workshop", "Panel"}},
+		{ID: uuid.New(), Order: 3, QuestionType: QuestionTypeFile, QuestionText: "Slides"},
+	}
+	matrix, ranking, files := questions[0].ID, questions[1].ID, questions[2].ID
+
+	aggregator := NewSurveyAnalyticsAggregator(questions)
+	responses := []SurveyResponse{
+		{Status: ResponseStatusSubmitted, Answers: []SurveyAnswer{
+			{QuestionID: matrix, AnswerJSON: `{"Venue":"Good","Food":"Poor"}`},
+			{QuestionID: ranking, AnswerArray: pq.StringArray{"Workshop", "Keynote", "Panel"}},
+			{QuestionID: files, AnswerJSON: `[{"key":"a","size":100,"contentType":"application/pdf"},{"key":"b","size":50,"contentType":"image/png"}]`},
+		}},
+		{Status: ResponseStatusSubmitted, Answers: []SurveyAnswer{
+			{QuestionID: matrix, AnswerJSON: `{"Venue":"Good"}`},
+			{QuestionID: ranking, AnswerJSON: `["Workshop","Panel","Keynote"]`},
+			{QuestionID: files, AnswerJSON: `[{"key":"c","size":25,"contentType":"application/pdf"}]`},
+		}},
+	}
+	for i := range responses {
+		aggregator.Add(&responses[i])
+	}
+	var analytics SurveyAnalytics
+	if err := aggregator.Apply(&analytics); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := analytics.Questions[0].Matrix
+	if len(rows) != 2 || rows[0].Row != "Venue" || rows[0].Answered != 2 || rows[1].Answered != 1 {
+		t.Fatalf("matrix rows = %+v, want Venue answered twice and Food once", rows)
+	}
+	if rows[0].Choices[1].Value != "Good" || rows[0].Choices[1].Count != 2 || rows[0].Choices[1].Percentage != 100 {
+		t.Errorf("Venue choices = %+v, want Good chosen by all", rows[0].Choices)
+	}
+
+	items := analytics.Questions[1].Ranking
+	if len(items) != 3 || items[0].Item != "Workshop" || items[0].AverageRank != 1 || items[0].RankCounts[0] != 2 {
+		t.Fatalf("ranking = %+v, want Workshop ranked first twice", items)
+	}
+	if items[1].Item != "Keynote" || items[1].AverageRank != 2.5 || items[2].Item != "Panel" {
+		t.Errorf("ranking = %+v, want Keynote then Panel", items)
+	}
+
+	uploads := analytics.Questions[2].Files
+	if uploads == nil || uploads.Files != 3 || uploads.TotalSize != 175 {
+		t.Fatalf("files = %+v, want 3 files of 175 bytes", uploads)
+	}
+	if uploads.Types[0].Value != "application/pdf" || uploads.Types[0].Count != 2 {
+		t.Errorf("file types = %+v, want 2 PDFs first", uploads.Types)
+	}
+}
+
+func TestSurveyCrossTab(t *testing.T) {
+	role := SurveyQuestion{ID: uuid.New(), QuestionType: QuestionTypeRadio, Options: []string{"Speaker", "Attendee"}}
+	topics := SurveyQuestion{ID: uuid.New(), QuestionType: QuestionTypeCheckbox, Options: []string{"AI", "Cloud"}}
+
+	crossTab := NewSurveyCrossTab(&role, &topics)
+	crossTab.Add([]SurveyAnswer{
+		{QuestionID: role.ID, AnswerText: "Speaker"},
+		{QuestionID: topics.ID, AnswerArray: pq.StringArray{"AI", "Cloud"}},
+	})
+	crossTab.Add([]SurveyAnswer{
+		{QuestionID: role.ID, AnswerText: "Attendee"},
+		{QuestionID: topics.ID, AnswerArray: pq.StringArray{"AI", "Security"}},
+	})
+	crossTab.Add([]SurveyAnswer{{QuestionID: role.ID, AnswerText: "Attendee"}})
+
+	if crossTab.Total != 2 {
+		t.Errorf("total = %d, want 2", crossTab.Total)
+	}
+	wantColumns := []string{"AI", "Cloud", "Security"}
+	if len(crossTab.Columns) != len(wantColumns) {
+		t.Fatalf("columns = %v, want %v", crossTab.Columns, wantColumns)
+	}
+	for i, column := range wantColumns {
+		if crossTab.Columns[i] != column {
+			t.Fatalf("columns = %v, want %v", crossTab.Columns, wantColumns)
+		}
+	}
+	want := [][]int{{1, 1, 0}, {1, 0, 1}}
+	for r := range want {
+		for c := range want[r] {
+			if crossTab.Counts[r][c] != want[r][c] {
+				t.Fatalf("counts = %v, want %v", crossTab.Counts, want)
+			}
+		}
+	}
+	if crossTab.RowTotals[0] != 1 || crossTab.RowTotals[1] != 1 || crossTab.ColumnTotals[0] != 2 {
+		t.Errorf("row totals = %v, column totals = %v", crossTab.RowTotals, crossTab.ColumnTotals)
+	}
+}
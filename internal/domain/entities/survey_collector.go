@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// Survey collector types
+const (
+	SurveyCollectorTypeWebLink = "web_link"
+	SurveyCollectorTypeEmail   = "email"   // Link sent in newsletters and mailings
+	SurveyCollectorTypeQRCode  = "qr_code" // WeChat QR code, e.g. printed on site
+	SurveyCollectorTypeEmbed   = "embed"   // Survey embedded in another page
+	SurveyCollectorTypeAPI     = "api"     // Responses submitted by other systems
+)
+
+// defaultCollectorEmbedHeight is the height in pixels of embedded surveys without one
+const defaultCollectorEmbedHeight = 600
+
+// ErrInvalidCollector is returned for collectors with an unknown type, limit or schedule, or invalid settings
+var ErrInvalidCollector = errors.New("invalid survey collector")
+
+// SurveyCollectorSettings is the JSON stored in SurveyCollector.Settings
+type SurveyCollectorSettings struct {
+	AllowMultiple *bool      `json:"allowMultiple,omitempty"` // Overrides the survey, e.g. for a tablet passed around on site
+	QRCodeType    QRCodeType `json:"qrCodeType,omitempty"`    // Kind of WeChat QR code, permanent by default
+	EmbedHeight   int        `json:"embedHeight,omitempty"`   // Height in pixels of the embed iframe
+}
+
+// Validate checks the type, response limit, schedule and settings of the collector
+func (c *SurveyCollector) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCollector)
+	}
+	switch c.Type {
+	case SurveyCollectorTypeWebLink, SurveyCollectorTypeEmail, SurveyCollectorTypeQRCode, SurveyCollectorTypeEmbed, SurveyCollectorTypeAPI:
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidCollector, c.Type)
+	}
+	if c.MaxResponses != nil && *c.MaxResponses <= 0 {
+		return fmt.Errorf("%w: maximum responses must be positive", ErrInvalidCollector)
+	}
+	if c.OpensAt != nil && c.ClosesAt != nil && !c.ClosesAt.After(*c.OpensAt) {
+		return fmt.Errorf("%w: collector must close after it opens", ErrInvalidCollector)
+	}
+
+	settings, err := c.GetSettings()
+	if err != nil {
+		return err
+	}
+	if settings.QRCodeType != QRCodeTypePermanent && settings.QRCodeType != QRCodeTypeTemporary {
+		return fmt.Errorf("%w: unknown QR code type %q", ErrInvalidCollector, settings.QRCodeType)
+	}
+	if settings.EmbedHeight < 0 {
+		return fmt.Errorf("%w: embed height must not be negative", ErrInvalidCollector)
+	}
+	return nil
+}
+
+// GetSettings parses the collector settings, filling in the defaults
+func (c *SurveyCollector) GetSettings() (*SurveyCollectorSettings, error) {
+	var settings SurveyCollectorSettings
+	if strings.TrimSpace(c.Settings) != "" {
+		if err := json.Unmarshal([]byte(c.Settings), &settings); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCollector, err)
+		}
+	}
+	if settings.QRCodeType == "" {
+		settings.QRCodeType = QRCodeTypePermanent
+	}
+	if settings.EmbedHeight == 0 {
+		settings.EmbedHeight = defaultCollectorEmbedHeight
+	}
+	return &settings, nil
+}
+
+// IsFull checks if the collector has reached its maximum number of responses
+func (c *SurveyCollector) IsFull() bool {
+	return c.MaxResponses != nil && c.ResponseCount >= *c.MaxResponses
+}
+
+// AcceptsResponses checks if the collector is open, within its schedule and not full
+func (c *SurveyCollector) AcceptsResponses(now time.Time) bool {
+	if !c.IsActive || c.IsFull() {
+		return false
+	}
+	if c.OpensAt != nil && now.Before(*c.OpensAt) {
+		return false
+	}
+	if c.ClosesAt != nil && !now.Before(*c.ClosesAt) {
+		return false
+	}
+	return true
+}
+
+// BuildLinks sets the link of the collector under the survey pages at baseURL, and the iframe
+// of embed collectors
+func (c *SurveyCollector) BuildLinks(baseURL string) {
+	c.URL = fmt.Sprintf("%s/surveys/%s?collector=%s", strings.TrimRight(baseURL, "/"), c.SurveyID, c.ID)
+	c.EmbedCode = ""
+	if c.Type != SurveyCollectorTypeEmbed {
+		return
+	}
+
+	height := defaultCollectorEmbedHeight
+	if settings, err := c.GetSettings(); err == nil {
+		height = settings.EmbedHeight
+	}
+	c.EmbedCode = fmt.Sprintf(`<iframe src="%s" width="100%%" height="%d" frameborder="0"></iframe>`,
+		html.EscapeString(c.URL+"&embed=1"), height)
+}
@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSurveyCollectorValidate(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	zero, ten := 0, 10
+
+	tests := []struct {
+		name      string
+		collector SurveyCollector
+		valid     bool
+	}{
+		{"web link", SurveyCollector{Name: "Website", Type: SurveyCollectorTypeWebLink}, true},
+		{"no name", SurveyCollector{Type: SurveyCollectorTypeWebLink}, false},
+		{"unknown type", SurveyCollector{Name: "SMS", Type: "sms"}, false},
+		{"limit", SurveyCollector{Name: "Booth", Type: SurveyCollectorTypeQRCode, MaxResponses: &ten}, true},
+		{"zero limit", SurveyCollector{Name: "Booth", Type: SurveyCollectorTypeQRCode, MaxResponses: &zero}, false},
+		{"schedule", SurveyCollector{Name: "Day one", Type: SurveyCollectorTypeEmail, OpensAt: &now, ClosesAt: &later}, true},
+		{"closes before opening", SurveyCollector{Name: "Day one", Type: SurveyCollectorTypeEmail, OpensAt: &later, ClosesAt: &now}, false},
+		{"temporary QR code", SurveyCollector{Name: "Booth", Type: SurveyCollectorTypeQRCode, Settings: `{"qrCodeType":"temporary"}`}, true},
+		{"unknown QR code type", SurveyCollector{Name: "Booth", Type: SurveyCollectorTypeQRCode, Settings: `{"qrCodeType":"animated"}`}, false},
+		{"negative embed height", SurveyCollector{Name: "Blog", Type: SurveyCollectorTypeEmbed, Settings: `{"embedHeight":-1}`}, false},
+		{"malformed settings", SurveyCollector{Name: "Blog", Type: SurveyCollectorTypeEmbed, Settings: `{`}, false},
+	}
+	for _, tt := range tests {
+		err := tt.collector.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: Expected valid = %v, got %v", tt.name, tt.valid, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidCollector) {
+			t.Errorf("%s: Expected ErrInvalidCollector, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestSurveyCollectorAcceptsResponses(t *testing.T) {
+	now := time.Now()
+	earlier, later := now.Add(-time.Hour), now.Add(time.Hour)
+	limit := 5
+
+	tests := []struct {
+		name      string
+		collector SurveyCollector
+		expected  bool
+	}{
+		{"open", SurveyCollector{IsActive: true}, true},
+		{"closed", SurveyCollector{IsActive: false}, false},
+		{"not yet open", SurveyCollector{IsActive: true, OpensAt: &later}, false},
+		{"within schedule", SurveyCollector{IsActive: true, OpensAt: &earlier, ClosesAt: &later}, true},
+		{"past closing", SurveyCollector{IsActive: true, ClosesAt: &now}, false},
+		{"below limit", SurveyCollector{IsActive: true, MaxResponses: &limit, ResponseCount: 4}, true},
+		{"full", SurveyCollector{IsActive: true, MaxResponses: &limit, ResponseCount: 5}, false},
+	}
+	for _, tt := range tests {
+		if result := tt.collector.AcceptsResponses(now); result != tt.expected {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.expected, result)
+		}
+	}
+}
+
+func TestSurveyCollectorBuildLinks(t *testing.T) {
+	collector := SurveyCollector{ID: uuid.New(), SurveyID: uuid.New(), Type: SurveyCollectorTypeWebLink}
+	collector.BuildLinks("https://events.example.com/")
+
+	expectedURL := "https://events.example.com/surveys/" + collector.SurveyID.String() + "?collector=" + collector.ID.String()
+	if collector.URL != expectedURL {
+		t.Errorf("Expected URL %s, got %s", expectedURL, collector.URL)
+	}
+	if collector.EmbedCode != "" {
+		t.Errorf("Expected no embed code for a web link, got %s", collector.EmbedCode)
+	}
+
+	collector.Type = SurveyCollectorTypeEmbed
+	collector.Settings = `{"embedHeight":800}`
+	collector.BuildLinks("https://events.example.com")
+	if !strings.Contains(collector.EmbedCode, `height="800"`) || !strings.Contains(collector.EmbedCode, "&amp;embed=1") {
+		t.Errorf("Expected embed iframe, got %s", collector.EmbedCode)
+	}
+}
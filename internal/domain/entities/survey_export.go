@@ -0,0 +1,287 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Survey export types
+const (
+	SurveyExportTypeCSV  = "csv"
+	SurveyExportTypeXLSX = "excel"
+	SurveyExportTypeJSON = "json"
+	SurveyExportTypeSPSS = "spss" // Coded CSV data with an SPSS syntax file of variable and value labels
+)
+
+// Survey export statuses
+const (
+	SurveyExportStatusPending    = "pending"
+	SurveyExportStatusProcessing = "processing"
+	SurveyExportStatusCompleted  = "completed"
+	SurveyExportStatusFailed     = "failed"
+)
+
+// ErrInvalidExport is returned for exports with an unknown type or invalid filters
+var ErrInvalidExport = errors.New("invalid survey export")
+
+// SurveyExportFilters is the JSON stored in SurveyExport.Filters
+type SurveyExportFilters struct {
+	StartDate         *time.Time     `json:"startDate,omitempty"`
+	EndDate           *time.Time     `json:"endDate,omitempty"`
+	Status            ResponseStatus `json:"status,omitempty"`
+	IncludeIncomplete bool           `json:"includeIncomplete,omitempty"` // Export responses in progress and abandoned too
+}
+
+// GetFilters parses the export filters
+func (e *SurveyExport) GetFilters() (*SurveyExportFilters, error) {
+	var filters SurveyExportFilters
+	if strings.TrimSpace(e.Filters) == "" {
+		return &filters, nil
+	}
+	if err := json.Unmarshal([]byte(e.Filters), &filters); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	return &filters, nil
+}
+
+// SetFilters stores the export filters as JSON
+func (e *SurveyExport) SetFilters(filters *SurveyExportFilters) error {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	e.Filters = string(data)
+	return nil
+}
+
+// Validate validates the export type and filters
+func (e *SurveyExport) Validate() error {
+	switch e.ExportType {
+	case SurveyExportTypeCSV, SurveyExportTypeXLSX, SurveyExportTypeJSON, SurveyExportTypeSPSS:
+	default:
+		return fmt.Errorf("%w: unsupported export type %q", ErrInvalidExport, e.ExportType)
+	}
+	filters, err := e.GetFilters()
+	if err != nil {
+		return err
+	}
+	if filters.StartDate != nil && filters.EndDate != nil && filters.EndDate.Before(*filters.StartDate) {
+		return fmt.Errorf("%w: end date is before start date", ErrInvalidExport)
+	}
+	return nil
+}
+
+// FileExtension returns the extension of the exported file
+func (e *SurveyExport) FileExtension() string {
+	switch e.ExportType {
+	case SurveyExportTypeXLSX:
+		return "xlsx"
+	case SurveyExportTypeJSON:
+		return "json"
+	case SurveyExportTypeSPSS:
+		return "zip"
+	default:
+		return "csv"
+	}
+}
+
+// exportColumnKind tells how a column reads its value from an answer
+type exportColumnKind int
+
+const (
+	exportColumnValue   exportColumnKind = iota // The whole answer
+	exportColumnOption                          // Whether a checkbox option was selected
+	exportColumnOther                           // Checkbox selections that are not among the options
+	exportColumnMatrix                          // The column chosen for a matrix row
+	exportColumnRanking                         // The rank given to an item
+	exportColumnFiles                           // The links of uploaded files
+)
+
+// SurveyExportColumn is one column of a flattened survey export. Checkbox, matrix and ranking
+// questions are spread over a column per option, row or item.
+type SurveyExportColumn struct {
+	Name         string    // Short variable name, e.g. Q3_2
+	Label        string    // Question text, followed by the option, row or item of a flattened column
+	QuestionID   uuid.UUID // Question the column reads
+	Option       string    // Checkbox option, matrix row or ranking item
+	Choices      []string  // Choices coded 1..n in SPSS-style exports
+	kind         exportColumnKind
+	options      []string     // Question options, which the other column leaves out
+	questionType QuestionType // Type of the question, to tell numeric columns apart
+}
+
+// BuildSurveyExportColumns flattens the questions, in survey order, into export columns
+func BuildSurveyExportColumns(questions []SurveyQuestion) []SurveyExportColumn {
+	ordered := make([]SurveyQuestion, len(questions))
+	copy(ordered, questions)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	var columns []SurveyExportColumn
+	for i, question := range ordered {
+		first := len(columns)
+		name := fmt.Sprintf("Q%d", i+1)
+		label := strings.TrimSpace(question.QuestionText)
+		switch question.QuestionType {
+		case QuestionTypeCheckbox:
+			for j, option := range question.Options {
+				columns = append(columns, SurveyExportColumn{
+					Name: fmt.Sprintf("%s_%d", name, j+1), Label: label + " - " + option,
+					QuestionID: question.ID, Option: option, kind: exportColumnOption,
+				})
+			}
+			columns = append(columns, SurveyExportColumn{
+				Name: name + "_other", Label: label + " - Other", QuestionID: question.ID, kind: exportColumnOther, options: question.Options,
+			})
+		case QuestionTypeMatrix:
+			var matrixColumns []string
+			if settings, err := question.GetMatrixSettings(); err == nil {
+				matrixColumns = settings.Columns
+			}
+			for j, row := range question.Options {
+				columns = append(columns, SurveyExportColumn{
+					Name: fmt.Sprintf("%s_%d", name, j+1), Label: label + " - " + row,
+					QuestionID: question.ID, Option: row, Choices: matrixColumns, kind: exportColumnMatrix,
+				})
+			}
+		case QuestionTypeRanking:
+			for j, item := range question.Options {
+				columns = append(columns, SurveyExportColumn{
+					Name: fmt.Sprintf("%s_%d", name, j+1), Label: label + " - " + item,
+					QuestionID: question.ID, Option: item, kind: exportColumnRanking,
+				})
+			}
+		default:
+			column := SurveyExportColumn{Name: name, Label: label, QuestionID: question.ID, kind: exportColumnValue}
+			switch question.QuestionType {
+			case QuestionTypeRadio, QuestionTypeDropdown:
+				column.Choices = question.Options
+			case QuestionTypeYesNo:
+				column.Choices = []string{"false", "true"}
+			case QuestionTypeFile:
+				column.kind = exportColumnFiles
+			}
+			columns = append(columns, column)
+		}
+		for j := first; j < len(columns); j++ {
+			columns[j].questionType = question.QuestionType
+		}
+	}
+	return columns
+}
+
+// IsNumeric checks if the column holds numbers in SPSS-style exports: codes, ranks and the answers
+// of numeric questions
+func (c *SurveyExportColumn) IsNumeric() bool {
+	switch c.kind {
+	case exportColumnOption, exportColumnRanking:
+		return true
+	case exportColumnOther, exportColumnFiles:
+		return false
+	}
+	if len(c.Choices) > 0 {
+		return true
+	}
+	switch c.questionType {
+	case QuestionTypeNumber, QuestionTypeRating, QuestionTypeScale:
+		return true
+	}
+	return false
+}
+
+// Value returns the column's value for an answer, or "" when the question was not answered
+func (c *SurveyExportColumn) Value(answer *SurveyAnswer) string {
+	if answer == nil || answer.IsSkipped || !answer.HasValue() {
+		return ""
+	}
+	switch c.kind {
+	case exportColumnOption:
+		if containsAny(answerValues(answer), []string{c.Option}) {
+			return "1"
+		}
+		return "0"
+	case exportColumnOther:
+		var other []string
+		for _, value := range answerValues(answer) {
+			if value != "" && !containsAny(c.options, []string{value}) {
+				other = append(other, value)
+			}
+		}
+		return strings.Join(other, "; ")
+	case exportColumnMatrix:
+		matrix, err := answer.GetMatrix()
+		if err != nil {
+			return ""
+		}
+		return strings.Join(matrix[c.Option], "; ")
+	case exportColumnFiles:
+		files, err := answer.GetFiles()
+		if err != nil {
+			return ""
+		}
+		urls := make([]string, 0, len(files))
+		for _, file := range files {
+			urls = append(urls, file.URL)
+		}
+		return strings.Join(urls, "; ")
+	case exportColumnRanking:
+		for i, item := range rankingItems(answer) {
+			if item == c.Option {
+				return strconv.Itoa(i + 1)
+			}
+		}
+		return ""
+	}
+	if answer.AnswerDate != nil && answer.AnswerText == "" {
+		return answer.AnswerDate.Format(time.RFC3339)
+	}
+	if answer.AnswerJSON != "" && len(answerValues(answer)) == 0 {
+		return answer.AnswerJSON
+	}
+	return strings.Join(answerValues(answer), "; ")
+}
+
+// Code returns the value coded for SPSS-style exports: choices become their 1-based position.
+// Values that are not among the choices are kept as they are.
+func (c *SurveyExportColumn) Code(answer *SurveyAnswer) string {
+	value := c.Value(answer)
+	if value == "" || len(c.Choices) == 0 {
+		return value
+	}
+	for i, choice := range c.Choices {
+		if choice == value {
+			return strconv.Itoa(i + 1)
+		}
+	}
+	return value
+}
+
+// ValueLabels returns the codes of an SPSS-style export column with their labels
+func (c *SurveyExportColumn) ValueLabels() [][2]string {
+	if c.kind == exportColumnOption {
+		return [][2]string{{"0", "Not selected"}, {"1", "Selected"}}
+	}
+	labels := make([][2]string, 0, len(c.Choices))
+	for i, choice := range c.Choices {
+		labels = append(labels, [2]string{strconv.Itoa(i + 1), choice})
+	}
+	return labels
+}
+
+// rankingItems returns the ranked items of an answer, best first
+func rankingItems(answer *SurveyAnswer) []string {
+	if len(answer.AnswerArray) > 0 {
+		return answer.AnswerArray
+	}
+	var items []string
+	if err := json.Unmarshal([]byte(answer.AnswerJSON), &items); err != nil {
+		return nil
+	}
+	return items
+}
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestSurveyExportColumnsFlattenAnswers(t *testing.T) {
+	questions := []SurveyQuestion{
+		{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeCheckbox, QuestionText: "Topics", Options: []string{"AI", "Cloud"}},
+		{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeRadio, QuestionText: "Role", Options: []string{"Speaker", "Attendee"}},
+		{ID: uuid.New(), Order: 3, QuestionType: QuestionTypeMatrix, QuestionText: "Rate", Options: []string{"Venue", "Food"},
+			Metadata: `{"columns":["Poor","Good"]}`},
+		{ID: uuid.New(), Order: 4, QuestionType: QuestionTypeRanking, QuestionText: "Rank", Options: []string{"Keynote", "Workshop"}},
+		{ID: uuid.New(), Order: 5, QuestionType: QuestionTypeFile, QuestionText: "Slides"},
+	}
+	columns := BuildSurveyExportColumns(questions)
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	want := []string{"Q1", "Q2_1", "Q2_2", "Q2_other", "Q3_1", "Q3_2", "Q4_1", "Q4_2", "Q5"}
+	if len(names) != len(want) {
+		t.Fatalf("columns = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("columns = %v, want %v", names, want)
+		}
+	}
+
+	answers := map[uuid.UUID]*SurveyAnswer{
+		questions[1].ID: {AnswerText: "Attendee"},
+		questions[0].ID: {AnswerArray: pq.StringArray{"Cloud", "Blockchain"}},
+		questions[2].ID: {AnswerJSON: `{"Venue":"Good","Food":"Poor"}`},
+		questions[3].ID: {AnswerArray: pq.StringArray{"Workshop", "Keynote"}},
+		questions[4].ID: {AnswerJSON: `[{"key":"a","name":"a.pdf","url":"/uploads/a.pdf"},{"key":"b","name":"b.png","url":"/uploads/b.png"}]`},
+	}
+	tests := []struct {
+		column      string
+		value, code string
+	}{
+		{"Q1", "Attendee", "2"},
+		{"Q2_1", "0", "0"},
+		{"Q2_2", "1", "1"},
+		{"Q2_other", "Blockchain", "Blockchain"},
+		{"Q3_1", "Good", "2"},
+		{"Q3_2", "Poor", "1"},
+		{"Q4_1", "2", "2"},
+		{"Q4_2", "1", "1"},
+		{"Q5", "/uploads/a.pdf; /uploads/b.png", "/uploads/a.pdf; /uploads/b.png"},
+	}
+	for i, tt := range tests {
+		column := columns[i]
+		answer := answers[column.QuestionID]
+		if got := column.Value(answer); got != tt.value {
+			t.Errorf("%s value = %q, want %q", tt.column, got, tt.value)
+		}
+		if got := column.Code(answer); got != tt.code {
+			t.Errorf("%s code = %q, want %q", tt.column, got, tt.code)
+		}
+	}
+
+	if got := columns[0].Value(&SurveyAnswer{AnswerText: "Speaker", IsSkipped: true}); got != "" {
+		t.Errorf("skipped answer exported as %q", got)
+	}
+}
+
+func TestSurveyExportValidate(t *testing.T) {
+	export := SurveyExport{ExportType: "pdf"}
+	if err := export.Validate(); err == nil {
+		t.Error("expected an unsupported export type to fail validation")
+	}
+
+	export = SurveyExport{ExportType: SurveyExportTypeSPSS, Filters: `{"status":"submitted"}`}
+	if err := export.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if export.FileExtension() != "zip" {
+		t.Errorf("extension = %q, want zip", export.FileExtension())
+	}
+}
@@ -79,10 +79,12 @@ type SurveyNotification struct {
 	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	SurveyID   uuid.UUID      `json:"surveyId" gorm:"type:uuid;not null;index"`
 	Survey     *Survey        `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
-	EventType  string         `json:"eventType" gorm:"not null;size:50"` // response_submitted, survey_completed, etc.
-	Recipients pq.StringArray `json:"recipients" gorm:"type:text[]"`     // email addresses
+	EventType  string         `json:"eventType" gorm:"not null;size:50"`               // response_submitted, quota_reached, survey_closed
+	Channel    string         `json:"channel" gorm:"not null;size:20;default:'email'"` // email, wechat, webhook
+	Recipients pq.StringArray `json:"recipients" gorm:"type:text[]"`                   // email addresses, OpenIDs or webhook URLs
+	Conditions string         `json:"conditions" gorm:"type:jsonb"`                    // Answers a response must have to be notified
 	Subject    string         `json:"subject" gorm:"size:255"`
-	Message    string         `json:"message" gorm:"type:text"`
+	Message    string         `json:"message" gorm:"type:text"` // {placeholders} are filled from the event
 	IsActive   bool           `json:"isActive" gorm:"default:true"`
 	LastSent   *time.Time     `json:"lastSent"`
 	SendCount  int            `json:"sendCount" gorm:"default:0"`
@@ -100,19 +102,25 @@ type SurveyInvitation struct {
 	ID          uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	SurveyID    uuid.UUID       `json:"surveyId" gorm:"type:uuid;not null;index"`
 	Survey      *Survey         `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
+	Channel     string          `json:"channel" gorm:"not null;size:20;default:'email'"` // email, wechat
 	Email       string          `json:"email" gorm:"not null;size:255;index"`
+	OpenID      string          `json:"openId" gorm:"size:100;index"` // WeChat follower of wechat invitations
 	Name        string          `json:"name" gorm:"size:255"`
 	Token       string          `json:"token" gorm:"not null;size:255;uniqueIndex"`
-	Status      string          `json:"status" gorm:"not null;size:50;default:'sent'"` // sent, opened, responded, expired
+	Status      string          `json:"status" gorm:"not null;size:50;default:'sent'"` // sent, opened, responded, expired, failed
 	SentAt      time.Time       `json:"sentAt" gorm:"autoCreateTime"`
 	OpenedAt    *time.Time      `json:"openedAt"`
 	RespondedAt *time.Time      `json:"respondedAt"`
 	ExpiresAt   *time.Time      `json:"expiresAt"`
 	ResponseID  *uuid.UUID      `json:"responseId" gorm:"type:uuid;index"`
 	Response    *SurveyResponse `json:"response,omitempty" gorm:"foreignKey:ResponseID"`
-	Metadata    string          `json:"metadata" gorm:"type:jsonb"`
-	CreatedAt   time.Time       `json:"createdAt" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time       `json:"updatedAt" gorm:"autoUpdateTime"`
+	// Reminders sent to non-responders
+	ReminderCount int        `json:"reminderCount" gorm:"not null;default:0"`
+	RemindedAt    *time.Time `json:"remindedAt"`
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"` // Why the last delivery failed
+	Metadata      string     `json:"metadata" gorm:"type:jsonb"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
 }
 
 // TableName returns the table name for SurveyInvitation
@@ -120,22 +128,26 @@ func (SurveyInvitation) TableName() string {
 	return "survey_invitations"
 }
 
-// SurveyCollector represents different ways to collect survey responses
+// SurveyCollector is one way of collecting responses to a survey, with its own link, QR code,
+// schedule and response limit. Responses record the collector they came through.
 type SurveyCollector struct {
-	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	SurveyID      uuid.UUID `json:"surveyId" gorm:"type:uuid;not null;index"`
-	Survey        *Survey   `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
-	Name          string    `json:"name" gorm:"not null;size:255"`
-	Type          string    `json:"type" gorm:"not null;size:50"` // web_link, email, qr_code, embed, api
-	URL           string    `json:"url" gorm:"size:500"`
-	EmbedCode     string    `json:"embedCode" gorm:"type:text"`
-	QRCodeURL     string    `json:"qrCodeUrl" gorm:"size:500"`
-	IsActive      bool      `json:"isActive" gorm:"default:true"`
-	ResponseCount int       `json:"responseCount" gorm:"default:0"`
-	Settings      string    `json:"settings" gorm:"type:jsonb"` // JSON settings for collector
-	CreatedBy     uuid.UUID `json:"createdBy" gorm:"type:uuid;not null"`
-	CreatedAt     time.Time `json:"createdAt" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SurveyID      uuid.UUID  `json:"surveyId" gorm:"type:uuid;not null;index"`
+	Survey        *Survey    `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
+	Name          string     `json:"name" gorm:"not null;size:255"`
+	Type          string     `json:"type" gorm:"not null;size:50"` // web_link, email, qr_code, embed, api
+	URL           string     `json:"url" gorm:"size:500"`
+	EmbedCode     string     `json:"embedCode" gorm:"type:text"`
+	QRCodeURL     string     `json:"qrCodeUrl" gorm:"size:500"`
+	IsActive      bool       `json:"isActive" gorm:"default:true"` // Closed collectors reject new responses
+	OpensAt       *time.Time `json:"opensAt"`
+	ClosesAt      *time.Time `json:"closesAt"`
+	MaxResponses  *int       `json:"maxResponses"` // Responses accepted through the collector, nil for no limit
+	ResponseCount int        `json:"responseCount" gorm:"default:0"`
+	Settings      string     `json:"settings" gorm:"type:jsonb"` // JSON settings for collector, see GetSettings
+	CreatedBy     uuid.UUID  `json:"createdBy" gorm:"type:uuid;not null"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
 }
 
 // TableName returns the table name for SurveyCollector
@@ -145,17 +157,18 @@ func (SurveyCollector) TableName() string {
 
 // SurveyQuota represents quotas for survey responses
 type SurveyQuota struct {
-	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	SurveyID     uuid.UUID `json:"surveyId" gorm:"type:uuid;not null;index"`
-	Survey       *Survey   `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
-	Name         string    `json:"name" gorm:"not null;size:255"`
-	Description  string    `json:"description" gorm:"type:text"`
-	Conditions   string    `json:"conditions" gorm:"type:jsonb;not null"` // JSON conditions
-	MaxResponses int       `json:"maxResponses" gorm:"not null"`
-	CurrentCount int       `json:"currentCount" gorm:"default:0"`
-	IsActive     bool      `json:"isActive" gorm:"default:true"`
-	CreatedAt    time.Time `json:"createdAt" gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SurveyID         uuid.UUID `json:"surveyId" gorm:"type:uuid;not null;index"`
+	Survey           *Survey   `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
+	Name             string    `json:"name" gorm:"not null;size:255"`
+	Description      string    `json:"description" gorm:"type:text"`
+	Conditions       string    `json:"conditions" gorm:"type:jsonb;not null"` // JSON conditions
+	MaxResponses     int       `json:"maxResponses" gorm:"not null"`
+	CurrentCount     int       `json:"currentCount" gorm:"default:0"`
+	IsActive         bool      `json:"isActive" gorm:"default:true"`
+	ScreenOutMessage string    `json:"screenOutMessage" gorm:"type:text"` // Shown to respondents screened out by a full quota
+	CreatedAt        time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
 }
 
 // TableName returns the table name for SurveyQuota
@@ -215,7 +228,7 @@ type SurveyExport struct {
 	Survey       *Survey    `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
 	ExportType   string     `json:"exportType" gorm:"not null;size:50"`               // csv, excel, pdf, json
 	Status       string     `json:"status" gorm:"not null;size:50;default:'pending'"` // pending, processing, completed, failed
-	FileURL      string     `json:"fileUrl" gorm:"size:500"`
+	FileKey      string     `json:"-" gorm:"column:file_url;size:500"`                // Private storage key of the file, cleared once it is deleted
 	FileSize     int64      `json:"fileSize" gorm:"default:0"`
 	RecordCount  int        `json:"recordCount" gorm:"default:0"`
 	Filters      string     `json:"filters" gorm:"type:jsonb"` // JSON export filters
@@ -245,7 +258,7 @@ func (i *SurveyInvitation) IsExpired() bool {
 
 // IsResponded checks if the invitation has been responded to
 func (i *SurveyInvitation) IsResponded() bool {
-	return i.Status == "responded" && i.ResponseID != nil
+	return i.Status == SurveyInvitationStatusResponded && i.ResponseID != nil
 }
 
 // IsQuotaFull checks if the quota is full
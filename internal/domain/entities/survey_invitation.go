@@ -0,0 +1,164 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Survey invitation statuses
+const (
+	SurveyInvitationStatusSent      = "sent"
+	SurveyInvitationStatusOpened    = "opened"
+	SurveyInvitationStatusResponded = "responded"
+	SurveyInvitationStatusExpired   = "expired"
+	SurveyInvitationStatusFailed    = "failed" // Delivery failed; retried with the reminders
+)
+
+// Survey invitation delivery channels
+const (
+	SurveyInvitationChannelEmail  = "email"
+	SurveyInvitationChannelWeChat = "wechat" // WeChat template message to a follower
+)
+
+// ErrInvalidInvitation is returned for invitations without a deliverable address and invalid invitee lists
+var ErrInvalidInvitation = errors.New("invalid survey invitation")
+
+// SurveyInvitee is a person to invite to a survey
+type SurveyInvitee struct {
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	OpenID string `json:"openId"`
+}
+
+// NewSurveyInvitationToken returns a random single-use token for an invitation link
+func NewSurveyInvitationToken() (string, error) {
+	data := make([]byte, 24)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Validate checks that the invitation can be delivered through its channel
+func (i *SurveyInvitation) Validate() error {
+	switch i.Channel {
+	case SurveyInvitationChannelEmail:
+		if _, err := mail.ParseAddress(i.Email); err != nil {
+			return fmt.Errorf("%w: invalid email %q", ErrInvalidInvitation, i.Email)
+		}
+	case SurveyInvitationChannelWeChat:
+		if strings.TrimSpace(i.OpenID) == "" {
+			return fmt.Errorf("%w: WeChat invitations need an OpenID", ErrInvalidInvitation)
+		}
+	default:
+		return fmt.Errorf("%w: unknown channel %q", ErrInvalidInvitation, i.Channel)
+	}
+	return nil
+}
+
+// Recipient returns the address the invitation is delivered to
+func (i *SurveyInvitation) Recipient() string {
+	if i.Channel == SurveyInvitationChannelWeChat {
+		return i.OpenID
+	}
+	return strings.ToLower(i.Email)
+}
+
+// MarkOpened records the first visit of the invitation link
+func (i *SurveyInvitation) MarkOpened(now time.Time) {
+	if i.OpenedAt == nil {
+		i.OpenedAt = &now
+	}
+	if i.Status == SurveyInvitationStatusSent || i.Status == SurveyInvitationStatusFailed {
+		i.Status = SurveyInvitationStatusOpened
+	}
+}
+
+// MarkResponded records the response submitted through the invitation
+func (i *SurveyInvitation) MarkResponded(responseID uuid.UUID, now time.Time) {
+	i.MarkOpened(now)
+	i.Status = SurveyInvitationStatusResponded
+	i.ResponseID = &responseID
+	i.RespondedAt = &now
+}
+
+// IsReminderDue reports whether a non-responder should be reminded: the invitation is still open,
+// fewer than maxReminders were sent and the last contact is at least interval ago
+func (i *SurveyInvitation) IsReminderDue(now time.Time, interval time.Duration, maxReminders int) bool {
+	switch i.Status {
+	case SurveyInvitationStatusSent, SurveyInvitationStatusOpened, SurveyInvitationStatusFailed:
+	default:
+		return false
+	}
+	if i.ExpiresAt != nil && !now.Before(*i.ExpiresAt) {
+		return false
+	}
+	if i.ReminderCount >= maxReminders {
+		return false
+	}
+	lastContact := i.SentAt
+	if i.RemindedAt != nil {
+		lastContact = *i.RemindedAt
+	}
+	return !now.Before(lastContact.Add(interval))
+}
+
+// ParseSurveyInviteeCSV reads an uploaded invitee list. The first row names the columns; name,
+// email and openid (or open_id) are recognised in any order and other columns are ignored.
+func ParseSurveyInviteeCSV(r io.Reader) ([]SurveyInvitee, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row: %v", ErrInvalidInvitation, err)
+	}
+	columns := map[string]int{"name": -1, "email": -1, "openid": -1}
+	for i, column := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		key = strings.ReplaceAll(key, "_", "")
+		if _, ok := columns[key]; ok {
+			columns[key] = i
+		}
+	}
+	if columns["email"] < 0 && columns["openid"] < 0 {
+		return nil, fmt.Errorf("%w: the list needs an email or openid column", ErrInvalidInvitation)
+	}
+
+	field := func(record []string, column string) string {
+		if i := columns[column]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var invitees []SurveyInvitee
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInvitation, err)
+		}
+		invitee := SurveyInvitee{
+			Name:   field(record, "name"),
+			Email:  field(record, "email"),
+			OpenID: field(record, "openid"),
+		}
+		if invitee.Email == "" && invitee.OpenID == "" {
+			continue
+		}
+		invitees = append(invitees, invitee)
+	}
+	return invitees, nil
+}
@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSurveyInviteeCSV(t *testing.T) {
+	list := "\ufeffEmail,Name,Company\n" +
+		"ann@example.com,Ann,Acme\n" +
+		",Nobody,Acme\n" +
+		"bob@example.com,Bob\n"
+	invitees, err := ParseSurveyInviteeCSV(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invitees) != 2 {
+		t.Fatalf("Expected 2 invitees, got %d", len(invitees))
+	}
+	if invitees[0].Email != "ann@example.com" || invitees[0].Name != "Ann" {
+		t.Errorf("Expected Ann, got %+v", invitees[0])
+	}
+	if invitees[1].Name != "Bob" {
+		t.Errorf("Expected Bob, got %+v", invitees[1])
+	}
+
+	invitees, err = ParseSurveyInviteeCSV(strings.NewReader("open_id,name\noABC,Carol\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invitees) != 1 || invitees[0].OpenID != "oABC" {
+		t.Errorf("Expected the OpenID column to be read, got %+v", invitees)
+	}
+
+	if _, err := ParseSurveyInviteeCSV(strings.NewReader("name,phone\nDan,123\n")); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("Expected ErrInvalidInvitation without an address column, got %v", err)
+	}
+}
+
+func TestSurveyInvitationValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		invitation SurveyInvitation
+		valid      bool
+	}{
+		{"email", SurveyInvitation{Channel: SurveyInvitationChannelEmail, Email: "ann@example.com"}, true},
+		{"bad email", SurveyInvitation{Channel: SurveyInvitationChannelEmail, Email: "ann"}, false},
+		{"wechat", SurveyInvitation{Channel: SurveyInvitationChannelWeChat, OpenID: "oABC"}, true},
+		{"wechat without OpenID", SurveyInvitation{Channel: SurveyInvitationChannelWeChat, Email: "ann@example.com"}, false},
+		{"unknown channel", SurveyInvitation{Channel: "sms", Email: "ann@example.com"}, false},
+	}
+	for _, tt := range tests {
+		err := tt.invitation.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: Expected valid = %v, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
+
+func TestSurveyInvitationStatusTransitions(t *testing.T) {
+	now := time.Now()
+	invitation := SurveyInvitation{Status: SurveyInvitationStatusSent}
+
+	invitation.MarkOpened(now)
+	if invitation.Status != SurveyInvitationStatusOpened || invitation.OpenedAt == nil {
+		t.Errorf("Expected opened invitation, got %s", invitation.Status)
+	}
+
+	responseID := uuid.New()
+	invitation.MarkResponded(responseID, now.Add(time.Minute))
+	if !invitation.IsResponded() || *invitation.ResponseID != responseID {
+		t.Errorf("Expected responded invitation, got %s", invitation.Status)
+	}
+	if !invitation.OpenedAt.Equal(now) {
+		t.Errorf("Expected the first opening to be kept, got %v", invitation.OpenedAt)
+	}
+
+	// Opening the link again does not reopen a responded invitation
+	invitation.MarkOpened(now.Add(time.Hour))
+	if invitation.Status != SurveyInvitationStatusResponded {
+		t.Errorf("Expected responded invitation, got %s", invitation.Status)
+	}
+}
+
+func TestSurveyInvitationIsReminderDue(t *testing.T) {
+	now := time.Now()
+	sent := now.Add(-4 * 24 * time.Hour)
+	expired := now.Add(-time.Hour)
+	recent := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		invitation SurveyInvitation
+		want       bool
+	}{
+		{"due", SurveyInvitation{Status: SurveyInvitationStatusSent, SentAt: sent}, true},
+		{"failed delivery", SurveyInvitation{Status: SurveyInvitationStatusFailed, SentAt: sent}, true},
+		{"responded", SurveyInvitation{Status: SurveyInvitationStatusResponded, SentAt: sent}, false},
+		{"expired", SurveyInvitation{Status: SurveyInvitationStatusOpened, SentAt: sent, ExpiresAt: &expired}, false},
+		{"reminded recently", SurveyInvitation{Status: SurveyInvitationStatusSent, SentAt: sent, ReminderCount: 1, RemindedAt: &recent}, false},
+		{"reminder limit", SurveyInvitation{Status: SurveyInvitationStatusSent, SentAt: sent, ReminderCount: 2}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.invitation.IsReminderDue(now, 72*time.Hour, 2); got != tt.want {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Survey live poll statuses
+const (
+	SurveyLivePollStatusOpen   = "open"
+	SurveyLivePollStatusClosed = "closed"
+)
+
+// maxLivePollRatingSteps bounds the rating values listed in live poll results when nobody chose them
+const maxLivePollRatingSteps = 20
+
+// SurveyLivePoll puts one survey question to the audience of an event in real time. Attendees join
+// with the interaction code of the event; votes are counted outside the database while the poll is
+// open and the final tally is stored when it closes.
+type SurveyLivePoll struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SurveyID   uuid.UUID  `json:"surveyId" gorm:"type:uuid;not null;index"`
+	QuestionID uuid.UUID  `json:"questionId" gorm:"type:uuid;not null"`
+	EventID    uuid.UUID  `json:"eventId" gorm:"type:uuid;not null;index"` // Site event whose interaction code attendees join with
+	Status     string     `json:"status" gorm:"not null;size:20;default:'open'"`
+	VoteCount  int        `json:"voteCount" gorm:"default:0"` // Votes counted when the poll closed
+	Tally      string     `json:"-" gorm:"type:jsonb"`        // Final tally, see GetTally
+	OpenedBy   *uuid.UUID `json:"openedBy" gorm:"type:uuid"`
+	OpenedAt   time.Time  `json:"openedAt"`
+	ClosedAt   *time.Time `json:"closedAt"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Computed fields
+	Question *SurveyQuestion `json:"question,omitempty" gorm:"-"`
+}
+
+// TableName returns the table name for SurveyLivePoll
+func (SurveyLivePoll) TableName() string {
+	return "survey_live_polls"
+}
+
+// IsOpen reports whether the poll accepts votes
+func (p *SurveyLivePoll) IsOpen() bool {
+	return p.Status == SurveyLivePollStatusOpen
+}
+
+// Close stops the poll, keeping the final tally
+func (p *SurveyLivePoll) Close(tally *SurveyLivePollTally, at time.Time) error {
+	data, err := json.Marshal(tally)
+	if err != nil {
+		return fmt.Errorf("failed to encode live poll tally: %w", err)
+	}
+	p.Status = SurveyLivePollStatusClosed
+	p.Tally = string(data)
+	p.VoteCount = tally.Votes
+	p.ClosedAt = &at
+	return nil
+}
+
+// GetTally returns the tally stored when the poll closed
+func (p *SurveyLivePoll) GetTally() (*SurveyLivePollTally, error) {
+	tally := &SurveyLivePollTally{Counts: map[string]int{}}
+	if strings.TrimSpace(p.Tally) == "" {
+		return tally, nil
+	}
+	if err := json.Unmarshal([]byte(p.Tally), tally); err != nil {
+		return nil, fmt.Errorf("failed to decode live poll tally: %w", err)
+	}
+	if tally.Counts == nil {
+		tally.Counts = map[string]int{}
+	}
+	return tally, nil
+}
+
+// SurveyLivePollTally counts the votes of a live poll by choice
+type SurveyLivePollTally struct {
+	Votes  int            `json:"votes"`
+	Counts map[string]int `json:"counts"`
+}
+
+// SupportsLivePoll reports whether the question can be put to a live poll: the audience picks from
+// its options or gives a rating, so the votes can be counted as they come in
+func (q *SurveyQuestion) SupportsLivePoll() bool {
+	switch q.QuestionType {
+	case QuestionTypeRadio, QuestionTypeDropdown, QuestionTypeYesNo, QuestionTypeCheckbox:
+		return len(q.Options) > 0
+	case QuestionTypeRating, QuestionTypeScale:
+		return true
+	default:
+		return false
+	}
+}
+
+// ratingBounds returns the range of rating and scale answers, 1 to 5 unless the options set it
+func (q *SurveyQuestion) ratingBounds() (float64, float64) {
+	minRating, maxRating := 1.0, 5.0
+	if len(q.Options) >= 2 {
+		if min, err := strconv.ParseFloat(q.Options[0], 64); err == nil {
+			minRating = min
+		}
+		if max, err := strconv.ParseFloat(q.Options[1], 64); err == nil {
+			maxRating = max
+		}
+	}
+	return minRating, maxRating
+}
+
+// SurveyLivePollVote is a vote of the audience on a live poll
+type SurveyLivePollVote struct {
+	PollID  uuid.UUID `json:"pollId"`
+	VoterID string    `json:"-"`       // Identifies the voter, who votes once per poll; set by the server
+	Options []string  `json:"options"` // Chosen options, or the rating of rating and scale questions
+}
+
+// Choices validates the vote against the question of the poll and returns the choices to count
+func (v *SurveyLivePollVote) Choices(question *SurveyQuestion) ([]string, error) {
+	if len(v.Options) == 0 {
+		return nil, errors.New("choose an option")
+	}
+
+	switch question.QuestionType {
+	case QuestionTypeRadio, QuestionTypeDropdown, QuestionTypeYesNo:
+		if len(v.Options) != 1 {
+			return nil, errors.New("choose one option")
+		}
+		if !containsOption(question.Options, v.Options[0]) {
+			return nil, errors.New("invalid option selected")
+		}
+		return v.Options, nil
+	case QuestionTypeCheckbox:
+		seen := make(map[string]bool, len(v.Options))
+		for _, option := range v.Options {
+			if seen[option] {
+				return nil, fmt.Errorf("option %s chosen more than once", option)
+			}
+			seen[option] = true
+		}
+		answer := SurveyAnswer{AnswerArray: v.Options}
+		if err := answer.ValidateByType(question); err != nil {
+			return nil, err
+		}
+		return v.Options, nil
+	case QuestionTypeRating, QuestionTypeScale:
+		if len(v.Options) != 1 {
+			return nil, errors.New("give one rating")
+		}
+		value, err := strconv.ParseFloat(v.Options[0], 64)
+		if err != nil {
+			return nil, errors.New("invalid rating")
+		}
+		answer := SurveyAnswer{AnswerNumber: &value}
+		if err := answer.ValidateByType(question); err != nil {
+			return nil, err
+		}
+		return []string{strconv.FormatFloat(value, 'f', -1, 64)}, nil
+	default:
+		return nil, fmt.Errorf("%s questions cannot be used in live polls", question.QuestionType)
+	}
+}
+
+// SurveyLivePollResults are the results of a live poll as shown on the big screen
+type SurveyLivePollResults struct {
+	PollID       uuid.UUID                    `json:"pollId"`
+	QuestionID   uuid.UUID                    `json:"questionId"`
+	QuestionText string                       `json:"questionText"`
+	QuestionType QuestionType                 `json:"questionType"`
+	Status       string                       `json:"status"`
+	Votes        int                          `json:"votes"`
+	Options      []SurveyLivePollOptionResult `json:"options"`
+	Average      *float64                     `json:"average,omitempty"` // Rating and scale questions
+}
+
+// SurveyLivePollOptionResult counts the votes for one option of a live poll. Percentages are
+// shares of the voters, so they add up to more than 100 for checkbox questions.
+type SurveyLivePollOptionResult struct {
+	Option     string  `json:"option"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// NewSurveyLivePollResults lays out the tally of a poll by the options of its question
+func NewSurveyLivePollResults(poll *SurveyLivePoll, question *SurveyQuestion, tally *SurveyLivePollTally) *SurveyLivePollResults {
+	results := &SurveyLivePollResults{
+		PollID:       poll.ID,
+		QuestionID:   question.ID,
+		QuestionText: question.QuestionText,
+		QuestionType: question.QuestionType,
+		Status:       poll.Status,
+		Votes:        tally.Votes,
+	}
+
+	var options []string
+	switch question.QuestionType {
+	case QuestionTypeRating, QuestionTypeScale:
+		options = livePollRatingOptions(question, tally)
+		if tally.Votes > 0 {
+			var sum float64
+			for option, count := range tally.Counts {
+				if value, err := strconv.ParseFloat(option, 64); err == nil {
+					sum += value * float64(count)
+				}
+			}
+			average := math.Round(sum/float64(tally.Votes)*100) / 100
+			results.Average = &average
+		}
+	default:
+		options = question.Options
+	}
+
+	results.Options = make([]SurveyLivePollOptionResult, 0, len(options))
+	for _, option := range options {
+		result := SurveyLivePollOptionResult{Option: option, Count: tally.Counts[option]}
+		if tally.Votes > 0 {
+			result.Percentage = math.Round(float64(result.Count)/float64(tally.Votes)*10000) / 100
+		}
+		results.Options = append(results.Options, result)
+	}
+	return results
+}
+
+// livePollRatingOptions lists the whole steps of the rating range, plus any other ratings given,
+// in ascending order
+func livePollRatingOptions(question *SurveyQuestion, tally *SurveyLivePollTally) []string {
+	minRating, maxRating := question.ratingBounds()
+	values := make(map[float64]bool)
+	if maxRating-minRating <= maxLivePollRatingSteps {
+		for value := math.Ceil(minRating); value <= maxRating; value++ {
+			values[value] = true
+		}
+	}
+	for option := range tally.Counts {
+		if value, err := strconv.ParseFloat(option, 64); err == nil {
+			values[value] = true
+		}
+	}
+
+	sorted := make([]float64, 0, len(values))
+	for value := range values {
+		sorted = append(sorted, value)
+	}
+	sort.Float64s(sorted)
+	options := make([]string, len(sorted))
+	for i, value := range sorted {
+		options[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return options
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestSurveyLivePollVoteChoices(t *testing.T) {
+	radio := &SurveyQuestion{QuestionType: QuestionTypeRadio, Options: pq.StringArray{"AI", "Web"}}
+	checkbox := &SurveyQuestion{QuestionType: QuestionTypeCheckbox, Options: pq.StringArray{"AI", "Web", "Cloud"}}
+	scale := &SurveyQuestion{QuestionType: QuestionTypeScale, Options: pq.StringArray{"0", "10"}}
+	text := &SurveyQuestion{QuestionType: QuestionTypeText}
+
+	tests := []struct {
+		name     string
+		question *SurveyQuestion
+		options  []string
+		expected []string
+	}{
+		{"single choice", radio, []string{"Web"}, []string{"Web"}},
+		{"unknown option", radio, []string{"Cloud"}, nil},
+		{"two single choices", radio, []string{"AI", "Web"}, nil},
+		{"no choice", radio, nil, nil},
+		{"multiple choice", checkbox, []string{"AI", "Cloud"}, []string{"AI", "Cloud"}},
+		{"repeated choice", checkbox, []string{"AI", "AI"}, nil},
+		{"rating", scale, []string{"7.0"}, []string{"7"}},
+		{"rating out of range", scale, []string{"11"}, nil},
+		{"not a rating", scale, []string{"good"}, nil},
+		{"text question", text, []string{"Hello"}, nil},
+	}
+	for _, tt := range tests {
+		vote := SurveyLivePollVote{Options: tt.options}
+		choices, err := vote.Choices(tt.question)
+		if tt.expected == nil {
+			if err == nil {
+				t.Errorf("%s: Expected an error, got %v", tt.name, choices)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(choices, tt.expected) {
+			t.Errorf("%s: Expected %v, got %v (%v)", tt.name, tt.expected, choices, err)
+		}
+	}
+}
+
+func TestNewSurveyLivePollResults(t *testing.T) {
+	poll := &SurveyLivePoll{ID: uuid.New(), Status: SurveyLivePollStatusOpen}
+	rating := &SurveyQuestion{ID: uuid.New(), QuestionType: QuestionTypeRating, QuestionText: "Rate the keynote"}
+	tally := &SurveyLivePollTally{Votes: 3, Counts: map[string]int{"5": 2, "2": 1}}
+
+	results := NewSurveyLivePollResults(poll, rating, tally)
+	if results.Votes != 3 || results.Average == nil || *results.Average != 4 {
+		t.Fatalf("Expected 3 votes averaging 4, got %d votes averaging %v", results.Votes, results.Average)
+	}
+	var options []string
+	for _, option := range results.Options {
+		options = append(options, option.Option)
+	}
+	if !reflect.DeepEqual(options, []string{"1", "2", "3", "4", "5"}) {
+		t.Errorf("Expected the rating steps, got %v", options)
+	}
+	if results.Options[4].Count != 2 || results.Options[4].Percentage != 66.67 {
+		t.Errorf("Expected 2 votes for 5, got %+v", results.Options[4])
+	}
+
+	// The tally stored when the poll closes gives the same results
+	if err := poll.Close(tally, time.Now()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stored, err := poll.GetTally()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if poll.IsOpen() || poll.VoteCount != 3 || !reflect.DeepEqual(stored, tally) {
+		t.Errorf("Expected closed poll with the tally, got %s with %+v", poll.Status, stored)
+	}
+}
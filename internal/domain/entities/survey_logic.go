@@ -0,0 +1,514 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Survey logic types
+const (
+	SurveyLogicSkip = "skip" // Hide the target questions when the conditions match
+	SurveyLogicShow = "show" // Hide the target questions unless the conditions match
+	SurveyLogicJump = "jump" // Hide every question between the trigger and the target
+	SurveyLogicEnd  = "end"  // End the survey after the trigger question
+)
+
+// Survey logic condition operators
+const (
+	LogicOperatorEquals         = "equals"
+	LogicOperatorNotEquals      = "not_equals"
+	LogicOperatorContains       = "contains"
+	LogicOperatorNotContains    = "not_contains"
+	LogicOperatorIn             = "in"
+	LogicOperatorNotIn          = "not_in"
+	LogicOperatorGreaterThan    = "greater_than"
+	LogicOperatorGreaterOrEqual = "greater_or_equal"
+	LogicOperatorLessThan       = "less_than"
+	LogicOperatorLessOrEqual    = "less_or_equal"
+	LogicOperatorAnswered       = "answered"
+	LogicOperatorNotAnswered    = "not_answered"
+)
+
+// Survey logic errors
+var (
+	ErrInvalidLogicType      = errors.New("invalid survey logic type")
+	ErrInvalidLogicCondition = errors.New("invalid survey logic condition")
+	ErrInvalidLogicAction    = errors.New("invalid survey logic action")
+)
+
+// SurveyLogicConditions is the JSON stored in SurveyLogic.Conditions. Rules are combined with
+// Match ("all" or "any"; all by default).
+type SurveyLogicConditions struct {
+	Match string            `json:"match,omitempty"`
+	Rules []SurveyLogicRule `json:"rules"`
+}
+
+// SurveyLogicRule compares the answer to a question with a value. Without a question ID the rule
+// reads the logic's trigger question.
+type SurveyLogicRule struct {
+	QuestionID *uuid.UUID  `json:"questionId,omitempty"`
+	Operator   string      `json:"operator"`
+	Value      interface{} `json:"value,omitempty"`
+}
+
+// SurveyLogicActions is the JSON stored in SurveyLogic.Actions
+type SurveyLogicActions struct {
+	QuestionIDs []uuid.UUID `json:"questionIds,omitempty"` // skip/show targets
+	Pages       []int       `json:"pages,omitempty"`       // skip/show every question on these pages
+	TargetID    *uuid.UUID  `json:"targetId,omitempty"`    // jump target question
+	TargetPage  int         `json:"targetPage,omitempty"`  // jump target page
+	Message     string      `json:"message,omitempty"`     // end message
+}
+
+// SurveyQuestionMetadata is the part of SurveyQuestion.Metadata the logic engine reads
+type SurveyQuestionMetadata struct {
+	Page int `json:"page,omitempty"`
+}
+
+// SurveyNavigation is the outcome of evaluating a survey's logic against a (partial) response
+type SurveyNavigation struct {
+	VisibleQuestionIDs []uuid.UUID
+	HiddenQuestionIDs  []uuid.UUID
+	NextQuestionID     *uuid.UUID // First visible question without an answer
+	NextPage           int
+	Pages              []int // Pages with at least one visible question
+	Ended              bool  // An end rule fired
+	EndMessage         string
+	Complete           bool // Every visible question has an answer, or the survey ended
+}
+
+// IsVisible checks if a question is visible in the navigation
+func (n *SurveyNavigation) IsVisible(questionID uuid.UUID) bool {
+	for _, id := range n.VisibleQuestionIDs {
+		if id == questionID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConditions parses the logic conditions. A bare array of rules is accepted as "all".
+func (l *SurveyLogic) GetConditions() (*SurveyLogicConditions, error) {
+	var conditions SurveyLogicConditions
+	trimmed := strings.TrimSpace(l.Conditions)
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &conditions.Rules); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLogicCondition, err)
+		}
+		return &conditions, nil
+	}
+	if err := json.Unmarshal([]byte(trimmed), &conditions); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogicCondition, err)
+	}
+	return &conditions, nil
+}
+
+// GetActions parses the logic actions
+func (l *SurveyLogic) GetActions() (*SurveyLogicActions, error) {
+	var actions SurveyLogicActions
+	if strings.TrimSpace(l.Actions) == "" {
+		return &actions, nil
+	}
+	if err := json.Unmarshal([]byte(l.Actions), &actions); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogicAction, err)
+	}
+	return &actions, nil
+}
+
+// Validate validates the logic type, conditions and actions
+func (l *SurveyLogic) Validate() error {
+	conditions, err := l.GetConditions()
+	if err != nil {
+		return err
+	}
+	if conditions.Match != "" && conditions.Match != "all" && conditions.Match != "any" {
+		return fmt.Errorf("%w: match must be all or any", ErrInvalidLogicCondition)
+	}
+	if len(conditions.Rules) == 0 {
+		return fmt.Errorf("%w: at least one rule is required", ErrInvalidLogicCondition)
+	}
+	for _, rule := range conditions.Rules {
+		if !isLogicOperator(rule.Operator) {
+			return fmt.Errorf("%w: unknown operator %q", ErrInvalidLogicCondition, rule.Operator)
+		}
+	}
+
+	actions, err := l.GetActions()
+	if err != nil {
+		return err
+	}
+	switch l.LogicType {
+	case SurveyLogicSkip, SurveyLogicShow:
+		if len(actions.QuestionIDs) == 0 && len(actions.Pages) == 0 {
+			return fmt.Errorf("%w: %s needs target questions or pages", ErrInvalidLogicAction, l.LogicType)
+		}
+	case SurveyLogicJump:
+		if actions.TargetID == nil && actions.TargetPage <= 0 {
+			return fmt.Errorf("%w: jump needs a target question or page", ErrInvalidLogicAction)
+		}
+	case SurveyLogicEnd:
+	default:
+		return ErrInvalidLogicType
+	}
+	return nil
+}
+
+// RemapQuestions points the trigger, rules and targets of the logic at other questions, e.g. the
+// copies of a survey's questions. Every question the logic refers to must be in the map.
+func (l *SurveyLogic) RemapQuestions(ids map[uuid.UUID]uuid.UUID) error {
+	remap := func(id uuid.UUID) (uuid.UUID, error) {
+		mapped, ok := ids[id]
+		if !ok {
+			return uuid.Nil, fmt.Errorf("%w: question %s is not part of the survey", ErrInvalidLogicCondition, id)
+		}
+		return mapped, nil
+	}
+
+	conditions, err := l.GetConditions()
+	if err != nil {
+		return err
+	}
+	actions, err := l.GetActions()
+	if err != nil {
+		return err
+	}
+
+	if l.QuestionID, err = remap(l.QuestionID); err != nil {
+		return err
+	}
+	for i, rule := range conditions.Rules {
+		if rule.QuestionID == nil {
+			continue
+		}
+		mapped, err := remap(*rule.QuestionID)
+		if err != nil {
+			return err
+		}
+		conditions.Rules[i].QuestionID = &mapped
+	}
+	for i, id := range actions.QuestionIDs {
+		if actions.QuestionIDs[i], err = remap(id); err != nil {
+			return err
+		}
+	}
+	if actions.TargetID != nil {
+		mapped, err := remap(*actions.TargetID)
+		if err != nil {
+			return err
+		}
+		actions.TargetID = &mapped
+	}
+
+	conditionsJSON, err := json.Marshal(conditions)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLogicCondition, err)
+	}
+	actionsJSON, err := json.Marshal(actions)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLogicAction, err)
+	}
+	l.Conditions = string(conditionsJSON)
+	l.Actions = string(actionsJSON)
+	return nil
+}
+
+// GetPage returns the page the question is shown on; questions without a page are on page 1
+func (q *SurveyQuestion) GetPage() int {
+	if q.Metadata == "" {
+		return 1
+	}
+	var metadata SurveyQuestionMetadata
+	if err := json.Unmarshal([]byte(q.Metadata), &metadata); err != nil || metadata.Page <= 0 {
+		return 1
+	}
+	return metadata.Page
+}
+
+// EvaluateSurveyLogic walks the questions in order and applies the active logic rules to the given
+// answers. Rules only fire from visible trigger questions, and answers to hidden questions are
+// treated as unanswered, so a branch that is skipped cannot affect later ones.
+func EvaluateSurveyLogic(questions []SurveyQuestion, logic []SurveyLogic, answers []SurveyAnswer) (*SurveyNavigation, error) {
+	ordered := make([]SurveyQuestion, len(questions))
+	copy(ordered, questions)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	position := make(map[uuid.UUID]int, len(ordered))
+	for i, question := range ordered {
+		position[question.ID] = i
+	}
+	answerByQuestion := make(map[uuid.UUID]*SurveyAnswer, len(answers))
+	for i := range answers {
+		answerByQuestion[answers[i].QuestionID] = &answers[i]
+	}
+
+	type parsedLogic struct {
+		logic      *SurveyLogic
+		conditions *SurveyLogicConditions
+		actions    *SurveyLogicActions
+	}
+	byTrigger := make(map[uuid.UUID][]parsedLogic)
+	showTargets := make(map[uuid.UUID]bool)
+	for i := range logic {
+		rule := &logic[i]
+		if !rule.IsActive {
+			continue
+		}
+		conditions, err := rule.GetConditions()
+		if err != nil {
+			return nil, err
+		}
+		actions, err := rule.GetActions()
+		if err != nil {
+			return nil, err
+		}
+		byTrigger[rule.QuestionID] = append(byTrigger[rule.QuestionID], parsedLogic{rule, conditions, actions})
+		if rule.LogicType == SurveyLogicShow {
+			for _, id := range targetQuestions(ordered, actions) {
+				showTargets[id] = true
+			}
+		}
+	}
+
+	navigation := &SurveyNavigation{}
+	hidden := make(map[uuid.UUID]bool)
+	shown := make(map[uuid.UUID]bool)
+	visibleAnswers := make(map[uuid.UUID]*SurveyAnswer)
+	jumpTo := -1
+	for i, question := range ordered {
+		if navigation.Ended || i < jumpTo || hidden[question.ID] || (showTargets[question.ID] && !shown[question.ID]) {
+			navigation.HiddenQuestionIDs = append(navigation.HiddenQuestionIDs, question.ID)
+			continue
+		}
+		navigation.VisibleQuestionIDs = append(navigation.VisibleQuestionIDs, question.ID)
+		if answer := answerByQuestion[question.ID]; answer != nil {
+			visibleAnswers[question.ID] = answer
+		}
+
+		for _, parsed := range byTrigger[question.ID] {
+			if !parsed.conditions.matches(question.ID, visibleAnswers) {
+				continue
+			}
+			switch parsed.logic.LogicType {
+			case SurveyLogicSkip:
+				for _, id := range targetQuestions(ordered, parsed.actions) {
+					hidden[id] = true
+				}
+			case SurveyLogicShow:
+				for _, id := range targetQuestions(ordered, parsed.actions) {
+					shown[id] = true
+				}
+			case SurveyLogicJump:
+				target := len(ordered)
+				if parsed.actions.TargetID != nil {
+					if p, ok := position[*parsed.actions.TargetID]; ok {
+						target = p
+					}
+				} else {
+					for j, candidate := range ordered {
+						if candidate.GetPage() >= parsed.actions.TargetPage {
+							target = j
+							break
+						}
+					}
+				}
+				if target > jumpTo {
+					jumpTo = target
+				}
+			case SurveyLogicEnd:
+				navigation.Ended = true
+				navigation.EndMessage = parsed.actions.Message
+			}
+		}
+	}
+
+	pages := make(map[int]bool)
+	for _, question := range ordered {
+		if !navigation.IsVisible(question.ID) {
+			continue
+		}
+		pages[question.GetPage()] = true
+		if navigation.NextQuestionID == nil && answerByQuestion[question.ID] == nil {
+			id := question.ID
+			navigation.NextQuestionID = &id
+			navigation.NextPage = question.GetPage()
+		}
+	}
+	for page := range pages {
+		navigation.Pages = append(navigation.Pages, page)
+	}
+	sort.Ints(navigation.Pages)
+	navigation.Complete = navigation.Ended || navigation.NextQuestionID == nil
+
+	return navigation, nil
+}
+
+// targetQuestions resolves the question and page targets of a skip/show action
+func targetQuestions(questions []SurveyQuestion, actions *SurveyLogicActions) []uuid.UUID {
+	targets := append([]uuid.UUID{}, actions.QuestionIDs...)
+	for _, page := range actions.Pages {
+		for _, question := range questions {
+			if question.GetPage() == page {
+				targets = append(targets, question.ID)
+			}
+		}
+	}
+	return targets
+}
+
+// matches evaluates the rules against the visible answers
+func (c *SurveyLogicConditions) matches(triggerID uuid.UUID, answers map[uuid.UUID]*SurveyAnswer) bool {
+	if len(c.Rules) == 0 {
+		return false
+	}
+	matchAny := c.Match == "any"
+	for _, rule := range c.Rules {
+		questionID := triggerID
+		if rule.QuestionID != nil {
+			questionID = *rule.QuestionID
+		}
+		matched := rule.matches(answers[questionID])
+		if matchAny && matched {
+			return true
+		}
+		if !matchAny && !matched {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+// matches evaluates one rule against an answer (nil when unanswered or hidden)
+func (r *SurveyLogicRule) matches(answer *SurveyAnswer) bool {
+	answered := answer != nil && answer.HasValue()
+	switch r.Operator {
+	case LogicOperatorAnswered:
+		return answered
+	case LogicOperatorNotAnswered:
+		return !answered
+	}
+	if !answered {
+		// Negative comparisons hold for questions that were never answered
+		return r.Operator == LogicOperatorNotEquals || r.Operator == LogicOperatorNotContains || r.Operator == LogicOperatorNotIn
+	}
+
+	values := answerValues(answer)
+	switch r.Operator {
+	case LogicOperatorEquals:
+		return len(values) == 1 && values[0] == logicValueString(r.Value)
+	case LogicOperatorNotEquals:
+		return !(len(values) == 1 && values[0] == logicValueString(r.Value))
+	case LogicOperatorContains:
+		return containsAny(values, []string{logicValueString(r.Value)}) || strings.Contains(answer.AnswerText, logicValueString(r.Value))
+	case LogicOperatorNotContains:
+		return !containsAny(values, []string{logicValueString(r.Value)}) && !strings.Contains(answer.AnswerText, logicValueString(r.Value))
+	case LogicOperatorIn:
+		return containsAny(values, logicValueList(r.Value))
+	case LogicOperatorNotIn:
+		return !containsAny(values, logicValueList(r.Value))
+	case LogicOperatorGreaterThan, LogicOperatorGreaterOrEqual, LogicOperatorLessThan, LogicOperatorLessOrEqual:
+		number, ok := answerNumber(answer)
+		if !ok {
+			return false
+		}
+		target, err := strconv.ParseFloat(logicValueString(r.Value), 64)
+		if err != nil {
+			return false
+		}
+		switch r.Operator {
+		case LogicOperatorGreaterThan:
+			return number > target
+		case LogicOperatorGreaterOrEqual:
+			return number >= target
+		case LogicOperatorLessThan:
+			return number < target
+		default:
+			return number <= target
+		}
+	}
+	return false
+}
+
+// isLogicOperator checks if the operator is supported
+func isLogicOperator(operator string) bool {
+	switch operator {
+	case LogicOperatorEquals, LogicOperatorNotEquals, LogicOperatorContains, LogicOperatorNotContains,
+		LogicOperatorIn, LogicOperatorNotIn, LogicOperatorGreaterThan, LogicOperatorGreaterOrEqual,
+		LogicOperatorLessThan, LogicOperatorLessOrEqual, LogicOperatorAnswered, LogicOperatorNotAnswered:
+		return true
+	}
+	return false
+}
+
+// answerValues returns the answer as strings: every selection of a multiple choice answer, or the single value
+func answerValues(answer *SurveyAnswer) []string {
+	if len(answer.AnswerArray) > 0 {
+		return answer.AnswerArray
+	}
+	switch {
+	case answer.AnswerText != "":
+		return []string{answer.AnswerText}
+	case answer.AnswerNumber != nil:
+		return []string{strconv.FormatFloat(*answer.AnswerNumber, 'f', -1, 64)}
+	case answer.AnswerBool != nil:
+		return []string{strconv.FormatBool(*answer.AnswerBool)}
+	case answer.AnswerDate != nil:
+		return []string{answer.AnswerDate.Format("2006-01-02")}
+	}
+	return nil
+}
+
+// answerNumber returns the numeric value of an answer
+func answerNumber(answer *SurveyAnswer) (float64, bool) {
+	if answer.AnswerNumber != nil {
+		return *answer.AnswerNumber, true
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(answer.AnswerText), 64)
+	return number, err == nil
+}
+
+// logicValueString formats a rule value the way answers are compared
+func logicValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// logicValueList reads a rule value that may be a single value or a list
+func logicValueList(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return []string{logicValueString(value)}
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		values = append(values, logicValueString(item))
+	}
+	return values
+}
+
+// containsAny checks if any of the wanted values is among the values
+func containsAny(values, wanted []string) bool {
+	for _, value := range values {
+		for _, w := range wanted {
+			if value == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// feedbackSurvey builds an event feedback form that branches on the attendee's role
+func feedbackSurvey() ([]SurveyQuestion, []SurveyLogic) {
+	questions := []SurveyQuestion{
+		{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeRadio, IsRequired: true, Options: []string{"Speaker", "Attendee", "Sponsor"}},
+		{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeText, IsRequired: true},                           // Speakers only
+		{ID: uuid.New(), Order: 3, QuestionType: QuestionTypeRating, IsRequired: true, Metadata: `{"page":2}`}, // Sponsors skip it
+		{ID: uuid.New(), Order: 4, QuestionType: QuestionTypeYesNo, IsRequired: true, Metadata: `{"page":2}`},  // Ends the survey on "no"
+		{ID: uuid.New(), Order: 5, QuestionType: QuestionTypeTextarea, IsRequired: true, Metadata: `{"page":3}`},
+	}
+	role := questions[0].ID
+	logic := []SurveyLogic{
+		{QuestionID: role, LogicType: SurveyLogicShow, IsActive: true,
+			Conditions: `[{"operator":"equals","value":"Speaker"}]`,
+			Actions:    fmt.Sprintf(`{"questionIds":["%s"]}`, questions[1].ID)},
+		{QuestionID: role, LogicType: SurveyLogicSkip, IsActive: true,
+			Conditions: `{"match":"any","rules":[{"operator":"equals","value":"Sponsor"}]}`,
+			Actions:    fmt.Sprintf(`{"questionIds":["%s"]}`, questions[2].ID)},
+		{QuestionID: questions[3].ID, LogicType: SurveyLogicEnd, IsActive: true,
+			Conditions: `[{"operator":"equals","value":false}]`,
+			Actions:    `{"message":"Thanks anyway!"}`},
+	}
+	return questions, logic
+}
+
+func TestSurveyLogicBranchesByRole(t *testing.T) {
+	questions, logic := feedbackSurvey()
+
+	navigation, err := EvaluateSurveyLogic(questions, logic, []SurveyAnswer{{QuestionID: questions[0].ID, AnswerText: "Sponsor"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if navigation.IsVisible(questions[1].ID) || navigation.IsVisible(questions[2].ID) {
+		t.Errorf("Expected the speaker and rating questions to be hidden for sponsors, got %v", navigation.VisibleQuestionIDs)
+	}
+	if navigation.NextQuestionID == nil || *navigation.NextQuestionID != questions[3].ID || navigation.NextPage != 2 {
+		t.Errorf("Expected the next question to be the page 2 yes/no question, got %v on page %d", navigation.NextQuestionID, navigation.NextPage)
+	}
+
+	navigation, err = EvaluateSurveyLogic(questions, logic, []SurveyAnswer{{QuestionID: questions[0].ID, AnswerText: "Speaker"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !navigation.IsVisible(questions[1].ID) || *navigation.NextQuestionID != questions[1].ID {
+		t.Errorf("Expected the speaker question to be shown next, got %v", navigation.NextQuestionID)
+	}
+}
+
+func TestSurveyLogicEndsEarly(t *testing.T) {
+	questions, logic := feedbackSurvey()
+	rating, no := 4.0, false
+
+	navigation, err := EvaluateSurveyLogic(questions, logic, []SurveyAnswer{
+		{QuestionID: questions[0].ID, AnswerText: "Attendee"},
+		{QuestionID: questions[2].ID, AnswerNumber: &rating},
+		{QuestionID: questions[3].ID, AnswerBool: &no},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !navigation.Ended || !navigation.Complete || navigation.EndMessage != "Thanks anyway!" {
+		t.Errorf("Expected the survey to end with a message, got %+v", navigation)
+	}
+	if navigation.IsVisible(questions[4].ID) {
+		t.Error("Expected the question after the end rule to be hidden")
+	}
+	if len(navigation.Pages) != 2 {
+		t.Errorf("Expected pages 1 and 2, got %v", navigation.Pages)
+	}
+}
+
+func TestSurveyLogicJumpAndValidation(t *testing.T) {
+	questions, _ := feedbackSurvey()
+	jump := SurveyLogic{QuestionID: questions[0].ID, LogicType: SurveyLogicJump, IsActive: true,
+		Conditions: `[{"operator":"in","value":["Attendee","Sponsor"]}]`,
+		Actions:    `{"targetPage":3}`}
+
+	navigation, err := EvaluateSurveyLogic(questions, []SurveyLogic{jump}, []SurveyAnswer{{QuestionID: questions[0].ID, AnswerText: "Attendee"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(navigation.VisibleQuestionIDs) != 2 || *navigation.NextQuestionID != questions[4].ID {
+		t.Errorf("Expected the jump to skip to page 3, got %v", navigation.VisibleQuestionIDs)
+	}
+
+	if err := jump.Validate(); err != nil {
+		t.Errorf("Expected the jump rule to be valid, got %v", err)
+	}
+	invalid := SurveyLogic{LogicType: SurveyLogicSkip, Conditions: `[{"operator":"like","value":"x"}]`, Actions: `{}`}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected an unknown operator to be rejected")
+	}
+}
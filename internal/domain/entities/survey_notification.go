@@ -0,0 +1,235 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Survey notification events
+const (
+	SurveyNotificationEventResponseSubmitted = "response_submitted"
+	SurveyNotificationEventQuotaReached      = "quota_reached"
+	SurveyNotificationEventSurveyClosed      = "survey_closed" // Closed, past its end date or full
+)
+
+// Survey notification channels. The recipients are email addresses, WeChat OpenIDs or webhook URLs.
+const (
+	SurveyNotificationChannelEmail   = "email"
+	SurveyNotificationChannelWeChat  = "wechat"
+	SurveyNotificationChannelWebhook = "webhook"
+)
+
+// Survey notification delivery statuses
+const (
+	SurveyNotificationDeliveryPending = "pending" // Waiting for its first attempt or a retry
+	SurveyNotificationDeliverySent    = "sent"
+	SurveyNotificationDeliveryFailed  = "failed" // Gave up after the last attempt
+)
+
+// ErrInvalidNotification is returned for notifications with an unknown event or channel, or invalid recipients or conditions
+var ErrInvalidNotification = errors.New("invalid survey notification")
+
+// surveyNotificationPlaceholder matches the {name} placeholders of notification subjects and messages
+var surveyNotificationPlaceholder = regexp.MustCompile(`\{([A-Za-z0-9_-]+)\}`)
+
+// Default subjects and messages of notifications created without them
+var surveyNotificationDefaults = map[string][2]string{
+	SurveyNotificationEventResponseSubmitted: {"New response to {survey}", "A new response to {survey} was submitted at {submittedAt}.\n\n{answers}"},
+	SurveyNotificationEventQuotaReached:      {"Quota reached: {quota}", "The quota {quota} of {survey} is full with {quotaCount} responses."},
+	SurveyNotificationEventSurveyClosed:      {"{survey} is closed", "{survey} is closed with {responseCount} responses."},
+}
+
+// SurveyNotificationDelivery logs one delivery of a notification to one recipient
+type SurveyNotificationDelivery struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	NotificationID uuid.UUID  `json:"notificationId" gorm:"type:uuid;not null;index"`
+	SurveyID       uuid.UUID  `json:"surveyId" gorm:"type:uuid;not null;index"`
+	ResponseID     *uuid.UUID `json:"responseId" gorm:"type:uuid"`
+	EventType      string     `json:"eventType" gorm:"not null;size:50"`
+	Channel        string     `json:"channel" gorm:"not null;size:20"`
+	Recipient      string     `json:"recipient" gorm:"not null;size:500"`
+	Subject        string     `json:"subject" gorm:"size:255"`
+	Message        string     `json:"message" gorm:"type:text"`
+	Payload        string     `json:"payload" gorm:"type:jsonb"` // Event data sent to webhooks
+	Status         string     `json:"status" gorm:"not null;size:20;default:'pending'"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt" gorm:"index"`
+	LastError      string     `json:"lastError,omitempty" gorm:"type:text"`
+	SentAt         *time.Time `json:"sentAt"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for SurveyNotificationDelivery
+func (SurveyNotificationDelivery) TableName() string {
+	return "survey_notification_deliveries"
+}
+
+// Validate checks the event, channel, recipients and conditions of the notification
+func (n *SurveyNotification) Validate() error {
+	if _, ok := surveyNotificationDefaults[n.EventType]; !ok {
+		return fmt.Errorf("%w: unknown event %q", ErrInvalidNotification, n.EventType)
+	}
+	if len(n.Recipients) == 0 {
+		return fmt.Errorf("%w: at least one recipient is required", ErrInvalidNotification)
+	}
+	for _, recipient := range n.Recipients {
+		if err := n.validateRecipient(recipient); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(n.Conditions) != "" {
+		conditions, err := n.GetConditions()
+		if err != nil {
+			return err
+		}
+		if len(conditions.Rules) > 0 && n.EventType == SurveyNotificationEventSurveyClosed {
+			return fmt.Errorf("%w: conditions only apply to response events", ErrInvalidNotification)
+		}
+		for _, rule := range conditions.Rules {
+			if rule.QuestionID == nil {
+				return fmt.Errorf("%w: every rule needs a question", ErrInvalidNotification)
+			}
+			if !isLogicOperator(rule.Operator) {
+				return fmt.Errorf("%w: unknown operator %q", ErrInvalidNotification, rule.Operator)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRecipient checks that a recipient is an address of the notification's channel
+func (n *SurveyNotification) validateRecipient(recipient string) error {
+	switch n.Channel {
+	case SurveyNotificationChannelEmail:
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("%w: invalid email %q", ErrInvalidNotification, recipient)
+		}
+	case SurveyNotificationChannelWeChat:
+		if strings.TrimSpace(recipient) == "" {
+			return fmt.Errorf("%w: empty OpenID", ErrInvalidNotification)
+		}
+	case SurveyNotificationChannelWebhook:
+		target, err := url.Parse(recipient)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("%w: invalid webhook URL %q", ErrInvalidNotification, recipient)
+		}
+	default:
+		return fmt.Errorf("%w: unknown channel %q", ErrInvalidNotification, n.Channel)
+	}
+	return nil
+}
+
+// GetConditions parses the conditions a response must meet to be notified. They use the logic
+// rule format, and every rule names the question it reads.
+func (n *SurveyNotification) GetConditions() (*SurveyLogicConditions, error) {
+	logic := SurveyLogic{Conditions: n.Conditions}
+	conditions, err := logic.GetConditions()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNotification, err)
+	}
+	return conditions, nil
+}
+
+// Matches checks if a response with the given answers is notified. Notifications without
+// condition rules match every response.
+func (n *SurveyNotification) Matches(answers []SurveyAnswer) (bool, error) {
+	if strings.TrimSpace(n.Conditions) == "" {
+		return true, nil
+	}
+	conditions, err := n.GetConditions()
+	if err != nil {
+		return false, err
+	}
+	if len(conditions.Rules) == 0 {
+		return true, nil
+	}
+	answerByQuestion := make(map[uuid.UUID]*SurveyAnswer, len(answers))
+	for i := range answers {
+		if !answers[i].IsSkipped {
+			answerByQuestion[answers[i].QuestionID] = &answers[i]
+		}
+	}
+	return conditions.matches(uuid.Nil, answerByQuestion), nil
+}
+
+// Render fills the placeholders of the subject and message, falling back to the event's defaults.
+// Unknown placeholders are left as they are.
+func (n *SurveyNotification) Render(values map[string]string) (subject, message string) {
+	defaults := surveyNotificationDefaults[n.EventType]
+	subject, message = n.Subject, n.Message
+	if strings.TrimSpace(subject) == "" {
+		subject = defaults[0]
+	}
+	if strings.TrimSpace(message) == "" {
+		message = defaults[1]
+	}
+	return RenderSurveyNotificationText(subject, values), RenderSurveyNotificationText(message, values)
+}
+
+// RenderSurveyNotificationText replaces the {name} placeholders with their values
+func RenderSurveyNotificationText(text string, values map[string]string) string {
+	return surveyNotificationPlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := values[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// SurveyNotificationValues returns the placeholder values of a response: {Q<n>} and {<question id>}
+// for each answer and {answers} for all of them, one question per line
+func SurveyNotificationValues(questions []SurveyQuestion, answers []SurveyAnswer) map[string]string {
+	answerByQuestion := make(map[uuid.UUID]*SurveyAnswer, len(answers))
+	for i := range answers {
+		if !answers[i].IsSkipped {
+			answerByQuestion[answers[i].QuestionID] = &answers[i]
+		}
+	}
+
+	values := make(map[string]string, 2*len(questions)+1)
+	var summary []string
+	for _, question := range questions {
+		answer, ok := answerByQuestion[question.ID]
+		if !ok {
+			continue
+		}
+		value := strings.Join(answerValues(answer), ", ")
+		values["Q"+strconv.Itoa(question.Order)] = value
+		values[question.ID.String()] = value
+		summary = append(summary, question.QuestionText+": "+value)
+	}
+	values["answers"] = strings.Join(summary, "\n")
+	return values
+}
+
+// RecordAttempt records the outcome of a delivery attempt. Failed attempts are retried with an
+// exponential backoff from retryDelay until maxAttempts were made.
+func (d *SurveyNotificationDelivery) RecordAttempt(err error, now time.Time, retryDelay time.Duration, maxAttempts int) {
+	d.Attempts++
+	if err == nil {
+		d.Status = SurveyNotificationDeliverySent
+		d.SentAt = &now
+		d.NextAttemptAt = nil
+		d.LastError = ""
+		return
+	}
+
+	d.LastError = err.Error()
+	if d.Attempts >= maxAttempts {
+		d.Status = SurveyNotificationDeliveryFailed
+		d.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(retryDelay << (d.Attempts - 1))
+	d.Status = SurveyNotificationDeliveryPending
+	d.NextAttemptAt = &next
+}
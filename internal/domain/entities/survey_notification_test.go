@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSurveyNotificationValidate(t *testing.T) {
+	questionID := uuid.New()
+	vipOnly := fmt.Sprintf(`[{"questionId":"%s","operator":"equals","value":"VIP"}]`, questionID)
+	submitted := SurveyNotificationEventResponseSubmitted
+
+	tests := []struct {
+		name         string
+		notification SurveyNotification
+		valid        bool
+	}{
+		{"email", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelEmail, Recipients: []string{"host@example.com"}}, true},
+		{"bad email", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelEmail, Recipients: []string{"host"}}, false},
+		{"no recipients", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelEmail}, false},
+		{"wechat", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelWeChat, Recipients: []string{"oABC"}}, true},
+		{"webhook", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelWebhook, Recipients: []string{"https://hooks.example.com/vip"}}, true},
+		{"bad webhook", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelWebhook, Recipients: []string{"ftp://hooks.example.com"}}, false},
+		{"unknown channel", SurveyNotification{EventType: submitted, Channel: "sms", Recipients: []string{"123"}}, false},
+		{"unknown event", SurveyNotification{EventType: "response_started", Channel: SurveyNotificationChannelEmail, Recipients: []string{"host@example.com"}}, false},
+		{"conditions", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelEmail, Recipients: []string{"host@example.com"}, Conditions: vipOnly}, true},
+		{"rule without question", SurveyNotification{EventType: submitted, Channel: SurveyNotificationChannelEmail, Recipients: []string{"host@example.com"},
+			Conditions: `[{"operator":"equals","value":"VIP"}]`}, false},
+		{"closed with conditions", SurveyNotification{EventType: SurveyNotificationEventSurveyClosed, Channel: SurveyNotificationChannelEmail, Recipients: []string{"host@example.com"},
+			Conditions: vipOnly}, false},
+		{"closed with empty conditions", SurveyNotification{EventType: SurveyNotificationEventSurveyClosed, Channel: SurveyNotificationChannelEmail, Recipients: []string{"host@example.com"},
+			Conditions: "{}"}, true},
+	}
+	for _, tt := range tests {
+		err := tt.notification.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: Expected valid = %v, got %v", tt.name, tt.valid, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidNotification) {
+			t.Errorf("%s: Expected ErrInvalidNotification, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestSurveyNotificationMatches(t *testing.T) {
+	questionID := uuid.New()
+	notification := SurveyNotification{Conditions: fmt.Sprintf(`[{"questionId":"%s","operator":"equals","value":"VIP"}]`, questionID)}
+
+	matched, err := notification.Matches([]SurveyAnswer{{QuestionID: questionID, AnswerText: "VIP"}})
+	if err != nil || !matched {
+		t.Errorf("Expected VIP answer to match, got %v, %v", matched, err)
+	}
+	matched, _ = notification.Matches([]SurveyAnswer{{QuestionID: questionID, AnswerText: "Standard"}})
+	if matched {
+		t.Error("Expected standard answer not to match")
+	}
+	matched, _ = notification.Matches([]SurveyAnswer{{QuestionID: questionID, AnswerText: "VIP", IsSkipped: true}})
+	if matched {
+		t.Error("Expected skipped answer not to match")
+	}
+
+	for _, conditions := range []string{"", "{}"} {
+		unconditional := SurveyNotification{Conditions: conditions}
+		if matched, _ := unconditional.Matches(nil); !matched {
+			t.Errorf("Expected conditions %q to match every response", conditions)
+		}
+	}
+}
+
+func TestSurveyNotificationRender(t *testing.T) {
+	rating, comment := uuid.New(), uuid.New()
+	questions := []SurveyQuestion{
+		{ID: rating, Order: 1, QuestionText: "Rating"},
+		{ID: comment, Order: 2, QuestionText: "Comment"},
+	}
+	score := 5.0
+	values := SurveyNotificationValues(questions, []SurveyAnswer{
+		{QuestionID: rating, AnswerNumber: &score},
+		{QuestionID: comment, AnswerText: "Great", IsSkipped: true},
+	})
+	values["survey"] = "Gala"
+
+	notification := SurveyNotification{EventType: SurveyNotificationEventResponseSubmitted, Message: "{survey} rated {Q1} ({" + rating.String() + "}), {Q2} {unknown}"}
+	subject, message := notification.Render(values)
+	if subject != "New response to Gala" {
+		t.Errorf("Expected the default subject, got %q", subject)
+	}
+	if message != "Gala rated 5 (5), {Q2} {unknown}" {
+		t.Errorf("Expected rendered message, got %q", message)
+	}
+	if values["answers"] != "Rating: 5" {
+		t.Errorf("Expected answer summary, got %q", values["answers"])
+	}
+}
+
+func TestSurveyNotificationDeliveryRecordAttempt(t *testing.T) {
+	now := time.Now()
+	delivery := SurveyNotificationDelivery{Status: SurveyNotificationDeliveryPending}
+	failure := errors.New("timeout")
+
+	delivery.RecordAttempt(failure, now, time.Minute, 3)
+	if delivery.Status != SurveyNotificationDeliveryPending || !delivery.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected a retry after a minute, got %s at %v", delivery.Status, delivery.NextAttemptAt)
+	}
+	delivery.RecordAttempt(failure, now, time.Minute, 3)
+	if !delivery.NextAttemptAt.Equal(now.Add(2 * time.Minute)) {
+		t.Errorf("Expected the retry delay to double, got %v", delivery.NextAttemptAt)
+	}
+	delivery.RecordAttempt(failure, now, time.Minute, 3)
+	if delivery.Status != SurveyNotificationDeliveryFailed || delivery.NextAttemptAt != nil {
+		t.Errorf("Expected failed delivery after the last attempt, got %s", delivery.Status)
+	}
+	if delivery.LastError != "timeout" {
+		t.Errorf("Expected the last error to be kept, got %q", delivery.LastError)
+	}
+
+	retried := SurveyNotificationDelivery{Status: SurveyNotificationDeliveryPending, Attempts: 1, LastError: "timeout"}
+	retried.RecordAttempt(nil, now, time.Minute, 3)
+	if retried.Status != SurveyNotificationDeliverySent || retried.SentAt == nil || retried.LastError != "" {
+		t.Errorf("Expected sent delivery, got %+v", retried)
+	}
+}
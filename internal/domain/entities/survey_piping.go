@@ -0,0 +1,355 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Survey piping types
+const (
+	SurveyPipeText        = "text"        // Substitute the source answer into the target text
+	SurveyPipeOption      = "option"      // Carry the source's options forward as the target's options
+	SurveyPipeCalculation = "calculation" // Substitute a value calculated from earlier answers
+)
+
+// Option pipe carry forward modes
+const (
+	PipeOptionsSelected   = "selected"
+	PipeOptionsUnselected = "unselected"
+)
+
+// Survey piping errors
+var (
+	ErrInvalidPipeType = errors.New("invalid survey piping type")
+	ErrInvalidPipeRule = errors.New("invalid survey piping rule")
+)
+
+// errPipeValueMissing is returned while a value a calculation refers to is unanswered
+var errPipeValueMissing = errors.New("piped value is not answered")
+
+// SurveyPipeRule is the JSON stored in SurveyPiping.PipeRule
+type SurveyPipeRule struct {
+	Placeholder string `json:"placeholder,omitempty"` // Token replaced in the target; {Q<n>} of the source by default
+	Separator   string `json:"separator,omitempty"`   // Joins multiple selections; ", " by default
+	Default     string `json:"default,omitempty"`     // Substituted while the value is unavailable
+	Options     string `json:"options,omitempty"`     // Option pipes: carry the selected (default) or unselected options
+	IncludeOwn  bool   `json:"includeOwn,omitempty"`  // Option pipes: keep the target's own options after the carried ones
+	Expression  string `json:"expression,omitempty"`  // Calculation pipes: arithmetic over {Q<n>} or {<question id>} references
+	Decimals    *int   `json:"decimals,omitempty"`    // Calculation pipes: fixed number of decimals
+}
+
+// PipingQuestion is the part of a question piping reads and rewrites
+type PipingQuestion struct {
+	ID      uuid.UUID
+	Order   int
+	Text    string
+	Options []string
+}
+
+// GetRule parses the pipe rule
+func (p *SurveyPiping) GetRule() (*SurveyPipeRule, error) {
+	var rule SurveyPipeRule
+	if strings.TrimSpace(p.PipeRule) == "" {
+		return &rule, nil
+	}
+	if err := json.Unmarshal([]byte(p.PipeRule), &rule); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPipeRule, err)
+	}
+	return &rule, nil
+}
+
+// Validate validates the pipe type and rule
+func (p *SurveyPiping) Validate() error {
+	if p.SourceQuestionID == p.TargetQuestionID {
+		return fmt.Errorf("%w: a question cannot pipe into itself", ErrInvalidPipeRule)
+	}
+	rule, err := p.GetRule()
+	if err != nil {
+		return err
+	}
+
+	switch p.PipeType {
+	case SurveyPipeText:
+	case SurveyPipeOption:
+		if rule.Options != "" && rule.Options != PipeOptionsSelected && rule.Options != PipeOptionsUnselected {
+			return fmt.Errorf("%w: options must be selected or unselected", ErrInvalidPipeRule)
+		}
+	case SurveyPipeCalculation:
+		if rule.Placeholder == "" || rule.Expression == "" {
+			return fmt.Errorf("%w: calculation needs a placeholder and an expression", ErrInvalidPipeRule)
+		}
+		// Check the syntax with every reference resolved to 1
+		if _, err := evaluatePipeExpression(rule.Expression, func(string) (float64, error) { return 1, nil }); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPipeRule, err)
+		}
+	default:
+		return ErrInvalidPipeType
+	}
+	return nil
+}
+
+// ApplySurveyPiping rewrites the questions with the answers given so far. Option pipes replace the
+// target's options first, then text and calculation pipes substitute their placeholders in the
+// target's text and options. Questions are processed in order, so a target that is itself piped
+// forward carries its piped options. Answers map a question to its value, or to every selection
+// of a multiple choice question.
+func ApplySurveyPiping(questions []PipingQuestion, piping []SurveyPiping, answers map[uuid.UUID][]string) ([]PipingQuestion, error) {
+	ordered := make([]PipingQuestion, len(questions))
+	for i, question := range questions {
+		ordered[i] = question
+		ordered[i].Options = append([]string(nil), question.Options...)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	byID := make(map[uuid.UUID]*PipingQuestion, len(ordered))
+	byRef := make(map[string]uuid.UUID, len(ordered)*2)
+	for i := range ordered {
+		byID[ordered[i].ID] = &ordered[i]
+		byRef[fmt.Sprintf("Q%d", ordered[i].Order)] = ordered[i].ID
+		byRef[ordered[i].ID.String()] = ordered[i].ID
+	}
+
+	type parsedPipe struct {
+		pipe *SurveyPiping
+		rule *SurveyPipeRule
+	}
+	byTarget := make(map[uuid.UUID][]parsedPipe)
+	for i := range piping {
+		pipe := &piping[i]
+		if !pipe.IsActive || byID[pipe.SourceQuestionID] == nil {
+			continue
+		}
+		rule, err := pipe.GetRule()
+		if err != nil {
+			return nil, err
+		}
+		byTarget[pipe.TargetQuestionID] = append(byTarget[pipe.TargetQuestionID], parsedPipe{pipe, rule})
+	}
+
+	resolve := func(ref string) (float64, error) {
+		id, ok := byRef[ref]
+		if !ok {
+			return 0, fmt.Errorf("unknown question %s", ref)
+		}
+		return pipeNumber(answers[id])
+	}
+
+	for i := range ordered {
+		question := &ordered[i]
+		pipes := byTarget[question.ID]
+		if len(pipes) == 0 {
+			continue
+		}
+
+		for _, parsed := range pipes {
+			if parsed.pipe.PipeType != SurveyPipeOption {
+				continue
+			}
+			selected := answers[parsed.pipe.SourceQuestionID]
+			if len(selected) == 0 {
+				continue
+			}
+			options := carriedOptions(byID[parsed.pipe.SourceQuestionID].Options, selected, parsed.rule.Options != PipeOptionsUnselected)
+			if parsed.rule.IncludeOwn {
+				for _, own := range question.Options {
+					if !containsAny(options, []string{own}) {
+						options = append(options, own)
+					}
+				}
+			}
+			question.Options = options
+		}
+
+		var replacements []string
+		for _, parsed := range pipes {
+			source := byID[parsed.pipe.SourceQuestionID]
+			placeholder := parsed.rule.Placeholder
+			value := parsed.rule.Default
+			switch parsed.pipe.PipeType {
+			case SurveyPipeText:
+				if placeholder == "" {
+					placeholder = fmt.Sprintf("{Q%d}", source.Order)
+				}
+				if selections := answers[source.ID]; len(selections) > 0 {
+					separator := parsed.rule.Separator
+					if separator == "" {
+						separator = ", "
+					}
+					value = strings.Join(selections, separator)
+				}
+			case SurveyPipeCalculation:
+				if result, err := evaluatePipeExpression(parsed.rule.Expression, resolve); err == nil {
+					decimals := -1
+					if parsed.rule.Decimals != nil {
+						decimals = *parsed.rule.Decimals
+					}
+					value = strconv.FormatFloat(result, 'f', decimals, 64)
+				}
+			default:
+				continue
+			}
+			if placeholder != "" {
+				replacements = append(replacements, placeholder, value)
+			}
+		}
+		if len(replacements) > 0 {
+			replacer := strings.NewReplacer(replacements...)
+			question.Text = replacer.Replace(question.Text)
+			for j, option := range question.Options {
+				question.Options[j] = replacer.Replace(option)
+			}
+		}
+	}
+
+	return ordered, nil
+}
+
+// carriedOptions returns the source options that were (or were not) selected, in source order
+func carriedOptions(options, selected []string, keepSelected bool) []string {
+	carried := make([]string, 0, len(options))
+	for _, option := range options {
+		if containsAny(selected, []string{option}) == keepSelected {
+			carried = append(carried, option)
+		}
+	}
+	return carried
+}
+
+// pipeNumber reads the numeric value of an answer. A multiple choice answer counts its selections.
+func pipeNumber(values []string) (float64, error) {
+	switch len(values) {
+	case 0:
+		return 0, errPipeValueMissing
+	case 1:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(values[0]), 64); err == nil {
+			return number, nil
+		}
+	}
+	return float64(len(values)), nil
+}
+
+// evaluatePipeExpression evaluates + - * / and parentheses over numbers and {reference} values
+func evaluatePipeExpression(expression string, resolve func(ref string) (float64, error)) (float64, error) {
+	parser := &pipeExpressionParser{input: expression, resolve: resolve}
+	value, err := parser.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	parser.skipSpaces()
+	if parser.pos < len(parser.input) {
+		return 0, fmt.Errorf("unexpected %q at %d", parser.input[parser.pos], parser.pos)
+	}
+	return value, nil
+}
+
+// pipeExpressionParser is a recursive descent parser for calculation expressions
+type pipeExpressionParser struct {
+	input   string
+	pos     int
+	resolve func(ref string) (float64, error)
+}
+
+func (p *pipeExpressionParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *pipeExpressionParser) parseSum() (float64, error) {
+	value, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return value, nil
+		}
+		operator := p.input[p.pos]
+		p.pos++
+		operand, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if operator == '+' {
+			value += operand
+		} else {
+			value -= operand
+		}
+	}
+}
+
+func (p *pipeExpressionParser) parseProduct() (float64, error) {
+	value, err := p.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			return value, nil
+		}
+		operator := p.input[p.pos]
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+		if operator == '*' {
+			value *= operand
+		} else {
+			if operand == 0 {
+				return 0, errors.New("division by zero")
+			}
+			value /= operand
+		}
+	}
+}
+
+func (p *pipeExpressionParser) parseFactor() (float64, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0, errors.New("unexpected end of expression")
+	}
+
+	switch c := p.input[p.pos]; {
+	case c == '-':
+		p.pos++
+		value, err := p.parseFactor()
+		return -value, err
+	case c == '(':
+		p.pos++
+		value, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return 0, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return value, nil
+	case c == '{':
+		end := strings.IndexByte(p.input[p.pos:], '}')
+		if end < 0 {
+			return 0, errors.New("missing closing brace")
+		}
+		ref := strings.TrimSpace(p.input[p.pos+1 : p.pos+end])
+		p.pos += end + 1
+		return p.resolve(ref)
+	case (c >= '0' && c <= '9') || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && ((p.input[p.pos] >= '0' && p.input[p.pos] <= '9') || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.input[start:p.pos], 64)
+	default:
+		return 0, fmt.Errorf("unexpected %q at %d", c, p.pos)
+	}
+}
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestApplySurveyPiping(t *testing.T) {
+	sessions := PipingQuestion{ID: uuid.New(), Order: 1, Text: "Which sessions did you attend?", Options: []string{"Keynote", "Workshop", "Panel"}}
+	rating := PipingQuestion{ID: uuid.New(), Order: 2, Text: "Rate the event"}
+	favourite := PipingQuestion{ID: uuid.New(), Order: 3, Text: "You rated us {Q2} after {count} sessions. Which was best?", Options: []string{"None"}}
+	questions := []PipingQuestion{favourite, rating, sessions}
+
+	piping := []SurveyPiping{
+		{SourceQuestionID: sessions.ID, TargetQuestionID: favourite.ID, PipeType: SurveyPipeOption, PipeRule: `{"includeOwn":true}`, IsActive: true},
+		{SourceQuestionID: rating.ID, TargetQuestionID: favourite.ID, PipeType: SurveyPipeText, PipeRule: `{"default":"-"}`, IsActive: true},
+		{SourceQuestionID: sessions.ID, TargetQuestionID: favourite.ID, PipeType: SurveyPipeCalculation, PipeRule: `{"placeholder":"{count}","expression":"{Q1}","default":"some"}`, IsActive: true},
+	}
+	for _, pipe := range piping {
+		if err := pipe.Validate(); err != nil {
+			t.Fatalf("Expected %s pipe to be valid, got %v", pipe.PipeType, err)
+		}
+	}
+
+	piped, err := ApplySurveyPiping(questions, piping, map[uuid.UUID][]string{
+		sessions.ID: {"Keynote", "Panel"},
+		rating.ID:   {"4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if piped[2].Text != "You rated us 4 after 2 sessions. Which was best?" {
+		t.Errorf("Unexpected piped text %q", piped[2].Text)
+	}
+	if want := []string{"Keynote", "Panel", "None"}; !reflect.DeepEqual(piped[2].Options, want) {
+		t.Errorf("Expected options %v, got %v", want, piped[2].Options)
+	}
+	if len(favourite.Options) != 1 {
+		t.Error("Expected the input questions to be left untouched")
+	}
+
+	piped, err = ApplySurveyPiping(questions, piping, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if piped[2].Text != "You rated us - after some sessions. Which was best?" || !reflect.DeepEqual(piped[2].Options, []string{"None"}) {
+		t.Errorf("Expected defaults without answers, got %q %v", piped[2].Text, piped[2].Options)
+	}
+}
+
+func TestSurveyPipingCalculation(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	target := PipingQuestion{ID: uuid.New(), Order: 3, Text: "Average: {avg}"}
+	questions := []PipingQuestion{{ID: first, Order: 1}, {ID: second, Order: 2}, target}
+	pipe := SurveyPiping{SourceQuestionID: first, TargetQuestionID: target.ID, PipeType: SurveyPipeCalculation,
+		PipeRule: `{"placeholder":"{avg}","expression":"({Q1} + {` + second.String() + `}) / 2","decimals":1}`, IsActive: true}
+
+	piped, err := ApplySurveyPiping(questions, []SurveyPiping{pipe}, map[uuid.UUID][]string{first: {"3"}, second: {"4"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if piped[2].Text != "Average: 3.5" {
+		t.Errorf("Expected the calculated average, got %q", piped[2].Text)
+	}
+
+	invalid := SurveyPiping{SourceQuestionID: first, TargetQuestionID: target.ID, PipeType: SurveyPipeCalculation,
+		PipeRule: `{"placeholder":"{avg}","expression":"{Q1} +"}`}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected an incomplete expression to be rejected")
+	}
+}
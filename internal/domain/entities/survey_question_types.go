@@ -0,0 +1,349 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// File upload limits. Questions can lower them in their metadata, not raise them.
+const (
+	SurveyFileDefaultMaxSize  = 10 << 20 // 10 MB
+	SurveyFileMaxSize         = 50 << 20 // 50 MB
+	SurveyFileDefaultMaxFiles = 1
+	SurveyFileMaxFiles        = 10
+)
+
+// SurveyFileDefaultTypes are the files accepted by file questions that do not list their own
+var SurveyFileDefaultTypes = []string{
+	".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".txt", ".csv",
+	".jpg", ".jpeg", ".png", ".gif", ".webp",
+}
+
+// ErrInvalidSurveyFile is returned for uploads that break the limits of their file question
+var ErrInvalidSurveyFile = errors.New("invalid survey file")
+
+// SurveyMatrixSettings is the part of SurveyQuestion.Metadata of a matrix question. The question
+// options are the rows; each row is answered with one of the columns, or several when
+// MultipleChoice is set. Answers are stored in AnswerJSON as an object of row to column, e.g.
+// {"Venue":"Good","Food":"Poor"}, or of row to columns, e.g. {"Venue":["Clean","Quiet"]}.
+type SurveyMatrixSettings struct {
+	Columns        []string `json:"columns"`
+	MultipleChoice bool     `json:"multipleChoice,omitempty"`
+	RequireAllRows bool     `json:"requireAllRows,omitempty"` // An answer must cover every row
+}
+
+// SurveyRankingSettings is the part of SurveyQuestion.Metadata of a ranking question. The question
+// options are the items to rank. Answers list the ranked items best first, in AnswerArray or as a
+// JSON array in AnswerJSON, e.g. ["Workshop","Keynote"].
+type SurveyRankingSettings struct {
+	Ranked int `json:"ranked,omitempty"` // Number of items to rank, best first; all items when 0
+}
+
+// SurveyFileSettings is the part of SurveyQuestion.Metadata of a file question
+type SurveyFileSettings struct {
+	MaxFiles     int      `json:"maxFiles,omitempty"`     // Defaults to SurveyFileDefaultMaxFiles
+	MaxFileSize  int64    `json:"maxFileSize,omitempty"`  // In bytes, defaults to SurveyFileDefaultMaxSize
+	AllowedTypes []string `json:"allowedTypes,omitempty"` // Extensions (".pdf"), MIME types or wildcards ("image/*")
+}
+
+// SurveyUploadedFile is a file uploaded for a file question. Answers list their files in AnswerJSON.
+type SurveyUploadedFile struct {
+	Key         string `json:"key"` // Storage key, under the prefix of the question
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType"`
+	URL         string `json:"url"`
+}
+
+// GetMatrixSettings parses the matrix settings of the question
+func (q *SurveyQuestion) GetMatrixSettings() (*SurveyMatrixSettings, error) {
+	var settings SurveyMatrixSettings
+	if err := parseQuestionMetadata(q.Metadata, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetRankingSettings parses the ranking settings of the question
+func (q *SurveyQuestion) GetRankingSettings() (*SurveyRankingSettings, error) {
+	var settings SurveyRankingSettings
+	if err := parseQuestionMetadata(q.Metadata, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetFileSettings parses the file settings of the question, filling in the defaults
+func (q *SurveyQuestion) GetFileSettings() (*SurveyFileSettings, error) {
+	var settings SurveyFileSettings
+	if err := parseQuestionMetadata(q.Metadata, &settings); err != nil {
+		return nil, err
+	}
+	if settings.MaxFiles == 0 {
+		settings.MaxFiles = SurveyFileDefaultMaxFiles
+	}
+	if settings.MaxFileSize == 0 {
+		settings.MaxFileSize = SurveyFileDefaultMaxSize
+	}
+	if len(settings.AllowedTypes) == 0 {
+		settings.AllowedTypes = SurveyFileDefaultTypes
+	}
+	return &settings, nil
+}
+
+// parseQuestionMetadata reads the question metadata into settings
+func parseQuestionMetadata(metadata string, settings interface{}) error {
+	if strings.TrimSpace(metadata) == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(metadata), settings); err != nil {
+		return fmt.Errorf("invalid question metadata: %w", err)
+	}
+	return nil
+}
+
+// validateMatrixSettings checks that a matrix question has rows and distinct columns
+func (q *SurveyQuestion) validateMatrixSettings() error {
+	if len(q.Options) == 0 {
+		return errors.New("matrix questions must have rows defined as options")
+	}
+	settings, err := q.GetMatrixSettings()
+	if err != nil {
+		return err
+	}
+	if len(settings.Columns) == 0 {
+		return errors.New("matrix questions must have columns defined in metadata")
+	}
+	if len(uniqueValues(settings.Columns)) != len(settings.Columns) {
+		return errors.New("matrix columns must be distinct and not empty")
+	}
+	return nil
+}
+
+// validateRankingSettings checks that a ranking question ranks at most its items
+func (q *SurveyQuestion) validateRankingSettings() error {
+	if len(q.Options) < 2 {
+		return errors.New("ranking questions must have at least 2 options")
+	}
+	settings, err := q.GetRankingSettings()
+	if err != nil {
+		return err
+	}
+	if settings.Ranked < 0 || settings.Ranked > len(q.Options) {
+		return fmt.Errorf("ranking questions can rank between 1 and %d items", len(q.Options))
+	}
+	return nil
+}
+
+// validateFileSettings checks that a file question stays within the upload limits
+func (q *SurveyQuestion) validateFileSettings() error {
+	settings, err := q.GetFileSettings()
+	if err != nil {
+		return err
+	}
+	if settings.MaxFiles < 0 || settings.MaxFiles > SurveyFileMaxFiles {
+		return fmt.Errorf("file questions can accept between 1 and %d files", SurveyFileMaxFiles)
+	}
+	if settings.MaxFileSize < 0 || settings.MaxFileSize > SurveyFileMaxSize {
+		return fmt.Errorf("file questions can accept files of up to %d MB", SurveyFileMaxSize>>20)
+	}
+	return nil
+}
+
+// Allows checks if a file is of one of the allowed types, by its extension or content type
+func (s *SurveyFileSettings) Allows(name, contentType string) bool {
+	extension := strings.ToLower(path.Ext(name))
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	for _, allowed := range s.AllowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		switch {
+		case strings.HasPrefix(allowed, "."):
+			if extension == allowed {
+				return true
+			}
+		case strings.HasSuffix(allowed, "/*"):
+			if mediaType != "" && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+		case mediaType != "" && mediaType == allowed:
+			return true
+		}
+	}
+	return false
+}
+
+// CheckFile checks an uploaded file against the limits of the question
+func (s *SurveyFileSettings) CheckFile(name, contentType string, size int64) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: file name is required", ErrInvalidSurveyFile)
+	}
+	if size <= 0 {
+		return fmt.Errorf("%w: %s is empty", ErrInvalidSurveyFile, name)
+	}
+	if size > s.MaxFileSize {
+		return fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidSurveyFile, name, s.MaxFileSize)
+	}
+	if !s.Allows(name, contentType) {
+		return fmt.Errorf("%w: %s is not an accepted file type", ErrInvalidSurveyFile, name)
+	}
+	return nil
+}
+
+// SurveyFileKeyPrefix returns the storage prefix of the files uploaded for a question
+func SurveyFileKeyPrefix(surveyID, questionID uuid.UUID) string {
+	return fmt.Sprintf("surveys/%s/files/%s/", surveyID, questionID)
+}
+
+// GetFiles parses the files of a file question answer
+func (a *SurveyAnswer) GetFiles() ([]SurveyUploadedFile, error) {
+	var files []SurveyUploadedFile
+	if strings.TrimSpace(a.AnswerJSON) == "" {
+		return files, nil
+	}
+	if err := json.Unmarshal([]byte(a.AnswerJSON), &files); err != nil {
+		return nil, errors.New("file answers must be a list of uploaded files")
+	}
+	return files, nil
+}
+
+// GetMatrix parses a matrix answer into the columns chosen for each row
+func (a *SurveyAnswer) GetMatrix() (map[string][]string, error) {
+	var rows map[string]interface{}
+	if strings.TrimSpace(a.AnswerJSON) == "" {
+		return map[string][]string{}, nil
+	}
+	if err := json.Unmarshal([]byte(a.AnswerJSON), &rows); err != nil {
+		return nil, errors.New("matrix answers must be an object of rows to columns")
+	}
+	matrix := make(map[string][]string, len(rows))
+	for row, value := range rows {
+		switch v := value.(type) {
+		case string:
+			matrix[row] = []string{v}
+		case []interface{}:
+			columns := make([]string, 0, len(v))
+			for _, column := range v {
+				text, ok := column.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid column for row %s", row)
+				}
+				columns = append(columns, text)
+			}
+			matrix[row] = columns
+		default:
+			return nil, fmt.Errorf("invalid column for row %s", row)
+		}
+	}
+	return matrix, nil
+}
+
+// validateMatrix validates the columns chosen for the rows of a matrix question
+func (a *SurveyAnswer) validateMatrix(question *SurveyQuestion) error {
+	if a.AnswerJSON == "" {
+		return nil
+	}
+	settings, err := question.GetMatrixSettings()
+	if err != nil {
+		return err
+	}
+	matrix, err := a.GetMatrix()
+	if err != nil {
+		return err
+	}
+	for row, columns := range matrix {
+		if !containsAny(question.Options, []string{row}) {
+			return fmt.Errorf("invalid matrix row: %s", row)
+		}
+		if len(columns) > 1 && !settings.MultipleChoice {
+			return fmt.Errorf("only one column can be chosen for row %s", row)
+		}
+		if len(uniqueValues(columns)) != len(columns) {
+			return fmt.Errorf("columns are repeated for row %s", row)
+		}
+		for _, column := range columns {
+			if !containsAny(settings.Columns, []string{column}) {
+				return fmt.Errorf("invalid matrix column for row %s: %s", row, column)
+			}
+		}
+	}
+	if settings.RequireAllRows {
+		for _, row := range question.Options {
+			if len(matrix[row]) == 0 {
+				return fmt.Errorf("row %s must be answered", row)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRanking validates the items ranked for a ranking question
+func (a *SurveyAnswer) validateRanking(question *SurveyQuestion) error {
+	if len(a.AnswerArray) == 0 && a.AnswerJSON == "" {
+		return nil
+	}
+	settings, err := question.GetRankingSettings()
+	if err != nil {
+		return err
+	}
+	items := rankingItems(a)
+	if items == nil {
+		return errors.New("ranking answers must be a list of items, best first")
+	}
+	if len(uniqueValues(items)) != len(items) {
+		return errors.New("ranked items must be distinct")
+	}
+	for _, item := range items {
+		if !containsAny(question.Options, []string{item}) {
+			return fmt.Errorf("invalid ranking item: %s", item)
+		}
+	}
+	expected := settings.Ranked
+	if expected == 0 {
+		expected = len(question.Options)
+	}
+	if len(items) != expected {
+		return fmt.Errorf("%d items must be ranked", expected)
+	}
+	return nil
+}
+
+// validateFiles validates the files of a file question. Files must have been uploaded for the
+// question, and still meet its limits.
+func (a *SurveyAnswer) validateFiles(question *SurveyQuestion) error {
+	if a.AnswerJSON == "" {
+		return nil
+	}
+	settings, err := question.GetFileSettings()
+	if err != nil {
+		return err
+	}
+	files, err := a.GetFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 && question.IsRequired {
+		return ErrAnswerRequired
+	}
+	if len(files) > settings.MaxFiles {
+		return fmt.Errorf("at most %d files can be uploaded", settings.MaxFiles)
+	}
+	prefix := SurveyFileKeyPrefix(question.SurveyID, question.ID)
+	for _, file := range files {
+		if !strings.HasPrefix(file.Key, prefix) || strings.Contains(file.Key, "..") {
+			return fmt.Errorf("%s was not uploaded for this question", file.Name)
+		}
+		if err := settings.CheckFile(file.Name, file.ContentType, file.Size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
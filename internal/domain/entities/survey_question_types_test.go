@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestStructuredQuestionValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		question SurveyQuestion
+		valid    bool
+	}{
+		{"matrix", SurveyQuestion{QuestionType: QuestionTypeMatrix, Options: []string{"Venue"}, Metadata: `{"columns":["Poor","Good"]}`}, true},
+		{"matrix without columns", SurveyQuestion{QuestionType: QuestionTypeMatrix, Options: []string{"Venue"}}, false},
+		{"matrix with repeated columns", SurveyQuestion{QuestionType: QuestionTypeMatrix, Options: []string{"Venue"}, Metadata: `{"columns":["Good","Good"]}`}, false},
+		{"ranking", SurveyQuestion{QuestionType: QuestionTypeRanking, Options: []string{"A", "B", "C"}, Metadata: `{"ranked":2}`}, true},
+		{"ranking more than its items", SurveyQuestion{QuestionType: QuestionTypeRanking, Options: []string{"A", "B"}, Metadata: `{"ranked":3}`}, false},
+		{"file", SurveyQuestion{QuestionType: QuestionTypeFile, Metadata: `{"maxFiles":3,"allowedTypes":["image/*"]}`}, true},
+		{"file over the size limit", SurveyQuestion{QuestionType: QuestionTypeFile, Metadata: `{"maxFileSize":104857600}`}, false},
+		{"file with too many files", SurveyQuestion{QuestionType: QuestionTypeFile, Metadata: `{"maxFiles":20}`}, false},
+		{"malformed metadata", SurveyQuestion{QuestionType: QuestionTypeFile, Metadata: `{`}, false},
+	}
+	for _, tt := range tests {
+		tt.question.QuestionText = "Question"
+		tt.question.Order = 1
+		if err := tt.question.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Expected valid = %v, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
+
+func TestStructuredAnswerValidate(t *testing.T) {
+	surveyID, fileID := uuid.New(), uuid.New()
+	matrix := &SurveyQuestion{QuestionType: QuestionTypeMatrix, Options: []string{"Venue", "Food"}, Metadata: `{"columns":["Poor","Good"]}`}
+	multiple := &SurveyQuestion{QuestionType: QuestionTypeMatrix, Options: []string{"Venue", "Food"},
+		Metadata: `{"columns":["Clean","Quiet"],"multipleChoice":true,"requireAllRows":true}`}
+	ranking := &SurveyQuestion{QuestionType: QuestionTypeRanking, Options: []string{"A", "B", "C"}}
+	topTwo := &SurveyQuestion{QuestionType: QuestionTypeRanking, Options: []string{"A", "B", "C"}, Metadata: `{"ranked":2}`}
+	file := &SurveyQuestion{ID: fileID, SurveyID: surveyID, QuestionType: QuestionTypeFile, IsRequired: true,
+		Metadata: `{"maxFiles":1,"maxFileSize":1000}`}
+	key := SurveyFileKeyPrefix(surveyID, fileID) + "response/1.pdf"
+
+	tests := []struct {
+		name     string
+		question *SurveyQuestion
+		answer   SurveyAnswer
+		valid    bool
+	}{
+		{"matrix", matrix, SurveyAnswer{AnswerJSON: `{"Venue":"Good"}`}, true},
+		{"matrix unknown row", matrix, SurveyAnswer{AnswerJSON: `{"Parking":"Good"}`}, false},
+		{"matrix unknown column", matrix, SurveyAnswer{AnswerJSON: `{"Venue":"Great"}`}, false},
+		{"matrix several columns", matrix, SurveyAnswer{AnswerJSON: `{"Venue":["Poor","Good"]}`}, false},
+		{"matrix not an object", matrix, SurveyAnswer{AnswerJSON: `["Good"]`}, false},
+		{"multiple choice matrix", multiple, SurveyAnswer{AnswerJSON: `{"Venue":["Clean","Quiet"],"Food":"Clean"}`}, true},
+		{"matrix missing a row", multiple, SurveyAnswer{AnswerJSON: `{"Venue":["Clean"]}`}, false},
+		{"ranking", ranking, SurveyAnswer{AnswerArray: pq.StringArray{"C", "A", "B"}}, true},
+		{"ranking as JSON", ranking, SurveyAnswer{AnswerJSON: `["B","A","C"]`}, true},
+		{"ranking incomplete", ranking, SurveyAnswer{AnswerArray: pq.StringArray{"C", "A"}}, false},
+		{"ranking repeated", ranking, SurveyAnswer{AnswerArray: pq.StringArray{"A", "A", "B"}}, false},
+		{"ranking unknown item", ranking, SurveyAnswer{AnswerArray: pq.StringArray{"A", "B", "D"}}, false},
+		{"top two", topTwo, SurveyAnswer{AnswerArray: pq.StringArray{"C", "A"}}, true},
+		{"file", file, SurveyAnswer{AnswerJSON: `[{"key":"` + key + `","name":"1.pdf","size":500,"contentType":"application/pdf"}]`}, true},
+		{"file from elsewhere", file, SurveyAnswer{AnswerJSON: `[{"key":"surveys/other/1.pdf","name":"1.pdf","size":500}]`}, false},
+		{"file too large", file, SurveyAnswer{AnswerJSON: `[{"key":"` + key + `","name":"1.pdf","size":5000}]`}, false},
+		{"too many files", file, SurveyAnswer{AnswerJSON: `[{"key":"` + key + `","name":"1.pdf","size":5},{"key":"` + key + `","name":"1.pdf","size":5}]`}, false},
+		{"no files for a required question", file, SurveyAnswer{AnswerJSON: `[]`}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.answer.ValidateByType(tt.question); (err == nil) != tt.valid {
+			t.Errorf("%s: Expected valid = %v, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
+
+func TestSurveyFileSettingsCheckFile(t *testing.T) {
+	question := SurveyQuestion{QuestionType: QuestionTypeFile}
+	defaults, err := question.GetFileSettings()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if defaults.MaxFiles != SurveyFileDefaultMaxFiles || defaults.MaxFileSize != SurveyFileDefaultMaxSize {
+		t.Errorf("Expected default limits, got %d files of %d bytes", defaults.MaxFiles, defaults.MaxFileSize)
+	}
+
+	settings := SurveyFileSettings{MaxFileSize: 100, AllowedTypes: []string{".PDF", "image/*", "text/csv"}}
+	tests := []struct {
+		name, fileName, contentType string
+		size                        int64
+		valid                       bool
+	}{
+		{"extension", "Agenda.pdf", "", 10, true},
+		{"wildcard", "photo", "image/jpeg", 10, true},
+		{"media type with parameters", "list.txt", "text/csv; charset=utf-8", 10, true},
+		{"other type", "setup.exe", "application/octet-stream", 10, false},
+		{"too large", "Agenda.pdf", "application/pdf", 101, false},
+		{"empty", "Agenda.pdf", "application/pdf", 0, false},
+	}
+	for _, tt := range tests {
+		err := settings.CheckFile(tt.fileName, tt.contentType, tt.size)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: Expected valid = %v, got %v", tt.name, tt.valid, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidSurveyFile) {
+			t.Errorf("%s: Expected ErrInvalidSurveyFile, got %v", tt.name, err)
+		}
+	}
+}
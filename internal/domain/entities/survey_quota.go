@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidQuota is returned for quotas with invalid limits or conditions
+var ErrInvalidQuota = errors.New("invalid survey quota")
+
+// GetConditions parses the quota conditions. They use the logic rule format, and every rule names
+// the question it reads.
+func (q *SurveyQuota) GetConditions() (*SurveyLogicConditions, error) {
+	logic := SurveyLogic{Conditions: q.Conditions}
+	conditions, err := logic.GetConditions()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuota, err)
+	}
+	return conditions, nil
+}
+
+// Validate validates the quota limit and conditions
+func (q *SurveyQuota) Validate() error {
+	if q.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidQuota)
+	}
+	if q.MaxResponses <= 0 {
+		return fmt.Errorf("%w: max responses must be positive", ErrInvalidQuota)
+	}
+	conditions, err := q.GetConditions()
+	if err != nil {
+		return err
+	}
+	if conditions.Match != "" && conditions.Match != "all" && conditions.Match != "any" {
+		return fmt.Errorf("%w: match must be all or any", ErrInvalidQuota)
+	}
+	if len(conditions.Rules) == 0 {
+		return fmt.Errorf("%w: at least one rule is required", ErrInvalidQuota)
+	}
+	for _, rule := range conditions.Rules {
+		if rule.QuestionID == nil {
+			return fmt.Errorf("%w: every rule needs a question", ErrInvalidQuota)
+		}
+		if !isLogicOperator(rule.Operator) {
+			return fmt.Errorf("%w: unknown operator %q", ErrInvalidQuota, rule.Operator)
+		}
+	}
+	return nil
+}
+
+// Matches checks if a response with the given answers falls into the quota. Skipped answers count
+// as unanswered.
+func (q *SurveyQuota) Matches(answers []SurveyAnswer) (bool, error) {
+	conditions, err := q.GetConditions()
+	if err != nil {
+		return false, err
+	}
+	answerByQuestion := make(map[uuid.UUID]*SurveyAnswer, len(answers))
+	for i := range answers {
+		if !answers[i].IsSkipped {
+			answerByQuestion[answers[i].QuestionID] = &answers[i]
+		}
+	}
+	return conditions.matches(uuid.Nil, answerByQuestion), nil
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSurveyQuotaMatchesAnswers(t *testing.T) {
+	city, role := uuid.New(), uuid.New()
+	quota := SurveyQuota{
+		Name:         "Shanghai managers",
+		MaxResponses: 50,
+		Conditions: fmt.Sprintf(`{"match":"all","rules":[{"questionId":"%s","operator":"equals","value":"Shanghai"},{"questionId":"%s","operator":"in","value":["Manager","Director"]}]}`,
+			city, role),
+	}
+	if err := quota.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		answers []SurveyAnswer
+		want    bool
+	}{
+		{"matching", []SurveyAnswer{{QuestionID: city, AnswerText: "Shanghai"}, {QuestionID: role, AnswerText: "Director"}}, true},
+		{"other city", []SurveyAnswer{{QuestionID: city, AnswerText: "Beijing"}, {QuestionID: role, AnswerText: "Manager"}}, false},
+		{"skipped role", []SurveyAnswer{{QuestionID: city, AnswerText: "Shanghai"}, {QuestionID: role, AnswerText: "Manager", IsSkipped: true}}, false},
+	}
+	for _, tt := range tests {
+		matched, err := quota.Matches(tt.answers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched != tt.want {
+			t.Errorf("%s: matched = %v, want %v", tt.name, matched, tt.want)
+		}
+	}
+}
+
+func TestSurveyQuotaValidate(t *testing.T) {
+	question := uuid.New()
+	tests := []SurveyQuota{
+		{Name: "No limit", Conditions: fmt.Sprintf(`[{"questionId":"%s","operator":"answered"}]`, question)},
+		{Name: "No question", MaxResponses: 10, Conditions: `[{"operator":"equals","value":"Shanghai"}]`},
+		{Name: "No rules", MaxResponses: 10, Conditions: `[]`},
+		{Name: "Bad operator", MaxResponses: 10, Conditions: fmt.Sprintf(`[{"questionId":"%s","operator":"like"}]`, question)},
+	}
+	for _, quota := range tests {
+		if err := quota.Validate(); !errors.Is(err, ErrInvalidQuota) {
+			t.Errorf("%s: expected ErrInvalidQuota, got %v", quota.Name, err)
+		}
+	}
+}
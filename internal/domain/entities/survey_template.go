@@ -0,0 +1,247 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTemplate is returned for templates without a name or with invalid questions or logic
+var ErrInvalidTemplate = errors.New("invalid survey template")
+
+// SurveyTemplateData is the JSON stored in SurveyTemplate.TemplateData: the settings and logic
+// given to surveys created from the template
+type SurveyTemplateData struct {
+	Title              string                `json:"title,omitempty"` // Survey title; the template name by default
+	Description        string                `json:"description,omitempty"`
+	Instructions       string                `json:"instructions,omitempty"`
+	IsAnonymous        bool                  `json:"isAnonymous"`
+	AllowMultiple      bool                  `json:"allowMultiple"`
+	RequireLogin       bool                  `json:"requireLogin"`
+	ShowResults        bool                  `json:"showResults"`
+	ShowProgress       bool                  `json:"showProgress"`
+	RandomizeQuestions bool                  `json:"randomizeQuestions"`
+	TimeLimit          *int                  `json:"timeLimit,omitempty"` // in minutes
+	Logic              []SurveyTemplateLogic `json:"logic,omitempty"`
+}
+
+// SurveyTemplateLogic is a logic rule of a template. Its question IDs refer to the template's questions.
+type SurveyTemplateLogic struct {
+	QuestionID uuid.UUID       `json:"questionId"`
+	LogicType  string          `json:"logicType"`
+	Conditions json.RawMessage `json:"conditions"`
+	Actions    json.RawMessage `json:"actions,omitempty"`
+}
+
+// surveyLogic returns the rule as survey logic, still pointing at template questions
+func (l *SurveyTemplateLogic) surveyLogic() SurveyLogic {
+	return SurveyLogic{
+		QuestionID: l.QuestionID,
+		LogicType:  l.LogicType,
+		Conditions: string(l.Conditions),
+		Actions:    string(l.Actions),
+		IsActive:   true,
+	}
+}
+
+// GetData parses the template data
+func (t *SurveyTemplate) GetData() (*SurveyTemplateData, error) {
+	data := SurveyTemplateData{IsAnonymous: true, ShowProgress: true}
+	if strings.TrimSpace(t.TemplateData) == "" {
+		return &data, nil
+	}
+	if err := json.Unmarshal([]byte(t.TemplateData), &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return &data, nil
+}
+
+// SetData stores the template data as JSON
+func (t *SurveyTemplate) SetData(data *SurveyTemplateData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	t.TemplateData = string(encoded)
+	return nil
+}
+
+// Validate validates the template's name, questions and logic
+func (t *SurveyTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	if len(t.Questions) == 0 {
+		return fmt.Errorf("%w: at least one question is required", ErrInvalidTemplate)
+	}
+
+	ids := make(map[uuid.UUID]uuid.UUID, len(t.Questions))
+	for i := range t.Questions {
+		question := t.Questions[i].surveyQuestion()
+		if err := question.Validate(); err != nil {
+			return fmt.Errorf("%w: question %d: %v", ErrInvalidTemplate, question.Order, err)
+		}
+		ids[question.ID] = question.ID
+	}
+
+	data, err := t.GetData()
+	if err != nil {
+		return err
+	}
+	if data.TimeLimit != nil && *data.TimeLimit <= 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, ErrInvalidTimeLimit)
+	}
+	for _, rule := range data.Logic {
+		logic := rule.surveyLogic()
+		if err := logic.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		if err := logic.RemapQuestions(ids); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+	}
+	return nil
+}
+
+// surveyQuestion returns the template question as a survey question with the same ID
+func (q *SurveyTemplateQuestion) surveyQuestion() SurveyQuestion {
+	return SurveyQuestion{
+		ID:           q.ID,
+		QuestionText: q.QuestionText,
+		QuestionType: q.QuestionType,
+		IsRequired:   q.IsRequired,
+		Order:        q.Order,
+		Options:      append([]string(nil), q.Options...),
+		Validation:   q.Validation,
+		Metadata:     q.Metadata,
+	}
+}
+
+// NewSurvey creates a draft survey from the template. The questions get new IDs and the logic is
+// pointed at them.
+func (t *SurveyTemplate) NewSurvey(title string, createdBy uuid.UUID) (*Survey, []SurveyLogic, error) {
+	data, err := t.GetData()
+	if err != nil {
+		return nil, nil, err
+	}
+	if title == "" {
+		title = data.Title
+	}
+	if title == "" {
+		title = t.Name
+	}
+	description := data.Description
+	if description == "" {
+		description = t.Description
+	}
+
+	survey := &Survey{
+		ID:                 uuid.New(),
+		Title:              title,
+		Description:        description,
+		Instructions:       data.Instructions,
+		Status:             SurveyStatusDraft,
+		IsAnonymous:        data.IsAnonymous,
+		AllowMultiple:      data.AllowMultiple,
+		RequireLogin:       data.RequireLogin,
+		ShowResults:        data.ShowResults,
+		ShowProgress:       data.ShowProgress,
+		RandomizeQuestions: data.RandomizeQuestions,
+		TimeLimit:          data.TimeLimit,
+		CreatedBy:          createdBy,
+	}
+
+	ids := make(map[uuid.UUID]uuid.UUID, len(t.Questions))
+	for _, templateQuestion := range sortedTemplateQuestions(t.Questions) {
+		question := templateQuestion.surveyQuestion()
+		question.ID = uuid.New()
+		question.SurveyID = survey.ID
+		ids[templateQuestion.ID] = question.ID
+		survey.Questions = append(survey.Questions, question)
+	}
+
+	logic := make([]SurveyLogic, 0, len(data.Logic))
+	for _, rule := range data.Logic {
+		copied := rule.surveyLogic()
+		copied.ID = uuid.New()
+		copied.SurveyID = survey.ID
+		if err := copied.RemapQuestions(ids); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		logic = append(logic, copied)
+	}
+	return survey, logic, nil
+}
+
+// NewSurveyTemplate creates a template from a survey and its active logic. The template is named
+// after the survey; its questions get new IDs and the logic is pointed at them.
+func NewSurveyTemplate(survey *Survey, logic []SurveyLogic) (*SurveyTemplate, error) {
+	template := &SurveyTemplate{
+		ID:          uuid.New(),
+		Name:        survey.Title,
+		Description: survey.Description,
+	}
+
+	ids := make(map[uuid.UUID]uuid.UUID, len(survey.Questions))
+	questions := make([]SurveyQuestion, len(survey.Questions))
+	copy(questions, survey.Questions)
+	sort.SliceStable(questions, func(i, j int) bool { return questions[i].Order < questions[j].Order })
+	for _, question := range questions {
+		id := uuid.New()
+		ids[question.ID] = id
+		template.Questions = append(template.Questions, SurveyTemplateQuestion{
+			ID:           id,
+			TemplateID:   template.ID,
+			QuestionText: question.QuestionText,
+			QuestionType: question.QuestionType,
+			IsRequired:   question.IsRequired,
+			Order:        question.Order,
+			Options:      append([]string(nil), question.Options...),
+			Validation:   question.Validation,
+			Metadata:     question.Metadata,
+		})
+	}
+
+	data := &SurveyTemplateData{
+		Title:              survey.Title,
+		Description:        survey.Description,
+		Instructions:       survey.Instructions,
+		IsAnonymous:        survey.IsAnonymous,
+		AllowMultiple:      survey.AllowMultiple,
+		RequireLogin:       survey.RequireLogin,
+		ShowResults:        survey.ShowResults,
+		ShowProgress:       survey.ShowProgress,
+		RandomizeQuestions: survey.RandomizeQuestions,
+		TimeLimit:          survey.TimeLimit,
+	}
+	for _, rule := range logic {
+		if !rule.IsActive {
+			continue
+		}
+		if err := rule.RemapQuestions(ids); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		data.Logic = append(data.Logic, SurveyTemplateLogic{
+			QuestionID: rule.QuestionID,
+			LogicType:  rule.LogicType,
+			Conditions: json.RawMessage(rule.Conditions),
+			Actions:    json.RawMessage(rule.Actions),
+		})
+	}
+	if err := template.SetData(data); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// sortedTemplateQuestions returns the questions in template order
+func sortedTemplateQuestions(questions []SurveyTemplateQuestion) []SurveyTemplateQuestion {
+	sorted := make([]SurveyTemplateQuestion, len(questions))
+	copy(sorted, questions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Order < sorted[j].Order })
+	return sorted
+}
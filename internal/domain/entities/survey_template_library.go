@@ -0,0 +1,134 @@
+package entities
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Built-in survey template categories
+const (
+	SurveyTemplateCategoryFeedback     = "feedback"
+	SurveyTemplateCategoryRegistration = "registration"
+)
+
+// builtInTemplateNamespace derives the fixed IDs of the built-in templates and their questions
+var builtInTemplateNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://nextevent/survey-templates"))
+
+// BuiltInSurveyTemplates returns the template library that ships with the application. The
+// templates are not stored; their IDs are fixed so surveys can be created from them like from
+// stored templates.
+func BuiltInSurveyTemplates() []SurveyTemplate {
+	return []SurveyTemplate{
+		eventFeedbackTemplate(),
+		npsTemplate(),
+		registrationTemplate(),
+	}
+}
+
+// FindBuiltInSurveyTemplate returns the built-in template with the ID, if there is one
+func FindBuiltInSurveyTemplate(id uuid.UUID) (*SurveyTemplate, bool) {
+	for _, template := range BuiltInSurveyTemplates() {
+		if template.ID == id {
+			return &template, true
+		}
+	}
+	return nil, false
+}
+
+// builtInTemplate assembles a built-in template, giving it and its questions fixed IDs
+func builtInTemplate(key string, template SurveyTemplate, data SurveyTemplateData, questions []SurveyTemplateQuestion) SurveyTemplate {
+	template.ID = uuid.NewSHA1(builtInTemplateNamespace, []byte(key))
+	template.IsPublic = true
+	for i := range questions {
+		questions[i].ID = builtInQuestionID(key, i+1)
+		questions[i].TemplateID = template.ID
+		questions[i].Order = i + 1
+	}
+	template.Questions = questions
+	// The data of the library is static and always encodes
+	_ = template.SetData(&data)
+	return template
+}
+
+// builtInQuestionID returns the fixed ID of the nth question of a built-in template
+func builtInQuestionID(key string, n int) uuid.UUID {
+	return uuid.NewSHA1(builtInTemplateNamespace, []byte(key+"/"+strconv.Itoa(n)))
+}
+
+// eventFeedbackTemplate is a post-event feedback survey
+func eventFeedbackTemplate() SurveyTemplate {
+	return builtInTemplate("event-feedback",
+		SurveyTemplate{
+			Name:        "Event feedback",
+			Description: "Post-event survey on the overall experience, sessions, venue and organisation.",
+			Category:    SurveyTemplateCategoryFeedback,
+			Tags:        pq.StringArray{"event", "feedback"},
+		},
+		SurveyTemplateData{IsAnonymous: true, ShowProgress: true},
+		[]SurveyTemplateQuestion{
+			{QuestionText: "How would you rate the event overall?", QuestionType: QuestionTypeRating, IsRequired: true, Options: pq.StringArray{"1", "5"}},
+			{QuestionText: "How did you hear about the event?", QuestionType: QuestionTypeRadio,
+				Options: pq.StringArray{"WeChat", "Email", "Colleague or friend", "Website", "Other"}},
+			{QuestionText: "Which parts of the event did you find most valuable?", QuestionType: QuestionTypeCheckbox,
+				Options: pq.StringArray{"Keynotes", "Breakout sessions", "Workshops", "Networking", "Exhibition"}},
+			{QuestionText: "How would you rate the venue and organisation?", QuestionType: QuestionTypeRating, Options: pq.StringArray{"1", "5"}},
+			{QuestionText: "Would you attend this event again?", QuestionType: QuestionTypeYesNo, IsRequired: true, Options: pq.StringArray{"Yes", "No"}},
+			{QuestionText: "What could we improve?", QuestionType: QuestionTypeTextarea},
+		})
+}
+
+// npsTemplate is a Net Promoter Score survey that asks critics what to improve
+func npsTemplate() SurveyTemplate {
+	const key = "nps"
+	improve := builtInQuestionID(key, 3)
+	conditions, _ := json.Marshal(SurveyLogicConditions{Rules: []SurveyLogicRule{{Operator: LogicOperatorLessOrEqual, Value: 8}}})
+	actions, _ := json.Marshal(SurveyLogicActions{QuestionIDs: []uuid.UUID{improve}})
+
+	return builtInTemplate(key,
+		SurveyTemplate{
+			Name:        "Net Promoter Score",
+			Description: "Asks how likely attendees are to recommend the event, on a 0-10 scale, and why.",
+			Category:    SurveyTemplateCategoryFeedback,
+			Tags:        pq.StringArray{"nps", "feedback"},
+		},
+		SurveyTemplateData{
+			IsAnonymous:  true,
+			ShowProgress: true,
+			Logic: []SurveyTemplateLogic{
+				{QuestionID: builtInQuestionID(key, 1), LogicType: SurveyLogicShow, Conditions: conditions, Actions: actions},
+			},
+		},
+		[]SurveyTemplateQuestion{
+			{QuestionText: "How likely are you to recommend this event to a friend or colleague?", QuestionType: QuestionTypeRating,
+				IsRequired: true, Options: pq.StringArray{"0", "10"}},
+			{QuestionText: "What is the main reason for your score?", QuestionType: QuestionTypeTextarea},
+			{QuestionText: "What would make you more likely to recommend us?", QuestionType: QuestionTypeTextarea},
+		})
+}
+
+// registrationTemplate is an attendee registration form
+func registrationTemplate() SurveyTemplate {
+	return builtInTemplate("registration",
+		SurveyTemplate{
+			Name:        "Registration form",
+			Description: "Collects attendee contact details, company and dietary requirements.",
+			Category:    SurveyTemplateCategoryRegistration,
+			Tags:        pq.StringArray{"event", "registration"},
+		},
+		SurveyTemplateData{IsAnonymous: false, ShowProgress: true},
+		[]SurveyTemplateQuestion{
+			{QuestionText: "Full name", QuestionType: QuestionTypeText, IsRequired: true},
+			{QuestionText: "Email", QuestionType: QuestionTypeEmail, IsRequired: true},
+			{QuestionText: "Mobile number", QuestionType: QuestionTypePhone, IsRequired: true},
+			{QuestionText: "Company", QuestionType: QuestionTypeText},
+			{QuestionText: "Job title", QuestionType: QuestionTypeText},
+			{QuestionText: "Industry", QuestionType: QuestionTypeDropdown,
+				Options: pq.StringArray{"Technology", "Finance", "Healthcare", "Manufacturing", "Education", "Government", "Other"}},
+			{QuestionText: "Dietary requirements", QuestionType: QuestionTypeCheckbox,
+				Options: pq.StringArray{"None", "Vegetarian", "Vegan", "Halal", "Gluten free"}},
+			{QuestionText: "May we contact you about future events?", QuestionType: QuestionTypeYesNo, Options: pq.StringArray{"Yes", "No"}},
+		})
+}
@@ -0,0 +1,140 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuiltInSurveyTemplatesAreValid(t *testing.T) {
+	seen := make(map[uuid.UUID]bool)
+	for _, template := range BuiltInSurveyTemplates() {
+		if err := template.Validate(); err != nil {
+			t.Errorf("Expected built-in template %q to be valid, got %v", template.Name, err)
+		}
+		if seen[template.ID] {
+			t.Errorf("Expected built-in template IDs to be unique, %q repeats %v", template.Name, template.ID)
+		}
+		seen[template.ID] = true
+
+		found, ok := FindBuiltInSurveyTemplate(template.ID)
+		if !ok || found.Name != template.Name {
+			t.Errorf("Expected to find built-in template %q by ID", template.Name)
+		}
+	}
+	if _, ok := FindBuiltInSurveyTemplate(uuid.New()); ok {
+		t.Error("Expected no built-in template for a random ID")
+	}
+}
+
+func TestSurveyTemplateNewSurveyRemapsLogic(t *testing.T) {
+	var nps *SurveyTemplate
+	for _, template := range BuiltInSurveyTemplates() {
+		if template.Name == "Net Promoter Score" {
+			nps = &template
+		}
+	}
+	if nps == nil {
+		t.Fatal("Expected the NPS template in the library")
+	}
+
+	createdBy := uuid.New()
+	survey, logic, err := nps.NewSurvey("", createdBy)
+	if err != nil {
+		t.Fatalf("Expected a survey, got %v", err)
+	}
+	if survey.Title != nps.Name || survey.Status != SurveyStatusDraft || survey.CreatedBy != createdBy {
+		t.Errorf("Expected a draft survey titled after the template, got %q (%s)", survey.Title, survey.Status)
+	}
+	if len(survey.Questions) != len(nps.Questions) || len(logic) != 1 {
+		t.Fatalf("Expected %d questions and 1 rule, got %d and %d", len(nps.Questions), len(survey.Questions), len(logic))
+	}
+	for i, question := range survey.Questions {
+		if question.ID == nps.Questions[i].ID || question.SurveyID != survey.ID {
+			t.Errorf("Expected question %d to get a new ID in the survey", i+1)
+		}
+	}
+
+	rule := logic[0]
+	if rule.SurveyID != survey.ID || rule.QuestionID != survey.Questions[0].ID {
+		t.Errorf("Expected the rule to be triggered by the survey's first question, got %v", rule.QuestionID)
+	}
+	actions, err := rule.GetActions()
+	if err != nil {
+		t.Fatalf("Expected the rule actions to parse, got %v", err)
+	}
+	if len(actions.QuestionIDs) != 1 || actions.QuestionIDs[0] != survey.Questions[2].ID {
+		t.Errorf("Expected the rule to show the survey's third question, got %v", actions.QuestionIDs)
+	}
+}
+
+func TestNewSurveyTemplateRoundTrip(t *testing.T) {
+	survey := &Survey{
+		ID:          uuid.New(),
+		Title:       "Workshop feedback",
+		Description: "After the workshop",
+		ShowResults: true,
+		Questions: []SurveyQuestion{
+			{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeTextarea, QuestionText: "Why?"},
+			{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeYesNo, QuestionText: "Useful?", Options: []string{"Yes", "No"}},
+		},
+	}
+	why, useful := survey.Questions[0].ID, survey.Questions[1].ID
+	active := SurveyLogic{SurveyID: survey.ID, QuestionID: useful, LogicType: SurveyLogicShow, IsActive: true,
+		Conditions: `{"rules":[{"operator":"equals","value":false}]}`,
+		Actions:    `{"questionIds":["` + why.String() + `"]}`}
+	inactive := active
+	inactive.IsActive = false
+
+	template, err := NewSurveyTemplate(survey, []SurveyLogic{active, inactive})
+	if err != nil {
+		t.Fatalf("Expected a template, got %v", err)
+	}
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Expected the template to be valid, got %v", err)
+	}
+	if template.Name != survey.Title || template.Questions[0].QuestionText != "Useful?" {
+		t.Errorf("Expected the template to be named after the survey with questions in order, got %q", template.Name)
+	}
+	data, err := template.GetData()
+	if err != nil {
+		t.Fatalf("Expected template data, got %v", err)
+	}
+	if !data.ShowResults || len(data.Logic) != 1 || data.Logic[0].QuestionID != template.Questions[0].ID {
+		t.Errorf("Expected the settings and the active rule to be copied, got %+v", data)
+	}
+
+	copied, logic, err := template.NewSurvey("Copy", uuid.New())
+	if err != nil {
+		t.Fatalf("Expected a survey from the template, got %v", err)
+	}
+	if copied.Title != "Copy" || !copied.ShowResults || len(logic) != 1 || logic[0].QuestionID != copied.Questions[0].ID {
+		t.Errorf("Expected the survey to carry the template settings and logic, got %+v", logic)
+	}
+}
+
+func TestSurveyTemplateValidation(t *testing.T) {
+	question := SurveyTemplateQuestion{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeText, QuestionText: "Name"}
+
+	if err := (&SurveyTemplate{Questions: []SurveyTemplateQuestion{question}}).Validate(); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected a template without a name to be invalid, got %v", err)
+	}
+	if err := (&SurveyTemplate{Name: "Empty"}).Validate(); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected a template without questions to be invalid, got %v", err)
+	}
+
+	template := &SurveyTemplate{Name: "Dangling", Questions: []SurveyTemplateQuestion{question}}
+	err := template.SetData(&SurveyTemplateData{Logic: []SurveyTemplateLogic{{
+		QuestionID: question.ID,
+		LogicType:  SurveyLogicShow,
+		Conditions: []byte(`{"rules":[{"operator":"answered"}]}`),
+		Actions:    []byte(`{"questionIds":["` + uuid.NewString() + `"]}`),
+	}}})
+	if err != nil {
+		t.Fatalf("Expected the data to encode, got %v", err)
+	}
+	if err := template.Validate(); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected logic pointing outside the template to be invalid, got %v", err)
+	}
+}
@@ -166,13 +166,11 @@ func (q *SurveyQuestion) ValidateTypeSpecific() error {
 			return errors.New("rating/scale questions must have at least 2 options")
 		}
 	case QuestionTypeMatrix:
-		if len(q.Options) == 0 {
-			return errors.New("matrix questions must have options defined")
-		}
+		return q.validateMatrixSettings()
 	case QuestionTypeRanking:
-		if len(q.Options) < 2 {
-			return errors.New("ranking questions must have at least 2 options")
-		}
+		return q.validateRankingSettings()
+	case QuestionTypeFile:
+		return q.validateFileSettings()
 	}
 	return nil
 }
@@ -257,6 +255,12 @@ func (a *SurveyAnswer) ValidateByType(question *SurveyQuestion) error {
 		return a.validateTime()
 	case QuestionTypeDateTime:
 		return a.validateDateTime()
+	case QuestionTypeMatrix:
+		return a.validateMatrix(question)
+	case QuestionTypeRanking:
+		return a.validateRanking(question)
+	case QuestionTypeFile:
+		return a.validateFiles(question)
 	default:
 		return a.validateText(question)
 	}
@@ -335,21 +339,8 @@ func (a *SurveyAnswer) validateRating(question *SurveyQuestion) error {
 	}
 	
 	value := *a.AnswerNumber
-	
-	// Default rating range is 1-5
-	minRating := 1.0
-	maxRating := 5.0
-	
-	// Check if custom range is defined in options
-	if len(question.Options) >= 2 {
-		if min, err := strconv.ParseFloat(question.Options[0], 64); err == nil {
-			minRating = min
-		}
-		if max, err := strconv.ParseFloat(question.Options[1], 64); err == nil {
-			maxRating = max
-		}
-	}
-	
+	minRating, maxRating := question.ratingBounds()
+
 	if value < minRating || value > maxRating {
 		return ErrInvalidRatingValue
 	}
@@ -503,10 +494,11 @@ func (r *SurveyResponse) Validate() error {
 // IsValidStatus checks if the response status is valid
 func (r *SurveyResponse) IsValidStatus() bool {
 	validStatuses := map[ResponseStatus]bool{
-		ResponseStatusInProgress: true,
-		ResponseStatusCompleted:  true,
-		ResponseStatusSubmitted:  true,
-		ResponseStatusAbandoned:  true,
+		ResponseStatusInProgress:  true,
+		ResponseStatusCompleted:   true,
+		ResponseStatusSubmitted:   true,
+		ResponseStatusAbandoned:   true,
+		ResponseStatusScreenedOut: true,
 	}
 	return validStatuses[r.Status]
 }
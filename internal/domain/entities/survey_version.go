@@ -0,0 +1,359 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SurveyVersion is a snapshot of the content of a survey. A new version is recorded each time a
+// published survey is revised, and every response records the version it was answered against.
+type SurveyVersion struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SurveyID     uuid.UUID  `json:"surveyId" gorm:"type:uuid;not null;uniqueIndex:idx_survey_versions_survey_version"`
+	Version      int        `json:"version" gorm:"not null;uniqueIndex:idx_survey_versions_survey_version"`
+	Title        string     `json:"title" gorm:"not null;size:255"`
+	Description  string     `json:"description" gorm:"type:text"`
+	Instructions string     `json:"instructions" gorm:"type:text"`
+	Snapshot     string     `json:"-" gorm:"type:jsonb;not null"` // Questions of the version, see GetQuestions
+	ChangeNote   string     `json:"changeNote" gorm:"type:text"`
+	CreatedBy    *uuid.UUID `json:"createdBy" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+
+	// Computed fields
+	Questions []SurveyQuestion `json:"questions" gorm:"-"`
+}
+
+// TableName returns the table name for SurveyVersion
+func (SurveyVersion) TableName() string {
+	return "survey_versions"
+}
+
+// NewSurveyVersion snapshots the current title, texts and questions of a survey as its current version
+func NewSurveyVersion(survey *Survey, changeNote string, createdBy *uuid.UUID) (*SurveyVersion, error) {
+	questions := make([]SurveyQuestion, len(survey.Questions))
+	for i, question := range survey.Questions {
+		question.Survey = nil
+		question.Answers = nil
+		question.Options = append([]string(nil), question.Options...)
+		questions[i] = question
+	}
+	sort.SliceStable(questions, func(i, j int) bool { return questions[i].Order < questions[j].Order })
+
+	version := &SurveyVersion{
+		ID:           uuid.New(),
+		SurveyID:     survey.ID,
+		Version:      survey.Version,
+		Title:        survey.Title,
+		Description:  survey.Description,
+		Instructions: survey.Instructions,
+		ChangeNote:   changeNote,
+		CreatedBy:    createdBy,
+	}
+	if err := version.SetQuestions(questions); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetQuestions parses the questions of the version
+func (v *SurveyVersion) GetQuestions() ([]SurveyQuestion, error) {
+	var questions []SurveyQuestion
+	if strings.TrimSpace(v.Snapshot) == "" {
+		return questions, nil
+	}
+	if err := json.Unmarshal([]byte(v.Snapshot), &questions); err != nil {
+		return nil, fmt.Errorf("failed to parse survey version %d: %w", v.Version, err)
+	}
+	return questions, nil
+}
+
+// SetQuestions stores the questions of the version as JSON
+func (v *SurveyVersion) SetQuestions(questions []SurveyQuestion) error {
+	data, err := json.Marshal(questions)
+	if err != nil {
+		return fmt.Errorf("failed to encode survey version %d: %w", v.Version, err)
+	}
+	v.Snapshot = string(data)
+	v.Questions = questions
+	return nil
+}
+
+// MoveToSurveyVersion moves a response in progress to the current version of its survey, dropping
+// the saved answers to questions the survey no longer has
+func (r *SurveyResponse) MoveToSurveyVersion(survey *Survey) {
+	if r.SurveyVersion == survey.Version {
+		return
+	}
+	current := make(map[uuid.UUID]bool, len(survey.Questions))
+	for _, question := range survey.Questions {
+		current[question.ID] = true
+	}
+	answers := r.Answers[:0:0]
+	for _, answer := range r.Answers {
+		if current[answer.QuestionID] {
+			answers = append(answers, answer)
+		}
+	}
+	r.Answers = answers
+	r.SurveyVersion = survey.Version
+}
+
+// SurveyFieldChange is a field that differs between two survey versions
+type SurveyFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// SurveyQuestionChange lists how a question kept between two survey versions changed
+type SurveyQuestionChange struct {
+	QuestionID     uuid.UUID           `json:"questionId"`
+	QuestionText   string              `json:"questionText"`
+	Changes        []SurveyFieldChange `json:"changes,omitempty"`
+	AddedOptions   []string            `json:"addedOptions,omitempty"`
+	RemovedOptions []string            `json:"removedOptions,omitempty"`
+	RenamedOptions map[string]string   `json:"renamedOptions,omitempty"` // Earlier option text to its new text
+}
+
+// SurveyVersionDiff lists the changes from one survey version to another
+type SurveyVersionDiff struct {
+	FromVersion      int                    `json:"fromVersion"`
+	ToVersion        int                    `json:"toVersion"`
+	Changes          []SurveyFieldChange    `json:"changes"`
+	AddedQuestions   []SurveyQuestion       `json:"addedQuestions"`
+	RemovedQuestions []SurveyQuestion       `json:"removedQuestions"`
+	ChangedQuestions []SurveyQuestionChange `json:"changedQuestions"`
+}
+
+// DiffSurveyVersions compares two versions of a survey. Questions are matched by ID, so a question
+// keeps its identity through edits.
+func DiffSurveyVersions(from, to *SurveyVersion) (*SurveyVersionDiff, error) {
+	fromQuestions, err := from.GetQuestions()
+	if err != nil {
+		return nil, err
+	}
+	toQuestions, err := to.GetQuestions()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SurveyVersionDiff{
+		FromVersion:      from.Version,
+		ToVersion:        to.Version,
+		Changes:          []SurveyFieldChange{},
+		AddedQuestions:   []SurveyQuestion{},
+		RemovedQuestions: []SurveyQuestion{},
+		ChangedQuestions: []SurveyQuestionChange{},
+	}
+	diff.Changes = appendFieldChange(diff.Changes, "title", from.Title, to.Title)
+	diff.Changes = appendFieldChange(diff.Changes, "description", from.Description, to.Description)
+	diff.Changes = appendFieldChange(diff.Changes, "instructions", from.Instructions, to.Instructions)
+
+	earlier := make(map[uuid.UUID]*SurveyQuestion, len(fromQuestions))
+	for i := range fromQuestions {
+		earlier[fromQuestions[i].ID] = &fromQuestions[i]
+	}
+	kept := make(map[uuid.UUID]bool, len(toQuestions))
+	for i := range toQuestions {
+		question := &toQuestions[i]
+		previous := earlier[question.ID]
+		if previous == nil {
+			diff.AddedQuestions = append(diff.AddedQuestions, *question)
+			continue
+		}
+		kept[question.ID] = true
+		if change := diffSurveyQuestions(previous, question); change != nil {
+			diff.ChangedQuestions = append(diff.ChangedQuestions, *change)
+		}
+	}
+	for _, question := range fromQuestions {
+		if !kept[question.ID] {
+			diff.RemovedQuestions = append(diff.RemovedQuestions, question)
+		}
+	}
+	return diff, nil
+}
+
+// diffSurveyQuestions returns the changes to a question, or nil when it did not change
+func diffSurveyQuestions(from, to *SurveyQuestion) *SurveyQuestionChange {
+	change := SurveyQuestionChange{QuestionID: to.ID, QuestionText: to.QuestionText}
+	change.Changes = appendFieldChange(change.Changes, "questionText", from.QuestionText, to.QuestionText)
+	change.Changes = appendFieldChange(change.Changes, "questionType", string(from.QuestionType), string(to.QuestionType))
+	change.Changes = appendFieldChange(change.Changes, "isRequired", strconv.FormatBool(from.IsRequired), strconv.FormatBool(to.IsRequired))
+	change.Changes = appendFieldChange(change.Changes, "order", strconv.Itoa(from.Order), strconv.Itoa(to.Order))
+	change.Changes = appendFieldChange(change.Changes, "validation", from.Validation, to.Validation)
+	change.Changes = appendFieldChange(change.Changes, "metadata", from.Metadata, to.Metadata)
+
+	renamed := renamedSurveyOptions(from.Options, to.Options)
+	if len(renamed) > 0 {
+		change.RenamedOptions = renamed
+	}
+	renamedTo := make(map[string]bool, len(renamed))
+	for _, option := range renamed {
+		renamedTo[option] = true
+	}
+	for _, option := range to.Options {
+		if !containsOption(from.Options, option) && !renamedTo[option] {
+			change.AddedOptions = append(change.AddedOptions, option)
+		}
+	}
+	for _, option := range from.Options {
+		if !containsOption(to.Options, option) && renamed[option] == "" {
+			change.RemovedOptions = append(change.RemovedOptions, option)
+		}
+	}
+
+	if len(change.Changes) == 0 && len(change.RenamedOptions) == 0 && len(change.AddedOptions) == 0 && len(change.RemovedOptions) == 0 {
+		return nil
+	}
+	return &change
+}
+
+// renamedSurveyOptions finds the options whose text was edited in place. Options are only matched
+// by position when the number of options is unchanged, as added or removed options shift them.
+func renamedSurveyOptions(from, to []string) map[string]string {
+	renamed := make(map[string]string)
+	if len(from) != len(to) {
+		return renamed
+	}
+	for i := range from {
+		if from[i] != to[i] && !containsOption(to, from[i]) && !containsOption(from, to[i]) {
+			renamed[from[i]] = to[i]
+		}
+	}
+	return renamed
+}
+
+// containsOption checks if an option is in the list
+func containsOption(options []string, option string) bool {
+	for _, candidate := range options {
+		if candidate == option {
+			return true
+		}
+	}
+	return false
+}
+
+// appendFieldChange appends the change of a field when its value differs
+func appendFieldChange(changes []SurveyFieldChange, field, from, to string) []SurveyFieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, SurveyFieldChange{Field: field, From: from, To: to})
+}
+
+// SurveyAnswerMapping maps the answers of responses collected under earlier versions of a survey
+// onto its current version, so analytics and exports count them together
+type SurveyAnswerMapping struct {
+	// Questions are the current questions followed by the questions removed in a revision, which
+	// keep their last definition
+	Questions []SurveyQuestion
+	steps     []surveyOptionRenames
+}
+
+// surveyOptionRenames holds the options renamed by the revision of one version, per question
+type surveyOptionRenames struct {
+	version int
+	renamed map[uuid.UUID]map[string]string
+}
+
+// NewSurveyAnswerMapping builds the mapping of a survey from its current questions and versions
+func NewSurveyAnswerMapping(questions []SurveyQuestion, versions []SurveyVersion) (*SurveyAnswerMapping, error) {
+	ordered := make([]SurveyVersion, len(versions))
+	copy(ordered, versions)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Version < ordered[j].Version })
+
+	current := make([]SurveyQuestion, len(questions))
+	copy(current, questions)
+	sort.SliceStable(current, func(i, j int) bool { return current[i].Order < current[j].Order })
+
+	mapping := &SurveyAnswerMapping{}
+	snapshots := make([][]SurveyQuestion, 0, len(ordered)+1)
+	for i := range ordered {
+		snapshot, err := ordered[i].GetQuestions()
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	snapshots = append(snapshots, current)
+
+	for i := 0; i+1 < len(snapshots); i++ {
+		next := make(map[uuid.UUID]*SurveyQuestion, len(snapshots[i+1]))
+		for j := range snapshots[i+1] {
+			next[snapshots[i+1][j].ID] = &snapshots[i+1][j]
+		}
+		step := surveyOptionRenames{version: ordered[i].Version, renamed: make(map[uuid.UUID]map[string]string)}
+		for _, question := range snapshots[i] {
+			if revised := next[question.ID]; revised != nil {
+				if renamed := renamedSurveyOptions(question.Options, revised.Options); len(renamed) > 0 {
+					step.renamed[question.ID] = renamed
+				}
+			}
+		}
+		mapping.steps = append(mapping.steps, step)
+	}
+
+	// Removed questions keep the definition of the last version that had them
+	mapping.Questions = current
+	seen := make(map[uuid.UUID]bool, len(current))
+	for _, question := range current {
+		seen[question.ID] = true
+	}
+	var removed []SurveyQuestion
+	for i := len(snapshots) - 2; i >= 0; i-- {
+		for _, question := range snapshots[i] {
+			if !seen[question.ID] {
+				seen[question.ID] = true
+				removed = append(removed, question)
+			}
+		}
+	}
+	sort.SliceStable(removed, func(i, j int) bool { return removed[i].Order < removed[j].Order })
+	order := 0
+	if len(current) > 0 {
+		order = current[len(current)-1].Order
+	}
+	for _, question := range removed {
+		order++
+		question.Order = order
+		mapping.Questions = append(mapping.Questions, question)
+	}
+	return mapping, nil
+}
+
+// MapResponse rewrites the choices of a response answered under an earlier version to the current
+// option texts
+func (m *SurveyAnswerMapping) MapResponse(response *SurveyResponse) {
+	for _, step := range m.steps {
+		if step.version < response.SurveyVersion {
+			continue
+		}
+		for i := range response.Answers {
+			answer := &response.Answers[i]
+			renamed := step.renamed[answer.QuestionID]
+			if len(renamed) == 0 {
+				continue
+			}
+			if option, ok := renamed[answer.AnswerText]; ok {
+				answer.AnswerText = option
+			}
+			if len(answer.AnswerArray) > 0 {
+				choices := make([]string, len(answer.AnswerArray))
+				for j, choice := range answer.AnswerArray {
+					if option, ok := renamed[choice]; ok {
+						choice = option
+					}
+					choices[j] = choice
+				}
+				answer.AnswerArray = choices
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func newTestSurveyVersion(t *testing.T, version int, title string, questions ...SurveyQuestion) SurveyVersion {
+	t.Helper()
+	surveyVersion, err := NewSurveyVersion(&Survey{ID: uuid.New(), Title: title, Version: version, Questions: questions}, "", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return *surveyVersion
+}
+
+func TestRenamedSurveyOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     []string
+		to       []string
+		expected map[string]string
+	}{
+		{"renamed", []string{"Speaker", "Guest"}, []string{"Speaker", "Attendee"}, map[string]string{"Guest": "Attendee"}},
+		{"reordered", []string{"Speaker", "Guest"}, []string{"Guest", "Speaker"}, map[string]string{}},
+		{"added", []string{"Speaker", "Guest"}, []string{"Speaker", "Attendee", "Sponsor"}, map[string]string{}},
+		{"unchanged", []string{"Speaker"}, []string{"Speaker"}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		if result := renamedSurveyOptions(tt.from, tt.to); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.expected, result)
+		}
+	}
+}
+
+func TestDiffSurveyVersions(t *testing.T) {
+	role := SurveyQuestion{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeCheckbox, QuestionText: "Sessions", Options: pq.StringArray{"Keynote", "Workshop", "Panel"}}
+	comments := SurveyQuestion{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeText, QuestionText: "Comments"}
+	rating := SurveyQuestion{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeNumber, QuestionText: "Rating"}
+	revisedRole := role
+	revisedRole.Order = 2
+	revisedRole.IsRequired = true
+	revisedRole.Options = pq.StringArray{"Keynote", "Hands-on workshop", "Panel", "Networking"}
+
+	from := newTestSurveyVersion(t, 1, "Feedback", role, comments)
+	to := newTestSurveyVersion(t, 2, "Event feedback", rating, revisedRole)
+	diff, err := DiffSurveyVersions(&from, &to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(diff.Changes) != 1 || diff.Changes[0].Field != "title" || diff.Changes[0].To != "Event feedback" {
+		t.Errorf("Expected title change, got %v", diff.Changes)
+	}
+	if len(diff.AddedQuestions) != 1 || diff.AddedQuestions[0].ID != rating.ID {
+		t.Errorf("Expected rating to be added, got %v", diff.AddedQuestions)
+	}
+	if len(diff.RemovedQuestions) != 1 || diff.RemovedQuestions[0].ID != comments.ID {
+		t.Errorf("Expected comments to be removed, got %v", diff.RemovedQuestions)
+	}
+	if len(diff.ChangedQuestions) != 1 {
+		t.Fatalf("Expected 1 changed question, got %d", len(diff.ChangedQuestions))
+	}
+	change := diff.ChangedQuestions[0]
+	if len(change.Changes) != 2 {
+		t.Errorf("Expected isRequired and order changes, got %v", change.Changes)
+	}
+	// With an option added, options are not matched by position so the edit shows as a swap
+	if !reflect.DeepEqual(change.AddedOptions, []string{"Hands-on workshop", "Networking"}) {
+		t.Errorf("Expected added options, got %v", change.AddedOptions)
+	}
+	if !reflect.DeepEqual(change.RemovedOptions, []string{"Workshop"}) {
+		t.Errorf("Expected removed options, got %v", change.RemovedOptions)
+	}
+	if change.RenamedOptions != nil {
+		t.Errorf("Expected no renamed options, got %v", change.RenamedOptions)
+	}
+
+	same, err := DiffSurveyVersions(&to, &to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(same.Changes)+len(same.AddedQuestions)+len(same.RemovedQuestions)+len(same.ChangedQuestions) != 0 {
+		t.Errorf("Expected no differences, got %+v", same)
+	}
+}
+
+func TestSurveyAnswerMapping(t *testing.T) {
+	role := SurveyQuestion{ID: uuid.New(), Order: 1, QuestionType: QuestionTypeRadio, QuestionText: "Role", Options: pq.StringArray{"Speaker", "Guest"}}
+	topics := SurveyQuestion{ID: uuid.New(), Order: 2, QuestionType: QuestionTypeCheckbox, QuestionText: "Topics", Options: pq.StringArray{"AI", "Web"}}
+	comments := SurveyQuestion{ID: uuid.New(), Order: 3, QuestionType: QuestionTypeText, QuestionText: "Comments"}
+
+	// Version 2 renamed guests to visitors and removed the comments, version 3 renamed visitors
+	// to attendees and web to frontend
+	roleV2, roleV3, topicsV3 := role, role, topics
+	roleV2.Options = pq.StringArray{"Speaker", "Visitor"}
+	roleV3.Options = pq.StringArray{"Speaker", "Attendee"}
+	topicsV3.Options = pq.StringArray{"AI", "Frontend"}
+	versions := []SurveyVersion{
+		newTestSurveyVersion(t, 2, "Feedback", roleV2, topics),
+		newTestSurveyVersion(t, 1, "Feedback", role, topics, comments),
+	}
+
+	mapping, err := NewSurveyAnswerMapping([]SurveyQuestion{topicsV3, roleV3}, versions)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mapping.Questions) != 3 {
+		t.Fatalf("Expected 3 questions, got %d", len(mapping.Questions))
+	}
+	if mapping.Questions[2].ID != comments.ID || mapping.Questions[2].Order != 3 {
+		t.Errorf("Expected removed comments last, got %v at %d", mapping.Questions[2].QuestionText, mapping.Questions[2].Order)
+	}
+
+	tests := []struct {
+		version int
+		role    string
+		topics  []string
+	}{
+		{1, "Guest", []string{"Web", "AI"}},
+		{2, "Visitor", []string{"Web"}},
+		{3, "Attendee", []string{"Frontend"}},
+	}
+	for _, tt := range tests {
+		response := SurveyResponse{SurveyVersion: tt.version, Answers: []SurveyAnswer{
+			{QuestionID: role.ID, AnswerText: tt.role},
+			{QuestionID: topics.ID, AnswerArray: tt.topics},
+			{QuestionID: comments.ID, AnswerText: "Guest"},
+		}}
+		mapping.MapResponse(&response)
+		if response.Answers[0].AnswerText != "Attendee" {
+			t.Errorf("Version %d: Expected Attendee, got %s", tt.version, response.Answers[0].AnswerText)
+		}
+		if !containsOption(response.Answers[1].AnswerArray, "Frontend") || containsOption(response.Answers[1].AnswerArray, "Web") {
+			t.Errorf("Version %d: Expected Frontend, got %v", tt.version, response.Answers[1].AnswerArray)
+		}
+		if response.Answers[2].AnswerText != "Guest" {
+			t.Errorf("Version %d: Expected other questions to be unchanged, got %s", tt.version, response.Answers[2].AnswerText)
+		}
+	}
+}
+
+func TestSurveyResponseMoveToSurveyVersion(t *testing.T) {
+	kept, removed := uuid.New(), uuid.New()
+	survey := &Survey{Version: 2, Questions: []SurveyQuestion{{ID: kept}}}
+	response := &SurveyResponse{SurveyVersion: 1, Answers: []SurveyAnswer{{QuestionID: kept}, {QuestionID: removed}}}
+
+	response.MoveToSurveyVersion(survey)
+	if response.SurveyVersion != 2 {
+		t.Errorf("Expected version 2, got %d", response.SurveyVersion)
+	}
+	if len(response.Answers) != 1 || response.Answers[0].QuestionID != kept {
+		t.Errorf("Expected only the answer to the kept question, got %v", response.Answers)
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeChat follower sync statuses
+const (
+	WeChatFollowerSyncStatusRunning   = "running" // Also kept by syncs interrupted by an error, which resume from their checkpoint
+	WeChatFollowerSyncStatusCompleted = "completed"
+)
+
+// WeChatFollowerSync is a run of the reconciliation of the WeChat followers with the WeChatUser
+// records. It checkpoints its position in the follower list after each batch of profiles, so an
+// interrupted run resumes where it stopped.
+type WeChatFollowerSync struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	Status     string     `json:"status" gorm:"not null;size:20;default:'running'"`
+	NextOpenID string     `json:"nextOpenId" gorm:"size:64"` // next_openid the current page of the follower list is fetched with
+	LastOpenID string     `json:"lastOpenId" gorm:"size:64"` // Last follower of the current page synced
+	Total      int        `json:"total" gorm:"not null"`     // Followers reported by WeChat
+	Processed  int        `json:"processed" gorm:"not null"`
+	Created    int        `json:"created" gorm:"not null"`
+	Updated    int        `json:"updated" gorm:"not null"`
+	Unfollowed int        `json:"unfollowed" gorm:"not null"`
+	LastError  string     `json:"lastError,omitempty" gorm:"type:text"`
+	StartedAt  time.Time  `json:"startedAt" gorm:"not null"`
+	FinishedAt *time.Time `json:"finishedAt"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for WeChatFollowerSync
+func (WeChatFollowerSync) TableName() string {
+	return "wechat_follower_syncs"
+}
+
+// IsRunning checks if the sync still has followers to go through
+func (s *WeChatFollowerSync) IsRunning() bool {
+	return s.Status == WeChatFollowerSyncStatusRunning
+}
+
+// Checkpoint records that the followers of the current page up to lastOpenID were synced
+func (s *WeChatFollowerSync) Checkpoint(lastOpenID string, count int) {
+	s.LastOpenID = lastOpenID
+	s.Processed += count
+	s.LastError = ""
+}
+
+// Remaining returns the followers of the current page still to sync. Followers who
+// unfollowed since the checkpoint shift the page, so the position is found by OpenID, and the
+// whole page is synced again when the last synced follower is gone.
+func (s *WeChatFollowerSync) Remaining(openIDs []string) []string {
+	if s.LastOpenID == "" {
+		return openIDs
+	}
+	for i, openID := range openIDs {
+		if openID == s.LastOpenID {
+			return openIDs[i+1:]
+		}
+	}
+	return openIDs
+}
+
+// NextPage moves the sync to the page of the follower list fetched with nextOpenID
+func (s *WeChatFollowerSync) NextPage(nextOpenID string) {
+	s.NextOpenID = nextOpenID
+	s.LastOpenID = ""
+}
+
+// Complete marks the sync as finished
+func (s *WeChatFollowerSync) Complete(unfollowed int, finishedAt time.Time) {
+	s.Status = WeChatFollowerSyncStatusCompleted
+	s.Unfollowed = unfollowed
+	s.LastError = ""
+	s.FinishedAt = &finishedAt
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWeChatFollowerSyncRemaining(t *testing.T) {
+	page := []string{"a", "b", "c", "d"}
+	tests := []struct {
+		name       string
+		lastOpenID string
+		expected   []string
+	}{
+		{"page not started", "", page},
+		{"middle of page", "b", []string{"c", "d"}},
+		{"page finished", "d", []string{}},
+		{"last synced follower unfollowed", "x", page},
+	}
+	for _, tt := range tests {
+		followerSync := &WeChatFollowerSync{LastOpenID: tt.lastOpenID}
+		if remaining := followerSync.Remaining(page); !reflect.DeepEqual(remaining, tt.expected) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.expected, remaining)
+		}
+	}
+}
+
+func TestWeChatFollowerSyncCheckpoints(t *testing.T) {
+	followerSync := &WeChatFollowerSync{Status: WeChatFollowerSyncStatusRunning, LastError: "system error"}
+	followerSync.Checkpoint("b", 2)
+	if followerSync.LastOpenID != "b" || followerSync.Processed != 2 || followerSync.LastError != "" {
+		t.Errorf("Expected a checkpoint at b after 2 followers, got %+v", followerSync)
+	}
+
+	followerSync.NextPage("d")
+	followerSync.Checkpoint("f", 2)
+	if followerSync.NextOpenID != "d" || followerSync.LastOpenID != "f" || followerSync.Processed != 4 {
+		t.Errorf("Expected a checkpoint at f of the page after d, got %+v", followerSync)
+	}
+	if !followerSync.IsRunning() {
+		t.Errorf("Expected the sync to be running")
+	}
+}
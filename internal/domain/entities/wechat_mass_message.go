@@ -0,0 +1,197 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeChat mass message statuses
+const (
+	WeChatMassMessageStatusDraft   = "draft"
+	WeChatMassMessageStatusSending = "sending" // Accepted by WeChat, which reports the outcome when the job finishes
+	WeChatMassMessageStatusSent    = "sent"
+	WeChatMassMessageStatusFailed  = "failed"
+)
+
+// WeChat mass message types
+const (
+	WeChatMassMessageTypeNews  = "mpnews" // Published news, by media ID
+	WeChatMassMessageTypeText  = "text"
+	WeChatMassMessageTypeImage = "image" // Permanent image, by media ID
+)
+
+// Statuses WeChat reports for mass messages, in the MASSSENDJOBFINISH event and when polled
+const (
+	WeChatMassJobSendSuccess  = "send success"
+	WeChatMassPollSendSuccess = "SEND_SUCCESS"
+	WeChatMassPollSendFail    = "SEND_FAIL"
+)
+
+// WeChat limits on mass messages sent to a list of followers
+const (
+	minWeChatMassRecipients   = 2
+	maxWeChatMassRecipients   = 10000
+	maxWeChatMassContentBytes = 2048
+)
+
+// ErrInvalidWeChatMassMessage is returned for mass messages that WeChat would reject
+var ErrInvalidWeChatMassMessage = errors.New("invalid WeChat mass message")
+
+// WeChatMassMessage is a broadcast of news, a text or an image to the followers carrying a WeChat
+// user tag or to a list of followers. WeChat sends it asynchronously, so the delivery counts are
+// recorded when it reports the job as finished.
+type WeChatMassMessage struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	Title        string     `json:"title" gorm:"not null;size:255"`
+	MsgType      string     `json:"msgType" gorm:"not null;size:20"`
+	Content      string     `json:"content,omitempty" gorm:"type:text"`
+	MediaID      string     `json:"mediaId,omitempty" gorm:"size:128"`
+	TagID        *int       `json:"tagId"`                       // WeChat user tag, nil when sent to Recipients
+	Recipients   string     `json:"-" gorm:"type:json;not null"` // OpenIDs the message is sent to, see GetOpenIDs
+	Status       string     `json:"status" gorm:"not null;size:20;default:'draft'"`
+	MsgID        int64      `json:"msgId,omitempty" gorm:"index"` // Assigned by WeChat when the message is sent
+	MsgDataID    int64      `json:"msgDataId,omitempty"`
+	WeChatStatus string     `json:"wechatStatus,omitempty" gorm:"column:wechat_status;size:50"` // Last status reported by WeChat
+	TotalCount   int        `json:"totalCount" gorm:"not null"`
+	FilterCount  int        `json:"filterCount" gorm:"not null"` // Followers WeChat sends to after filtering
+	SentCount    int        `json:"sentCount" gorm:"not null"`
+	ErrorCount   int        `json:"errorCount" gorm:"not null"`
+	LastError    string     `json:"lastError,omitempty" gorm:"type:text"`
+	PreviewedAt  *time.Time `json:"previewedAt"`
+	SentAt       *time.Time `json:"sentAt"`
+	FinishedAt   *time.Time `json:"finishedAt"`
+	CreatedBy    *uuid.UUID `json:"createdBy" gorm:"type:char(36)"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Computed fields
+	OpenIDs []string `json:"openIds,omitempty" gorm:"-"`
+}
+
+// TableName returns the table name for WeChatMassMessage
+func (WeChatMassMessage) TableName() string {
+	return "wechat_mass_messages"
+}
+
+// GetOpenIDs parses the followers the message is sent to
+func (m *WeChatMassMessage) GetOpenIDs() ([]string, error) {
+	var openIDs []string
+	if strings.TrimSpace(m.Recipients) == "" {
+		return openIDs, nil
+	}
+	if err := json.Unmarshal([]byte(m.Recipients), &openIDs); err != nil {
+		return nil, fmt.Errorf("failed to parse recipients of WeChat mass message %s: %w", m.ID, err)
+	}
+	return openIDs, nil
+}
+
+// SetOpenIDs stores the followers the message is sent to as JSON
+func (m *WeChatMassMessage) SetOpenIDs(openIDs []string) error {
+	if openIDs == nil {
+		openIDs = []string{}
+	}
+	data, err := json.Marshal(openIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode recipients of WeChat mass message %s: %w", m.ID, err)
+	}
+	m.Recipients = string(data)
+	m.OpenIDs = openIDs
+	return nil
+}
+
+// Validate checks the content of the message against its type, and that it is sent either to a
+// tag or to 2 to 10,000 followers
+func (m *WeChatMassMessage) Validate() error {
+	if strings.TrimSpace(m.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidWeChatMassMessage)
+	}
+	switch m.MsgType {
+	case WeChatMassMessageTypeText:
+		if strings.TrimSpace(m.Content) == "" {
+			return fmt.Errorf("%w: text messages need content", ErrInvalidWeChatMassMessage)
+		}
+		if len(m.Content) > maxWeChatMassContentBytes {
+			return fmt.Errorf("%w: content is longer than %d bytes", ErrInvalidWeChatMassMessage, maxWeChatMassContentBytes)
+		}
+	case WeChatMassMessageTypeNews, WeChatMassMessageTypeImage:
+		if m.MediaID == "" {
+			return fmt.Errorf("%w: %s messages need a media ID", ErrInvalidWeChatMassMessage, m.MsgType)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported message type %q", ErrInvalidWeChatMassMessage, m.MsgType)
+	}
+
+	if m.TagID != nil {
+		if len(m.OpenIDs) > 0 {
+			return fmt.Errorf("%w: a message is sent to a tag or to followers, not both", ErrInvalidWeChatMassMessage)
+		}
+		return nil
+	}
+	if len(m.OpenIDs) < minWeChatMassRecipients || len(m.OpenIDs) > maxWeChatMassRecipients {
+		return fmt.Errorf("%w: a message without a tag is sent to %d to %d followers",
+			ErrInvalidWeChatMassMessage, minWeChatMassRecipients, maxWeChatMassRecipients)
+	}
+	return nil
+}
+
+// CanSend checks if the message was not sent yet, or failed and may be sent again
+func (m *WeChatMassMessage) CanSend() bool {
+	return m.Status == WeChatMassMessageStatusDraft || m.Status == WeChatMassMessageStatusFailed
+}
+
+// Sending records that WeChat accepted the message for sending
+func (m *WeChatMassMessage) Sending(msgID, msgDataID int64, sentAt time.Time) {
+	m.Status = WeChatMassMessageStatusSending
+	m.MsgID = msgID
+	m.MsgDataID = msgDataID
+	m.WeChatStatus = ""
+	m.LastError = ""
+	m.SentAt = &sentAt
+	m.FinishedAt = nil
+}
+
+// Fail records that the message could not be sent
+func (m *WeChatMassMessage) Fail(reason string, failedAt time.Time) {
+	m.Status = WeChatMassMessageStatusFailed
+	m.LastError = reason
+	m.FinishedAt = &failedAt
+}
+
+// Finish records the outcome WeChat reports when the sending job finishes: "send success", or
+// "send fail" and "err(code)" when WeChat stopped the job
+func (m *WeChatMassMessage) Finish(wechatStatus string, total, filtered, sent, failed int, finishedAt time.Time) {
+	m.WeChatStatus = wechatStatus
+	m.TotalCount = total
+	m.FilterCount = filtered
+	m.SentCount = sent
+	m.ErrorCount = failed
+	m.FinishedAt = &finishedAt
+	if wechatStatus == WeChatMassJobSendSuccess {
+		m.Status = WeChatMassMessageStatusSent
+		m.LastError = ""
+		return
+	}
+	m.Status = WeChatMassMessageStatusFailed
+	m.LastError = wechatStatus
+}
+
+// ApplyPolledStatus records the status WeChat returns when polled, finishing messages WeChat
+// reports as sent or failed; counts are only reported by the MASSSENDJOBFINISH event
+func (m *WeChatMassMessage) ApplyPolledStatus(msgStatus string, at time.Time) {
+	m.WeChatStatus = msgStatus
+	if m.Status != WeChatMassMessageStatusSending {
+		return
+	}
+	switch msgStatus {
+	case WeChatMassPollSendSuccess:
+		m.Status = WeChatMassMessageStatusSent
+		m.FinishedAt = &at
+	case WeChatMassPollSendFail:
+		m.Fail(msgStatus, at)
+	}
+}
@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeChatMassMessageValidate(t *testing.T) {
+	tagID := 100
+	followers := []string{"openid-1", "openid-2"}
+	tests := []struct {
+		name    string
+		message WeChatMassMessage
+		valid   bool
+	}{
+		{"news to tag", WeChatMassMessage{Title: "Recap", MsgType: WeChatMassMessageTypeNews, MediaID: "news-1", TagID: &tagID}, true},
+		{"text to followers", WeChatMassMessage{Title: "Reminder", MsgType: WeChatMassMessageTypeText, Content: "Hi", OpenIDs: followers}, true},
+		{"missing title", WeChatMassMessage{MsgType: WeChatMassMessageTypeNews, MediaID: "news-1", TagID: &tagID}, false},
+		{"image without media", WeChatMassMessage{Title: "Photo", MsgType: WeChatMassMessageTypeImage, TagID: &tagID}, false},
+		{"long text", WeChatMassMessage{Title: "Reminder", MsgType: WeChatMassMessageTypeText, Content: strings.Repeat("a", 2049), TagID: &tagID}, false},
+		{"unsupported type", WeChatMassMessage{Title: "Voice", MsgType: "voice", MediaID: "voice-1", TagID: &tagID}, false},
+		{"tag and followers", WeChatMassMessage{Title: "Recap", MsgType: WeChatMassMessageTypeNews, MediaID: "news-1", TagID: &tagID, OpenIDs: followers}, false},
+		{"single follower", WeChatMassMessage{Title: "Recap", MsgType: WeChatMassMessageTypeNews, MediaID: "news-1", OpenIDs: followers[:1]}, false},
+	}
+	for _, tt := range tests {
+		err := tt.message.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidWeChatMassMessage) {
+			t.Errorf("%s: Expected ErrInvalidWeChatMassMessage, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestWeChatMassMessageOutcome(t *testing.T) {
+	sentAt := time.Date(2025, 1, 18, 9, 0, 0, 0, time.UTC)
+
+	message := &WeChatMassMessage{Status: WeChatMassMessageStatusDraft}
+	message.Sending(1001, 2001, sentAt)
+	if message.CanSend() {
+		t.Error("Expected a message being sent not to be sent again")
+	}
+
+	message.Finish("err(30003)", 10, 10, 0, 10, sentAt.Add(time.Minute))
+	if message.Status != WeChatMassMessageStatusFailed || message.LastError != "err(30003)" || !message.CanSend() {
+		t.Errorf("Expected a failed job to fail the message, got %+v", message)
+	}
+
+	message.Sending(1002, 2002, sentAt.Add(time.Hour))
+	message.ApplyPolledStatus("SENDING", sentAt.Add(time.Hour))
+	if message.Status != WeChatMassMessageStatusSending || message.FinishedAt != nil {
+		t.Errorf("Expected a message still sending, got %+v", message)
+	}
+	message.Finish(WeChatMassJobSendSuccess, 10, 9, 9, 0, sentAt.Add(2*time.Hour))
+	if message.Status != WeChatMassMessageStatusSent || message.LastError != "" || message.SentCount != 9 {
+		t.Errorf("Expected a sent message, got %+v", message)
+	}
+
+	// A late poll does not undo the outcome of the job
+	message.ApplyPolledStatus(WeChatMassPollSendFail, sentAt.Add(3*time.Hour))
+	if message.Status != WeChatMassMessageStatusSent {
+		t.Errorf("Expected the message to stay sent, got %s", message.Status)
+	}
+}
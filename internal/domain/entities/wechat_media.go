@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeChat media types
+const (
+	WeChatMediaTypeImage = "image"
+	WeChatMediaTypeVoice = "voice"
+	WeChatMediaTypeVideo = "video"
+	WeChatMediaTypeThumb = "thumb"
+)
+
+const (
+	// WeChatTemporaryMediaLifetime is how long WeChat keeps temporary media
+	WeChatTemporaryMediaLifetime = 3 * 24 * time.Hour
+	// WeChatMediaExpiryMargin is how long before expiring temporary media stops being reused, so
+	// messages sent with it do not reach WeChat after it expired
+	WeChatMediaExpiryMargin = time.Hour
+	// WeChatMediaMaxSize is the largest file WeChat accepts as media of any type
+	WeChatMediaMaxSize = 10 << 20
+)
+
+// weChatMediaSizeLimits are the largest files WeChat accepts for each media type
+var weChatMediaSizeLimits = map[string]int64{
+	WeChatMediaTypeImage: WeChatMediaMaxSize,
+	WeChatMediaTypeVoice: 2 << 20,
+	WeChatMediaTypeVideo: WeChatMediaMaxSize,
+	WeChatMediaTypeThumb: 64 << 10,
+}
+
+// ErrInvalidWeChatMedia is returned for media WeChat would reject
+var ErrInvalidWeChatMedia = errors.New("invalid WeChat media")
+
+// WeChatMedia records media uploaded to WeChat by the hash of its content, so the same content is
+// not uploaded again while WeChat keeps it. Media uploaded for a site image is also mapped to it.
+type WeChatMedia struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	SiteImageID *uuid.UUID `json:"siteImageId,omitempty" gorm:"type:char(36);index"`
+	MediaType   string     `json:"mediaType" gorm:"not null;size:20"`
+	Permanent   bool       `json:"permanent" gorm:"not null;default:false"`
+	MediaID     string     `json:"mediaId" gorm:"not null;size:128;index"`
+	URL         string     `json:"url,omitempty" gorm:"size:500"` // Only returned for permanent images
+	ContentHash string     `json:"contentHash" gorm:"not null;size:64;index"`
+	FileName    string     `json:"fileName" gorm:"size:255"`
+	FileSize    int64      `json:"fileSize" gorm:"not null"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"` // Only set for temporary media
+	CreatedAt   time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for WeChatMedia
+func (WeChatMedia) TableName() string {
+	return "wechat_media"
+}
+
+// HashWeChatMediaContent returns the content hash media is deduplicated by
+func HashWeChatMediaContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Validate checks the media type and file size against the limits of WeChat
+func (m *WeChatMedia) Validate() error {
+	limit, ok := weChatMediaSizeLimits[m.MediaType]
+	if !ok {
+		return fmt.Errorf("%w: unknown media type %q", ErrInvalidWeChatMedia, m.MediaType)
+	}
+	if m.FileSize == 0 {
+		return fmt.Errorf("%w: empty file", ErrInvalidWeChatMedia)
+	}
+	if m.FileSize > limit {
+		return fmt.Errorf("%w: %s files are limited to %d bytes", ErrInvalidWeChatMedia, m.MediaType, limit)
+	}
+	return nil
+}
+
+// Uploaded records the media ID WeChat assigned to the media, which expires after
+// WeChatTemporaryMediaLifetime unless the media is permanent
+func (m *WeChatMedia) Uploaded(mediaID, url string, at time.Time) {
+	m.MediaID = mediaID
+	m.URL = url
+	m.ExpiresAt = nil
+	if !m.Permanent {
+		expiresAt := at.Add(WeChatTemporaryMediaLifetime)
+		m.ExpiresAt = &expiresAt
+	}
+}
+
+// IsAvailable checks if the media can still be reused at now
+func (m *WeChatMedia) IsAvailable(now time.Time) bool {
+	if m.Permanent {
+		return true
+	}
+	return m.ExpiresAt != nil && now.Add(WeChatMediaExpiryMargin).Before(*m.ExpiresAt)
+}
+
+// MapTo returns a record of the same WeChat media mapped to a site image
+func (m *WeChatMedia) MapTo(siteImageID uuid.UUID) *WeChatMedia {
+	mapped := *m
+	mapped.ID = uuid.New()
+	mapped.SiteImageID = &siteImageID
+	mapped.CreatedAt = time.Time{}
+	mapped.UpdatedAt = time.Time{}
+	return &mapped
+}
@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWeChatMediaValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		size      int64
+		valid     bool
+	}{
+		{"image", WeChatMediaTypeImage, 1 << 20, true},
+		{"large image", WeChatMediaTypeImage, WeChatMediaMaxSize + 1, false},
+		{"voice", WeChatMediaTypeVoice, 1 << 20, true},
+		{"large voice", WeChatMediaTypeVoice, 3 << 20, false},
+		{"thumb", WeChatMediaTypeThumb, 32 << 10, true},
+		{"large thumb", WeChatMediaTypeThumb, 65 << 10, false},
+		{"empty file", WeChatMediaTypeImage, 0, false},
+		{"unknown type", "news", 1 << 10, false},
+	}
+	for _, tt := range tests {
+		media := &WeChatMedia{MediaType: tt.mediaType, FileSize: tt.size}
+		err := media.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidWeChatMedia) {
+			t.Errorf("%s: Expected ErrInvalidWeChatMedia, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestWeChatMediaAvailability(t *testing.T) {
+	uploadedAt := time.Date(2025, 1, 17, 9, 0, 0, 0, time.UTC)
+
+	temporary := &WeChatMedia{MediaType: WeChatMediaTypeImage}
+	temporary.Uploaded("media-1", "", uploadedAt)
+	if temporary.ExpiresAt == nil || !temporary.ExpiresAt.Equal(uploadedAt.Add(WeChatTemporaryMediaLifetime)) {
+		t.Fatalf("Expected temporary media to expire after three days, got %v", temporary.ExpiresAt)
+	}
+	if !temporary.IsAvailable(uploadedAt.Add(48 * time.Hour)) {
+		t.Error("Expected temporary media to be available after two days")
+	}
+	if temporary.IsAvailable(uploadedAt.Add(WeChatTemporaryMediaLifetime - 30*time.Minute)) {
+		t.Error("Expected temporary media about to expire to be unavailable")
+	}
+
+	permanent := &WeChatMedia{MediaType: WeChatMediaTypeImage, Permanent: true}
+	permanent.Uploaded("media-2", "https://mmbiz.qpic.cn/cover.jpg", uploadedAt)
+	if permanent.ExpiresAt != nil || !permanent.IsAvailable(uploadedAt.AddDate(1, 0, 0)) {
+		t.Error("Expected permanent media not to expire")
+	}
+
+	siteImageID := uuid.New()
+	mapped := permanent.MapTo(siteImageID)
+	if mapped.ID == permanent.ID || *mapped.SiteImageID != siteImageID || mapped.MediaID != "media-2" {
+		t.Errorf("Expected a new record of the same media mapped to the site image, got %+v", mapped)
+	}
+	if permanent.SiteImageID != nil {
+		t.Error("Expected the original record not to be mapped")
+	}
+}
@@ -0,0 +1,270 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeChat menu statuses
+const (
+	WeChatMenuStatusDraft     = "draft"
+	WeChatMenuStatusPublished = "published"
+	WeChatMenuStatusArchived  = "archived" // Published before and replaced by another version
+)
+
+// WeChat menu button types
+const (
+	WeChatMenuButtonClick              = "click"       // Sends a CLICK event with the button key
+	WeChatMenuButtonView               = "view"        // Opens the button URL
+	WeChatMenuButtonMiniProgram        = "miniprogram" // Opens a mini program page, or the URL on old clients
+	WeChatMenuButtonScanCodePush       = "scancode_push"
+	WeChatMenuButtonScanCodeWaitMsg    = "scancode_waitmsg"
+	WeChatMenuButtonPicSysPhoto        = "pic_sysphoto"
+	WeChatMenuButtonPicPhotoOrAlbum    = "pic_photo_or_album"
+	WeChatMenuButtonPicWeixin          = "pic_weixin"
+	WeChatMenuButtonLocationSelect     = "location_select"
+	WeChatMenuButtonArticleID          = "article_id"           // Sends a published article
+	WeChatMenuButtonArticleViewLimited = "article_view_limited" // Opens a published article
+)
+
+// WeChat menu click replies
+const (
+	WeChatMenuReplyText = "text"
+	WeChatMenuReplyNews = "news"
+)
+
+// WeChat limits on custom menus; names are measured in bytes, so four Chinese characters fill a
+// top-level button
+const (
+	maxWeChatMenuButtons        = 3
+	maxWeChatMenuSubButtons     = 5
+	maxWeChatMenuButtonName     = 16
+	maxWeChatMenuSubButtonName  = 60
+	maxWeChatMenuButtonKey      = 128
+	maxWeChatMenuButtonURL      = 1024
+	maxWeChatMenuReplyTextBytes = 2048
+)
+
+// ErrInvalidWeChatMenu is returned for menus that WeChat would reject
+var ErrInvalidWeChatMenu = errors.New("invalid WeChat menu")
+
+// WeChatMenu is a version of a WeChat custom menu. Each save records a new version, so a menu can
+// be rolled back by publishing an earlier one. Menus without a tag are the default menu; menus with
+// a tag are conditional menus shown to the followers with that tag.
+type WeChatMenu struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	Name         string     `json:"name" gorm:"not null;size:255"`
+	TagID        string     `json:"tagId" gorm:"size:50;not null;default:'';uniqueIndex:idx_wechat_menus_tag_version"` // WeChat user tag, empty for the default menu
+	Version      int        `json:"version" gorm:"not null;uniqueIndex:idx_wechat_menus_tag_version"`
+	Status       string     `json:"status" gorm:"not null;size:20;default:'draft'"`
+	Definition   string     `json:"-" gorm:"type:json;not null"` // Buttons of the menu, see GetButtons
+	ChangeNote   string     `json:"changeNote" gorm:"type:text"`
+	WeChatMenuID string     `json:"wechatMenuId,omitempty" gorm:"column:wechat_menu_id;size:50"` // Assigned by WeChat to published conditional menus
+	PublishedAt  *time.Time `json:"publishedAt"`
+	PublishedBy  *uuid.UUID `json:"publishedBy" gorm:"type:char(36)"`
+	CreatedBy    *uuid.UUID `json:"createdBy" gorm:"type:char(36)"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Computed fields
+	Buttons []WeChatMenuButton `json:"buttons" gorm:"-"`
+}
+
+// TableName returns the table name for WeChatMenu
+func (WeChatMenu) TableName() string {
+	return "wechat_menus"
+}
+
+// WeChatMenuButton is a button of a WeChat menu, or a top-level sub menu when it has sub buttons
+type WeChatMenuButton struct {
+	Type       string             `json:"type,omitempty"`
+	Name       string             `json:"name"`
+	Key        string             `json:"key,omitempty"`
+	URL        string             `json:"url,omitempty"`
+	AppID      string             `json:"appId,omitempty"`
+	PagePath   string             `json:"pagePath,omitempty"`
+	ArticleID  string             `json:"articleId,omitempty"`
+	Reply      *WeChatMenuReply   `json:"reply,omitempty"` // Sent when a CLICK button is clicked
+	SubButtons []WeChatMenuButton `json:"subButtons,omitempty"`
+}
+
+// WeChatMenuReply is the passive reply to a click on a CLICK button
+type WeChatMenuReply struct {
+	Type        string `json:"type"` // text or news
+	Content     string `json:"content,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	PicURL      string `json:"picUrl,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// IsPublished checks if the version is the one followers see
+func (m *WeChatMenu) IsPublished() bool {
+	return m.Status == WeChatMenuStatusPublished
+}
+
+// IsConditional checks if the menu is only shown to the followers with its tag
+func (m *WeChatMenu) IsConditional() bool {
+	return m.TagID != ""
+}
+
+// GetButtons parses the buttons of the menu
+func (m *WeChatMenu) GetButtons() ([]WeChatMenuButton, error) {
+	var buttons []WeChatMenuButton
+	if strings.TrimSpace(m.Definition) == "" {
+		return buttons, nil
+	}
+	if err := json.Unmarshal([]byte(m.Definition), &buttons); err != nil {
+		return nil, fmt.Errorf("failed to parse WeChat menu version %d: %w", m.Version, err)
+	}
+	return buttons, nil
+}
+
+// SetButtons stores the buttons of the menu as JSON
+func (m *WeChatMenu) SetButtons(buttons []WeChatMenuButton) error {
+	data, err := json.Marshal(buttons)
+	if err != nil {
+		return fmt.Errorf("failed to encode WeChat menu version %d: %w", m.Version, err)
+	}
+	m.Definition = string(data)
+	m.Buttons = buttons
+	return nil
+}
+
+// Validate checks the menu against the WeChat limits: up to three top-level buttons of up to five
+// sub buttons each, the fields each button type needs, and CLICK keys used once in the menu
+func (m *WeChatMenu) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWeChatMenu)
+	}
+	if len(m.Buttons) == 0 || len(m.Buttons) > maxWeChatMenuButtons {
+		return fmt.Errorf("%w: a menu has 1 to %d buttons", ErrInvalidWeChatMenu, maxWeChatMenuButtons)
+	}
+
+	keys := make(map[string]bool)
+	for _, button := range m.Buttons {
+		if len(button.SubButtons) == 0 {
+			if err := button.validate(maxWeChatMenuButtonName, keys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := validateWeChatMenuButtonName(button.Name, maxWeChatMenuButtonName); err != nil {
+			return err
+		}
+		if len(button.SubButtons) > maxWeChatMenuSubButtons {
+			return fmt.Errorf("%w: %q has more than %d sub buttons", ErrInvalidWeChatMenu, button.Name, maxWeChatMenuSubButtons)
+		}
+		for _, sub := range button.SubButtons {
+			if len(sub.SubButtons) > 0 {
+				return fmt.Errorf("%w: sub button %q cannot have sub buttons", ErrInvalidWeChatMenu, sub.Name)
+			}
+			if err := sub.validate(maxWeChatMenuSubButtonName, keys); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ClickReplies returns the replies of the CLICK buttons of the menu by key
+func (m *WeChatMenu) ClickReplies() map[string]WeChatMenuReply {
+	replies := make(map[string]WeChatMenuReply)
+	var collect func(buttons []WeChatMenuButton)
+	collect = func(buttons []WeChatMenuButton) {
+		for _, button := range buttons {
+			if button.Type == WeChatMenuButtonClick && button.Reply != nil {
+				replies[button.Key] = *button.Reply
+			}
+			collect(button.SubButtons)
+		}
+	}
+	collect(m.Buttons)
+	return replies
+}
+
+// validate checks a button without sub buttons, recording its key in keys
+func (b *WeChatMenuButton) validate(maxName int, keys map[string]bool) error {
+	if err := validateWeChatMenuButtonName(b.Name, maxName); err != nil {
+		return err
+	}
+
+	switch b.Type {
+	case WeChatMenuButtonClick, WeChatMenuButtonScanCodePush, WeChatMenuButtonScanCodeWaitMsg,
+		WeChatMenuButtonPicSysPhoto, WeChatMenuButtonPicPhotoOrAlbum, WeChatMenuButtonPicWeixin,
+		WeChatMenuButtonLocationSelect:
+		if b.Key == "" || len(b.Key) > maxWeChatMenuButtonKey {
+			return fmt.Errorf("%w: %q needs a key of up to %d bytes", ErrInvalidWeChatMenu, b.Name, maxWeChatMenuButtonKey)
+		}
+		if keys[b.Key] {
+			return fmt.Errorf("%w: key %q is used twice", ErrInvalidWeChatMenu, b.Key)
+		}
+		keys[b.Key] = true
+	case WeChatMenuButtonView:
+		if err := validateWeChatMenuButtonURL(b); err != nil {
+			return err
+		}
+	case WeChatMenuButtonMiniProgram:
+		if b.AppID == "" || b.PagePath == "" {
+			return fmt.Errorf("%w: %q needs a mini program app ID and page path", ErrInvalidWeChatMenu, b.Name)
+		}
+		// Clients without mini program support open the URL instead
+		if err := validateWeChatMenuButtonURL(b); err != nil {
+			return err
+		}
+	case WeChatMenuButtonArticleID, WeChatMenuButtonArticleViewLimited:
+		if b.ArticleID == "" {
+			return fmt.Errorf("%w: %q needs an article ID", ErrInvalidWeChatMenu, b.Name)
+		}
+	default:
+		return fmt.Errorf("%w: %q has unknown type %q", ErrInvalidWeChatMenu, b.Name, b.Type)
+	}
+
+	if b.Reply == nil {
+		return nil
+	}
+	if b.Type != WeChatMenuButtonClick {
+		return fmt.Errorf("%w: only click buttons have replies", ErrInvalidWeChatMenu)
+	}
+	return b.Reply.validate()
+}
+
+// validate checks the reply has the content its type needs
+func (r *WeChatMenuReply) validate() error {
+	switch r.Type {
+	case WeChatMenuReplyText:
+		if strings.TrimSpace(r.Content) == "" || len(r.Content) > maxWeChatMenuReplyTextBytes {
+			return fmt.Errorf("%w: text replies need content of up to %d bytes", ErrInvalidWeChatMenu, maxWeChatMenuReplyTextBytes)
+		}
+	case WeChatMenuReplyNews:
+		if strings.TrimSpace(r.Title) == "" || r.URL == "" {
+			return fmt.Errorf("%w: news replies need a title and URL", ErrInvalidWeChatMenu)
+		}
+	default:
+		return fmt.Errorf("%w: unknown reply type %q", ErrInvalidWeChatMenu, r.Type)
+	}
+	return nil
+}
+
+func validateWeChatMenuButtonName(name string, maxName int) error {
+	if strings.TrimSpace(name) == "" || len(name) > maxName {
+		return fmt.Errorf("%w: button name %q must have 1 to %d bytes", ErrInvalidWeChatMenu, name, maxName)
+	}
+	return nil
+}
+
+func validateWeChatMenuButtonURL(b *WeChatMenuButton) error {
+	if b.URL == "" || len(b.URL) > maxWeChatMenuButtonURL {
+		return fmt.Errorf("%w: %q needs a URL of up to %d bytes", ErrInvalidWeChatMenu, b.Name, maxWeChatMenuButtonURL)
+	}
+	if !strings.HasPrefix(b.URL, "http://") && !strings.HasPrefix(b.URL, "https://") {
+		return fmt.Errorf("%w: %q needs an http or https URL", ErrInvalidWeChatMenu, b.Name)
+	}
+	return nil
+}
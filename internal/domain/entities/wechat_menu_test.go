@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWeChatMenuValidate(t *testing.T) {
+	click := func(name, key string) WeChatMenuButton {
+		return WeChatMenuButton{Type: WeChatMenuButtonClick, Name: name, Key: key}
+	}
+	view := WeChatMenuButton{Type: WeChatMenuButtonView, Name: "Website", URL: "https://example.com"}
+	agenda := WeChatMenuButton{Name: "Event", SubButtons: []WeChatMenuButton{click("Agenda", "agenda"), view}}
+
+	tests := []struct {
+		name    string
+		buttons []WeChatMenuButton
+		valid   bool
+	}{
+		{"sub menu and click", []WeChatMenuButton{agenda, click("Help", "help")}, true},
+		{"no buttons", nil, false},
+		{"four buttons", []WeChatMenuButton{view, view, view, view}, false},
+		{"six sub buttons", []WeChatMenuButton{{Name: "More", SubButtons: []WeChatMenuButton{view, view, view, view, view, view}}}, false},
+		{"nested sub menu", []WeChatMenuButton{{Name: "More", SubButtons: []WeChatMenuButton{agenda}}}, false},
+		{"long top-level name", []WeChatMenuButton{click("活动议程安排", "agenda")}, false},
+		{"long sub button name", []WeChatMenuButton{{Name: "More", SubButtons: []WeChatMenuButton{click(strings.Repeat("a", 61), "a")}}}, false},
+		{"click without key", []WeChatMenuButton{click("Help", "")}, false},
+		{"repeated key", []WeChatMenuButton{agenda, click("Agenda", "agenda")}, false},
+		{"view without URL", []WeChatMenuButton{{Type: WeChatMenuButtonView, Name: "Website"}}, false},
+		{"view with relative URL", []WeChatMenuButton{{Type: WeChatMenuButtonView, Name: "Website", URL: "/events"}}, false},
+		{"mini program without page", []WeChatMenuButton{{Type: WeChatMenuButtonMiniProgram, Name: "App", AppID: "wx123", URL: "https://example.com"}}, false},
+		{"unknown type", []WeChatMenuButton{{Type: "media_id", Name: "Photo"}}, false},
+		{"text reply", []WeChatMenuButton{{Type: WeChatMenuButtonClick, Name: "Help", Key: "help", Reply: &WeChatMenuReply{Type: WeChatMenuReplyText, Content: "Hi"}}}, true},
+		{"empty text reply", []WeChatMenuButton{{Type: WeChatMenuButtonClick, Name: "Help", Key: "help", Reply: &WeChatMenuReply{Type: WeChatMenuReplyText}}}, false},
+		{"news reply without URL", []WeChatMenuButton{{Type: WeChatMenuButtonClick, Name: "News", Key: "news", Reply: &WeChatMenuReply{Type: WeChatMenuReplyNews, Title: "Recap"}}}, false},
+		{"reply on view", []WeChatMenuButton{{Type: WeChatMenuButtonView, Name: "Website", URL: "https://example.com", Reply: &WeChatMenuReply{Type: WeChatMenuReplyText, Content: "Hi"}}}, false},
+	}
+	for _, tt := range tests {
+		menu := &WeChatMenu{Name: "Summit", Buttons: tt.buttons}
+		err := menu.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidWeChatMenu) {
+			t.Errorf("%s: Expected ErrInvalidWeChatMenu, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestWeChatMenuButtons(t *testing.T) {
+	reply := &WeChatMenuReply{Type: WeChatMenuReplyText, Content: "Doors open at 9:00"}
+	menu := &WeChatMenu{Version: 2}
+	err := menu.SetButtons([]WeChatMenuButton{
+		{Name: "Event", SubButtons: []WeChatMenuButton{
+			{Type: WeChatMenuButtonClick, Name: "Agenda", Key: "agenda", Reply: reply},
+			{Type: WeChatMenuButtonClick, Name: "Check-in", Key: "checkin"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stored := &WeChatMenu{Version: 2, Definition: menu.Definition}
+	buttons, err := stored.GetButtons()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stored.Buttons = buttons
+
+	replies := stored.ClickReplies()
+	if len(replies) != 1 || replies["agenda"] != *reply {
+		t.Errorf("Expected the agenda reply only, got %v", replies)
+	}
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,6 +25,27 @@ const (
 	QRCodeStatusRevoked QRCodeStatus = "revoked" // Manually revoked
 )
 
+// Resource types encoded in parametric QR code scene strings
+const (
+	QRResourceEvent     = "event"     // Event check-in code, scene "event_<eventId>"
+	QRResourceAttendee  = "attendee"  // Personal attendee code, scene "attendee_<attendeeId>"
+	QRResourceCollector = "collector" // Survey collector code, scene "collector_<collectorId>"
+)
+
+// BuildSceneStr encodes a resource as a WeChat QR scene string, e.g. "event_<uuid>"
+func BuildSceneStr(resourceType string, resourceId uuid.UUID) string {
+	return resourceType + "_" + resourceId.String()
+}
+
+// ParseSceneStr splits a scene string into its resource type and value
+func ParseSceneStr(sceneStr string) (resourceType string, value string, ok bool) {
+	resourceType, value, ok = strings.Cut(sceneStr, "_")
+	if !ok || resourceType == "" || value == "" {
+		return "", "", false
+	}
+	return resourceType, value, true
+}
+
 // WeChatQrCode represents WeChat QR code management for content sharing
 type WeChatQrCode struct {
 	ID              uuid.UUID  `gorm:"type:char(36);primaryKey;column:Id" json:"id"`
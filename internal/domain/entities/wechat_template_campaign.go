@@ -0,0 +1,331 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeChat template campaign audiences
+const (
+	WeChatCampaignAudienceEventAttendees      = "event_attendees"       // Registered attendees of an event
+	WeChatCampaignAudienceSurveyNonResponders = "survey_non_responders" // Followers invited to a survey who did not respond
+	WeChatCampaignAudienceTag                 = "tag"                   // Followers carrying a WeChat user tag
+)
+
+// WeChat template campaign statuses
+const (
+	WeChatTemplateCampaignStatusDraft     = "draft"
+	WeChatTemplateCampaignStatusSending   = "sending"
+	WeChatTemplateCampaignStatusCompleted = "completed" // Every delivery was sent or failed
+	WeChatTemplateCampaignStatusCancelled = "cancelled"
+	WeChatTemplateCampaignStatusFailed    = "failed" // Stopped by an error affecting every delivery, like an invalid template
+)
+
+// WeChat template delivery statuses
+const (
+	WeChatTemplateDeliveryStatusPending   = "pending"
+	WeChatTemplateDeliveryStatusSent      = "sent" // Accepted by WeChat, which reports the delivery in the TEMPLATESENDJOBFINISH event
+	WeChatTemplateDeliveryStatusDelivered = "delivered"
+	WeChatTemplateDeliveryStatusFailed    = "failed"
+	WeChatTemplateDeliveryStatusCancelled = "cancelled"
+)
+
+// WeChatTemplateJobSuccess is the status of the TEMPLATESENDJOBFINISH event of delivered messages;
+// "failed:user block" and "failed: system failed" are reported otherwise
+const WeChatTemplateJobSuccess = "success"
+
+// ErrInvalidWeChatTemplateCampaign is returned for campaigns that cannot be sent
+var ErrInvalidWeChatTemplateCampaign = errors.New("invalid WeChat template campaign")
+
+// WeChatTemplateField maps a field of a WeChat template to its value, in which {placeholders} are
+// filled for each recipient
+type WeChatTemplateField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// WeChatTemplateCampaign sends a template message to an audience of followers, one delivery per
+// follower, with the template fields rendered from the follower and attendee data
+type WeChatTemplateCampaign struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	Name          string     `json:"name" gorm:"not null;size:255"`
+	TemplateID    string     `json:"templateId" gorm:"not null;size:128"`
+	URL           string     `json:"url,omitempty" gorm:"size:1000"` // Opened from the message; {placeholders} are filled per recipient
+	Fields        string     `json:"-" gorm:"type:json;not null"`    // Template fields, see GetFields
+	Audience      string     `json:"audience" gorm:"not null;size:30"`
+	EventID       *uuid.UUID `json:"eventId" gorm:"type:char(36);index"` // Event of the attendee audience, also filling the {event} placeholders
+	SurveyID      *uuid.UUID `json:"surveyId" gorm:"type:char(36)"`
+	TagID         *int       `json:"tagId"`
+	CheckedInOnly bool       `json:"checkedInOnly" gorm:"not null;default:false"` // Attendee audience only: attendees who checked in on site
+	Status        string     `json:"status" gorm:"not null;size:20;default:'draft'"`
+	Total         int        `json:"total" gorm:"not null"` // Deliveries created when the audience was resolved
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"`
+	ResolvedAt    *time.Time `json:"resolvedAt"` // When the deliveries were created
+	StartedAt     *time.Time `json:"startedAt"`
+	CompletedAt   *time.Time `json:"completedAt"`
+	CreatedBy     *uuid.UUID `json:"createdBy" gorm:"type:char(36)"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Computed fields
+	FieldMappings []WeChatTemplateField `json:"fields" gorm:"-"`
+	Stats         map[string]int        `json:"stats,omitempty" gorm:"-"` // Deliveries by status
+}
+
+// TableName returns the table name for WeChatTemplateCampaign
+func (WeChatTemplateCampaign) TableName() string {
+	return "wechat_template_campaigns"
+}
+
+// GetFields parses the template fields of the campaign
+func (c *WeChatTemplateCampaign) GetFields() ([]WeChatTemplateField, error) {
+	var fields []WeChatTemplateField
+	if strings.TrimSpace(c.Fields) == "" {
+		return fields, nil
+	}
+	if err := json.Unmarshal([]byte(c.Fields), &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse fields of WeChat template campaign %s: %w", c.ID, err)
+	}
+	return fields, nil
+}
+
+// SetFields stores the template fields of the campaign as JSON
+func (c *WeChatTemplateCampaign) SetFields(fields []WeChatTemplateField) error {
+	if fields == nil {
+		fields = []WeChatTemplateField{}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode fields of WeChat template campaign %s: %w", c.ID, err)
+	}
+	c.Fields = string(data)
+	c.FieldMappings = fields
+	return nil
+}
+
+// Validate checks the template, its fields and that the audience names its event, survey or tag
+func (c *WeChatTemplateCampaign) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWeChatTemplateCampaign)
+	}
+	if strings.TrimSpace(c.TemplateID) == "" {
+		return fmt.Errorf("%w: template ID is required", ErrInvalidWeChatTemplateCampaign)
+	}
+	if len(c.FieldMappings) == 0 {
+		return fmt.Errorf("%w: at least one template field is required", ErrInvalidWeChatTemplateCampaign)
+	}
+	keys := make(map[string]bool, len(c.FieldMappings))
+	for _, field := range c.FieldMappings {
+		if strings.TrimSpace(field.Key) == "" {
+			return fmt.Errorf("%w: template fields need a key", ErrInvalidWeChatTemplateCampaign)
+		}
+		if keys[field.Key] {
+			return fmt.Errorf("%w: template field %q is mapped twice", ErrInvalidWeChatTemplateCampaign, field.Key)
+		}
+		keys[field.Key] = true
+	}
+
+	switch c.Audience {
+	case WeChatCampaignAudienceEventAttendees:
+		if c.EventID == nil {
+			return fmt.Errorf("%w: the attendee audience needs an event", ErrInvalidWeChatTemplateCampaign)
+		}
+	case WeChatCampaignAudienceSurveyNonResponders:
+		if c.SurveyID == nil {
+			return fmt.Errorf("%w: the non-responder audience needs a survey", ErrInvalidWeChatTemplateCampaign)
+		}
+	case WeChatCampaignAudienceTag:
+		if c.TagID == nil {
+			return fmt.Errorf("%w: the tag audience needs a tag", ErrInvalidWeChatTemplateCampaign)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported audience %q", ErrInvalidWeChatTemplateCampaign, c.Audience)
+	}
+	return nil
+}
+
+// IsFinished checks if the campaign completed, failed or was cancelled
+func (c *WeChatTemplateCampaign) IsFinished() bool {
+	switch c.Status {
+	case WeChatTemplateCampaignStatusCompleted, WeChatTemplateCampaignStatusCancelled, WeChatTemplateCampaignStatusFailed:
+		return true
+	}
+	return false
+}
+
+// Start records that the campaign started sending
+func (c *WeChatTemplateCampaign) Start(startedAt time.Time) {
+	c.Status = WeChatTemplateCampaignStatusSending
+	c.LastError = ""
+	c.StartedAt = &startedAt
+}
+
+// Finish records the final status of the campaign, with the error that stopped it if any
+func (c *WeChatTemplateCampaign) Finish(status, reason string, finishedAt time.Time) {
+	c.Status = status
+	c.LastError = reason
+	c.CompletedAt = &finishedAt
+}
+
+// NewDelivery renders the template fields and the URL of the campaign for a recipient
+func (c *WeChatTemplateCampaign) NewDelivery(recipient *WeChatTemplateRecipient, values map[string]string) (*WeChatTemplateDelivery, error) {
+	data := make(map[string]map[string]string, len(c.FieldMappings))
+	for _, field := range c.FieldMappings {
+		value := map[string]string{"value": RenderSurveyNotificationText(field.Value, values)}
+		if field.Color != "" {
+			value["color"] = field.Color
+		}
+		data[field.Key] = value
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template data of WeChat template campaign %s: %w", c.ID, err)
+	}
+
+	delivery := &WeChatTemplateDelivery{
+		ID:         uuid.New(),
+		CampaignID: c.ID,
+		OpenID:     recipient.OpenID,
+		Data:       string(encoded),
+		URL:        RenderSurveyNotificationText(c.URL, values),
+		Status:     WeChatTemplateDeliveryStatusPending,
+	}
+	if recipient.User != nil {
+		delivery.WeChatUserID = &recipient.User.ID
+	}
+	if recipient.Attendee != nil {
+		delivery.AttendeeID = &recipient.Attendee.ID
+	}
+	return delivery, nil
+}
+
+// WeChatTemplateRecipient is a follower of a campaign audience with the records its placeholders
+// are filled from; User is nil for followers not synced yet
+type WeChatTemplateRecipient struct {
+	OpenID     string
+	User       *WeChatUser
+	Attendee   *EventAttendee
+	Invitation *SurveyInvitation
+}
+
+// Values returns the placeholder values of the recipient: {openId}, {nickname}, {name}, {company},
+// {position}, {mobile}, {email} and {city}, preferring the attendee registration to the follower
+// profile, {event}, {eventStart} and {eventEnd} for campaigns of an event, and {survey} and
+// {invitationToken} for survey non-responders
+func (r *WeChatTemplateRecipient) Values(event *SiteEvent, survey *Survey) map[string]string {
+	values := map[string]string{"openId": r.OpenID}
+	if r.User != nil {
+		values["nickname"] = r.User.NickName
+		values["name"] = r.User.NickName
+		for key, value := range map[string]*string{
+			"name":     r.User.RealName,
+			"company":  r.User.CompanyName,
+			"position": r.User.Position,
+			"mobile":   r.User.Mobile,
+			"email":    r.User.Email,
+			"city":     r.User.City,
+		} {
+			if value != nil && *value != "" {
+				values[key] = *value
+			}
+		}
+	}
+	if r.Attendee != nil {
+		for key, value := range map[string]string{
+			"name":    r.Attendee.Name,
+			"company": r.Attendee.Company,
+			"mobile":  r.Attendee.Mobile,
+		} {
+			if value != "" {
+				values[key] = value
+			}
+		}
+	}
+	if r.Invitation != nil {
+		if _, ok := values["name"]; !ok && r.Invitation.Name != "" {
+			values["name"] = r.Invitation.Name
+		}
+		values["invitationToken"] = r.Invitation.Token
+	}
+	if event != nil {
+		values["event"] = event.EventTitle
+		values["eventStart"] = event.EventStartDate.Format("2006-01-02 15:04")
+		values["eventEnd"] = event.EventEndDate.Format("2006-01-02 15:04")
+	}
+	if survey != nil {
+		values["survey"] = survey.Title
+	}
+	return values
+}
+
+// WeChatTemplateDelivery is the template message of a campaign rendered for one follower, with
+// the outcome of sending it
+type WeChatTemplateDelivery struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	CampaignID   uuid.UUID  `json:"campaignId" gorm:"type:char(36);not null;uniqueIndex:idx_wechat_template_delivery_recipient"`
+	OpenID       string     `json:"openId" gorm:"not null;size:64;uniqueIndex:idx_wechat_template_delivery_recipient"`
+	WeChatUserID *uuid.UUID `json:"wechatUserId" gorm:"column:wechat_user_id;type:char(36)"`
+	AttendeeID   *uuid.UUID `json:"attendeeId" gorm:"type:char(36)"`
+	Data         string     `json:"data" gorm:"type:json;not null"` // Rendered template data, as sent to WeChat
+	URL          string     `json:"url,omitempty" gorm:"size:1000"`
+	Status       string     `json:"status" gorm:"not null;size:20;default:'pending'"`
+	MsgID        int64      `json:"msgId,omitempty" gorm:"index"` // Assigned by WeChat when the message is sent
+	Attempts     int        `json:"attempts" gorm:"not null"`
+	LastError    string     `json:"lastError,omitempty" gorm:"type:text"`
+	SentAt       *time.Time `json:"sentAt"`
+	FinishedAt   *time.Time `json:"finishedAt"` // When WeChat reported the delivery, or sending failed
+	CreatedAt    time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for WeChatTemplateDelivery
+func (WeChatTemplateDelivery) TableName() string {
+	return "wechat_template_deliveries"
+}
+
+// GetData parses the rendered template data
+func (d *WeChatTemplateDelivery) GetData() (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(d.Data), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse data of WeChat template delivery %s: %w", d.ID, err)
+	}
+	return data, nil
+}
+
+// Sent records that WeChat accepted the message
+func (d *WeChatTemplateDelivery) Sent(msgID int64, sentAt time.Time) {
+	d.Status = WeChatTemplateDeliveryStatusSent
+	d.MsgID = msgID
+	d.Attempts++
+	d.LastError = ""
+	d.SentAt = &sentAt
+}
+
+// RecordFailure records a failed attempt, failing the delivery when the error is permanent or
+// the attempts are exhausted; otherwise it stays pending to be retried
+func (d *WeChatTemplateDelivery) RecordFailure(reason string, permanent bool, maxAttempts int, failedAt time.Time) {
+	d.Attempts++
+	d.LastError = reason
+	if permanent || d.Attempts >= maxAttempts {
+		d.Status = WeChatTemplateDeliveryStatusFailed
+		d.FinishedAt = &failedAt
+	}
+}
+
+// Finish records the outcome WeChat reports in the TEMPLATESENDJOBFINISH event
+func (d *WeChatTemplateDelivery) Finish(wechatStatus string, finishedAt time.Time) {
+	d.FinishedAt = &finishedAt
+	if wechatStatus == WeChatTemplateJobSuccess {
+		d.Status = WeChatTemplateDeliveryStatusDelivered
+		d.LastError = ""
+		return
+	}
+	d.Status = WeChatTemplateDeliveryStatusFailed
+	d.LastError = wechatStatus
+}
@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWeChatTemplateCampaignValidate(t *testing.T) {
+	eventID := uuid.New()
+	tagID := 100
+	fields := []WeChatTemplateField{{Key: "first", Value: "Hi {name}"}, {Key: "keyword1", Value: "{event}"}}
+	tests := []struct {
+		name     string
+		campaign WeChatTemplateCampaign
+		valid    bool
+	}{
+		{"event attendees", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", FieldMappings: fields, Audience: WeChatCampaignAudienceEventAttendees, EventID: &eventID}, true},
+		{"tag", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", FieldMappings: fields, Audience: WeChatCampaignAudienceTag, TagID: &tagID}, true},
+		{"missing template", WeChatTemplateCampaign{Name: "Reminder", FieldMappings: fields, Audience: WeChatCampaignAudienceTag, TagID: &tagID}, false},
+		{"no fields", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", Audience: WeChatCampaignAudienceTag, TagID: &tagID}, false},
+		{"field mapped twice", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", FieldMappings: append(fields, WeChatTemplateField{Key: "first"}), Audience: WeChatCampaignAudienceTag, TagID: &tagID}, false},
+		{"attendees without event", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", FieldMappings: fields, Audience: WeChatCampaignAudienceEventAttendees}, false},
+		{"non-responders without survey", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", FieldMappings: fields, Audience: WeChatCampaignAudienceSurveyNonResponders, EventID: &eventID}, false},
+		{"unsupported audience", WeChatTemplateCampaign{Name: "Reminder", TemplateID: "tpl-1", FieldMappings: fields, Audience: "everyone"}, false},
+	}
+	for _, tt := range tests {
+		err := tt.campaign.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidWeChatTemplateCampaign) {
+			t.Errorf("%s: Expected ErrInvalidWeChatTemplateCampaign, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestWeChatTemplateCampaignNewDelivery(t *testing.T) {
+	realName := "Li Lei"
+	city := "Shanghai"
+	user := &WeChatUser{ID: uuid.New(), OpenID: "openid-1", NickName: "lilei", RealName: &realName, City: &city}
+	attendee := &EventAttendee{ID: uuid.New(), Name: "Lei Li", Company: "Acme"}
+	event := &SiteEvent{EventTitle: "DevCon", EventStartDate: time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)}
+
+	campaign := &WeChatTemplateCampaign{ID: uuid.New(), URL: "https://example.com/checkin?openid={openId}"}
+	campaign.SetFields([]WeChatTemplateField{
+		{Key: "first", Value: "Dear {name} of {company}", Color: "#173177"},
+		{Key: "keyword1", Value: "{event} at {eventStart}"},
+		{Key: "remark", Value: "{city}, {unknown}"},
+	})
+
+	recipient := &WeChatTemplateRecipient{OpenID: user.OpenID, User: user, Attendee: attendee}
+	delivery, err := campaign.NewDelivery(recipient, recipient.Values(event, nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if delivery.URL != "https://example.com/checkin?openid=openid-1" {
+		t.Errorf("Expected the URL to be rendered, got %s", delivery.URL)
+	}
+	if delivery.Status != WeChatTemplateDeliveryStatusPending || *delivery.WeChatUserID != user.ID || *delivery.AttendeeID != attendee.ID {
+		t.Errorf("Expected a pending delivery of the follower and attendee, got %+v", delivery)
+	}
+
+	data, err := delivery.GetData()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := map[string]map[string]string{
+		"first":    {"value": "Dear Lei Li of Acme", "color": "#173177"},
+		"keyword1": {"value": "DevCon at 2025-03-01 09:30"},
+		"remark":   {"value": "Shanghai, {unknown}"},
+	}
+	for key, want := range expected {
+		got, _ := data[key].(map[string]interface{})
+		for name, value := range want {
+			if got[name] != value {
+				t.Errorf("Expected %s.%s to be %q, got %v", key, name, value, got[name])
+			}
+		}
+	}
+}
+
+func TestWeChatTemplateDeliveryOutcome(t *testing.T) {
+	now := time.Date(2025, 1, 19, 9, 0, 0, 0, time.UTC)
+
+	delivery := &WeChatTemplateDelivery{Status: WeChatTemplateDeliveryStatusPending}
+	delivery.RecordFailure("system busy", false, 3, now)
+	if delivery.Status != WeChatTemplateDeliveryStatusPending || delivery.Attempts != 1 {
+		t.Errorf("Expected a temporary failure to keep the delivery pending, got %+v", delivery)
+	}
+	delivery.RecordFailure("system busy", false, 2, now)
+	if delivery.Status != WeChatTemplateDeliveryStatusFailed || delivery.FinishedAt == nil {
+		t.Errorf("Expected the last attempt to fail the delivery, got %+v", delivery)
+	}
+
+	delivery = &WeChatTemplateDelivery{Status: WeChatTemplateDeliveryStatusPending}
+	delivery.RecordFailure("user unsubscribed", true, 3, now)
+	if delivery.Status != WeChatTemplateDeliveryStatusFailed {
+		t.Errorf("Expected a permanent failure to fail the delivery, got %s", delivery.Status)
+	}
+
+	delivery = &WeChatTemplateDelivery{Status: WeChatTemplateDeliveryStatusPending}
+	delivery.Sent(1001, now)
+	if delivery.Status != WeChatTemplateDeliveryStatusSent || delivery.MsgID != 1001 {
+		t.Errorf("Expected a sent delivery, got %+v", delivery)
+	}
+	delivery.Finish("failed:user block", now.Add(time.Second))
+	if delivery.Status != WeChatTemplateDeliveryStatusFailed || delivery.LastError != "failed:user block" {
+		t.Errorf("Expected a blocked delivery to fail, got %+v", delivery)
+	}
+	delivery.Finish(WeChatTemplateJobSuccess, now.Add(time.Second))
+	if delivery.Status != WeChatTemplateDeliveryStatusDelivered || delivery.LastError != "" {
+		t.Errorf("Expected a delivered message, got %+v", delivery)
+	}
+}
@@ -2,25 +2,40 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/zenteam/nextevent-go/internal/domain/entities"
 )
 
+// ErrAttendeeExists is returned when the user already holds an active registration for the event
+var ErrAttendeeExists = errors.New("attendee already registered for event")
+
 // EventAttendeeRepository defines the interface for EventAttendee data operations
 type EventAttendeeRepository interface {
 	// Create creates a new event attendee
 	Create(ctx context.Context, attendee *entities.EventAttendee) error
 	
+	// CreateBatchWithCapacity creates several event attendees in one transaction, in order, as
+	// registered while places are free and as waitlisted otherwise. A capacity of 0 means unlimited.
+	CreateBatchWithCapacity(ctx context.Context, attendees []*entities.EventAttendee, capacity int) error
+	
 	// GetByID retrieves an event attendee by ID
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.EventAttendee, error)
 	
 	// GetByEventAndUser retrieves an attendee by event and user ID
 	GetByEventAndUser(ctx context.Context, eventID, userID uuid.UUID) (*entities.EventAttendee, error)
 	
+	// GetByEventAndMobile retrieves an attendee by event and mobile number
+	GetByEventAndMobile(ctx context.Context, eventID uuid.UUID, mobile string) (*entities.EventAttendee, error)
+	
 	// GetByEvent retrieves all attendees for an event with pagination
 	GetByEvent(ctx context.Context, eventID uuid.UUID, offset, limit int) ([]*entities.EventAttendee, error)
 	
+	// GetActivityByEvent retrieves the registration and check-in fields of every attendee of an event,
+	// without associations, for computing statistics
+	GetActivityByEvent(ctx context.Context, eventID uuid.UUID) ([]*entities.EventAttendee, error)
+	
 	// GetByUser retrieves all attendees for a user with pagination
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entities.EventAttendee, error)
 	
@@ -44,4 +59,18 @@ type EventAttendeeRepository interface {
 	
 	// GetAttendeesByStatus retrieves attendees by status
 	GetAttendeesByStatus(ctx context.Context, eventID uuid.UUID, status string, offset, limit int) ([]*entities.EventAttendee, error)
+	
+	// CountRegisteredByEvent returns the number of attendees holding a confirmed place
+	CountRegisteredByEvent(ctx context.Context, eventID uuid.UUID) (int64, error)
+	
+	// CountByEventAndStatus returns the number of attendees with the given status
+	CountByEventAndStatus(ctx context.Context, eventID uuid.UUID, status string) (int64, error)
+	
+	// CreateWithCapacity creates the attendee as registered while the event has fewer than capacity
+	// registered attendees and as waitlisted otherwise. A capacity of 0 means unlimited. Returns
+	// ErrAttendeeExists when the attendee's user already holds a registration that is not cancelled.
+	CreateWithCapacity(ctx context.Context, attendee *entities.EventAttendee, capacity int) error
+	
+	// PromoteWaitlisted moves the longest-waiting attendees off the waitlist while places are free
+	PromoteWaitlisted(ctx context.Context, eventID uuid.UUID, capacity int) ([]*entities.EventAttendee, error)
 }
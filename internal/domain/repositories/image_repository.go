@@ -11,7 +11,8 @@ import (
 
 // Common repository errors
 var (
-	ErrNotFound = errors.New("record not found")
+	ErrNotFound     = errors.New("record not found")
+	ErrLimitReached = errors.New("limit reached")
 )
 
 // ImageFilter represents filtering options for image queries
@@ -106,6 +106,8 @@ type SurveyQuestionRepository interface {
 type SurveyResponseFilter struct {
 	SurveyID     uuid.UUID
 	RespondentID *uuid.UUID
+	SessionID    string
+	CollectorID  *uuid.UUID
 	Status       entities.ResponseStatus
 	StartDate    *time.Time
 	EndDate      *time.Time
@@ -134,12 +136,18 @@ type SurveyResponseRepository interface {
 	CountWithFilter(ctx context.Context, filter SurveyResponseFilter) (int64, error)
 	FindByRespondent(ctx context.Context, respondentID uuid.UUID, limit int) ([]entities.SurveyResponse, error)
 	FindBySessionID(ctx context.Context, sessionID string) (*entities.SurveyResponse, error)
+	// FindInBatches walks the matching responses, with their answers, in batches of batchSize
+	FindInBatches(ctx context.Context, filter SurveyResponseFilter, batchSize int, fn func(responses []entities.SurveyResponse) error) error
 
 	// Status operations
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entities.ResponseStatus) error
 	MarkCompleted(ctx context.Context, id uuid.UUID) error
 	MarkSubmitted(ctx context.Context, id uuid.UUID) error
 	MarkAbandoned(ctx context.Context, id uuid.UUID) error
+	// SaveWithAnswers replaces the answers of a response and saves it in one transaction. With a
+	// positive maxCompleted the survey row is locked, and ErrLimitReached is returned without
+	// writing anything when the survey already has that many completed responses.
+	SaveWithAnswers(ctx context.Context, response *entities.SurveyResponse, answers []entities.SurveyAnswer, maxCompleted int) error
 
 	// Analytics operations
 	GetResponseStats(ctx context.Context, surveyID uuid.UUID) (*ResponseStats, error)
@@ -306,3 +314,132 @@ type SurveyShareRepository interface {
 	IncrementAccess(ctx context.Context, id uuid.UUID) error
 	FindActive(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyShare, error)
 }
+
+// SurveyLogicRepository defines the interface for survey logic data access
+type SurveyLogicRepository interface {
+	Create(ctx context.Context, logic *entities.SurveyLogic) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyLogic, error)
+	Update(ctx context.Context, logic *entities.SurveyLogic) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error)
+	FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error)
+}
+
+// SurveyPipingRepository defines the interface for survey answer piping data access
+type SurveyPipingRepository interface {
+	Create(ctx context.Context, piping *entities.SurveyPiping) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyPiping, error)
+	Update(ctx context.Context, piping *entities.SurveyPiping) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyPiping, error)
+	FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyPiping, error)
+}
+
+// SurveySessionRepository defines the interface for survey session data access
+type SurveySessionRepository interface {
+	Create(ctx context.Context, session *entities.SurveySession) error
+	FindBySessionID(ctx context.Context, sessionID string) (*entities.SurveySession, error)
+	FindByResponseID(ctx context.Context, responseID uuid.UUID) (*entities.SurveySession, error)
+	Update(ctx context.Context, session *entities.SurveySession) error
+}
+
+// SurveyQuotaRepository defines the interface for survey quota data access
+type SurveyQuotaRepository interface {
+	Create(ctx context.Context, quota *entities.SurveyQuota) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyQuota, error)
+	Update(ctx context.Context, quota *entities.SurveyQuota) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error)
+	FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error)
+
+	// IncrementIfAvailable atomically counts a response against the quota; it returns false when the quota is full
+	IncrementIfAvailable(ctx context.Context, id uuid.UUID) (bool, error)
+	Decrement(ctx context.Context, id uuid.UUID) error
+}
+
+// SurveyExportRepository defines the interface for survey export data access
+type SurveyExportRepository interface {
+	Create(ctx context.Context, export *entities.SurveyExport) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, error)
+	Update(ctx context.Context, export *entities.SurveyExport) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID, limit int) ([]entities.SurveyExport, error)
+	// FindExpiredWithFiles finds exports that expired before the given time and still have a stored file
+	FindExpiredWithFiles(ctx context.Context, before time.Time, limit int) ([]entities.SurveyExport, error)
+}
+
+// SurveyInvitationRepository defines the interface for survey invitation data access
+type SurveyInvitationRepository interface {
+	CreateBatch(ctx context.Context, invitations []entities.SurveyInvitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyInvitation, error)
+	FindByToken(ctx context.Context, token string) (*entities.SurveyInvitation, error)
+	Update(ctx context.Context, invitation *entities.SurveyInvitation) error
+	// FindBySurveyID lists a survey's invitations, optionally with one status
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID, status string) ([]entities.SurveyInvitation, error)
+	// FindAwaitingResponse finds unexpired invitations that were not responded to, last contacted
+	// before the cutoff and reminded fewer than maxReminders times
+	FindAwaitingResponse(ctx context.Context, surveyID *uuid.UUID, contactedBefore time.Time, maxReminders, limit int) ([]entities.SurveyInvitation, error)
+	// ExpireOverdue marks invitations past their expiry that were not responded to as expired
+	ExpireOverdue(ctx context.Context, now time.Time) (int64, error)
+}
+
+// SurveyNotificationRepository defines the interface for survey notification data access
+type SurveyNotificationRepository interface {
+	Create(ctx context.Context, notification *entities.SurveyNotification) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyNotification, error)
+	Update(ctx context.Context, notification *entities.SurveyNotification) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyNotification, error)
+	// FindActive finds the active notifications of a survey event
+	FindActive(ctx context.Context, surveyID uuid.UUID, eventType string) ([]entities.SurveyNotification, error)
+	// FindUndispatchedClosed finds active survey_closed notifications that were never dispatched, of
+	// surveys that are closed or past their end date
+	FindUndispatchedClosed(ctx context.Context, now time.Time, limit int) ([]entities.SurveyNotification, error)
+	// RecordSent counts a sent delivery of the notification
+	RecordSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+}
+
+// SurveyNotificationDeliveryRepository defines the interface for the survey notification delivery log
+type SurveyNotificationDeliveryRepository interface {
+	CreateBatch(ctx context.Context, deliveries []entities.SurveyNotificationDelivery) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyNotificationDelivery, error)
+	Update(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error
+	// FindByNotificationID lists the latest deliveries of a notification
+	FindByNotificationID(ctx context.Context, notificationID uuid.UUID, limit int) ([]entities.SurveyNotificationDelivery, error)
+	// FindDue finds pending deliveries whose next attempt is due
+	FindDue(ctx context.Context, now time.Time, limit int) ([]entities.SurveyNotificationDelivery, error)
+	// ExistsForNotification reports whether the notification was dispatched before
+	ExistsForNotification(ctx context.Context, notificationID uuid.UUID) (bool, error)
+}
+
+// SurveyCollectorRepository defines the interface for survey collector data access
+type SurveyCollectorRepository interface {
+	Create(ctx context.Context, collector *entities.SurveyCollector) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyCollector, error)
+	Update(ctx context.Context, collector *entities.SurveyCollector) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyCollector, error)
+
+	// IncrementIfAvailable atomically counts a response against the collector; it returns false when the collector is full
+	IncrementIfAvailable(ctx context.Context, id uuid.UUID) (bool, error)
+	Decrement(ctx context.Context, id uuid.UUID) error
+}
+
+// SurveyVersionRepository defines the interface for survey version data access
+type SurveyVersionRepository interface {
+	Create(ctx context.Context, version *entities.SurveyVersion) error
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyVersion, error)
+	FindByVersion(ctx context.Context, surveyID uuid.UUID, version int) (*entities.SurveyVersion, error)
+}
+
+// SurveyLivePollRepository defines the interface for survey live poll data access
+type SurveyLivePollRepository interface {
+	Create(ctx context.Context, poll *entities.SurveyLivePoll) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyLivePoll, error)
+	Update(ctx context.Context, poll *entities.SurveyLivePoll) error
+	// FindBySurveyID lists the polls of a survey, latest first
+	FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLivePoll, error)
+	// FindLatestByEventID finds the poll opened last at an event, open or not
+	FindLatestByEventID(ctx context.Context, eventID uuid.UUID) (*entities.SurveyLivePoll, error)
+	// FindOpenByEventID lists the polls of an event that are still open
+	FindOpenByEventID(ctx context.Context, eventID uuid.UUID) ([]entities.SurveyLivePoll, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChatFollowerSyncRepository defines the interface for WeChat follower sync data operations
+type WeChatFollowerSyncRepository interface {
+	Create(ctx context.Context, sync *entities.WeChatFollowerSync) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatFollowerSync, error)
+	Update(ctx context.Context, sync *entities.WeChatFollowerSync) error
+	// FindRunning finds the sync started last among those still running
+	FindRunning(ctx context.Context) (*entities.WeChatFollowerSync, error)
+	// FindRecent finds the syncs started last, latest first
+	FindRecent(ctx context.Context, limit int) ([]entities.WeChatFollowerSync, error)
+}
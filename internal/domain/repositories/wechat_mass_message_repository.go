@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChatMassMessageRepository defines the interface for WeChat mass message data operations
+type WeChatMassMessageRepository interface {
+	Create(ctx context.Context, message *entities.WeChatMassMessage) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error)
+	// FindByMsgID finds the message WeChat assigned a msg_id when sending it
+	FindByMsgID(ctx context.Context, msgID int64) (*entities.WeChatMassMessage, error)
+	Update(ctx context.Context, message *entities.WeChatMassMessage) error
+	// ClaimForSending moves a draft or failed message to sending and reports whether it did, so
+	// that only one request sends it
+	ClaimForSending(ctx context.Context, id uuid.UUID) (bool, error)
+	// FindRecent finds the messages created last, latest first
+	FindRecent(ctx context.Context, limit int) ([]entities.WeChatMassMessage, error)
+}
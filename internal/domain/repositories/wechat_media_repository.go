@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChatMediaRepository defines the interface for WeChat media data operations
+type WeChatMediaRepository interface {
+	Create(ctx context.Context, media *entities.WeChatMedia) error
+	// FindByContentHash finds the media of a type last uploaded with the content
+	FindByContentHash(ctx context.Context, contentHash, mediaType string, permanent bool) (*entities.WeChatMedia, error)
+	// FindBySiteImage finds the media of a type last mapped to the site image
+	FindBySiteImage(ctx context.Context, siteImageID uuid.UUID, mediaType string, permanent bool) (*entities.WeChatMedia, error)
+	// DeleteByMediaID deletes the records of WeChat media, including their site image mappings
+	DeleteByMediaID(ctx context.Context, mediaID string) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChatMenuRepository defines the interface for WeChat menu version data operations
+type WeChatMenuRepository interface {
+	Create(ctx context.Context, menu *entities.WeChatMenu) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatMenu, error)
+	Update(ctx context.Context, menu *entities.WeChatMenu) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// FindByTagID finds the versions of the menu of a tag, or of the default menu for an empty tag, latest first
+	FindByTagID(ctx context.Context, tagID string) ([]entities.WeChatMenu, error)
+	// FindPublished finds the published menus, the default menu first
+	FindPublished(ctx context.Context) ([]entities.WeChatMenu, error)
+}
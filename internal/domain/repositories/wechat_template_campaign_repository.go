@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChatTemplateCampaignRepository defines the interface for WeChat template campaign data operations
+type WeChatTemplateCampaignRepository interface {
+	Create(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error)
+	Update(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error
+	// FindRecent finds the campaigns created last, latest first
+	FindRecent(ctx context.Context, limit int) ([]entities.WeChatTemplateCampaign, error)
+	// FindStalled finds the sending campaigns neither they nor their deliveries were updated
+	// since idleSince, oldest first
+	FindStalled(ctx context.Context, idleSince time.Time, limit int) ([]entities.WeChatTemplateCampaign, error)
+}
+
+// WeChatTemplateDeliveryRepository defines the interface for the deliveries of WeChat template campaigns
+type WeChatTemplateDeliveryRepository interface {
+	// CreateBatch creates deliveries, skipping followers the campaign already has a delivery for
+	CreateBatch(ctx context.Context, deliveries []entities.WeChatTemplateDelivery) error
+	// FindPending finds the pending deliveries of a campaign, oldest first
+	FindPending(ctx context.Context, campaignID uuid.UUID, limit int) ([]entities.WeChatTemplateDelivery, error)
+	// FindByMsgID finds the delivery WeChat assigned a msgid when sending it
+	FindByMsgID(ctx context.Context, msgID int64) (*entities.WeChatTemplateDelivery, error)
+	// FindByCampaign finds the deliveries of a campaign with a status, or all of them for ""
+	FindByCampaign(ctx context.Context, campaignID uuid.UUID, status string, offset, limit int) ([]entities.WeChatTemplateDelivery, int64, error)
+	Update(ctx context.Context, delivery *entities.WeChatTemplateDelivery) error
+	// CountByStatus counts the deliveries of a campaign by status
+	CountByStatus(ctx context.Context, campaignID uuid.UUID) (map[string]int, error)
+	// CancelPending cancels the pending deliveries of a campaign and returns how many were cancelled
+	CancelPending(ctx context.Context, campaignID uuid.UUID) (int64, error)
+}
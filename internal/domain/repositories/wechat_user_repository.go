@@ -15,6 +15,7 @@ type WeChatUserRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.WeChatUser, error)
 	GetByOpenID(ctx context.Context, openID string) (*entities.WeChatUser, error)
 	GetByUnionID(ctx context.Context, unionID string) (*entities.WeChatUser, error)
+	GetByMobiles(ctx context.Context, mobiles []string) ([]*entities.WeChatUser, error)
 	Update(ctx context.Context, user *entities.WeChatUser) error
 	Delete(ctx context.Context, id uuid.UUID) error
 
@@ -41,6 +42,9 @@ type WeChatUserRepository interface {
 
 	// Bulk operations
 	BulkUpdateSubscription(ctx context.Context, openIDs []string, subscribed bool) error
+	// UnsubscribeNotUpdatedSince marks the subscribed users not updated since the given time as
+	// unsubscribed and returns how many were marked
+	UnsubscribeNotUpdatedSince(ctx context.Context, since time.Time) (int64, error)
 	BulkDelete(ctx context.Context, ids []uuid.UUID) error
 
 	// Statistics
@@ -62,6 +66,10 @@ type WeChatUserFilter struct {
 	City      string  // Filter by city
 	Province  string  // Filter by province
 	Country   string  // Filter by country
+	AllowTest *bool   // Filter by test users, who receive previews of mass messages
+
+	// CheckedInEventID keeps the users who checked in to the event on site
+	CheckedInEventID *uuid.UUID
 
 	// Date range filters
 	SubscribeTimeStart *time.Time
@@ -2,12 +2,34 @@ package services
 
 import (
 	"context"
+	"io"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/zenteam/nextevent-go/internal/domain/entities"
 )
 
+var (
+	// ErrAlreadyRegistered is returned when the user already holds a registration for the event
+	ErrAlreadyRegistered = errors.New("user is already registered for this event")
+	// ErrAttendeeNotRegistered is returned when checking in a waitlisted or cancelled attendee
+	ErrAttendeeNotRegistered = errors.New("attendee does not hold a confirmed place")
+	// ErrInvalidAttendeeStatus is returned for unknown attendee statuses
+	ErrInvalidAttendeeStatus = errors.New("invalid attendee status")
+	// ErrEventEnded is returned when issuing a ticket for an event whose tickets can no longer be used
+	ErrEventEnded = errors.New("event has already ended")
+	// ErrTicketSigningDisabled is returned for ticket operations when no ticket signing key is configured
+	ErrTicketSigningDisabled = errors.New("ticket signing is disabled")
+	// ErrUnsupportedFileFormat is returned for attendee files that are neither CSV nor XLSX
+	ErrUnsupportedFileFormat = errors.New("unsupported file format")
+	// ErrInvalidAttendeeFile is returned when an attendee file cannot be read as a whole,
+	// e.g. it has no mobile column or too many rows
+	ErrInvalidAttendeeFile = errors.New("invalid attendee file")
+	// ErrInvalidStatisticsInterval is returned for trend intervals other than hour and day
+	ErrInvalidStatisticsInterval = errors.New("invalid statistics interval")
+)
+
 // EventService defines the interface for event management operations
 type EventService interface {
 	// Event CRUD Operations
@@ -26,7 +48,7 @@ type EventService interface {
 	GetUpcomingEvents(ctx context.Context, limit int) ([]*entities.SiteEvent, error)
 	
 	// Event Analytics
-	GetEventStatistics(ctx context.Context, eventID uuid.UUID) (*EventStatistics, error)
+	GetEventStatistics(ctx context.Context, eventID uuid.UUID, interval string) (*EventStatistics, error)
 	GetEventAttendanceReport(ctx context.Context, eventID uuid.UUID) (*AttendanceReport, error)
 }
 
@@ -47,6 +69,10 @@ type AttendeeService interface {
 	UpdateAttendeeStatus(ctx context.Context, attendeeID uuid.UUID, status string) error
 	CancelRegistration(ctx context.Context, attendeeID uuid.UUID) error
 	GetAttendeeCount(ctx context.Context, eventID uuid.UUID) (int64, error)
+	GetAttendeeCounts(ctx context.Context, eventID uuid.UUID) (*AttendeeCounts, error)
+	
+	// Waitlist Management
+	PromoteWaitlist(ctx context.Context, eventID uuid.UUID) error
 }
 
 // QRCodeService defines the interface for QR code operations
@@ -61,7 +87,29 @@ type QRCodeService interface {
 	
 	// QR Code Management
 	GetQRCodeInfo(ctx context.Context, qrCode string) (*QRCodeInfo, error)
+	GetQRCodeImage(ctx context.Context, qrCode string, size int) ([]byte, error)
 	DeactivateQRCode(ctx context.Context, qrCode string) error
+	
+	// Offline Verification
+	GetTicketVerificationKey(ctx context.Context) (*TicketVerificationKey, error)
+}
+
+// AttendeeTransferService defines the interface for bulk attendee import and attendance export
+type AttendeeTransferService interface {
+	// ImportAttendees validates an attendee list and, unless dryRun is set, registers the valid rows
+	ImportAttendees(ctx context.Context, eventID uuid.UUID, file io.Reader, format string, dryRun bool) (*AttendeeImportResult, error)
+	
+	// ExportAttendance writes the event's attendance report in the given format
+	ExportAttendance(ctx context.Context, eventID uuid.UUID, format string, w io.Writer) error
+}
+
+// EventCheckInService defines the interface for checking followers in by WeChat QR code scans
+type EventCheckInService interface {
+	// GenerateEventQRCode issues the WeChat QR code followers scan to check in to an event
+	GenerateEventQRCode(ctx context.Context, eventID uuid.UUID, qrType entities.QRCodeType) (*entities.WeChatQrCode, error)
+
+	// CheckInByScene checks the follower in from the scene string of a scanned QR code
+	CheckInByScene(ctx context.Context, openID, sceneStr, ticket string) (*QRScanResult, error)
 }
 
 // EventSearchQuery represents search criteria for events
@@ -87,15 +135,28 @@ type AttendeeRegistration struct {
 	SourceData  map[string]interface{}
 }
 
+// Statistics trend intervals
+const (
+	StatisticsIntervalHour = "hour"
+	StatisticsIntervalDay  = "day"
+)
+
 // EventStatistics represents event analytics data
 type EventStatistics struct {
 	EventID           uuid.UUID
-	TotalRegistered   int64
-	TotalCheckedIn    int64
-	CheckInRate       float64
+	Interval          string // Bucket size of the trends
+	TotalRegistered   int64  // Attendees holding a confirmed place
+	TotalWaitlisted   int64
+	TotalCancelled    int64
+	TotalCheckedIn    int64   // Attendees currently checked in
+	CheckInRate       float64 // Percentage of registered attendees who checked in
+	CheckInsLastHour  int64
+	LastCheckInAt     *time.Time
 	RegistrationTrend []RegistrationPoint
 	CheckInTrend      []CheckInPoint
-	TopSources        []SourceStatistic
+	TopSources        []SourceStatistic // Registrations and check-ins by registration source
+	CheckInMethods    []SourceStatistic // Check-ins by method (WeChat scan, ticket scan, ...)
+	GeneratedAt       time.Time
 }
 
 // AttendanceReport represents detailed attendance information
@@ -110,6 +171,54 @@ type AttendanceReport struct {
 	Timeline        []AttendanceTimePoint
 }
 
+// Attendee list file formats
+const (
+	AttendeeFileFormatCSV  = "csv"
+	AttendeeFileFormatXLSX = "xlsx"
+)
+
+// Attendee import row outcomes
+const (
+	ImportRowCreated   = "created"   // Registered (or would be, in a dry run)
+	ImportRowDuplicate = "duplicate" // Mobile already registered for the event or repeated in the file
+	ImportRowInvalid   = "invalid"   // Failed validation
+)
+
+// AttendeeImportResult summarizes an attendee import or its dry-run preview
+type AttendeeImportResult struct {
+	EventID    uuid.UUID
+	DryRun     bool
+	TotalRows  int
+	Created    int
+	Duplicates int
+	Invalid    int
+	Waitlisted int // Created rows that did not fit the event's capacity
+	Matched    int // Created rows linked to a WeChat follower by mobile
+	Rows       []AttendeeImportRow
+}
+
+// AttendeeImportRow is the outcome of one data row of an attendee list
+type AttendeeImportRow struct {
+	Row            int // 1-based line number in the file, counting the header
+	Mobile         string
+	Name           string
+	Company        string
+	Outcome        string
+	AttendeeStatus string // Registration status of created rows
+	UserID         *uuid.UUID
+	Errors         []string
+}
+
+// AttendeeCounts represents registration counts for a capacity-limited event
+type AttendeeCounts struct {
+	EventID    uuid.UUID
+	Capacity   int // 0 means unlimited
+	Registered int64
+	Waitlisted int64
+	Cancelled  int64
+	CheckedIn  int64
+}
+
 // CheckInResult represents the result of a check-in operation
 type CheckInResult struct {
 	Success       bool
@@ -166,31 +275,54 @@ type QRValidationResult struct {
 	IsValid   bool
 	Type      string
 	EntityID  uuid.UUID
+	EventID   *uuid.UUID
 	ExpiresAt *time.Time
 	Message   string
 }
 
+// TicketVerificationKey is what a door scanner caches to verify attendee tickets while offline
+type TicketVerificationKey struct {
+	Algorithm   string // "Ed25519"
+	KeyID       string
+	PublicKey   []byte
+	Revocations []TicketRevocation
+	GeneratedAt time.Time
+}
+
+// TicketRevocation revokes a single ticket (by ticket ID) or every ticket of an attendee
+// issued up to RevokedAt (by attendee ID)
+type TicketRevocation struct {
+	ID        string
+	RevokedAt time.Time
+	ExpiresAt time.Time
+}
+
 // Supporting data structures
 type RegistrationPoint struct {
-	Date  time.Time
-	Count int64
+	Date       time.Time // Start of the bucket
+	Count      int64
+	Cumulative int64
 }
 
 type CheckInPoint struct {
-	Date  time.Time
-	Count int64
+	Date       time.Time // Start of the bucket
+	Count      int64
+	Cumulative int64
 }
 
 type SourceStatistic struct {
-	Source string
-	Count  int64
-	Percentage float64
+	Source         string
+	Count          int64
+	Percentage     float64 // Share of the total
+	CheckedIn      int64
+	ConversionRate float64 // Percentage of the source's registered attendees who checked in
 }
 
 type AttendeeInfo struct {
 	AttendeeID   uuid.UUID
 	UserID       uuid.UUID
 	UserName     string
+	Company      string
 	Email        string
 	PhoneNumber  string
 	RegisteredAt time.Time
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+var (
+	// ErrInvalidSurveyResponse is returned when the answers of a response fail validation
+	ErrInvalidSurveyResponse = errors.New("invalid survey response")
+
+	ErrSurveyNotOpen           = errors.New("survey is not open for responses")
+	ErrSurveyClosed            = errors.New("survey has ended")
+	ErrSurveyFull              = errors.New("survey has reached its maximum number of responses")
+	ErrSurveyLoginRequired     = errors.New("survey requires login")
+	ErrSurveyAlreadyResponded  = errors.New("survey has already been answered")
+	ErrSurveyTimeLimitExceeded = errors.New("survey time limit exceeded")
+	ErrSurveyResponseSubmitted = errors.New("survey response is no longer in progress")
+	ErrSurveyQuotaFull         = errors.New("survey quota is full")
+	ErrSurveyExportNotReady    = errors.New("survey export is not ready")
+	ErrSurveyExportExpired     = errors.New("survey export has expired")
+	ErrInvalidSurveyCrossTab   = errors.New("invalid survey cross-tabulation")
+	ErrSurveyTemplateBuiltIn   = errors.New("built-in survey templates cannot be changed")
+	ErrSurveyInvitationExpired = errors.New("survey invitation has expired")
+	ErrSurveyInvitationUsed    = errors.New("survey invitation has already been used")
+	ErrSurveyCollectorClosed   = errors.New("survey collector is not accepting responses")
+	ErrSurveyNotRevisable      = errors.New("archived surveys cannot be revised")
+	ErrInvalidSurveyRevision   = errors.New("invalid survey revision")
+	ErrLivePollNotSupported    = errors.New("question cannot be used in a live poll")
+	ErrNoLivePoll              = errors.New("no live poll is open")
+	ErrLivePollClosed          = errors.New("live poll is closed")
+	ErrLivePollAlreadyVoted    = errors.New("live poll has already been answered")
+	ErrInvalidLivePollVote     = errors.New("invalid live poll vote")
+)
+
+// SurveyResponseValidationError lists the answer errors of a response by question
+type SurveyResponseValidationError struct {
+	Errors map[uuid.UUID]string
+}
+
+// Error summarizes the answer errors
+func (e *SurveyResponseValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for questionID, message := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", questionID, message))
+	}
+	sort.Strings(messages)
+	return fmt.Sprintf("%s: %s", ErrInvalidSurveyResponse, strings.Join(messages, "; "))
+}
+
+// Unwrap makes the error match ErrInvalidSurveyResponse
+func (e *SurveyResponseValidationError) Unwrap() error {
+	return ErrInvalidSurveyResponse
+}
+
+// SurveyQuotaFullError reports the full quota that screened a response out
+type SurveyQuotaFullError struct {
+	Quota entities.SurveyQuota
+}
+
+// Error describes the full quota
+func (e *SurveyQuotaFullError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrSurveyQuotaFull, e.Quota.Name)
+}
+
+// Unwrap makes the error match ErrSurveyQuotaFull
+func (e *SurveyQuotaFullError) Unwrap() error {
+	return ErrSurveyQuotaFull
+}
+
+// SurveyLogicService defines the interface for survey branching logic
+type SurveyLogicService interface {
+	// Logic rule management
+	CreateLogic(ctx context.Context, logic *entities.SurveyLogic) error
+	GetLogic(ctx context.Context, id uuid.UUID) (*entities.SurveyLogic, error)
+	GetSurveyLogic(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error)
+	UpdateLogic(ctx context.Context, logic *entities.SurveyLogic) error
+	DeleteLogic(ctx context.Context, id uuid.UUID) error
+
+	// Evaluate computes the visible questions and the next question/page of a partial response
+	Evaluate(ctx context.Context, surveyID uuid.UUID, answers []entities.SurveyAnswer) (*entities.SurveyNavigation, error)
+	// ApplyLogic prepares the answers of a response for submission: answers to hidden questions are
+	// cleared and marked skipped, and visible answers are validated with required questions enforced.
+	// It returns a *SurveyResponseValidationError when an answer is invalid.
+	ApplyLogic(ctx context.Context, surveyID uuid.UUID, answers []entities.SurveyAnswer) ([]entities.SurveyAnswer, *entities.SurveyNavigation, error)
+}
+
+// SurveyPipingService defines the interface for piping answers between survey questions
+type SurveyPipingService interface {
+	// Piping rule management
+	CreatePiping(ctx context.Context, piping *entities.SurveyPiping) error
+	GetPiping(ctx context.Context, id uuid.UUID) (*entities.SurveyPiping, error)
+	GetSurveyPiping(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyPiping, error)
+	UpdatePiping(ctx context.Context, piping *entities.SurveyPiping) error
+	DeletePiping(ctx context.Context, id uuid.UUID) error
+
+	// PipeQuestions substitutes the answers given so far into the survey's question text and options
+	PipeQuestions(ctx context.Context, surveyID uuid.UUID, questions []entities.PipingQuestion, answers map[uuid.UUID][]string) ([]entities.PipingQuestion, error)
+}
+
+// SurveyRespondent identifies who is answering a survey
+type SurveyRespondent struct {
+	RespondentID *uuid.UUID // Logged in user, nil for anonymous respondents
+	SessionID    string     // Client session of anonymous respondents (X-Session-ID)
+	IPAddress    string
+	UserAgent    string
+
+	InvitationToken string     // Token of the invitation link the respondent followed, if any
+	CollectorID     *uuid.UUID // Collector whose link or QR code the respondent followed, if any
+}
+
+// SurveyProgress is the state of a respondent's survey session
+type SurveyProgress struct {
+	Session    *entities.SurveySession
+	Response   *entities.SurveyResponse
+	Answers    []entities.SurveyAnswer
+	Navigation *entities.SurveyNavigation
+	ExpiresAt  *time.Time // When the survey time limit runs out
+
+	ScreenOutMessage string // Set when a full quota screened the response out
+}
+
+// SurveyFileUpload is a file a respondent uploads for a file question
+type SurveyFileUpload struct {
+	Name        string
+	ContentType string
+	Size        int64 // In bytes
+	Content     io.Reader
+}
+
+// SurveyResponseService defines the interface for answering surveys
+type SurveyResponseService interface {
+	// StartResponse starts a response, or resumes the respondent's response in progress
+	StartResponse(ctx context.Context, surveyID uuid.UUID, respondent SurveyRespondent) (*SurveyProgress, error)
+	// GetProgress returns the state of a session so it can be resumed
+	GetProgress(ctx context.Context, sessionID string) (*SurveyProgress, error)
+	// SaveProgress stores partial answers and the current page of a session
+	SaveProgress(ctx context.Context, sessionID string, answers []entities.SurveyAnswer, currentPage int) (*SurveyProgress, error)
+	// SubmitResponse validates the complete set of answers and submits the response
+	SubmitResponse(ctx context.Context, sessionID string, answers []entities.SurveyAnswer) (*SurveyProgress, error)
+	// UploadFile stores a file for a file question of the session's survey. The returned file is
+	// then listed in the AnswerJSON of the question's answer.
+	UploadFile(ctx context.Context, sessionID string, questionID uuid.UUID, upload *SurveyFileUpload) (*entities.SurveyUploadedFile, error)
+}
+
+// SurveyFileService gives organizers the files respondents uploaded. The files are kept in private
+// storage, so they are only reachable through this service.
+type SurveyFileService interface {
+	// OpenFile opens an uploaded file by its storage key; the caller closes it
+	OpenFile(ctx context.Context, key string) (io.ReadCloser, error)
+	// DeleteAbandonedFiles deletes the files uploaded for responses that were abandoned, or whose
+	// session has been inactive too long to be resumed, and returns how many were deleted
+	DeleteAbandonedFiles(ctx context.Context) (int, error)
+}
+
+// SurveyQuotaService defines the interface for survey response quotas
+type SurveyQuotaService interface {
+	// Quota management
+	CreateQuota(ctx context.Context, quota *entities.SurveyQuota) error
+	GetQuota(ctx context.Context, id uuid.UUID) (*entities.SurveyQuota, error)
+	GetSurveyQuotas(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error)
+	UpdateQuota(ctx context.Context, quota *entities.SurveyQuota) error
+	DeleteQuota(ctx context.Context, id uuid.UUID) error
+
+	// ReserveQuotas counts a response against every active quota it matches. When one of them is full
+	// nothing is counted and a *SurveyQuotaFullError is returned.
+	ReserveQuotas(ctx context.Context, surveyID uuid.UUID, answers []entities.SurveyAnswer) ([]entities.SurveyQuota, error)
+	// ReleaseQuotas gives back quotas reserved for a response that was not stored
+	ReleaseQuotas(ctx context.Context, quotas []entities.SurveyQuota) error
+}
+
+// SurveyTemplateService defines the interface for survey templates. The built-in template library
+// is listed and used like stored templates, but cannot be changed.
+type SurveyTemplateService interface {
+	// Template management
+	CreateTemplate(ctx context.Context, template *entities.SurveyTemplate) error
+	GetTemplate(ctx context.Context, id uuid.UUID) (*entities.SurveyTemplate, error)
+	GetTemplates(ctx context.Context, category string) ([]entities.SurveyTemplate, error)
+	UpdateTemplate(ctx context.Context, template *entities.SurveyTemplate) error
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
+
+	// CreateSurvey creates a draft survey with the questions, settings and logic of a template
+	CreateSurvey(ctx context.Context, templateID uuid.UUID, title string, createdBy uuid.UUID) (*entities.Survey, error)
+	// SaveSurveyAsTemplate stores the questions, settings and logic of a survey as a new template.
+	// The name, description, category and tags given in template are kept.
+	SaveSurveyAsTemplate(ctx context.Context, surveyID uuid.UUID, template *entities.SurveyTemplate) error
+}
+
+// SurveyAnalyticsService defines the interface for survey result analytics
+type SurveyAnalyticsService interface {
+	// GetAnalytics returns the stored analytics of a survey, recalculating them when they are out of date
+	GetAnalytics(ctx context.Context, surveyID uuid.UUID) (*entities.SurveyAnalytics, error)
+	// RefreshAnalytics recalculates and stores the analytics of a survey
+	RefreshAnalytics(ctx context.Context, surveyID uuid.UUID) (*entities.SurveyAnalytics, error)
+	// GetCollectorAnalytics calculates the analytics of the responses collected by one collector
+	GetCollectorAnalytics(ctx context.Context, surveyID, collectorID uuid.UUID) (*entities.SurveyAnalytics, error)
+	// RefreshActiveSurveys recalculates the analytics of the surveys open for responses
+	RefreshActiveSurveys(ctx context.Context) (int, error)
+
+	// GetCrossTab cross-tabulates the answers of finished responses to two questions of a survey
+	GetCrossTab(ctx context.Context, surveyID, rowQuestionID, columnQuestionID uuid.UUID) (*entities.SurveyCrossTab, error)
+}
+
+// SurveyExportQueue hands survey exports to the background workers
+type SurveyExportQueue interface {
+	EnqueueSurveyExport(ctx context.Context, exportID uuid.UUID) error
+}
+
+// SurveyExportService defines the interface for survey result exports
+type SurveyExportService interface {
+	// RequestExport records a pending export and queues it for processing
+	RequestExport(ctx context.Context, export *entities.SurveyExport) error
+	GetExport(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, error)
+	GetSurveyExports(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyExport, error)
+
+	// ProcessExport streams the survey's responses into the export file and stores it
+	ProcessExport(ctx context.Context, id uuid.UUID) error
+	// OpenExportFile opens the file of a completed export that has not expired; the caller closes it
+	OpenExportFile(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, io.ReadCloser, error)
+	// DeleteExpiredExportFiles deletes the files of expired exports and returns how many were deleted
+	DeleteExpiredExportFiles(ctx context.Context) (int, error)
+}
+
+// SurveyInviteeSegment selects subscribed WeChat followers to invite
+type SurveyInviteeSegment struct {
+	Search           string // Nickname, real name or company
+	City             string
+	Province         string
+	Country          string
+	Sex              *int
+	SubscribedAfter  *time.Time
+	SubscribedBefore *time.Time
+}
+
+// SurveyInvitationRequest describes a batch of invitations to a survey. Invitees are taken from
+// the list, the segment or both.
+type SurveyInvitationRequest struct {
+	SurveyID  uuid.UUID
+	Channel   string // entities.SurveyInvitationChannelEmail or SurveyInvitationChannelWeChat
+	Invitees  []entities.SurveyInvitee
+	Segment   *SurveyInviteeSegment
+	Message   string     // Personal note included in the invitation
+	ExpiresAt *time.Time // When the links stop working; the service default when nil
+}
+
+// SurveyInvitationResult summarizes a batch of invitations
+type SurveyInvitationResult struct {
+	Invitations []entities.SurveyInvitation `json:"invitations"`
+	Sent        int                         `json:"sent"`
+	Failed      int                         `json:"failed"`  // Created, but delivery failed; retried with the reminders
+	Skipped     int                         `json:"skipped"` // Already invited, duplicated or without an address for the channel
+}
+
+// SurveyInvitationService defines the interface for tokenized survey invitations
+type SurveyInvitationService interface {
+	// CreateInvitations creates an invitation with a single-use link for every new invitee and delivers it
+	CreateInvitations(ctx context.Context, req *SurveyInvitationRequest) (*SurveyInvitationResult, error)
+	GetInvitations(ctx context.Context, surveyID uuid.UUID, status string) ([]entities.SurveyInvitation, error)
+
+	// OpenInvitation records the visit of an invitation link. It returns ErrSurveyInvitationUsed once
+	// the invitation was responded to and ErrSurveyInvitationExpired after its expiry.
+	OpenInvitation(ctx context.Context, token string) (*entities.SurveyInvitation, error)
+	// MarkResponded records the response submitted through an invitation
+	MarkResponded(ctx context.Context, invitationID, responseID uuid.UUID) error
+
+	// RemindSurvey reminds the non-responders of one survey whose reminder is due
+	RemindSurvey(ctx context.Context, surveyID uuid.UUID) (int, error)
+	// SendReminders expires overdue invitations and reminds the non-responders of all surveys
+	SendReminders(ctx context.Context) (int, error)
+}
+
+// EmailMessage is a plain text email to one recipient
+type EmailMessage struct {
+	To      string
+	ToName  string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers email
+type EmailSender interface {
+	SendEmail(ctx context.Context, message *EmailMessage) error
+}
+
+// SurveyNotificationEvent is a survey event notifications are dispatched for
+type SurveyNotificationEvent struct {
+	EventType string
+	Survey    *entities.Survey
+	Response  *entities.SurveyResponse // The submitted response of response_submitted and quota_reached events
+	Answers   []entities.SurveyAnswer  // Answers of the response
+	Quota     *entities.SurveyQuota    // The quota of quota_reached events
+}
+
+// SurveyNotificationChannel delivers survey notifications to the recipients of one channel
+type SurveyNotificationChannel interface {
+	Deliver(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error
+}
+
+// SurveyNotificationQueue hands survey notification deliveries to the background workers
+type SurveyNotificationQueue interface {
+	EnqueueSurveyNotificationDelivery(ctx context.Context, deliveryID uuid.UUID) error
+}
+
+// SurveyNotificationService defines the interface for survey notifications and their delivery
+type SurveyNotificationService interface {
+	// Notification management
+	CreateNotification(ctx context.Context, notification *entities.SurveyNotification) error
+	GetNotification(ctx context.Context, id uuid.UUID) (*entities.SurveyNotification, error)
+	GetSurveyNotifications(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyNotification, error)
+	UpdateNotification(ctx context.Context, notification *entities.SurveyNotification) error
+	DeleteNotification(ctx context.Context, id uuid.UUID) error
+	// GetDeliveries lists the latest deliveries of a notification
+	GetDeliveries(ctx context.Context, notificationID uuid.UUID) ([]entities.SurveyNotificationDelivery, error)
+
+	// Dispatch renders the survey's active notifications of the event that match the response and
+	// logs a delivery for each of their recipients, then hands the deliveries to their channels
+	Dispatch(ctx context.Context, event *SurveyNotificationEvent) error
+	// ProcessDelivery attempts a pending delivery. Failed attempts are recorded on the delivery and retried later.
+	ProcessDelivery(ctx context.Context, deliveryID uuid.UUID) error
+	// RetryDeliveries attempts the pending deliveries whose retry is due
+	RetryDeliveries(ctx context.Context) (int, error)
+	// NotifyClosedSurveys dispatches the survey_closed notifications of surveys that were closed or ended
+	NotifyClosedSurveys(ctx context.Context) (int, error)
+}
+
+// SurveyCollectorQRCodeGenerator creates WeChat QR codes that open survey collectors
+type SurveyCollectorQRCodeGenerator interface {
+	GenerateSurveyCollectorQRCode(ctx context.Context, collector *entities.SurveyCollector, qrType entities.QRCodeType) (*entities.WeChatQrCode, error)
+}
+
+// SurveyCollectorService defines the interface for survey collectors and response attribution
+type SurveyCollectorService interface {
+	// Collector management
+	CreateCollector(ctx context.Context, collector *entities.SurveyCollector) error
+	GetCollector(ctx context.Context, id uuid.UUID) (*entities.SurveyCollector, error)
+	GetSurveyCollectors(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyCollector, error)
+	UpdateCollector(ctx context.Context, collector *entities.SurveyCollector) error
+	DeleteCollector(ctx context.Context, id uuid.UUID) error
+	// SetCollectorActive opens or closes a collector for new responses
+	SetCollectorActive(ctx context.Context, id uuid.UUID, active bool) (*entities.SurveyCollector, error)
+	// GenerateQRCode creates a WeChat QR code for the collector link and stores its URL on the collector
+	GenerateQRCode(ctx context.Context, id uuid.UUID) (*entities.WeChatQrCode, error)
+
+	// OpenCollector finds a collector of the survey that accepts responses. It returns
+	// ErrSurveyCollectorClosed when the collector is closed, outside its schedule or full.
+	OpenCollector(ctx context.Context, surveyID, collectorID uuid.UUID) (*entities.SurveyCollector, error)
+	// ReserveResponse counts a submitted response against its collector, returning
+	// ErrSurveyCollectorClosed when the collector stopped accepting responses
+	ReserveResponse(ctx context.Context, collectorID uuid.UUID) error
+	// ReleaseResponse gives back a response counted by ReserveResponse
+	ReleaseResponse(ctx context.Context, collectorID uuid.UUID) error
+}
+
+// SurveyRevision is the new content of a survey
+type SurveyRevision struct {
+	Title        string
+	Description  string
+	Instructions string
+	// Questions is the complete question list in display order. Questions with the ID of a current
+	// question update it, questions without an ID are added and missing questions are removed.
+	Questions  []entities.SurveyQuestion
+	ChangeNote string
+	EditedBy   *uuid.UUID
+}
+
+// SurveyVersionService defines the interface for survey revisions. Drafts are edited in place;
+// revising a published or closed survey records a new version so collected responses keep the
+// content they were answered against.
+type SurveyVersionService interface {
+	// ReviseSurvey applies a revision and returns the revised survey
+	ReviseSurvey(ctx context.Context, surveyID uuid.UUID, revision *SurveyRevision) (*entities.Survey, error)
+	// GetVersions returns the versions of a survey, oldest first
+	GetVersions(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyVersion, error)
+	GetVersion(ctx context.Context, surveyID uuid.UUID, version int) (*entities.SurveyVersion, error)
+	// DiffVersions lists the changes between two versions of a survey
+	DiffVersions(ctx context.Context, surveyID uuid.UUID, from, to int) (*entities.SurveyVersionDiff, error)
+}
+
+// SurveyLivePollRequest opens a question of a survey as a live poll at an event
+type SurveyLivePollRequest struct {
+	EventID    uuid.UUID
+	QuestionID uuid.UUID
+	OpenedBy   *uuid.UUID
+}
+
+// SurveyLivePollService defines the interface for live polls, which put single questions of a
+// published survey to the audience of an event in real time. Attendees join with the interaction
+// code of the event.
+type SurveyLivePollService interface {
+	// OpenPoll opens a question at an event, closing the poll that was open there before
+	OpenPoll(ctx context.Context, surveyID uuid.UUID, request *SurveyLivePollRequest) (*entities.SurveyLivePoll, error)
+	// ClosePoll stops the voting and stores the final tally
+	ClosePoll(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePoll, error)
+	// GetSurveyPolls lists the polls of a survey, latest first
+	GetSurveyPolls(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLivePoll, error)
+	GetResults(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePollResults, error)
+
+	// GetCurrentPoll returns the open poll of the event with the interaction code, with its
+	// question. It returns ErrNoLivePoll when no poll is open.
+	GetCurrentPoll(ctx context.Context, code string) (*entities.SurveyLivePoll, error)
+	// SubmitVote counts a vote on the open poll of the event. It returns ErrLivePollClosed when the
+	// vote is for another poll and ErrLivePollAlreadyVoted when the voter voted before.
+	SubmitVote(ctx context.Context, code string, vote *entities.SurveyLivePollVote) error
+	// GetCurrentResults returns the live results of the poll opened last at the event, for the
+	// big screen. It returns ErrNoLivePoll before the first poll is opened.
+	GetCurrentResults(ctx context.Context, code string) (*entities.SurveyLivePollResults, error)
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChatFollowerSyncQueue hands WeChat follower syncs to the background workers
+type WeChatFollowerSyncQueue interface {
+	EnqueueWeChatFollowerSync(ctx context.Context, syncID uuid.UUID) error
+}
+
+// WeChatFollowerSyncService reconciles the WeChat followers with the WeChatUser records
+type WeChatFollowerSyncService interface {
+	// StartSync queues the running sync, or a new sync when none is running
+	StartSync(ctx context.Context) (*entities.WeChatFollowerSync, error)
+	// RunSync syncs the followers from the checkpoint of a sync to the end of the follower list
+	RunSync(ctx context.Context, id uuid.UUID) error
+	GetSync(ctx context.Context, id uuid.UUID) (*entities.WeChatFollowerSync, error)
+	GetRecentSyncs(ctx context.Context, limit int) ([]entities.WeChatFollowerSync, error)
+}
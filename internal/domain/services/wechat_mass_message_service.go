@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChat mass message errors
+var (
+	ErrWeChatMassMessageSent    = errors.New("WeChat mass message was already sent")
+	ErrWeChatMassMessageNotSent = errors.New("WeChat mass message was not sent yet")
+	ErrNoWeChatTestUsers        = errors.New("no subscribed WeChat test users to preview mass messages")
+)
+
+// WeChatMassMessageService broadcasts mass messages to WeChat user tags or lists of followers,
+// tracking the outcome WeChat reports
+type WeChatMassMessageService interface {
+	CreateMessage(ctx context.Context, message *entities.WeChatMassMessage) error
+	GetMessage(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error)
+	GetRecentMessages(ctx context.Context, limit int) ([]entities.WeChatMassMessage, error)
+
+	// Preview sends a message to the subscribed test users and returns how many received it
+	Preview(ctx context.Context, id uuid.UUID) (int, error)
+	// Send broadcasts a draft or failed message
+	Send(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error)
+	// RefreshStatus polls WeChat for the status of a sent message
+	RefreshStatus(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error)
+}
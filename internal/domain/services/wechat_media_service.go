@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChat media errors
+var (
+	ErrWeChatMediaWithoutURL = errors.New("only temporary WeChat videos are linked by URL")
+	ErrWeChatMediaIsVideo    = errors.New("temporary WeChat videos are linked by URL instead of downloaded")
+)
+
+// WeChatMediaStore keeps media on WeChat
+type WeChatMediaStore interface {
+	UploadMediaFromBytes(ctx context.Context, mediaType string, filename string, data []byte) (*WeChatMediaInfo, error)
+	UploadPermanentMediaFromBytes(ctx context.Context, mediaType string, filename string, data []byte) (*WeChatPermanentMediaInfo, error)
+	GetMedia(ctx context.Context, mediaID string) ([]byte, error)
+	GetPermanentMediaList(ctx context.Context, mediaType string, offset, count int) (*WeChatMediaList, error)
+	DeletePermanentMedia(ctx context.Context, mediaID string) error
+}
+
+// WeChatMediaUpload is a file to upload to WeChat as media
+type WeChatMediaUpload struct {
+	MediaType   string
+	Permanent   bool
+	FileName    string
+	Data        []byte
+	SiteImageID *uuid.UUID // Maps the media to the site image the file belongs to
+}
+
+// WeChatMediaService uploads media to WeChat once per content, reusing the media uploaded before
+// while WeChat keeps it
+type WeChatMediaService interface {
+	// Upload uploads a file unless media of the same type and content is still available on WeChat
+	Upload(ctx context.Context, upload *WeChatMediaUpload) (*entities.WeChatMedia, error)
+	// UploadSiteImage uploads a site image as a permanent image unless it was uploaded before
+	UploadSiteImage(ctx context.Context, siteImageID uuid.UUID) (*entities.WeChatMedia, error)
+	// GetMedia downloads temporary media
+	GetMedia(ctx context.Context, mediaID string) ([]byte, error)
+	GetPermanentMediaList(ctx context.Context, mediaType string, offset, count int) (*WeChatMediaList, error)
+	// DeletePermanentMedia deletes permanent media from WeChat along with its records
+	DeletePermanentMedia(ctx context.Context, mediaID string) error
+}
@@ -2,8 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
 	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChat menu errors
+var (
+	ErrWeChatMenuPublished    = errors.New("published WeChat menu versions cannot be deleted")
+	ErrNoDefaultWeChatMenu    = errors.New("conditional WeChat menus need a published default menu")
+	ErrNoWeChatMenuToRollback = errors.New("no earlier WeChat menu version to roll back to")
 )
 
 // WeChatService defines the interface for WeChat integration operations
@@ -18,15 +28,35 @@ type WeChatService interface {
 	// User Management
 	GetUserInfo(ctx context.Context, openID string) (*WeChatUserInfo, error)
 	GetUserList(ctx context.Context) (*WeChatUserList, error)
+	GetFollowers(ctx context.Context, nextOpenID string) (*WeChatUserList, error)
+	BatchGetUserInfo(ctx context.Context, openIDs []string) ([]WeChatUserInfo, error)
 
 	// Message Sending
 	SendTextMessage(ctx context.Context, openID, content string) error
 	SendTemplateMessage(ctx context.Context, openID string, templateMsg *TemplateMessage) error
+	SendTrackedTemplateMessage(ctx context.Context, openID string, templateMsg *TemplateMessage) (int64, error)
 
 	// Menu Management
 	CreateMenu(ctx context.Context, menu *Menu) error
 	GetMenu(ctx context.Context) (*Menu, error)
 	DeleteMenu(ctx context.Context) error
+	AddConditionalMenu(ctx context.Context, menu *Menu) (string, error)
+	DeleteConditionalMenu(ctx context.Context, menuID string) error
+
+	// Tag Management
+	CreateTag(ctx context.Context, name string) (*WeChatTag, error)
+	GetTags(ctx context.Context) ([]WeChatTag, error)
+	UpdateTag(ctx context.Context, tagID int, name string) error
+	DeleteTag(ctx context.Context, tagID int) error
+	BatchTagUsers(ctx context.Context, tagID int, openIDs []string) error
+	BatchUntagUsers(ctx context.Context, tagID int, openIDs []string) error
+	GetTagFollowers(ctx context.Context, tagID int, nextOpenID string) (*WeChatUserList, error)
+
+	// Mass Messaging
+	SendMassByTag(ctx context.Context, tagID int, msg *MassMessage) (*MassSendResult, error)
+	SendMassByOpenIDs(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResult, error)
+	PreviewMass(ctx context.Context, openID string, msg *MassMessage) error
+	GetMassStatus(ctx context.Context, msgID int64) (string, error)
 
 	// QR Code Management
 	CreateQRCode(ctx context.Context, sceneStr string, expireSeconds int) (*WeChatQRCodeInfo, error)
@@ -38,6 +68,7 @@ type WeChatService interface {
 	GetMedia(ctx context.Context, mediaID string) ([]byte, error)
 	GetMediaURL(ctx context.Context, mediaID string) (string, error)
 	UploadPermanentMedia(ctx context.Context, mediaType string, filePath string) (*WeChatPermanentMediaInfo, error)
+	UploadPermanentMediaFromBytes(ctx context.Context, mediaType string, filename string, data []byte) (*WeChatPermanentMediaInfo, error)
 	GetPermanentMediaList(ctx context.Context, mediaType string, offset, count int) (*WeChatMediaList, error)
 	DeletePermanentMedia(ctx context.Context, mediaID string) error
 
@@ -50,23 +81,45 @@ type WeChatService interface {
 
 // WeChatUserInfo represents WeChat user information
 type WeChatUserInfo struct {
-	OpenID     string `json:"openid"`
-	UnionID    string `json:"unionid,omitempty"`
-	Nickname   string `json:"nickname"`
-	Sex        int    `json:"sex"`
-	City       string `json:"city"`
-	Country    string `json:"country"`
-	Province   string `json:"province"`
-	Language   string `json:"language"`
-	HeadImgURL string `json:"headimgurl"`
-	Subscribe  int    `json:"subscribe"`
+	OpenID        string `json:"openid"`
+	UnionID       string `json:"unionid,omitempty"`
+	Nickname      string `json:"nickname"`
+	Sex           int    `json:"sex"`
+	City          string `json:"city"`
+	Country       string `json:"country"`
+	Province      string `json:"province"`
+	Language      string `json:"language"`
+	HeadImgURL    string `json:"headimgurl"`
+	Subscribe     int    `json:"subscribe"`
+	SubscribeTime int64  `json:"subscribe_time,omitempty"` // Unix time of the last follow
+	Remark        string `json:"remark,omitempty"`
+	TagIDs        []int  `json:"tagid_list,omitempty"`
 }
 
-// WeChatUserList represents a list of WeChat users
+// WeChatUserList represents a page of the WeChat followers
 type WeChatUserList struct {
-	Total int      `json:"total"`
-	Count int      `json:"count"`
-	Data  []string `json:"data"`
+	Total      int      `json:"total"`
+	Count      int      `json:"count"`
+	Data       []string `json:"data"`
+	NextOpenID string   `json:"next_openid"` // Fetches the next page, empty after the last one
+}
+
+// WeChatFollowerSource pages through the WeChat followers and their profiles
+type WeChatFollowerSource interface {
+	GetFollowers(ctx context.Context, nextOpenID string) (*WeChatUserList, error)
+	BatchGetUserInfo(ctx context.Context, openIDs []string) ([]WeChatUserInfo, error)
+}
+
+// TemplateMessageSender sends WeChat template messages to followers
+type TemplateMessageSender interface {
+	SendTemplateMessage(ctx context.Context, openID string, templateMsg *TemplateMessage) error
+}
+
+// WeChatTemplateCampaignSender sends the template messages of campaigns, keeping the msgid WeChat
+// reports each delivery with, and pages through the followers carrying a tag
+type WeChatTemplateCampaignSender interface {
+	SendTrackedTemplateMessage(ctx context.Context, openID string, templateMsg *TemplateMessage) (int64, error)
+	GetTagFollowers(ctx context.Context, tagID int, nextOpenID string) (*WeChatUserList, error)
 }
 
 // TemplateMessage represents a WeChat template message
@@ -78,7 +131,9 @@ type TemplateMessage struct {
 
 // Menu represents WeChat menu structure
 type Menu struct {
-	Buttons []MenuButton `json:"button"`
+	Buttons   []MenuButton   `json:"button"`
+	MatchRule *MenuMatchRule `json:"matchrule,omitempty"`
+	MenuID    string         `json:"menuid,omitempty"`
 }
 
 // MenuButton represents a menu button
@@ -87,9 +142,88 @@ type MenuButton struct {
 	Name      string       `json:"name"`
 	Key       string       `json:"key,omitempty"`
 	URL       string       `json:"url,omitempty"`
+	AppID     string       `json:"appid,omitempty"`
+	PagePath  string       `json:"pagepath,omitempty"`
+	MediaID   string       `json:"media_id,omitempty"`
+	ArticleID string       `json:"article_id,omitempty"`
 	SubButton []MenuButton `json:"sub_button,omitempty"`
 }
 
+// MenuMatchRule selects the followers who see a conditional menu
+type MenuMatchRule struct {
+	TagID              string `json:"tag_id,omitempty"`
+	Sex                string `json:"sex,omitempty"`
+	Country            string `json:"country,omitempty"`
+	Province           string `json:"province,omitempty"`
+	City               string `json:"city,omitempty"`
+	ClientPlatformType string `json:"client_platform_type,omitempty"`
+	Language           string `json:"language,omitempty"`
+}
+
+// WeChatMenuPublisher pushes custom menus to the WeChat official account
+type WeChatMenuPublisher interface {
+	CreateMenu(ctx context.Context, menu *Menu) error
+	DeleteMenu(ctx context.Context) error
+	AddConditionalMenu(ctx context.Context, menu *Menu) (string, error)
+	DeleteConditionalMenu(ctx context.Context, menuID string) error
+}
+
+// WeChatMenuService manages versioned WeChat custom menus. Menus are identified by their user tag,
+// with an empty tag for the default menu, and each save records a new draft version of the menu.
+type WeChatMenuService interface {
+	SaveMenu(ctx context.Context, menu *entities.WeChatMenu) error
+	GetMenu(ctx context.Context, id uuid.UUID) (*entities.WeChatMenu, error)
+	GetMenuVersions(ctx context.Context, tagID string) ([]entities.WeChatMenu, error)
+	GetPublishedMenus(ctx context.Context) ([]entities.WeChatMenu, error)
+	DeleteMenu(ctx context.Context, id uuid.UUID) error
+
+	// PublishMenu pushes a version to WeChat, replacing the version of the same menu published before
+	PublishMenu(ctx context.Context, id uuid.UUID, publishedBy *uuid.UUID) (*entities.WeChatMenu, error)
+	// RollbackMenu publishes again the version of a menu published before the current one
+	RollbackMenu(ctx context.Context, tagID string, publishedBy *uuid.UUID) (*entities.WeChatMenu, error)
+	// UnpublishMenu removes a menu from WeChat; removing the default menu removes every menu
+	UnpublishMenu(ctx context.Context, tagID string) error
+}
+
+// WeChatTag represents a WeChat user tag
+type WeChatTag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// WeChatTagManager manages the WeChat user tags and the followers carrying them
+type WeChatTagManager interface {
+	CreateTag(ctx context.Context, name string) (*WeChatTag, error)
+	GetTags(ctx context.Context) ([]WeChatTag, error)
+	UpdateTag(ctx context.Context, tagID int, name string) error
+	DeleteTag(ctx context.Context, tagID int) error
+	BatchTagUsers(ctx context.Context, tagID int, openIDs []string) error
+	BatchUntagUsers(ctx context.Context, tagID int, openIDs []string) error
+}
+
+// MassMessage represents the content of a WeChat mass message
+type MassMessage struct {
+	MsgType     string `json:"msgtype"`
+	MediaID     string `json:"media_id,omitempty"`
+	Content     string `json:"content,omitempty"`
+	ClientMsgID string `json:"clientmsgid,omitempty"` // WeChat sends a message once per client ID
+}
+
+// MassSendResult identifies a mass message WeChat accepted for sending
+type MassSendResult struct {
+	MsgID     int64 `json:"msg_id"`
+	MsgDataID int64 `json:"msg_data_id,omitempty"`
+}
+
+// WeChatMassSender broadcasts WeChat mass messages
+type WeChatMassSender interface {
+	SendMassByTag(ctx context.Context, tagID int, msg *MassMessage) (*MassSendResult, error)
+	SendMassByOpenIDs(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResult, error)
+	PreviewMass(ctx context.Context, openID string, msg *MassMessage) error
+	GetMassStatus(ctx context.Context, msgID int64) (string, error)
+}
+
 // WeChatQRCodeInfo represents WeChat QR code information
 type WeChatQRCodeInfo struct {
 	Ticket        string `json:"ticket"`
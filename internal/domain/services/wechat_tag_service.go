@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidWeChatTagName is returned for tag names WeChat would reject
+var ErrInvalidWeChatTagName = errors.New("WeChat tag names are 1 to 30 characters")
+
+// WeChatEventTagging is the outcome of tagging the followers who checked in to an event
+type WeChatEventTagging struct {
+	EventID uuid.UUID `json:"eventId"`
+	TagID   int       `json:"tagId"`
+	TagName string    `json:"tagName"`
+	Tagged  int       `json:"tagged"` // Followers tagged
+}
+
+// WeChatTagService manages the WeChat user tags used to segment followers for mass messages and
+// conditional menus
+type WeChatTagService interface {
+	GetTags(ctx context.Context) ([]WeChatTag, error)
+	CreateTag(ctx context.Context, name string) (*WeChatTag, error)
+	UpdateTag(ctx context.Context, tagID int, name string) error
+	DeleteTag(ctx context.Context, tagID int) error
+
+	// TagUsers tags followers, any number of them
+	TagUsers(ctx context.Context, tagID int, openIDs []string) error
+	// UntagUsers removes a tag from followers, any number of them
+	UntagUsers(ctx context.Context, tagID int, openIDs []string) error
+	// TagEventAttendees tags the subscribed followers who checked in to an event with the event
+	// tag, creating the tag first when the event has none
+	TagEventAttendees(ctx context.Context, eventID uuid.UUID) (*WeChatEventTagging, error)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// WeChat template campaign errors
+var (
+	ErrWeChatTemplateCampaignStarted  = errors.New("WeChat template campaign was already started")
+	ErrWeChatTemplateCampaignFinished = errors.New("WeChat template campaign has finished")
+)
+
+// WeChatTemplateCampaignQueue hands WeChat template campaign runs to the background workers
+type WeChatTemplateCampaignQueue interface {
+	// EnqueueWeChatTemplateCampaign queues the next run of a campaign, after delay when positive
+	EnqueueWeChatTemplateCampaign(ctx context.Context, campaignID uuid.UUID, delay time.Duration) error
+}
+
+// WeChatTemplateCampaignService sends template messages to event attendees, survey non-responders
+// or tagged followers, rendered for each recipient and throttled to the WeChat quotas, and tracks
+// the delivery WeChat reports for each of them
+type WeChatTemplateCampaignService interface {
+	CreateCampaign(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error
+	// GetCampaign retrieves a campaign with its deliveries counted by status
+	GetCampaign(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error)
+	GetRecentCampaigns(ctx context.Context, limit int) ([]entities.WeChatTemplateCampaign, error)
+	// GetDeliveries lists the deliveries of a campaign with a status, or all of them for ""
+	GetDeliveries(ctx context.Context, campaignID uuid.UUID, status string, offset, limit int) ([]entities.WeChatTemplateDelivery, int64, error)
+
+	// StartCampaign starts sending a draft campaign
+	StartCampaign(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error)
+	// CancelCampaign stops a campaign, cancelling the deliveries not sent yet
+	CancelCampaign(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error)
+	// RunCampaign resolves the recipients of a started campaign on its first run, then sends a
+	// batch of its pending deliveries and queues the next run
+	RunCampaign(ctx context.Context, id uuid.UUID) error
+	// ResumeStalledCampaigns queues a run of the sending campaigns whose runs stopped being
+	// handled, and returns how many were queued
+	ResumeStalledCampaigns(ctx context.Context) (int, error)
+}
@@ -0,0 +1,128 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/config"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// dialTimeout bounds connecting to the SMTP server when the context has no deadline
+const dialTimeout = 10 * time.Second
+
+// SMTPSender delivers email through an SMTP server. STARTTLS is used when the server offers it and
+// credentials are only sent when a username is configured, so a local mail catcher works without setup.
+type SMTPSender struct {
+	config config.EmailConfig
+	logger *zap.Logger
+}
+
+// NewSMTPSender creates a new SMTP email sender
+func NewSMTPSender(cfg config.EmailConfig, logger *zap.Logger) services.EmailSender {
+	return &SMTPSender{
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// SendEmail sends a plain text email
+func (s *SMTPSender) SendEmail(ctx context.Context, message *services.EmailMessage) error {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", message.To, err)
+	}
+	data, err := s.buildMessage(message, to.Address)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.config.From); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected recipient: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := client.Quit(); err != nil {
+		s.logger.Warn("SMTP server did not close the session cleanly", zap.Error(err))
+	}
+
+	s.logger.Debug("Email sent", zap.String("to", to.Address), zap.String("subject", message.Subject))
+	return nil
+}
+
+// buildMessage encodes the email with UTF-8 headers and a quoted-printable body
+func (s *SMTPSender) buildMessage(message *services.EmailMessage, to string) ([]byte, error) {
+	from := mail.Address{Name: s.config.FromName, Address: s.config.From}
+	recipient := mail.Address{Name: message.ToName, Address: to}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), s.config.Host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(message.Body)); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if err := body.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/zenteam/nextevent-go/internal/config"
+	"github.com/zenteam/nextevent-go/internal/jobs"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -14,6 +15,9 @@ type Infrastructure struct {
 	DB          *gorm.DB
 	RedisClient *redis.Client
 	Logger      *zap.Logger
+
+	// JobScheduler queues background jobs for the worker, shared by every service; nil without Redis
+	JobScheduler *jobs.AsynqScheduler
 }
 
 func Initialize(cfg *config.Config) (*Infrastructure, error) {
@@ -42,6 +46,9 @@ func Initialize(cfg *config.Config) (*Infrastructure, error) {
 		RedisClient: redisClient,
 		Logger:      zapLogger,
 	}
+	if redisClient != nil {
+		infra.JobScheduler = jobs.NewAsynqScheduler(redisClient, zapLogger)
+	}
 
 	// Skip auto-migration for now - use existing database schema
 	// if err := infra.autoMigrate(); err != nil {
@@ -53,6 +60,10 @@ func Initialize(cfg *config.Config) (*Infrastructure, error) {
 }
 
 func (i *Infrastructure) Close() {
+	if i.JobScheduler != nil {
+		i.JobScheduler.Close()
+	}
+
 	if i.RedisClient != nil {
 		i.RedisClient.Close()
 	}
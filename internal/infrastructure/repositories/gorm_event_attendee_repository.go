@@ -7,6 +7,7 @@ import (
 	"github.com/zenteam/nextevent-go/internal/domain/entities"
 	"github.com/zenteam/nextevent-go/internal/domain/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormEventAttendeeRepository implements EventAttendeeRepository using GORM
@@ -24,6 +25,39 @@ func (r *GormEventAttendeeRepository) Create(ctx context.Context, attendee *enti
 	return r.db.WithContext(ctx).Create(attendee).Error
 }
 
+// CreateBatchWithCapacity creates several event attendees in one transaction, registering them
+// while places are free. The event row is locked like in CreateWithCapacity.
+func (r *GormEventAttendeeRepository) CreateBatchWithCapacity(ctx context.Context, attendees []*entities.EventAttendee, capacity int) error {
+	if len(attendees) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockEvent(tx, attendees[0].EventID); err != nil {
+			return err
+		}
+
+		var registered int64
+		if capacity > 0 {
+			var err error
+			if registered, err = countRegistered(tx, attendees[0].EventID); err != nil {
+				return err
+			}
+		}
+		for _, attendee := range attendees {
+			attendee.Status = entities.AttendeeStatusRegistered
+			if capacity > 0 {
+				if registered >= int64(capacity) {
+					attendee.Status = entities.AttendeeStatusWaitlisted
+				} else {
+					registered++
+				}
+			}
+		}
+
+		return tx.CreateInBatches(attendees, 200).Error
+	})
+}
+
 // GetByID retrieves an event attendee by ID
 func (r *GormEventAttendeeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.EventAttendee, error) {
 	var attendee entities.EventAttendee
@@ -51,6 +85,18 @@ func (r *GormEventAttendeeRepository) GetByEventAndUser(ctx context.Context, eve
 	return &attendee, nil
 }
 
+// GetByEventAndMobile retrieves an attendee by event and mobile number
+func (r *GormEventAttendeeRepository) GetByEventAndMobile(ctx context.Context, eventID uuid.UUID, mobile string) (*entities.EventAttendee, error) {
+	var attendee entities.EventAttendee
+	err := r.db.WithContext(ctx).
+		Where("EventId = ? AND Mobile = ? AND IsDeleted = ?", eventID, mobile, false).
+		First(&attendee).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendee, nil
+}
+
 // GetByEvent retrieves all attendees for an event with pagination
 func (r *GormEventAttendeeRepository) GetByEvent(ctx context.Context, eventID uuid.UUID, offset, limit int) ([]*entities.EventAttendee, error) {
 	var attendees []*entities.EventAttendee
@@ -65,6 +111,17 @@ func (r *GormEventAttendeeRepository) GetByEvent(ctx context.Context, eventID uu
 	return attendees, err
 }
 
+// GetActivityByEvent retrieves the registration and check-in fields of every attendee of an event
+func (r *GormEventAttendeeRepository) GetActivityByEvent(ctx context.Context, eventID uuid.UUID) ([]*entities.EventAttendee, error) {
+	var attendees []*entities.EventAttendee
+	err := r.db.WithContext(ctx).
+		Select("Id", "EventId", "Status", "Source", "OnSiteScanned", "CheckInDate", "CheckInMethod", "CreationTime").
+		Where("EventId = ? AND IsDeleted = ?", eventID, false).
+		Order("CreationTime ASC").
+		Find(&attendees).Error
+	return attendees, err
+}
+
 // GetByUser retrieves all attendees for a user with pagination
 func (r *GormEventAttendeeRepository) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entities.EventAttendee, error) {
 	var attendees []*entities.EventAttendee
@@ -118,7 +175,7 @@ func (r *GormEventAttendeeRepository) CountCheckedInByEvent(ctx context.Context,
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&entities.EventAttendee{}).
-		Where("EventId = ? AND IsCheckedIn = ?", eventID, true).
+		Where("EventId = ? AND OnSiteScanned = ?", eventID, true).
 		Count(&count).Error
 	return count, err
 }
@@ -129,7 +186,7 @@ func (r *GormEventAttendeeRepository) GetCheckedInAttendees(ctx context.Context,
 	err := r.db.WithContext(ctx).
 		Preload("Event").
 		Preload("User").
-		Where("EventId = ? AND IsCheckedIn = ?", eventID, true).
+		Where("EventId = ? AND OnSiteScanned = ?", eventID, true).
 		Offset(offset).
 		Limit(limit).
 		Order("CheckInDate DESC").
@@ -150,3 +207,113 @@ func (r *GormEventAttendeeRepository) GetAttendeesByStatus(ctx context.Context,
 		Find(&attendees).Error
 	return attendees, err
 }
+
+// CountRegisteredByEvent returns the number of attendees holding a confirmed place
+func (r *GormEventAttendeeRepository) CountRegisteredByEvent(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	return countRegistered(r.db.WithContext(ctx), eventID)
+}
+
+// CountByEventAndStatus returns the number of attendees with the given status
+func (r *GormEventAttendeeRepository) CountByEventAndStatus(ctx context.Context, eventID uuid.UUID, status string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.EventAttendee{}).
+		Where("EventId = ? AND Status = ?", eventID, status).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateWithCapacity creates the attendee as registered or waitlisted depending on the free places.
+// The event row is locked so concurrent registrations cannot overbook or register a user twice.
+func (r *GormEventAttendeeRepository) CreateWithCapacity(ctx context.Context, attendee *entities.EventAttendee, capacity int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockEvent(tx, attendee.EventID); err != nil {
+			return err
+		}
+
+		if attendee.UserID != nil {
+			var active int64
+			err := tx.Model(&entities.EventAttendee{}).
+				Where("EventId = ? AND UserId = ?", attendee.EventID, *attendee.UserID).
+				Where("Status IS NULL OR Status <> ?", entities.AttendeeStatusCancelled).
+				Count(&active).Error
+			if err != nil {
+				return err
+			}
+			if active > 0 {
+				return repositories.ErrAttendeeExists
+			}
+		}
+
+		attendee.Status = entities.AttendeeStatusRegistered
+		if capacity > 0 {
+			registered, err := countRegistered(tx, attendee.EventID)
+			if err != nil {
+				return err
+			}
+			if registered >= int64(capacity) {
+				attendee.Status = entities.AttendeeStatusWaitlisted
+			}
+		}
+
+		return tx.Save(attendee).Error
+	})
+}
+
+// PromoteWaitlisted moves the longest-waiting attendees off the waitlist while places are free
+func (r *GormEventAttendeeRepository) PromoteWaitlisted(ctx context.Context, eventID uuid.UUID, capacity int) ([]*entities.EventAttendee, error) {
+	var promoted []*entities.EventAttendee
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockEvent(tx, eventID); err != nil {
+			return err
+		}
+
+		// Waitlisted rows are only modified when they join the waitlist, so this orders them by waiting time
+		query := tx.Where("EventId = ? AND Status = ?", eventID, entities.AttendeeStatusWaitlisted).
+			Order("LastModificationTime ASC, CreationTime ASC")
+		if capacity > 0 {
+			registered, err := countRegistered(tx, eventID)
+			if err != nil {
+				return err
+			}
+			free := int64(capacity) - registered
+			if free <= 0 {
+				return nil
+			}
+			query = query.Limit(int(free))
+		}
+
+		if err := query.Find(&promoted).Error; err != nil {
+			return err
+		}
+		for _, attendee := range promoted {
+			attendee.Status = entities.AttendeeStatusRegistered
+			if err := tx.Model(attendee).Update("Status", attendee.Status).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// countRegistered counts attendees holding a confirmed place; legacy rows without a status count as registered
+func countRegistered(db *gorm.DB, eventID uuid.UUID) (int64, error) {
+	var count int64
+	err := db.Model(&entities.EventAttendee{}).
+		Where("EventId = ?", eventID).
+		Where("Status IS NULL OR Status NOT IN ?", []string{entities.AttendeeStatusWaitlisted, entities.AttendeeStatusCancelled}).
+		Count(&count).Error
+	return count, err
+}
+
+// lockEvent takes a row lock on the event to serialize capacity checks
+func lockEvent(tx *gorm.DB, eventID uuid.UUID) error {
+	var event entities.SiteEvent
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("Id").
+		First(&event, "Id = ?", eventID).Error
+}
@@ -47,9 +47,24 @@ func (r *GormSurveyTemplateRepository) FindByID(ctx context.Context, id uuid.UUI
 	return &template, nil
 }
 
-// Update updates an existing survey template
+// Update updates an existing survey template and replaces its questions
 func (r *GormSurveyTemplateRepository) Update(ctx context.Context, template *entities.SurveyTemplate) error {
-	if err := r.db.WithContext(ctx).Save(template).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Questions").Save(template).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&entities.SurveyTemplateQuestion{}).Error; err != nil {
+			return err
+		}
+		if len(template.Questions) == 0 {
+			return nil
+		}
+		for i := range template.Questions {
+			template.Questions[i].TemplateID = template.ID
+		}
+		return tx.Create(&template.Questions).Error
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update survey template: %w", err)
 	}
 	return nil
@@ -363,3 +378,909 @@ func (r *GormSurveyShareRepository) FindActive(ctx context.Context, surveyID uui
 	
 	return shares, nil
 }
+
+// GormSurveyLogicRepository implements SurveyLogicRepository using GORM
+type GormSurveyLogicRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyLogicRepository creates a new GORM survey logic repository
+func NewGormSurveyLogicRepository(db *gorm.DB) repositories.SurveyLogicRepository {
+	return &GormSurveyLogicRepository{db: db}
+}
+
+// Create creates a new survey logic rule
+func (r *GormSurveyLogicRepository) Create(ctx context.Context, logic *entities.SurveyLogic) error {
+	if err := r.db.WithContext(ctx).Create(logic).Error; err != nil {
+		return fmt.Errorf("failed to create survey logic: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey logic rule by ID
+func (r *GormSurveyLogicRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyLogic, error) {
+	var logic entities.SurveyLogic
+	err := r.db.WithContext(ctx).First(&logic, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey logic: %w", err)
+	}
+	return &logic, nil
+}
+
+// Update updates an existing survey logic rule
+func (r *GormSurveyLogicRepository) Update(ctx context.Context, logic *entities.SurveyLogic) error {
+	if err := r.db.WithContext(ctx).Save(logic).Error; err != nil {
+		return fmt.Errorf("failed to update survey logic: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a survey logic rule by ID
+func (r *GormSurveyLogicRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.SurveyLogic{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete survey logic: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindBySurveyID finds all logic rules of a survey
+func (r *GormSurveyLogicRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error) {
+	var logic []entities.SurveyLogic
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("created_at ASC").
+		Find(&logic).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey logic: %w", err)
+	}
+	return logic, nil
+}
+
+// FindActiveBySurveyID finds the active logic rules of a survey
+func (r *GormSurveyLogicRepository) FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error) {
+	var logic []entities.SurveyLogic
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ? AND is_active = ?", surveyID, true).
+		Order("created_at ASC").
+		Find(&logic).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active survey logic: %w", err)
+	}
+	return logic, nil
+}
+
+// GormSurveyPipingRepository implements SurveyPipingRepository using GORM
+type GormSurveyPipingRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyPipingRepository creates a new GORM survey piping repository
+func NewGormSurveyPipingRepository(db *gorm.DB) repositories.SurveyPipingRepository {
+	return &GormSurveyPipingRepository{db: db}
+}
+
+// Create creates a new survey piping rule
+func (r *GormSurveyPipingRepository) Create(ctx context.Context, piping *entities.SurveyPiping) error {
+	if err := r.db.WithContext(ctx).Create(piping).Error; err != nil {
+		return fmt.Errorf("failed to create survey piping: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey piping rule by ID
+func (r *GormSurveyPipingRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyPiping, error) {
+	var piping entities.SurveyPiping
+	err := r.db.WithContext(ctx).First(&piping, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey piping: %w", err)
+	}
+	return &piping, nil
+}
+
+// Update updates an existing survey piping rule
+func (r *GormSurveyPipingRepository) Update(ctx context.Context, piping *entities.SurveyPiping) error {
+	if err := r.db.WithContext(ctx).Save(piping).Error; err != nil {
+		return fmt.Errorf("failed to update survey piping: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a survey piping rule by ID
+func (r *GormSurveyPipingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.SurveyPiping{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete survey piping: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindBySurveyID finds all piping rules of a survey
+func (r *GormSurveyPipingRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyPiping, error) {
+	var piping []entities.SurveyPiping
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("created_at ASC").
+		Find(&piping).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey piping: %w", err)
+	}
+	return piping, nil
+}
+
+// FindActiveBySurveyID finds the active piping rules of a survey
+func (r *GormSurveyPipingRepository) FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyPiping, error) {
+	var piping []entities.SurveyPiping
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ? AND is_active = ?", surveyID, true).
+		Order("created_at ASC").
+		Find(&piping).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active survey piping: %w", err)
+	}
+	return piping, nil
+}
+
+// GormSurveySessionRepository implements SurveySessionRepository using GORM
+type GormSurveySessionRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveySessionRepository creates a new GORM survey session repository
+func NewGormSurveySessionRepository(db *gorm.DB) repositories.SurveySessionRepository {
+	return &GormSurveySessionRepository{db: db}
+}
+
+// Create creates a new survey session
+func (r *GormSurveySessionRepository) Create(ctx context.Context, session *entities.SurveySession) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create survey session: %w", err)
+	}
+	return nil
+}
+
+// FindBySessionID finds a survey session by its session token
+func (r *GormSurveySessionRepository) FindBySessionID(ctx context.Context, sessionID string) (*entities.SurveySession, error) {
+	var session entities.SurveySession
+	err := r.db.WithContext(ctx).First(&session, "session_id = ?", sessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey session: %w", err)
+	}
+	return &session, nil
+}
+
+// FindByResponseID finds the session of a survey response
+func (r *GormSurveySessionRepository) FindByResponseID(ctx context.Context, responseID uuid.UUID) (*entities.SurveySession, error) {
+	var session entities.SurveySession
+	err := r.db.WithContext(ctx).
+		Where("response_id = ?", responseID).
+		Order("created_at DESC").
+		First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey session: %w", err)
+	}
+	return &session, nil
+}
+
+// Update updates an existing survey session
+func (r *GormSurveySessionRepository) Update(ctx context.Context, session *entities.SurveySession) error {
+	if err := r.db.WithContext(ctx).Save(session).Error; err != nil {
+		return fmt.Errorf("failed to update survey session: %w", err)
+	}
+	return nil
+}
+
+// GormSurveyQuotaRepository implements SurveyQuotaRepository using GORM
+type GormSurveyQuotaRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyQuotaRepository creates a new GORM survey quota repository
+func NewGormSurveyQuotaRepository(db *gorm.DB) repositories.SurveyQuotaRepository {
+	return &GormSurveyQuotaRepository{db: db}
+}
+
+// Create creates a new survey quota
+func (r *GormSurveyQuotaRepository) Create(ctx context.Context, quota *entities.SurveyQuota) error {
+	if err := r.db.WithContext(ctx).Create(quota).Error; err != nil {
+		return fmt.Errorf("failed to create survey quota: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey quota by ID
+func (r *GormSurveyQuotaRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyQuota, error) {
+	var quota entities.SurveyQuota
+	err := r.db.WithContext(ctx).First(&quota, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey quota: %w", err)
+	}
+	return &quota, nil
+}
+
+// Update updates the settings of a survey quota. The current count is left alone so concurrent
+// submissions are not overwritten.
+func (r *GormSurveyQuotaRepository) Update(ctx context.Context, quota *entities.SurveyQuota) error {
+	err := r.db.WithContext(ctx).
+		Model(quota).
+		Select("name", "description", "conditions", "max_responses", "is_active", "screen_out_message").
+		Updates(quota).Error
+	if err != nil {
+		return fmt.Errorf("failed to update survey quota: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a survey quota by ID
+func (r *GormSurveyQuotaRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.SurveyQuota{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete survey quota: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindBySurveyID finds all quotas of a survey
+func (r *GormSurveyQuotaRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error) {
+	var quotas []entities.SurveyQuota
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("created_at ASC").
+		Find(&quotas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey quotas: %w", err)
+	}
+	return quotas, nil
+}
+
+// FindActiveBySurveyID finds the active quotas of a survey
+func (r *GormSurveyQuotaRepository) FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error) {
+	var quotas []entities.SurveyQuota
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ? AND is_active = ?", surveyID, true).
+		Order("created_at ASC").
+		Find(&quotas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active survey quotas: %w", err)
+	}
+	return quotas, nil
+}
+
+// IncrementIfAvailable increments the current count in a single conditional update, so concurrent
+// submissions cannot exceed the maximum
+func (r *GormSurveyQuotaRepository) IncrementIfAvailable(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.SurveyQuota{}).
+		Where("id = ? AND current_count < max_responses", id).
+		UpdateColumn("current_count", gorm.Expr("current_count + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to increment survey quota: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Decrement releases a count taken by IncrementIfAvailable
+func (r *GormSurveyQuotaRepository) Decrement(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.SurveyQuota{}).
+		Where("id = ? AND current_count > 0", id).
+		UpdateColumn("current_count", gorm.Expr("current_count - 1")).Error
+	if err != nil {
+		return fmt.Errorf("failed to decrement survey quota: %w", err)
+	}
+	return nil
+}
+
+// GormSurveyExportRepository implements SurveyExportRepository using GORM
+type GormSurveyExportRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyExportRepository creates a new GORM survey export repository
+func NewGormSurveyExportRepository(db *gorm.DB) repositories.SurveyExportRepository {
+	return &GormSurveyExportRepository{db: db}
+}
+
+// Create creates a new survey export
+func (r *GormSurveyExportRepository) Create(ctx context.Context, export *entities.SurveyExport) error {
+	if err := r.db.WithContext(ctx).Create(export).Error; err != nil {
+		return fmt.Errorf("failed to create survey export: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey export by ID
+func (r *GormSurveyExportRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, error) {
+	var export entities.SurveyExport
+	err := r.db.WithContext(ctx).First(&export, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey export: %w", err)
+	}
+	return &export, nil
+}
+
+// Update updates a survey export
+func (r *GormSurveyExportRepository) Update(ctx context.Context, export *entities.SurveyExport) error {
+	if err := r.db.WithContext(ctx).Omit("Survey").Save(export).Error; err != nil {
+		return fmt.Errorf("failed to update survey export: %w", err)
+	}
+	return nil
+}
+
+// FindBySurveyID finds the latest exports of a survey
+func (r *GormSurveyExportRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID, limit int) ([]entities.SurveyExport, error) {
+	var exports []entities.SurveyExport
+	query := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to find survey exports: %w", err)
+	}
+	return exports, nil
+}
+
+// FindExpiredWithFiles finds completed exports that expired before the given time and still have a stored file
+func (r *GormSurveyExportRepository) FindExpiredWithFiles(ctx context.Context, before time.Time, limit int) ([]entities.SurveyExport, error) {
+	var exports []entities.SurveyExport
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ? AND file_url <> ''", entities.SurveyExportStatusCompleted, before).
+		Order("expires_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to find expired survey exports: %w", err)
+	}
+	return exports, nil
+}
+
+// GormSurveyInvitationRepository implements SurveyInvitationRepository using GORM
+type GormSurveyInvitationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyInvitationRepository creates a new GORM survey invitation repository
+func NewGormSurveyInvitationRepository(db *gorm.DB) repositories.SurveyInvitationRepository {
+	return &GormSurveyInvitationRepository{db: db}
+}
+
+// CreateBatch creates survey invitations in batches
+func (r *GormSurveyInvitationRepository) CreateBatch(ctx context.Context, invitations []entities.SurveyInvitation) error {
+	if len(invitations) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(invitations, 500).Error; err != nil {
+		return fmt.Errorf("failed to create survey invitations: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey invitation by ID
+func (r *GormSurveyInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyInvitation, error) {
+	var invitation entities.SurveyInvitation
+	err := r.db.WithContext(ctx).First(&invitation, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// FindByToken finds a survey invitation by its link token
+func (r *GormSurveyInvitationRepository) FindByToken(ctx context.Context, token string) (*entities.SurveyInvitation, error) {
+	var invitation entities.SurveyInvitation
+	err := r.db.WithContext(ctx).First(&invitation, "token = ?", token).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// Update updates a survey invitation
+func (r *GormSurveyInvitationRepository) Update(ctx context.Context, invitation *entities.SurveyInvitation) error {
+	if err := r.db.WithContext(ctx).Omit("Survey", "Response").Save(invitation).Error; err != nil {
+		return fmt.Errorf("failed to update survey invitation: %w", err)
+	}
+	return nil
+}
+
+// FindBySurveyID lists a survey's invitations, optionally with one status
+func (r *GormSurveyInvitationRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID, status string) ([]entities.SurveyInvitation, error) {
+	var invitations []entities.SurveyInvitation
+	query := r.db.WithContext(ctx).Where("survey_id = ?", surveyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find survey invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// FindAwaitingResponse finds invitations due for a reminder
+func (r *GormSurveyInvitationRepository) FindAwaitingResponse(ctx context.Context, surveyID *uuid.UUID, contactedBefore time.Time, maxReminders, limit int) ([]entities.SurveyInvitation, error) {
+	var invitations []entities.SurveyInvitation
+	query := r.db.WithContext(ctx).
+		Where("status IN ?", []string{
+			entities.SurveyInvitationStatusSent,
+			entities.SurveyInvitationStatusOpened,
+			entities.SurveyInvitationStatusFailed,
+		}).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Where("reminder_count < ?", maxReminders).
+		Where("COALESCE(reminded_at, sent_at) <= ?", contactedBefore)
+	if surveyID != nil {
+		query = query.Where("survey_id = ?", *surveyID)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("sent_at ASC").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find survey invitations awaiting response: %w", err)
+	}
+	return invitations, nil
+}
+
+// ExpireOverdue marks overdue invitations as expired
+func (r *GormSurveyInvitationRepository) ExpireOverdue(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.SurveyInvitation{}).
+		Where("expires_at <= ?", now).
+		Where("status NOT IN ?", []string{entities.SurveyInvitationStatusResponded, entities.SurveyInvitationStatusExpired}).
+		Update("status", entities.SurveyInvitationStatusExpired)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire survey invitations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GormSurveyNotificationRepository implements SurveyNotificationRepository using GORM
+type GormSurveyNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyNotificationRepository creates a new GORM survey notification repository
+func NewGormSurveyNotificationRepository(db *gorm.DB) repositories.SurveyNotificationRepository {
+	return &GormSurveyNotificationRepository{db: db}
+}
+
+// Create creates a new survey notification
+func (r *GormSurveyNotificationRepository) Create(ctx context.Context, notification *entities.SurveyNotification) error {
+	if err := r.db.WithContext(ctx).Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create survey notification: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey notification by ID
+func (r *GormSurveyNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyNotification, error) {
+	var notification entities.SurveyNotification
+	err := r.db.WithContext(ctx).First(&notification, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey notification: %w", err)
+	}
+	return &notification, nil
+}
+
+// Update updates the settings of a survey notification. The send count is left alone so
+// concurrent deliveries are not overwritten.
+func (r *GormSurveyNotificationRepository) Update(ctx context.Context, notification *entities.SurveyNotification) error {
+	err := r.db.WithContext(ctx).
+		Model(notification).
+		Select("event_type", "channel", "recipients", "conditions", "subject", "message", "is_active").
+		Updates(notification).Error
+	if err != nil {
+		return fmt.Errorf("failed to update survey notification: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a survey notification by ID
+func (r *GormSurveyNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.SurveyNotification{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete survey notification: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindBySurveyID finds all notifications of a survey
+func (r *GormSurveyNotificationRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyNotification, error) {
+	var notifications []entities.SurveyNotification
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("created_at ASC").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// FindActive finds the active notifications of a survey event
+func (r *GormSurveyNotificationRepository) FindActive(ctx context.Context, surveyID uuid.UUID, eventType string) ([]entities.SurveyNotification, error) {
+	var notifications []entities.SurveyNotification
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ? AND event_type = ? AND is_active = ?", surveyID, eventType, true).
+		Order("created_at ASC").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active survey notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// FindUndispatchedClosed finds the survey_closed notifications still to dispatch
+func (r *GormSurveyNotificationRepository) FindUndispatchedClosed(ctx context.Context, now time.Time, limit int) ([]entities.SurveyNotification, error) {
+	var notifications []entities.SurveyNotification
+	query := r.db.WithContext(ctx).
+		Joins("JOIN surveys ON surveys.id = survey_notifications.survey_id").
+		Where("survey_notifications.event_type = ? AND survey_notifications.is_active = ?", entities.SurveyNotificationEventSurveyClosed, true).
+		Where("surveys.status = ? OR (surveys.status = ? AND surveys.end_date <= ?)",
+			entities.SurveyStatusClosed, entities.SurveyStatusPublished, now).
+		Where("NOT EXISTS (SELECT 1 FROM survey_notification_deliveries d WHERE d.notification_id = survey_notifications.id)")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("survey_notifications.created_at ASC").Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to find closed survey notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// RecordSent increments the send count of a survey notification
+func (r *GormSurveyNotificationRepository) RecordSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.SurveyNotification{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"send_count": gorm.Expr("send_count + 1"),
+			"last_sent":  sentAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record survey notification sent: %w", err)
+	}
+	return nil
+}
+
+// GormSurveyNotificationDeliveryRepository implements SurveyNotificationDeliveryRepository using GORM
+type GormSurveyNotificationDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyNotificationDeliveryRepository creates a new GORM survey notification delivery repository
+func NewGormSurveyNotificationDeliveryRepository(db *gorm.DB) repositories.SurveyNotificationDeliveryRepository {
+	return &GormSurveyNotificationDeliveryRepository{db: db}
+}
+
+// CreateBatch creates survey notification deliveries in batches
+func (r *GormSurveyNotificationDeliveryRepository) CreateBatch(ctx context.Context, deliveries []entities.SurveyNotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(deliveries, 500).Error; err != nil {
+		return fmt.Errorf("failed to create survey notification deliveries: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey notification delivery by ID
+func (r *GormSurveyNotificationDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyNotificationDelivery, error) {
+	var delivery entities.SurveyNotificationDelivery
+	err := r.db.WithContext(ctx).First(&delivery, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey notification delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// Update updates a survey notification delivery
+func (r *GormSurveyNotificationDeliveryRepository) Update(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error {
+	if err := r.db.WithContext(ctx).Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update survey notification delivery: %w", err)
+	}
+	return nil
+}
+
+// FindByNotificationID lists the latest deliveries of a notification
+func (r *GormSurveyNotificationDeliveryRepository) FindByNotificationID(ctx context.Context, notificationID uuid.UUID, limit int) ([]entities.SurveyNotificationDelivery, error) {
+	var deliveries []entities.SurveyNotificationDelivery
+	query := r.db.WithContext(ctx).Where("notification_id = ?", notificationID)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find survey notification deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// FindDue finds pending deliveries whose next attempt is due
+func (r *GormSurveyNotificationDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]entities.SurveyNotificationDelivery, error) {
+	var deliveries []entities.SurveyNotificationDelivery
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", entities.SurveyNotificationDeliveryPending, now)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("next_attempt_at ASC").Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find due survey notification deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ExistsForNotification reports whether a notification has deliveries
+func (r *GormSurveyNotificationDeliveryRepository) ExistsForNotification(ctx context.Context, notificationID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.SurveyNotificationDelivery{}).
+		Where("notification_id = ?", notificationID).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check survey notification deliveries: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GormSurveyCollectorRepository implements SurveyCollectorRepository using GORM
+type GormSurveyCollectorRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyCollectorRepository creates a new GORM survey collector repository
+func NewGormSurveyCollectorRepository(db *gorm.DB) repositories.SurveyCollectorRepository {
+	return &GormSurveyCollectorRepository{db: db}
+}
+
+// Create creates a new survey collector
+func (r *GormSurveyCollectorRepository) Create(ctx context.Context, collector *entities.SurveyCollector) error {
+	if err := r.db.WithContext(ctx).Create(collector).Error; err != nil {
+		return fmt.Errorf("failed to create survey collector: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a survey collector by ID
+func (r *GormSurveyCollectorRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyCollector, error) {
+	var collector entities.SurveyCollector
+	err := r.db.WithContext(ctx).First(&collector, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey collector: %w", err)
+	}
+	return &collector, nil
+}
+
+// Update updates the settings of a survey collector. The response count is left alone so
+// concurrent submissions are not overwritten.
+func (r *GormSurveyCollectorRepository) Update(ctx context.Context, collector *entities.SurveyCollector) error {
+	err := r.db.WithContext(ctx).
+		Model(collector).
+		Select("name", "type", "url", "embed_code", "qr_code_url", "is_active", "opens_at", "closes_at", "max_responses", "settings").
+		Updates(collector).Error
+	if err != nil {
+		return fmt.Errorf("failed to update survey collector: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a survey collector by ID. Its responses are kept without a collector.
+func (r *GormSurveyCollectorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.SurveyCollector{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete survey collector: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindBySurveyID finds all collectors of a survey
+func (r *GormSurveyCollectorRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyCollector, error) {
+	var collectors []entities.SurveyCollector
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("created_at ASC").
+		Find(&collectors).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey collectors: %w", err)
+	}
+	return collectors, nil
+}
+
+// IncrementIfAvailable increments the response count in a single conditional update, so concurrent
+// submissions cannot exceed the collector's maximum
+func (r *GormSurveyCollectorRepository) IncrementIfAvailable(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.SurveyCollector{}).
+		Where("id = ? AND (max_responses IS NULL OR response_count < max_responses)", id).
+		UpdateColumn("response_count", gorm.Expr("response_count + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to increment survey collector: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Decrement releases a count taken by IncrementIfAvailable
+func (r *GormSurveyCollectorRepository) Decrement(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.SurveyCollector{}).
+		Where("id = ? AND response_count > 0", id).
+		UpdateColumn("response_count", gorm.Expr("response_count - 1")).Error
+	if err != nil {
+		return fmt.Errorf("failed to decrement survey collector: %w", err)
+	}
+	return nil
+}
+
+// GormSurveyVersionRepository implements SurveyVersionRepository using GORM
+type GormSurveyVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyVersionRepository creates a new GORM survey version repository
+func NewGormSurveyVersionRepository(db *gorm.DB) repositories.SurveyVersionRepository {
+	return &GormSurveyVersionRepository{db: db}
+}
+
+// Create creates a new survey version
+func (r *GormSurveyVersionRepository) Create(ctx context.Context, version *entities.SurveyVersion) error {
+	if err := r.db.WithContext(ctx).Create(version).Error; err != nil {
+		return fmt.Errorf("failed to create survey version: %w", err)
+	}
+	return nil
+}
+
+// FindBySurveyID finds the versions of a survey, oldest first
+func (r *GormSurveyVersionRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyVersion, error) {
+	var versions []entities.SurveyVersion
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("version ASC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey versions: %w", err)
+	}
+	return versions, nil
+}
+
+// FindByVersion finds one version of a survey
+func (r *GormSurveyVersionRepository) FindByVersion(ctx context.Context, surveyID uuid.UUID, version int) (*entities.SurveyVersion, error) {
+	var surveyVersion entities.SurveyVersion
+	err := r.db.WithContext(ctx).First(&surveyVersion, "survey_id = ? AND version = ?", surveyID, version).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey version: %w", err)
+	}
+	return &surveyVersion, nil
+}
+
+// GormSurveyLivePollRepository implements SurveyLivePollRepository using GORM
+type GormSurveyLivePollRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSurveyLivePollRepository creates a new GORM survey live poll repository
+func NewGormSurveyLivePollRepository(db *gorm.DB) repositories.SurveyLivePollRepository {
+	return &GormSurveyLivePollRepository{db: db}
+}
+
+// Create creates a new live poll
+func (r *GormSurveyLivePollRepository) Create(ctx context.Context, poll *entities.SurveyLivePoll) error {
+	if err := r.db.WithContext(ctx).Create(poll).Error; err != nil {
+		return fmt.Errorf("failed to create survey live poll: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a live poll by ID
+func (r *GormSurveyLivePollRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyLivePoll, error) {
+	var poll entities.SurveyLivePoll
+	err := r.db.WithContext(ctx).First(&poll, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey live poll: %w", err)
+	}
+	return &poll, nil
+}
+
+// Update updates a live poll
+func (r *GormSurveyLivePollRepository) Update(ctx context.Context, poll *entities.SurveyLivePoll) error {
+	if err := r.db.WithContext(ctx).Save(poll).Error; err != nil {
+		return fmt.Errorf("failed to update survey live poll: %w", err)
+	}
+	return nil
+}
+
+// FindBySurveyID finds the live polls of a survey, latest first
+func (r *GormSurveyLivePollRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLivePoll, error) {
+	var polls []entities.SurveyLivePoll
+	err := r.db.WithContext(ctx).
+		Where("survey_id = ?", surveyID).
+		Order("opened_at DESC").
+		Find(&polls).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find survey live polls: %w", err)
+	}
+	return polls, nil
+}
+
+// FindLatestByEventID finds the live poll opened last at an event
+func (r *GormSurveyLivePollRepository) FindLatestByEventID(ctx context.Context, eventID uuid.UUID) (*entities.SurveyLivePoll, error) {
+	var poll entities.SurveyLivePoll
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("opened_at DESC").
+		First(&poll).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find survey live poll: %w", err)
+	}
+	return &poll, nil
+}
+
+// FindOpenByEventID finds the open live polls of an event
+func (r *GormSurveyLivePollRepository) FindOpenByEventID(ctx context.Context, eventID uuid.UUID) ([]entities.SurveyLivePoll, error) {
+	var polls []entities.SurveyLivePoll
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND status = ?", eventID, entities.SurveyLivePollStatusOpen).
+		Find(&polls).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open survey live polls: %w", err)
+	}
+	return polls, nil
+}
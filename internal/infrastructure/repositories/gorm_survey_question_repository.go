@@ -70,11 +70,11 @@ func (r *GormSurveyQuestionRepository) Delete(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
-// FindBySurveyID finds all questions for a survey
+// FindBySurveyID finds the current questions of a survey, leaving out those removed by a revision
 func (r *GormSurveyQuestionRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuestion, error) {
 	var questions []entities.SurveyQuestion
 	err := r.db.WithContext(ctx).
-		Where("survey_id = ?", surveyID).
+		Where("survey_id = ? AND removed_in_version IS NULL", surveyID).
 		Order("\"order\" ASC").
 		Find(&questions).Error
 	
@@ -132,7 +132,7 @@ func (r *GormSurveyQuestionRepository) FindWithFilter(ctx context.Context, filte
 func (r *GormSurveyQuestionRepository) applyFilters(query *gorm.DB, filter repositories.SurveyQuestionFilter) *gorm.DB {
 	// Survey ID filter
 	if filter.SurveyID != uuid.Nil {
-		query = query.Where("survey_id = ?", filter.SurveyID)
+		query = query.Where("survey_id = ? AND removed_in_version IS NULL", filter.SurveyID)
 	}
 	
 	// Question type filter
@@ -158,7 +158,7 @@ func (r *GormSurveyQuestionRepository) CountBySurveyID(ctx context.Context, surv
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&entities.SurveyQuestion{}).
-		Where("survey_id = ?", surveyID).
+		Where("survey_id = ? AND removed_in_version IS NULL", surveyID).
 		Count(&count).Error
 	
 	if err != nil {
@@ -298,7 +298,7 @@ func (r *GormSurveyQuestionRepository) DeleteBySurveyID(ctx context.Context, sur
 func (r *GormSurveyQuestionRepository) FindRequiredQuestions(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuestion, error) {
 	var questions []entities.SurveyQuestion
 	err := r.db.WithContext(ctx).
-		Where("survey_id = ? AND is_required = ?", surveyID, true).
+		Where("survey_id = ? AND is_required = ? AND removed_in_version IS NULL", surveyID, true).
 		Order("\"order\" ASC").
 		Find(&questions).Error
 	
@@ -313,7 +313,7 @@ func (r *GormSurveyQuestionRepository) FindRequiredQuestions(ctx context.Context
 func (r *GormSurveyQuestionRepository) FindOptionalQuestions(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuestion, error) {
 	var questions []entities.SurveyQuestion
 	err := r.db.WithContext(ctx).
-		Where("survey_id = ? AND is_required = ?", surveyID, false).
+		Where("survey_id = ? AND is_required = ? AND removed_in_version IS NULL", surveyID, false).
 		Order("\"order\" ASC").
 		Find(&questions).Error
 	
@@ -334,7 +334,7 @@ func (r *GormSurveyQuestionRepository) GetQuestionTypeStats(ctx context.Context,
 	err := r.db.WithContext(ctx).
 		Model(&entities.SurveyQuestion{}).
 		Select("question_type, COUNT(*) as count").
-		Where("survey_id = ?", surveyID).
+		Where("survey_id = ? AND removed_in_version IS NULL", surveyID).
 		Group("question_type").
 		Scan(&results).Error
 	
@@ -36,7 +36,7 @@ func (r *GormSurveyRepository) FindByID(ctx context.Context, id uuid.UUID) (*ent
 	var survey entities.Survey
 	err := r.db.WithContext(ctx).
 		Preload("Questions", func(db *gorm.DB) *gorm.DB {
-			return db.Order("\"order\" ASC")
+			return db.Where("removed_in_version IS NULL").Order("\"order\" ASC")
 		}).
 		Preload("Responses").
 		First(&survey, "id = ?", id).Error
@@ -79,7 +79,7 @@ func (r *GormSurveyRepository) Delete(ctx context.Context, id uuid.UUID) error {
 func (r *GormSurveyRepository) FindAll(ctx context.Context) ([]entities.Survey, error) {
 	var surveys []entities.Survey
 	err := r.db.WithContext(ctx).
-		Preload("Questions").
+		Preload("Questions", "removed_in_version IS NULL").
 		Preload("Responses").
 		Find(&surveys).Error
 
@@ -101,7 +101,7 @@ func (r *GormSurveyRepository) FindWithFilter(ctx context.Context, filter reposi
 	var surveys []entities.Survey
 
 	query := r.db.WithContext(ctx).
-		Preload("Questions").
+		Preload("Questions", "removed_in_version IS NULL").
 		Preload("Responses")
 
 	// Apply filters
@@ -2,11 +2,13 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/zenteam/nextevent-go/internal/domain/entities"
 	"github.com/zenteam/nextevent-go/internal/domain/repositories"
@@ -63,6 +65,48 @@ func (r *GormSurveyResponseRepository) Update(ctx context.Context, response *ent
 	return nil
 }
 
+// SaveWithAnswers replaces the answers of a response and saves it in one transaction, locking the
+// survey row while counting completed responses against maxCompleted
+func (r *GormSurveyResponseRepository) SaveWithAnswers(ctx context.Context, response *entities.SurveyResponse, answers []entities.SurveyAnswer, maxCompleted int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if maxCompleted > 0 {
+			var survey entities.Survey
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id").
+				First(&survey, "id = ?", response.SurveyID).Error; err != nil {
+				return err
+			}
+
+			var completed int64
+			if err := tx.Model(&entities.SurveyResponse{}).
+				Where("survey_id = ? AND status IN ?", response.SurveyID, []entities.ResponseStatus{
+					entities.ResponseStatusCompleted,
+					entities.ResponseStatusSubmitted,
+				}).
+				Count(&completed).Error; err != nil {
+				return err
+			}
+			if completed >= int64(maxCompleted) {
+				return repositories.ErrLimitReached
+			}
+		}
+
+		if err := tx.Where("response_id = ?", response.ID).Delete(&entities.SurveyAnswer{}).Error; err != nil {
+			return err
+		}
+		if len(answers) > 0 {
+			if err := tx.Create(&answers).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(response).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrLimitReached) {
+		return fmt.Errorf("failed to save survey response: %w", err)
+	}
+	return err
+}
+
 // Delete deletes a survey response by ID
 func (r *GormSurveyResponseRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	result := r.db.WithContext(ctx).Delete(&entities.SurveyResponse{}, "id = ?", id)
@@ -168,6 +212,16 @@ func (r *GormSurveyResponseRepository) applyFilters(query *gorm.DB, filter repos
 		query = query.Where("respondent_id = ?", *filter.RespondentID)
 	}
 	
+	// Session ID filter
+	if filter.SessionID != "" {
+		query = query.Where("session_id = ?", filter.SessionID)
+	}
+	
+	// Collector filter
+	if filter.CollectorID != nil {
+		query = query.Where("collector_id = ?", *filter.CollectorID)
+	}
+	
 	// Status filter
 	if filter.Status != "" {
 		query = query.Where("status = ?", filter.Status)
@@ -261,6 +315,44 @@ func (r *GormSurveyResponseRepository) FindBySessionID(ctx context.Context, sess
 	return &response, nil
 }
 
+// FindInBatches walks the matching responses in start order, paging by (started_at, id) so that
+// large surveys are read in constant memory
+func (r *GormSurveyResponseRepository) FindInBatches(ctx context.Context, filter repositories.SurveyResponseFilter, batchSize int, fn func(responses []entities.SurveyResponse) error) error {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var lastStartedAt time.Time
+	var lastID uuid.UUID
+	for first := true; ; first = false {
+		query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.SurveyResponse{}), filter)
+		if !first {
+			query = query.Where("(started_at, id) > (?, ?)", lastStartedAt, lastID)
+		}
+
+		var responses []entities.SurveyResponse
+		err := query.
+			Preload("Answers").
+			Order("started_at ASC, id ASC").
+			Limit(batchSize).
+			Find(&responses).Error
+		if err != nil {
+			return fmt.Errorf("failed to find survey responses in batches: %w", err)
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		if err := fn(responses); err != nil {
+			return err
+		}
+		if len(responses) < batchSize {
+			return nil
+		}
+		last := responses[len(responses)-1]
+		lastStartedAt, lastID = last.StartedAt, last.ID
+	}
+}
+
 // UpdateStatus updates response status
 func (r *GormSurveyResponseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.ResponseStatus) error {
 	updates := map[string]interface{}{
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+// GormWeChatFollowerSyncRepository implements WeChatFollowerSyncRepository using GORM
+type GormWeChatFollowerSyncRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWeChatFollowerSyncRepository creates a new GORM-based WeChat follower sync repository
+func NewGormWeChatFollowerSyncRepository(db *gorm.DB) repositories.WeChatFollowerSyncRepository {
+	return &GormWeChatFollowerSyncRepository{db: db}
+}
+
+// Create creates a new follower sync
+func (r *GormWeChatFollowerSyncRepository) Create(ctx context.Context, sync *entities.WeChatFollowerSync) error {
+	if err := r.db.WithContext(ctx).Create(sync).Error; err != nil {
+		return fmt.Errorf("failed to create WeChat follower sync: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a follower sync by ID
+func (r *GormWeChatFollowerSyncRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatFollowerSync, error) {
+	var sync entities.WeChatFollowerSync
+	err := r.db.WithContext(ctx).First(&sync, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat follower sync: %w", err)
+	}
+	return &sync, nil
+}
+
+// Update updates a follower sync
+func (r *GormWeChatFollowerSyncRepository) Update(ctx context.Context, sync *entities.WeChatFollowerSync) error {
+	if err := r.db.WithContext(ctx).Save(sync).Error; err != nil {
+		return fmt.Errorf("failed to update WeChat follower sync: %w", err)
+	}
+	return nil
+}
+
+// FindRunning finds the sync started last among those still running
+func (r *GormWeChatFollowerSyncRepository) FindRunning(ctx context.Context) (*entities.WeChatFollowerSync, error) {
+	var sync entities.WeChatFollowerSync
+	err := r.db.WithContext(ctx).
+		Where("status = ?", entities.WeChatFollowerSyncStatusRunning).
+		Order("started_at DESC").
+		First(&sync).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find running WeChat follower sync: %w", err)
+	}
+	return &sync, nil
+}
+
+// FindRecent finds the syncs started last, latest first
+func (r *GormWeChatFollowerSyncRepository) FindRecent(ctx context.Context, limit int) ([]entities.WeChatFollowerSync, error) {
+	var syncs []entities.WeChatFollowerSync
+	err := r.db.WithContext(ctx).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&syncs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find WeChat follower syncs: %w", err)
+	}
+	return syncs, nil
+}
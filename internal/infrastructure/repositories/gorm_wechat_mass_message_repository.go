@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+// GormWeChatMassMessageRepository implements WeChatMassMessageRepository using GORM
+type GormWeChatMassMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWeChatMassMessageRepository creates a new GORM-based WeChat mass message repository
+func NewGormWeChatMassMessageRepository(db *gorm.DB) repositories.WeChatMassMessageRepository {
+	return &GormWeChatMassMessageRepository{db: db}
+}
+
+// Create creates a new mass message
+func (r *GormWeChatMassMessageRepository) Create(ctx context.Context, message *entities.WeChatMassMessage) error {
+	if err := r.db.WithContext(ctx).Create(message).Error; err != nil {
+		return fmt.Errorf("failed to create WeChat mass message: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a mass message by ID
+func (r *GormWeChatMassMessageRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error) {
+	var message entities.WeChatMassMessage
+	err := r.db.WithContext(ctx).First(&message, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat mass message: %w", err)
+	}
+	return r.withOpenIDs(&message)
+}
+
+// FindByMsgID finds the message WeChat assigned a msg_id when sending it
+func (r *GormWeChatMassMessageRepository) FindByMsgID(ctx context.Context, msgID int64) (*entities.WeChatMassMessage, error) {
+	var message entities.WeChatMassMessage
+	err := r.db.WithContext(ctx).First(&message, "msg_id = ?", msgID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat mass message: %w", err)
+	}
+	return r.withOpenIDs(&message)
+}
+
+// Update updates a mass message
+func (r *GormWeChatMassMessageRepository) Update(ctx context.Context, message *entities.WeChatMassMessage) error {
+	if err := r.db.WithContext(ctx).Save(message).Error; err != nil {
+		return fmt.Errorf("failed to update WeChat mass message: %w", err)
+	}
+	return nil
+}
+
+// ClaimForSending moves a draft or failed message to sending and reports whether it did
+func (r *GormWeChatMassMessageRepository) ClaimForSending(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.WeChatMassMessage{}).
+		Where("id = ? AND status IN ?", id, []string{entities.WeChatMassMessageStatusDraft, entities.WeChatMassMessageStatusFailed}).
+		Update("status", entities.WeChatMassMessageStatusSending)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim WeChat mass message: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindRecent finds the messages created last, latest first
+func (r *GormWeChatMassMessageRepository) FindRecent(ctx context.Context, limit int) ([]entities.WeChatMassMessage, error) {
+	var messages []entities.WeChatMassMessage
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find WeChat mass messages: %w", err)
+	}
+	for i := range messages {
+		if _, err := r.withOpenIDs(&messages[i]); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
+}
+
+// withOpenIDs fills the computed OpenIDs of a message
+func (r *GormWeChatMassMessageRepository) withOpenIDs(message *entities.WeChatMassMessage) (*entities.WeChatMassMessage, error) {
+	openIDs, err := message.GetOpenIDs()
+	if err != nil {
+		return nil, err
+	}
+	message.OpenIDs = openIDs
+	return message, nil
+}
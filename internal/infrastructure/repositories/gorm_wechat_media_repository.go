@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+// GormWeChatMediaRepository implements WeChatMediaRepository using GORM
+type GormWeChatMediaRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWeChatMediaRepository creates a new GORM-based WeChat media repository
+func NewGormWeChatMediaRepository(db *gorm.DB) repositories.WeChatMediaRepository {
+	return &GormWeChatMediaRepository{db: db}
+}
+
+// Create creates a new media record
+func (r *GormWeChatMediaRepository) Create(ctx context.Context, media *entities.WeChatMedia) error {
+	if err := r.db.WithContext(ctx).Create(media).Error; err != nil {
+		return fmt.Errorf("failed to create WeChat media: %w", err)
+	}
+	return nil
+}
+
+// FindByContentHash finds the media of a type last uploaded with the content
+func (r *GormWeChatMediaRepository) FindByContentHash(ctx context.Context, contentHash, mediaType string, permanent bool) (*entities.WeChatMedia, error) {
+	var media entities.WeChatMedia
+	err := r.db.WithContext(ctx).
+		Where("content_hash = ? AND media_type = ? AND permanent = ?", contentHash, mediaType, permanent).
+		Order("created_at DESC").
+		First(&media).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat media by content hash: %w", err)
+	}
+	return &media, nil
+}
+
+// FindBySiteImage finds the media of a type last mapped to the site image
+func (r *GormWeChatMediaRepository) FindBySiteImage(ctx context.Context, siteImageID uuid.UUID, mediaType string, permanent bool) (*entities.WeChatMedia, error) {
+	var media entities.WeChatMedia
+	err := r.db.WithContext(ctx).
+		Where("site_image_id = ? AND media_type = ? AND permanent = ?", siteImageID, mediaType, permanent).
+		Order("created_at DESC").
+		First(&media).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat media by site image: %w", err)
+	}
+	return &media, nil
+}
+
+// DeleteByMediaID deletes the records of WeChat media, including their site image mappings
+func (r *GormWeChatMediaRepository) DeleteByMediaID(ctx context.Context, mediaID string) error {
+	if err := r.db.WithContext(ctx).Where("media_id = ?", mediaID).Delete(&entities.WeChatMedia{}).Error; err != nil {
+		return fmt.Errorf("failed to delete WeChat media: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+// GormWeChatMenuRepository implements WeChatMenuRepository using GORM
+type GormWeChatMenuRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWeChatMenuRepository creates a new GORM-based WeChat menu repository
+func NewGormWeChatMenuRepository(db *gorm.DB) repositories.WeChatMenuRepository {
+	return &GormWeChatMenuRepository{db: db}
+}
+
+// Create creates a new menu version
+func (r *GormWeChatMenuRepository) Create(ctx context.Context, menu *entities.WeChatMenu) error {
+	if err := r.db.WithContext(ctx).Create(menu).Error; err != nil {
+		return fmt.Errorf("failed to create WeChat menu: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a menu version by ID
+func (r *GormWeChatMenuRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatMenu, error) {
+	var menu entities.WeChatMenu
+	err := r.db.WithContext(ctx).First(&menu, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat menu: %w", err)
+	}
+	return &menu, nil
+}
+
+// Update updates a menu version
+func (r *GormWeChatMenuRepository) Update(ctx context.Context, menu *entities.WeChatMenu) error {
+	if err := r.db.WithContext(ctx).Save(menu).Error; err != nil {
+		return fmt.Errorf("failed to update WeChat menu: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a menu version
+func (r *GormWeChatMenuRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.WeChatMenu{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete WeChat menu: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindByTagID finds the versions of the menu of a tag, latest first
+func (r *GormWeChatMenuRepository) FindByTagID(ctx context.Context, tagID string) ([]entities.WeChatMenu, error) {
+	var menus []entities.WeChatMenu
+	err := r.db.WithContext(ctx).
+		Where("tag_id = ?", tagID).
+		Order("version DESC").
+		Find(&menus).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find WeChat menu versions: %w", err)
+	}
+	return menus, nil
+}
+
+// FindPublished finds the published menus, the default menu first
+func (r *GormWeChatMenuRepository) FindPublished(ctx context.Context) ([]entities.WeChatMenu, error) {
+	var menus []entities.WeChatMenu
+	err := r.db.WithContext(ctx).
+		Where("status = ?", entities.WeChatMenuStatusPublished).
+		Order("tag_id ASC, published_at ASC").
+		Find(&menus).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find published WeChat menus: %w", err)
+	}
+	return menus, nil
+}
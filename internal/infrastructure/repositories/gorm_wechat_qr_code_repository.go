@@ -72,11 +72,18 @@ func (r *GormWeChatQrCodeRepository) GetActiveByResource(ctx context.Context, re
 	return &qrCode, nil
 }
 
-// GetBySceneStr retrieves a QR code by scene string
+// GetBySceneStr retrieves a QR code by scene string. Scene strings are not stored, so the
+// resource ID encoded in them ("<type>_<id>") must equal ParamsValue.
 func (r *GormWeChatQrCodeRepository) GetBySceneStr(ctx context.Context, sceneStr string) (*entities.WeChatQrCode, error) {
+	_, value, ok := entities.ParseSceneStr(sceneStr)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
 	var qrCode entities.WeChatQrCode
 	err := r.db.WithContext(ctx).
-		Where("scene_str = ? AND is_deleted = ?", sceneStr, false).
+		Where("ParamsValue = ? AND IsDeleted = ?", value, false).
+		Order("CreationTime DESC").
 		First(&qrCode).Error
 	if err != nil {
 		return nil, err
@@ -88,7 +95,7 @@ func (r *GormWeChatQrCodeRepository) GetBySceneStr(ctx context.Context, sceneStr
 func (r *GormWeChatQrCodeRepository) GetByTicket(ctx context.Context, ticket string) (*entities.WeChatQrCode, error) {
 	var qrCode entities.WeChatQrCode
 	err := r.db.WithContext(ctx).
-		Where("ticket = ? AND is_deleted = ?", ticket, false).
+		Where("Ticket = ? AND IsDeleted = ?", ticket, false).
 		First(&qrCode).Error
 	if err != nil {
 		return nil, err
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deliveryInsertBatchSize bounds the deliveries inserted by one statement
+const deliveryInsertBatchSize = 500
+
+// GormWeChatTemplateCampaignRepository implements WeChatTemplateCampaignRepository using GORM
+type GormWeChatTemplateCampaignRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWeChatTemplateCampaignRepository creates a new GORM-based WeChat template campaign repository
+func NewGormWeChatTemplateCampaignRepository(db *gorm.DB) repositories.WeChatTemplateCampaignRepository {
+	return &GormWeChatTemplateCampaignRepository{db: db}
+}
+
+// Create creates a new campaign
+func (r *GormWeChatTemplateCampaignRepository) Create(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error {
+	if err := r.db.WithContext(ctx).Create(campaign).Error; err != nil {
+		return fmt.Errorf("failed to create WeChat template campaign: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a campaign by ID
+func (r *GormWeChatTemplateCampaignRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error) {
+	var campaign entities.WeChatTemplateCampaign
+	err := r.db.WithContext(ctx).First(&campaign, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat template campaign: %w", err)
+	}
+	return r.withFields(&campaign)
+}
+
+// Update updates a campaign
+func (r *GormWeChatTemplateCampaignRepository) Update(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error {
+	if err := r.db.WithContext(ctx).Save(campaign).Error; err != nil {
+		return fmt.Errorf("failed to update WeChat template campaign: %w", err)
+	}
+	return nil
+}
+
+// FindRecent finds the campaigns created last, latest first
+func (r *GormWeChatTemplateCampaignRepository) FindRecent(ctx context.Context, limit int) ([]entities.WeChatTemplateCampaign, error) {
+	var campaigns []entities.WeChatTemplateCampaign
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&campaigns).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find WeChat template campaigns: %w", err)
+	}
+	for i := range campaigns {
+		if _, err := r.withFields(&campaigns[i]); err != nil {
+			return nil, err
+		}
+	}
+	return campaigns, nil
+}
+
+// FindStalled finds the sending campaigns neither they nor their deliveries were updated since
+// idleSince, oldest first
+func (r *GormWeChatTemplateCampaignRepository) FindStalled(ctx context.Context, idleSince time.Time, limit int) ([]entities.WeChatTemplateCampaign, error) {
+	var campaigns []entities.WeChatTemplateCampaign
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND updated_at < ?", entities.WeChatTemplateCampaignStatusSending, idleSince).
+		Where("NOT EXISTS (SELECT 1 FROM wechat_template_deliveries d WHERE d.campaign_id = wechat_template_campaigns.id AND d.updated_at >= ?)", idleSince).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&campaigns).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stalled WeChat template campaigns: %w", err)
+	}
+	for i := range campaigns {
+		if _, err := r.withFields(&campaigns[i]); err != nil {
+			return nil, err
+		}
+	}
+	return campaigns, nil
+}
+
+// withFields fills the computed field mappings of a campaign
+func (r *GormWeChatTemplateCampaignRepository) withFields(campaign *entities.WeChatTemplateCampaign) (*entities.WeChatTemplateCampaign, error) {
+	fields, err := campaign.GetFields()
+	if err != nil {
+		return nil, err
+	}
+	campaign.FieldMappings = fields
+	return campaign, nil
+}
+
+// GormWeChatTemplateDeliveryRepository implements WeChatTemplateDeliveryRepository using GORM
+type GormWeChatTemplateDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWeChatTemplateDeliveryRepository creates a new GORM-based WeChat template delivery repository
+func NewGormWeChatTemplateDeliveryRepository(db *gorm.DB) repositories.WeChatTemplateDeliveryRepository {
+	return &GormWeChatTemplateDeliveryRepository{db: db}
+}
+
+// CreateBatch creates deliveries, skipping followers the campaign already has a delivery for
+func (r *GormWeChatTemplateDeliveryRepository) CreateBatch(ctx context.Context, deliveries []entities.WeChatTemplateDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(deliveries, deliveryInsertBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to create WeChat template deliveries: %w", err)
+	}
+	return nil
+}
+
+// FindPending finds the pending deliveries of a campaign, oldest first
+func (r *GormWeChatTemplateDeliveryRepository) FindPending(ctx context.Context, campaignID uuid.UUID, limit int) ([]entities.WeChatTemplateDelivery, error) {
+	var deliveries []entities.WeChatTemplateDelivery
+	err := r.db.WithContext(ctx).
+		Where("campaign_id = ? AND status = ?", campaignID, entities.WeChatTemplateDeliveryStatusPending).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending WeChat template deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// FindByMsgID finds the delivery WeChat assigned a msgid when sending it
+func (r *GormWeChatTemplateDeliveryRepository) FindByMsgID(ctx context.Context, msgID int64) (*entities.WeChatTemplateDelivery, error) {
+	var delivery entities.WeChatTemplateDelivery
+	err := r.db.WithContext(ctx).First(&delivery, "msg_id = ?", msgID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find WeChat template delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// FindByCampaign finds the deliveries of a campaign with a status, or all of them for ""
+func (r *GormWeChatTemplateDeliveryRepository) FindByCampaign(ctx context.Context, campaignID uuid.UUID, status string, offset, limit int) ([]entities.WeChatTemplateDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.WeChatTemplateDelivery{}).Where("campaign_id = ?", campaignID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count WeChat template deliveries: %w", err)
+	}
+
+	var deliveries []entities.WeChatTemplateDelivery
+	err := query.
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find WeChat template deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// Update updates a delivery
+func (r *GormWeChatTemplateDeliveryRepository) Update(ctx context.Context, delivery *entities.WeChatTemplateDelivery) error {
+	if err := r.db.WithContext(ctx).Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update WeChat template delivery: %w", err)
+	}
+	return nil
+}
+
+// CountByStatus counts the deliveries of a campaign by status
+func (r *GormWeChatTemplateDeliveryRepository) CountByStatus(ctx context.Context, campaignID uuid.UUID) (map[string]int, error) {
+	var statusCounts []struct {
+		Status string `gorm:"column:status"`
+		Count  int    `gorm:"column:count"`
+	}
+	err := r.db.WithContext(ctx).
+		Model(&entities.WeChatTemplateDelivery{}).
+		Select("status, COUNT(*) as count").
+		Where("campaign_id = ?", campaignID).
+		Group("status").
+		Scan(&statusCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count WeChat template deliveries: %w", err)
+	}
+
+	counts := make(map[string]int, len(statusCounts))
+	for _, sc := range statusCounts {
+		counts[sc.Status] = sc.Count
+	}
+	return counts, nil
+}
+
+// CancelPending cancels the pending deliveries of a campaign and returns how many were cancelled
+func (r *GormWeChatTemplateDeliveryRepository) CancelPending(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.WeChatTemplateDelivery{}).
+		Where("campaign_id = ? AND status = ?", campaignID, entities.WeChatTemplateDeliveryStatusPending).
+		Update("status", entities.WeChatTemplateDeliveryStatusCancelled)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to cancel WeChat template deliveries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -56,6 +56,16 @@ func (r *GormWeChatUserRepository) GetByUnionID(ctx context.Context, unionID str
 	return &user, nil
 }
 
+// GetByMobiles retrieves the WeChat users whose mobile number is one of the given numbers
+func (r *GormWeChatUserRepository) GetByMobiles(ctx context.Context, mobiles []string) ([]*entities.WeChatUser, error) {
+	var users []*entities.WeChatUser
+	if len(mobiles) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("Mobile IN ?", mobiles).Find(&users).Error
+	return users, err
+}
+
 // Update updates an existing WeChat user
 func (r *GormWeChatUserRepository) Update(ctx context.Context, user *entities.WeChatUser) error {
 	return r.db.WithContext(ctx).Save(user).Error
@@ -241,6 +251,16 @@ func (r *GormWeChatUserRepository) BulkUpdateSubscription(ctx context.Context, o
 		Update("Subscribe", subscribed).Error
 }
 
+// UnsubscribeNotUpdatedSince marks the subscribed users not updated since the given time as unsubscribed
+func (r *GormWeChatUserRepository) UnsubscribeNotUpdatedSince(ctx context.Context, since time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.WeChatUser{}).
+		Where("Subscribe = ?", true).
+		Where("LastModificationTime IS NULL OR LastModificationTime < ?", since).
+		Updates(map[string]interface{}{"Subscribe": false, "LastModificationTime": time.Now()})
+	return result.RowsAffected, result.Error
+}
+
 // BulkDelete deletes multiple users
 func (r *GormWeChatUserRepository) BulkDelete(ctx context.Context, ids []uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&entities.WeChatUser{}, "Id IN ?", ids).Error
@@ -276,6 +296,18 @@ func (r *GormWeChatUserRepository) applyFilters(query *gorm.DB, filter repositor
 		query = query.Where("Country = ?", filter.Country)
 	}
 
+	// Test user filter
+	if filter.AllowTest != nil {
+		query = query.Where("AllowTest = ?", *filter.AllowTest)
+	}
+
+	// Event check-in filter
+	if filter.CheckedInEventID != nil {
+		query = query.Where("Id IN (?)", r.db.Model(&entities.EventAttendee{}).
+			Select("UserId").
+			Where("EventId = ? AND OnSiteScanned = ? AND IsDeleted = ?", *filter.CheckedInEventID, true, false))
+	}
+
 	// Date range filters
 	if filter.SubscribeTimeStart != nil {
 		query = query.Where("SubscribeTime >= ?", *filter.SubscribeTimeStart)
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AttendeeServiceImpl implements the AttendeeService interface
+type AttendeeServiceImpl struct {
+	attendeeRepo   repositories.EventAttendeeRepository
+	eventRepo      repositories.SiteEventRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	qrCodeService  services.QRCodeService
+	logger         *zap.Logger
+}
+
+// NewAttendeeService creates a new attendee service implementation
+func NewAttendeeService(
+	attendeeRepo repositories.EventAttendeeRepository,
+	eventRepo repositories.SiteEventRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	qrCodeService services.QRCodeService,
+	logger *zap.Logger,
+) services.AttendeeService {
+	return &AttendeeServiceImpl{
+		attendeeRepo:   attendeeRepo,
+		eventRepo:      eventRepo,
+		wechatUserRepo: wechatUserRepo,
+		qrCodeService:  qrCodeService,
+		logger:         logger,
+	}
+}
+
+// RegisterAttendee registers a user for an event, placing them on the waitlist when the event is full.
+// A previously cancelled registration is reactivated rather than duplicated. The repository repeats the
+// duplicate check under the event lock, so concurrent registrations of the same user create one row.
+func (s *AttendeeServiceImpl) RegisterAttendee(ctx context.Context, registration *services.AttendeeRegistration) (*entities.EventAttendee, error) {
+	event, err := s.eventRepo.GetByID(ctx, registration.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	attendee, err := s.attendeeRepo.GetByEventAndUser(ctx, event.ID, registration.UserID)
+	switch {
+	case err == nil:
+		if !attendee.IsCancelled() {
+			return nil, services.ErrAlreadyRegistered
+		}
+		attendee.CancelledAt = nil
+		attendee.Event = nil
+		attendee.User = nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		userID := registration.UserID
+		attendee = &entities.EventAttendee{EventID: event.ID, UserID: &userID}
+	default:
+		return nil, fmt.Errorf("failed to get attendee: %w", err)
+	}
+
+	attendee.Source = registration.Source
+	attendee.Notes = registration.Notes
+	attendee.Mobile = s.registrationMobile(ctx, registration, attendee.Mobile)
+
+	if err := s.attendeeRepo.CreateWithCapacity(ctx, attendee, event.Capacity); err != nil {
+		if errors.Is(err, repositories.ErrAttendeeExists) {
+			return nil, services.ErrAlreadyRegistered
+		}
+		return nil, fmt.Errorf("failed to register attendee: %w", err)
+	}
+
+	s.logger.Info("Attendee registered",
+		zap.String("eventId", event.ID.String()),
+		zap.String("attendeeId", attendee.ID.String()),
+		zap.String("status", attendee.Status))
+
+	return attendee, nil
+}
+
+// GetAttendeeByID retrieves an attendee by ID
+func (s *AttendeeServiceImpl) GetAttendeeByID(ctx context.Context, id uuid.UUID) (*entities.EventAttendee, error) {
+	return s.attendeeRepo.GetByID(ctx, id)
+}
+
+// GetAttendeesByEvent retrieves attendees for an event with pagination
+func (s *AttendeeServiceImpl) GetAttendeesByEvent(ctx context.Context, eventID uuid.UUID, offset, limit int) ([]*entities.EventAttendee, error) {
+	return s.attendeeRepo.GetByEvent(ctx, eventID, offset, limit)
+}
+
+// GetAttendeesByUser retrieves all registrations of a user
+func (s *AttendeeServiceImpl) GetAttendeesByUser(ctx context.Context, userID uuid.UUID) ([]*entities.EventAttendee, error) {
+	return s.attendeeRepo.GetByUser(ctx, userID, 0, -1)
+}
+
+// CheckInAttendee checks a registered attendee in
+func (s *AttendeeServiceImpl) CheckInAttendee(ctx context.Context, attendeeID uuid.UUID) error {
+	_, err := s.checkIn(ctx, attendeeID, entities.CheckInMethodManual)
+	return err
+}
+
+// CheckInByQRCode checks in the attendee encoded in an attendee QR code ("attendee_<id>" or a bare ID)
+func (s *AttendeeServiceImpl) CheckInByQRCode(ctx context.Context, qrCode string) (*services.CheckInResult, error) {
+	attendeeID, err := parseAttendeeCode(qrCode)
+	if err != nil {
+		return nil, err
+	}
+
+	alreadyCheckedIn, err := s.checkIn(ctx, attendeeID, entities.CheckInMethodQRCode)
+	if err != nil {
+		if errors.Is(err, services.ErrAttendeeNotRegistered) {
+			return &services.CheckInResult{
+				Success:    false,
+				AttendeeID: attendeeID,
+				Message:    "Attendee does not hold a confirmed place",
+			}, nil
+		}
+		return nil, err
+	}
+
+	attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendee: %w", err)
+	}
+
+	message := "Checked in successfully"
+	if alreadyCheckedIn {
+		message = "Attendee has already checked in"
+	}
+	result := &services.CheckInResult{
+		Success:          true,
+		AttendeeID:       attendee.ID,
+		EventID:          attendee.EventID,
+		Message:          message,
+		AlreadyCheckedIn: alreadyCheckedIn,
+	}
+	if attendee.CheckInDate != nil {
+		result.CheckInTime = *attendee.CheckInDate
+	}
+	return result, nil
+}
+
+// GetCheckInStatus reports whether a user is registered for and checked in to an event
+func (s *AttendeeServiceImpl) GetCheckInStatus(ctx context.Context, eventID, userID uuid.UUID) (*services.CheckInStatus, error) {
+	attendee, err := s.attendeeRepo.GetByEventAndUser(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &services.CheckInStatus{}, nil
+		}
+		return nil, fmt.Errorf("failed to get attendee: %w", err)
+	}
+
+	status := attendee.Status
+	if status == "" {
+		status = entities.AttendeeStatusRegistered
+	}
+
+	return &services.CheckInStatus{
+		IsRegistered: attendee.IsRegistered(),
+		IsCheckedIn:  attendee.IsCheckedIn(),
+		CheckInTime:  attendee.CheckInDate,
+		QRCode:       entities.BuildSceneStr(entities.QRResourceAttendee, attendee.ID),
+		Status:       status,
+	}, nil
+}
+
+// UpdateAttendeeStatus changes an attendee's registration status. Cancelling or waitlisting a registered
+// attendee frees the place for the waitlist and revokes their tickets; registering is taken as-is and may
+// exceed capacity.
+func (s *AttendeeServiceImpl) UpdateAttendeeStatus(ctx context.Context, attendeeID uuid.UUID, status string) error {
+	switch status {
+	case entities.AttendeeStatusCancelled:
+		return s.CancelRegistration(ctx, attendeeID)
+	case entities.AttendeeStatusRegistered, entities.AttendeeStatusWaitlisted:
+	default:
+		return fmt.Errorf("%w: %s", services.ErrInvalidAttendeeStatus, status)
+	}
+
+	attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get attendee: %w", err)
+	}
+	if attendee.Status == status {
+		return nil
+	}
+
+	freesPlace := attendee.IsRegistered() && status == entities.AttendeeStatusWaitlisted
+	attendee.Status = status
+	attendee.CancelledAt = nil
+	attendee.Event = nil
+	attendee.User = nil
+	if err := s.attendeeRepo.Update(ctx, attendee); err != nil {
+		return fmt.Errorf("failed to update attendee: %w", err)
+	}
+	if status == entities.AttendeeStatusWaitlisted {
+		s.revokeTickets(ctx, attendee.ID)
+	}
+
+	if freesPlace {
+		return s.PromoteWaitlist(ctx, attendee.EventID)
+	}
+	return nil
+}
+
+// CancelRegistration cancels a registration, revokes the attendee's tickets and promotes the next
+// waitlisted attendee into the freed place
+func (s *AttendeeServiceImpl) CancelRegistration(ctx context.Context, attendeeID uuid.UUID) error {
+	attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get attendee: %w", err)
+	}
+	if attendee.IsCancelled() {
+		return nil
+	}
+
+	freesPlace := attendee.IsRegistered()
+	attendee.Cancel()
+	attendee.Event = nil
+	attendee.User = nil
+	if err := s.attendeeRepo.Update(ctx, attendee); err != nil {
+		return fmt.Errorf("failed to cancel registration: %w", err)
+	}
+	s.revokeTickets(ctx, attendee.ID)
+
+	s.logger.Info("Attendee registration cancelled",
+		zap.String("eventId", attendee.EventID.String()),
+		zap.String("attendeeId", attendee.ID.String()))
+
+	if freesPlace {
+		return s.PromoteWaitlist(ctx, attendee.EventID)
+	}
+	return nil
+}
+
+// GetAttendeeCount returns the number of attendees holding a confirmed place
+func (s *AttendeeServiceImpl) GetAttendeeCount(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	return s.attendeeRepo.CountRegisteredByEvent(ctx, eventID)
+}
+
+// GetAttendeeCounts returns registration, waitlist and check-in counts for an event
+func (s *AttendeeServiceImpl) GetAttendeeCounts(ctx context.Context, eventID uuid.UUID) (*services.AttendeeCounts, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	counts := &services.AttendeeCounts{EventID: eventID, Capacity: event.Capacity}
+	if counts.Registered, err = s.attendeeRepo.CountRegisteredByEvent(ctx, eventID); err != nil {
+		return nil, fmt.Errorf("failed to count registered attendees: %w", err)
+	}
+	if counts.Waitlisted, err = s.attendeeRepo.CountByEventAndStatus(ctx, eventID, entities.AttendeeStatusWaitlisted); err != nil {
+		return nil, fmt.Errorf("failed to count waitlisted attendees: %w", err)
+	}
+	if counts.Cancelled, err = s.attendeeRepo.CountByEventAndStatus(ctx, eventID, entities.AttendeeStatusCancelled); err != nil {
+		return nil, fmt.Errorf("failed to count cancelled attendees: %w", err)
+	}
+	if counts.CheckedIn, err = s.attendeeRepo.CountCheckedInByEvent(ctx, eventID); err != nil {
+		return nil, fmt.Errorf("failed to count checked-in attendees: %w", err)
+	}
+	return counts, nil
+}
+
+// PromoteWaitlist moves waitlisted attendees into free places, e.g. after a cancellation or a capacity increase
+func (s *AttendeeServiceImpl) PromoteWaitlist(ctx context.Context, eventID uuid.UUID) error {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+
+	promoted, err := s.attendeeRepo.PromoteWaitlisted(ctx, eventID, event.Capacity)
+	if err != nil {
+		return fmt.Errorf("failed to promote waitlisted attendees: %w", err)
+	}
+
+	for _, attendee := range promoted {
+		s.logger.Info("Attendee promoted from waitlist",
+			zap.String("eventId", eventID.String()),
+			zap.String("attendeeId", attendee.ID.String()))
+	}
+	return nil
+}
+
+// Helper methods
+
+// checkIn checks a registered attendee in and reports whether they had already been checked in
+func (s *AttendeeServiceImpl) checkIn(ctx context.Context, attendeeID uuid.UUID, method string) (bool, error) {
+	attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get attendee: %w", err)
+	}
+	if !attendee.IsRegistered() {
+		return false, services.ErrAttendeeNotRegistered
+	}
+	if attendee.IsCheckedIn() {
+		return true, nil
+	}
+
+	attendee.CheckIn(method)
+	attendee.Event = nil
+	attendee.User = nil
+	if err := s.attendeeRepo.Update(ctx, attendee); err != nil {
+		return false, fmt.Errorf("failed to check in attendee: %w", err)
+	}
+	return false, nil
+}
+
+// revokeTickets stops the tickets already issued to an attendee who lost their place from opening the door
+func (s *AttendeeServiceImpl) revokeTickets(ctx context.Context, attendeeID uuid.UUID) {
+	if s.qrCodeService == nil {
+		return
+	}
+	if err := s.qrCodeService.DeactivateQRCode(ctx, attendeeID.String()); err != nil {
+		s.logger.Warn("Failed to revoke attendee tickets",
+			zap.String("attendeeId", attendeeID.String()),
+			zap.Error(err))
+	}
+}
+
+// registrationMobile picks the mobile number from the registration data, falling back to the WeChat profile
+func (s *AttendeeServiceImpl) registrationMobile(ctx context.Context, registration *services.AttendeeRegistration, current string) string {
+	if mobile, ok := registration.SourceData["mobile"].(string); ok && mobile != "" {
+		return mobile
+	}
+	if current != "" || s.wechatUserRepo == nil {
+		return current
+	}
+
+	user, err := s.wechatUserRepo.GetByID(ctx, registration.UserID)
+	if err != nil || user.Mobile == nil {
+		return current
+	}
+	return *user.Mobile
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryEventRepository serves events from memory; unused methods panic via the embedded interface
+type memoryEventRepository struct {
+	repositories.SiteEventRepository
+	events map[uuid.UUID]*entities.SiteEvent
+}
+
+func (r *memoryEventRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.SiteEvent, error) {
+	event, ok := r.events[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *event
+	return &copied, nil
+}
+
+// memoryAttendeeRepository keeps attendees in memory in registration order
+type memoryAttendeeRepository struct {
+	repositories.EventAttendeeRepository
+	attendees []*entities.EventAttendee
+}
+
+func (r *memoryAttendeeRepository) find(match func(*entities.EventAttendee) bool) (*entities.EventAttendee, error) {
+	for _, attendee := range r.attendees {
+		if match(attendee) {
+			copied := *attendee
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAttendeeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.EventAttendee, error) {
+	return r.find(func(a *entities.EventAttendee) bool { return a.ID == id })
+}
+
+func (r *memoryAttendeeRepository) GetByEventAndUser(ctx context.Context, eventID, userID uuid.UUID) (*entities.EventAttendee, error) {
+	return r.find(func(a *entities.EventAttendee) bool {
+		return a.EventID == eventID && a.UserID != nil && *a.UserID == userID
+	})
+}
+
+func (r *memoryAttendeeRepository) Update(ctx context.Context, attendee *entities.EventAttendee) error {
+	for i, existing := range r.attendees {
+		if existing.ID == attendee.ID {
+			now := time.Now()
+			copied := *attendee
+			copied.UpdatedAt = &now
+			r.attendees[i] = &copied
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryAttendeeRepository) CountRegisteredByEvent(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	var count int64
+	for _, attendee := range r.attendees {
+		if attendee.EventID == eventID && attendee.IsRegistered() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryAttendeeRepository) CreateWithCapacity(ctx context.Context, attendee *entities.EventAttendee, capacity int) error {
+	if attendee.UserID != nil {
+		if existing, err := r.GetByEventAndUser(ctx, attendee.EventID, *attendee.UserID); err == nil && !existing.IsCancelled() {
+			return repositories.ErrAttendeeExists
+		}
+	}
+	registered, _ := r.CountRegisteredByEvent(ctx, attendee.EventID)
+	attendee.Status = entities.AttendeeStatusRegistered
+	if capacity > 0 && registered >= int64(capacity) {
+		attendee.Status = entities.AttendeeStatusWaitlisted
+	}
+	if attendee.ID != uuid.Nil {
+		return r.Update(ctx, attendee)
+	}
+	attendee.ID = uuid.New()
+	copied := *attendee
+	r.attendees = append(r.attendees, &copied)
+	return nil
+}
+
+func (r *memoryAttendeeRepository) PromoteWaitlisted(ctx context.Context, eventID uuid.UUID, capacity int) ([]*entities.EventAttendee, error) {
+	registered, _ := r.CountRegisteredByEvent(ctx, eventID)
+	var waitlisted []*entities.EventAttendee
+	for _, attendee := range r.attendees {
+		if attendee.EventID == eventID && attendee.IsWaitlisted() {
+			waitlisted = append(waitlisted, attendee)
+		}
+	}
+	// Attendees are modified when they join the waitlist, like in the GORM repository
+	sort.SliceStable(waitlisted, func(i, j int) bool {
+		return modifiedAt(waitlisted[i]).Before(modifiedAt(waitlisted[j]))
+	})
+
+	var promoted []*entities.EventAttendee
+	for _, attendee := range waitlisted {
+		if capacity > 0 && registered >= int64(capacity) {
+			break
+		}
+		attendee.Status = entities.AttendeeStatusRegistered
+		promoted = append(promoted, attendee)
+		registered++
+	}
+	return promoted, nil
+}
+
+func modifiedAt(attendee *entities.EventAttendee) time.Time {
+	if attendee.UpdatedAt == nil {
+		return time.Time{}
+	}
+	return *attendee.UpdatedAt
+}
+
+type AttendeeServiceTestSuite struct {
+	suite.Suite
+	event        *entities.SiteEvent
+	attendeeRepo *memoryAttendeeRepository
+	qrCodes      services.QRCodeService
+	service      services.AttendeeService
+}
+
+func (suite *AttendeeServiceTestSuite) SetupTest() {
+	suite.event = &entities.SiteEvent{ID: uuid.New(), EventTitle: "Workshop", Capacity: 2, EventEndDate: time.Now().Add(48 * time.Hour)}
+	suite.attendeeRepo = &memoryAttendeeRepository{}
+	eventRepo := &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{suite.event.ID: suite.event}}
+
+	signer, err := NewTicketSignerFromConfig("dGVzdC10aWNrZXQtc2lnbmluZy1rZXktMzItYnl0ZXM=")
+	suite.Require().NoError(err)
+	suite.qrCodes = NewQRCodeService(eventRepo, suite.attendeeRepo, signer, nil, &QRCodeServiceConfig{TicketGraceHours: 12}, zap.NewNop())
+	suite.service = NewAttendeeService(suite.attendeeRepo, eventRepo, nil, suite.qrCodes, zap.NewNop())
+}
+
+func (suite *AttendeeServiceTestSuite) register() *entities.EventAttendee {
+	attendee, err := suite.service.RegisterAttendee(context.Background(), &services.AttendeeRegistration{
+		EventID: suite.event.ID,
+		UserID:  uuid.New(),
+		Source:  "web",
+	})
+	suite.Require().NoError(err)
+	return attendee
+}
+
+func (suite *AttendeeServiceTestSuite) statuses() []string {
+	var statuses []string
+	for _, attendee := range suite.attendeeRepo.attendees {
+		statuses = append(statuses, attendee.Status)
+	}
+	return statuses
+}
+
+func (suite *AttendeeServiceTestSuite) TestWaitlistWhenFull() {
+	suite.Equal(entities.AttendeeStatusRegistered, suite.register().Status)
+	suite.Equal(entities.AttendeeStatusRegistered, suite.register().Status)
+	suite.Equal(entities.AttendeeStatusWaitlisted, suite.register().Status)
+
+	count, err := suite.service.GetAttendeeCount(context.Background(), suite.event.ID)
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), count)
+}
+
+func (suite *AttendeeServiceTestSuite) TestCancelPromotesWaitlist() {
+	ctx := context.Background()
+	first := suite.register()
+	suite.register()
+	waitlisted := suite.register()
+	suite.register()
+
+	suite.Require().NoError(suite.service.CancelRegistration(ctx, first.ID))
+
+	promoted, err := suite.service.GetAttendeeByID(ctx, waitlisted.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.AttendeeStatusRegistered, promoted.Status)
+
+	statuses := suite.statuses()
+	sort.Strings(statuses)
+	suite.Equal([]string{
+		entities.AttendeeStatusCancelled,
+		entities.AttendeeStatusRegistered,
+		entities.AttendeeStatusRegistered,
+		entities.AttendeeStatusWaitlisted,
+	}, statuses)
+}
+
+func (suite *AttendeeServiceTestSuite) TestWaitlistingPromotesNext() {
+	ctx := context.Background()
+	first := suite.register()
+	suite.register()
+	waitlisted := suite.register()
+
+	suite.Require().NoError(suite.service.UpdateAttendeeStatus(ctx, first.ID, entities.AttendeeStatusWaitlisted))
+
+	promoted, err := suite.service.GetAttendeeByID(ctx, waitlisted.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.AttendeeStatusRegistered, promoted.Status)
+	demoted, err := suite.service.GetAttendeeByID(ctx, first.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.AttendeeStatusWaitlisted, demoted.Status)
+}
+
+func (suite *AttendeeServiceTestSuite) TestLosingPlaceRevokesTickets() {
+	ctx := context.Background()
+	cancelled := suite.register()
+	waitlisted := suite.register()
+
+	var tickets []string
+	for _, attendee := range []*entities.EventAttendee{cancelled, waitlisted} {
+		ticket, err := suite.qrCodes.GenerateAttendeeQRCode(ctx, attendee.ID)
+		suite.Require().NoError(err)
+		tickets = append(tickets, ticket.Code)
+	}
+
+	suite.Require().NoError(suite.service.CancelRegistration(ctx, cancelled.ID))
+	suite.Require().NoError(suite.service.UpdateAttendeeStatus(ctx, waitlisted.ID, entities.AttendeeStatusWaitlisted))
+
+	for _, ticket := range tickets {
+		validation, err := suite.qrCodes.ValidateQRCode(ctx, ticket)
+		suite.Require().NoError(err)
+		suite.False(validation.IsValid)
+		suite.Equal(ticketRevokedMessage, validation.Message)
+	}
+}
+
+func (suite *AttendeeServiceTestSuite) TestDuplicateAndReRegistration() {
+	ctx := context.Background()
+	attendee := suite.register()
+	registration := &services.AttendeeRegistration{EventID: suite.event.ID, UserID: *attendee.UserID}
+
+	_, err := suite.service.RegisterAttendee(ctx, registration)
+	suite.ErrorIs(err, services.ErrAlreadyRegistered)
+
+	suite.Require().NoError(suite.service.CancelRegistration(ctx, attendee.ID))
+	again, err := suite.service.RegisterAttendee(ctx, registration)
+	suite.Require().NoError(err)
+	suite.Equal(attendee.ID, again.ID)
+	suite.Equal(entities.AttendeeStatusRegistered, again.Status)
+	suite.Nil(again.CancelledAt)
+}
+
+func (suite *AttendeeServiceTestSuite) TestCheckInRequiresPlace() {
+	ctx := context.Background()
+	suite.register()
+	suite.register()
+	waitlisted := suite.register()
+
+	suite.ErrorIs(suite.service.CheckInAttendee(ctx, waitlisted.ID), services.ErrAttendeeNotRegistered)
+
+	registered := suite.attendeeRepo.attendees[0]
+	result, err := suite.service.CheckInByQRCode(ctx, entities.BuildSceneStr(entities.QRResourceAttendee, registered.ID))
+	suite.Require().NoError(err)
+	suite.True(result.Success)
+	suite.False(result.AlreadyCheckedIn)
+
+	result, err = suite.service.CheckInByQRCode(ctx, registered.ID.String())
+	suite.Require().NoError(err)
+	suite.True(result.AlreadyCheckedIn)
+}
+
+func TestAttendeeServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AttendeeServiceTestSuite))
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxImportRows caps the number of data rows accepted in one attendee file
+	maxImportRows = 10000
+	// importSource marks attendees registered through a bulk import
+	importSource = "import"
+
+	exportTimeLayout = "2006-01-02 15:04:05"
+)
+
+// utf8BOM makes spreadsheet applications read exported CSV files as UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// mobilePattern accepts mainland mobile numbers and international numbers with a country code
+var mobilePattern = regexp.MustCompile(`^(1\d{10}|\+\d{8,15})$`)
+
+// importColumnAliases maps accepted header names to attendee fields
+var importColumnAliases = map[string]string{
+	"mobile":       "mobile",
+	"mobile phone": "mobile",
+	"phone":        "mobile",
+	"phone number": "mobile",
+	"手机":           "mobile",
+	"手机号":          "mobile",
+	"手机号码":         "mobile",
+	"name":         "name",
+	"full name":    "name",
+	"姓名":           "name",
+	"company":      "company",
+	"organization": "company",
+	"公司":           "company",
+	"单位":           "company",
+	"notes":        "notes",
+	"备注":           "notes",
+}
+
+// attendanceExportHeader is the header row of attendance exports
+var attendanceExportHeader = []string{
+	"Name", "Mobile", "Company", "Email", "Status", "Source", "Registered At", "Checked In", "Checked In At",
+}
+
+// AttendeeTransferServiceImpl implements the AttendeeTransferService interface
+type AttendeeTransferServiceImpl struct {
+	eventService   services.EventService
+	attendeeRepo   repositories.EventAttendeeRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	logger         *zap.Logger
+}
+
+// NewAttendeeTransferService creates a new attendee import/export service implementation
+func NewAttendeeTransferService(
+	eventService services.EventService,
+	attendeeRepo repositories.EventAttendeeRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	logger *zap.Logger,
+) services.AttendeeTransferService {
+	return &AttendeeTransferServiceImpl{
+		eventService:   eventService,
+		attendeeRepo:   attendeeRepo,
+		wechatUserRepo: wechatUserRepo,
+		logger:         logger,
+	}
+}
+
+// importRow is a validated data row waiting to be registered
+type importRow struct {
+	result *services.AttendeeImportRow
+	notes  string
+}
+
+// ImportAttendees validates every row of an attendee list, skips mobiles already registered for the
+// event (or repeated in the file) and links rows to WeChat followers by mobile. Rows beyond the
+// event's capacity are waitlisted. With dryRun nothing is written.
+func (s *AttendeeTransferServiceImpl) ImportAttendees(ctx context.Context, eventID uuid.UUID, file io.Reader, format string, dryRun bool) (*services.AttendeeImportResult, error) {
+	event, err := s.eventService.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	records, err := readAttendeeRecords(file, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", services.ErrInvalidAttendeeFile)
+	}
+	if len(records)-1 > maxImportRows {
+		return nil, fmt.Errorf("%w: more than %d rows", services.ErrInvalidAttendeeFile, maxImportRows)
+	}
+	columns, err := mapImportColumns(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &services.AttendeeImportResult{EventID: eventID, DryRun: dryRun}
+	var rows []*importRow
+	for i, record := range records[1:] {
+		row := parseImportRow(i+2, record, columns)
+		if row == nil {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	result.TotalRows = len(rows)
+
+	users, err := s.matchWeChatUsers(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.attendeeRepo.GetByEvent(ctx, eventID, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendees: %w", err)
+	}
+	registeredMobiles := make(map[string]bool, len(existing))
+	registeredUsers := make(map[uuid.UUID]bool, len(existing))
+	for _, attendee := range existing {
+		if attendee.Mobile != "" {
+			registeredMobiles[normalizeMobile(attendee.Mobile)] = true
+		}
+		if attendee.UserID != nil {
+			registeredUsers[*attendee.UserID] = true
+		}
+	}
+
+	registered, err := s.attendeeRepo.CountRegisteredByEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count registered attendees: %w", err)
+	}
+
+	seenInFile := make(map[string]int)
+	var attendees []*entities.EventAttendee
+	var createdRows []int // Index in result.Rows of the row each attendee comes from
+	for _, row := range rows {
+		line := row.result
+		if line.Outcome == services.ImportRowInvalid {
+			result.Invalid++
+			result.Rows = append(result.Rows, *line)
+			continue
+		}
+
+		user := users[line.Mobile]
+		switch {
+		case registeredMobiles[line.Mobile]:
+			line.Outcome = services.ImportRowDuplicate
+			line.Errors = append(line.Errors, "mobile is already registered for this event")
+		case seenInFile[line.Mobile] > 0:
+			line.Outcome = services.ImportRowDuplicate
+			line.Errors = append(line.Errors, fmt.Sprintf("mobile repeats row %d", seenInFile[line.Mobile]))
+		case user != nil && registeredUsers[user.ID]:
+			line.Outcome = services.ImportRowDuplicate
+			line.Errors = append(line.Errors, "WeChat user is already registered for this event")
+		}
+		if line.Outcome == services.ImportRowDuplicate {
+			result.Duplicates++
+			result.Rows = append(result.Rows, *line)
+			continue
+		}
+		seenInFile[line.Mobile] = line.Row
+
+		attendee := &entities.EventAttendee{
+			EventID: eventID,
+			Mobile:  line.Mobile,
+			Name:    line.Name,
+			Company: line.Company,
+			Notes:   row.notes,
+			Status:  entities.AttendeeStatusRegistered,
+			Source:  importSource,
+		}
+		if user != nil {
+			userID := user.ID
+			attendee.UserID = &userID
+			line.UserID = &userID
+			registeredUsers[userID] = true
+			result.Matched++
+		}
+		if event.HasCapacityLimit() && registered >= int64(event.Capacity) {
+			attendee.Status = entities.AttendeeStatusWaitlisted
+			result.Waitlisted++
+		} else {
+			registered++
+		}
+
+		line.Outcome = services.ImportRowCreated
+		line.AttendeeStatus = attendee.Status
+		result.Created++
+		createdRows = append(createdRows, len(result.Rows))
+		result.Rows = append(result.Rows, *line)
+		attendees = append(attendees, attendee)
+	}
+
+	if !dryRun {
+		// Places are assigned again under the event lock, as registrations may have come in meanwhile
+		if err := s.attendeeRepo.CreateBatchWithCapacity(ctx, attendees, event.Capacity); err != nil {
+			return nil, fmt.Errorf("failed to import attendees: %w", err)
+		}
+		result.Waitlisted = 0
+		for i, attendee := range attendees {
+			result.Rows[createdRows[i]].AttendeeStatus = attendee.Status
+			if attendee.Status == entities.AttendeeStatusWaitlisted {
+				result.Waitlisted++
+			}
+		}
+	}
+
+	s.logger.Info("Attendee list imported",
+		zap.String("eventId", eventID.String()),
+		zap.Bool("dryRun", dryRun),
+		zap.Int("rows", result.TotalRows),
+		zap.Int("created", result.Created),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("invalid", result.Invalid))
+
+	return result, nil
+}
+
+// ExportAttendance writes the event's attendance report as CSV or XLSX
+func (s *AttendeeTransferServiceImpl) ExportAttendance(ctx context.Context, eventID uuid.UUID, format string, w io.Writer) error {
+	if format != services.AttendeeFileFormatCSV && format != services.AttendeeFileFormatXLSX {
+		return fmt.Errorf("%w: %s", services.ErrUnsupportedFileFormat, format)
+	}
+
+	report, err := s.eventService.GetEventAttendanceReport(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(report.Attendees)+1)
+	rows = append(rows, attendanceExportHeader)
+	for _, attendee := range report.Attendees {
+		checkedIn, checkedInAt := "No", ""
+		if attendee.CheckedInAt != nil {
+			checkedIn, checkedInAt = "Yes", attendee.CheckedInAt.Format(exportTimeLayout)
+		}
+		rows = append(rows, []string{
+			attendee.UserName,
+			attendee.PhoneNumber,
+			attendee.Company,
+			attendee.Email,
+			attendee.Status,
+			attendee.Source,
+			attendee.RegisteredAt.Format(exportTimeLayout),
+			checkedIn,
+			checkedInAt,
+		})
+	}
+
+	if format == services.AttendeeFileFormatCSV {
+		return writeAttendanceCSV(w, rows)
+	}
+	return writeAttendanceXLSX(w, report, rows)
+}
+
+// matchWeChatUsers finds the WeChat followers of the valid rows by mobile
+func (s *AttendeeTransferServiceImpl) matchWeChatUsers(ctx context.Context, rows []*importRow) (map[string]*entities.WeChatUser, error) {
+	users := make(map[string]*entities.WeChatUser)
+	if s.wechatUserRepo == nil {
+		return users, nil
+	}
+
+	var mobiles []string
+	for _, row := range rows {
+		if row.result.Outcome != services.ImportRowInvalid {
+			mobiles = append(mobiles, row.result.Mobile)
+		}
+	}
+
+	found, err := s.wechatUserRepo.GetByMobiles(ctx, mobiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match WeChat users: %w", err)
+	}
+	for _, user := range found {
+		if user.Mobile == nil {
+			continue
+		}
+		mobile := normalizeMobile(*user.Mobile)
+		if _, ok := users[mobile]; !ok {
+			users[mobile] = user
+		}
+	}
+	return users, nil
+}
+
+// readAttendeeRecords reads all rows of a CSV or XLSX attendee file
+func readAttendeeRecords(file io.Reader, format string) ([][]string, error) {
+	switch format {
+	case services.AttendeeFileFormatCSV:
+		reader := bufio.NewReader(file)
+		if bom, err := reader.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+			reader.Discard(len(utf8BOM))
+		}
+		csvReader := csv.NewReader(reader)
+		csvReader.FieldsPerRecord = -1
+		csvReader.TrimLeadingSpace = true
+		records, err := csvReader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", services.ErrInvalidAttendeeFile, err)
+		}
+		return records, nil
+	case services.AttendeeFileFormatXLSX:
+		workbook, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", services.ErrInvalidAttendeeFile, err)
+		}
+		defer workbook.Close()
+		records, err := workbook.GetRows(workbook.GetSheetName(0))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", services.ErrInvalidAttendeeFile, err)
+		}
+		return records, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", services.ErrUnsupportedFileFormat, format)
+	}
+}
+
+// mapImportColumns finds the column index of each attendee field from the header row
+func mapImportColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		field, ok := importColumnAliases[strings.ToLower(strings.TrimSpace(name))]
+		if _, taken := columns[field]; ok && !taken {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["mobile"]; !ok {
+		return nil, fmt.Errorf("%w: no mobile column in the header row", services.ErrInvalidAttendeeFile)
+	}
+	return columns, nil
+}
+
+// parseImportRow validates one data row; it returns nil for blank rows
+func parseImportRow(line int, record []string, columns map[string]int) *importRow {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rawMobile, name, company, notes := field("mobile"), field("name"), field("company"), field("notes")
+	if rawMobile == "" && name == "" && company == "" && notes == "" {
+		return nil
+	}
+
+	result := &services.AttendeeImportRow{
+		Row:     line,
+		Mobile:  normalizeMobile(rawMobile),
+		Name:    name,
+		Company: company,
+	}
+	switch {
+	case rawMobile == "":
+		result.Errors = append(result.Errors, "mobile is required")
+	case !mobilePattern.MatchString(result.Mobile):
+		result.Errors = append(result.Errors, fmt.Sprintf("mobile %q is not a valid mobile number", rawMobile))
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		result.Errors = append(result.Errors, "name must be at most 100 characters")
+	}
+	if utf8.RuneCountInString(company) > 200 {
+		result.Errors = append(result.Errors, "company must be at most 200 characters")
+	}
+	if len(result.Errors) > 0 {
+		result.Outcome = services.ImportRowInvalid
+	}
+
+	return &importRow{result: result, notes: notes}
+}
+
+// normalizeMobile strips separators and the mainland country code so the same number always compares equal
+func normalizeMobile(mobile string) string {
+	normalized := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '\t':
+			return -1
+		}
+		return r
+	}, mobile)
+
+	for _, prefix := range []string{"+86", "0086"} {
+		if rest := strings.TrimPrefix(normalized, prefix); rest != normalized && len(rest) == 11 {
+			return rest
+		}
+	}
+	return normalized
+}
+
+// writeAttendanceCSV writes the export rows as UTF-8 CSV
+func writeAttendanceCSV(w io.Writer, rows [][]string) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+	escaped := make([][]string, len(rows))
+	for i, row := range rows {
+		escaped[i] = make([]string, len(row))
+		for j, value := range row {
+			escaped[i][j] = escapeCSVFormula(value)
+		}
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(escaped); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// escapeCSVFormula prefixes values spreadsheets would run as formulas with a quote, so attendee
+// supplied names and companies open as text
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// writeAttendanceXLSX writes the export rows to an Attendance sheet, with the report totals on a Summary sheet
+func writeAttendanceXLSX(w io.Writer, report *services.AttendanceReport, rows [][]string) error {
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	const attendanceSheet, summarySheet = "Attendance", "Summary"
+	if err := workbook.SetSheetName(workbook.GetSheetName(0), attendanceSheet); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	for i, row := range rows {
+		cells := make([]interface{}, len(row))
+		for j, value := range row {
+			cells[j] = value
+		}
+		if err := workbook.SetSheetRow(attendanceSheet, "A"+strconv.Itoa(i+1), &cells); err != nil {
+			return fmt.Errorf("failed to write XLSX: %w", err)
+		}
+	}
+
+	if _, err := workbook.NewSheet(summarySheet); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	summary := [][]interface{}{
+		{"Event", report.EventTitle},
+		{"Capacity", report.TotalCapacity},
+		{"Registered", report.TotalRegistered},
+		{"Checked In", report.TotalCheckedIn},
+		{"Check-in Rate (%)", fmt.Sprintf("%.1f", report.CheckInRate)},
+	}
+	for i, row := range summary {
+		if err := workbook.SetSheetRow(summarySheet, "A"+strconv.Itoa(i+1), &row); err != nil {
+			return fmt.Errorf("failed to write XLSX: %w", err)
+		}
+	}
+
+	if _, err := workbook.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/xuri/excelize/v2"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+func (r *memoryAttendeeRepository) GetByEvent(ctx context.Context, eventID uuid.UUID, offset, limit int) ([]*entities.EventAttendee, error) {
+	var attendees []*entities.EventAttendee
+	for _, attendee := range r.attendees {
+		if attendee.EventID == eventID {
+			copied := *attendee
+			attendees = append(attendees, &copied)
+		}
+	}
+	return attendees, nil
+}
+
+func (r *memoryAttendeeRepository) CreateBatchWithCapacity(ctx context.Context, attendees []*entities.EventAttendee, capacity int) error {
+	for _, attendee := range attendees {
+		registered, _ := r.CountRegisteredByEvent(ctx, attendee.EventID)
+		attendee.Status = entities.AttendeeStatusRegistered
+		if capacity > 0 && registered >= int64(capacity) {
+			attendee.Status = entities.AttendeeStatusWaitlisted
+		}
+		attendee.ID = uuid.New()
+		attendee.CreatedAt = time.Now()
+		copied := *attendee
+		r.attendees = append(r.attendees, &copied)
+	}
+	return nil
+}
+
+// memoryWeChatUserRepository serves WeChat followers from memory
+type memoryWeChatUserRepository struct {
+	repositories.WeChatUserRepository
+	users    []*entities.WeChatUser
+	checkIns map[uuid.UUID][]uuid.UUID // Followers who checked in on site, by event
+}
+
+func (r *memoryWeChatUserRepository) GetByMobiles(ctx context.Context, mobiles []string) ([]*entities.WeChatUser, error) {
+	var users []*entities.WeChatUser
+	for _, user := range r.users {
+		for _, mobile := range mobiles {
+			if user.Mobile != nil && *user.Mobile == mobile {
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryWeChatUserRepository) GetAll(ctx context.Context, filter repositories.WeChatUserFilter) ([]*entities.WeChatUser, error) {
+	var users []*entities.WeChatUser
+	for _, user := range r.users {
+		if filter.Subscribe != nil && user.Subscribe != *filter.Subscribe {
+			continue
+		}
+		if filter.City != "" && (user.City == nil || *user.City != filter.City) {
+			continue
+		}
+		if filter.AllowTest != nil && user.AllowTest != *filter.AllowTest {
+			continue
+		}
+		if filter.CheckedInEventID != nil && !slices.Contains(r.checkIns[*filter.CheckedInEventID], user.ID) {
+			continue
+		}
+		users = append(users, user)
+	}
+	if filter.Offset >= len(users) {
+		return nil, nil
+	}
+	users = users[filter.Offset:]
+	if filter.Limit > 0 && len(users) > filter.Limit {
+		users = users[:filter.Limit]
+	}
+	return users, nil
+}
+
+type AttendeeTransferServiceTestSuite struct {
+	suite.Suite
+	event        *entities.SiteEvent
+	follower     *entities.WeChatUser
+	attendeeRepo *memoryAttendeeRepository
+	service      services.AttendeeTransferService
+}
+
+func (suite *AttendeeTransferServiceTestSuite) SetupTest() {
+	mobile := "13800000002"
+	suite.event = &entities.SiteEvent{ID: uuid.New(), EventTitle: "Expo", Capacity: 3}
+	suite.follower = &entities.WeChatUser{ID: uuid.New(), NickName: "follower", Mobile: &mobile}
+	suite.attendeeRepo = &memoryAttendeeRepository{attendees: []*entities.EventAttendee{
+		{ID: uuid.New(), EventID: suite.event.ID, Mobile: "13800000001", Status: entities.AttendeeStatusRegistered},
+	}}
+	eventRepo := &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{suite.event.ID: suite.event}}
+	userRepo := &memoryWeChatUserRepository{users: []*entities.WeChatUser{suite.follower}}
+
+	eventService := NewEventService(eventRepo, nil, suite.attendeeRepo, zap.NewNop(), nil)
+	suite.service = NewAttendeeTransferService(eventService, suite.attendeeRepo, userRepo, zap.NewNop())
+}
+
+const attendeeList = "\xEF\xBB\xBF手机号,姓名,公司\n" +
+	"138 0000 0001,Existing,Acme\n" +
+	"+86 138-0000-0002,Follower,Acme\n" +
+	"12345,Bad Mobile,Acme\n" +
+	",,\n" +
+	"13800000003,Third,Beta\n" +
+	"13800000003,Third Again,Beta\n" +
+	"13800000004,Fourth,Gamma\n"
+
+func (suite *AttendeeTransferServiceTestSuite) TestImportDryRunAndCommit() {
+	ctx := context.Background()
+
+	preview, err := suite.service.ImportAttendees(ctx, suite.event.ID, strings.NewReader(attendeeList), services.AttendeeFileFormatCSV, true)
+	suite.Require().NoError(err)
+	suite.Equal(6, preview.TotalRows)
+	suite.Equal(3, preview.Created)
+	suite.Equal(2, preview.Duplicates)
+	suite.Equal(1, preview.Invalid)
+	suite.Equal(1, preview.Matched)
+	suite.Equal(1, preview.Waitlisted)
+	suite.Len(suite.attendeeRepo.attendees, 1, "a dry run must not register anyone")
+
+	outcomes := make(map[int]string)
+	for _, row := range preview.Rows {
+		outcomes[row.Row] = row.Outcome
+	}
+	suite.Equal(map[int]string{
+		2: services.ImportRowDuplicate,
+		3: services.ImportRowCreated,
+		4: services.ImportRowInvalid,
+		6: services.ImportRowCreated,
+		7: services.ImportRowDuplicate,
+		8: services.ImportRowCreated,
+	}, outcomes)
+	suite.Equal(suite.follower.ID, *preview.Rows[1].UserID)
+	suite.Equal(entities.AttendeeStatusWaitlisted, preview.Rows[5].AttendeeStatus)
+
+	result, err := suite.service.ImportAttendees(ctx, suite.event.ID, strings.NewReader(attendeeList), services.AttendeeFileFormatCSV, false)
+	suite.Require().NoError(err)
+	suite.Equal(3, result.Created)
+	suite.Len(suite.attendeeRepo.attendees, 4)
+
+	// Importing the same list again only finds duplicates
+	again, err := suite.service.ImportAttendees(ctx, suite.event.ID, strings.NewReader(attendeeList), services.AttendeeFileFormatCSV, false)
+	suite.Require().NoError(err)
+	suite.Equal(0, again.Created)
+	suite.Equal(5, again.Duplicates)
+}
+
+func (suite *AttendeeTransferServiceTestSuite) TestImportRespectsRegistrationsSincePreview() {
+	ctx := context.Background()
+
+	preview, err := suite.service.ImportAttendees(ctx, suite.event.ID, strings.NewReader(attendeeList), services.AttendeeFileFormatCSV, true)
+	suite.Require().NoError(err)
+	suite.Equal(1, preview.Waitlisted)
+
+	// A self-registration takes one of the places the preview counted on
+	suite.Require().NoError(suite.attendeeRepo.CreateWithCapacity(ctx, &entities.EventAttendee{EventID: suite.event.ID, Mobile: "13900000000"}, suite.event.Capacity))
+
+	result, err := suite.service.ImportAttendees(ctx, suite.event.ID, strings.NewReader(attendeeList), services.AttendeeFileFormatCSV, false)
+	suite.Require().NoError(err)
+	suite.Equal(3, result.Created)
+	suite.Equal(2, result.Waitlisted)
+	suite.Equal(entities.AttendeeStatusWaitlisted, result.Rows[3].AttendeeStatus)
+
+	registered, _ := suite.attendeeRepo.CountRegisteredByEvent(ctx, suite.event.ID)
+	suite.Equal(int64(suite.event.Capacity), registered)
+}
+
+func (suite *AttendeeTransferServiceTestSuite) TestImportRequiresMobileColumn() {
+	_, err := suite.service.ImportAttendees(context.Background(), suite.event.ID, strings.NewReader("name,company\nA,B\n"), services.AttendeeFileFormatCSV, true)
+	suite.ErrorIs(err, services.ErrInvalidAttendeeFile)
+
+	_, err = suite.service.ImportAttendees(context.Background(), suite.event.ID, strings.NewReader(""), "pdf", true)
+	suite.ErrorIs(err, services.ErrUnsupportedFileFormat)
+}
+
+func (suite *AttendeeTransferServiceTestSuite) TestExport() {
+	ctx := context.Background()
+	suite.follower.NickName = "=HYPERLINK(\"http://evil\")"
+	checkedIn := time.Date(2026, 5, 1, 9, 30, 0, 0, time.Local)
+	suite.attendeeRepo.attendees = append(suite.attendeeRepo.attendees, &entities.EventAttendee{
+		ID: uuid.New(), EventID: suite.event.ID, UserID: &suite.follower.ID, User: suite.follower,
+		OnSiteScanned: true, CheckInDate: &checkedIn, Source: "wechat",
+	})
+
+	var buf bytes.Buffer
+	suite.Require().NoError(suite.service.ExportAttendance(ctx, suite.event.ID, services.AttendeeFileFormatCSV, &buf))
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(buf.Bytes(), utf8BOM))).ReadAll()
+	suite.Require().NoError(err)
+	suite.Len(records, 3)
+	suite.Equal(attendanceExportHeader, records[0])
+	suite.Equal([]string{"'=HYPERLINK(\"http://evil\")", "13800000002", "", "", "registered", "wechat"}, records[2][:6])
+	suite.Equal([]string{"Yes", "2026-05-01 09:30:00"}, records[2][7:])
+
+	buf.Reset()
+	suite.Require().NoError(suite.service.ExportAttendance(ctx, suite.event.ID, services.AttendeeFileFormatXLSX, &buf))
+	workbook, err := excelize.OpenReader(&buf)
+	suite.Require().NoError(err)
+	defer workbook.Close()
+	rows, err := workbook.GetRows("Attendance")
+	suite.Require().NoError(err)
+	suite.Len(rows, 3)
+	rate, err := workbook.GetCellValue("Summary", "B5")
+	suite.Require().NoError(err)
+	suite.Equal("50.0", rate)
+}
+
+func TestAttendeeTransferServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AttendeeTransferServiceTestSuite))
+}
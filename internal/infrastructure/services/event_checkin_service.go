@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	checkInSuccessMessage   = "签到成功：%s"
+	alreadyCheckedInMessage = "您已签到：%s"
+	invalidQRCodeMessage    = "该二维码已失效，请联系现场工作人员。"
+	notOwnedQRCodeMessage   = "该签到码属于其他参会者，请使用您本人的签到码。"
+	notRegisteredMessage    = "您暂未获得本次活动的参会名额，请联系现场工作人员。"
+)
+
+var (
+	// ErrQRCodeNotUsable is returned when the scanned QR code is expired or revoked
+	ErrQRCodeNotUsable = errors.New("QR code is expired or revoked")
+	// ErrUnsupportedQRCode is returned when the scene string does not encode an event or attendee
+	ErrUnsupportedQRCode = errors.New("QR code does not encode an event or attendee")
+	// ErrQRCodeNotRecorded is returned when the scanned QR code was not issued by the system
+	ErrQRCodeNotRecorded = errors.New("QR code was not issued by the system")
+	// ErrAttendeeCodeNotOwned is returned when a follower scans the personal code of another attendee
+	ErrAttendeeCodeNotOwned = errors.New("attendee QR code belongs to another user")
+)
+
+// EventCheckInService checks attendees in when they scan an event or attendee QR code in WeChat
+type EventCheckInService struct {
+	qrCodeRepo     repositories.WeChatQrCodeRepository
+	qrService      *WeChatQRService
+	eventRepo      repositories.SiteEventRepository
+	attendeeRepo   repositories.EventAttendeeRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	newsRepo       repositories.NewsRepository
+	logger         *zap.Logger
+}
+
+// NewEventCheckInService creates a new QR-scan check-in service
+func NewEventCheckInService(
+	qrCodeRepo repositories.WeChatQrCodeRepository,
+	qrService *WeChatQRService,
+	eventRepo repositories.SiteEventRepository,
+	attendeeRepo repositories.EventAttendeeRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	newsRepo repositories.NewsRepository,
+	logger *zap.Logger,
+) *EventCheckInService {
+	return &EventCheckInService{
+		qrCodeRepo:     qrCodeRepo,
+		qrService:      qrService,
+		eventRepo:      eventRepo,
+		attendeeRepo:   attendeeRepo,
+		wechatUserRepo: wechatUserRepo,
+		newsRepo:       newsRepo,
+		logger:         logger,
+	}
+}
+
+// RegisterHandlers routes scans of event and attendee QR codes to the check-in flow
+func (s *EventCheckInService) RegisterHandlers(router *wechat.MessageRouter) {
+	router.HandleScene(entities.QRResourceEvent+"_", s.handleScan)
+	router.HandleScene(entities.QRResourceAttendee+"_", s.handleScan)
+}
+
+// handleScan checks the scanning follower in and replies with the event's scan content
+func (s *EventCheckInService) handleScan(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	event, _, alreadyCheckedIn, err := s.checkIn(ctx, msg.GetOpenID(), wechat.SceneStr(msg), msg.Ticket)
+	if err != nil {
+		if errors.Is(err, ErrAttendeeCodeNotOwned) {
+			s.logger.Warn("Rejected WeChat scan of another attendee's code",
+				zap.String("openId", msg.GetOpenID()),
+				zap.String("eventKey", msg.EventKey))
+			return wechat.TextReply(notOwnedQRCodeMessage), nil
+		}
+		if errors.Is(err, services.ErrAttendeeNotRegistered) {
+			s.logger.Info("Rejected WeChat scan of an attendee without a place",
+				zap.String("openId", msg.GetOpenID()),
+				zap.String("eventKey", msg.EventKey))
+			return wechat.TextReply(notRegisteredMessage), nil
+		}
+		if errors.Is(err, ErrQRCodeNotUsable) || errors.Is(err, ErrQRCodeNotRecorded) ||
+			errors.Is(err, ErrUnsupportedQRCode) || errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("Rejected WeChat check-in scan",
+				zap.String("openId", msg.GetOpenID()),
+				zap.String("eventKey", msg.EventKey),
+				zap.Error(err))
+			return wechat.TextReply(invalidQRCodeMessage), nil
+		}
+		return nil, err
+	}
+
+	return s.buildReply(ctx, event, alreadyCheckedIn), nil
+}
+
+// CheckInByScene resolves the scanned QR code, creates or checks in the attendee and makes the
+// event the follower's current event
+func (s *EventCheckInService) CheckInByScene(ctx context.Context, openID, sceneStr, ticket string) (*services.QRScanResult, error) {
+	event, attendee, alreadyCheckedIn, err := s.checkIn(ctx, openID, sceneStr, ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceType, _, _ := entities.ParseSceneStr(sceneStr)
+	resultMessage := fmt.Sprintf(checkInSuccessMessage, event.EventTitle)
+	if alreadyCheckedIn {
+		resultMessage = fmt.Sprintf(alreadyCheckedInMessage, event.EventTitle)
+	}
+
+	return &services.QRScanResult{
+		Success:    true,
+		Type:       resourceType,
+		EventID:    &event.ID,
+		AttendeeID: &attendee.ID,
+		Action:     "check_in",
+		Message:    resultMessage,
+		Data: map[string]interface{}{
+			"alreadyCheckedIn": alreadyCheckedIn,
+			"checkInTime":      attendee.CheckInDate,
+		},
+	}, nil
+}
+
+// GenerateEventQRCode generates the WeChat QR code followers scan to check in to an event
+func (s *EventCheckInService) GenerateEventQRCode(ctx context.Context, eventID uuid.UUID, qrType entities.QRCodeType) (*entities.WeChatQrCode, error) {
+	if _, err := s.eventRepo.GetByID(ctx, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return s.qrService.GenerateEventCheckInQRCode(ctx, eventID, qrType)
+}
+
+// checkIn performs the check-in and reports whether the attendee had already been checked in
+func (s *EventCheckInService) checkIn(ctx context.Context, openID, sceneStr, ticket string) (*entities.SiteEvent, *entities.EventAttendee, bool, error) {
+	resourceType, resourceID, err := s.resolveQRCode(ctx, sceneStr, ticket)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	user, err := s.getOrCreateUser(ctx, openID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var event *entities.SiteEvent
+	var attendee *entities.EventAttendee
+	switch resourceType {
+	case entities.QRResourceEvent:
+		event, err = s.eventRepo.GetByID(ctx, resourceID)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to get event: %w", err)
+		}
+		attendee, err = s.findOrCreateAttendee(ctx, event, user)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	case entities.QRResourceAttendee:
+		attendee, err = s.attendeeRepo.GetByID(ctx, resourceID)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to get attendee: %w", err)
+		}
+		event, err = s.eventRepo.GetByID(ctx, attendee.EventID)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to get event: %w", err)
+		}
+	default:
+		return nil, nil, false, fmt.Errorf("%w: %s", ErrUnsupportedQRCode, resourceType)
+	}
+
+	// A personal code only admits its owner, so a forwarded code cannot take over the registration
+	if attendee.UserID != nil && *attendee.UserID != user.ID {
+		return nil, nil, false, ErrAttendeeCodeNotOwned
+	}
+
+	// Like staff check-ins, a scan only admits attendees holding a place
+	if !attendee.IsRegistered() {
+		return nil, nil, false, services.ErrAttendeeNotRegistered
+	}
+	alreadyCheckedIn := attendee.IsCheckedIn()
+	attendee.CheckIn(entities.CheckInMethodWeChatScan)
+	if attendee.UserID == nil {
+		attendee.UserID = &user.ID
+	}
+	if attendee.Mobile == "" && user.Mobile != nil {
+		attendee.Mobile = *user.Mobile
+	}
+	// Drop preloaded associations so Save only touches the attendee row
+	attendee.Event = nil
+	attendee.User = nil
+	if err := s.attendeeRepo.Update(ctx, attendee); err != nil {
+		return nil, nil, false, fmt.Errorf("failed to check in attendee: %w", err)
+	}
+
+	user.CurrentEventID = &event.ID
+	if err := s.wechatUserRepo.Update(ctx, user); err != nil {
+		return nil, nil, false, fmt.Errorf("failed to update WeChat user: %w", err)
+	}
+
+	s.logger.Info("Attendee checked in via WeChat scan",
+		zap.String("eventId", event.ID.String()),
+		zap.String("attendeeId", attendee.ID.String()),
+		zap.String("openId", openID),
+		zap.Bool("alreadyCheckedIn", alreadyCheckedIn))
+
+	return event, attendee, alreadyCheckedIn, nil
+}
+
+// resolveQRCode looks up the stored QR code by ticket or scene string and returns the resource it encodes.
+// Only codes issued by the system are accepted.
+func (s *EventCheckInService) resolveQRCode(ctx context.Context, sceneStr, ticket string) (string, uuid.UUID, error) {
+	resourceType, value, ok := entities.ParseSceneStr(sceneStr)
+	if !ok {
+		return "", uuid.Nil, fmt.Errorf("%w: %q", ErrUnsupportedQRCode, sceneStr)
+	}
+	resourceID, err := uuid.Parse(value)
+	if err != nil {
+		return "", uuid.Nil, fmt.Errorf("%w: %q", ErrUnsupportedQRCode, sceneStr)
+	}
+
+	var qrCode *entities.WeChatQrCode
+	if ticket != "" {
+		qrCode, err = s.qrCodeRepo.GetByTicket(ctx, ticket)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", uuid.Nil, fmt.Errorf("failed to get QR code: %w", err)
+		}
+	}
+	if qrCode == nil {
+		qrCode, err = s.qrCodeRepo.GetBySceneStr(ctx, sceneStr)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", uuid.Nil, fmt.Errorf("failed to get QR code: %w", err)
+		}
+	}
+
+	if qrCode == nil || qrCode.GetResourceType() != resourceType || qrCode.ParamsValue != value {
+		return "", uuid.Nil, fmt.Errorf("%w: %q", ErrQRCodeNotRecorded, sceneStr)
+	}
+	if !qrCode.IsUsable() {
+		return "", uuid.Nil, ErrQRCodeNotUsable
+	}
+
+	return resourceType, resourceID, nil
+}
+
+// getOrCreateUser loads the scanning follower, creating a record if we have not seen them yet
+func (s *EventCheckInService) getOrCreateUser(ctx context.Context, openID string) (*entities.WeChatUser, error) {
+	user, err := s.wechatUserRepo.GetByOpenID(ctx, openID)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get WeChat user: %w", err)
+	}
+
+	user = &entities.WeChatUser{OpenID: openID, Subscribe: true}
+	if err := s.wechatUserRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create WeChat user: %w", err)
+	}
+	return user, nil
+}
+
+// findOrCreateAttendee matches the follower to an existing registration by user or mobile,
+// registering them on the spot when there is none. Walk-ins are waitlisted when the event is full.
+func (s *EventCheckInService) findOrCreateAttendee(ctx context.Context, event *entities.SiteEvent, user *entities.WeChatUser) (*entities.EventAttendee, error) {
+	eventID := event.ID
+	attendee, err := s.attendeeRepo.GetByEventAndUser(ctx, eventID, user.ID)
+	if err == nil {
+		return attendee, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get attendee: %w", err)
+	}
+
+	if user.Mobile != nil && *user.Mobile != "" {
+		attendee, err = s.attendeeRepo.GetByEventAndMobile(ctx, eventID, *user.Mobile)
+		if err == nil {
+			return attendee, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get attendee: %w", err)
+		}
+	}
+
+	attendee = &entities.EventAttendee{
+		EventID: eventID,
+		UserID:  &user.ID,
+		Source:  "wechat",
+	}
+	if user.Mobile != nil {
+		attendee.Mobile = *user.Mobile
+	}
+	if err := s.attendeeRepo.CreateWithCapacity(ctx, attendee, event.Capacity); err != nil {
+		// A concurrent scan of the same follower registered them first
+		if errors.Is(err, repositories.ErrAttendeeExists) {
+			return s.attendeeRepo.GetByEventAndUser(ctx, eventID, user.ID)
+		}
+		return nil, fmt.Errorf("failed to register attendee: %w", err)
+	}
+	return attendee, nil
+}
+
+// buildReply prefers the event's scan news, then its scan message, then a generic confirmation
+func (s *EventCheckInService) buildReply(ctx context.Context, event *entities.SiteEvent, alreadyCheckedIn bool) *message.Reply {
+	if event.ScanNewsID != uuid.Nil && s.newsRepo != nil {
+		news, err := s.newsRepo.GetByID(ctx, event.ScanNewsID)
+		if err == nil {
+			var coverURL string
+			if news.FrontCoverImageUrl != nil {
+				coverURL = *news.FrontCoverImageUrl
+			}
+			return wechat.NewsReply(news.Title, news.Summary, coverURL, news.WeChatURL)
+		}
+		s.logger.Warn("Failed to load event scan news",
+			zap.String("eventId", event.ID.String()),
+			zap.String("newsId", event.ScanNewsID.String()),
+			zap.Error(err))
+	}
+
+	if event.ScanMessage != "" {
+		return wechat.TextReply(event.ScanMessage)
+	}
+	if alreadyCheckedIn {
+		return wechat.TextReply(fmt.Sprintf(alreadyCheckedInMessage, event.EventTitle))
+	}
+	return wechat.TextReply(fmt.Sprintf(checkInSuccessMessage, event.EventTitle))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryWeChatQrCodeRepository serves recorded QR codes from memory
+type memoryWeChatQrCodeRepository struct {
+	repositories.WeChatQrCodeRepository
+	qrCodes []*entities.WeChatQrCode
+}
+
+func (r *memoryWeChatQrCodeRepository) GetByTicket(ctx context.Context, ticket string) (*entities.WeChatQrCode, error) {
+	for _, qrCode := range r.qrCodes {
+		if qrCode.Ticket == ticket {
+			return qrCode, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryWeChatQrCodeRepository) GetBySceneStr(ctx context.Context, sceneStr string) (*entities.WeChatQrCode, error) {
+	_, value, _ := entities.ParseSceneStr(sceneStr)
+	for _, qrCode := range r.qrCodes {
+		if qrCode.ParamsValue == value {
+			return qrCode, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type EventCheckInServiceTestSuite struct {
+	suite.Suite
+	qrCodes   *memoryWeChatQrCodeRepository
+	attendees *memoryAttendeeRepository
+	users     *memoryWeChatUserRepository
+	service   *EventCheckInService
+	event     *entities.SiteEvent
+}
+
+func (s *EventCheckInServiceTestSuite) SetupTest() {
+	s.event = &entities.SiteEvent{ID: uuid.New(), EventTitle: "Launch"}
+	s.qrCodes = &memoryWeChatQrCodeRepository{}
+	s.attendees = &memoryAttendeeRepository{}
+	s.users = &memoryWeChatUserRepository{}
+	events := &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{s.event.ID: s.event}}
+	s.service = NewEventCheckInService(s.qrCodes, nil, events, s.attendees, s.users, nil, zap.NewNop())
+}
+
+// recordAttendeeCode records the personal code of a new attendee owned by the given follower
+func (s *EventCheckInServiceTestSuite) recordAttendeeCode(owner *entities.WeChatUser) *entities.EventAttendee {
+	attendee := &entities.EventAttendee{ID: uuid.New(), EventID: s.event.ID, Status: entities.AttendeeStatusRegistered}
+	if owner != nil {
+		attendee.UserID = &owner.ID
+	}
+	s.attendees.attendees = append(s.attendees.attendees, attendee)
+	s.qrCodes.qrCodes = append(s.qrCodes.qrCodes, &entities.WeChatQrCode{
+		ID:          uuid.New(),
+		ParamsValue: attendee.ID.String(),
+		ParamKey:    entities.QRResourceAttendee,
+	})
+	return attendee
+}
+
+func (s *EventCheckInServiceTestSuite) TestOwnerChecksInWithPersonalCode() {
+	ctx := context.Background()
+	owner := &entities.WeChatUser{OpenID: "owner"}
+	s.Require().NoError(s.users.Create(ctx, owner))
+	attendee := s.recordAttendeeCode(owner)
+
+	result, err := s.service.CheckInByScene(ctx, "owner", entities.BuildSceneStr(entities.QRResourceAttendee, attendee.ID), "")
+	s.Require().NoError(err)
+	s.Equal(attendee.ID, *result.AttendeeID)
+
+	stored, _ := s.attendees.GetByID(ctx, attendee.ID)
+	s.True(stored.IsCheckedIn())
+}
+
+func (s *EventCheckInServiceTestSuite) TestForwardedPersonalCodeIsRejected() {
+	ctx := context.Background()
+	owner := &entities.WeChatUser{OpenID: "owner"}
+	s.Require().NoError(s.users.Create(ctx, owner))
+	attendee := s.recordAttendeeCode(owner)
+
+	_, err := s.service.CheckInByScene(ctx, "stranger", entities.BuildSceneStr(entities.QRResourceAttendee, attendee.ID), "")
+	s.ErrorIs(err, ErrAttendeeCodeNotOwned)
+
+	stored, _ := s.attendees.GetByID(ctx, attendee.ID)
+	s.False(stored.IsCheckedIn())
+	s.Equal(owner.ID, *stored.UserID)
+	stranger, _ := s.users.GetByOpenID(ctx, "stranger")
+	s.Nil(stranger.CurrentEventID)
+}
+
+func (s *EventCheckInServiceTestSuite) TestUnrecordedCodeIsRejected() {
+	_, err := s.service.CheckInByScene(context.Background(), "owner", entities.BuildSceneStr(entities.QRResourceEvent, s.event.ID), "")
+	s.ErrorIs(err, ErrQRCodeNotRecorded)
+}
+
+func (s *EventCheckInServiceTestSuite) TestScanRequiresPlace() {
+	ctx := context.Background()
+	s.event.Capacity = 1
+	s.qrCodes.qrCodes = append(s.qrCodes.qrCodes, &entities.WeChatQrCode{
+		ID:          uuid.New(),
+		ParamsValue: s.event.ID.String(),
+		ParamKey:    entities.QRResourceEvent,
+	})
+	owner := &entities.WeChatUser{OpenID: "owner"}
+	s.Require().NoError(s.users.Create(ctx, owner))
+	s.recordAttendeeCode(owner)
+
+	_, err := s.service.CheckInByScene(ctx, "walk-in", entities.BuildSceneStr(entities.QRResourceEvent, s.event.ID), "")
+	s.ErrorIs(err, services.ErrAttendeeNotRegistered)
+	walkIn := s.attendees.attendees[1]
+	s.Equal(entities.AttendeeStatusWaitlisted, walkIn.Status)
+	s.False(walkIn.IsCheckedIn())
+
+	cancelled := &entities.WeChatUser{OpenID: "cancelled"}
+	s.Require().NoError(s.users.Create(ctx, cancelled))
+	attendee := s.recordAttendeeCode(cancelled)
+	attendee.Status = entities.AttendeeStatusCancelled
+
+	_, err = s.service.CheckInByScene(ctx, "cancelled", entities.BuildSceneStr(entities.QRResourceAttendee, attendee.ID), "")
+	s.ErrorIs(err, services.ErrAttendeeNotRegistered)
+	stored, _ := s.attendees.GetByID(ctx, attendee.ID)
+	s.True(stored.IsCancelled())
+	s.False(stored.IsCheckedIn())
+}
+
+func TestEventCheckInServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(EventCheckInServiceTestSuite))
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -132,26 +134,121 @@ func (s *EventServiceImpl) GetUpcomingEvents(ctx context.Context, limit int) ([]
 	return s.GetEventsByDateRange(ctx, now, now.AddDate(1, 0, 0)) // Next year
 }
 
-// GetEventStatistics retrieves event analytics
-func (s *EventServiceImpl) GetEventStatistics(ctx context.Context, eventID uuid.UUID) (*services.EventStatistics, error) {
+// GetEventStatistics computes registration and check-in trends bucketed by hour or day, registration
+// and check-in counts per source and check-in method, and the live checked-in count. WeChat QR scans
+// are counted through the attendee rows: WeiChatQrCodes keeps no per-scan records, and every scan that
+// checks someone in stamps their row with CheckInDate and the wechat_scan method.
+func (s *EventServiceImpl) GetEventStatistics(ctx context.Context, eventID uuid.UUID, interval string) (*services.EventStatistics, error) {
 	s.logger.Info("Getting event statistics", zap.String("event_id", eventID.String()))
 	
-	// This would require attendee repository methods
-	// For now, return basic statistics
+	if interval == "" {
+		interval = services.StatisticsIntervalDay
+	}
+	if interval != services.StatisticsIntervalHour && interval != services.StatisticsIntervalDay {
+		return nil, fmt.Errorf("%w: %s", services.ErrInvalidStatisticsInterval, interval)
+	}
+	
+	if _, err := s.eventRepo.GetByID(ctx, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	
+	attendees, err := s.attendeeRepo.GetActivityByEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendees: %w", err)
+	}
+	
+	now := time.Now()
 	stats := &services.EventStatistics{
 		EventID:           eventID,
-		TotalRegistered:   0,
-		TotalCheckedIn:    0,
-		CheckInRate:       0.0,
+		Interval:          interval,
 		RegistrationTrend: []services.RegistrationPoint{},
 		CheckInTrend:      []services.CheckInPoint{},
 		TopSources:        []services.SourceStatistic{},
+		CheckInMethods:    []services.SourceStatistic{},
+		GeneratedAt:       now,
+	}
+	
+	sources := make(map[string]*services.SourceStatistic)
+	sourceRegistered := make(map[string]int64)
+	methods := make(map[string]*services.SourceStatistic)
+	registeredAt := make([]time.Time, 0, len(attendees))
+	var checkedInAt []time.Time
+	for _, attendee := range attendees {
+		registeredAt = append(registeredAt, attendee.CreatedAt)
+		
+		source := statisticsKey(attendee.Source)
+		if sources[source] == nil {
+			sources[source] = &services.SourceStatistic{Source: source}
+		}
+		sources[source].Count++
+		
+		switch {
+		case attendee.IsCancelled():
+			stats.TotalCancelled++
+			continue
+		case attendee.IsWaitlisted():
+			stats.TotalWaitlisted++
+			continue
+		}
+		stats.TotalRegistered++
+		sourceRegistered[source]++
+		if !attendee.IsCheckedIn() {
+			continue
+		}
+		
+		stats.TotalCheckedIn++
+		sources[source].CheckedIn++
+		method := statisticsKey(attendee.CheckInMethod)
+		if methods[method] == nil {
+			methods[method] = &services.SourceStatistic{Source: method}
+		}
+		methods[method].Count++
+		methods[method].CheckedIn++
+		
+		if attendee.CheckInDate == nil {
+			continue
+		}
+		checkedInAt = append(checkedInAt, *attendee.CheckInDate)
+		if now.Sub(*attendee.CheckInDate) < time.Hour {
+			stats.CheckInsLastHour++
+		}
+		if stats.LastCheckInAt == nil || attendee.CheckInDate.After(*stats.LastCheckInAt) {
+			stats.LastCheckInAt = attendee.CheckInDate
+		}
 	}
+	stats.CheckInRate = percentage(stats.TotalCheckedIn, stats.TotalRegistered)
+	
+	for _, bucket := range bucketTrend(registeredAt, interval) {
+		stats.RegistrationTrend = append(stats.RegistrationTrend, services.RegistrationPoint{
+			Date:       bucket.start,
+			Count:      bucket.count,
+			Cumulative: bucket.cumulative,
+		})
+	}
+	for _, bucket := range bucketTrend(checkedInAt, interval) {
+		stats.CheckInTrend = append(stats.CheckInTrend, services.CheckInPoint{
+			Date:       bucket.start,
+			Count:      bucket.count,
+			Cumulative: bucket.cumulative,
+		})
+	}
+	
+	for source, stat := range sources {
+		stat.Percentage = percentage(stat.Count, int64(len(attendees)))
+		stat.ConversionRate = percentage(stat.CheckedIn, sourceRegistered[source])
+		stats.TopSources = append(stats.TopSources, *stat)
+	}
+	for _, stat := range methods {
+		stat.Percentage = percentage(stat.Count, stats.TotalCheckedIn)
+		stats.CheckInMethods = append(stats.CheckInMethods, *stat)
+	}
+	sortSourceStatistics(stats.TopSources)
+	sortSourceStatistics(stats.CheckInMethods)
 	
 	return stats, nil
 }
 
-// GetEventAttendanceReport generates attendance report
+// GetEventAttendanceReport generates the attendance report with every attendee and an hourly check-in timeline
 func (s *EventServiceImpl) GetEventAttendanceReport(ctx context.Context, eventID uuid.UUID) (*services.AttendanceReport, error) {
 	s.logger.Info("Generating attendance report", zap.String("event_id", eventID.String()))
 	
@@ -160,15 +257,54 @@ func (s *EventServiceImpl) GetEventAttendanceReport(ctx context.Context, eventID
 		return nil, err
 	}
 	
+	attendees, err := s.attendeeRepo.GetByEvent(ctx, eventID, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendees: %w", err)
+	}
+	sort.SliceStable(attendees, func(i, j int) bool {
+		return attendees[i].CreatedAt.Before(attendees[j].CreatedAt)
+	})
+	
 	report := &services.AttendanceReport{
-		EventID:         eventID,
-		EventTitle:      event.EventTitle,
-		TotalCapacity:   1000, // This would come from event configuration
-		TotalRegistered: 0,
-		TotalCheckedIn:  0,
-		CheckInRate:     0.0,
-		Attendees:       []services.AttendeeInfo{},
-		Timeline:        []services.AttendanceTimePoint{},
+		EventID:       eventID,
+		EventTitle:    event.EventTitle,
+		TotalCapacity: event.Capacity,
+		Attendees:     make([]services.AttendeeInfo, 0, len(attendees)),
+		Timeline:      []services.AttendanceTimePoint{},
+	}
+	
+	checkInsByHour := make(map[time.Time]int64)
+	for _, attendee := range attendees {
+		info := newAttendeeInfo(attendee)
+		report.Attendees = append(report.Attendees, info)
+		
+		if attendee.IsRegistered() {
+			report.TotalRegistered++
+		}
+		if attendee.IsCheckedIn() {
+			report.TotalCheckedIn++
+			if info.CheckedInAt != nil {
+				checkInsByHour[info.CheckedInAt.Truncate(time.Hour)]++
+			}
+		}
+	}
+	if report.TotalRegistered > 0 {
+		report.CheckInRate = float64(report.TotalCheckedIn) / float64(report.TotalRegistered) * 100
+	}
+	
+	hours := make([]time.Time, 0, len(checkInsByHour))
+	for hour := range checkInsByHour {
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Before(hours[j]) })
+	var cumulative int64
+	for _, hour := range hours {
+		cumulative += checkInsByHour[hour]
+		report.Timeline = append(report.Timeline, services.AttendanceTimePoint{
+			Time:       hour,
+			CheckIns:   checkInsByHour[hour],
+			Cumulative: cumulative,
+		})
 	}
 	
 	return report, nil
@@ -176,6 +312,85 @@ func (s *EventServiceImpl) GetEventAttendanceReport(ctx context.Context, eventID
 
 // Helper methods
 
+// trendBucket is one interval of a time series
+type trendBucket struct {
+	start      time.Time
+	count      int64
+	cumulative int64
+}
+
+// bucketTrend counts the times per hour or day in local time, from the first bucket to the last
+// with empty buckets included so the series can be charted directly
+func bucketTrend(times []time.Time, interval string) []trendBucket {
+	if len(times) == 0 {
+		return nil
+	}
+	
+	counts := make(map[time.Time]int64)
+	var first, last time.Time
+	for _, t := range times {
+		start := bucketStart(t, interval)
+		counts[start]++
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	
+	var buckets []trendBucket
+	var cumulative int64
+	for start := first; !start.After(last); start = nextBucket(start, interval) {
+		cumulative += counts[start]
+		buckets = append(buckets, trendBucket{start: start, count: counts[start], cumulative: cumulative})
+	}
+	return buckets
+}
+
+// bucketStart truncates a time to the start of its hour or day in local time
+func bucketStart(t time.Time, interval string) time.Time {
+	t = t.In(time.Local)
+	if interval == services.StatisticsIntervalHour {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// nextBucket returns the start of the following hour or day
+func nextBucket(start time.Time, interval string) time.Time {
+	if interval == services.StatisticsIntervalHour {
+		return bucketStart(start.Add(time.Hour), interval)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// statisticsKey groups attendees without a recorded source or check-in method under "unknown"
+func statisticsKey(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return strings.ToLower(value)
+}
+
+// percentage returns part as a percentage of total, or 0 when total is 0
+func percentage(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// sortSourceStatistics orders statistics by count, largest first
+func sortSourceStatistics(stats []services.SourceStatistic) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Source < stats[j].Source
+	})
+}
+
 // validateEvent validates event data
 func (s *EventServiceImpl) validateEvent(event *entities.SiteEvent) error {
 	if event.EventTitle == "" {
@@ -197,6 +412,46 @@ func (s *EventServiceImpl) validateEvent(event *entities.SiteEvent) error {
 	return nil
 }
 
+// newAttendeeInfo flattens an attendee and their WeChat profile for reporting.
+// Details given at registration or import take precedence over the WeChat profile.
+func newAttendeeInfo(attendee *entities.EventAttendee) services.AttendeeInfo {
+	info := services.AttendeeInfo{
+		AttendeeID:   attendee.ID,
+		UserName:     attendee.Name,
+		Company:      attendee.Company,
+		PhoneNumber:  attendee.Mobile,
+		RegisteredAt: attendee.CreatedAt,
+		CheckedInAt:  attendee.CheckInDate,
+		Status:       attendee.Status,
+		Source:       attendee.Source,
+	}
+	if info.Status == "" {
+		info.Status = entities.AttendeeStatusRegistered
+	}
+	if attendee.UserID != nil {
+		info.UserID = *attendee.UserID
+	}
+	
+	if user := attendee.User; user != nil {
+		if info.UserName == "" {
+			info.UserName = user.NickName
+			if user.RealName != nil && *user.RealName != "" {
+				info.UserName = *user.RealName
+			}
+		}
+		if info.Company == "" && user.CompanyName != nil {
+			info.Company = *user.CompanyName
+		}
+		if info.PhoneNumber == "" && user.Mobile != nil {
+			info.PhoneNumber = *user.Mobile
+		}
+		if user.Email != nil {
+			info.Email = *user.Email
+		}
+	}
+	return info
+}
+
 // generateInteractionCode generates a unique interaction code
 func (s *EventServiceImpl) generateInteractionCode() string {
 	// Generate a simple interaction code
@@ -196,7 +196,7 @@ func (suite *EventServiceTestSuite) TestGetEventStatistics() {
 	suite.Require().NoError(err)
 
 	// Get statistics
-	stats, err := suite.eventService.GetEventStatistics(ctx, event.ID)
+	stats, err := suite.eventService.GetEventStatistics(ctx, event.ID, "day")
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), event.ID, stats.EventID)
 	assert.Equal(suite.T(), int64(0), stats.TotalRegistered)
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *memoryAttendeeRepository) GetActivityByEvent(ctx context.Context, eventID uuid.UUID) ([]*entities.EventAttendee, error) {
+	return r.GetByEvent(ctx, eventID, 0, -1)
+}
+
+type EventStatisticsTestSuite struct {
+	suite.Suite
+	eventID      uuid.UUID
+	attendeeRepo *memoryAttendeeRepository
+	service      services.EventService
+}
+
+func (suite *EventStatisticsTestSuite) SetupTest() {
+	suite.eventID = uuid.New()
+	suite.attendeeRepo = &memoryAttendeeRepository{}
+	eventRepo := &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{suite.eventID: {ID: suite.eventID}}}
+	suite.service = NewEventService(eventRepo, nil, suite.attendeeRepo, zap.NewNop(), nil)
+}
+
+func (suite *EventStatisticsTestSuite) addAttendee(source, status string, registeredAt time.Time, checkedInAt *time.Time, method string) {
+	suite.attendeeRepo.attendees = append(suite.attendeeRepo.attendees, &entities.EventAttendee{
+		ID:            uuid.New(),
+		EventID:       suite.eventID,
+		Source:        source,
+		Status:        status,
+		CreatedAt:     registeredAt,
+		OnSiteScanned: checkedInAt != nil,
+		CheckInDate:   checkedInAt,
+		CheckInMethod: method,
+	})
+}
+
+func (suite *EventStatisticsTestSuite) TestTrendsAndSources() {
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)
+	doors := day.AddDate(0, 0, 3).Add(9 * time.Hour)
+	early, late := doors.Add(10*time.Minute), doors.Add(2*time.Hour+5*time.Minute)
+
+	suite.addAttendee("wechat", entities.AttendeeStatusRegistered, day.Add(10*time.Hour), &early, entities.CheckInMethodWeChatScan)
+	suite.addAttendee("wechat", entities.AttendeeStatusRegistered, day.Add(11*time.Hour), &late, entities.CheckInMethodTicketScan)
+	suite.addAttendee("wechat", entities.AttendeeStatusRegistered, day.AddDate(0, 0, 2), nil, "")
+	suite.addAttendee("web", entities.AttendeeStatusRegistered, day.AddDate(0, 0, 2).Add(time.Hour), &early, entities.CheckInMethodTicketScan)
+	suite.addAttendee("web", entities.AttendeeStatusCancelled, day.AddDate(0, 0, 2).Add(2*time.Hour), nil, "")
+	suite.addAttendee("", entities.AttendeeStatusWaitlisted, day.AddDate(0, 0, 2).Add(3*time.Hour), nil, "")
+
+	stats, err := suite.service.GetEventStatistics(context.Background(), suite.eventID, services.StatisticsIntervalDay)
+	suite.Require().NoError(err)
+	suite.Equal(int64(4), stats.TotalRegistered)
+	suite.Equal(int64(1), stats.TotalWaitlisted)
+	suite.Equal(int64(1), stats.TotalCancelled)
+	suite.Equal(int64(3), stats.TotalCheckedIn)
+	suite.Equal(75.0, stats.CheckInRate)
+	suite.Equal(late, *stats.LastCheckInAt)
+
+	// Empty days between the first and last registration are kept
+	suite.Len(stats.RegistrationTrend, 3)
+	suite.Equal(services.RegistrationPoint{Date: day, Count: 2, Cumulative: 2}, stats.RegistrationTrend[0])
+	suite.Equal(services.RegistrationPoint{Date: day.AddDate(0, 0, 1), Count: 0, Cumulative: 2}, stats.RegistrationTrend[1])
+	suite.Equal(services.RegistrationPoint{Date: day.AddDate(0, 0, 2), Count: 4, Cumulative: 6}, stats.RegistrationTrend[2])
+
+	suite.Require().Len(stats.TopSources, 3)
+	wechat, web, unknown := stats.TopSources[0], stats.TopSources[1], stats.TopSources[2]
+	suite.Equal("wechat", wechat.Source)
+	suite.Equal(int64(2), wechat.CheckedIn)
+	suite.Equal(50.0, wechat.Percentage)
+	suite.InDelta(66.67, wechat.ConversionRate, 0.01)
+	suite.Equal("web", web.Source)
+	suite.Equal(100.0, web.ConversionRate, "cancelled registrations do not count against conversion")
+	suite.Equal("unknown", unknown.Source)
+	suite.Equal(int64(1), unknown.Count)
+	suite.Equal(entities.CheckInMethodTicketScan, stats.CheckInMethods[0].Source)
+	suite.Equal(int64(2), stats.CheckInMethods[0].Count)
+
+	hourly, err := suite.service.GetEventStatistics(context.Background(), suite.eventID, services.StatisticsIntervalHour)
+	suite.Require().NoError(err)
+	suite.Equal([]services.CheckInPoint{
+		{Date: doors, Count: 2, Cumulative: 2},
+		{Date: doors.Add(time.Hour), Count: 0, Cumulative: 2},
+		{Date: doors.Add(2 * time.Hour), Count: 1, Cumulative: 3},
+	}, hourly.CheckInTrend)
+
+	_, err = suite.service.GetEventStatistics(context.Background(), suite.eventID, "week")
+	suite.ErrorIs(err, services.ErrInvalidStatisticsInterval)
+
+	_, err = suite.service.GetEventStatistics(context.Background(), uuid.New(), services.StatisticsIntervalDay)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func TestEventStatisticsTestSuite(t *testing.T) {
+	suite.Run(t, new(EventStatisticsTestSuite))
+}
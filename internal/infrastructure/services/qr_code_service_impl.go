@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/skip2/go-qrcode"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	ticketAcceptedMessage = "Ticket accepted"
+	ticketUsedMessage     = "Ticket has already been used"
+	ticketInvalidMessage  = "Ticket is not valid"
+	ticketExpiredMessage  = "Ticket has expired"
+	ticketRevokedMessage  = "Ticket has been revoked"
+)
+
+// QRCodeServiceConfig holds configuration for the QR code service
+type QRCodeServiceConfig struct {
+	DefaultSize        int // Default PNG size in pixels
+	MaxSize            int // Largest PNG size served
+	DefaultExpireHours int // Expiry of event QR codes when none is requested
+	TicketGraceHours   int // How long attendee tickets stay valid after the event ends
+}
+
+// QRCodeServiceImpl implements the QRCodeService interface. Attendee tickets are Ed25519-signed so
+// that scanners can verify them offline; revocations and single-use check-in claims live in Redis
+// so online verification needs no database round-trip either.
+type QRCodeServiceImpl struct {
+	eventRepo    repositories.SiteEventRepository
+	attendeeRepo repositories.EventAttendeeRepository
+	signer       *TicketSigner
+	store        ticketStore
+	config       *QRCodeServiceConfig
+	logger       *zap.Logger
+}
+
+// NewQRCodeService creates a new QR code service implementation. A nil signer disables attendee
+// tickets, and a nil Redis client keeps revocations and check-in claims in process memory.
+func NewQRCodeService(
+	eventRepo repositories.SiteEventRepository,
+	attendeeRepo repositories.EventAttendeeRepository,
+	signer *TicketSigner,
+	redisClient *redis.Client,
+	config *QRCodeServiceConfig,
+	logger *zap.Logger,
+) services.QRCodeService {
+	return &QRCodeServiceImpl{
+		eventRepo:    eventRepo,
+		attendeeRepo: attendeeRepo,
+		signer:       signer,
+		store:        newTicketStore(redisClient),
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// GenerateEventQRCode generates an event QR code carrying the event scene string
+func (s *QRCodeServiceImpl) GenerateEventQRCode(ctx context.Context, eventID uuid.UUID, expireHours int) (*services.QRCodeInfo, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if expireHours <= 0 {
+		expireHours = s.config.DefaultExpireHours
+	}
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(expireHours) * time.Hour)
+
+	return &services.QRCodeInfo{
+		Code:      entities.BuildSceneStr(entities.QRResourceEvent, event.ID),
+		Type:      entities.QRResourceEvent,
+		EntityID:  event.ID,
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+		IsActive:  true,
+	}, nil
+}
+
+// GenerateAttendeeQRCode issues a signed ticket for an attendee holding a confirmed place.
+// Tickets stay valid until the configured grace period after the event ends.
+func (s *QRCodeServiceImpl) GenerateAttendeeQRCode(ctx context.Context, attendeeID uuid.UUID) (*services.QRCodeInfo, error) {
+	attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendee: %w", err)
+	}
+	if s.signer == nil {
+		return nil, services.ErrTicketSigningDisabled
+	}
+	if !attendee.IsRegistered() {
+		return nil, services.ErrAttendeeNotRegistered
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, attendee.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := s.ticketExpiry(event)
+	if now.After(expiresAt) {
+		return nil, services.ErrEventEnded
+	}
+
+	data := &entities.QRCodeData{
+		EventID:    event.ID,
+		AttendeeID: attendee.ID,
+		Timestamp:  now,
+		ExpiresAt:  expiresAt,
+	}
+	if attendee.UserID != nil {
+		data.UserID = *attendee.UserID
+	}
+
+	return &services.QRCodeInfo{
+		Code:      s.signer.Sign(data),
+		Type:      entities.QRResourceAttendee,
+		EntityID:  attendee.ID,
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+		IsActive:  true,
+	}, nil
+}
+
+// ProcessQRCodeScan admits the holder of a signed ticket at most once. The scanner's timestamp is
+// used for scans queued while offline, so tickets scanned before they expired are still honoured.
+func (s *QRCodeServiceImpl) ProcessQRCodeScan(ctx context.Context, qrData string, scannerInfo *services.QRScannerInfo) (*services.QRScanResult, error) {
+	now := time.Now()
+	scannedAt := now
+	if scannerInfo != nil && !scannerInfo.Timestamp.IsZero() && scannerInfo.Timestamp.Before(now) {
+		scannedAt = scannerInfo.Timestamp
+	}
+
+	if !IsTicket(qrData) {
+		return s.processSceneScan(qrData), nil
+	}
+
+	data, reason, err := s.checkTicket(ctx, qrData, scannedAt)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return &services.QRScanResult{Success: false, Type: entities.QRResourceAttendee, Action: "check_in", Message: reason}, nil
+	}
+
+	claimed, firstScannedAt, err := s.store.ClaimCheckIn(ctx, data.AttendeeID, scannedAt, data.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim check-in: %w", err)
+	}
+
+	result := &services.QRScanResult{
+		Type:       entities.QRResourceAttendee,
+		EventID:    &data.EventID,
+		AttendeeID: &data.AttendeeID,
+		Action:     "check_in",
+		Data: map[string]interface{}{
+			"alreadyCheckedIn": !claimed,
+			"checkInTime":      firstScannedAt,
+		},
+	}
+	if !claimed {
+		result.Message = ticketUsedMessage
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = ticketAcceptedMessage
+	result.Data["recorded"] = s.recordCheckIn(ctx, data.AttendeeID, scannedAt)
+
+	s.logger.Info("Ticket accepted",
+		zap.String("eventId", data.EventID.String()),
+		zap.String("attendeeId", data.AttendeeID.String()),
+		zap.Time("scannedAt", scannedAt))
+
+	return result, nil
+}
+
+// ValidateQRCode checks a QR code's signature, expiry and revocation without consuming it
+func (s *QRCodeServiceImpl) ValidateQRCode(ctx context.Context, qrData string) (*services.QRValidationResult, error) {
+	if !IsTicket(qrData) {
+		resourceType, eventID, ok := parseEventScene(qrData)
+		if !ok {
+			return &services.QRValidationResult{IsValid: false, Type: resourceType, Message: ticketInvalidMessage}, nil
+		}
+		return &services.QRValidationResult{IsValid: true, Type: resourceType, EntityID: eventID, EventID: &eventID}, nil
+	}
+
+	data, reason, err := s.checkTicket(ctx, qrData, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &services.QRValidationResult{IsValid: false, Type: entities.QRResourceAttendee, Message: reason}, nil
+	}
+
+	return &services.QRValidationResult{
+		IsValid:   reason == "",
+		Type:      entities.QRResourceAttendee,
+		EntityID:  data.AttendeeID,
+		EventID:   &data.EventID,
+		ExpiresAt: &data.ExpiresAt,
+		Message:   reason,
+	}, nil
+}
+
+// GetQRCodeInfo describes a ticket or event QR code, including whether the ticket has been used
+func (s *QRCodeServiceImpl) GetQRCodeInfo(ctx context.Context, qrCode string) (*services.QRCodeInfo, error) {
+	if !IsTicket(qrCode) {
+		resourceType, eventID, ok := parseEventScene(qrCode)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedQRCode, qrCode)
+		}
+		return &services.QRCodeInfo{Code: qrCode, Type: resourceType, EntityID: eventID, IsActive: true}, nil
+	}
+
+	data, reason, err := s.checkTicket(ctx, qrCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrMalformedTicket
+	}
+
+	info := &services.QRCodeInfo{
+		Code:      qrCode,
+		Type:      entities.QRResourceAttendee,
+		EntityID:  data.AttendeeID,
+		ExpiresAt: &data.ExpiresAt,
+		CreatedAt: data.Timestamp,
+		IsActive:  reason == "",
+	}
+	if info.LastScannedAt, err = s.store.CheckInTime(ctx, data.AttendeeID); err != nil {
+		return nil, fmt.Errorf("failed to get check-in time: %w", err)
+	}
+	if info.LastScannedAt != nil {
+		info.ScanCount = 1
+	}
+	return info, nil
+}
+
+// GetQRCodeImage renders a QR code as a PNG, clamping the size to the configured maximum
+func (s *QRCodeServiceImpl) GetQRCodeImage(ctx context.Context, qrCode string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = s.config.DefaultSize
+	}
+	if s.config.MaxSize > 0 && size > s.config.MaxSize {
+		size = s.config.MaxSize
+	}
+
+	png, err := qrcode.Encode(qrCode, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	return png, nil
+}
+
+// DeactivateQRCode revokes a single signed ticket, or every ticket issued so far to an attendee
+// when given an attendee scene string or ID
+func (s *QRCodeServiceImpl) DeactivateQRCode(ctx context.Context, qrCode string) error {
+	now := time.Now()
+	var revocation services.TicketRevocation
+
+	if IsTicket(qrCode) {
+		if s.signer == nil {
+			return services.ErrTicketSigningDisabled
+		}
+		data, err := s.signer.Verify(qrCode)
+		if err != nil {
+			return err
+		}
+		ticketID, err := TicketID(qrCode)
+		if err != nil {
+			return err
+		}
+		revocation = services.TicketRevocation{ID: ticketID, RevokedAt: now, ExpiresAt: data.ExpiresAt}
+	} else {
+		attendeeID, err := parseAttendeeCode(qrCode)
+		if err != nil {
+			return err
+		}
+		attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+		if err != nil {
+			return fmt.Errorf("failed to get attendee: %w", err)
+		}
+		event, err := s.eventRepo.GetByID(ctx, attendee.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+		revocation = services.TicketRevocation{ID: attendee.ID.String(), RevokedAt: now, ExpiresAt: s.ticketExpiry(event)}
+	}
+
+	if err := s.store.Revoke(ctx, revocation); err != nil {
+		return fmt.Errorf("failed to revoke ticket: %w", err)
+	}
+
+	s.logger.Info("Ticket revoked", zap.String("revocationId", revocation.ID))
+	return nil
+}
+
+// GetTicketVerificationKey returns the public key and current revocations for offline scanners
+func (s *QRCodeServiceImpl) GetTicketVerificationKey(ctx context.Context) (*services.TicketVerificationKey, error) {
+	if s.signer == nil {
+		return nil, services.ErrTicketSigningDisabled
+	}
+	revocations, err := s.store.Revocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revocations: %w", err)
+	}
+
+	return &services.TicketVerificationKey{
+		Algorithm:   "Ed25519",
+		KeyID:       s.signer.KeyID(),
+		PublicKey:   s.signer.PublicKey(),
+		Revocations: revocations,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// Helper methods
+
+// checkTicket verifies a ticket and returns its data with the reason it is not acceptable at the
+// given time, or an empty reason if it is. The data is nil when the ticket cannot be trusted at all.
+func (s *QRCodeServiceImpl) checkTicket(ctx context.Context, ticket string, at time.Time) (*entities.QRCodeData, string, error) {
+	if s.signer == nil {
+		return nil, ticketInvalidMessage, nil
+	}
+	data, err := s.signer.Verify(ticket)
+	if err != nil {
+		return nil, ticketInvalidMessage, nil
+	}
+	if data.IsExpired(at) {
+		return data, ticketExpiredMessage, nil
+	}
+
+	ticketID, err := TicketID(ticket)
+	if err != nil {
+		return nil, ticketInvalidMessage, nil
+	}
+	revocation, err := s.store.Revocation(ctx, ticketID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check revocation: %w", err)
+	}
+	if revocation != nil {
+		return data, ticketRevokedMessage, nil
+	}
+
+	// Attendee-wide revocations only cover tickets issued up to the revocation
+	revocation, err = s.store.Revocation(ctx, data.AttendeeID.String())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check revocation: %w", err)
+	}
+	if revocation != nil && !data.Timestamp.After(revocation.RevokedAt) {
+		return data, ticketRevokedMessage, nil
+	}
+
+	return data, "", nil
+}
+
+// recordCheckIn writes an accepted ticket scan to the attendee record. Admission does not depend
+// on it, so failures are only logged; it reports whether the check-in was stored.
+func (s *QRCodeServiceImpl) recordCheckIn(ctx context.Context, attendeeID uuid.UUID, at time.Time) bool {
+	attendee, err := s.attendeeRepo.GetByID(ctx, attendeeID)
+	if err == nil && !attendee.IsCheckedIn() {
+		attendee.CheckInDate = &at
+		attendee.CheckIn(entities.CheckInMethodTicketScan)
+		attendee.Event = nil
+		attendee.User = nil
+		err = s.attendeeRepo.Update(ctx, attendee)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to record ticket check-in",
+			zap.String("attendeeId", attendeeID.String()),
+			zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// processSceneScan answers scans of unsigned codes; only event codes are meaningful to a door scanner
+func (s *QRCodeServiceImpl) processSceneScan(qrData string) *services.QRScanResult {
+	resourceType, eventID, ok := parseEventScene(qrData)
+	if !ok {
+		return &services.QRScanResult{Success: false, Type: resourceType, Message: ticketInvalidMessage}
+	}
+	return &services.QRScanResult{Success: true, Type: resourceType, EventID: &eventID, Action: "info"}
+}
+
+// ticketExpiry is when tickets for an event stop being accepted
+func (s *QRCodeServiceImpl) ticketExpiry(event *entities.SiteEvent) time.Time {
+	return event.EventEndDate.Add(time.Duration(s.config.TicketGraceHours) * time.Hour)
+}
+
+// parseEventScene parses an event scene string ("event_<id>")
+func parseEventScene(qrCode string) (string, uuid.UUID, bool) {
+	resourceType, value, ok := entities.ParseSceneStr(qrCode)
+	if !ok || resourceType != entities.QRResourceEvent {
+		return resourceType, uuid.Nil, false
+	}
+	eventID, err := uuid.Parse(value)
+	if err != nil {
+		return resourceType, uuid.Nil, false
+	}
+	return resourceType, eventID, true
+}
+
+// parseAttendeeCode parses an attendee scene string ("attendee_<id>") or a bare attendee ID
+func parseAttendeeCode(qrCode string) (uuid.UUID, error) {
+	value := qrCode
+	if resourceType, id, ok := entities.ParseSceneStr(qrCode); ok {
+		if resourceType != entities.QRResourceAttendee {
+			return uuid.Nil, fmt.Errorf("%w: %s", ErrUnsupportedQRCode, resourceType)
+		}
+		value = id
+	}
+
+	attendeeID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %q", ErrUnsupportedQRCode, qrCode)
+	}
+	return attendeeID, nil
+}
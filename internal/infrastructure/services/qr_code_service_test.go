@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+type QRCodeServiceTestSuite struct {
+	suite.Suite
+	event        *entities.SiteEvent
+	attendee     *entities.EventAttendee
+	attendeeRepo *memoryAttendeeRepository
+	signer       *TicketSigner
+	service      services.QRCodeService
+}
+
+func (suite *QRCodeServiceTestSuite) SetupTest() {
+	userID := uuid.New()
+	suite.event = &entities.SiteEvent{ID: uuid.New(), EventTitle: "Summit", EventEndDate: time.Now().Add(48 * time.Hour)}
+	suite.attendee = &entities.EventAttendee{ID: uuid.New(), EventID: suite.event.ID, UserID: &userID, Status: entities.AttendeeStatusRegistered}
+	suite.attendeeRepo = &memoryAttendeeRepository{attendees: []*entities.EventAttendee{suite.attendee}}
+	eventRepo := &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{suite.event.ID: suite.event}}
+
+	var err error
+	suite.signer, err = NewTicketSignerFromConfig("dGVzdC10aWNrZXQtc2lnbmluZy1rZXktMzItYnl0ZXM=")
+	suite.Require().NoError(err)
+	suite.service = NewQRCodeService(eventRepo, suite.attendeeRepo, suite.signer, nil,
+		&QRCodeServiceConfig{DefaultSize: 256, MaxSize: 512, DefaultExpireHours: 24, TicketGraceHours: 12}, zap.NewNop())
+}
+
+func (suite *QRCodeServiceTestSuite) issueTicket() string {
+	ticket, err := suite.service.GenerateAttendeeQRCode(context.Background(), suite.attendee.ID)
+	suite.Require().NoError(err)
+	return ticket.Code
+}
+
+func (suite *QRCodeServiceTestSuite) TestTicketVerifiesOffline() {
+	code := suite.issueTicket()
+
+	// A scanner holding only the public key can decode and verify the ticket
+	offline := &TicketSigner{publicKey: suite.signer.PublicKey()}
+	data, err := offline.Verify(code)
+	suite.Require().NoError(err)
+	suite.Equal(suite.event.ID, data.EventID)
+	suite.Equal(suite.attendee.ID, data.AttendeeID)
+	suite.Equal(*suite.attendee.UserID, data.UserID)
+	suite.WithinDuration(suite.event.EventEndDate.Add(12*time.Hour), data.ExpiresAt, time.Second)
+
+	tampered := []byte(code)
+	tampered[len(ticketPrefix)+3] ^= 0x01
+	_, err = offline.Verify(string(tampered))
+	suite.Error(err)
+
+	other, err := NewTicketSigner(make([]byte, 32))
+	suite.Require().NoError(err)
+	_, err = other.Verify(code)
+	suite.ErrorIs(err, ErrInvalidTicketSignature)
+
+	png, err := suite.service.GetQRCodeImage(context.Background(), code, 0)
+	suite.Require().NoError(err)
+	suite.Equal([]byte("\x89PNG"), png[:4])
+}
+
+func (suite *QRCodeServiceTestSuite) TestTicketIsSingleUse() {
+	ctx := context.Background()
+	code := suite.issueTicket()
+
+	result, err := suite.service.ProcessQRCodeScan(ctx, code, nil)
+	suite.Require().NoError(err)
+	suite.True(result.Success)
+	suite.Equal(true, result.Data["recorded"])
+	suite.True(suite.attendeeRepo.attendees[0].IsCheckedIn())
+
+	// A second ticket for the same attendee does not get anyone else in either
+	result, err = suite.service.ProcessQRCodeScan(ctx, suite.issueTicket(), nil)
+	suite.Require().NoError(err)
+	suite.False(result.Success)
+	suite.Equal(ticketUsedMessage, result.Message)
+
+	info, err := suite.service.GetQRCodeInfo(ctx, code)
+	suite.Require().NoError(err)
+	suite.Equal(1, info.ScanCount)
+}
+
+func (suite *QRCodeServiceTestSuite) TestRevocation() {
+	ctx := context.Background()
+	code := suite.issueTicket()
+
+	suite.Require().NoError(suite.service.DeactivateQRCode(ctx, code))
+	validation, err := suite.service.ValidateQRCode(ctx, code)
+	suite.Require().NoError(err)
+	suite.False(validation.IsValid)
+	suite.Equal(ticketRevokedMessage, validation.Message)
+
+	key, err := suite.service.GetTicketVerificationKey(ctx)
+	suite.Require().NoError(err)
+	suite.Len(key.Revocations, 1)
+	suite.Equal([]byte(suite.signer.PublicKey()), key.PublicKey)
+
+	// Revoking the attendee covers every ticket issued so far
+	other := suite.issueTicket()
+	suite.Require().NoError(suite.service.DeactivateQRCode(ctx, entities.BuildSceneStr(entities.QRResourceAttendee, suite.attendee.ID)))
+	result, err := suite.service.ProcessQRCodeScan(ctx, other, nil)
+	suite.Require().NoError(err)
+	suite.False(result.Success)
+	suite.Equal(ticketRevokedMessage, result.Message)
+}
+
+func (suite *QRCodeServiceTestSuite) TestOfflineScanBeforeExpiry() {
+	ctx := context.Background()
+	code := suite.signer.Sign(&entities.QRCodeData{
+		EventID:    suite.event.ID,
+		AttendeeID: suite.attendee.ID,
+		Timestamp:  time.Now().Add(-3 * time.Hour),
+		ExpiresAt:  time.Now().Add(-time.Hour),
+	})
+
+	result, err := suite.service.ProcessQRCodeScan(ctx, code, nil)
+	suite.Require().NoError(err)
+	suite.False(result.Success)
+	suite.Equal(ticketExpiredMessage, result.Message)
+
+	scannedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	result, err = suite.service.ProcessQRCodeScan(ctx, code, &services.QRScannerInfo{Timestamp: scannedAt})
+	suite.Require().NoError(err)
+	suite.True(result.Success)
+	suite.Equal(scannedAt, *suite.attendeeRepo.attendees[0].CheckInDate)
+
+	// The claim outlives the expired ticket, so a replayed offline scan is still rejected
+	result, err = suite.service.ProcessQRCodeScan(ctx, code, &services.QRScannerInfo{Timestamp: scannedAt})
+	suite.Require().NoError(err)
+	suite.False(result.Success)
+	suite.Equal(ticketUsedMessage, result.Message)
+}
+
+func (suite *QRCodeServiceTestSuite) TestTicketsDisabledWithoutSigningKey() {
+	ctx := context.Background()
+	signer, err := NewTicketSignerFromConfig("")
+	suite.Require().NoError(err)
+	suite.Nil(signer)
+
+	eventRepo := &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{suite.event.ID: suite.event}}
+	disabled := NewQRCodeService(eventRepo, suite.attendeeRepo, nil, nil, &QRCodeServiceConfig{}, zap.NewNop())
+
+	_, err = disabled.GenerateAttendeeQRCode(ctx, suite.attendee.ID)
+	suite.ErrorIs(err, services.ErrTicketSigningDisabled)
+	_, err = disabled.GetTicketVerificationKey(ctx)
+	suite.ErrorIs(err, services.ErrTicketSigningDisabled)
+
+	result, err := disabled.ProcessQRCodeScan(ctx, suite.issueTicket(), nil)
+	suite.Require().NoError(err)
+	suite.False(result.Success)
+	suite.Equal(ticketInvalidMessage, result.Message)
+}
+
+func TestQRCodeServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(QRCodeServiceTestSuite))
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// surveyAnalyticsMaxAge is how long stored analytics are served before they are recalculated
+	surveyAnalyticsMaxAge = 15 * time.Minute
+	// surveyAnalyticsRefreshLimit caps the number of surveys recalculated by one refresh run
+	surveyAnalyticsRefreshLimit = 200
+	// surveyAnalyticsBatchSize is the number of responses read from the database at a time
+	surveyAnalyticsBatchSize = 500
+)
+
+// SurveyAnalyticsServiceImpl implements the SurveyAnalyticsService interface
+type SurveyAnalyticsServiceImpl struct {
+	surveyRepo    repositories.SurveyRepository
+	responseRepo  repositories.SurveyResponseRepository
+	analyticsRepo repositories.SurveyAnalyticsRepository
+	versionRepo   repositories.SurveyVersionRepository
+	logger        *zap.Logger
+}
+
+// NewSurveyAnalyticsService creates a new survey analytics service implementation
+func NewSurveyAnalyticsService(
+	surveyRepo repositories.SurveyRepository,
+	responseRepo repositories.SurveyResponseRepository,
+	analyticsRepo repositories.SurveyAnalyticsRepository,
+	versionRepo repositories.SurveyVersionRepository,
+	logger *zap.Logger,
+) services.SurveyAnalyticsService {
+	return &SurveyAnalyticsServiceImpl{
+		surveyRepo:    surveyRepo,
+		responseRepo:  responseRepo,
+		analyticsRepo: analyticsRepo,
+		versionRepo:   versionRepo,
+		logger:        logger,
+	}
+}
+
+// GetAnalytics returns the stored analytics while they are recent, and recalculates them otherwise
+func (s *SurveyAnalyticsServiceImpl) GetAnalytics(ctx context.Context, surveyID uuid.UUID) (*entities.SurveyAnalytics, error) {
+	analytics, err := s.analyticsRepo.FindBySurveyID(ctx, surveyID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || time.Since(analytics.LastCalculated) > surveyAnalyticsMaxAge {
+		return s.RefreshAnalytics(ctx, surveyID)
+	}
+
+	if analytics.Questions, err = analytics.GetQuestionStats(); err != nil {
+		return nil, err
+	}
+	return analytics, nil
+}
+
+// RefreshAnalytics walks every response of the survey and stores the aggregated analytics. Answers
+// collected under earlier versions of the survey are counted with the current version.
+func (s *SurveyAnalyticsServiceImpl) RefreshAnalytics(ctx context.Context, surveyID uuid.UUID) (*entities.SurveyAnalytics, error) {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregator, err := s.aggregate(ctx, survey, repositories.SurveyResponseFilter{SurveyID: surveyID})
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := s.analyticsRepo.FindBySurveyID(ctx, surveyID)
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		analytics = &entities.SurveyAnalytics{ID: uuid.New(), SurveyID: surveyID}
+		if err := aggregator.Apply(analytics); err != nil {
+			return nil, err
+		}
+		err = s.analyticsRepo.Create(ctx, analytics)
+	case err == nil:
+		if err := aggregator.Apply(analytics); err != nil {
+			return nil, err
+		}
+		err = s.analyticsRepo.Update(ctx, analytics)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Survey analytics refreshed",
+		zap.String("surveyId", surveyID.String()),
+		zap.Int("starts", analytics.TotalStarts),
+		zap.Int("completions", analytics.TotalCompletions))
+	return analytics, nil
+}
+
+// GetCollectorAnalytics calculates the analytics of the responses collected by one collector of a
+// survey. They are not stored, as the split is only looked at on demand.
+func (s *SurveyAnalyticsServiceImpl) GetCollectorAnalytics(ctx context.Context, surveyID, collectorID uuid.UUID) (*entities.SurveyAnalytics, error) {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregator, err := s.aggregate(ctx, survey, repositories.SurveyResponseFilter{SurveyID: surveyID, CollectorID: &collectorID})
+	if err != nil {
+		return nil, err
+	}
+	analytics := &entities.SurveyAnalytics{SurveyID: surveyID}
+	if err := aggregator.Apply(analytics); err != nil {
+		return nil, err
+	}
+	return analytics, nil
+}
+
+// aggregate walks the responses matching the filter
+func (s *SurveyAnalyticsServiceImpl) aggregate(ctx context.Context, survey *entities.Survey, filter repositories.SurveyResponseFilter) (*entities.SurveyAnalyticsAggregator, error) {
+	mapping, err := s.answerMapping(ctx, survey)
+	if err != nil {
+		return nil, err
+	}
+	aggregator := entities.NewSurveyAnalyticsAggregator(mapping.Questions)
+	err = s.responseRepo.FindInBatches(ctx, filter, surveyAnalyticsBatchSize,
+		func(responses []entities.SurveyResponse) error {
+			for i := range responses {
+				mapping.MapResponse(&responses[i])
+				aggregator.Add(&responses[i])
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read survey responses: %w", err)
+	}
+	return aggregator, nil
+}
+
+// answerMapping maps the answers collected under earlier versions of the survey onto its current version
+func (s *SurveyAnalyticsServiceImpl) answerMapping(ctx context.Context, survey *entities.Survey) (*entities.SurveyAnswerMapping, error) {
+	versions, err := s.versionRepo.FindBySurveyID(ctx, survey.ID)
+	if err != nil {
+		return nil, err
+	}
+	return entities.NewSurveyAnswerMapping(survey.Questions, versions)
+}
+
+// RefreshActiveSurveys recalculates the analytics of published surveys. A survey that fails is
+// logged and skipped so the others are still refreshed.
+func (s *SurveyAnalyticsServiceImpl) RefreshActiveSurveys(ctx context.Context) (int, error) {
+	surveys, err := s.surveyRepo.FindActive(ctx, surveyAnalyticsRefreshLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, survey := range surveys {
+		if _, err := s.RefreshAnalytics(ctx, survey.ID); err != nil {
+			s.logger.Error("Failed to refresh survey analytics",
+				zap.String("surveyId", survey.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// GetCrossTab cross-tabulates two questions over the finished responses of a survey
+func (s *SurveyAnalyticsServiceImpl) GetCrossTab(ctx context.Context, surveyID, rowQuestionID, columnQuestionID uuid.UUID) (*entities.SurveyCrossTab, error) {
+	if rowQuestionID == columnQuestionID {
+		return nil, fmt.Errorf("%w: a question cannot be cross-tabulated with itself", services.ErrInvalidSurveyCrossTab)
+	}
+
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.answerMapping(ctx, survey)
+	if err != nil {
+		return nil, err
+	}
+	var row, column *entities.SurveyQuestion
+	for i := range mapping.Questions {
+		switch mapping.Questions[i].ID {
+		case rowQuestionID:
+			row = &mapping.Questions[i]
+		case columnQuestionID:
+			column = &mapping.Questions[i]
+		}
+	}
+	if row == nil || column == nil {
+		return nil, fmt.Errorf("%w: questions do not belong to the survey", services.ErrInvalidSurveyCrossTab)
+	}
+
+	crossTab := entities.NewSurveyCrossTab(row, column)
+	completed := true
+	filter := repositories.SurveyResponseFilter{SurveyID: surveyID, IsCompleted: &completed}
+	err = s.responseRepo.FindInBatches(ctx, filter, surveyAnalyticsBatchSize,
+		func(responses []entities.SurveyResponse) error {
+			for i := range responses {
+				mapping.MapResponse(&responses[i])
+				crossTab.Add(responses[i].Answers)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read survey responses: %w", err)
+	}
+	return crossTab, nil
+}
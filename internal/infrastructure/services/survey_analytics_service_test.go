@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// memorySurveyAnalyticsRepository keeps analytics in memory
+type memorySurveyAnalyticsRepository struct {
+	repositories.SurveyAnalyticsRepository
+	analytics map[uuid.UUID]entities.SurveyAnalytics
+}
+
+func (r *memorySurveyAnalyticsRepository) Create(ctx context.Context, analytics *entities.SurveyAnalytics) error {
+	r.analytics[analytics.SurveyID] = *analytics
+	return nil
+}
+
+func (r *memorySurveyAnalyticsRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) (*entities.SurveyAnalytics, error) {
+	analytics, ok := r.analytics[surveyID]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	analytics.Questions = nil
+	return &analytics, nil
+}
+
+func (r *memorySurveyAnalyticsRepository) Update(ctx context.Context, analytics *entities.SurveyAnalytics) error {
+	r.analytics[analytics.SurveyID] = *analytics
+	return nil
+}
+
+type SurveyAnalyticsServiceTestSuite struct {
+	suite.Suite
+	repo      *memorySurveyAnalyticsRepository
+	responses *memorySurveyResponseRepository
+	versions  *memorySurveyVersionRepository
+	service   services.SurveyAnalyticsService
+	survey    *entities.Survey
+	ctx       context.Context
+}
+
+func (suite *SurveyAnalyticsServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.survey = &entities.Survey{
+		ID:    uuid.New(),
+		Title: "Event feedback",
+		Questions: []entities.SurveyQuestion{
+			{ID: uuid.New(), Order: 1, QuestionType: entities.QuestionTypeRadio, QuestionText: "Role", Options: []string{"Speaker", "Attendee"}},
+			{ID: uuid.New(), Order: 2, QuestionType: entities.QuestionTypeYesNo, QuestionText: "Attend again?"},
+		},
+	}
+
+	surveys := &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	answers := &memorySurveyAnswerRepository{}
+	suite.responses = &memorySurveyResponseRepository{answers: answers}
+	yes, no := true, false
+	for i, role := range []string{"Speaker", "Attendee", "Attendee"} {
+		response := &entities.SurveyResponse{ID: uuid.New(), SurveyID: suite.survey.ID, Status: entities.ResponseStatusSubmitted}
+		again := &yes
+		if i == 2 {
+			again = &no
+		}
+		suite.responses.responses = append(suite.responses.responses, response)
+		answers.answers = append(answers.answers,
+			entities.SurveyAnswer{ResponseID: response.ID, QuestionID: suite.survey.Questions[0].ID, AnswerText: role},
+			entities.SurveyAnswer{ResponseID: response.ID, QuestionID: suite.survey.Questions[1].ID, AnswerBool: again},
+		)
+	}
+
+	suite.repo = &memorySurveyAnalyticsRepository{analytics: make(map[uuid.UUID]entities.SurveyAnalytics)}
+	suite.versions = &memorySurveyVersionRepository{}
+	suite.service = NewSurveyAnalyticsService(surveys, suite.responses, suite.repo, suite.versions, zap.NewNop())
+}
+
+func (suite *SurveyAnalyticsServiceTestSuite) TestGetAnalyticsCalculatesAndCaches() {
+	analytics, err := suite.service.GetAnalytics(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Equal(3, analytics.TotalCompletions)
+	suite.Require().Len(analytics.Questions, 2)
+	suite.Equal(2, analytics.Questions[0].Choices[1].Count)
+	suite.Contains(suite.repo.analytics, suite.survey.ID)
+
+	// Recent analytics are served as stored, without counting new responses
+	suite.responses.responses = append(suite.responses.responses,
+		&entities.SurveyResponse{ID: uuid.New(), SurveyID: suite.survey.ID, Status: entities.ResponseStatusAbandoned})
+	cached, err := suite.service.GetAnalytics(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Equal(3, cached.TotalStarts)
+	suite.Len(cached.Questions, 2)
+
+	// Out of date analytics are recalculated
+	stale := suite.repo.analytics[suite.survey.ID]
+	stale.LastCalculated = time.Now().Add(-time.Hour)
+	suite.repo.analytics[suite.survey.ID] = stale
+	refreshed, err := suite.service.GetAnalytics(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Equal(4, refreshed.TotalStarts)
+	suite.Equal(stale.ID, refreshed.ID)
+}
+
+func (suite *SurveyAnalyticsServiceTestSuite) TestGetCrossTab() {
+	role, again := suite.survey.Questions[0].ID, suite.survey.Questions[1].ID
+
+	crossTab, err := suite.service.GetCrossTab(suite.ctx, suite.survey.ID, role, again)
+	suite.Require().NoError(err)
+	suite.Equal(3, crossTab.Total)
+	suite.Equal([]string{"Speaker", "Attendee"}, crossTab.Rows)
+	suite.Equal([]string{"true", "false"}, crossTab.Columns)
+	suite.Equal([][]int{{1, 0}, {1, 1}}, crossTab.Counts)
+
+	_, err = suite.service.GetCrossTab(suite.ctx, suite.survey.ID, role, role)
+	suite.ErrorIs(err, services.ErrInvalidSurveyCrossTab)
+	_, err = suite.service.GetCrossTab(suite.ctx, suite.survey.ID, role, uuid.New())
+	suite.ErrorIs(err, services.ErrInvalidSurveyCrossTab)
+	_, err = suite.service.GetCrossTab(suite.ctx, uuid.New(), role, again)
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func (suite *SurveyAnalyticsServiceTestSuite) TestGetCollectorAnalytics() {
+	collectorID := uuid.New()
+	suite.responses.responses[1].CollectorID = &collectorID
+
+	analytics, err := suite.service.GetCollectorAnalytics(suite.ctx, suite.survey.ID, collectorID)
+	suite.Require().NoError(err)
+	suite.Equal(1, analytics.TotalCompletions)
+	suite.Require().Len(analytics.Questions, 2)
+	suite.Equal(1, analytics.Questions[0].Choices[1].Count)
+	suite.NotContains(suite.repo.analytics, suite.survey.ID)
+}
+
+func (suite *SurveyAnalyticsServiceTestSuite) TestAnalyticsMapEarlierVersions() {
+	// Version 1 called attendees guests and asked for comments, since removed
+	comments := entities.SurveyQuestion{ID: uuid.New(), Order: 3, QuestionType: entities.QuestionTypeText, QuestionText: "Comments"}
+	first := *suite.survey
+	first.Version = 1
+	first.Questions = []entities.SurveyQuestion{suite.survey.Questions[0], suite.survey.Questions[1], comments}
+	first.Questions[0].Options = []string{"Speaker", "Guest"}
+	version, err := entities.NewSurveyVersion(&first, "", nil)
+	suite.Require().NoError(err)
+	suite.versions.versions = append(suite.versions.versions, *version)
+	suite.survey.Version = 2
+
+	response := &entities.SurveyResponse{ID: uuid.New(), SurveyID: suite.survey.ID, Status: entities.ResponseStatusSubmitted, SurveyVersion: 1}
+	suite.responses.responses = append(suite.responses.responses, response)
+	suite.responses.answers.answers = append(suite.responses.answers.answers,
+		entities.SurveyAnswer{ResponseID: response.ID, QuestionID: suite.survey.Questions[0].ID, AnswerText: "Guest"},
+		entities.SurveyAnswer{ResponseID: response.ID, QuestionID: comments.ID, AnswerText: "Great venue"},
+	)
+
+	analytics, err := suite.service.GetAnalytics(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Equal(4, analytics.TotalCompletions)
+	suite.Require().Len(analytics.Questions, 3)
+	suite.Equal("Attendee", analytics.Questions[0].Choices[1].Value)
+	suite.Equal(3, analytics.Questions[0].Choices[1].Count)
+	suite.Equal(comments.ID, analytics.Questions[2].QuestionID)
+	suite.Equal(1, analytics.Questions[2].Answered)
+}
+
+func TestSurveyAnalyticsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyAnalyticsServiceTestSuite))
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+const (
+	collectorLinkMessage   = "点击参与调研：<a href=\"%s\">%s</a>"
+	collectorClosedMessage = "该调研已停止收集答卷，感谢您的关注。"
+)
+
+// SurveyCollectorServiceImpl implements the SurveyCollectorService interface
+type SurveyCollectorServiceImpl struct {
+	collectorRepo repositories.SurveyCollectorRepository
+	surveyRepo    repositories.SurveyRepository
+	qrCodes       services.SurveyCollectorQRCodeGenerator
+	baseURL       string
+	logger        *zap.Logger
+}
+
+// NewSurveyCollectorService creates a new survey collector service implementation. Collector links
+// open the survey pages at baseURL; qrCodes may be nil when WeChat QR codes are not available.
+func NewSurveyCollectorService(
+	collectorRepo repositories.SurveyCollectorRepository,
+	surveyRepo repositories.SurveyRepository,
+	qrCodes services.SurveyCollectorQRCodeGenerator,
+	baseURL string,
+	logger *zap.Logger,
+) *SurveyCollectorServiceImpl {
+	return &SurveyCollectorServiceImpl{
+		collectorRepo: collectorRepo,
+		surveyRepo:    surveyRepo,
+		qrCodes:       qrCodes,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		logger:        logger,
+	}
+}
+
+// RegisterHandlers replies to scans of collector QR codes with the collector link
+func (s *SurveyCollectorServiceImpl) RegisterHandlers(router *wechat.MessageRouter) {
+	router.HandleScene(entities.QRResourceCollector+"_", s.handleScan)
+}
+
+// CreateCollector validates and stores a collector with its link. QR code collectors get their
+// WeChat QR code right away when QR codes are available.
+func (s *SurveyCollectorServiceImpl) CreateCollector(ctx context.Context, collector *entities.SurveyCollector) error {
+	if err := collector.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.surveyRepo.FindByID(ctx, collector.SurveyID); err != nil {
+		return err
+	}
+	if strings.TrimSpace(collector.Settings) == "" {
+		collector.Settings = "{}"
+	}
+	collector.ResponseCount = 0
+	collector.BuildLinks(s.baseURL)
+	if err := s.collectorRepo.Create(ctx, collector); err != nil {
+		return err
+	}
+
+	if collector.Type == entities.SurveyCollectorTypeQRCode && s.qrCodes != nil {
+		if _, err := s.generateQRCode(ctx, collector); err != nil {
+			s.logger.Warn("Failed to generate survey collector QR code",
+				zap.String("collectorId", collector.ID.String()), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Survey collector created",
+		zap.String("surveyId", collector.SurveyID.String()),
+		zap.String("collectorId", collector.ID.String()),
+		zap.String("type", collector.Type))
+	return nil
+}
+
+// GetCollector retrieves a collector by ID
+func (s *SurveyCollectorServiceImpl) GetCollector(ctx context.Context, id uuid.UUID) (*entities.SurveyCollector, error) {
+	return s.collectorRepo.FindByID(ctx, id)
+}
+
+// GetSurveyCollectors retrieves every collector of a survey
+func (s *SurveyCollectorServiceImpl) GetSurveyCollectors(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyCollector, error) {
+	return s.collectorRepo.FindBySurveyID(ctx, surveyID)
+}
+
+// UpdateCollector validates and updates a collector's settings, rebuilding its links
+func (s *SurveyCollectorServiceImpl) UpdateCollector(ctx context.Context, collector *entities.SurveyCollector) error {
+	if err := collector.Validate(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(collector.Settings) == "" {
+		collector.Settings = "{}"
+	}
+	collector.BuildLinks(s.baseURL)
+	return s.collectorRepo.Update(ctx, collector)
+}
+
+// DeleteCollector deletes a collector. Its responses are kept without a collector.
+func (s *SurveyCollectorServiceImpl) DeleteCollector(ctx context.Context, id uuid.UUID) error {
+	return s.collectorRepo.Delete(ctx, id)
+}
+
+// SetCollectorActive opens or closes a collector for new responses
+func (s *SurveyCollectorServiceImpl) SetCollectorActive(ctx context.Context, id uuid.UUID, active bool) (*entities.SurveyCollector, error) {
+	collector, err := s.collectorRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	collector.IsActive = active
+	if err := s.collectorRepo.Update(ctx, collector); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Survey collector updated",
+		zap.String("collectorId", collector.ID.String()),
+		zap.Bool("isActive", active))
+	return collector, nil
+}
+
+// GenerateQRCode creates a WeChat QR code for the collector link and stores its URL on the collector
+func (s *SurveyCollectorServiceImpl) GenerateQRCode(ctx context.Context, id uuid.UUID) (*entities.WeChatQrCode, error) {
+	collector, err := s.collectorRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.qrCodes == nil {
+		return nil, fmt.Errorf("%w: WeChat QR codes are not configured", entities.ErrInvalidCollector)
+	}
+	return s.generateQRCode(ctx, collector)
+}
+
+// OpenCollector finds a collector of the survey that accepts responses
+func (s *SurveyCollectorServiceImpl) OpenCollector(ctx context.Context, surveyID, collectorID uuid.UUID) (*entities.SurveyCollector, error) {
+	collector, err := s.collectorRepo.FindByID(ctx, collectorID)
+	if err != nil {
+		return nil, err
+	}
+	if collector.SurveyID != surveyID {
+		return nil, repositories.ErrNotFound
+	}
+	if !collector.AcceptsResponses(time.Now()) {
+		return nil, services.ErrSurveyCollectorClosed
+	}
+	return collector, nil
+}
+
+// ReserveResponse counts a submitted response against its collector. The count is taken in one
+// conditional update so concurrent submissions cannot exceed the collector's maximum.
+func (s *SurveyCollectorServiceImpl) ReserveResponse(ctx context.Context, collectorID uuid.UUID) error {
+	collector, err := s.collectorRepo.FindByID(ctx, collectorID)
+	if err != nil {
+		return err
+	}
+	if !collector.AcceptsResponses(time.Now()) {
+		return services.ErrSurveyCollectorClosed
+	}
+	ok, err := s.collectorRepo.IncrementIfAvailable(ctx, collectorID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return services.ErrSurveyCollectorClosed
+	}
+	return nil
+}
+
+// ReleaseResponse gives back a response counted by ReserveResponse
+func (s *SurveyCollectorServiceImpl) ReleaseResponse(ctx context.Context, collectorID uuid.UUID) error {
+	return s.collectorRepo.Decrement(ctx, collectorID)
+}
+
+// generateQRCode creates the collector's WeChat QR code and stores its URL on the collector
+func (s *SurveyCollectorServiceImpl) generateQRCode(ctx context.Context, collector *entities.SurveyCollector) (*entities.WeChatQrCode, error) {
+	settings, err := collector.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	qrCode, err := s.qrCodes.GenerateSurveyCollectorQRCode(ctx, collector, settings.QRCodeType)
+	if err != nil {
+		return nil, err
+	}
+	collector.QRCodeURL = qrCode.Url
+	if err := s.collectorRepo.Update(ctx, collector); err != nil {
+		return nil, err
+	}
+	return qrCode, nil
+}
+
+// handleScan replies to a collector QR code scan with the collector link, or a notice when the
+// collector no longer accepts responses
+func (s *SurveyCollectorServiceImpl) handleScan(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	_, value, _ := entities.ParseSceneStr(wechat.SceneStr(msg))
+	collectorID, err := uuid.Parse(value)
+	if err != nil {
+		return wechat.TextReply(invalidQRCodeMessage), nil
+	}
+
+	collector, err := s.collectorRepo.FindByID(ctx, collectorID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return wechat.TextReply(invalidQRCodeMessage), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !collector.AcceptsResponses(time.Now()) {
+		return wechat.TextReply(collectorClosedMessage), nil
+	}
+
+	title := collector.Name
+	if survey, err := s.surveyRepo.FindByID(ctx, collector.SurveyID); err == nil {
+		title = survey.Title
+	}
+	return wechat.TextReply(fmt.Sprintf(collectorLinkMessage, collector.URL, title)), nil
+}
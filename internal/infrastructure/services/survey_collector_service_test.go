@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// memorySurveyCollectorRepository keeps collectors in memory
+type memorySurveyCollectorRepository struct {
+	repositories.SurveyCollectorRepository
+	collectors []*entities.SurveyCollector
+}
+
+func (r *memorySurveyCollectorRepository) Create(ctx context.Context, collector *entities.SurveyCollector) error {
+	if collector.ID == uuid.Nil {
+		collector.ID = uuid.New()
+	}
+	copied := *collector
+	r.collectors = append(r.collectors, &copied)
+	return nil
+}
+
+func (r *memorySurveyCollectorRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyCollector, error) {
+	for _, collector := range r.collectors {
+		if collector.ID == id {
+			copied := *collector
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyCollectorRepository) Update(ctx context.Context, collector *entities.SurveyCollector) error {
+	for i, stored := range r.collectors {
+		if stored.ID == collector.ID {
+			copied := *collector
+			copied.ResponseCount = stored.ResponseCount
+			r.collectors[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyCollectorRepository) IncrementIfAvailable(ctx context.Context, id uuid.UUID) (bool, error) {
+	for _, collector := range r.collectors {
+		if collector.ID == id {
+			if collector.IsFull() {
+				return false, nil
+			}
+			collector.ResponseCount++
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memorySurveyCollectorRepository) Decrement(ctx context.Context, id uuid.UUID) error {
+	for _, collector := range r.collectors {
+		if collector.ID == id && collector.ResponseCount > 0 {
+			collector.ResponseCount--
+		}
+	}
+	return nil
+}
+
+// fakeSurveyCollectorQRCodeGenerator records the QR codes generated for collectors
+type fakeSurveyCollectorQRCodeGenerator struct {
+	generated map[uuid.UUID]entities.QRCodeType
+}
+
+func (g *fakeSurveyCollectorQRCodeGenerator) GenerateSurveyCollectorQRCode(ctx context.Context, collector *entities.SurveyCollector, qrType entities.QRCodeType) (*entities.WeChatQrCode, error) {
+	g.generated[collector.ID] = qrType
+	return &entities.WeChatQrCode{ID: uuid.New(), Url: "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=" + collector.ID.String()}, nil
+}
+
+type SurveyCollectorServiceTestSuite struct {
+	suite.Suite
+	repo    *memorySurveyCollectorRepository
+	qrCodes *fakeSurveyCollectorQRCodeGenerator
+	service *SurveyCollectorServiceImpl
+	survey  *entities.Survey
+	ctx     context.Context
+}
+
+func (suite *SurveyCollectorServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.survey = &entities.Survey{ID: uuid.New(), Title: "Event feedback", Status: entities.SurveyStatusPublished}
+	surveys := &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	suite.repo = &memorySurveyCollectorRepository{}
+	suite.qrCodes = &fakeSurveyCollectorQRCodeGenerator{generated: make(map[uuid.UUID]entities.QRCodeType)}
+	suite.service = NewSurveyCollectorService(suite.repo, surveys, suite.qrCodes, "https://events.example.com/", zap.NewNop())
+}
+
+func (suite *SurveyCollectorServiceTestSuite) newCollector(collectorType string) *entities.SurveyCollector {
+	return &entities.SurveyCollector{
+		ID:        uuid.New(),
+		SurveyID:  suite.survey.ID,
+		Name:      "Booth",
+		Type:      collectorType,
+		IsActive:  true,
+		CreatedBy: uuid.New(),
+	}
+}
+
+func (suite *SurveyCollectorServiceTestSuite) TestCreateBuildsLinksAndQRCode() {
+	collector := suite.newCollector(entities.SurveyCollectorTypeQRCode)
+	collector.Settings = `{"qrCodeType":"temporary"}`
+	collector.ResponseCount = 12
+	suite.Require().NoError(suite.service.CreateCollector(suite.ctx, collector))
+
+	stored, err := suite.service.GetCollector(suite.ctx, collector.ID)
+	suite.Require().NoError(err)
+	suite.Equal("https://events.example.com/surveys/"+suite.survey.ID.String()+"?collector="+collector.ID.String(), stored.URL)
+	suite.Zero(stored.ResponseCount)
+	suite.Equal(entities.QRCodeTypeTemporary, suite.qrCodes.generated[collector.ID])
+	suite.Contains(stored.QRCodeURL, collector.ID.String())
+
+	webLink := suite.newCollector(entities.SurveyCollectorTypeWebLink)
+	suite.Require().NoError(suite.service.CreateCollector(suite.ctx, webLink))
+	suite.NotContains(suite.qrCodes.generated, webLink.ID)
+	suite.Equal("{}", webLink.Settings)
+
+	unknown := suite.newCollector(entities.SurveyCollectorTypeWebLink)
+	unknown.SurveyID = uuid.New()
+	suite.ErrorIs(suite.service.CreateCollector(suite.ctx, unknown), repositories.ErrNotFound)
+	invalid := suite.newCollector("sms")
+	suite.ErrorIs(suite.service.CreateCollector(suite.ctx, invalid), entities.ErrInvalidCollector)
+}
+
+func (suite *SurveyCollectorServiceTestSuite) TestReserveRespectsLimitAndState() {
+	limit := 1
+	collector := suite.newCollector(entities.SurveyCollectorTypeEmail)
+	collector.MaxResponses = &limit
+	suite.Require().NoError(suite.service.CreateCollector(suite.ctx, collector))
+
+	_, err := suite.service.OpenCollector(suite.ctx, suite.survey.ID, collector.ID)
+	suite.Require().NoError(err)
+	_, err = suite.service.OpenCollector(suite.ctx, uuid.New(), collector.ID)
+	suite.ErrorIs(err, repositories.ErrNotFound)
+
+	suite.Require().NoError(suite.service.ReserveResponse(suite.ctx, collector.ID))
+	suite.ErrorIs(suite.service.ReserveResponse(suite.ctx, collector.ID), services.ErrSurveyCollectorClosed)
+	_, err = suite.service.OpenCollector(suite.ctx, suite.survey.ID, collector.ID)
+	suite.ErrorIs(err, services.ErrSurveyCollectorClosed)
+
+	suite.Require().NoError(suite.service.ReleaseResponse(suite.ctx, collector.ID))
+	closed, err := suite.service.SetCollectorActive(suite.ctx, collector.ID, false)
+	suite.Require().NoError(err)
+	suite.False(closed.IsActive)
+	suite.ErrorIs(suite.service.ReserveResponse(suite.ctx, collector.ID), services.ErrSurveyCollectorClosed)
+}
+
+func (suite *SurveyCollectorServiceTestSuite) TestOpenCollectorFollowsSchedule() {
+	opensAt := time.Now().Add(time.Hour)
+	collector := suite.newCollector(entities.SurveyCollectorTypeWebLink)
+	collector.OpensAt = &opensAt
+	suite.Require().NoError(suite.service.CreateCollector(suite.ctx, collector))
+
+	_, err := suite.service.OpenCollector(suite.ctx, suite.survey.ID, collector.ID)
+	suite.ErrorIs(err, services.ErrSurveyCollectorClosed)
+}
+
+func (suite *SurveyCollectorServiceTestSuite) TestGenerateQRCodeRequiresWeChat() {
+	collector := suite.newCollector(entities.SurveyCollectorTypeWebLink)
+	suite.Require().NoError(suite.service.CreateCollector(suite.ctx, collector))
+
+	qrCode, err := suite.service.GenerateQRCode(suite.ctx, collector.ID)
+	suite.Require().NoError(err)
+	suite.NotEmpty(qrCode.Url)
+
+	suite.service.qrCodes = nil
+	_, err = suite.service.GenerateQRCode(suite.ctx, collector.ID)
+	suite.ErrorIs(err, entities.ErrInvalidCollector)
+}
+
+func TestSurveyCollectorServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyCollectorServiceTestSuite))
+}
@@ -0,0 +1,663 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// surveyExportBatchSize is the number of responses read from the database at a time
+	surveyExportBatchSize = 500
+	// surveyExportRetention is how long export files can be downloaded
+	surveyExportRetention = 7 * 24 * time.Hour
+	// surveyExportCleanupBatchSize is the number of expired export files deleted per run
+	surveyExportCleanupBatchSize = 200
+	// surveyExportListLimit caps the number of exports listed per survey
+	surveyExportListLimit = 50
+)
+
+// surveyExportFixedColumns come before the question columns of every export
+var surveyExportFixedColumns = []string{"Response ID", "Status", "Respondent ID", "Started At", "Submitted At", "Time Spent (s)"}
+
+// spssFixedVariables are the SPSS variable names of the fixed columns
+var spssFixedVariables = []string{"response_id", "status", "respondent_id", "started_at", "submitted_at", "time_spent"}
+
+// SurveyExportServiceImpl implements the SurveyExportService interface
+type SurveyExportServiceImpl struct {
+	surveyRepo   repositories.SurveyRepository
+	questionRepo repositories.SurveyQuestionRepository
+	versionRepo  repositories.SurveyVersionRepository
+	responseRepo repositories.SurveyResponseRepository
+	exportRepo   repositories.SurveyExportRepository
+	storage      storage.StorageProvider
+	queue        services.SurveyExportQueue
+	logger       *zap.Logger
+}
+
+// NewSurveyExportService creates a new survey export service implementation. Without a queue
+// exports are processed in the background of the current process.
+func NewSurveyExportService(
+	surveyRepo repositories.SurveyRepository,
+	questionRepo repositories.SurveyQuestionRepository,
+	versionRepo repositories.SurveyVersionRepository,
+	responseRepo repositories.SurveyResponseRepository,
+	exportRepo repositories.SurveyExportRepository,
+	storageProvider storage.StorageProvider,
+	queue services.SurveyExportQueue,
+	logger *zap.Logger,
+) services.SurveyExportService {
+	return &SurveyExportServiceImpl{
+		surveyRepo:   surveyRepo,
+		questionRepo: questionRepo,
+		versionRepo:  versionRepo,
+		responseRepo: responseRepo,
+		exportRepo:   exportRepo,
+		storage:      storageProvider,
+		queue:        queue,
+		logger:       logger,
+	}
+}
+
+// RequestExport validates and stores a pending export, then queues it
+func (s *SurveyExportServiceImpl) RequestExport(ctx context.Context, export *entities.SurveyExport) error {
+	if err := export.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.surveyRepo.FindByID(ctx, export.SurveyID); err != nil {
+		return err
+	}
+
+	if export.ID == uuid.Nil {
+		export.ID = uuid.New()
+	}
+	if export.Filters == "" {
+		export.Filters = "{}"
+	}
+	export.Status = entities.SurveyExportStatusPending
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return err
+	}
+
+	if s.queue == nil {
+		go s.processInBackground(export.ID)
+		return nil
+	}
+	if err := s.queue.EnqueueSurveyExport(ctx, export.ID); err != nil {
+		s.fail(ctx, export, err)
+		return err
+	}
+
+	s.logger.Info("Survey export queued",
+		zap.String("surveyId", export.SurveyID.String()),
+		zap.String("exportId", export.ID.String()),
+		zap.String("exportType", export.ExportType))
+	return nil
+}
+
+// GetExport retrieves an export by ID
+func (s *SurveyExportServiceImpl) GetExport(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, error) {
+	return s.exportRepo.FindByID(ctx, id)
+}
+
+// GetSurveyExports retrieves the latest exports of a survey
+func (s *SurveyExportServiceImpl) GetSurveyExports(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyExport, error) {
+	return s.exportRepo.FindBySurveyID(ctx, surveyID, surveyExportListLimit)
+}
+
+// ProcessExport writes the export file to a temporary file, stores it and completes the export.
+// Completed exports are left alone, so retried jobs do not export twice.
+func (s *SurveyExportServiceImpl) ProcessExport(ctx context.Context, id uuid.UUID) error {
+	export, err := s.exportRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if export.IsCompleted() {
+		return nil
+	}
+
+	now := time.Now()
+	export.Status = entities.SurveyExportStatusProcessing
+	export.StartedAt = &now
+	export.ErrorMessage = ""
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return err
+	}
+
+	if err := s.writeExport(ctx, export); err != nil {
+		s.fail(ctx, export, err)
+		return err
+	}
+
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(surveyExportRetention)
+	export.Status = entities.SurveyExportStatusCompleted
+	export.CompletedAt = &completedAt
+	export.ExpiresAt = &expiresAt
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return err
+	}
+
+	s.logger.Info("Survey export completed",
+		zap.String("surveyId", export.SurveyID.String()),
+		zap.String("exportId", export.ID.String()),
+		zap.Int("recordCount", export.RecordCount),
+		zap.Int64("fileSize", export.FileSize),
+		zap.Duration("duration", completedAt.Sub(now)))
+	return nil
+}
+
+// OpenExportFile opens the file of a completed export that has not expired. Export files are kept
+// in private storage, so they are only reachable through this method.
+func (s *SurveyExportServiceImpl) OpenExportFile(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, io.ReadCloser, error) {
+	export, err := s.exportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !export.IsCompleted() {
+		return nil, nil, services.ErrSurveyExportNotReady
+	}
+	if export.IsExpired() || export.FileKey == "" {
+		return nil, nil, services.ErrSurveyExportExpired
+	}
+	file, err := s.storage.Download(ctx, export.FileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, file, nil
+}
+
+// DeleteExpiredExportFiles deletes the files of expired exports. The exports themselves are kept,
+// so their history stays listed.
+func (s *SurveyExportServiceImpl) DeleteExpiredExportFiles(ctx context.Context) (int, error) {
+	exports, err := s.exportRepo.FindExpiredWithFiles(ctx, time.Now(), surveyExportCleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for i := range exports {
+		export := &exports[i]
+		if err := s.storage.Delete(ctx, export.FileKey); err != nil {
+			s.logger.Error("Failed to delete expired survey export file", zap.String("exportId", export.ID.String()), zap.Error(err))
+			continue
+		}
+		export.FileKey = ""
+		if err := s.exportRepo.Update(ctx, export); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// processInBackground processes an export outside of the request that asked for it. Without a
+// worker there is no cron scheduler either, so the files of expired exports are deleted here too.
+func (s *SurveyExportServiceImpl) processInBackground(id uuid.UUID) {
+	ctx := context.Background()
+	if err := s.ProcessExport(ctx, id); err != nil {
+		s.logger.Error("Failed to process survey export", zap.String("exportId", id.String()), zap.Error(err))
+	}
+	if _, err := s.DeleteExpiredExportFiles(ctx); err != nil {
+		s.logger.Error("Failed to delete expired survey export files", zap.Error(err))
+	}
+}
+
+// fail marks an export as failed
+func (s *SurveyExportServiceImpl) fail(ctx context.Context, export *entities.SurveyExport, cause error) {
+	export.Status = entities.SurveyExportStatusFailed
+	export.ErrorMessage = cause.Error()
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		s.logger.Error("Failed to mark survey export as failed", zap.String("exportId", export.ID.String()), zap.Error(err))
+	}
+}
+
+// writeExport streams the matching responses into a temporary file and uploads it. Responses
+// collected under earlier versions of the survey are mapped onto the current version, and the
+// questions removed by a revision are exported after the current ones.
+func (s *SurveyExportServiceImpl) writeExport(ctx context.Context, export *entities.SurveyExport) error {
+	filters, err := export.GetFilters()
+	if err != nil {
+		return err
+	}
+	survey, err := s.surveyRepo.FindByID(ctx, export.SurveyID)
+	if err != nil {
+		return err
+	}
+	questions, err := s.questionRepo.FindBySurveyID(ctx, export.SurveyID)
+	if err != nil {
+		return err
+	}
+	versions, err := s.versionRepo.FindBySurveyID(ctx, export.SurveyID)
+	if err != nil {
+		return err
+	}
+	mapping, err := entities.NewSurveyAnswerMapping(questions, versions)
+	if err != nil {
+		return err
+	}
+	columns := entities.BuildSurveyExportColumns(mapping.Questions)
+
+	file, err := os.CreateTemp("", "survey-export-*."+export.FileExtension())
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	writer, err := newSurveyExportWriter(export.ExportType, file, survey, columns)
+	if err != nil {
+		return err
+	}
+	count := 0
+	err = s.responseRepo.FindInBatches(ctx, responseFilterForExport(export.SurveyID, filters), surveyExportBatchSize,
+		func(responses []entities.SurveyResponse) error {
+			for i := range responses {
+				mapping.MapResponse(&responses[i])
+				if err := writer.WriteResponse(&responses[i]); err != nil {
+					return err
+				}
+			}
+			count += len(responses)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read export file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read export file: %w", err)
+	}
+	key := surveyExportKey(export)
+	if _, err := s.storage.Upload(ctx, key, file, info.Size()); err != nil {
+		return err
+	}
+
+	export.FileKey = key
+	export.FileSize = info.Size()
+	export.RecordCount = count
+	return nil
+}
+
+// responseFilterForExport converts export filters to a response filter. Only finished responses are
+// exported unless a status is given or incomplete responses are asked for.
+func responseFilterForExport(surveyID uuid.UUID, filters *entities.SurveyExportFilters) repositories.SurveyResponseFilter {
+	filter := repositories.SurveyResponseFilter{
+		SurveyID:  surveyID,
+		StartDate: filters.StartDate,
+		EndDate:   filters.EndDate,
+		Status:    filters.Status,
+	}
+	if filters.Status == "" && !filters.IncludeIncomplete {
+		completed := true
+		filter.IsCompleted = &completed
+	}
+	return filter
+}
+
+// surveyExportKey is the storage key of an export file
+func surveyExportKey(export *entities.SurveyExport) string {
+	return fmt.Sprintf("survey-exports/%s/%s.%s", export.SurveyID, export.ID, export.FileExtension())
+}
+
+// surveyExportWriter writes responses in one export format
+type surveyExportWriter interface {
+	WriteResponse(response *entities.SurveyResponse) error
+	Close() error
+}
+
+// newSurveyExportWriter creates the writer of an export type and writes its header
+func newSurveyExportWriter(exportType string, w io.Writer, survey *entities.Survey, columns []entities.SurveyExportColumn) (surveyExportWriter, error) {
+	switch exportType {
+	case entities.SurveyExportTypeCSV:
+		return newCSVSurveyExportWriter(w, columns)
+	case entities.SurveyExportTypeXLSX:
+		return newXLSXSurveyExportWriter(w, columns)
+	case entities.SurveyExportTypeJSON:
+		return newJSONSurveyExportWriter(w, survey, columns)
+	case entities.SurveyExportTypeSPSS:
+		return newSPSSSurveyExportWriter(w, survey, columns)
+	}
+	return nil, fmt.Errorf("%w: unsupported export type %q", entities.ErrInvalidExport, exportType)
+}
+
+// surveyExportRow returns the fixed columns and one value per question column of a response.
+// The coded flag returns SPSS codes instead of answer values.
+func surveyExportRow(response *entities.SurveyResponse, columns []entities.SurveyExportColumn, coded bool) []string {
+	answers := make(map[uuid.UUID]*entities.SurveyAnswer, len(response.Answers))
+	for i := range response.Answers {
+		answers[response.Answers[i].QuestionID] = &response.Answers[i]
+	}
+
+	row := make([]string, 0, len(surveyExportFixedColumns)+len(columns))
+	row = append(row, response.ID.String(), string(response.Status), "", response.StartedAt.Format(time.RFC3339), "", "")
+	if response.RespondentID != nil {
+		row[2] = response.RespondentID.String()
+	}
+	if response.SubmittedAt != nil {
+		row[4] = response.SubmittedAt.Format(time.RFC3339)
+	}
+	if response.TimeSpent != nil {
+		row[5] = strconv.Itoa(*response.TimeSpent)
+	}
+	for i := range columns {
+		if coded {
+			row = append(row, columns[i].Code(answers[columns[i].QuestionID]))
+		} else {
+			row = append(row, columns[i].Value(answers[columns[i].QuestionID]))
+		}
+	}
+	return row
+}
+
+// csvSurveyExportWriter writes UTF-8 CSV with the question text as header
+type csvSurveyExportWriter struct {
+	writer  *csv.Writer
+	columns []entities.SurveyExportColumn
+}
+
+func newCSVSurveyExportWriter(w io.Writer, columns []entities.SurveyExportColumn) (*csvSurveyExportWriter, error) {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	writer := csv.NewWriter(w)
+	header := append([]string{}, surveyExportFixedColumns...)
+	for _, column := range columns {
+		header = append(header, column.Label)
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return &csvSurveyExportWriter{writer: writer, columns: columns}, nil
+}
+
+func (w *csvSurveyExportWriter) WriteResponse(response *entities.SurveyResponse) error {
+	if err := w.writer.Write(surveyExportRow(response, w.columns, false)); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func (w *csvSurveyExportWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// xlsxSurveyExportWriter streams rows to a Responses sheet
+type xlsxSurveyExportWriter struct {
+	out      io.Writer
+	workbook *excelize.File
+	stream   *excelize.StreamWriter
+	columns  []entities.SurveyExportColumn
+	row      int
+}
+
+func newXLSXSurveyExportWriter(w io.Writer, columns []entities.SurveyExportColumn) (*xlsxSurveyExportWriter, error) {
+	const responsesSheet = "Responses"
+	workbook := excelize.NewFile()
+	if err := workbook.SetSheetName(workbook.GetSheetName(0), responsesSheet); err != nil {
+		workbook.Close()
+		return nil, fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	stream, err := workbook.NewStreamWriter(responsesSheet)
+	if err != nil {
+		workbook.Close()
+		return nil, fmt.Errorf("failed to write XLSX: %w", err)
+	}
+
+	writer := &xlsxSurveyExportWriter{out: w, workbook: workbook, stream: stream, columns: columns}
+	header := append([]string{}, surveyExportFixedColumns...)
+	for _, column := range columns {
+		header = append(header, column.Label)
+	}
+	if err := writer.writeRow(header); err != nil {
+		workbook.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *xlsxSurveyExportWriter) writeRow(values []string) error {
+	w.row++
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	if err := w.stream.SetRow(cell, cells); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
+
+func (w *xlsxSurveyExportWriter) WriteResponse(response *entities.SurveyResponse) error {
+	return w.writeRow(surveyExportRow(response, w.columns, false))
+}
+
+func (w *xlsxSurveyExportWriter) Close() error {
+	defer w.workbook.Close()
+	if err := w.stream.Flush(); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	if _, err := w.workbook.WriteTo(w.out); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
+
+// jsonSurveyExportWriter writes {"survey":...,"columns":[...],"responses":[...]}, one response at a time
+type jsonSurveyExportWriter struct {
+	out     io.Writer
+	columns []entities.SurveyExportColumn
+	count   int
+}
+
+// jsonSurveyExportResponse is one response of a JSON export
+type jsonSurveyExportResponse struct {
+	ResponseID   string            `json:"responseId"`
+	Status       string            `json:"status"`
+	RespondentID string            `json:"respondentId,omitempty"`
+	StartedAt    string            `json:"startedAt"`
+	SubmittedAt  string            `json:"submittedAt,omitempty"`
+	TimeSpent    string            `json:"timeSpent,omitempty"`
+	Answers      map[string]string `json:"answers"` // Answer by column name
+}
+
+func newJSONSurveyExportWriter(w io.Writer, survey *entities.Survey, columns []entities.SurveyExportColumn) (*jsonSurveyExportWriter, error) {
+	type jsonColumn struct {
+		Name       string    `json:"name"`
+		Label      string    `json:"label"`
+		QuestionID uuid.UUID `json:"questionId"`
+	}
+	header := struct {
+		ID    uuid.UUID `json:"id"`
+		Title string    `json:"title"`
+	}{survey.ID, survey.Title}
+	jsonColumns := make([]jsonColumn, len(columns))
+	for i, column := range columns {
+		jsonColumns[i] = jsonColumn{Name: column.Name, Label: column.Label, QuestionID: column.QuestionID}
+	}
+
+	surveyJSON, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write JSON: %w", err)
+	}
+	columnsJSON, err := json.Marshal(jsonColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write JSON: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, `{"survey":%s,"columns":%s,"responses":[`, surveyJSON, columnsJSON); err != nil {
+		return nil, fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return &jsonSurveyExportWriter{out: w, columns: columns}, nil
+}
+
+func (w *jsonSurveyExportWriter) WriteResponse(response *entities.SurveyResponse) error {
+	row := surveyExportRow(response, w.columns, false)
+	record := jsonSurveyExportResponse{
+		ResponseID:   row[0],
+		Status:       row[1],
+		RespondentID: row[2],
+		StartedAt:    row[3],
+		SubmittedAt:  row[4],
+		TimeSpent:    row[5],
+		Answers:      make(map[string]string, len(w.columns)),
+	}
+	for i, column := range w.columns {
+		if value := row[len(surveyExportFixedColumns)+i]; value != "" {
+			record.Answers[column.Name] = value
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	if w.count > 0 {
+		data = append([]byte{','}, data...)
+	}
+	w.count++
+	if _, err := w.out.Write(data); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+func (w *jsonSurveyExportWriter) Close() error {
+	if _, err := io.WriteString(w.out, "]}"); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// spssSurveyExportWriter writes a zip of coded CSV data and an SPSS syntax file that reads it and
+// applies the variable and value labels
+type spssSurveyExportWriter struct {
+	archive *zip.Writer
+	data    *csv.Writer
+	survey  *entities.Survey
+	columns []entities.SurveyExportColumn
+}
+
+func newSPSSSurveyExportWriter(w io.Writer, survey *entities.Survey, columns []entities.SurveyExportColumn) (*spssSurveyExportWriter, error) {
+	archive := zip.NewWriter(w)
+	dataFile, err := archive.Create("data.csv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	data := csv.NewWriter(dataFile)
+	header := append([]string{}, spssFixedVariables...)
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+	if err := data.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	return &spssSurveyExportWriter{archive: archive, data: data, survey: survey, columns: columns}, nil
+}
+
+func (w *spssSurveyExportWriter) WriteResponse(response *entities.SurveyResponse) error {
+	if err := w.data.Write(surveyExportRow(response, w.columns, true)); err != nil {
+		return fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	return nil
+}
+
+func (w *spssSurveyExportWriter) Close() error {
+	w.data.Flush()
+	if err := w.data.Error(); err != nil {
+		return fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	syntaxFile, err := w.archive.Create("survey.sps")
+	if err != nil {
+		return fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	if _, err := io.WriteString(syntaxFile, spssSyntax(w.survey, w.columns)); err != nil {
+		return fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	if err := w.archive.Close(); err != nil {
+		return fmt.Errorf("failed to write SPSS export: %w", err)
+	}
+	return nil
+}
+
+// spssSyntax builds the syntax that loads data.csv with variable and value labels
+func spssSyntax(survey *entities.Survey, columns []entities.SurveyExportColumn) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "* Encoding: UTF-8.\n* %s.\n\n", spssComment(survey.Title))
+	b.WriteString("GET DATA /TYPE=TXT /FILE='data.csv' /ENCODING='UTF8' /DELIMITERS=\",\" /QUALIFIER='\"'\n")
+	b.WriteString("  /ARRANGEMENT=DELIMITED /FIRSTCASE=2 /VARIABLES=\n")
+	b.WriteString("  response_id A36 status A20 respondent_id A36 started_at A25 submitted_at A25 time_spent F8.0\n")
+	for _, column := range columns {
+		format := "A1000"
+		if column.IsNumeric() {
+			format = "F8.2"
+		}
+		fmt.Fprintf(&b, "  %s %s\n", column.Name, format)
+	}
+	b.WriteString(".\n\nVARIABLE LABELS\n")
+	for i, name := range spssFixedVariables {
+		fmt.Fprintf(&b, "  %s %s\n", name, spssString(surveyExportFixedColumns[i]))
+	}
+	for _, column := range columns {
+		fmt.Fprintf(&b, "  %s %s\n", column.Name, spssString(column.Label))
+	}
+	b.WriteString(".\n")
+
+	var valueLabels []string
+	for _, column := range columns {
+		labels := column.ValueLabels()
+		if len(labels) == 0 {
+			continue
+		}
+		entry := column.Name
+		for _, label := range labels {
+			entry += fmt.Sprintf(" %s %s", label[0], spssString(label[1]))
+		}
+		valueLabels = append(valueLabels, entry)
+	}
+	if len(valueLabels) > 0 {
+		fmt.Fprintf(&b, "\nVALUE LABELS\n  %s\n.\n", strings.Join(valueLabels, "\n  /"))
+	}
+	b.WriteString("\nEXECUTE.\n")
+	return b.String()
+}
+
+// spssString quotes a label for SPSS syntax
+func spssString(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// spssComment keeps a comment on one line without ending it early
+func spssComment(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "\n", " "), ".", "")
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// memorySurveyExportRepository keeps exports in memory
+type memorySurveyExportRepository struct {
+	exports map[uuid.UUID]entities.SurveyExport
+}
+
+func (r *memorySurveyExportRepository) Create(ctx context.Context, export *entities.SurveyExport) error {
+	r.exports[export.ID] = *export
+	return nil
+}
+
+func (r *memorySurveyExportRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyExport, error) {
+	export, ok := r.exports[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &export, nil
+}
+
+func (r *memorySurveyExportRepository) Update(ctx context.Context, export *entities.SurveyExport) error {
+	r.exports[export.ID] = *export
+	return nil
+}
+
+func (r *memorySurveyExportRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID, limit int) ([]entities.SurveyExport, error) {
+	var exports []entities.SurveyExport
+	for _, export := range r.exports {
+		if export.SurveyID == surveyID {
+			exports = append(exports, export)
+		}
+	}
+	return exports, nil
+}
+
+func (r *memorySurveyExportRepository) FindExpiredWithFiles(ctx context.Context, before time.Time, limit int) ([]entities.SurveyExport, error) {
+	var exports []entities.SurveyExport
+	for _, export := range r.exports {
+		if export.IsCompleted() && export.ExpiresAt != nil && export.ExpiresAt.Before(before) && export.FileKey != "" {
+			exports = append(exports, export)
+		}
+	}
+	return exports, nil
+}
+
+// exportQueue records the queued exports
+type exportQueue struct {
+	queued []uuid.UUID
+}
+
+func (q *exportQueue) EnqueueSurveyExport(ctx context.Context, exportID uuid.UUID) error {
+	q.queued = append(q.queued, exportID)
+	return nil
+}
+
+type SurveyExportServiceTestSuite struct {
+	suite.Suite
+	dir     string
+	queue   *exportQueue
+	exports *memorySurveyExportRepository
+	service services.SurveyExportService
+	survey  *entities.Survey
+	ctx     context.Context
+}
+
+func (suite *SurveyExportServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.dir = suite.T().TempDir()
+	suite.survey = &entities.Survey{
+		ID:    uuid.New(),
+		Title: "Event feedback",
+		Questions: []entities.SurveyQuestion{
+			{ID: uuid.New(), Order: 1, QuestionType: entities.QuestionTypeRadio, QuestionText: "Role", Options: []string{"Speaker", "Attendee"}},
+			{ID: uuid.New(), Order: 2, QuestionType: entities.QuestionTypeCheckbox, QuestionText: "Topics", Options: []string{"AI", "Cloud"}},
+		},
+	}
+
+	surveys := &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	answers := &memorySurveyAnswerRepository{}
+	responses := &memorySurveyResponseRepository{answers: answers}
+	for i, role := range []string{"Speaker", "Attendee", "Attendee"} {
+		response := &entities.SurveyResponse{
+			ID:        uuid.New(),
+			SurveyID:  suite.survey.ID,
+			Status:    entities.ResponseStatusSubmitted,
+			StartedAt: time.Now().Add(time.Duration(i) * time.Minute),
+		}
+		if i == 2 {
+			response.Status = entities.ResponseStatusInProgress
+		}
+		responses.responses = append(responses.responses, response)
+		answers.answers = append(answers.answers,
+			entities.SurveyAnswer{ResponseID: response.ID, QuestionID: suite.survey.Questions[0].ID, AnswerText: role},
+			entities.SurveyAnswer{ResponseID: response.ID, QuestionID: suite.survey.Questions[1].ID, AnswerArray: pq.StringArray{"Cloud"}},
+		)
+	}
+
+	suite.queue = &exportQueue{}
+	suite.exports = &memorySurveyExportRepository{exports: make(map[uuid.UUID]entities.SurveyExport)}
+	suite.service = NewSurveyExportService(
+		surveys,
+		&memorySurveyQuestionRepository{surveys: surveys},
+		&memorySurveyVersionRepository{},
+		responses,
+		suite.exports,
+		storage.NewLocalStorage(suite.dir, ""),
+		suite.queue,
+		zap.NewNop(),
+	)
+}
+
+// export requests and processes an export, returning it with the path of its file
+func (suite *SurveyExportServiceTestSuite) export(exportType string) (*entities.SurveyExport, string) {
+	export := &entities.SurveyExport{SurveyID: suite.survey.ID, ExportType: exportType}
+	suite.Require().NoError(suite.service.RequestExport(suite.ctx, export))
+	suite.Require().Contains(suite.queue.queued, export.ID)
+
+	_, _, err := suite.service.OpenExportFile(suite.ctx, export.ID)
+	suite.ErrorIs(err, services.ErrSurveyExportNotReady)
+
+	suite.Require().NoError(suite.service.ProcessExport(suite.ctx, export.ID))
+	processed, file, err := suite.service.OpenExportFile(suite.ctx, export.ID)
+	suite.Require().NoError(err)
+	file.Close()
+	suite.Equal(entities.SurveyExportStatusCompleted, processed.Status)
+	suite.Require().NotNil(processed.ExpiresAt)
+	return processed, filepath.Join(suite.dir, processed.FileKey)
+}
+
+func (suite *SurveyExportServiceTestSuite) TestCSVExportFlattensCompletedResponses() {
+	export, path := suite.export(entities.SurveyExportTypeCSV)
+	suite.Equal(2, export.RecordCount)
+
+	file, err := os.Open(path)
+	suite.Require().NoError(err)
+	defer file.Close()
+	suite.Equal(export.FileSize, fileSize(suite.T(), file))
+
+	records, err := csv.NewReader(file).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 3)
+	suite.Equal([]string{"Role", "Topics - AI", "Topics - Cloud", "Topics - Other"}, records[0][6:])
+	suite.Equal([]string{"Speaker", "0", "1", ""}, records[1][6:])
+}
+
+func (suite *SurveyExportServiceTestSuite) TestSPSSExportCodesChoices() {
+	_, path := suite.export(entities.SurveyExportTypeSPSS)
+
+	archive, err := zip.OpenReader(path)
+	suite.Require().NoError(err)
+	defer archive.Close()
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		suite.Require().NoError(err)
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		suite.Require().NoError(err)
+		files[file.Name] = string(content)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(files["data.csv"])).ReadAll()
+	suite.Require().NoError(err)
+	suite.Equal([]string{"Q1", "Q2_1", "Q2_2", "Q2_other"}, records[0][6:])
+	suite.Equal("1", records[1][6])
+	suite.Equal("2", records[2][6])
+	suite.Contains(files["survey.sps"], "Q1 1 'Speaker' 2 'Attendee'")
+}
+
+func (suite *SurveyExportServiceTestSuite) TestExpiredExportFilesAreDeleted() {
+	export, path := suite.export(entities.SurveyExportTypeCSV)
+	expired := time.Now().Add(-time.Minute)
+	export.ExpiresAt = &expired
+	suite.Require().NoError(suite.exports.Update(suite.ctx, export))
+
+	_, _, err := suite.service.OpenExportFile(suite.ctx, export.ID)
+	suite.ErrorIs(err, services.ErrSurveyExportExpired)
+
+	deleted, err := suite.service.DeleteExpiredExportFiles(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, deleted)
+	suite.NoFileExists(path)
+
+	stored, err := suite.service.GetExport(suite.ctx, export.ID)
+	suite.Require().NoError(err)
+	suite.Empty(stored.FileKey)
+}
+
+func fileSize(t *testing.T, file *os.File) int64 {
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestSurveyExportServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyExportServiceTestSuite))
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// surveyFileDownloadPrefix maps a file key onto its authorized download route,
+	// GET /api/v1/surveys/:surveyId/files/:questionId/:responseId/:fileName
+	surveyFileDownloadPrefix = "/api/v1/"
+	// surveyFileRoot is the storage prefix of every uploaded survey file
+	surveyFileRoot = "surveys/"
+	// surveyAbandonedUploadAge is how long a session stays inactive before its uploads are deleted
+	surveyAbandonedUploadAge = 7 * 24 * time.Hour
+)
+
+// SurveyFileServiceImpl implements the SurveyFileService interface
+type SurveyFileServiceImpl struct {
+	responseRepo repositories.SurveyResponseRepository
+	sessionRepo  repositories.SurveySessionRepository
+	files        storage.StorageProvider
+	logger       *zap.Logger
+}
+
+// NewSurveyFileService creates a new survey file service implementation
+func NewSurveyFileService(
+	responseRepo repositories.SurveyResponseRepository,
+	sessionRepo repositories.SurveySessionRepository,
+	files storage.StorageProvider,
+	logger *zap.Logger,
+) services.SurveyFileService {
+	return &SurveyFileServiceImpl{
+		responseRepo: responseRepo,
+		sessionRepo:  sessionRepo,
+		files:        files,
+		logger:       logger,
+	}
+}
+
+// OpenFile opens an uploaded file by its storage key
+func (s *SurveyFileServiceImpl) OpenFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(key, surveyFileRoot) || strings.Contains(key, "..") {
+		return nil, repositories.ErrNotFound
+	}
+	exists, err := s.files.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repositories.ErrNotFound
+	}
+	return s.files.Download(ctx, key)
+}
+
+// DeleteAbandonedFiles walks the uploaded files and deletes those of abandoned responses, of
+// responses whose session has been inactive for surveyAbandonedUploadAge, and of responses that no
+// longer exist. Files uploaded less than surveyAbandonedUploadAge ago are always kept.
+func (s *SurveyFileServiceImpl) DeleteAbandonedFiles(ctx context.Context) (int, error) {
+	files, err := s.files.ListFiles(ctx, surveyFileRoot, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-surveyAbandonedUploadAge)
+	abandoned := make(map[uuid.UUID]bool)
+	deleted := 0
+	for _, file := range files {
+		modified, err := time.Parse(time.RFC3339, file.LastModified)
+		if err != nil || modified.After(cutoff) {
+			continue
+		}
+		responseID, ok := surveyFileResponseID(file.Key)
+		if !ok {
+			continue
+		}
+		isAbandoned, checked := abandoned[responseID]
+		if !checked {
+			isAbandoned, err = s.isAbandoned(ctx, responseID, cutoff)
+			if err != nil {
+				return deleted, err
+			}
+			abandoned[responseID] = isAbandoned
+		}
+		if !isAbandoned {
+			continue
+		}
+		if err := s.files.Delete(ctx, file.Key); err != nil {
+			s.logger.Error("Failed to delete abandoned survey file", zap.String("key", file.Key), zap.Error(err))
+			continue
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		s.logger.Info("Abandoned survey files deleted", zap.Int("deleted", deleted))
+	}
+	return deleted, nil
+}
+
+// isAbandoned tells whether the files of a response can be deleted
+func (s *SurveyFileServiceImpl) isAbandoned(ctx context.Context, responseID uuid.UUID, cutoff time.Time) (bool, error) {
+	response, err := s.responseRepo.FindByID(ctx, responseID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	switch response.Status {
+	case entities.ResponseStatusAbandoned:
+		return true, nil
+	case entities.ResponseStatusInProgress:
+	default:
+		return false, nil
+	}
+
+	session, err := s.sessionRepo.FindByResponseID(ctx, responseID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return response.StartedAt.Before(cutoff), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return session.LastActivity.Before(cutoff), nil
+}
+
+// surveyFileResponseID reads the response ID from a file key, which SurveyResponseServiceImpl.UploadFile
+// builds as surveys/<surveyId>/files/<questionId>/<responseId>/<fileName>
+func surveyFileResponseID(key string) (uuid.UUID, bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 6 || parts[2] != "files" {
+		return uuid.Nil, false
+	}
+	responseID, err := uuid.Parse(parts[4])
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return responseID, true
+}
+
+// surveyFileURL returns the authorized download path of an uploaded file
+func surveyFileURL(key string) string {
+	return surveyFileDownloadPrefix + key
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+type SurveyFileServiceTestSuite struct {
+	suite.Suite
+	dir       string
+	responses *memorySurveyResponseRepository
+	sessions  *memorySurveySessionRepository
+	service   services.SurveyFileService
+	surveyID  uuid.UUID
+	ctx       context.Context
+}
+
+func (suite *SurveyFileServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.dir = suite.T().TempDir()
+	suite.surveyID = uuid.New()
+	suite.responses = &memorySurveyResponseRepository{answers: &memorySurveyAnswerRepository{}}
+	suite.sessions = &memorySurveySessionRepository{}
+	suite.service = NewSurveyFileService(suite.responses, suite.sessions, storage.NewLocalStorage(suite.dir, ""), zap.NewNop())
+}
+
+// upload stores a file for a response, last modified the given time ago, and returns its key
+func (suite *SurveyFileServiceTestSuite) upload(responseID uuid.UUID, age time.Duration) string {
+	key := entities.SurveyFileKeyPrefix(suite.surveyID, uuid.New()) + responseID.String() + "/" + uuid.New().String() + ".pdf"
+	path := filepath.Join(suite.dir, key)
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(path), 0755))
+	suite.Require().NoError(os.WriteFile(path, []byte("%PDF"), 0644))
+	modified := time.Now().Add(-age)
+	suite.Require().NoError(os.Chtimes(path, modified, modified))
+	return key
+}
+
+// respond records a response with a session last active the given time ago
+func (suite *SurveyFileServiceTestSuite) respond(status entities.ResponseStatus, inactive time.Duration) uuid.UUID {
+	response := &entities.SurveyResponse{ID: uuid.New(), SurveyID: suite.surveyID, Status: status, StartedAt: time.Now().Add(-inactive)}
+	suite.Require().NoError(suite.responses.Create(suite.ctx, response))
+	suite.Require().NoError(suite.sessions.Create(suite.ctx, &entities.SurveySession{
+		ID:           uuid.New(),
+		SurveyID:     suite.surveyID,
+		SessionID:    uuid.New().String(),
+		ResponseID:   &response.ID,
+		LastActivity: time.Now().Add(-inactive),
+	}))
+	return response.ID
+}
+
+func (suite *SurveyFileServiceTestSuite) TestDeleteAbandonedFiles() {
+	old := 8 * 24 * time.Hour
+	submitted := suite.upload(suite.respond(entities.ResponseStatusSubmitted, old), old)
+	resumable := suite.upload(suite.respond(entities.ResponseStatusInProgress, time.Hour), old)
+	inactive := suite.upload(suite.respond(entities.ResponseStatusInProgress, old), old)
+	abandoned := suite.upload(suite.respond(entities.ResponseStatusAbandoned, old), old)
+	orphaned := suite.upload(uuid.New(), old)
+	recent := suite.upload(uuid.New(), time.Hour)
+
+	deleted, err := suite.service.DeleteAbandonedFiles(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(3, deleted)
+
+	for _, key := range []string{submitted, resumable, recent} {
+		suite.FileExists(filepath.Join(suite.dir, key))
+	}
+	for _, key := range []string{inactive, abandoned, orphaned} {
+		suite.NoFileExists(filepath.Join(suite.dir, key))
+	}
+}
+
+func (suite *SurveyFileServiceTestSuite) TestOpenFileStaysInsideSurveyFiles() {
+	key := suite.upload(uuid.New(), time.Hour)
+	file, err := suite.service.OpenFile(suite.ctx, key)
+	suite.Require().NoError(err)
+	file.Close()
+
+	_, err = suite.service.OpenFile(suite.ctx, "survey-exports/"+strings.TrimPrefix(key, surveyFileRoot))
+	suite.ErrorIs(err, repositories.ErrNotFound)
+	_, err = suite.service.OpenFile(suite.ctx, surveyFileRoot+"../config.yaml")
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func TestSurveyFileServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyFileServiceTestSuite))
+}
@@ -0,0 +1,451 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxSurveyInvitees bounds the invitees of one batch
+	maxSurveyInvitees = 5000
+	// inviteeSegmentPageSize is the page size used to read a WeChat follower segment
+	inviteeSegmentPageSize = 500
+	// invitationReminderBatchSize bounds the reminders sent by one run
+	invitationReminderBatchSize = 200
+)
+
+// SurveyInvitationConfig holds configuration for survey invitations
+type SurveyInvitationConfig struct {
+	BaseURL          string        // Base URL of the survey pages the invitation links open
+	WeChatTemplateID string        // Template message of WeChat invitations and reminders
+	DefaultExpiry    time.Duration // Lifetime of invitations created without an expiry; 0 for none
+	ReminderInterval time.Duration // Wait after the invitation or the last reminder before reminding again
+	MaxReminders     int           // Reminders sent to a non-responder at most
+}
+
+// DefaultSurveyInvitationConfig returns the invitation configuration used when none is given
+func DefaultSurveyInvitationConfig(baseURL, wechatTemplateID string) *SurveyInvitationConfig {
+	return &SurveyInvitationConfig{
+		BaseURL:          strings.TrimRight(baseURL, "/"),
+		WeChatTemplateID: wechatTemplateID,
+		DefaultExpiry:    30 * 24 * time.Hour,
+		ReminderInterval: 72 * time.Hour,
+		MaxReminders:     2,
+	}
+}
+
+// SurveyInvitationServiceImpl implements the SurveyInvitationService interface
+type SurveyInvitationServiceImpl struct {
+	invitationRepo repositories.SurveyInvitationRepository
+	surveyRepo     repositories.SurveyRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	wechat         services.TemplateMessageSender // nil when WeChat is not configured
+	email          services.EmailSender
+	config         *SurveyInvitationConfig
+	logger         *zap.Logger
+}
+
+// NewSurveyInvitationService creates a new survey invitation service implementation
+func NewSurveyInvitationService(
+	invitationRepo repositories.SurveyInvitationRepository,
+	surveyRepo repositories.SurveyRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	wechat services.TemplateMessageSender,
+	email services.EmailSender,
+	config *SurveyInvitationConfig,
+	logger *zap.Logger,
+) services.SurveyInvitationService {
+	return &SurveyInvitationServiceImpl{
+		invitationRepo: invitationRepo,
+		surveyRepo:     surveyRepo,
+		wechatUserRepo: wechatUserRepo,
+		wechat:         wechat,
+		email:          email,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// surveyInvitationMetadata is the JSON stored in SurveyInvitation.Metadata
+type surveyInvitationMetadata struct {
+	Message string `json:"message,omitempty"`
+}
+
+// CreateInvitations invites the listed and segment invitees who were not invited to the survey yet
+func (s *SurveyInvitationServiceImpl) CreateInvitations(ctx context.Context, req *services.SurveyInvitationRequest) (*services.SurveyInvitationResult, error) {
+	if err := s.checkChannel(req.Channel); err != nil {
+		return nil, err
+	}
+	survey, err := s.surveyRepo.FindByID(ctx, req.SurveyID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := checkSurveyOpen(survey, now); err != nil {
+		return nil, err
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt == nil && s.config.DefaultExpiry > 0 {
+		defaultExpiry := now.Add(s.config.DefaultExpiry)
+		expiresAt = &defaultExpiry
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", entities.ErrInvalidInvitation)
+	}
+
+	invitees := req.Invitees
+	if req.Segment != nil {
+		followers, err := s.segmentInvitees(ctx, req.Segment)
+		if err != nil {
+			return nil, err
+		}
+		invitees = append(invitees, followers...)
+	}
+	if len(invitees) > maxSurveyInvitees {
+		return nil, fmt.Errorf("%w: at most %d invitees per batch", entities.ErrInvalidInvitation, maxSurveyInvitees)
+	}
+
+	metadata, err := json.Marshal(surveyInvitationMetadata{Message: req.Message})
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.invitationRepo.FindBySurveyID(ctx, survey.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	invited := make(map[string]bool, len(existing)+len(invitees))
+	for i := range existing {
+		invited[existing[i].Recipient()] = true
+	}
+
+	result := &services.SurveyInvitationResult{}
+	for _, invitee := range invitees {
+		token, err := entities.NewSurveyInvitationToken()
+		if err != nil {
+			return nil, err
+		}
+		invitation := entities.SurveyInvitation{
+			ID:        uuid.New(),
+			SurveyID:  survey.ID,
+			Channel:   req.Channel,
+			Email:     strings.TrimSpace(invitee.Email),
+			OpenID:    strings.TrimSpace(invitee.OpenID),
+			Name:      strings.TrimSpace(invitee.Name),
+			Token:     token,
+			Status:    entities.SurveyInvitationStatusSent,
+			SentAt:    now,
+			ExpiresAt: expiresAt,
+			Metadata:  string(metadata),
+		}
+		if invitation.Validate() != nil || invited[invitation.Recipient()] {
+			result.Skipped++
+			continue
+		}
+		invited[invitation.Recipient()] = true
+		result.Invitations = append(result.Invitations, invitation)
+	}
+	if len(result.Invitations) == 0 {
+		return nil, fmt.Errorf("%w: no new invitees with an address for the %s channel", entities.ErrInvalidInvitation, req.Channel)
+	}
+	if err := s.invitationRepo.CreateBatch(ctx, result.Invitations); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Invitations {
+		invitation := &result.Invitations[i]
+		if err := s.deliver(ctx, survey, invitation, false); err != nil {
+			s.logger.Warn("Failed to deliver survey invitation",
+				zap.String("invitationId", invitation.ID.String()),
+				zap.String("channel", invitation.Channel),
+				zap.Error(err))
+			invitation.Status = entities.SurveyInvitationStatusFailed
+			invitation.LastError = err.Error()
+			if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+				return nil, err
+			}
+			result.Failed++
+			continue
+		}
+		result.Sent++
+	}
+
+	s.logger.Info("Survey invitations created",
+		zap.String("surveyId", survey.ID.String()),
+		zap.String("channel", req.Channel),
+		zap.Int("sent", result.Sent),
+		zap.Int("failed", result.Failed),
+		zap.Int("skipped", result.Skipped))
+	return result, nil
+}
+
+// GetInvitations lists a survey's invitations, optionally with one status
+func (s *SurveyInvitationServiceImpl) GetInvitations(ctx context.Context, surveyID uuid.UUID, status string) ([]entities.SurveyInvitation, error) {
+	return s.invitationRepo.FindBySurveyID(ctx, surveyID, status)
+}
+
+// OpenInvitation records the visit of an invitation link
+func (s *SurveyInvitationServiceImpl) OpenInvitation(ctx context.Context, token string) (*entities.SurveyInvitation, error) {
+	invitation, err := s.invitationRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case invitation.Status == entities.SurveyInvitationStatusResponded:
+		return nil, services.ErrSurveyInvitationUsed
+	case invitation.Status == entities.SurveyInvitationStatusExpired:
+		return nil, services.ErrSurveyInvitationExpired
+	case invitation.IsExpired():
+		invitation.Status = entities.SurveyInvitationStatusExpired
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			return nil, err
+		}
+		return nil, services.ErrSurveyInvitationExpired
+	}
+
+	if invitation.Status != entities.SurveyInvitationStatusOpened {
+		invitation.MarkOpened(time.Now())
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			return nil, err
+		}
+	}
+	return invitation, nil
+}
+
+// MarkResponded records the response submitted through an invitation
+func (s *SurveyInvitationServiceImpl) MarkResponded(ctx context.Context, invitationID, responseID uuid.UUID) error {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.Status == entities.SurveyInvitationStatusResponded {
+		if invitation.ResponseID != nil && *invitation.ResponseID == responseID {
+			return nil
+		}
+		return services.ErrSurveyInvitationUsed
+	}
+	invitation.MarkResponded(responseID, time.Now())
+	return s.invitationRepo.Update(ctx, invitation)
+}
+
+// RemindSurvey reminds the non-responders of one survey whose reminder is due
+func (s *SurveyInvitationServiceImpl) RemindSurvey(ctx context.Context, surveyID uuid.UUID) (int, error) {
+	return s.remind(ctx, &surveyID)
+}
+
+// SendReminders expires overdue invitations and reminds the non-responders of all surveys
+func (s *SurveyInvitationServiceImpl) SendReminders(ctx context.Context) (int, error) {
+	expired, err := s.invitationRepo.ExpireOverdue(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if expired > 0 {
+		s.logger.Info("Survey invitations expired", zap.Int64("count", expired))
+	}
+	return s.remind(ctx, nil)
+}
+
+// remind sends the due reminders, optionally of one survey. Failed deliveries are retried this way
+// too, and count towards the reminder limit so unreachable invitees are not retried forever.
+func (s *SurveyInvitationServiceImpl) remind(ctx context.Context, surveyID *uuid.UUID) (int, error) {
+	now := time.Now()
+	invitations, err := s.invitationRepo.FindAwaitingResponse(ctx, surveyID, now.Add(-s.config.ReminderInterval), s.config.MaxReminders, invitationReminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	surveys := make(map[uuid.UUID]*entities.Survey)
+	reminded := 0
+	for i := range invitations {
+		invitation := &invitations[i]
+		if !invitation.IsReminderDue(now, s.config.ReminderInterval, s.config.MaxReminders) {
+			continue
+		}
+		survey, ok := surveys[invitation.SurveyID]
+		if !ok {
+			survey, err = s.surveyRepo.FindByID(ctx, invitation.SurveyID)
+			if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return reminded, err
+			}
+			surveys[invitation.SurveyID] = survey
+		}
+		if survey == nil || checkSurveyOpen(survey, now) != nil {
+			continue
+		}
+
+		deliveryErr := s.deliver(ctx, survey, invitation, invitation.Status != entities.SurveyInvitationStatusFailed)
+		invitation.ReminderCount++
+		invitation.RemindedAt = &now
+		if deliveryErr != nil {
+			s.logger.Warn("Failed to deliver survey invitation reminder",
+				zap.String("invitationId", invitation.ID.String()),
+				zap.Error(deliveryErr))
+			if invitation.Status == entities.SurveyInvitationStatusSent {
+				invitation.Status = entities.SurveyInvitationStatusFailed
+			}
+			invitation.LastError = deliveryErr.Error()
+		} else {
+			if invitation.Status == entities.SurveyInvitationStatusFailed {
+				invitation.Status = entities.SurveyInvitationStatusSent
+			}
+			invitation.LastError = ""
+			reminded++
+		}
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			return reminded, err
+		}
+	}
+
+	if reminded > 0 {
+		s.logger.Info("Survey invitation reminders sent", zap.Int("count", reminded))
+	}
+	return reminded, nil
+}
+
+// checkChannel checks that invitations can be delivered through the channel
+func (s *SurveyInvitationServiceImpl) checkChannel(channel string) error {
+	switch channel {
+	case entities.SurveyInvitationChannelEmail:
+		if s.email == nil {
+			return fmt.Errorf("%w: email delivery is not configured", entities.ErrInvalidInvitation)
+		}
+	case entities.SurveyInvitationChannelWeChat:
+		if s.wechat == nil || s.config.WeChatTemplateID == "" {
+			return fmt.Errorf("%w: WeChat delivery is not configured", entities.ErrInvalidInvitation)
+		}
+	default:
+		return fmt.Errorf("%w: unknown channel %q", entities.ErrInvalidInvitation, channel)
+	}
+	return nil
+}
+
+// segmentInvitees reads the subscribed WeChat followers of a segment
+func (s *SurveyInvitationServiceImpl) segmentInvitees(ctx context.Context, segment *services.SurveyInviteeSegment) ([]entities.SurveyInvitee, error) {
+	subscribed := true
+	filter := repositories.WeChatUserFilter{
+		Limit:              inviteeSegmentPageSize,
+		Search:             segment.Search,
+		Subscribe:          &subscribed,
+		Sex:                segment.Sex,
+		City:               segment.City,
+		Province:           segment.Province,
+		Country:            segment.Country,
+		SubscribeTimeStart: segment.SubscribedAfter,
+		SubscribeTimeEnd:   segment.SubscribedBefore,
+	}
+
+	var invitees []entities.SurveyInvitee
+	for {
+		users, err := s.wechatUserRepo.GetAll(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			invitee := entities.SurveyInvitee{Name: user.NickName, OpenID: user.OpenID}
+			if user.RealName != nil && *user.RealName != "" {
+				invitee.Name = *user.RealName
+			}
+			if user.Email != nil {
+				invitee.Email = *user.Email
+			}
+			invitees = append(invitees, invitee)
+		}
+		if len(users) < filter.Limit || len(invitees) > maxSurveyInvitees {
+			return invitees, nil
+		}
+		filter.Offset += filter.Limit
+	}
+}
+
+// deliver sends an invitation or a reminder through the invitation's channel
+func (s *SurveyInvitationServiceImpl) deliver(ctx context.Context, survey *entities.Survey, invitation *entities.SurveyInvitation, reminder bool) error {
+	link := s.invitationLink(invitation)
+	var metadata surveyInvitationMetadata
+	if invitation.Metadata != "" {
+		_ = json.Unmarshal([]byte(invitation.Metadata), &metadata)
+	}
+	expiry := "No deadline"
+	if invitation.ExpiresAt != nil {
+		expiry = invitation.ExpiresAt.Format("2006-01-02 15:04")
+	}
+
+	switch invitation.Channel {
+	case entities.SurveyInvitationChannelWeChat:
+		if s.wechat == nil || s.config.WeChatTemplateID == "" {
+			return errors.New("WeChat delivery is not configured")
+		}
+		first := "You are invited to take part in a survey."
+		if reminder {
+			first = "Reminder: we are still waiting for your answers."
+		}
+		remark := metadata.Message
+		if remark == "" {
+			remark = "Tap to answer the survey."
+		}
+		return s.wechat.SendTemplateMessage(ctx, invitation.OpenID, &services.TemplateMessage{
+			TemplateID: s.config.WeChatTemplateID,
+			URL:        link,
+			Data: map[string]interface{}{
+				"first":    map[string]string{"value": first},
+				"keyword1": map[string]string{"value": survey.Title},
+				"keyword2": map[string]string{"value": expiry},
+				"remark":   map[string]string{"value": remark},
+			},
+		})
+
+	case entities.SurveyInvitationChannelEmail:
+		if s.email == nil {
+			return errors.New("email delivery is not configured")
+		}
+		subject := "You're invited: " + survey.Title
+		if reminder {
+			subject = "Reminder: " + survey.Title
+		}
+		return s.email.SendEmail(ctx, &services.EmailMessage{
+			To:      invitation.Email,
+			ToName:  invitation.Name,
+			Subject: subject,
+			Body:    invitationEmailBody(survey, invitation, metadata.Message, link, expiry, reminder),
+		})
+	}
+	return fmt.Errorf("unknown invitation channel %q", invitation.Channel)
+}
+
+// invitationLink returns the personal survey link of an invitation
+func (s *SurveyInvitationServiceImpl) invitationLink(invitation *entities.SurveyInvitation) string {
+	return fmt.Sprintf("%s/surveys/%s?invitation=%s", s.config.BaseURL, invitation.SurveyID, url.QueryEscape(invitation.Token))
+}
+
+// invitationEmailBody writes the plain text of an invitation or reminder email
+func invitationEmailBody(survey *entities.Survey, invitation *entities.SurveyInvitation, message, link, expiry string, reminder bool) string {
+	var body strings.Builder
+	if invitation.Name != "" {
+		fmt.Fprintf(&body, "Hello %s,\n\n", invitation.Name)
+	} else {
+		body.WriteString("Hello,\n\n")
+	}
+	if reminder {
+		fmt.Fprintf(&body, "A quick reminder that we would still like to hear from you in \"%s\".\n\n", survey.Title)
+	} else {
+		fmt.Fprintf(&body, "You are invited to take part in \"%s\".\n\n", survey.Title)
+	}
+	if message != "" {
+		body.WriteString(message + "\n\n")
+	} else if survey.Description != "" {
+		body.WriteString(survey.Description + "\n\n")
+	}
+	fmt.Fprintf(&body, "Answer the survey: %s\n\n", link)
+	fmt.Fprintf(&body, "This link is personal and can be used once. Deadline: %s.\n", expiry)
+	return body.String()
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// memorySurveyInvitationRepository keeps invitations in memory
+type memorySurveyInvitationRepository struct {
+	invitations []*entities.SurveyInvitation
+}
+
+func (r *memorySurveyInvitationRepository) CreateBatch(ctx context.Context, invitations []entities.SurveyInvitation) error {
+	for i := range invitations {
+		copied := invitations[i]
+		r.invitations = append(r.invitations, &copied)
+	}
+	return nil
+}
+
+func (r *memorySurveyInvitationRepository) find(match func(*entities.SurveyInvitation) bool) (*entities.SurveyInvitation, error) {
+	for _, invitation := range r.invitations {
+		if match(invitation) {
+			copied := *invitation
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyInvitation, error) {
+	return r.find(func(invitation *entities.SurveyInvitation) bool { return invitation.ID == id })
+}
+
+func (r *memorySurveyInvitationRepository) FindByToken(ctx context.Context, token string) (*entities.SurveyInvitation, error) {
+	return r.find(func(invitation *entities.SurveyInvitation) bool { return invitation.Token == token })
+}
+
+func (r *memorySurveyInvitationRepository) Update(ctx context.Context, invitation *entities.SurveyInvitation) error {
+	for i, existing := range r.invitations {
+		if existing.ID == invitation.ID {
+			copied := *invitation
+			r.invitations[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyInvitationRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID, status string) ([]entities.SurveyInvitation, error) {
+	var invitations []entities.SurveyInvitation
+	for _, invitation := range r.invitations {
+		if invitation.SurveyID == surveyID && (status == "" || invitation.Status == status) {
+			invitations = append(invitations, *invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (r *memorySurveyInvitationRepository) FindAwaitingResponse(ctx context.Context, surveyID *uuid.UUID, contactedBefore time.Time, maxReminders, limit int) ([]entities.SurveyInvitation, error) {
+	var invitations []entities.SurveyInvitation
+	for _, invitation := range r.invitations {
+		if surveyID != nil && invitation.SurveyID != *surveyID {
+			continue
+		}
+		if invitation.IsReminderDue(contactedBefore, 0, maxReminders) && len(invitations) < limit {
+			invitations = append(invitations, *invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (r *memorySurveyInvitationRepository) ExpireOverdue(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	for _, invitation := range r.invitations {
+		if invitation.Status != entities.SurveyInvitationStatusResponded && invitation.Status != entities.SurveyInvitationStatusExpired &&
+			invitation.ExpiresAt != nil && invitation.ExpiresAt.Before(now) {
+			invitation.Status = entities.SurveyInvitationStatusExpired
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// fakeEmailSender records sent emails and rejects the addresses in fail
+type fakeEmailSender struct {
+	sent []services.EmailMessage
+	fail map[string]bool
+}
+
+func (s *fakeEmailSender) SendEmail(ctx context.Context, message *services.EmailMessage) error {
+	if s.fail[message.To] {
+		return errors.New("mailbox unavailable")
+	}
+	s.sent = append(s.sent, *message)
+	return nil
+}
+
+// fakeTemplateMessageSender records WeChat template messages by OpenID
+type fakeTemplateMessageSender struct {
+	sent map[string]*services.TemplateMessage
+}
+
+func (s *fakeTemplateMessageSender) SendTemplateMessage(ctx context.Context, openID string, message *services.TemplateMessage) error {
+	s.sent[openID] = message
+	return nil
+}
+
+type SurveyInvitationServiceTestSuite struct {
+	suite.Suite
+	invitations *memorySurveyInvitationRepository
+	email       *fakeEmailSender
+	wechat      *fakeTemplateMessageSender
+	service     services.SurveyInvitationService
+	survey      *entities.Survey
+	ctx         context.Context
+}
+
+func (suite *SurveyInvitationServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.survey = &entities.Survey{ID: uuid.New(), Title: "Event feedback", Status: entities.SurveyStatusPublished}
+	surveys := &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	shanghai := "Shanghai"
+	followers := &memoryWeChatUserRepository{users: []*entities.WeChatUser{
+		{OpenID: "o-shanghai", NickName: "Ann", Subscribe: true, City: &shanghai},
+		{OpenID: "o-unsubscribed", NickName: "Bob", Subscribe: false, City: &shanghai},
+		{OpenID: "o-elsewhere", NickName: "Carol", Subscribe: true},
+	}}
+
+	suite.invitations = &memorySurveyInvitationRepository{}
+	suite.email = &fakeEmailSender{fail: map[string]bool{}}
+	suite.wechat = &fakeTemplateMessageSender{sent: map[string]*services.TemplateMessage{}}
+	config := DefaultSurveyInvitationConfig("https://events.example.com/", "survey-template")
+	suite.service = NewSurveyInvitationService(suite.invitations, surveys, followers, suite.wechat, suite.email, config, zap.NewNop())
+}
+
+func (suite *SurveyInvitationServiceTestSuite) TestCreateInvitationsSkipsDuplicates() {
+	result, err := suite.service.CreateInvitations(suite.ctx, &services.SurveyInvitationRequest{
+		SurveyID: suite.survey.ID,
+		Channel:  entities.SurveyInvitationChannelEmail,
+		Invitees: []entities.SurveyInvitee{
+			{Name: "Ann", Email: "ann@example.com"},
+			{Name: "Ann again", Email: "ANN@example.com"},
+			{Name: "No address"},
+			{Name: "Bob", Email: "bob@example.com"},
+		},
+		Message: "Thanks for coming!",
+	})
+	suite.Require().NoError(err)
+	suite.Equal(2, result.Sent)
+	suite.Equal(2, result.Skipped)
+	suite.Require().Len(suite.email.sent, 2)
+	suite.Contains(suite.email.sent[0].Body, "https://events.example.com/surveys/"+suite.survey.ID.String()+"?invitation="+result.Invitations[0].Token)
+	suite.Contains(suite.email.sent[0].Body, "Thanks for coming!")
+	suite.Require().NotNil(result.Invitations[0].ExpiresAt)
+
+	// Invitees are only invited once per survey
+	_, err = suite.service.CreateInvitations(suite.ctx, &services.SurveyInvitationRequest{
+		SurveyID: suite.survey.ID,
+		Channel:  entities.SurveyInvitationChannelEmail,
+		Invitees: []entities.SurveyInvitee{{Email: "bob@example.com"}},
+	})
+	suite.ErrorIs(err, entities.ErrInvalidInvitation)
+}
+
+func (suite *SurveyInvitationServiceTestSuite) TestCreateInvitationsFromSegment() {
+	result, err := suite.service.CreateInvitations(suite.ctx, &services.SurveyInvitationRequest{
+		SurveyID: suite.survey.ID,
+		Channel:  entities.SurveyInvitationChannelWeChat,
+		Segment:  &services.SurveyInviteeSegment{City: "Shanghai"},
+	})
+	suite.Require().NoError(err)
+	suite.Equal(1, result.Sent)
+	suite.Require().Contains(suite.wechat.sent, "o-shanghai")
+	message := suite.wechat.sent["o-shanghai"]
+	suite.Equal("survey-template", message.TemplateID)
+	suite.Contains(message.URL, result.Invitations[0].Token)
+}
+
+func (suite *SurveyInvitationServiceTestSuite) TestFailedDeliveryIsRetriedWithReminders() {
+	suite.email.fail["bob@example.com"] = true
+	result, err := suite.service.CreateInvitations(suite.ctx, &services.SurveyInvitationRequest{
+		SurveyID: suite.survey.ID,
+		Channel:  entities.SurveyInvitationChannelEmail,
+		Invitees: []entities.SurveyInvitee{{Email: "ann@example.com"}, {Email: "bob@example.com"}},
+	})
+	suite.Require().NoError(err)
+	suite.Equal(1, result.Sent)
+	suite.Equal(1, result.Failed)
+	failed, err := suite.service.GetInvitations(suite.ctx, suite.survey.ID, entities.SurveyInvitationStatusFailed)
+	suite.Require().NoError(err)
+	suite.Require().Len(failed, 1)
+	suite.Equal("mailbox unavailable", failed[0].LastError)
+
+	// Nobody is due right after the invitations went out
+	reminded, err := suite.service.SendReminders(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Zero(reminded)
+
+	for _, invitation := range suite.invitations.invitations {
+		invitation.SentAt = time.Now().Add(-4 * 24 * time.Hour)
+	}
+	delete(suite.email.fail, "bob@example.com")
+	reminded, err = suite.service.RemindSurvey(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Equal(2, reminded)
+	for _, invitation := range suite.invitations.invitations {
+		suite.Equal(entities.SurveyInvitationStatusSent, invitation.Status)
+		suite.Equal(1, invitation.ReminderCount)
+		suite.Empty(invitation.LastError)
+	}
+	suite.Equal("Reminder: Event feedback", suite.email.sent[len(suite.email.sent)-2].Subject)
+}
+
+func (suite *SurveyInvitationServiceTestSuite) TestOpenInvitation() {
+	result, err := suite.service.CreateInvitations(suite.ctx, &services.SurveyInvitationRequest{
+		SurveyID: suite.survey.ID,
+		Channel:  entities.SurveyInvitationChannelEmail,
+		Invitees: []entities.SurveyInvitee{{Email: "ann@example.com"}, {Email: "bob@example.com"}},
+	})
+	suite.Require().NoError(err)
+	ann, bob := result.Invitations[0], result.Invitations[1]
+
+	opened, err := suite.service.OpenInvitation(suite.ctx, ann.Token)
+	suite.Require().NoError(err)
+	suite.Equal(entities.SurveyInvitationStatusOpened, opened.Status)
+
+	responseID := uuid.New()
+	suite.Require().NoError(suite.service.MarkResponded(suite.ctx, ann.ID, responseID))
+	suite.NoError(suite.service.MarkResponded(suite.ctx, ann.ID, responseID))
+	suite.ErrorIs(suite.service.MarkResponded(suite.ctx, ann.ID, uuid.New()), services.ErrSurveyInvitationUsed)
+	_, err = suite.service.OpenInvitation(suite.ctx, ann.Token)
+	suite.ErrorIs(err, services.ErrSurveyInvitationUsed)
+
+	expired := time.Now().Add(-time.Minute)
+	suite.invitations.invitations[1].ExpiresAt = &expired
+	_, err = suite.service.OpenInvitation(suite.ctx, bob.Token)
+	suite.ErrorIs(err, services.ErrSurveyInvitationExpired)
+	suite.Equal(entities.SurveyInvitationStatusExpired, suite.invitations.invitations[1].Status)
+
+	_, err = suite.service.OpenInvitation(suite.ctx, "unknown")
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func TestSurveyInvitationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyInvitationServiceTestSuite))
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// livePollCacheTTL is how long the poll opened last at an event is reused for the interaction
+// code. Every results stream asks for it each second, so only the tally is read on each tick.
+const livePollCacheTTL = 2 * time.Second
+
+// cachedLivePoll is the poll opened last at an event, nil before the first one, with its question
+type cachedLivePoll struct {
+	eventID uuid.UUID
+	poll    *entities.SurveyLivePoll
+	expires time.Time
+}
+
+// SurveyLivePollServiceImpl implements the SurveyLivePollService interface
+type SurveyLivePollServiceImpl struct {
+	pollRepo     repositories.SurveyLivePollRepository
+	surveyRepo   repositories.SurveyRepository
+	questionRepo repositories.SurveyQuestionRepository
+	eventRepo    repositories.SiteEventRepository
+	store        livePollStore
+	logger       *zap.Logger
+
+	mu    sync.Mutex
+	polls map[string]cachedLivePoll // by interaction code
+}
+
+// NewSurveyLivePollService creates a new survey live poll service implementation. Votes are
+// counted in Redis; a nil Redis client counts them in process memory, which only works with a
+// single API instance.
+func NewSurveyLivePollService(
+	pollRepo repositories.SurveyLivePollRepository,
+	surveyRepo repositories.SurveyRepository,
+	questionRepo repositories.SurveyQuestionRepository,
+	eventRepo repositories.SiteEventRepository,
+	redisClient *redis.Client,
+	logger *zap.Logger,
+) services.SurveyLivePollService {
+	return &SurveyLivePollServiceImpl{
+		pollRepo:     pollRepo,
+		surveyRepo:   surveyRepo,
+		questionRepo: questionRepo,
+		eventRepo:    eventRepo,
+		store:        newLivePollStore(redisClient),
+		logger:       logger,
+		polls:        make(map[string]cachedLivePoll),
+	}
+}
+
+// OpenPoll opens a question of a published survey at an event, closing the poll open there before
+func (s *SurveyLivePollServiceImpl) OpenPoll(ctx context.Context, surveyID uuid.UUID, request *services.SurveyLivePollRequest) (*entities.SurveyLivePoll, error) {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	if survey.Status != entities.SurveyStatusPublished {
+		return nil, services.ErrSurveyNotOpen
+	}
+
+	var question *entities.SurveyQuestion
+	for i := range survey.Questions {
+		if survey.Questions[i].ID == request.QuestionID {
+			question = &survey.Questions[i]
+			break
+		}
+	}
+	if question == nil {
+		return nil, repositories.ErrNotFound
+	}
+	if !question.SupportsLivePoll() {
+		return nil, fmt.Errorf("%w: %s questions cannot be counted live", services.ErrLivePollNotSupported, question.QuestionType)
+	}
+
+	if _, err := s.eventRepo.GetByID(ctx, request.EventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	// One question is live at a time per event
+	open, err := s.pollRepo.FindOpenByEventID(ctx, request.EventID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range open {
+		if err := s.closePoll(ctx, &open[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	poll := &entities.SurveyLivePoll{
+		ID:         uuid.New(),
+		SurveyID:   survey.ID,
+		QuestionID: question.ID,
+		EventID:    request.EventID,
+		Status:     entities.SurveyLivePollStatusOpen,
+		Tally:      "{}",
+		OpenedBy:   request.OpenedBy,
+		OpenedAt:   time.Now(),
+	}
+	if err := s.pollRepo.Create(ctx, poll); err != nil {
+		return nil, err
+	}
+	poll.Question = question
+	s.forgetPoll(request.EventID)
+
+	s.logger.Info("Live poll opened",
+		zap.String("pollId", poll.ID.String()),
+		zap.String("surveyId", survey.ID.String()),
+		zap.String("questionId", question.ID.String()),
+		zap.String("eventId", request.EventID.String()))
+	return poll, nil
+}
+
+// ClosePoll stops the voting and stores the final tally. Closing a closed poll returns it unchanged.
+func (s *SurveyLivePollServiceImpl) ClosePoll(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePoll, error) {
+	poll, err := s.pollRepo.FindByID(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.IsOpen() {
+		if err := s.closePoll(ctx, poll); err != nil {
+			return nil, err
+		}
+	}
+	return poll, nil
+}
+
+// GetSurveyPolls lists the polls of a survey, latest first
+func (s *SurveyLivePollServiceImpl) GetSurveyPolls(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLivePoll, error) {
+	if _, err := s.surveyRepo.FindByID(ctx, surveyID); err != nil {
+		return nil, err
+	}
+	return s.pollRepo.FindBySurveyID(ctx, surveyID)
+}
+
+// GetResults returns the live results of an open poll or the final results of a closed one
+func (s *SurveyLivePollServiceImpl) GetResults(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePollResults, error) {
+	poll, err := s.pollRepo.FindByID(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	return s.results(ctx, poll)
+}
+
+// GetCurrentPoll returns the open poll of the event with the interaction code, with its question
+func (s *SurveyLivePollServiceImpl) GetCurrentPoll(ctx context.Context, code string) (*entities.SurveyLivePoll, error) {
+	poll, err := s.latestPoll(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.IsOpen() {
+		return nil, services.ErrNoLivePoll
+	}
+	return poll, nil
+}
+
+// SubmitVote counts a vote on the open poll of the event with the interaction code
+func (s *SurveyLivePollServiceImpl) SubmitVote(ctx context.Context, code string, vote *entities.SurveyLivePollVote) error {
+	if strings.TrimSpace(vote.VoterID) == "" {
+		return fmt.Errorf("%w: voter ID is required", services.ErrInvalidLivePollVote)
+	}
+
+	// Votes skip the cache so that none is counted after the poll closes
+	poll, err := s.findLatestPoll(ctx, code)
+	if err != nil {
+		if errors.Is(err, services.ErrNoLivePoll) {
+			return services.ErrLivePollClosed
+		}
+		return err
+	}
+	if !poll.IsOpen() || vote.PollID != poll.ID {
+		// The presenter moved on while the voter was choosing
+		return services.ErrLivePollClosed
+	}
+
+	choices, err := vote.Choices(poll.Question)
+	if err != nil {
+		return fmt.Errorf("%w: %v", services.ErrInvalidLivePollVote, err)
+	}
+	counted, err := s.store.Vote(ctx, poll.ID, vote.VoterID, choices)
+	if err != nil {
+		return fmt.Errorf("failed to count live poll vote: %w", err)
+	}
+	if !counted {
+		return services.ErrLivePollAlreadyVoted
+	}
+	return nil
+}
+
+// GetCurrentResults returns the results of the poll opened last at the event with the interaction code
+func (s *SurveyLivePollServiceImpl) GetCurrentResults(ctx context.Context, code string) (*entities.SurveyLivePollResults, error) {
+	poll, err := s.latestPoll(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.pollResults(ctx, poll, poll.Question)
+}
+
+// latestPoll returns the poll opened last at the event with the interaction code, with its
+// question, looking it up at most once per livePollCacheTTL
+func (s *SurveyLivePollServiceImpl) latestPoll(ctx context.Context, code string) (*entities.SurveyLivePoll, error) {
+	code = strings.TrimSpace(code)
+	s.mu.Lock()
+	cached, ok := s.polls[code]
+	s.mu.Unlock()
+	if !ok || time.Now().After(cached.expires) {
+		eventID, poll, err := s.lookupLatestPoll(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		cached = cachedLivePoll{eventID: eventID, poll: poll, expires: time.Now().Add(livePollCacheTTL)}
+		s.mu.Lock()
+		s.polls[code] = cached
+		s.mu.Unlock()
+	}
+
+	if cached.poll == nil {
+		return nil, services.ErrNoLivePoll
+	}
+	// Callers may change the poll, so they get their own copy
+	poll := *cached.poll
+	return &poll, nil
+}
+
+// findLatestPoll returns the poll opened last at the event with the interaction code, with its
+// question, bypassing the cache
+func (s *SurveyLivePollServiceImpl) findLatestPoll(ctx context.Context, code string) (*entities.SurveyLivePoll, error) {
+	_, poll, err := s.lookupLatestPoll(ctx, strings.TrimSpace(code))
+	if err != nil {
+		return nil, err
+	}
+	if poll == nil {
+		return nil, services.ErrNoLivePoll
+	}
+	return poll, nil
+}
+
+// lookupLatestPoll finds the event with the interaction code and the poll opened last there, with
+// its question. The poll is nil before the first one is opened.
+func (s *SurveyLivePollServiceImpl) lookupLatestPoll(ctx context.Context, code string) (uuid.UUID, *entities.SurveyLivePoll, error) {
+	if code == "" {
+		return uuid.Nil, nil, repositories.ErrNotFound
+	}
+	event, err := s.eventRepo.GetByInteractionCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, nil, repositories.ErrNotFound
+		}
+		return uuid.Nil, nil, fmt.Errorf("failed to find event: %w", err)
+	}
+
+	poll, err := s.pollRepo.FindLatestByEventID(ctx, event.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return event.ID, nil, nil
+		}
+		return uuid.Nil, nil, err
+	}
+	question, err := s.questionRepo.FindByID(ctx, poll.QuestionID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	poll.Question = question
+	return event.ID, poll, nil
+}
+
+// forgetPoll drops the cached poll of an event after the presenter opens or closes one
+func (s *SurveyLivePollServiceImpl) forgetPoll(eventID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for code, cached := range s.polls {
+		if cached.eventID == eventID {
+			delete(s.polls, code)
+		}
+	}
+}
+
+// results lays out the tally of a poll, counted live while it is open
+func (s *SurveyLivePollServiceImpl) results(ctx context.Context, poll *entities.SurveyLivePoll) (*entities.SurveyLivePollResults, error) {
+	question, err := s.questionRepo.FindByID(ctx, poll.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.pollResults(ctx, poll, question)
+}
+
+// pollResults lays out the tally of a poll on its question
+func (s *SurveyLivePollServiceImpl) pollResults(ctx context.Context, poll *entities.SurveyLivePoll, question *entities.SurveyQuestion) (*entities.SurveyLivePollResults, error) {
+	var tally *entities.SurveyLivePollTally
+	var err error
+	if poll.IsOpen() {
+		tally, err = s.store.Tally(ctx, poll.ID)
+	} else {
+		tally, err = poll.GetTally()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entities.NewSurveyLivePollResults(poll, question, tally), nil
+}
+
+// closePoll stores the tally counted so far and closes the poll
+func (s *SurveyLivePollServiceImpl) closePoll(ctx context.Context, poll *entities.SurveyLivePoll) error {
+	tally, err := s.store.Tally(ctx, poll.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count live poll votes: %w", err)
+	}
+	if err := poll.Close(tally, time.Now()); err != nil {
+		return err
+	}
+	if err := s.pollRepo.Update(ctx, poll); err != nil {
+		return err
+	}
+	s.forgetPoll(poll.EventID)
+
+	s.logger.Info("Live poll closed",
+		zap.String("pollId", poll.ID.String()),
+		zap.Int("votes", tally.Votes))
+	return nil
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *memoryEventRepository) GetByInteractionCode(ctx context.Context, code string) (*entities.SiteEvent, error) {
+	for _, event := range r.events {
+		if event.InteractionCode == code {
+			copied := *event
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memorySurveyQuestionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyQuestion, error) {
+	for _, survey := range r.surveys.surveys {
+		for _, question := range survey.Questions {
+			if question.ID == id {
+				return &question, nil
+			}
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// memorySurveyLivePollRepository keeps live polls in memory in the order they were opened
+type memorySurveyLivePollRepository struct {
+	repositories.SurveyLivePollRepository
+	polls   []entities.SurveyLivePoll
+	lookups int // calls to FindLatestByEventID
+}
+
+func (r *memorySurveyLivePollRepository) Create(ctx context.Context, poll *entities.SurveyLivePoll) error {
+	r.polls = append(r.polls, *poll)
+	return nil
+}
+
+func (r *memorySurveyLivePollRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyLivePoll, error) {
+	for _, poll := range r.polls {
+		if poll.ID == id {
+			return &poll, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyLivePollRepository) Update(ctx context.Context, poll *entities.SurveyLivePoll) error {
+	for i := range r.polls {
+		if r.polls[i].ID == poll.ID {
+			r.polls[i] = *poll
+			r.polls[i].Question = nil
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyLivePollRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLivePoll, error) {
+	var polls []entities.SurveyLivePoll
+	for i := len(r.polls) - 1; i >= 0; i-- {
+		if r.polls[i].SurveyID == surveyID {
+			polls = append(polls, r.polls[i])
+		}
+	}
+	return polls, nil
+}
+
+func (r *memorySurveyLivePollRepository) FindLatestByEventID(ctx context.Context, eventID uuid.UUID) (*entities.SurveyLivePoll, error) {
+	r.lookups++
+	for i := len(r.polls) - 1; i >= 0; i-- {
+		if r.polls[i].EventID == eventID {
+			poll := r.polls[i]
+			return &poll, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyLivePollRepository) FindOpenByEventID(ctx context.Context, eventID uuid.UUID) ([]entities.SurveyLivePoll, error) {
+	var polls []entities.SurveyLivePoll
+	for _, poll := range r.polls {
+		if poll.EventID == eventID && poll.IsOpen() {
+			polls = append(polls, poll)
+		}
+	}
+	return polls, nil
+}
+
+type SurveyLivePollServiceTestSuite struct {
+	suite.Suite
+	polls   *memorySurveyLivePollRepository
+	service services.SurveyLivePollService
+	survey  *entities.Survey
+	event   *entities.SiteEvent
+	ctx     context.Context
+}
+
+func (suite *SurveyLivePollServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	surveyID := uuid.New()
+	suite.survey = &entities.Survey{
+		ID:     surveyID,
+		Title:  "Keynote",
+		Status: entities.SurveyStatusPublished,
+		Questions: []entities.SurveyQuestion{
+			{ID: uuid.New(), SurveyID: surveyID, Order: 1, QuestionType: entities.QuestionTypeRadio, QuestionText: "Favourite topic", Options: pq.StringArray{"AI", "Web", "Cloud"}},
+			{ID: uuid.New(), SurveyID: surveyID, Order: 2, QuestionType: entities.QuestionTypeRating, QuestionText: "Rate the keynote"},
+			{ID: uuid.New(), SurveyID: surveyID, Order: 3, QuestionType: entities.QuestionTypeText, QuestionText: "Questions for the speaker"},
+		},
+	}
+	suite.event = &entities.SiteEvent{ID: uuid.New(), EventTitle: "Summit", InteractionCode: "EVT123"}
+
+	surveys := &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{surveyID: suite.survey}}
+	suite.polls = &memorySurveyLivePollRepository{}
+	suite.service = NewSurveyLivePollService(
+		suite.polls,
+		surveys,
+		&memorySurveyQuestionRepository{surveys: surveys},
+		&memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{suite.event.ID: suite.event}},
+		nil,
+		zap.NewNop(),
+	)
+}
+
+func (suite *SurveyLivePollServiceTestSuite) open(question int) *entities.SurveyLivePoll {
+	poll, err := suite.service.OpenPoll(suite.ctx, suite.survey.ID, &services.SurveyLivePollRequest{
+		EventID:    suite.event.ID,
+		QuestionID: suite.survey.Questions[question].ID,
+	})
+	suite.Require().NoError(err)
+	return poll
+}
+
+func (suite *SurveyLivePollServiceTestSuite) vote(pollID uuid.UUID, voter string, options ...string) error {
+	return suite.service.SubmitVote(suite.ctx, "EVT123", &entities.SurveyLivePollVote{PollID: pollID, VoterID: voter, Options: options})
+}
+
+func (suite *SurveyLivePollServiceTestSuite) TestVotingOnLivePoll() {
+	_, err := suite.service.GetCurrentPoll(suite.ctx, "EVT123")
+	suite.ErrorIs(err, services.ErrNoLivePoll)
+
+	poll := suite.open(0)
+	current, err := suite.service.GetCurrentPoll(suite.ctx, "EVT123")
+	suite.Require().NoError(err)
+	suite.Equal(poll.ID, current.ID)
+	suite.Equal("Favourite topic", current.Question.QuestionText)
+
+	suite.NoError(suite.vote(poll.ID, "a", "AI"))
+	suite.NoError(suite.vote(poll.ID, "b", "AI"))
+	suite.NoError(suite.vote(poll.ID, "c", "Cloud"))
+	suite.ErrorIs(suite.vote(poll.ID, "a", "Web"), services.ErrLivePollAlreadyVoted)
+	suite.ErrorIs(suite.vote(poll.ID, "d", "Mobile"), services.ErrInvalidLivePollVote)
+	suite.ErrorIs(suite.vote(poll.ID, "", "AI"), services.ErrInvalidLivePollVote)
+	suite.ErrorIs(suite.vote(uuid.New(), "d", "AI"), services.ErrLivePollClosed)
+
+	results, err := suite.service.GetCurrentResults(suite.ctx, "EVT123")
+	suite.Require().NoError(err)
+	suite.Equal(3, results.Votes)
+	suite.Require().Len(results.Options, 3)
+	suite.Equal(entities.SurveyLivePollOptionResult{Option: "AI", Count: 2, Percentage: 66.67}, results.Options[0])
+	suite.Equal(0, results.Options[1].Count)
+
+	// Opening the next question closes the poll and keeps its tally
+	next := suite.open(1)
+	suite.ErrorIs(suite.vote(poll.ID, "d", "AI"), services.ErrLivePollClosed)
+	suite.NoError(suite.vote(next.ID, "a", "4"))
+
+	closed, err := suite.service.GetResults(suite.ctx, poll.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.SurveyLivePollStatusClosed, closed.Status)
+	suite.Equal(3, closed.Votes)
+	suite.Equal(2, closed.Options[0].Count)
+
+	closedPoll, err := suite.service.ClosePoll(suite.ctx, next.ID)
+	suite.Require().NoError(err)
+	suite.Equal(1, closedPoll.VoteCount)
+	_, err = suite.service.GetCurrentPoll(suite.ctx, "EVT123")
+	suite.ErrorIs(err, services.ErrNoLivePoll)
+
+	// The big screen keeps showing the final results of the last poll
+	results, err = suite.service.GetCurrentResults(suite.ctx, "EVT123")
+	suite.Require().NoError(err)
+	suite.Equal(next.ID, results.PollID)
+	suite.Require().NotNil(results.Average)
+	suite.Equal(4.0, *results.Average)
+
+	polls, err := suite.service.GetSurveyPolls(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Len(polls, 2)
+}
+
+func (suite *SurveyLivePollServiceTestSuite) TestResultsStreamReusesPollLookup() {
+	poll := suite.open(0)
+	for i := 0; i < 3; i++ {
+		_, err := suite.service.GetCurrentResults(suite.ctx, "EVT123")
+		suite.Require().NoError(err)
+	}
+	suite.Equal(1, suite.polls.lookups)
+
+	// Tallies stay live while the poll is cached
+	suite.NoError(suite.vote(poll.ID, "a", "Web"))
+	results, err := suite.service.GetCurrentResults(suite.ctx, "EVT123")
+	suite.Require().NoError(err)
+	suite.Equal(1, results.Votes)
+
+	// Closing the poll shows on the next tick
+	_, err = suite.service.ClosePoll(suite.ctx, poll.ID)
+	suite.Require().NoError(err)
+	results, err = suite.service.GetCurrentResults(suite.ctx, "EVT123")
+	suite.Require().NoError(err)
+	suite.Equal(entities.SurveyLivePollStatusClosed, results.Status)
+}
+
+func (suite *SurveyLivePollServiceTestSuite) TestOpenPollRejectsInvalidRequests() {
+	_, err := suite.service.OpenPoll(suite.ctx, suite.survey.ID, &services.SurveyLivePollRequest{
+		EventID:    suite.event.ID,
+		QuestionID: suite.survey.Questions[2].ID,
+	})
+	suite.ErrorIs(err, services.ErrLivePollNotSupported)
+
+	_, err = suite.service.OpenPoll(suite.ctx, suite.survey.ID, &services.SurveyLivePollRequest{
+		EventID:    uuid.New(),
+		QuestionID: suite.survey.Questions[0].ID,
+	})
+	suite.ErrorIs(err, repositories.ErrNotFound)
+
+	suite.survey.Status = entities.SurveyStatusDraft
+	_, err = suite.service.OpenPoll(suite.ctx, suite.survey.ID, &services.SurveyLivePollRequest{
+		EventID:    suite.event.ID,
+		QuestionID: suite.survey.Questions[0].ID,
+	})
+	suite.ErrorIs(err, services.ErrSurveyNotOpen)
+	suite.Empty(suite.polls.polls)
+
+	_, err = suite.service.GetCurrentPoll(suite.ctx, "UNKNOWN")
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func TestSurveyLivePollServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyLivePollServiceTestSuite))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+const livePollKeyPrefix = "survey:live_poll:"
+
+// livePollVoteTTL is how long the votes of a poll are kept after the last vote; closing the poll
+// stores them in the database
+const livePollVoteTTL = 24 * time.Hour
+
+// livePollStore counts live poll votes outside the database, so a keynote audience voting at once
+// does not queue up on it
+type livePollStore interface {
+	// Vote counts the choices of a voter. It returns false when the voter has voted on the poll before.
+	Vote(ctx context.Context, pollID uuid.UUID, voter string, choices []string) (bool, error)
+	// Tally returns the votes counted for a poll
+	Tally(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePollTally, error)
+}
+
+// newLivePollStore uses Redis when it is configured and falls back to process memory otherwise
+func newLivePollStore(redisClient *redis.Client) livePollStore {
+	if redisClient == nil {
+		return newMemoryLivePollStore()
+	}
+	return &redisLivePollStore{client: redisClient}
+}
+
+// redisLivePollStore shares the counters of a poll between API instances
+type redisLivePollStore struct {
+	client *redis.Client
+}
+
+func (s *redisLivePollStore) keys(pollID uuid.UUID) (voters, votes, counts string) {
+	prefix := livePollKeyPrefix + pollID.String()
+	return prefix + ":voters", prefix + ":votes", prefix + ":counts"
+}
+
+func (s *redisLivePollStore) Vote(ctx context.Context, pollID uuid.UUID, voter string, choices []string) (bool, error) {
+	voters, votes, counts := s.keys(pollID)
+	added, err := s.client.SAdd(ctx, voters, voter).Result()
+	if err != nil || added == 0 {
+		return false, err
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, votes)
+		for _, choice := range choices {
+			pipe.HIncrBy(ctx, counts, choice, 1)
+		}
+		for _, key := range []string{voters, votes, counts} {
+			pipe.Expire(ctx, key, livePollVoteTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		// Let the voter try again
+		s.client.SRem(ctx, voters, voter)
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *redisLivePollStore) Tally(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePollTally, error) {
+	_, votes, counts := s.keys(pollID)
+	pipe := s.client.Pipeline()
+	votesCmd := pipe.Get(ctx, votes)
+	countsCmd := pipe.HGetAll(ctx, counts)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	tally := &entities.SurveyLivePollTally{Counts: map[string]int{}}
+	if value, err := votesCmd.Int(); err == nil {
+		tally.Votes = value
+	}
+	for choice, value := range countsCmd.Val() {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		tally.Counts[choice] = count
+	}
+	return tally, nil
+}
+
+// memoryLivePollStore counts votes in process memory, for single-instance deployments without Redis
+type memoryLivePollStore struct {
+	mu     sync.Mutex
+	voters map[uuid.UUID]map[string]bool
+	tally  map[uuid.UUID]*entities.SurveyLivePollTally
+}
+
+func newMemoryLivePollStore() *memoryLivePollStore {
+	return &memoryLivePollStore{
+		voters: make(map[uuid.UUID]map[string]bool),
+		tally:  make(map[uuid.UUID]*entities.SurveyLivePollTally),
+	}
+}
+
+func (s *memoryLivePollStore) Vote(ctx context.Context, pollID uuid.UUID, voter string, choices []string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.voters[pollID] == nil {
+		s.voters[pollID] = make(map[string]bool)
+		s.tally[pollID] = &entities.SurveyLivePollTally{Counts: map[string]int{}}
+	}
+	if s.voters[pollID][voter] {
+		return false, nil
+	}
+	s.voters[pollID][voter] = true
+
+	tally := s.tally[pollID]
+	tally.Votes++
+	for _, choice := range choices {
+		tally.Counts[choice]++
+	}
+	return true, nil
+}
+
+func (s *memoryLivePollStore) Tally(ctx context.Context, pollID uuid.UUID) (*entities.SurveyLivePollTally, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tally := &entities.SurveyLivePollTally{Counts: map[string]int{}}
+	if counted, ok := s.tally[pollID]; ok {
+		tally.Votes = counted.Votes
+		for choice, count := range counted.Counts {
+			tally.Counts[choice] = count
+		}
+	}
+	return tally, nil
+}
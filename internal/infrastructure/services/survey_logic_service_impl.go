@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyLogicServiceImpl implements the SurveyLogicService interface
+type SurveyLogicServiceImpl struct {
+	logicRepo    repositories.SurveyLogicRepository
+	questionRepo repositories.SurveyQuestionRepository
+	logger       *zap.Logger
+}
+
+// NewSurveyLogicService creates a new survey logic service implementation
+func NewSurveyLogicService(
+	logicRepo repositories.SurveyLogicRepository,
+	questionRepo repositories.SurveyQuestionRepository,
+	logger *zap.Logger,
+) services.SurveyLogicService {
+	return &SurveyLogicServiceImpl{
+		logicRepo:    logicRepo,
+		questionRepo: questionRepo,
+		logger:       logger,
+	}
+}
+
+// CreateLogic validates and stores a logic rule
+func (s *SurveyLogicServiceImpl) CreateLogic(ctx context.Context, logic *entities.SurveyLogic) error {
+	if err := s.validateLogic(ctx, logic); err != nil {
+		return err
+	}
+	if err := s.logicRepo.Create(ctx, logic); err != nil {
+		return err
+	}
+
+	s.logger.Info("Survey logic created",
+		zap.String("surveyId", logic.SurveyID.String()),
+		zap.String("logicId", logic.ID.String()),
+		zap.String("type", logic.LogicType))
+	return nil
+}
+
+// GetLogic retrieves a logic rule by ID
+func (s *SurveyLogicServiceImpl) GetLogic(ctx context.Context, id uuid.UUID) (*entities.SurveyLogic, error) {
+	return s.logicRepo.FindByID(ctx, id)
+}
+
+// GetSurveyLogic retrieves every logic rule of a survey
+func (s *SurveyLogicServiceImpl) GetSurveyLogic(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error) {
+	return s.logicRepo.FindBySurveyID(ctx, surveyID)
+}
+
+// UpdateLogic validates and updates a logic rule
+func (s *SurveyLogicServiceImpl) UpdateLogic(ctx context.Context, logic *entities.SurveyLogic) error {
+	if err := s.validateLogic(ctx, logic); err != nil {
+		return err
+	}
+	return s.logicRepo.Update(ctx, logic)
+}
+
+// DeleteLogic deletes a logic rule
+func (s *SurveyLogicServiceImpl) DeleteLogic(ctx context.Context, id uuid.UUID) error {
+	return s.logicRepo.Delete(ctx, id)
+}
+
+// Evaluate computes the navigation of a partial response
+func (s *SurveyLogicServiceImpl) Evaluate(ctx context.Context, surveyID uuid.UUID, answers []entities.SurveyAnswer) (*entities.SurveyNavigation, error) {
+	questions, logic, err := s.loadSurvey(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	return entities.EvaluateSurveyLogic(questions, logic, answers)
+}
+
+// ApplyLogic clears and skips the answers to hidden questions and validates the visible ones
+func (s *SurveyLogicServiceImpl) ApplyLogic(ctx context.Context, surveyID uuid.UUID, answers []entities.SurveyAnswer) ([]entities.SurveyAnswer, *entities.SurveyNavigation, error) {
+	questions, logic, err := s.loadSurvey(ctx, surveyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	navigation, err := entities.EvaluateSurveyLogic(questions, logic, answers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	questionByID := make(map[uuid.UUID]*entities.SurveyQuestion, len(questions))
+	for i := range questions {
+		questionByID[questions[i].ID] = &questions[i]
+	}
+
+	validationErrors := make(map[uuid.UUID]string)
+	prepared := make([]entities.SurveyAnswer, 0, len(questions))
+	answered := make(map[uuid.UUID]bool, len(answers))
+	for _, answer := range answers {
+		question := questionByID[answer.QuestionID]
+		if question == nil {
+			validationErrors[answer.QuestionID] = "question is not part of this survey"
+			continue
+		}
+		if answered[answer.QuestionID] {
+			validationErrors[answer.QuestionID] = "question is answered more than once"
+			continue
+		}
+		answered[answer.QuestionID] = true
+
+		if !navigation.IsVisible(answer.QuestionID) {
+			prepared = append(prepared, skippedAnswer(answer))
+			continue
+		}
+		if err := validateSurveyAnswer(&answer, question); err != nil {
+			validationErrors[answer.QuestionID] = err.Error()
+			continue
+		}
+		prepared = append(prepared, answer)
+	}
+
+	// Hidden questions are recorded as skipped; visible required ones must have been answered
+	for _, question := range questions {
+		if answered[question.ID] {
+			continue
+		}
+		if !navigation.IsVisible(question.ID) {
+			prepared = append(prepared, entities.SurveyAnswer{QuestionID: question.ID, IsSkipped: true})
+			continue
+		}
+		if question.IsRequired {
+			validationErrors[question.ID] = entities.ErrAnswerRequired.Error()
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, navigation, &services.SurveyResponseValidationError{Errors: validationErrors}
+	}
+	return prepared, navigation, nil
+}
+
+// loadSurvey loads the questions and active logic rules of a survey
+func (s *SurveyLogicServiceImpl) loadSurvey(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuestion, []entities.SurveyLogic, error) {
+	questions, err := s.questionRepo.FindBySurveyID(ctx, surveyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	logic, err := s.logicRepo.FindActiveBySurveyID(ctx, surveyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return questions, logic, nil
+}
+
+// validateLogic checks the rule itself and that every question it refers to belongs to the survey
+func (s *SurveyLogicServiceImpl) validateLogic(ctx context.Context, logic *entities.SurveyLogic) error {
+	if err := logic.Validate(); err != nil {
+		return err
+	}
+
+	questions, err := s.questionRepo.FindBySurveyID(ctx, logic.SurveyID)
+	if err != nil {
+		return err
+	}
+	inSurvey := make(map[uuid.UUID]bool, len(questions))
+	for _, question := range questions {
+		inSurvey[question.ID] = true
+	}
+	if !inSurvey[logic.QuestionID] {
+		return fmt.Errorf("%w: trigger question %s is not part of the survey", entities.ErrInvalidLogicCondition, logic.QuestionID)
+	}
+
+	conditions, _ := logic.GetConditions()
+	for _, rule := range conditions.Rules {
+		if rule.QuestionID != nil && !inSurvey[*rule.QuestionID] {
+			return fmt.Errorf("%w: question %s is not part of the survey", entities.ErrInvalidLogicCondition, *rule.QuestionID)
+		}
+	}
+	actions, _ := logic.GetActions()
+	targets := append([]uuid.UUID{}, actions.QuestionIDs...)
+	if actions.TargetID != nil {
+		targets = append(targets, *actions.TargetID)
+	}
+	for _, id := range targets {
+		if !inSurvey[id] {
+			return fmt.Errorf("%w: question %s is not part of the survey", entities.ErrInvalidLogicAction, id)
+		}
+	}
+	return nil
+}
+
+// validateSurveyAnswer validates a visible answer. Unlike SurveyAnswer.Validate, a required
+// question cannot be answered by skipping it.
+func validateSurveyAnswer(answer *entities.SurveyAnswer, question *entities.SurveyQuestion) error {
+	if question.IsRequired && !answer.HasValue() {
+		return entities.ErrAnswerRequired
+	}
+	return answer.Validate(question)
+}
+
+// skippedAnswer clears the value of an answer to a hidden question
+func skippedAnswer(answer entities.SurveyAnswer) entities.SurveyAnswer {
+	return entities.SurveyAnswer{
+		ID:         answer.ID,
+		ResponseID: answer.ResponseID,
+		QuestionID: answer.QuestionID,
+		IsSkipped:  true,
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+)
+
+// webhookTimeout bounds one webhook call
+const webhookTimeout = 10 * time.Second
+
+// surveyNotificationEventLabels names the events in WeChat template messages
+var surveyNotificationEventLabels = map[string]string{
+	entities.SurveyNotificationEventResponseSubmitted: "New survey response",
+	entities.SurveyNotificationEventQuotaReached:      "Survey quota reached",
+	entities.SurveyNotificationEventSurveyClosed:      "Survey closed",
+}
+
+// NewSurveyNotificationChannels returns the notification channels that are configured: email
+// always, WeChat template messages when a sender and template are given and webhooks.
+func NewSurveyNotificationChannels(email services.EmailSender, wechat services.TemplateMessageSender, wechatTemplateID string) map[string]services.SurveyNotificationChannel {
+	channels := map[string]services.SurveyNotificationChannel{
+		entities.SurveyNotificationChannelWebhook: NewWebhookNotificationChannel(&http.Client{Timeout: webhookTimeout}),
+	}
+	if email != nil {
+		channels[entities.SurveyNotificationChannelEmail] = NewEmailNotificationChannel(email)
+	}
+	if wechat != nil && wechatTemplateID != "" {
+		channels[entities.SurveyNotificationChannelWeChat] = NewWeChatNotificationChannel(wechat, wechatTemplateID)
+	}
+	return channels
+}
+
+// EmailNotificationChannel delivers survey notifications as plain text email
+type EmailNotificationChannel struct {
+	sender services.EmailSender
+}
+
+// NewEmailNotificationChannel creates a new email notification channel
+func NewEmailNotificationChannel(sender services.EmailSender) services.SurveyNotificationChannel {
+	return &EmailNotificationChannel{sender: sender}
+}
+
+// Deliver emails the notification to the recipient
+func (c *EmailNotificationChannel) Deliver(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error {
+	return c.sender.SendEmail(ctx, &services.EmailMessage{
+		To:      delivery.Recipient,
+		Subject: delivery.Subject,
+		Body:    delivery.Message,
+	})
+}
+
+// WeChatNotificationChannel delivers survey notifications as WeChat template messages to followers
+type WeChatNotificationChannel struct {
+	sender     services.TemplateMessageSender
+	templateID string
+}
+
+// NewWeChatNotificationChannel creates a new WeChat template message notification channel
+func NewWeChatNotificationChannel(sender services.TemplateMessageSender, templateID string) services.SurveyNotificationChannel {
+	return &WeChatNotificationChannel{
+		sender:     sender,
+		templateID: templateID,
+	}
+}
+
+// Deliver sends the notification to the recipient's OpenID
+func (c *WeChatNotificationChannel) Deliver(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error {
+	return c.sender.SendTemplateMessage(ctx, delivery.Recipient, &services.TemplateMessage{
+		TemplateID: c.templateID,
+		Data: map[string]interface{}{
+			"first":    map[string]string{"value": delivery.Subject},
+			"keyword1": map[string]string{"value": surveyNotificationEventLabels[delivery.EventType]},
+			"keyword2": map[string]string{"value": delivery.CreatedAt.Format("2006-01-02 15:04")},
+			"remark":   map[string]string{"value": delivery.Message},
+		},
+	})
+}
+
+// WebhookNotificationChannel posts survey notifications as JSON to webhook URLs
+type WebhookNotificationChannel struct {
+	client *http.Client
+}
+
+// NewWebhookNotificationChannel creates a new webhook notification channel
+func NewWebhookNotificationChannel(client *http.Client) services.SurveyNotificationChannel {
+	return &WebhookNotificationChannel{client: client}
+}
+
+// Deliver posts the delivery payload to the recipient URL. The delivery ID is sent along so
+// receivers can ignore retries they already handled.
+func (c *WebhookNotificationChannel) Deliver(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Recipient, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-NextEvent-Event", delivery.EventType)
+	req.Header.Set("X-NextEvent-Delivery", delivery.ID.String())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// notificationRetryDelay is the wait before the first retry of a failed delivery; it doubles with every attempt
+	notificationRetryDelay = time.Minute
+	// maxNotificationAttempts bounds the attempts of one delivery
+	maxNotificationAttempts = 5
+	// notificationDeliveryBatchSize bounds the deliveries retried and the closed surveys notified by one run
+	notificationDeliveryBatchSize = 200
+	// notificationDeliveryListLimit bounds the delivery log returned for a notification
+	notificationDeliveryListLimit = 100
+)
+
+// SurveyNotificationServiceImpl implements the SurveyNotificationService interface
+type SurveyNotificationServiceImpl struct {
+	notificationRepo repositories.SurveyNotificationRepository
+	deliveryRepo     repositories.SurveyNotificationDeliveryRepository
+	surveyRepo       repositories.SurveyRepository
+	responseRepo     repositories.SurveyResponseRepository
+	channels         map[string]services.SurveyNotificationChannel
+	queue            services.SurveyNotificationQueue
+	logger           *zap.Logger
+}
+
+// NewSurveyNotificationService creates a new survey notification service implementation. Without a
+// queue deliveries are attempted in the background of the calling process.
+func NewSurveyNotificationService(
+	notificationRepo repositories.SurveyNotificationRepository,
+	deliveryRepo repositories.SurveyNotificationDeliveryRepository,
+	surveyRepo repositories.SurveyRepository,
+	responseRepo repositories.SurveyResponseRepository,
+	channels map[string]services.SurveyNotificationChannel,
+	queue services.SurveyNotificationQueue,
+	logger *zap.Logger,
+) services.SurveyNotificationService {
+	return &SurveyNotificationServiceImpl{
+		notificationRepo: notificationRepo,
+		deliveryRepo:     deliveryRepo,
+		surveyRepo:       surveyRepo,
+		responseRepo:     responseRepo,
+		channels:         channels,
+		queue:            queue,
+		logger:           logger,
+	}
+}
+
+// surveyNotificationPayload is the event data stored with a delivery and posted to webhooks
+type surveyNotificationPayload struct {
+	Event       string            `json:"event"`
+	SurveyID    uuid.UUID         `json:"surveyId"`
+	SurveyTitle string            `json:"surveyTitle"`
+	ResponseID  *uuid.UUID        `json:"responseId,omitempty"`
+	QuotaID     *uuid.UUID        `json:"quotaId,omitempty"`
+	QuotaName   string            `json:"quotaName,omitempty"`
+	Answers     map[string]string `json:"answers,omitempty"` // Answer of each question by question ID
+	Subject     string            `json:"subject"`
+	Message     string            `json:"message"`
+	OccurredAt  time.Time         `json:"occurredAt"`
+}
+
+// CreateNotification validates and stores a notification
+func (s *SurveyNotificationServiceImpl) CreateNotification(ctx context.Context, notification *entities.SurveyNotification) error {
+	if err := s.validateNotification(ctx, notification); err != nil {
+		return err
+	}
+	notification.SendCount = 0
+	notification.LastSent = nil
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	s.logger.Info("Survey notification created",
+		zap.String("surveyId", notification.SurveyID.String()),
+		zap.String("notificationId", notification.ID.String()),
+		zap.String("eventType", notification.EventType),
+		zap.String("channel", notification.Channel))
+	return nil
+}
+
+// GetNotification retrieves a notification by ID
+func (s *SurveyNotificationServiceImpl) GetNotification(ctx context.Context, id uuid.UUID) (*entities.SurveyNotification, error) {
+	return s.notificationRepo.FindByID(ctx, id)
+}
+
+// GetSurveyNotifications retrieves every notification of a survey
+func (s *SurveyNotificationServiceImpl) GetSurveyNotifications(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyNotification, error) {
+	return s.notificationRepo.FindBySurveyID(ctx, surveyID)
+}
+
+// UpdateNotification validates and updates a notification's settings
+func (s *SurveyNotificationServiceImpl) UpdateNotification(ctx context.Context, notification *entities.SurveyNotification) error {
+	if err := s.validateNotification(ctx, notification); err != nil {
+		return err
+	}
+	return s.notificationRepo.Update(ctx, notification)
+}
+
+// DeleteNotification deletes a notification and its delivery log
+func (s *SurveyNotificationServiceImpl) DeleteNotification(ctx context.Context, id uuid.UUID) error {
+	return s.notificationRepo.Delete(ctx, id)
+}
+
+// GetDeliveries lists the latest deliveries of a notification
+func (s *SurveyNotificationServiceImpl) GetDeliveries(ctx context.Context, notificationID uuid.UUID) ([]entities.SurveyNotificationDelivery, error) {
+	if _, err := s.notificationRepo.FindByID(ctx, notificationID); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.FindByNotificationID(ctx, notificationID, notificationDeliveryListLimit)
+}
+
+// Dispatch logs and hands out the deliveries of the event's notifications. survey_closed
+// notifications are only dispatched once.
+func (s *SurveyNotificationServiceImpl) Dispatch(ctx context.Context, event *services.SurveyNotificationEvent) error {
+	notifications, err := s.notificationRepo.FindActive(ctx, event.Survey.ID, event.EventType)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+	values, err := s.eventValues(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []entities.SurveyNotificationDelivery
+	for i := range notifications {
+		notification := &notifications[i]
+		matched, err := notification.Matches(event.Answers)
+		if err != nil {
+			s.logger.Warn("Skipping survey notification with invalid conditions",
+				zap.String("notificationId", notification.ID.String()), zap.Error(err))
+			continue
+		}
+		if !matched {
+			continue
+		}
+		if event.EventType == entities.SurveyNotificationEventSurveyClosed {
+			dispatched, err := s.deliveryRepo.ExistsForNotification(ctx, notification.ID)
+			if err != nil {
+				return err
+			}
+			if dispatched {
+				continue
+			}
+		}
+
+		subject, message := notification.Render(values)
+		payload, err := s.buildPayload(event, values, subject, message, now)
+		if err != nil {
+			return err
+		}
+		_, configured := s.channels[notification.Channel]
+		seen := make(map[string]bool, len(notification.Recipients))
+		for _, recipient := range notification.Recipients {
+			recipient = strings.TrimSpace(recipient)
+			if recipient == "" || seen[recipient] {
+				continue
+			}
+			seen[recipient] = true
+
+			nextAttempt := now.Add(notificationRetryDelay)
+			delivery := entities.SurveyNotificationDelivery{
+				ID:             uuid.New(),
+				NotificationID: notification.ID,
+				SurveyID:       event.Survey.ID,
+				EventType:      event.EventType,
+				Channel:        notification.Channel,
+				Recipient:      recipient,
+				Subject:        subject,
+				Message:        message,
+				Payload:        string(payload),
+				Status:         entities.SurveyNotificationDeliveryPending,
+				NextAttemptAt:  &nextAttempt,
+			}
+			if event.Response != nil {
+				delivery.ResponseID = &event.Response.ID
+			}
+			if !configured {
+				// Logged as failed so organizers can see why nothing arrived
+				delivery.Status = entities.SurveyNotificationDeliveryFailed
+				delivery.NextAttemptAt = nil
+				delivery.LastError = fmt.Sprintf("%s notifications are not configured", notification.Channel)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := s.deliveryRepo.CreateBatch(ctx, deliveries); err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if deliveries[i].Status == entities.SurveyNotificationDeliveryPending {
+			s.send(ctx, deliveries[i].ID)
+		}
+	}
+
+	s.logger.Info("Survey notifications dispatched",
+		zap.String("surveyId", event.Survey.ID.String()),
+		zap.String("eventType", event.EventType),
+		zap.Int("deliveries", len(deliveries)))
+	return nil
+}
+
+// ProcessDelivery attempts a pending delivery. Deliveries that were sent or gave up are left alone,
+// so retried jobs do not deliver twice.
+func (s *SurveyNotificationServiceImpl) ProcessDelivery(ctx context.Context, deliveryID uuid.UUID) error {
+	delivery, err := s.deliveryRepo.FindByID(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.Status != entities.SurveyNotificationDeliveryPending {
+		return nil
+	}
+	_, err = s.attempt(ctx, delivery)
+	return err
+}
+
+// RetryDeliveries attempts the pending deliveries whose retry is due
+func (s *SurveyNotificationServiceImpl) RetryDeliveries(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveryRepo.FindDue(ctx, time.Now(), notificationDeliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range deliveries {
+		ok, err := s.attempt(ctx, &deliveries[i])
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+	if len(deliveries) > 0 {
+		s.logger.Info("Survey notification deliveries retried",
+			zap.Int("attempted", len(deliveries)),
+			zap.Int("sent", sent))
+	}
+	return sent, nil
+}
+
+// NotifyClosedSurveys dispatches the survey_closed notifications of closed and ended surveys
+func (s *SurveyNotificationServiceImpl) NotifyClosedSurveys(ctx context.Context) (int, error) {
+	notifications, err := s.notificationRepo.FindUndispatchedClosed(ctx, time.Now(), notificationDeliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	notified := 0
+	for _, notification := range notifications {
+		if seen[notification.SurveyID] {
+			continue
+		}
+		seen[notification.SurveyID] = true
+
+		survey, err := s.surveyRepo.FindByID(ctx, notification.SurveyID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return notified, err
+		}
+		event := &services.SurveyNotificationEvent{EventType: entities.SurveyNotificationEventSurveyClosed, Survey: survey}
+		if err := s.Dispatch(ctx, event); err != nil {
+			return notified, err
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+// send hands a delivery to the queue, or attempts it in the background without one. Deliveries the
+// queue does not take are picked up by RetryDeliveries.
+func (s *SurveyNotificationServiceImpl) send(ctx context.Context, deliveryID uuid.UUID) {
+	if s.queue == nil {
+		go s.processInBackground(deliveryID)
+		return
+	}
+	if err := s.queue.EnqueueSurveyNotificationDelivery(ctx, deliveryID); err != nil {
+		s.logger.Warn("Failed to queue survey notification delivery, it will be retried",
+			zap.String("deliveryId", deliveryID.String()), zap.Error(err))
+	}
+}
+
+// processInBackground attempts a delivery outside the request that dispatched it
+func (s *SurveyNotificationServiceImpl) processInBackground(deliveryID uuid.UUID) {
+	if err := s.ProcessDelivery(context.Background(), deliveryID); err != nil {
+		s.logger.Error("Failed to process survey notification delivery", zap.String("deliveryId", deliveryID.String()), zap.Error(err))
+	}
+}
+
+// attempt delivers through the delivery's channel and records the outcome. It reports whether the
+// delivery was sent; only failures to record the outcome are returned.
+func (s *SurveyNotificationServiceImpl) attempt(ctx context.Context, delivery *entities.SurveyNotificationDelivery) (bool, error) {
+	var deliveryErr error
+	if channel, ok := s.channels[delivery.Channel]; ok {
+		deliveryErr = channel.Deliver(ctx, delivery)
+	} else {
+		deliveryErr = fmt.Errorf("%s notifications are not configured", delivery.Channel)
+	}
+
+	now := time.Now()
+	delivery.RecordAttempt(deliveryErr, now, notificationRetryDelay, maxNotificationAttempts)
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		return false, err
+	}
+	if deliveryErr != nil {
+		s.logger.Warn("Failed to deliver survey notification",
+			zap.String("deliveryId", delivery.ID.String()),
+			zap.String("channel", delivery.Channel),
+			zap.Int("attempts", delivery.Attempts),
+			zap.String("status", delivery.Status),
+			zap.Error(deliveryErr))
+		return false, nil
+	}
+	if err := s.notificationRepo.RecordSent(ctx, delivery.NotificationID, now); err != nil {
+		s.logger.Warn("Failed to count sent survey notification",
+			zap.String("notificationId", delivery.NotificationID.String()), zap.Error(err))
+	}
+	return true, nil
+}
+
+// eventValues returns the placeholder values of an event
+func (s *SurveyNotificationServiceImpl) eventValues(ctx context.Context, event *services.SurveyNotificationEvent) (map[string]string, error) {
+	values := entities.SurveyNotificationValues(event.Survey.Questions, event.Answers)
+	values["survey"] = event.Survey.Title
+	values["surveyId"] = event.Survey.ID.String()
+	values["event"] = event.EventType
+
+	if event.Response != nil {
+		values["responseId"] = event.Response.ID.String()
+		submittedAt := time.Now()
+		if event.Response.SubmittedAt != nil {
+			submittedAt = *event.Response.SubmittedAt
+		}
+		values["submittedAt"] = submittedAt.Format("2006-01-02 15:04")
+	}
+	if event.Quota != nil {
+		values["quota"] = event.Quota.Name
+		values["quotaCount"] = strconv.Itoa(event.Quota.MaxResponses)
+	}
+	if event.EventType == entities.SurveyNotificationEventSurveyClosed {
+		completed := true
+		count, err := s.responseRepo.CountWithFilter(ctx, repositories.SurveyResponseFilter{SurveyID: event.Survey.ID, IsCompleted: &completed})
+		if err != nil {
+			return nil, err
+		}
+		values["responseCount"] = strconv.FormatInt(count, 10)
+	}
+	return values, nil
+}
+
+// buildPayload encodes the event data posted to webhooks
+func (s *SurveyNotificationServiceImpl) buildPayload(event *services.SurveyNotificationEvent, values map[string]string, subject, message string, now time.Time) ([]byte, error) {
+	payload := surveyNotificationPayload{
+		Event:       event.EventType,
+		SurveyID:    event.Survey.ID,
+		SurveyTitle: event.Survey.Title,
+		Subject:     subject,
+		Message:     message,
+		OccurredAt:  now,
+	}
+	if event.Response != nil {
+		payload.ResponseID = &event.Response.ID
+	}
+	if event.Quota != nil {
+		payload.QuotaID = &event.Quota.ID
+		payload.QuotaName = event.Quota.Name
+	}
+	for _, question := range event.Survey.Questions {
+		if value, ok := values[question.ID.String()]; ok {
+			if payload.Answers == nil {
+				payload.Answers = make(map[string]string)
+			}
+			payload.Answers[question.ID.String()] = value
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// validateNotification checks the notification, that its survey exists and that its rules read
+// questions of the survey
+func (s *SurveyNotificationServiceImpl) validateNotification(ctx context.Context, notification *entities.SurveyNotification) error {
+	if err := notification.Validate(); err != nil {
+		return err
+	}
+	survey, err := s.surveyRepo.FindByID(ctx, notification.SurveyID)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(notification.Conditions) == "" {
+		return nil
+	}
+
+	inSurvey := make(map[uuid.UUID]bool, len(survey.Questions))
+	for _, question := range survey.Questions {
+		inSurvey[question.ID] = true
+	}
+	conditions, _ := notification.GetConditions()
+	for _, rule := range conditions.Rules {
+		if !inSurvey[*rule.QuestionID] {
+			return fmt.Errorf("%w: question %s is not part of the survey", entities.ErrInvalidNotification, *rule.QuestionID)
+		}
+	}
+	return nil
+}
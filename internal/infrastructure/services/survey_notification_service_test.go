@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// memorySurveyNotificationRepository keeps notifications in memory
+type memorySurveyNotificationRepository struct {
+	surveys       *memorySurveyRepository
+	deliveries    *memorySurveyNotificationDeliveryRepository
+	notifications []*entities.SurveyNotification
+}
+
+func (r *memorySurveyNotificationRepository) Create(ctx context.Context, notification *entities.SurveyNotification) error {
+	copied := *notification
+	r.notifications = append(r.notifications, &copied)
+	return nil
+}
+
+func (r *memorySurveyNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyNotification, error) {
+	for _, notification := range r.notifications {
+		if notification.ID == id {
+			copied := *notification
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyNotificationRepository) Update(ctx context.Context, notification *entities.SurveyNotification) error {
+	for i, existing := range r.notifications {
+		if existing.ID == notification.ID {
+			copied := *notification
+			r.notifications[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	for i, notification := range r.notifications {
+		if notification.ID == id {
+			r.notifications = append(r.notifications[:i], r.notifications[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyNotificationRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyNotification, error) {
+	var notifications []entities.SurveyNotification
+	for _, notification := range r.notifications {
+		if notification.SurveyID == surveyID {
+			notifications = append(notifications, *notification)
+		}
+	}
+	return notifications, nil
+}
+
+func (r *memorySurveyNotificationRepository) FindActive(ctx context.Context, surveyID uuid.UUID, eventType string) ([]entities.SurveyNotification, error) {
+	var notifications []entities.SurveyNotification
+	for _, notification := range r.notifications {
+		if notification.SurveyID == surveyID && notification.EventType == eventType && notification.IsActive {
+			notifications = append(notifications, *notification)
+		}
+	}
+	return notifications, nil
+}
+
+func (r *memorySurveyNotificationRepository) FindUndispatchedClosed(ctx context.Context, now time.Time, limit int) ([]entities.SurveyNotification, error) {
+	var notifications []entities.SurveyNotification
+	for _, notification := range r.notifications {
+		survey, ok := r.surveys.surveys[notification.SurveyID]
+		if !ok || notification.EventType != entities.SurveyNotificationEventSurveyClosed || !notification.IsActive {
+			continue
+		}
+		ended := survey.Status == entities.SurveyStatusPublished && survey.EndDate != nil && !survey.EndDate.After(now)
+		if survey.Status != entities.SurveyStatusClosed && !ended {
+			continue
+		}
+		if dispatched, _ := r.deliveries.ExistsForNotification(ctx, notification.ID); !dispatched && len(notifications) < limit {
+			notifications = append(notifications, *notification)
+		}
+	}
+	return notifications, nil
+}
+
+func (r *memorySurveyNotificationRepository) RecordSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	for _, notification := range r.notifications {
+		if notification.ID == id {
+			notification.SendCount++
+			notification.LastSent = &sentAt
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+// memorySurveyNotificationDeliveryRepository keeps the delivery log in memory
+type memorySurveyNotificationDeliveryRepository struct {
+	deliveries []*entities.SurveyNotificationDelivery
+}
+
+func (r *memorySurveyNotificationDeliveryRepository) CreateBatch(ctx context.Context, deliveries []entities.SurveyNotificationDelivery) error {
+	for i := range deliveries {
+		copied := deliveries[i]
+		r.deliveries = append(r.deliveries, &copied)
+	}
+	return nil
+}
+
+func (r *memorySurveyNotificationDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyNotificationDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			copied := *delivery
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyNotificationDeliveryRepository) Update(ctx context.Context, delivery *entities.SurveyNotificationDelivery) error {
+	for i, existing := range r.deliveries {
+		if existing.ID == delivery.ID {
+			copied := *delivery
+			r.deliveries[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyNotificationDeliveryRepository) FindByNotificationID(ctx context.Context, notificationID uuid.UUID, limit int) ([]entities.SurveyNotificationDelivery, error) {
+	var deliveries []entities.SurveyNotificationDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.NotificationID == notificationID && len(deliveries) < limit {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memorySurveyNotificationDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]entities.SurveyNotificationDelivery, error) {
+	var deliveries []entities.SurveyNotificationDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == entities.SurveyNotificationDeliveryPending && delivery.NextAttemptAt != nil &&
+			!delivery.NextAttemptAt.After(now) && len(deliveries) < limit {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memorySurveyNotificationDeliveryRepository) ExistsForNotification(ctx context.Context, notificationID uuid.UUID) (bool, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.NotificationID == notificationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeSurveyNotificationQueue records the queued deliveries
+type fakeSurveyNotificationQueue struct {
+	queued []uuid.UUID
+}
+
+func (q *fakeSurveyNotificationQueue) EnqueueSurveyNotificationDelivery(ctx context.Context, deliveryID uuid.UUID) error {
+	q.queued = append(q.queued, deliveryID)
+	return nil
+}
+
+// newMemorySurveyNotificationService creates a notification service over in-memory repositories that
+// delivers email through the given sender
+func newMemorySurveyNotificationService(surveys *memorySurveyRepository, responses repositories.SurveyResponseRepository, sender *fakeEmailSender, queue *fakeSurveyNotificationQueue) (services.SurveyNotificationService, *memorySurveyNotificationRepository) {
+	deliveries := &memorySurveyNotificationDeliveryRepository{}
+	notifications := &memorySurveyNotificationRepository{surveys: surveys, deliveries: deliveries}
+	channels := NewSurveyNotificationChannels(sender, nil, "")
+	return NewSurveyNotificationService(notifications, deliveries, surveys, responses, channels, queue, zap.NewNop()), notifications
+}
+
+type SurveyNotificationServiceTestSuite struct {
+	suite.Suite
+	notifications *memorySurveyNotificationRepository
+	email         *fakeEmailSender
+	queue         *fakeSurveyNotificationQueue
+	service       services.SurveyNotificationService
+	survey        *entities.Survey
+	tier          uuid.UUID
+	comment       uuid.UUID
+	ctx           context.Context
+}
+
+func (suite *SurveyNotificationServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.tier, suite.comment = uuid.New(), uuid.New()
+	suite.survey = &entities.Survey{
+		ID:     uuid.New(),
+		Title:  "Gala dinner feedback",
+		Status: entities.SurveyStatusPublished,
+		Questions: []entities.SurveyQuestion{
+			{ID: suite.tier, Order: 1, QuestionText: "Ticket", QuestionType: entities.QuestionTypeText},
+			{ID: suite.comment, Order: 2, QuestionText: "Comment", QuestionType: entities.QuestionTypeText},
+		},
+	}
+	surveys := &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	suite.email = &fakeEmailSender{fail: map[string]bool{}}
+	suite.queue = &fakeSurveyNotificationQueue{}
+	suite.service, suite.notifications = newMemorySurveyNotificationService(surveys, &memorySurveyResponseRepository{answers: &memorySurveyAnswerRepository{}}, suite.email, suite.queue)
+}
+
+// createNotification creates an active notification of the survey
+func (suite *SurveyNotificationServiceTestSuite) createNotification(eventType, channel, conditions, message string, recipients ...string) *entities.SurveyNotification {
+	notification := &entities.SurveyNotification{
+		ID:         uuid.New(),
+		SurveyID:   suite.survey.ID,
+		EventType:  eventType,
+		Channel:    channel,
+		Recipients: recipients,
+		Conditions: conditions,
+		Message:    message,
+		IsActive:   true,
+	}
+	suite.Require().NoError(suite.service.CreateNotification(suite.ctx, notification))
+	return notification
+}
+
+// submit dispatches the response_submitted event of a response with the given answers
+func (suite *SurveyNotificationServiceTestSuite) submit(tier, comment string) *entities.SurveyResponse {
+	response := &entities.SurveyResponse{ID: uuid.New(), SurveyID: suite.survey.ID}
+	suite.Require().NoError(suite.service.Dispatch(suite.ctx, &services.SurveyNotificationEvent{
+		EventType: entities.SurveyNotificationEventResponseSubmitted,
+		Survey:    suite.survey,
+		Response:  response,
+		Answers: []entities.SurveyAnswer{
+			{QuestionID: suite.tier, AnswerText: tier},
+			{QuestionID: suite.comment, AnswerText: comment},
+		},
+	}))
+	return response
+}
+
+func (suite *SurveyNotificationServiceTestSuite) TestVIPResponsesAreNotified() {
+	notification := suite.createNotification(entities.SurveyNotificationEventResponseSubmitted, entities.SurveyNotificationChannelEmail,
+		fmt.Sprintf(`[{"questionId":"%s","operator":"equals","value":"VIP"}]`, suite.tier),
+		"{survey}: VIP guest said {Q2}", "host@example.com", "host@example.com", "vip-desk@example.com")
+
+	suite.submit("Standard", "Fine")
+	suite.Empty(suite.queue.queued)
+
+	response := suite.submit("VIP", "Cold soup")
+	suite.Require().Len(suite.queue.queued, 2, "duplicate recipients get one delivery")
+	for _, id := range suite.queue.queued {
+		suite.Require().NoError(suite.service.ProcessDelivery(suite.ctx, id))
+	}
+
+	suite.Require().Len(suite.email.sent, 2)
+	suite.Equal("host@example.com", suite.email.sent[0].To)
+	suite.Equal("New response to Gala dinner feedback", suite.email.sent[0].Subject)
+	suite.Equal("Gala dinner feedback: VIP guest said Cold soup", suite.email.sent[0].Body)
+
+	deliveries, err := suite.service.GetDeliveries(suite.ctx, notification.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(deliveries, 2)
+	suite.Equal(entities.SurveyNotificationDeliverySent, deliveries[0].Status)
+	suite.Equal(response.ID, *deliveries[0].ResponseID)
+	suite.Contains(deliveries[0].Payload, `"event":"response_submitted"`)
+
+	stored, err := suite.service.GetNotification(suite.ctx, notification.ID)
+	suite.Require().NoError(err)
+	suite.Equal(2, stored.SendCount)
+
+	// Processing a delivery again does not send it twice
+	suite.Require().NoError(suite.service.ProcessDelivery(suite.ctx, suite.queue.queued[0]))
+	suite.Len(suite.email.sent, 2)
+}
+
+func (suite *SurveyNotificationServiceTestSuite) TestFailedDeliveriesAreRetried() {
+	notification := suite.createNotification(entities.SurveyNotificationEventResponseSubmitted, entities.SurveyNotificationChannelEmail,
+		"{}", "", "host@example.com")
+	suite.email.fail["host@example.com"] = true
+
+	suite.submit("VIP", "Great")
+	suite.Require().Len(suite.queue.queued, 1)
+	suite.Require().NoError(suite.service.ProcessDelivery(suite.ctx, suite.queue.queued[0]))
+
+	deliveries, err := suite.service.GetDeliveries(suite.ctx, notification.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.SurveyNotificationDeliveryPending, deliveries[0].Status)
+	suite.Equal(1, deliveries[0].Attempts)
+	suite.Equal("mailbox unavailable", deliveries[0].LastError)
+
+	// Nothing is due before the backoff has passed
+	sent, err := suite.service.RetryDeliveries(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(0, sent)
+
+	past := time.Now().Add(-time.Second)
+	suite.notifications.deliveries.deliveries[0].NextAttemptAt = &past
+	delete(suite.email.fail, "host@example.com")
+	sent, err = suite.service.RetryDeliveries(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, sent)
+
+	deliveries, err = suite.service.GetDeliveries(suite.ctx, notification.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.SurveyNotificationDeliverySent, deliveries[0].Status)
+	suite.Equal(2, deliveries[0].Attempts)
+	suite.Empty(deliveries[0].LastError)
+}
+
+func (suite *SurveyNotificationServiceTestSuite) TestUnconfiguredChannelIsLoggedAsFailed() {
+	notification := suite.createNotification(entities.SurveyNotificationEventResponseSubmitted, entities.SurveyNotificationChannelWeChat,
+		"", "", "o-organizer")
+
+	suite.submit("VIP", "Great")
+	suite.Empty(suite.queue.queued)
+
+	deliveries, err := suite.service.GetDeliveries(suite.ctx, notification.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(deliveries, 1)
+	suite.Equal(entities.SurveyNotificationDeliveryFailed, deliveries[0].Status)
+	suite.Equal("wechat notifications are not configured", deliveries[0].LastError)
+}
+
+func (suite *SurveyNotificationServiceTestSuite) TestSurveyClosedIsNotifiedOnce() {
+	notification := suite.createNotification(entities.SurveyNotificationEventSurveyClosed, entities.SurveyNotificationChannelEmail,
+		"", "", "host@example.com")
+
+	notified, err := suite.service.NotifyClosedSurveys(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(0, notified)
+
+	suite.survey.Status = entities.SurveyStatusClosed
+	notified, err = suite.service.NotifyClosedSurveys(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, notified)
+	suite.Require().Len(suite.queue.queued, 1)
+	suite.Require().NoError(suite.service.ProcessDelivery(suite.ctx, suite.queue.queued[0]))
+	suite.Equal("Gala dinner feedback is closed with 0 responses.", suite.email.sent[0].Body)
+
+	// Neither the cron job nor a direct dispatch notifies it again
+	notified, err = suite.service.NotifyClosedSurveys(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(0, notified)
+	suite.Require().NoError(suite.service.Dispatch(suite.ctx, &services.SurveyNotificationEvent{
+		EventType: entities.SurveyNotificationEventSurveyClosed,
+		Survey:    suite.survey,
+	}))
+	deliveries, err := suite.service.GetDeliveries(suite.ctx, notification.ID)
+	suite.Require().NoError(err)
+	suite.Len(deliveries, 1)
+}
+
+func (suite *SurveyNotificationServiceTestSuite) TestInvalidNotificationsAreRejected() {
+	notification := &entities.SurveyNotification{
+		ID:         uuid.New(),
+		SurveyID:   suite.survey.ID,
+		EventType:  entities.SurveyNotificationEventResponseSubmitted,
+		Channel:    entities.SurveyNotificationChannelEmail,
+		Recipients: []string{"host@example.com"},
+		Conditions: fmt.Sprintf(`[{"questionId":"%s","operator":"equals","value":"VIP"}]`, uuid.New()),
+	}
+	suite.ErrorIs(suite.service.CreateNotification(suite.ctx, notification), entities.ErrInvalidNotification)
+
+	notification.Conditions = ""
+	notification.SurveyID = uuid.New()
+	suite.ErrorIs(suite.service.CreateNotification(suite.ctx, notification), repositories.ErrNotFound)
+}
+
+func TestSurveyNotificationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyNotificationServiceTestSuite))
+}
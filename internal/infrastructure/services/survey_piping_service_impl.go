@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyPipingServiceImpl implements the SurveyPipingService interface
+type SurveyPipingServiceImpl struct {
+	pipingRepo repositories.SurveyPipingRepository
+	logger     *zap.Logger
+}
+
+// NewSurveyPipingService creates a new survey piping service implementation
+func NewSurveyPipingService(pipingRepo repositories.SurveyPipingRepository, logger *zap.Logger) services.SurveyPipingService {
+	return &SurveyPipingServiceImpl{
+		pipingRepo: pipingRepo,
+		logger:     logger,
+	}
+}
+
+// CreatePiping validates and stores a piping rule
+func (s *SurveyPipingServiceImpl) CreatePiping(ctx context.Context, piping *entities.SurveyPiping) error {
+	if err := piping.Validate(); err != nil {
+		return err
+	}
+	if err := s.pipingRepo.Create(ctx, piping); err != nil {
+		return err
+	}
+
+	s.logger.Info("Survey piping created",
+		zap.String("surveyId", piping.SurveyID.String()),
+		zap.String("pipingId", piping.ID.String()),
+		zap.String("type", piping.PipeType))
+	return nil
+}
+
+// GetPiping retrieves a piping rule by ID
+func (s *SurveyPipingServiceImpl) GetPiping(ctx context.Context, id uuid.UUID) (*entities.SurveyPiping, error) {
+	return s.pipingRepo.FindByID(ctx, id)
+}
+
+// GetSurveyPiping retrieves every piping rule of a survey
+func (s *SurveyPipingServiceImpl) GetSurveyPiping(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyPiping, error) {
+	return s.pipingRepo.FindBySurveyID(ctx, surveyID)
+}
+
+// UpdatePiping validates and updates a piping rule
+func (s *SurveyPipingServiceImpl) UpdatePiping(ctx context.Context, piping *entities.SurveyPiping) error {
+	if err := piping.Validate(); err != nil {
+		return err
+	}
+	return s.pipingRepo.Update(ctx, piping)
+}
+
+// DeletePiping deletes a piping rule
+func (s *SurveyPipingServiceImpl) DeletePiping(ctx context.Context, id uuid.UUID) error {
+	return s.pipingRepo.Delete(ctx, id)
+}
+
+// PipeQuestions applies the survey's active piping rules to its questions
+func (s *SurveyPipingServiceImpl) PipeQuestions(ctx context.Context, surveyID uuid.UUID, questions []entities.PipingQuestion, answers map[uuid.UUID][]string) ([]entities.PipingQuestion, error) {
+	piping, err := s.pipingRepo.FindActiveBySurveyID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(piping) == 0 {
+		return questions, nil
+	}
+	return entities.ApplySurveyPiping(questions, piping, answers)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyQuotaServiceImpl implements the SurveyQuotaService interface
+type SurveyQuotaServiceImpl struct {
+	quotaRepo    repositories.SurveyQuotaRepository
+	questionRepo repositories.SurveyQuestionRepository
+	logger       *zap.Logger
+}
+
+// NewSurveyQuotaService creates a new survey quota service implementation
+func NewSurveyQuotaService(
+	quotaRepo repositories.SurveyQuotaRepository,
+	questionRepo repositories.SurveyQuestionRepository,
+	logger *zap.Logger,
+) services.SurveyQuotaService {
+	return &SurveyQuotaServiceImpl{
+		quotaRepo:    quotaRepo,
+		questionRepo: questionRepo,
+		logger:       logger,
+	}
+}
+
+// CreateQuota validates and stores a quota
+func (s *SurveyQuotaServiceImpl) CreateQuota(ctx context.Context, quota *entities.SurveyQuota) error {
+	if err := s.validateQuota(ctx, quota); err != nil {
+		return err
+	}
+	quota.CurrentCount = 0
+	if err := s.quotaRepo.Create(ctx, quota); err != nil {
+		return err
+	}
+
+	s.logger.Info("Survey quota created",
+		zap.String("surveyId", quota.SurveyID.String()),
+		zap.String("quotaId", quota.ID.String()),
+		zap.Int("maxResponses", quota.MaxResponses))
+	return nil
+}
+
+// GetQuota retrieves a quota by ID
+func (s *SurveyQuotaServiceImpl) GetQuota(ctx context.Context, id uuid.UUID) (*entities.SurveyQuota, error) {
+	return s.quotaRepo.FindByID(ctx, id)
+}
+
+// GetSurveyQuotas retrieves every quota of a survey
+func (s *SurveyQuotaServiceImpl) GetSurveyQuotas(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error) {
+	return s.quotaRepo.FindBySurveyID(ctx, surveyID)
+}
+
+// UpdateQuota validates and updates a quota's settings
+func (s *SurveyQuotaServiceImpl) UpdateQuota(ctx context.Context, quota *entities.SurveyQuota) error {
+	if err := s.validateQuota(ctx, quota); err != nil {
+		return err
+	}
+	return s.quotaRepo.Update(ctx, quota)
+}
+
+// DeleteQuota deletes a quota
+func (s *SurveyQuotaServiceImpl) DeleteQuota(ctx context.Context, id uuid.UUID) error {
+	return s.quotaRepo.Delete(ctx, id)
+}
+
+// ReserveQuotas increments every matching quota, releasing the ones already taken when one is full
+func (s *SurveyQuotaServiceImpl) ReserveQuotas(ctx context.Context, surveyID uuid.UUID, answers []entities.SurveyAnswer) ([]entities.SurveyQuota, error) {
+	quotas, err := s.quotaRepo.FindActiveBySurveyID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reserved []entities.SurveyQuota
+	for _, quota := range quotas {
+		matched, err := quota.Matches(answers)
+		if err != nil {
+			s.logger.Warn("Skipping survey quota with invalid conditions",
+				zap.String("quotaId", quota.ID.String()), zap.Error(err))
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		ok, err := s.quotaRepo.IncrementIfAvailable(ctx, quota.ID)
+		if err == nil && !ok {
+			err = &services.SurveyQuotaFullError{Quota: quota}
+		}
+		if err != nil {
+			if releaseErr := s.ReleaseQuotas(ctx, reserved); releaseErr != nil {
+				s.logger.Error("Failed to release survey quotas", zap.Error(releaseErr))
+			}
+			return nil, err
+		}
+		reserved = append(reserved, quota)
+	}
+	return reserved, nil
+}
+
+// ReleaseQuotas decrements the given quotas
+func (s *SurveyQuotaServiceImpl) ReleaseQuotas(ctx context.Context, quotas []entities.SurveyQuota) error {
+	for _, quota := range quotas {
+		if err := s.quotaRepo.Decrement(ctx, quota.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateQuota checks the quota and that its rules read questions of the survey
+func (s *SurveyQuotaServiceImpl) validateQuota(ctx context.Context, quota *entities.SurveyQuota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+
+	questions, err := s.questionRepo.FindBySurveyID(ctx, quota.SurveyID)
+	if err != nil {
+		return err
+	}
+	inSurvey := make(map[uuid.UUID]bool, len(questions))
+	for _, question := range questions {
+		inSurvey[question.ID] = true
+	}
+	conditions, _ := quota.GetConditions()
+	for _, rule := range conditions.Rules {
+		if !inSurvey[*rule.QuestionID] {
+			return fmt.Errorf("%w: question %s is not part of the survey", entities.ErrInvalidQuota, *rule.QuestionID)
+		}
+	}
+	return nil
+}
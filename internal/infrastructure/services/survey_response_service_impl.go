@@ -0,0 +1,680 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// defaultScreenOutMessage is shown when a full quota has no message of its own
+const defaultScreenOutMessage = "Thank you for your time. We already have enough responses from participants like you."
+
+// SurveyResponseServiceImpl implements the SurveyResponseService interface
+type SurveyResponseServiceImpl struct {
+	surveyRepo   repositories.SurveyRepository
+	responseRepo repositories.SurveyResponseRepository
+	sessionRepo  repositories.SurveySessionRepository
+	logicService services.SurveyLogicService
+	quotaService services.SurveyQuotaService
+	// invitationService tracks responses to invitation links; nil when invitations are not used
+	invitationService services.SurveyInvitationService
+	// notificationService notifies organizers of submissions; nil when notifications are not used
+	notificationService services.SurveyNotificationService
+	// collectorService attributes responses to collectors; nil when collectors are not used
+	collectorService services.SurveyCollectorService
+	// files stores the uploads of file questions in private storage; nil when uploads are not enabled
+	files  storage.StorageProvider
+	logger *zap.Logger
+}
+
+// surveyResponseMetadata is the JSON stored in SurveyResponse.Metadata
+type surveyResponseMetadata struct {
+	InvitationID *uuid.UUID `json:"invitationId,omitempty"` // Invitation whose link the response was started from
+}
+
+// NewSurveyResponseService creates a new survey response service implementation
+func NewSurveyResponseService(
+	surveyRepo repositories.SurveyRepository,
+	responseRepo repositories.SurveyResponseRepository,
+	sessionRepo repositories.SurveySessionRepository,
+	logicService services.SurveyLogicService,
+	quotaService services.SurveyQuotaService,
+	invitationService services.SurveyInvitationService,
+	notificationService services.SurveyNotificationService,
+	collectorService services.SurveyCollectorService,
+	files storage.StorageProvider,
+	logger *zap.Logger,
+) services.SurveyResponseService {
+	return &SurveyResponseServiceImpl{
+		surveyRepo:          surveyRepo,
+		responseRepo:        responseRepo,
+		sessionRepo:         sessionRepo,
+		logicService:        logicService,
+		quotaService:        quotaService,
+		invitationService:   invitationService,
+		notificationService: notificationService,
+		collectorService:    collectorService,
+		files:               files,
+		logger:              logger,
+	}
+}
+
+// StartResponse starts a response, or resumes the respondent's response in progress. Responses
+// started from an invitation link open the invitation, and anonymous ones are resumed through it.
+// Responses started from a collector are attributed to it and follow its settings.
+func (s *SurveyResponseServiceImpl) StartResponse(ctx context.Context, surveyID uuid.UUID, respondent services.SurveyRespondent) (*services.SurveyProgress, error) {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := checkSurveyOpen(survey, now); err != nil {
+		return nil, err
+	}
+	if survey.RequireLogin && respondent.RespondentID == nil {
+		return nil, services.ErrSurveyLoginRequired
+	}
+
+	metadata := surveyResponseMetadata{}
+	if respondent.InvitationToken != "" {
+		if s.invitationService == nil {
+			return nil, repositories.ErrNotFound
+		}
+		invitation, err := s.invitationService.OpenInvitation(ctx, respondent.InvitationToken)
+		if err != nil {
+			return nil, err
+		}
+		if invitation.SurveyID != survey.ID {
+			return nil, repositories.ErrNotFound
+		}
+		metadata.InvitationID = &invitation.ID
+		if respondent.RespondentID == nil && respondent.SessionID == "" {
+			respondent.SessionID = "invitation:" + invitation.ID.String()
+		}
+	}
+	if respondent.CollectorID != nil {
+		if s.collectorService == nil {
+			return nil, repositories.ErrNotFound
+		}
+		collector, err := s.collectorService.OpenCollector(ctx, survey.ID, *respondent.CollectorID)
+		if err != nil {
+			return nil, err
+		}
+		settings, err := collector.GetSettings()
+		if err != nil {
+			return nil, err
+		}
+		if settings.AllowMultiple != nil {
+			survey.AllowMultiple = *settings.AllowMultiple
+		}
+	}
+	encodedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resume the response in progress unless its time ran out
+	if response, session, err := s.findInProgress(ctx, survey.ID, respondent); err != nil {
+		return nil, err
+	} else if response != nil {
+		if expiresAt := timeLimitExpiry(survey, response); expiresAt == nil || now.Before(*expiresAt) {
+			response.MoveToSurveyVersion(survey)
+			return s.buildProgress(ctx, survey, response, session, response.Answers, 0)
+		}
+		if err := s.responseRepo.MarkAbandoned(ctx, response.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if !survey.AllowMultiple && (respondent.RespondentID != nil || respondent.SessionID != "") {
+		completed := true
+		filter := repositories.SurveyResponseFilter{SurveyID: survey.ID, IsCompleted: &completed}
+		if respondent.RespondentID != nil {
+			filter.RespondentID = respondent.RespondentID
+		} else {
+			filter.SessionID = respondent.SessionID
+		}
+		count, err := s.responseRepo.CountWithFilter(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, services.ErrSurveyAlreadyResponded
+		}
+
+		// Screened out respondents may not retry with different answers
+		filter.IsCompleted = nil
+		filter.Status = entities.ResponseStatusScreenedOut
+		if count, err = s.responseRepo.CountWithFilter(ctx, filter); err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, services.ErrSurveyAlreadyResponded
+		}
+	}
+	if err := s.checkCapacity(ctx, survey); err != nil {
+		return nil, err
+	}
+
+	token := uuid.New().String()
+	response := &entities.SurveyResponse{
+		ID:            uuid.New(),
+		SurveyID:      survey.ID,
+		RespondentID:  respondent.RespondentID,
+		SessionID:     respondent.SessionID,
+		CollectorID:   respondent.CollectorID,
+		SurveyVersion: survey.Version,
+		Status:        entities.ResponseStatusInProgress,
+		StartedAt:     now,
+		IPAddress:     respondent.IPAddress,
+		UserAgent:     respondent.UserAgent,
+		Metadata:      string(encodedMetadata),
+	}
+	if response.SessionID == "" {
+		response.SessionID = token
+	}
+	if err := s.responseRepo.Create(ctx, response); err != nil {
+		return nil, err
+	}
+
+	session := &entities.SurveySession{
+		ID:           uuid.New(),
+		SurveyID:     survey.ID,
+		SessionID:    token,
+		ResponseID:   &response.ID,
+		CurrentPage:  1,
+		TotalPages:   1,
+		LastActivity: now,
+		IPAddress:    respondent.IPAddress,
+		UserAgent:    respondent.UserAgent,
+		Metadata:     "{}",
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Survey response started",
+		zap.String("surveyId", survey.ID.String()),
+		zap.String("responseId", response.ID.String()))
+
+	return s.buildProgress(ctx, survey, response, session, nil, 0)
+}
+
+// GetProgress returns the state of a session so it can be resumed
+func (s *SurveyResponseServiceImpl) GetProgress(ctx context.Context, sessionID string) (*services.SurveyProgress, error) {
+	session, response, survey, err := s.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildProgress(ctx, survey, response, session, response.Answers, 0)
+}
+
+// SaveProgress validates and stores partial answers. Required questions are only enforced on submit.
+func (s *SurveyResponseServiceImpl) SaveProgress(ctx context.Context, sessionID string, answers []entities.SurveyAnswer, currentPage int) (*services.SurveyProgress, error) {
+	session, response, survey, err := s.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAnswerable(ctx, survey, response); err != nil {
+		return nil, err
+	}
+
+	questionByID := make(map[uuid.UUID]*entities.SurveyQuestion, len(survey.Questions))
+	for i := range survey.Questions {
+		questionByID[survey.Questions[i].ID] = &survey.Questions[i]
+	}
+	validationErrors := make(map[uuid.UUID]string)
+	for _, answer := range answers {
+		question := questionByID[answer.QuestionID]
+		if question == nil {
+			validationErrors[answer.QuestionID] = "question is not part of this survey"
+			continue
+		}
+		if answer.HasValue() {
+			if err := answer.ValidateByType(question); err != nil {
+				validationErrors[answer.QuestionID] = err.Error()
+			}
+		}
+	}
+	if len(validationErrors) > 0 {
+		return nil, &services.SurveyResponseValidationError{Errors: validationErrors}
+	}
+
+	merged := mergeSurveyAnswers(response.Answers, answers)
+	if err := s.storeResponse(ctx, response, merged, 0); err != nil {
+		return nil, err
+	}
+	return s.buildProgress(ctx, survey, response, session, merged, currentPage)
+}
+
+// SubmitResponse applies the survey logic to the saved and submitted answers, validates them and
+// submits the response. Responses falling into a full quota are stored as screened out.
+func (s *SurveyResponseServiceImpl) SubmitResponse(ctx context.Context, sessionID string, answers []entities.SurveyAnswer) (*services.SurveyProgress, error) {
+	session, response, survey, err := s.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAnswerable(ctx, survey, response); err != nil {
+		return nil, err
+	}
+
+	prepared, _, err := s.logicService.ApplyLogic(ctx, survey.ID, mergeSurveyAnswers(response.Answers, answers))
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := s.quotaService.ReserveQuotas(ctx, survey.ID, prepared)
+	var quotaErr *services.SurveyQuotaFullError
+	if errors.As(err, &quotaErr) {
+		return s.screenOut(ctx, survey, response, session, prepared, &quotaErr.Quota)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reserveCollector(ctx, response); err != nil {
+		s.releaseQuotas(ctx, reserved)
+		return nil, err
+	}
+
+	now := time.Now()
+	timeSpent := int(now.Sub(response.StartedAt).Seconds())
+	response.Status = entities.ResponseStatusSubmitted
+	response.CompletedAt = &now
+	response.SubmittedAt = &now
+	response.TimeSpent = &timeSpent
+	// The response limit is checked while storing, under a lock on the survey
+	maxCompleted := 0
+	if survey.MaxResponses != nil {
+		maxCompleted = *survey.MaxResponses
+	}
+	if err := s.storeResponse(ctx, response, prepared, maxCompleted); err != nil {
+		s.releaseQuotas(ctx, reserved)
+		s.releaseCollector(ctx, response)
+		return nil, err
+	}
+	s.recordInvitationResponse(ctx, response)
+	s.notifySubmitted(ctx, survey, response, prepared, reserved)
+
+	s.logger.Info("Survey response submitted",
+		zap.String("surveyId", survey.ID.String()),
+		zap.String("responseId", response.ID.String()),
+		zap.Int("timeSpent", timeSpent))
+
+	return s.buildProgress(ctx, survey, response, session, prepared, 0)
+}
+
+// UploadFile checks an upload against the limits of its file question and stores it under the
+// question's prefix, which answer validation requires of the files an answer lists
+func (s *SurveyResponseServiceImpl) UploadFile(ctx context.Context, sessionID string, questionID uuid.UUID, upload *services.SurveyFileUpload) (*entities.SurveyUploadedFile, error) {
+	if s.files == nil {
+		return nil, fmt.Errorf("%w: file uploads are not enabled", entities.ErrInvalidSurveyFile)
+	}
+	_, response, survey, err := s.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAnswerable(ctx, survey, response); err != nil {
+		return nil, err
+	}
+
+	var question *entities.SurveyQuestion
+	for i := range survey.Questions {
+		if survey.Questions[i].ID == questionID {
+			question = &survey.Questions[i]
+		}
+	}
+	if question == nil || question.QuestionType != entities.QuestionTypeFile {
+		return nil, fmt.Errorf("%w: question does not accept files", entities.ErrInvalidSurveyFile)
+	}
+	settings, err := question.GetFileSettings()
+	if err != nil {
+		return nil, err
+	}
+	name := path.Base(strings.ReplaceAll(strings.TrimSpace(upload.Name), "\\", "/"))
+	if err := settings.CheckFile(name, upload.ContentType, upload.Size); err != nil {
+		return nil, err
+	}
+
+	// Uploads are anonymous, so the file limit is enforced on the stored files and not only on answers
+	prefix := entities.SurveyFileKeyPrefix(survey.ID, question.ID) + response.ID.String() + "/"
+	stored, err := s.files.ListFiles(ctx, prefix, settings.MaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) >= settings.MaxFiles {
+		return nil, fmt.Errorf("%w: at most %d files can be uploaded", entities.ErrInvalidSurveyFile, settings.MaxFiles)
+	}
+
+	file := &entities.SurveyUploadedFile{
+		Key:         prefix + uuid.New().String() + strings.ToLower(path.Ext(name)),
+		Name:        name,
+		Size:        upload.Size,
+		ContentType: upload.ContentType,
+	}
+	if _, err := s.files.Upload(ctx, file.Key, io.LimitReader(upload.Content, upload.Size), upload.Size); err != nil {
+		return nil, err
+	}
+	file.URL = surveyFileURL(file.Key)
+
+	s.logger.Info("Survey file uploaded",
+		zap.String("surveyId", survey.ID.String()),
+		zap.String("responseId", response.ID.String()),
+		zap.String("key", file.Key),
+		zap.Int64("size", file.Size))
+	return file, nil
+}
+
+// screenOut stores a response rejected by a full quota
+func (s *SurveyResponseServiceImpl) screenOut(ctx context.Context, survey *entities.Survey, response *entities.SurveyResponse, session *entities.SurveySession, answers []entities.SurveyAnswer, quota *entities.SurveyQuota) (*services.SurveyProgress, error) {
+	now := time.Now()
+	timeSpent := int(now.Sub(response.StartedAt).Seconds())
+	response.Status = entities.ResponseStatusScreenedOut
+	response.CompletedAt = &now
+	response.TimeSpent = &timeSpent
+	if err := s.storeResponse(ctx, response, answers, 0); err != nil {
+		return nil, err
+	}
+	s.recordInvitationResponse(ctx, response)
+
+	s.logger.Info("Survey response screened out by quota",
+		zap.String("surveyId", survey.ID.String()),
+		zap.String("responseId", response.ID.String()),
+		zap.String("quotaId", quota.ID.String()))
+
+	progress, err := s.buildProgress(ctx, survey, response, session, answers, 0)
+	if err != nil {
+		return nil, err
+	}
+	progress.ScreenOutMessage = quota.ScreenOutMessage
+	if progress.ScreenOutMessage == "" {
+		progress.ScreenOutMessage = defaultScreenOutMessage
+	}
+	return progress, nil
+}
+
+// reserveCollector counts a submitted response against the collector it was started from
+func (s *SurveyResponseServiceImpl) reserveCollector(ctx context.Context, response *entities.SurveyResponse) error {
+	if response.CollectorID == nil || s.collectorService == nil {
+		return nil
+	}
+	return s.collectorService.ReserveResponse(ctx, *response.CollectorID)
+}
+
+// releaseCollector gives back the collector response counted by reserveCollector
+func (s *SurveyResponseServiceImpl) releaseCollector(ctx context.Context, response *entities.SurveyResponse) {
+	if response.CollectorID == nil || s.collectorService == nil {
+		return
+	}
+	if err := s.collectorService.ReleaseResponse(ctx, *response.CollectorID); err != nil {
+		s.logger.Error("Failed to release survey collector response", zap.Error(err))
+	}
+}
+
+// releaseQuotas gives back the quota places reserved for a response that was not stored
+func (s *SurveyResponseServiceImpl) releaseQuotas(ctx context.Context, reserved []entities.SurveyQuota) {
+	if err := s.quotaService.ReleaseQuotas(ctx, reserved); err != nil {
+		s.logger.Error("Failed to release survey quotas", zap.Error(err))
+	}
+}
+
+// recordInvitationResponse marks the invitation a finished response was started from as responded
+func (s *SurveyResponseServiceImpl) recordInvitationResponse(ctx context.Context, response *entities.SurveyResponse) {
+	if s.invitationService == nil {
+		return
+	}
+	var metadata surveyResponseMetadata
+	if err := json.Unmarshal([]byte(response.Metadata), &metadata); err != nil || metadata.InvitationID == nil {
+		return
+	}
+	if err := s.invitationService.MarkResponded(ctx, *metadata.InvitationID, response.ID); err != nil {
+		s.logger.Warn("Failed to record survey invitation response",
+			zap.String("invitationId", metadata.InvitationID.String()),
+			zap.String("responseId", response.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// notifySubmitted dispatches the notifications of a submitted response: the submission, the quotas
+// it filled and the survey closing when it filled the survey. Failures do not fail the submission.
+func (s *SurveyResponseServiceImpl) notifySubmitted(ctx context.Context, survey *entities.Survey, response *entities.SurveyResponse, answers []entities.SurveyAnswer, reserved []entities.SurveyQuota) {
+	if s.notificationService == nil {
+		return
+	}
+	events := []*services.SurveyNotificationEvent{{
+		EventType: entities.SurveyNotificationEventResponseSubmitted,
+		Survey:    survey,
+		Response:  response,
+		Answers:   answers,
+	}}
+	for i := range reserved {
+		// The reserved quotas hold their count from before this response
+		if reserved[i].CurrentCount+1 >= reserved[i].MaxResponses {
+			events = append(events, &services.SurveyNotificationEvent{
+				EventType: entities.SurveyNotificationEventQuotaReached,
+				Survey:    survey,
+				Response:  response,
+				Answers:   answers,
+				Quota:     &reserved[i],
+			})
+		}
+	}
+	if err := s.checkCapacity(ctx, survey); errors.Is(err, services.ErrSurveyFull) {
+		events = append(events, &services.SurveyNotificationEvent{EventType: entities.SurveyNotificationEventSurveyClosed, Survey: survey})
+	}
+
+	for _, event := range events {
+		if err := s.notificationService.Dispatch(ctx, event); err != nil {
+			s.logger.Warn("Failed to dispatch survey notifications",
+				zap.String("surveyId", survey.ID.String()),
+				zap.String("responseId", response.ID.String()),
+				zap.String("eventType", event.EventType),
+				zap.Error(err))
+		}
+	}
+}
+
+// findInProgress finds the respondent's response in progress and its session
+func (s *SurveyResponseServiceImpl) findInProgress(ctx context.Context, surveyID uuid.UUID, respondent services.SurveyRespondent) (*entities.SurveyResponse, *entities.SurveySession, error) {
+	filter := repositories.SurveyResponseFilter{
+		SurveyID: surveyID,
+		Status:   entities.ResponseStatusInProgress,
+		Limit:    1,
+	}
+	switch {
+	case respondent.RespondentID != nil:
+		filter.RespondentID = respondent.RespondentID
+	case respondent.SessionID != "":
+		filter.SessionID = respondent.SessionID
+	default:
+		return nil, nil, nil
+	}
+
+	responses, err := s.responseRepo.FindWithFilter(ctx, filter)
+	if err != nil || len(responses) == 0 {
+		return nil, nil, err
+	}
+	session, err := s.sessionRepo.FindByResponseID(ctx, responses[0].ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &responses[0], session, nil
+}
+
+// loadSession loads a session with its response and survey
+func (s *SurveyResponseServiceImpl) loadSession(ctx context.Context, sessionID string) (*entities.SurveySession, *entities.SurveyResponse, *entities.Survey, error) {
+	session, err := s.sessionRepo.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if session.ResponseID == nil {
+		return nil, nil, nil, repositories.ErrNotFound
+	}
+	response, err := s.responseRepo.FindByID(ctx, *session.ResponseID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	survey, err := s.surveyRepo.FindByID(ctx, session.SurveyID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Responses started before the survey was revised continue under the current version
+	if response.Status == entities.ResponseStatusInProgress {
+		response.MoveToSurveyVersion(survey)
+	}
+	return session, response, survey, nil
+}
+
+// checkAnswerable checks that a response can still be changed, abandoning it when its time ran out
+func (s *SurveyResponseServiceImpl) checkAnswerable(ctx context.Context, survey *entities.Survey, response *entities.SurveyResponse) error {
+	if response.Status != entities.ResponseStatusInProgress {
+		return services.ErrSurveyResponseSubmitted
+	}
+	now := time.Now()
+	if expiresAt := timeLimitExpiry(survey, response); expiresAt != nil && now.After(*expiresAt) {
+		if err := s.responseRepo.MarkAbandoned(ctx, response.ID); err != nil {
+			return err
+		}
+		return services.ErrSurveyTimeLimitExceeded
+	}
+	return checkSurveyOpen(survey, now)
+}
+
+// checkCapacity checks the survey has not reached its maximum number of responses
+func (s *SurveyResponseServiceImpl) checkCapacity(ctx context.Context, survey *entities.Survey) error {
+	if survey.MaxResponses == nil {
+		return nil
+	}
+	completed := true
+	count, err := s.responseRepo.CountWithFilter(ctx, repositories.SurveyResponseFilter{SurveyID: survey.ID, IsCompleted: &completed})
+	if err != nil {
+		return err
+	}
+	if count >= int64(*survey.MaxResponses) {
+		return services.ErrSurveyFull
+	}
+	return nil
+}
+
+// storeResponse replaces the answers of a response and saves it in one transaction. A positive
+// maxCompleted is the number of completed responses the survey may hold once this one is stored.
+func (s *SurveyResponseServiceImpl) storeResponse(ctx context.Context, response *entities.SurveyResponse, answers []entities.SurveyAnswer, maxCompleted int) error {
+	for i := range answers {
+		answers[i].ID = uuid.New()
+		answers[i].ResponseID = response.ID
+		answers[i].Response = nil
+		answers[i].Question = nil
+	}
+	record := *response
+	record.Survey = nil
+	record.Answers = nil
+	err := s.responseRepo.SaveWithAnswers(ctx, &record, answers, maxCompleted)
+	if errors.Is(err, repositories.ErrLimitReached) {
+		return services.ErrSurveyFull
+	}
+	return err
+}
+
+// buildProgress evaluates the navigation of the answers and records the session's pages and progress
+func (s *SurveyResponseServiceImpl) buildProgress(ctx context.Context, survey *entities.Survey, response *entities.SurveyResponse, session *entities.SurveySession, answers []entities.SurveyAnswer, currentPage int) (*services.SurveyProgress, error) {
+	navigation, err := s.logicService.Evaluate(ctx, survey.ID, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	answered := make(map[uuid.UUID]bool, len(answers))
+	for _, answer := range answers {
+		if answer.HasValue() {
+			answered[answer.QuestionID] = true
+		}
+	}
+	answeredVisible := 0
+	for _, id := range navigation.VisibleQuestionIDs {
+		if answered[id] {
+			answeredVisible++
+		}
+	}
+
+	session.TotalPages = len(navigation.Pages)
+	if session.TotalPages == 0 {
+		session.TotalPages = 1
+	}
+	finished := response.IsCompleted() || response.Status == entities.ResponseStatusScreenedOut
+	switch {
+	case finished:
+		session.Progress = 100
+		session.CurrentPage = session.TotalPages
+	case currentPage > 0:
+		session.CurrentPage = currentPage
+	}
+	if !finished {
+		session.Progress = percentage(int64(answeredVisible), int64(len(navigation.VisibleQuestionIDs)))
+	}
+	session.LastActivity = time.Now()
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &services.SurveyProgress{
+		Session:    session,
+		Response:   response,
+		Answers:    answers,
+		Navigation: navigation,
+		ExpiresAt:  timeLimitExpiry(survey, response),
+	}, nil
+}
+
+// checkSurveyOpen checks the survey is published and within its start and end dates
+func checkSurveyOpen(survey *entities.Survey, now time.Time) error {
+	switch {
+	case survey.Status == entities.SurveyStatusClosed || survey.Status == entities.SurveyStatusArchived:
+		return services.ErrSurveyClosed
+	case survey.Status != entities.SurveyStatusPublished:
+		return services.ErrSurveyNotOpen
+	case survey.StartDate != nil && now.Before(*survey.StartDate):
+		return services.ErrSurveyNotOpen
+	case survey.EndDate != nil && now.After(*survey.EndDate):
+		return services.ErrSurveyClosed
+	}
+	return nil
+}
+
+// timeLimitExpiry returns when the time limit of a response runs out, nil without a limit
+func timeLimitExpiry(survey *entities.Survey, response *entities.SurveyResponse) *time.Time {
+	if survey.TimeLimit == nil || *survey.TimeLimit <= 0 {
+		return nil
+	}
+	expiresAt := response.StartedAt.Add(time.Duration(*survey.TimeLimit) * time.Minute)
+	return &expiresAt
+}
+
+// mergeSurveyAnswers overlays new answers on the saved ones, keyed by question
+func mergeSurveyAnswers(saved, updates []entities.SurveyAnswer) []entities.SurveyAnswer {
+	merged := make([]entities.SurveyAnswer, 0, len(saved)+len(updates))
+	position := make(map[uuid.UUID]int, len(saved)+len(updates))
+	for _, answer := range append(append([]entities.SurveyAnswer{}, saved...), updates...) {
+		answer.Question = nil
+		answer.Response = nil
+		if i, ok := position[answer.QuestionID]; ok {
+			merged[i] = answer
+			continue
+		}
+		position[answer.QuestionID] = len(merged)
+		merged = append(merged, answer)
+	}
+	return merged
+}
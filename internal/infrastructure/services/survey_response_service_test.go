@@ -0,0 +1,634 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// memorySurveyRepository serves surveys from memory
+type memorySurveyRepository struct {
+	repositories.SurveyRepository
+	surveys map[uuid.UUID]*entities.Survey
+}
+
+func (r *memorySurveyRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Survey, error) {
+	survey, ok := r.surveys[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	copied := *survey
+	return &copied, nil
+}
+
+func (r *memorySurveyRepository) Create(ctx context.Context, survey *entities.Survey) error {
+	r.surveys[survey.ID] = survey
+	return nil
+}
+
+func (r *memorySurveyRepository) Update(ctx context.Context, survey *entities.Survey) error {
+	stored, ok := r.surveys[survey.ID]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	copied := *survey
+	copied.Questions = stored.Questions
+	r.surveys[survey.ID] = &copied
+	return nil
+}
+
+func (r *memorySurveyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.surveys, id)
+	return nil
+}
+
+// memorySurveyQuestionRepository serves the questions of the surveys in memory
+type memorySurveyQuestionRepository struct {
+	repositories.SurveyQuestionRepository
+	surveys *memorySurveyRepository
+}
+
+func (r *memorySurveyQuestionRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuestion, error) {
+	return append([]entities.SurveyQuestion{}, r.surveys.surveys[surveyID].Questions...), nil
+}
+
+// memorySurveyLogicRepository keeps logic rules in memory
+type memorySurveyLogicRepository struct {
+	repositories.SurveyLogicRepository
+	logic []entities.SurveyLogic
+}
+
+func (r *memorySurveyLogicRepository) Create(ctx context.Context, logic *entities.SurveyLogic) error {
+	r.logic = append(r.logic, *logic)
+	return nil
+}
+
+func (r *memorySurveyLogicRepository) FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyLogic, error) {
+	var logic []entities.SurveyLogic
+	for _, rule := range r.logic {
+		if rule.SurveyID == surveyID && rule.IsActive {
+			logic = append(logic, rule)
+		}
+	}
+	return logic, nil
+}
+
+// memorySurveyResponseRepository keeps responses and their answers in memory
+type memorySurveyResponseRepository struct {
+	repositories.SurveyResponseRepository
+	responses []*entities.SurveyResponse
+	answers   *memorySurveyAnswerRepository
+}
+
+func (r *memorySurveyResponseRepository) Create(ctx context.Context, response *entities.SurveyResponse) error {
+	copied := *response
+	r.responses = append(r.responses, &copied)
+	return nil
+}
+
+func (r *memorySurveyResponseRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyResponse, error) {
+	for _, response := range r.responses {
+		if response.ID == id {
+			copied := *response
+			copied.Answers = r.answers.byResponse(id)
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveyResponseRepository) Update(ctx context.Context, response *entities.SurveyResponse) error {
+	for i, existing := range r.responses {
+		if existing.ID == response.ID {
+			copied := *response
+			r.responses[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memorySurveyResponseRepository) SaveWithAnswers(ctx context.Context, response *entities.SurveyResponse, answers []entities.SurveyAnswer, maxCompleted int) error {
+	if maxCompleted > 0 {
+		completed := true
+		count, _ := r.CountWithFilter(ctx, repositories.SurveyResponseFilter{SurveyID: response.SurveyID, IsCompleted: &completed})
+		if count >= int64(maxCompleted) {
+			return repositories.ErrLimitReached
+		}
+	}
+	if err := r.answers.DeleteByResponseID(ctx, response.ID); err != nil {
+		return err
+	}
+	if err := r.answers.BulkCreate(ctx, answers); err != nil {
+		return err
+	}
+	return r.Update(ctx, response)
+}
+
+func (r *memorySurveyResponseRepository) matching(filter repositories.SurveyResponseFilter) []entities.SurveyResponse {
+	var responses []entities.SurveyResponse
+	for _, response := range r.responses {
+		if response.SurveyID != filter.SurveyID ||
+			(filter.RespondentID != nil && (response.RespondentID == nil || *response.RespondentID != *filter.RespondentID)) ||
+			(filter.SessionID != "" && response.SessionID != filter.SessionID) ||
+			(filter.CollectorID != nil && (response.CollectorID == nil || *response.CollectorID != *filter.CollectorID)) ||
+			(filter.Status != "" && response.Status != filter.Status) ||
+			(filter.IsCompleted != nil && response.IsCompleted() != *filter.IsCompleted) {
+			continue
+		}
+		copied := *response
+		copied.Answers = r.answers.byResponse(response.ID)
+		responses = append(responses, copied)
+	}
+	return responses
+}
+
+func (r *memorySurveyResponseRepository) FindWithFilter(ctx context.Context, filter repositories.SurveyResponseFilter) ([]entities.SurveyResponse, error) {
+	return r.matching(filter), nil
+}
+
+func (r *memorySurveyResponseRepository) CountWithFilter(ctx context.Context, filter repositories.SurveyResponseFilter) (int64, error) {
+	return int64(len(r.matching(filter))), nil
+}
+
+func (r *memorySurveyResponseRepository) FindInBatches(ctx context.Context, filter repositories.SurveyResponseFilter, batchSize int, fn func(responses []entities.SurveyResponse) error) error {
+	responses := r.matching(filter)
+	for start := 0; start < len(responses); start += batchSize {
+		end := start + batchSize
+		if end > len(responses) {
+			end = len(responses)
+		}
+		if err := fn(responses[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memorySurveyResponseRepository) MarkAbandoned(ctx context.Context, id uuid.UUID) error {
+	for _, response := range r.responses {
+		if response.ID == id {
+			response.Status = entities.ResponseStatusAbandoned
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+// memorySurveyAnswerRepository keeps answers in memory
+type memorySurveyAnswerRepository struct {
+	repositories.SurveyAnswerRepository
+	answers []entities.SurveyAnswer
+}
+
+func (r *memorySurveyAnswerRepository) byResponse(responseID uuid.UUID) []entities.SurveyAnswer {
+	var answers []entities.SurveyAnswer
+	for _, answer := range r.answers {
+		if answer.ResponseID == responseID {
+			answers = append(answers, answer)
+		}
+	}
+	return answers
+}
+
+func (r *memorySurveyAnswerRepository) DeleteByResponseID(ctx context.Context, responseID uuid.UUID) error {
+	kept := r.answers[:0]
+	for _, answer := range r.answers {
+		if answer.ResponseID != responseID {
+			kept = append(kept, answer)
+		}
+	}
+	r.answers = kept
+	return nil
+}
+
+func (r *memorySurveyAnswerRepository) BulkCreate(ctx context.Context, answers []entities.SurveyAnswer) error {
+	r.answers = append(r.answers, answers...)
+	return nil
+}
+
+// memorySurveySessionRepository keeps sessions in memory
+type memorySurveySessionRepository struct {
+	sessions []*entities.SurveySession
+}
+
+func (r *memorySurveySessionRepository) Create(ctx context.Context, session *entities.SurveySession) error {
+	copied := *session
+	r.sessions = append(r.sessions, &copied)
+	return nil
+}
+
+func (r *memorySurveySessionRepository) FindBySessionID(ctx context.Context, sessionID string) (*entities.SurveySession, error) {
+	for _, session := range r.sessions {
+		if session.SessionID == sessionID {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveySessionRepository) FindByResponseID(ctx context.Context, responseID uuid.UUID) (*entities.SurveySession, error) {
+	for _, session := range r.sessions {
+		if session.ResponseID != nil && *session.ResponseID == responseID {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memorySurveySessionRepository) Update(ctx context.Context, session *entities.SurveySession) error {
+	for i, existing := range r.sessions {
+		if existing.ID == session.ID {
+			copied := *session
+			r.sessions[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+// memorySurveyQuotaRepository keeps quotas in memory
+type memorySurveyQuotaRepository struct {
+	repositories.SurveyQuotaRepository
+	quotas []*entities.SurveyQuota
+}
+
+func (r *memorySurveyQuotaRepository) FindActiveBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyQuota, error) {
+	var quotas []entities.SurveyQuota
+	for _, quota := range r.quotas {
+		if quota.SurveyID == surveyID && quota.IsActive {
+			quotas = append(quotas, *quota)
+		}
+	}
+	return quotas, nil
+}
+
+func (r *memorySurveyQuotaRepository) IncrementIfAvailable(ctx context.Context, id uuid.UUID) (bool, error) {
+	for _, quota := range r.quotas {
+		if quota.ID == id {
+			if quota.IsQuotaFull() {
+				return false, nil
+			}
+			quota.CurrentCount++
+			return true, nil
+		}
+	}
+	return false, repositories.ErrNotFound
+}
+
+func (r *memorySurveyQuotaRepository) Decrement(ctx context.Context, id uuid.UUID) error {
+	for _, quota := range r.quotas {
+		if quota.ID == id && quota.CurrentCount > 0 {
+			quota.CurrentCount--
+		}
+	}
+	return nil
+}
+
+type SurveyResponseServiceTestSuite struct {
+	suite.Suite
+	surveys       *memorySurveyRepository
+	responses     *memorySurveyResponseRepository
+	quotas        *memorySurveyQuotaRepository
+	collectors    *memorySurveyCollectorRepository
+	invitations   services.SurveyInvitationService
+	notifications services.SurveyNotificationService
+	email         *fakeEmailSender
+	queue         *fakeSurveyNotificationQueue
+	service       services.SurveyResponseService
+	survey        *entities.Survey
+	rating        uuid.UUID
+	comment       uuid.UUID
+	ctx           context.Context
+}
+
+func (suite *SurveyResponseServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.rating, suite.comment = uuid.New(), uuid.New()
+	suite.survey = &entities.Survey{
+		ID:     uuid.New(),
+		Title:  "Event feedback",
+		Status: entities.SurveyStatusPublished,
+		Questions: []entities.SurveyQuestion{
+			{ID: suite.rating, Order: 1, QuestionType: entities.QuestionTypeRating, IsRequired: true},
+			{ID: suite.comment, Order: 2, QuestionType: entities.QuestionTypeText, IsRequired: true, Metadata: `{"page":2}`},
+		},
+	}
+
+	suite.surveys = &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	answers := &memorySurveyAnswerRepository{}
+	suite.responses = &memorySurveyResponseRepository{answers: answers}
+	logger := zap.NewNop()
+	logicService := NewSurveyLogicService(&memorySurveyLogicRepository{}, &memorySurveyQuestionRepository{surveys: suite.surveys}, logger)
+	suite.quotas = &memorySurveyQuotaRepository{}
+	quotaService := NewSurveyQuotaService(suite.quotas, &memorySurveyQuestionRepository{surveys: suite.surveys}, logger)
+	suite.email = &fakeEmailSender{fail: map[string]bool{}}
+	suite.queue = &fakeSurveyNotificationQueue{}
+	suite.notifications, _ = newMemorySurveyNotificationService(suite.surveys, suite.responses, suite.email, suite.queue)
+	suite.invitations = NewSurveyInvitationService(&memorySurveyInvitationRepository{}, suite.surveys, &memoryWeChatUserRepository{}, nil,
+		&fakeEmailSender{}, DefaultSurveyInvitationConfig("https://events.example.com", ""), logger)
+	suite.collectors = &memorySurveyCollectorRepository{}
+	collectorService := NewSurveyCollectorService(suite.collectors, suite.surveys, nil, "https://events.example.com", logger)
+	suite.service = NewSurveyResponseService(suite.surveys, suite.responses, &memorySurveySessionRepository{}, logicService, quotaService,
+		suite.invitations, suite.notifications, collectorService, storage.NewLocalStorage(suite.T().TempDir(), ""), logger)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestSaveResumeAndSubmit() {
+	respondent := services.SurveyRespondent{SessionID: "wechat-browser"}
+	progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, respondent)
+	suite.Require().NoError(err)
+	suite.Equal(2, progress.Session.TotalPages)
+
+	rating := 4.0
+	progress, err = suite.service.SaveProgress(suite.ctx, progress.Session.SessionID,
+		[]entities.SurveyAnswer{{QuestionID: suite.rating, AnswerNumber: &rating}}, 2)
+	suite.Require().NoError(err)
+	suite.InDelta(50, progress.Session.Progress, 0.001)
+
+	// Starting again from the same browser resumes the saved answers
+	resumed, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, respondent)
+	suite.Require().NoError(err)
+	suite.Equal(progress.Session.SessionID, resumed.Session.SessionID)
+	suite.Equal(2, resumed.Session.CurrentPage)
+	suite.Len(resumed.Answers, 1)
+
+	_, err = suite.service.SubmitResponse(suite.ctx, progress.Session.SessionID, nil)
+	var validationErr *services.SurveyResponseValidationError
+	suite.Require().True(errors.As(err, &validationErr))
+	suite.Contains(validationErr.Errors, suite.comment)
+
+	submitted, err := suite.service.SubmitResponse(suite.ctx, progress.Session.SessionID,
+		[]entities.SurveyAnswer{{QuestionID: suite.comment, AnswerText: "Great venue"}})
+	suite.Require().NoError(err)
+	suite.Equal(entities.ResponseStatusSubmitted, submitted.Response.Status)
+	suite.Equal(float64(100), submitted.Session.Progress)
+	suite.Len(submitted.Answers, 2)
+
+	_, err = suite.service.StartResponse(suite.ctx, suite.survey.ID, respondent)
+	suite.ErrorIs(err, services.ErrSurveyAlreadyResponded)
+	_, err = suite.service.SaveProgress(suite.ctx, progress.Session.SessionID, nil, 1)
+	suite.ErrorIs(err, services.ErrSurveyResponseSubmitted)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestInvalidAnswerIsRejected() {
+	progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{})
+	suite.Require().NoError(err)
+
+	rating := 42.0
+	_, err = suite.service.SaveProgress(suite.ctx, progress.Session.SessionID,
+		[]entities.SurveyAnswer{{QuestionID: suite.rating, AnswerNumber: &rating}}, 1)
+	suite.ErrorIs(err, services.ErrInvalidSurveyResponse)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestSurveyRestrictions() {
+	suite.survey.RequireLogin = true
+	_, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{})
+	suite.ErrorIs(err, services.ErrSurveyLoginRequired)
+	suite.survey.RequireLogin = false
+
+	ended := time.Now().Add(-time.Hour)
+	suite.survey.EndDate = &ended
+	_, err = suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{})
+	suite.ErrorIs(err, services.ErrSurveyClosed)
+	suite.survey.EndDate = nil
+
+	limit := 10
+	suite.survey.TimeLimit = &limit
+	progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{})
+	suite.Require().NoError(err)
+	suite.Require().NotNil(progress.ExpiresAt)
+	suite.responses.responses[0].StartedAt = time.Now().Add(-11 * time.Minute)
+	_, err = suite.service.SaveProgress(suite.ctx, progress.Session.SessionID, nil, 1)
+	suite.ErrorIs(err, services.ErrSurveyTimeLimitExceeded)
+	suite.Equal(entities.ResponseStatusAbandoned, suite.responses.responses[0].Status)
+
+	maxResponses := 1
+	suite.survey.MaxResponses = &maxResponses
+	suite.responses.responses[0].Status = entities.ResponseStatusSubmitted
+	_, err = suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{})
+	suite.ErrorIs(err, services.ErrSurveyFull)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestSubmitRechecksMaxResponses() {
+	rating := 5.0
+	answers := []entities.SurveyAnswer{{QuestionID: suite.rating, AnswerNumber: &rating}, {QuestionID: suite.comment, AnswerText: "Great venue"}}
+	first, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "first"})
+	suite.Require().NoError(err)
+	second, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "second"})
+	suite.Require().NoError(err)
+
+	// Both respondents started while there was room, but only one response fits
+	maxResponses := 1
+	suite.survey.MaxResponses = &maxResponses
+	_, err = suite.service.SubmitResponse(suite.ctx, first.Session.SessionID, answers)
+	suite.Require().NoError(err)
+	_, err = suite.service.SubmitResponse(suite.ctx, second.Session.SessionID, answers)
+	suite.ErrorIs(err, services.ErrSurveyFull)
+
+	completed := true
+	count, _ := suite.responses.CountWithFilter(suite.ctx, repositories.SurveyResponseFilter{SurveyID: suite.survey.ID, IsCompleted: &completed})
+	suite.Equal(int64(1), count)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestFullQuotaScreensOut() {
+	lowRatings := &entities.SurveyQuota{
+		ID:           uuid.New(),
+		SurveyID:     suite.survey.ID,
+		Name:         "Low ratings",
+		Conditions:   fmt.Sprintf(`[{"questionId":"%s","operator":"less_or_equal","value":2}]`, suite.rating),
+		MaxResponses: 1,
+		IsActive:     true,
+	}
+	suite.quotas.quotas = append(suite.quotas.quotas, lowRatings)
+
+	submit := func(respondent string, rating float64) *services.SurveyProgress {
+		progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: respondent})
+		suite.Require().NoError(err)
+		submitted, err := suite.service.SubmitResponse(suite.ctx, progress.Session.SessionID, []entities.SurveyAnswer{
+			{QuestionID: suite.rating, AnswerNumber: &rating},
+			{QuestionID: suite.comment, AnswerText: "Too crowded"},
+		})
+		suite.Require().NoError(err)
+		return submitted
+	}
+
+	suite.Equal(entities.ResponseStatusSubmitted, submit("first", 1).Response.Status)
+	suite.Equal(1, lowRatings.CurrentCount)
+
+	screened := submit("second", 2)
+	suite.Equal(entities.ResponseStatusScreenedOut, screened.Response.Status)
+	suite.Equal(defaultScreenOutMessage, screened.ScreenOutMessage)
+	suite.Equal(1, lowRatings.CurrentCount)
+
+	_, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "second"})
+	suite.ErrorIs(err, services.ErrSurveyAlreadyResponded)
+
+	// Responses outside the quota are not affected
+	suite.Equal(entities.ResponseStatusSubmitted, submit("third", 5).Response.Status)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestInvitationLinkIsSingleUse() {
+	result, err := suite.invitations.CreateInvitations(suite.ctx, &services.SurveyInvitationRequest{
+		SurveyID: suite.survey.ID,
+		Channel:  entities.SurveyInvitationChannelEmail,
+		Invitees: []entities.SurveyInvitee{{Email: "ann@example.com"}},
+	})
+	suite.Require().NoError(err)
+	token := result.Invitations[0].Token
+
+	progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{InvitationToken: token})
+	suite.Require().NoError(err)
+	rating := 5.0
+	submitted, err := suite.service.SubmitResponse(suite.ctx, progress.Session.SessionID, []entities.SurveyAnswer{
+		{QuestionID: suite.rating, AnswerNumber: &rating},
+		{QuestionID: suite.comment, AnswerText: "Great venue"},
+	})
+	suite.Require().NoError(err)
+
+	invitations, err := suite.invitations.GetInvitations(suite.ctx, suite.survey.ID, entities.SurveyInvitationStatusResponded)
+	suite.Require().NoError(err)
+	suite.Require().Len(invitations, 1)
+	suite.Equal(submitted.Response.ID, *invitations[0].ResponseID)
+
+	_, err = suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{InvitationToken: token})
+	suite.ErrorIs(err, services.ErrSurveyInvitationUsed)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestSubmissionDispatchesNotifications() {
+	maxResponses := 1
+	suite.survey.MaxResponses = &maxResponses
+	suite.quotas.quotas = append(suite.quotas.quotas, &entities.SurveyQuota{
+		ID:           uuid.New(),
+		SurveyID:     suite.survey.ID,
+		Name:         "Top ratings",
+		Conditions:   fmt.Sprintf(`[{"questionId":"%s","operator":"equals","value":5}]`, suite.rating),
+		MaxResponses: 1,
+		IsActive:     true,
+	})
+	for _, eventType := range []string{
+		entities.SurveyNotificationEventResponseSubmitted,
+		entities.SurveyNotificationEventQuotaReached,
+		entities.SurveyNotificationEventSurveyClosed,
+	} {
+		suite.Require().NoError(suite.notifications.CreateNotification(suite.ctx, &entities.SurveyNotification{
+			ID:         uuid.New(),
+			SurveyID:   suite.survey.ID,
+			EventType:  eventType,
+			Channel:    entities.SurveyNotificationChannelEmail,
+			Recipients: []string{"host@example.com"},
+			IsActive:   true,
+		}))
+	}
+
+	progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "vip"})
+	suite.Require().NoError(err)
+	rating := 5.0
+	_, err = suite.service.SubmitResponse(suite.ctx, progress.Session.SessionID, []entities.SurveyAnswer{
+		{QuestionID: suite.rating, AnswerNumber: &rating},
+		{QuestionID: suite.comment, AnswerText: "Loved it"},
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().Len(suite.queue.queued, 3)
+	for _, id := range suite.queue.queued {
+		suite.Require().NoError(suite.notifications.ProcessDelivery(suite.ctx, id))
+	}
+	suite.Require().Len(suite.email.sent, 3)
+	suite.Contains(suite.email.sent[0].Body, ": Loved it")
+	suite.Equal("Quota reached: Top ratings", suite.email.sent[1].Subject)
+	suite.Equal("Event feedback is closed", suite.email.sent[2].Subject)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestCollectorAttribution() {
+	limit := 2
+	kiosk := &entities.SurveyCollector{ID: uuid.New(), SurveyID: suite.survey.ID, Name: "Kiosk", Type: entities.SurveyCollectorTypeQRCode,
+		IsActive: true, MaxResponses: &limit, Settings: `{"allowMultiple":true}`}
+	closed := &entities.SurveyCollector{ID: uuid.New(), SurveyID: suite.survey.ID, Name: "Newsletter", Type: entities.SurveyCollectorTypeEmail}
+	suite.collectors.collectors = append(suite.collectors.collectors, kiosk, closed)
+
+	rating := 5.0
+	answers := []entities.SurveyAnswer{
+		{QuestionID: suite.rating, AnswerNumber: &rating},
+		{QuestionID: suite.comment, AnswerText: "Great"},
+	}
+	// The kiosk takes several responses from one browser, up to its own limit
+	respondent := services.SurveyRespondent{SessionID: "kiosk", CollectorID: &kiosk.ID}
+	for i := 0; i < limit; i++ {
+		progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, respondent)
+		suite.Require().NoError(err)
+		suite.Equal(kiosk.ID, *progress.Response.CollectorID)
+		_, err = suite.service.SubmitResponse(suite.ctx, progress.Session.SessionID, answers)
+		suite.Require().NoError(err)
+	}
+	suite.Equal(limit, suite.collectors.collectors[0].ResponseCount)
+	_, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, respondent)
+	suite.ErrorIs(err, services.ErrSurveyCollectorClosed)
+
+	_, err = suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "reader", CollectorID: &closed.ID})
+	suite.ErrorIs(err, services.ErrSurveyCollectorClosed)
+	unknown := uuid.New()
+	_, err = suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "reader", CollectorID: &unknown})
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func (suite *SurveyResponseServiceTestSuite) TestUploadFile() {
+	attachment := entities.SurveyQuestion{ID: uuid.New(), SurveyID: suite.survey.ID, Order: 3, QuestionType: entities.QuestionTypeFile,
+		QuestionText: "Slides", Metadata: `{"maxFiles":2,"maxFileSize":1024,"allowedTypes":[".pdf","image/*"]}`}
+	suite.survey.Questions = append(suite.survey.Questions, attachment)
+	progress, err := suite.service.StartResponse(suite.ctx, suite.survey.ID, services.SurveyRespondent{SessionID: "speaker"})
+	suite.Require().NoError(err)
+	sessionID := progress.Session.SessionID
+
+	upload := func(questionID uuid.UUID, name, contentType, content string) (*entities.SurveyUploadedFile, error) {
+		return suite.service.UploadFile(suite.ctx, sessionID, questionID, &services.SurveyFileUpload{
+			Name: name, ContentType: contentType, Size: int64(len(content)), Content: strings.NewReader(content),
+		})
+	}
+	file, err := upload(attachment.ID, "../../slides.pdf", "application/pdf", "%PDF-1.7")
+	suite.Require().NoError(err)
+	suite.Equal("slides.pdf", file.Name)
+	suite.True(strings.HasPrefix(file.Key, entities.SurveyFileKeyPrefix(suite.survey.ID, attachment.ID)))
+	suite.Equal("/api/v1/"+file.Key, file.URL)
+
+	_, err = upload(attachment.ID, "photo.heic", "image/heic", "photo")
+	suite.NoError(err)
+	_, err = upload(attachment.ID, "notes.pdf", "application/pdf", "%PDF-1.7")
+	suite.ErrorIs(err, entities.ErrInvalidSurveyFile)
+	suite.ErrorContains(err, "at most 2 files")
+	_, err = upload(attachment.ID, "setup.exe", "application/octet-stream", "MZ")
+	suite.ErrorIs(err, entities.ErrInvalidSurveyFile)
+	_, err = upload(attachment.ID, "slides.pdf", "application/pdf", strings.Repeat("x", 2048))
+	suite.ErrorIs(err, entities.ErrInvalidSurveyFile)
+	_, err = upload(suite.comment, "slides.pdf", "application/pdf", "%PDF-1.7")
+	suite.ErrorIs(err, entities.ErrInvalidSurveyFile)
+
+	// Answers can only list files uploaded for the question
+	listed, err := json.Marshal([]entities.SurveyUploadedFile{*file})
+	suite.Require().NoError(err)
+	_, err = suite.service.SaveProgress(suite.ctx, sessionID, []entities.SurveyAnswer{{QuestionID: attachment.ID, AnswerJSON: string(listed)}}, 1)
+	suite.NoError(err)
+	forged := *file
+	forged.Key = "surveys/elsewhere/slides.pdf"
+	listed, err = json.Marshal([]entities.SurveyUploadedFile{forged})
+	suite.Require().NoError(err)
+	_, err = suite.service.SaveProgress(suite.ctx, sessionID, []entities.SurveyAnswer{{QuestionID: attachment.ID, AnswerJSON: string(listed)}}, 1)
+	suite.ErrorIs(err, services.ErrInvalidSurveyResponse)
+}
+
+func TestSurveyResponseServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyResponseServiceTestSuite))
+}
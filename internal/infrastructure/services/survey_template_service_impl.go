@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyTemplateServiceImpl implements the SurveyTemplateService interface
+type SurveyTemplateServiceImpl struct {
+	templateRepo repositories.SurveyTemplateRepository
+	surveyRepo   repositories.SurveyRepository
+	logicRepo    repositories.SurveyLogicRepository
+	logger       *zap.Logger
+}
+
+// NewSurveyTemplateService creates a new survey template service implementation
+func NewSurveyTemplateService(
+	templateRepo repositories.SurveyTemplateRepository,
+	surveyRepo repositories.SurveyRepository,
+	logicRepo repositories.SurveyLogicRepository,
+	logger *zap.Logger,
+) services.SurveyTemplateService {
+	return &SurveyTemplateServiceImpl{
+		templateRepo: templateRepo,
+		surveyRepo:   surveyRepo,
+		logicRepo:    logicRepo,
+		logger:       logger,
+	}
+}
+
+// CreateTemplate validates and stores a template with its questions
+func (s *SurveyTemplateServiceImpl) CreateTemplate(ctx context.Context, template *entities.SurveyTemplate) error {
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	prepareTemplateQuestions(template)
+	if template.TemplateData == "" {
+		if err := template.SetData(&entities.SurveyTemplateData{IsAnonymous: true, ShowProgress: true}); err != nil {
+			return err
+		}
+	}
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return err
+	}
+
+	s.logger.Info("Survey template created",
+		zap.String("templateId", template.ID.String()),
+		zap.String("name", template.Name))
+	return nil
+}
+
+// GetTemplate retrieves a built-in or stored template by ID
+func (s *SurveyTemplateServiceImpl) GetTemplate(ctx context.Context, id uuid.UUID) (*entities.SurveyTemplate, error) {
+	if template, ok := entities.FindBuiltInSurveyTemplate(id); ok {
+		return template, nil
+	}
+	return s.templateRepo.FindByID(ctx, id)
+}
+
+// GetTemplates lists the built-in templates followed by the stored ones, optionally of one category
+func (s *SurveyTemplateServiceImpl) GetTemplates(ctx context.Context, category string) ([]entities.SurveyTemplate, error) {
+	var templates []entities.SurveyTemplate
+	for _, template := range entities.BuiltInSurveyTemplates() {
+		if category == "" || template.Category == category {
+			templates = append(templates, template)
+		}
+	}
+
+	var stored []entities.SurveyTemplate
+	var err error
+	if category == "" {
+		stored, err = s.templateRepo.FindAll(ctx)
+	} else {
+		stored, err = s.templateRepo.FindByCategory(ctx, category)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(templates, stored...), nil
+}
+
+// UpdateTemplate validates and updates a stored template, replacing its questions. Usage and
+// authorship are kept.
+func (s *SurveyTemplateServiceImpl) UpdateTemplate(ctx context.Context, template *entities.SurveyTemplate) error {
+	if _, ok := entities.FindBuiltInSurveyTemplate(template.ID); ok {
+		return services.ErrSurveyTemplateBuiltIn
+	}
+	existing, err := s.templateRepo.FindByID(ctx, template.ID)
+	if err != nil {
+		return err
+	}
+	template.UsageCount = existing.UsageCount
+	template.Rating = existing.Rating
+	template.CreatedBy = existing.CreatedBy
+	template.CreatedAt = existing.CreatedAt
+	if template.TemplateData == "" {
+		template.TemplateData = existing.TemplateData
+	}
+
+	prepareTemplateQuestions(template)
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	return s.templateRepo.Update(ctx, template)
+}
+
+// DeleteTemplate deletes a stored template
+func (s *SurveyTemplateServiceImpl) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	if _, ok := entities.FindBuiltInSurveyTemplate(id); ok {
+		return services.ErrSurveyTemplateBuiltIn
+	}
+	return s.templateRepo.Delete(ctx, id)
+}
+
+// CreateSurvey copies a template into a new draft survey with its logic. The survey is removed
+// again when its logic cannot be stored.
+func (s *SurveyTemplateServiceImpl) CreateSurvey(ctx context.Context, templateID uuid.UUID, title string, createdBy uuid.UUID) (*entities.Survey, error) {
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	survey, logic, err := template.NewSurvey(title, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := survey.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.surveyRepo.Create(ctx, survey); err != nil {
+		return nil, err
+	}
+	for i := range logic {
+		if err := s.logicRepo.Create(ctx, &logic[i]); err != nil {
+			if deleteErr := s.surveyRepo.Delete(ctx, survey.ID); deleteErr != nil {
+				s.logger.Error("Failed to remove survey created from template",
+					zap.String("surveyId", survey.ID.String()),
+					zap.Error(deleteErr))
+			}
+			return nil, err
+		}
+	}
+
+	if _, builtIn := entities.FindBuiltInSurveyTemplate(templateID); !builtIn {
+		if err := s.templateRepo.IncrementUsage(ctx, templateID); err != nil {
+			s.logger.Warn("Failed to count survey template usage",
+				zap.String("templateId", templateID.String()),
+				zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Survey created from template",
+		zap.String("templateId", templateID.String()),
+		zap.String("surveyId", survey.ID.String()))
+	return survey, nil
+}
+
+// SaveSurveyAsTemplate stores a copy of a survey's questions, settings and active logic as a template
+func (s *SurveyTemplateServiceImpl) SaveSurveyAsTemplate(ctx context.Context, surveyID uuid.UUID, template *entities.SurveyTemplate) error {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return err
+	}
+	logic, err := s.logicRepo.FindActiveBySurveyID(ctx, surveyID)
+	if err != nil {
+		return err
+	}
+
+	saved, err := entities.NewSurveyTemplate(survey, logic)
+	if err != nil {
+		return err
+	}
+	if template.Name != "" {
+		saved.Name = template.Name
+	}
+	if template.Description != "" {
+		saved.Description = template.Description
+	}
+	saved.Category = template.Category
+	saved.Tags = template.Tags
+	saved.IsPublic = template.IsPublic
+	saved.CreatedBy = template.CreatedBy
+
+	if err := saved.Validate(); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Create(ctx, saved); err != nil {
+		return err
+	}
+	*template = *saved
+
+	s.logger.Info("Survey saved as template",
+		zap.String("surveyId", surveyID.String()),
+		zap.String("templateId", template.ID.String()))
+	return nil
+}
+
+// prepareTemplateQuestions gives new questions an ID and points every question at the template
+func prepareTemplateQuestions(template *entities.SurveyTemplate) {
+	for i := range template.Questions {
+		if template.Questions[i].ID == uuid.Nil {
+			template.Questions[i].ID = uuid.New()
+		}
+		template.Questions[i].TemplateID = template.ID
+	}
+}
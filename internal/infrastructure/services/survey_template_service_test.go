@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// memorySurveyTemplateRepository keeps templates in memory
+type memorySurveyTemplateRepository struct {
+	repositories.SurveyTemplateRepository
+	templates map[uuid.UUID]*entities.SurveyTemplate
+}
+
+func (r *memorySurveyTemplateRepository) Create(ctx context.Context, template *entities.SurveyTemplate) error {
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *memorySurveyTemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.SurveyTemplate, error) {
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	copied := *template
+	return &copied, nil
+}
+
+func (r *memorySurveyTemplateRepository) Update(ctx context.Context, template *entities.SurveyTemplate) error {
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *memorySurveyTemplateRepository) FindAll(ctx context.Context) ([]entities.SurveyTemplate, error) {
+	var templates []entities.SurveyTemplate
+	for _, template := range r.templates {
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+func (r *memorySurveyTemplateRepository) IncrementUsage(ctx context.Context, id uuid.UUID) error {
+	r.templates[id].UsageCount++
+	return nil
+}
+
+type SurveyTemplateServiceTestSuite struct {
+	suite.Suite
+	templates *memorySurveyTemplateRepository
+	surveys   *memorySurveyRepository
+	logic     *memorySurveyLogicRepository
+	service   services.SurveyTemplateService
+	ctx       context.Context
+}
+
+func (suite *SurveyTemplateServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.templates = &memorySurveyTemplateRepository{templates: make(map[uuid.UUID]*entities.SurveyTemplate)}
+	suite.surveys = &memorySurveyRepository{surveys: make(map[uuid.UUID]*entities.Survey)}
+	suite.logic = &memorySurveyLogicRepository{}
+	suite.service = NewSurveyTemplateService(suite.templates, suite.surveys, suite.logic, zap.NewNop())
+}
+
+func (suite *SurveyTemplateServiceTestSuite) newTemplate() *entities.SurveyTemplate {
+	return &entities.SurveyTemplate{
+		Name: "Speaker feedback",
+		Questions: []entities.SurveyTemplateQuestion{
+			{Order: 1, QuestionType: entities.QuestionTypeRating, QuestionText: "Rate the talk", Options: []string{"1", "5"}},
+		},
+	}
+}
+
+func (suite *SurveyTemplateServiceTestSuite) TestBuiltInTemplatesAreListedAndReadOnly() {
+	suite.Require().NoError(suite.service.CreateTemplate(suite.ctx, suite.newTemplate()))
+
+	templates, err := suite.service.GetTemplates(suite.ctx, "")
+	suite.Require().NoError(err)
+	builtIn := entities.BuiltInSurveyTemplates()
+	suite.Require().Len(templates, len(builtIn)+1)
+	suite.Equal(builtIn[0].ID, templates[0].ID)
+	suite.Equal("Speaker feedback", templates[len(templates)-1].Name)
+
+	suite.ErrorIs(suite.service.DeleteTemplate(suite.ctx, builtIn[0].ID), services.ErrSurveyTemplateBuiltIn)
+	update := builtIn[0]
+	suite.ErrorIs(suite.service.UpdateTemplate(suite.ctx, &update), services.ErrSurveyTemplateBuiltIn)
+}
+
+func (suite *SurveyTemplateServiceTestSuite) TestCreateSurveyFromTemplate() {
+	template := suite.newTemplate()
+	suite.Require().NoError(suite.service.CreateTemplate(suite.ctx, template))
+
+	createdBy := uuid.New()
+	survey, err := suite.service.CreateSurvey(suite.ctx, template.ID, "Day 1 talks", createdBy)
+	suite.Require().NoError(err)
+	suite.Equal("Day 1 talks", survey.Title)
+	suite.Equal(createdBy, survey.CreatedBy)
+	suite.Contains(suite.surveys.surveys, survey.ID)
+	suite.Equal(1, suite.templates.templates[template.ID].UsageCount)
+
+	_, err = suite.service.CreateSurvey(suite.ctx, uuid.New(), "", createdBy)
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func (suite *SurveyTemplateServiceTestSuite) TestCreateSurveyFromBuiltInTemplateCopiesLogic() {
+	var nps entities.SurveyTemplate
+	for _, template := range entities.BuiltInSurveyTemplates() {
+		if template.Name == "Net Promoter Score" {
+			nps = template
+		}
+	}
+
+	survey, err := suite.service.CreateSurvey(suite.ctx, nps.ID, "", uuid.New())
+	suite.Require().NoError(err)
+	suite.Require().Len(suite.logic.logic, 1)
+	suite.Equal(survey.ID, suite.logic.logic[0].SurveyID)
+	suite.Equal(survey.Questions[0].ID, suite.logic.logic[0].QuestionID)
+}
+
+func (suite *SurveyTemplateServiceTestSuite) TestUpdateTemplateKeepsUsage() {
+	template := suite.newTemplate()
+	suite.Require().NoError(suite.service.CreateTemplate(suite.ctx, template))
+	template.UsageCount = 4
+
+	update := suite.newTemplate()
+	update.ID = template.ID
+	update.Name = "Talk feedback"
+	suite.Require().NoError(suite.service.UpdateTemplate(suite.ctx, update))
+	suite.Equal("Talk feedback", suite.templates.templates[template.ID].Name)
+	suite.Equal(4, suite.templates.templates[template.ID].UsageCount)
+
+	update.Questions = nil
+	suite.ErrorIs(suite.service.UpdateTemplate(suite.ctx, update), entities.ErrInvalidTemplate)
+}
+
+func (suite *SurveyTemplateServiceTestSuite) TestSaveSurveyAsTemplate() {
+	survey := &entities.Survey{
+		ID:    uuid.New(),
+		Title: "Workshop feedback",
+		Questions: []entities.SurveyQuestion{
+			{ID: uuid.New(), Order: 1, QuestionType: entities.QuestionTypeText, QuestionText: "Comments"},
+		},
+	}
+	suite.surveys.surveys[survey.ID] = survey
+	createdBy := uuid.New()
+
+	template := &entities.SurveyTemplate{Category: entities.SurveyTemplateCategoryFeedback, CreatedBy: createdBy}
+	suite.Require().NoError(suite.service.SaveSurveyAsTemplate(suite.ctx, survey.ID, template))
+	suite.Equal("Workshop feedback", template.Name)
+	suite.Equal(createdBy, template.CreatedBy)
+	suite.Contains(suite.templates.templates, template.ID)
+	suite.NotEqual(survey.Questions[0].ID, template.Questions[0].ID)
+}
+
+func TestSurveyTemplateServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyTemplateServiceTestSuite))
+}
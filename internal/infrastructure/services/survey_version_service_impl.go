@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyVersionServiceImpl implements the SurveyVersionService interface
+type SurveyVersionServiceImpl struct {
+	surveyRepo   repositories.SurveyRepository
+	questionRepo repositories.SurveyQuestionRepository
+	versionRepo  repositories.SurveyVersionRepository
+	logger       *zap.Logger
+}
+
+// NewSurveyVersionService creates a new survey version service implementation
+func NewSurveyVersionService(
+	surveyRepo repositories.SurveyRepository,
+	questionRepo repositories.SurveyQuestionRepository,
+	versionRepo repositories.SurveyVersionRepository,
+	logger *zap.Logger,
+) services.SurveyVersionService {
+	return &SurveyVersionServiceImpl{
+		surveyRepo:   surveyRepo,
+		questionRepo: questionRepo,
+		versionRepo:  versionRepo,
+		logger:       logger,
+	}
+}
+
+// ReviseSurvey applies a revision. Revising a published or closed survey snapshots the version
+// responses were collected under, raises the version and retires removed questions instead of
+// deleting them, so their answers are kept.
+func (s *SurveyVersionServiceImpl) ReviseSurvey(ctx context.Context, surveyID uuid.UUID, revision *services.SurveyRevision) (*entities.Survey, error) {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	if survey.Status == entities.SurveyStatusArchived {
+		return nil, services.ErrSurveyNotRevisable
+	}
+	versioned := !survey.CanBeEdited()
+
+	questions, added, removed, err := planSurveyRevision(survey, revision, versioned)
+	if err != nil {
+		return nil, err
+	}
+	revised := *survey
+	revised.Title = revision.Title
+	revised.Description = revision.Description
+	revised.Instructions = revision.Instructions
+	if err := revised.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidSurveyRevision, err)
+	}
+
+	if versioned {
+		if err := s.ensureVersion(ctx, survey); err != nil {
+			return nil, err
+		}
+		revised.Version++
+	}
+
+	for i := range questions {
+		if added[questions[i].ID] {
+			err = s.questionRepo.Create(ctx, &questions[i])
+		} else {
+			err = s.questionRepo.Update(ctx, &questions[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range removed {
+		if versioned {
+			removed[i].RemovedInVersion = &revised.Version
+			err = s.questionRepo.Update(ctx, &removed[i])
+		} else {
+			err = s.questionRepo.Delete(ctx, removed[i].ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	record := revised
+	record.Questions = nil
+	record.Responses = nil
+	if err := s.surveyRepo.Update(ctx, &record); err != nil {
+		return nil, err
+	}
+
+	if versioned {
+		revised.Questions = questions
+		version, err := entities.NewSurveyVersion(&revised, revision.ChangeNote, revision.EditedBy)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.versionRepo.Create(ctx, version); err != nil {
+			return nil, err
+		}
+		s.logger.Info("Survey revised",
+			zap.String("surveyId", survey.ID.String()),
+			zap.Int("version", revised.Version),
+			zap.Int("addedQuestions", len(added)),
+			zap.Int("removedQuestions", len(removed)))
+	}
+
+	return s.surveyRepo.FindByID(ctx, surveyID)
+}
+
+// GetVersions returns the recorded versions of a survey. A survey that was never revised has its
+// current content as its only version.
+func (s *SurveyVersionServiceImpl) GetVersions(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyVersion, error) {
+	survey, err := s.surveyRepo.FindByID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.versionRepo.FindBySurveyID(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) > 0 && versions[len(versions)-1].Version == survey.Version {
+		return parseSurveyVersions(versions)
+	}
+
+	current, err := entities.NewSurveyVersion(survey, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseSurveyVersions(append(versions, *current))
+}
+
+// GetVersion returns one version of a survey
+func (s *SurveyVersionServiceImpl) GetVersion(ctx context.Context, surveyID uuid.UUID, version int) (*entities.SurveyVersion, error) {
+	versions, err := s.GetVersions(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i], nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// DiffVersions lists the changes between two versions of a survey
+func (s *SurveyVersionServiceImpl) DiffVersions(ctx context.Context, surveyID uuid.UUID, from, to int) (*entities.SurveyVersionDiff, error) {
+	fromVersion, err := s.GetVersion(ctx, surveyID, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.GetVersion(ctx, surveyID, to)
+	if err != nil {
+		return nil, err
+	}
+	return entities.DiffSurveyVersions(fromVersion, toVersion)
+}
+
+// ensureVersion records the current content of a survey as its current version unless it was
+// recorded by an earlier revision
+func (s *SurveyVersionServiceImpl) ensureVersion(ctx context.Context, survey *entities.Survey) error {
+	_, err := s.versionRepo.FindByVersion(ctx, survey.ID, survey.Version)
+	if err == nil || !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+	version, err := entities.NewSurveyVersion(survey, "", nil)
+	if err != nil {
+		return err
+	}
+	return s.versionRepo.Create(ctx, version)
+}
+
+// planSurveyRevision validates the questions of a revision against the survey. It returns the
+// questions in their new order, the IDs of the added ones and the questions to remove. Once
+// responses are collected a question cannot change its type, as its answers would no longer fit.
+func planSurveyRevision(survey *entities.Survey, revision *services.SurveyRevision, versioned bool) ([]entities.SurveyQuestion, map[uuid.UUID]bool, []entities.SurveyQuestion, error) {
+	existing := make(map[uuid.UUID]*entities.SurveyQuestion, len(survey.Questions))
+	for i := range survey.Questions {
+		existing[survey.Questions[i].ID] = &survey.Questions[i]
+	}
+
+	questions := make([]entities.SurveyQuestion, 0, len(revision.Questions))
+	added := make(map[uuid.UUID]bool)
+	kept := make(map[uuid.UUID]bool, len(revision.Questions))
+	for i, question := range revision.Questions {
+		if question.ID == uuid.Nil {
+			question.ID = uuid.New()
+			added[question.ID] = true
+		} else if previous := existing[question.ID]; previous == nil || kept[question.ID] {
+			return nil, nil, nil, fmt.Errorf("%w: question %d is not a question of the survey", services.ErrInvalidSurveyRevision, i+1)
+		} else {
+			if versioned && previous.QuestionType != question.QuestionType {
+				return nil, nil, nil, fmt.Errorf("%w: question %d cannot change its type once responses are collected; remove it and add a new question",
+					services.ErrInvalidSurveyRevision, i+1)
+			}
+			question.CreatedAt = previous.CreatedAt
+			kept[question.ID] = true
+		}
+
+		question.SurveyID = survey.ID
+		question.Survey = nil
+		question.Answers = nil
+		question.RemovedInVersion = nil
+		question.Order = i + 1
+		if strings.TrimSpace(question.Validation) == "" {
+			question.Validation = "{}"
+		}
+		if strings.TrimSpace(question.Metadata) == "" {
+			question.Metadata = "{}"
+		}
+		if err := question.Validate(); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: question %d: %v", services.ErrInvalidSurveyRevision, i+1, err)
+		}
+		questions = append(questions, question)
+	}
+
+	var removed []entities.SurveyQuestion
+	for _, question := range survey.Questions {
+		if !kept[question.ID] {
+			question.Answers = nil
+			question.Survey = nil
+			removed = append(removed, question)
+		}
+	}
+	return questions, added, removed, nil
+}
+
+// parseSurveyVersions fills in the questions of the versions
+func parseSurveyVersions(versions []entities.SurveyVersion) ([]entities.SurveyVersion, error) {
+	for i := range versions {
+		questions, err := versions[i].GetQuestions()
+		if err != nil {
+			return nil, err
+		}
+		versions[i].Questions = questions
+	}
+	return versions, nil
+}
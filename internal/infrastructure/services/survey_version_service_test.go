@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// memorySurveyVersionRepository keeps survey versions in memory
+type memorySurveyVersionRepository struct {
+	repositories.SurveyVersionRepository
+	versions []entities.SurveyVersion
+}
+
+func (r *memorySurveyVersionRepository) Create(ctx context.Context, version *entities.SurveyVersion) error {
+	r.versions = append(r.versions, *version)
+	return nil
+}
+
+func (r *memorySurveyVersionRepository) FindBySurveyID(ctx context.Context, surveyID uuid.UUID) ([]entities.SurveyVersion, error) {
+	var versions []entities.SurveyVersion
+	for _, version := range r.versions {
+		if version.SurveyID == surveyID {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+func (r *memorySurveyVersionRepository) FindByVersion(ctx context.Context, surveyID uuid.UUID, version int) (*entities.SurveyVersion, error) {
+	for _, stored := range r.versions {
+		if stored.SurveyID == surveyID && stored.Version == version {
+			return &stored, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// memoryRevisedSurveyQuestionRepository edits the questions of the surveys in memory, keeping
+// retired questions apart as the survey repository no longer loads them
+type memoryRevisedSurveyQuestionRepository struct {
+	repositories.SurveyQuestionRepository
+	surveys *memorySurveyRepository
+	retired []entities.SurveyQuestion
+}
+
+func (r *memoryRevisedSurveyQuestionRepository) Create(ctx context.Context, question *entities.SurveyQuestion) error {
+	survey := r.surveys.surveys[question.SurveyID]
+	survey.Questions = append(append([]entities.SurveyQuestion{}, survey.Questions...), *question)
+	r.sort(survey)
+	return nil
+}
+
+func (r *memoryRevisedSurveyQuestionRepository) Update(ctx context.Context, question *entities.SurveyQuestion) error {
+	survey := r.surveys.surveys[question.SurveyID]
+	questions := make([]entities.SurveyQuestion, 0, len(survey.Questions))
+	for _, stored := range survey.Questions {
+		if stored.ID != question.ID {
+			questions = append(questions, stored)
+		}
+	}
+	if question.RemovedInVersion != nil {
+		r.retired = append(r.retired, *question)
+	} else {
+		questions = append(questions, *question)
+	}
+	survey.Questions = questions
+	r.sort(survey)
+	return nil
+}
+
+func (r *memoryRevisedSurveyQuestionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	for _, survey := range r.surveys.surveys {
+		questions := make([]entities.SurveyQuestion, 0, len(survey.Questions))
+		for _, stored := range survey.Questions {
+			if stored.ID != id {
+				questions = append(questions, stored)
+			}
+		}
+		survey.Questions = questions
+	}
+	return nil
+}
+
+func (r *memoryRevisedSurveyQuestionRepository) sort(survey *entities.Survey) {
+	sort.SliceStable(survey.Questions, func(i, j int) bool { return survey.Questions[i].Order < survey.Questions[j].Order })
+}
+
+type SurveyVersionServiceTestSuite struct {
+	suite.Suite
+	surveys   *memorySurveyRepository
+	questions *memoryRevisedSurveyQuestionRepository
+	versions  *memorySurveyVersionRepository
+	service   services.SurveyVersionService
+	survey    *entities.Survey
+	ctx       context.Context
+}
+
+func (suite *SurveyVersionServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	surveyID := uuid.New()
+	suite.survey = &entities.Survey{
+		ID:      surveyID,
+		Title:   "Event feedback",
+		Status:  entities.SurveyStatusPublished,
+		Version: 1,
+		Questions: []entities.SurveyQuestion{
+			{ID: uuid.New(), SurveyID: surveyID, Order: 1, QuestionType: entities.QuestionTypeRadio, QuestionText: "Role", Options: pq.StringArray{"Speaker", "Guest"}},
+			{ID: uuid.New(), SurveyID: surveyID, Order: 2, QuestionType: entities.QuestionTypeText, QuestionText: "Comments"},
+		},
+	}
+	suite.surveys = &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{suite.survey.ID: suite.survey}}
+	suite.questions = &memoryRevisedSurveyQuestionRepository{surveys: suite.surveys}
+	suite.versions = &memorySurveyVersionRepository{}
+	suite.service = NewSurveyVersionService(suite.surveys, suite.questions, suite.versions, zap.NewNop())
+}
+
+// revision keeps the role question with renamed options, drops the comments and asks for the sessions attended
+func (suite *SurveyVersionServiceTestSuite) revision() *services.SurveyRevision {
+	editor := uuid.New()
+	return &services.SurveyRevision{
+		Title: "Event feedback 2026",
+		Questions: []entities.SurveyQuestion{
+			{QuestionType: entities.QuestionTypeNumber, QuestionText: "Sessions attended"},
+			{ID: suite.survey.Questions[0].ID, QuestionType: entities.QuestionTypeRadio, QuestionText: "Role", Options: pq.StringArray{"Speaker", "Attendee"}},
+		},
+		ChangeNote: "Rename guests to attendees",
+		EditedBy:   &editor,
+	}
+}
+
+func (suite *SurveyVersionServiceTestSuite) TestRevisePublishedSurveyCreatesVersion() {
+	role, comments := suite.survey.Questions[0].ID, suite.survey.Questions[1].ID
+
+	revised, err := suite.service.ReviseSurvey(suite.ctx, suite.survey.ID, suite.revision())
+	suite.Require().NoError(err)
+	suite.Equal(2, revised.Version)
+	suite.Equal("Event feedback 2026", revised.Title)
+	suite.Require().Len(revised.Questions, 2)
+	suite.Equal("Sessions attended", revised.Questions[0].QuestionText)
+	suite.Equal(role, revised.Questions[1].ID)
+	suite.Equal(2, revised.Questions[1].Order)
+	suite.Equal("{}", revised.Questions[0].Validation)
+
+	// The comments question is retired, not deleted, so its answers are kept
+	suite.Require().Len(suite.questions.retired, 1)
+	suite.Equal(comments, suite.questions.retired[0].ID)
+	suite.Equal(2, *suite.questions.retired[0].RemovedInVersion)
+
+	versions, err := suite.service.GetVersions(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(versions, 2)
+	suite.Equal("Event feedback", versions[0].Title)
+	suite.Len(versions[0].Questions, 2)
+	suite.Equal("Rename guests to attendees", versions[1].ChangeNote)
+
+	diff, err := suite.service.DiffVersions(suite.ctx, suite.survey.ID, 1, 2)
+	suite.Require().NoError(err)
+	suite.Len(diff.AddedQuestions, 1)
+	suite.Require().Len(diff.RemovedQuestions, 1)
+	suite.Equal(comments, diff.RemovedQuestions[0].ID)
+	suite.Require().Len(diff.ChangedQuestions, 1)
+	suite.Equal(map[string]string{"Guest": "Attendee"}, diff.ChangedQuestions[0].RenamedOptions)
+
+	_, err = suite.service.GetVersion(suite.ctx, suite.survey.ID, 3)
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func (suite *SurveyVersionServiceTestSuite) TestReviseDraftEditsInPlace() {
+	suite.survey.Status = entities.SurveyStatusDraft
+
+	revised, err := suite.service.ReviseSurvey(suite.ctx, suite.survey.ID, suite.revision())
+	suite.Require().NoError(err)
+	suite.Equal(1, revised.Version)
+	suite.Len(revised.Questions, 2)
+	suite.Empty(suite.questions.retired)
+	suite.Empty(suite.versions.versions)
+
+	versions, err := suite.service.GetVersions(suite.ctx, suite.survey.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(versions, 1)
+	suite.Equal("Event feedback 2026", versions[0].Title)
+}
+
+func (suite *SurveyVersionServiceTestSuite) TestReviseRejectsInvalidRevisions() {
+	changedType := suite.revision()
+	changedType.Questions[1].QuestionType = entities.QuestionTypeCheckbox
+	_, err := suite.service.ReviseSurvey(suite.ctx, suite.survey.ID, changedType)
+	suite.ErrorIs(err, services.ErrInvalidSurveyRevision)
+
+	unknown := suite.revision()
+	unknown.Questions[1].ID = uuid.New()
+	_, err = suite.service.ReviseSurvey(suite.ctx, suite.survey.ID, unknown)
+	suite.ErrorIs(err, services.ErrInvalidSurveyRevision)
+
+	untitled := suite.revision()
+	untitled.Title = ""
+	_, err = suite.service.ReviseSurvey(suite.ctx, suite.survey.ID, untitled)
+	suite.ErrorIs(err, services.ErrInvalidSurveyRevision)
+	suite.Empty(suite.versions.versions)
+
+	suite.survey.Status = entities.SurveyStatusArchived
+	_, err = suite.service.ReviseSurvey(suite.ctx, suite.survey.ID, suite.revision())
+	suite.ErrorIs(err, services.ErrSurveyNotRevisable)
+}
+
+func TestSurveyVersionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SurveyVersionServiceTestSuite))
+}
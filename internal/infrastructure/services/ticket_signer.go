@@ -0,0 +1,140 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+)
+
+// ticketPrefix marks a signed attendee ticket and its format version. The dot keeps tickets
+// apart from QR scene strings, which use "<type>_<id>".
+const ticketPrefix = "NT1."
+
+// ticketPayloadSize is event ID, attendee ID and user ID (16 bytes each) followed by the
+// issue and expiry times (8-byte Unix seconds each)
+const ticketPayloadSize = 16*3 + 8*2
+
+var (
+	// ErrMalformedTicket is returned when a QR code is not a well-formed signed ticket
+	ErrMalformedTicket = errors.New("malformed ticket")
+	// ErrInvalidTicketSignature is returned when a ticket was not signed with our key or was tampered with
+	ErrInvalidTicketSignature = errors.New("invalid ticket signature")
+)
+
+// TicketSigner signs and verifies compact attendee tickets with Ed25519. Only the server holds the
+// private key; door scanners verify tickets offline with the public key.
+type TicketSigner struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	keyID      string
+}
+
+// NewTicketSigner creates a ticket signer from a 32-byte Ed25519 seed
+func NewTicketSigner(seed []byte) (*TicketSigner, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("ticket signing key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	fingerprint := sha256.Sum256(publicKey)
+
+	return &TicketSigner{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		keyID:      hex.EncodeToString(fingerprint[:4]),
+	}, nil
+}
+
+// NewTicketSignerFromConfig creates a ticket signer from a base64-encoded seed. It returns nil when
+// no key is configured, which disables ticket signing.
+func NewTicketSignerFromConfig(signingKey string) (*TicketSigner, error) {
+	if signingKey == "" {
+		return nil, nil
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ticket signing key: %w", err)
+	}
+	return NewTicketSigner(seed)
+}
+
+// Sign encodes and signs ticket data
+func (s *TicketSigner) Sign(data *entities.QRCodeData) string {
+	payload := make([]byte, ticketPayloadSize, ticketPayloadSize+ed25519.SignatureSize)
+	copy(payload[0:16], data.EventID[:])
+	copy(payload[16:32], data.AttendeeID[:])
+	copy(payload[32:48], data.UserID[:])
+	binary.BigEndian.PutUint64(payload[48:56], uint64(data.Timestamp.Unix()))
+	binary.BigEndian.PutUint64(payload[56:64], uint64(data.ExpiresAt.Unix()))
+
+	signed := append(payload, ed25519.Sign(s.privateKey, payload)...)
+	return ticketPrefix + base64.RawURLEncoding.EncodeToString(signed)
+}
+
+// Verify checks a ticket's signature and decodes its data. It does not check expiry or revocation.
+func (s *TicketSigner) Verify(ticket string) (*entities.QRCodeData, error) {
+	payload, signature, err := splitTicket(ticket)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(s.publicKey, payload, signature) {
+		return nil, ErrInvalidTicketSignature
+	}
+
+	data := &entities.QRCodeData{
+		Timestamp: time.Unix(int64(binary.BigEndian.Uint64(payload[48:56])), 0),
+		ExpiresAt: time.Unix(int64(binary.BigEndian.Uint64(payload[56:64])), 0),
+	}
+	data.EventID, _ = uuid.FromBytes(payload[0:16])
+	data.AttendeeID, _ = uuid.FromBytes(payload[16:32])
+	data.UserID, _ = uuid.FromBytes(payload[32:48])
+	return data, nil
+}
+
+// PublicKey returns the key door scanners use to verify tickets
+func (s *TicketSigner) PublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// KeyID returns a short fingerprint of the public key so scanners can tell when the key rotates
+func (s *TicketSigner) KeyID() string {
+	return s.keyID
+}
+
+// IsTicket reports whether a QR code looks like a signed ticket
+func IsTicket(qrCode string) bool {
+	return strings.HasPrefix(qrCode, ticketPrefix)
+}
+
+// TicketID returns the stable identifier of a ticket used for revocation
+func TicketID(ticket string) (string, error) {
+	payload, _, err := splitTicket(ticket)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:12]), nil
+}
+
+// splitTicket decodes a ticket into its payload and signature
+func splitTicket(ticket string) ([]byte, []byte, error) {
+	if !IsTicket(ticket) {
+		return nil, nil, ErrMalformedTicket
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(ticket, ticketPrefix))
+	if err != nil || len(raw) != ticketPayloadSize+ed25519.SignatureSize {
+		return nil, nil, ErrMalformedTicket
+	}
+	return raw[:ticketPayloadSize], raw[ticketPayloadSize:], nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+)
+
+const (
+	ticketRevocationsKey   = "ticket:revocations"
+	ticketCheckInKeyPrefix = "ticket:checkin:"
+
+	// ticketCheckInMinTTL keeps check-in claims of tickets scanned around or after their expiry
+	// long enough to reject replays
+	ticketCheckInMinTTL = time.Hour
+)
+
+// ticketStore keeps ticket revocations and single-use check-in claims out of the database so the
+// door-scanner path never waits on it
+type ticketStore interface {
+	// Revoke records a revocation of a ticket ID or attendee ID
+	Revoke(ctx context.Context, revocation services.TicketRevocation) error
+	// Revocation returns the revocation recorded for an ID, or nil if there is none
+	Revocation(ctx context.Context, id string) (*services.TicketRevocation, error)
+	// Revocations lists the revocations that have not expired yet
+	Revocations(ctx context.Context) ([]services.TicketRevocation, error)
+	// ClaimCheckIn marks an attendee as admitted until the ticket expires. It returns false and
+	// the first admission time when the attendee has already been admitted.
+	ClaimCheckIn(ctx context.Context, attendeeID uuid.UUID, at, until time.Time) (bool, time.Time, error)
+	// CheckInTime returns when the attendee was admitted, or nil if they have not been
+	CheckInTime(ctx context.Context, attendeeID uuid.UUID) (*time.Time, error)
+}
+
+// newTicketStore uses Redis when it is configured and falls back to process memory otherwise
+func newTicketStore(redisClient *redis.Client) ticketStore {
+	if redisClient == nil {
+		return newMemoryTicketStore()
+	}
+	return &redisTicketStore{client: redisClient}
+}
+
+// redisTicketStore shares revocations and check-in claims between API instances
+type redisTicketStore struct {
+	client *redis.Client
+}
+
+func (s *redisTicketStore) Revoke(ctx context.Context, revocation services.TicketRevocation) error {
+	data, err := json.Marshal(revocation)
+	if err != nil {
+		return fmt.Errorf("failed to encode revocation: %w", err)
+	}
+	return s.client.HSet(ctx, ticketRevocationsKey, revocation.ID, data).Err()
+}
+
+func (s *redisTicketStore) Revocation(ctx context.Context, id string) (*services.TicketRevocation, error) {
+	data, err := s.client.HGet(ctx, ticketRevocationsKey, id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var revocation services.TicketRevocation
+	if err := json.Unmarshal(data, &revocation); err != nil {
+		return nil, fmt.Errorf("failed to decode revocation: %w", err)
+	}
+	return &revocation, nil
+}
+
+func (s *redisTicketStore) Revocations(ctx context.Context) ([]services.TicketRevocation, error) {
+	entries, err := s.client.HGetAll(ctx, ticketRevocationsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	revocations := make([]services.TicketRevocation, 0, len(entries))
+	var expired []string
+	for id, data := range entries {
+		var revocation services.TicketRevocation
+		if err := json.Unmarshal([]byte(data), &revocation); err != nil {
+			return nil, fmt.Errorf("failed to decode revocation: %w", err)
+		}
+		if now.After(revocation.ExpiresAt) {
+			expired = append(expired, id)
+			continue
+		}
+		revocations = append(revocations, revocation)
+	}
+
+	// Revocations of expired tickets are no longer needed by anyone
+	if len(expired) > 0 {
+		s.client.HDel(ctx, ticketRevocationsKey, expired...)
+	}
+	return revocations, nil
+}
+
+func (s *redisTicketStore) ClaimCheckIn(ctx context.Context, attendeeID uuid.UUID, at, until time.Time) (bool, time.Time, error) {
+	key := ticketCheckInKeyPrefix + attendeeID.String()
+	claimed, err := s.client.SetNX(ctx, key, at.Unix(), checkInTTL(until)).Result()
+	if err != nil || claimed {
+		return claimed, at, err
+	}
+
+	first, err := s.CheckInTime(ctx, attendeeID)
+	if err != nil || first == nil {
+		return false, at, err
+	}
+	return false, *first, nil
+}
+
+func (s *redisTicketStore) CheckInTime(ctx context.Context, attendeeID uuid.UUID) (*time.Time, error) {
+	value, err := s.client.Get(ctx, ticketCheckInKeyPrefix+attendeeID.String()).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode check-in time: %w", err)
+	}
+	at := time.Unix(seconds, 0)
+	return &at, nil
+}
+
+// checkInTTL is how long a check-in claim is kept, at least ticketCheckInMinTTL
+func checkInTTL(until time.Time) time.Duration {
+	ttl := time.Until(until)
+	if ttl < ticketCheckInMinTTL {
+		return ticketCheckInMinTTL
+	}
+	return ttl
+}
+
+// memoryCheckIn is a check-in claim kept in memory until it expires
+type memoryCheckIn struct {
+	at        time.Time
+	expiresAt time.Time
+}
+
+// memoryTicketStore serves single-instance deployments without Redis
+type memoryTicketStore struct {
+	mu          sync.Mutex
+	revocations map[string]services.TicketRevocation
+	checkIns    map[uuid.UUID]memoryCheckIn
+	lastSweep   time.Time
+}
+
+func newMemoryTicketStore() *memoryTicketStore {
+	return &memoryTicketStore{
+		revocations: make(map[string]services.TicketRevocation),
+		checkIns:    make(map[uuid.UUID]memoryCheckIn),
+	}
+}
+
+func (s *memoryTicketStore) Revoke(ctx context.Context, revocation services.TicketRevocation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revocations[revocation.ID] = revocation
+	return nil
+}
+
+func (s *memoryTicketStore) Revocation(ctx context.Context, id string) (*services.TicketRevocation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revocation, ok := s.revocations[id]
+	if !ok {
+		return nil, nil
+	}
+	return &revocation, nil
+}
+
+func (s *memoryTicketStore) Revocations(ctx context.Context) ([]services.TicketRevocation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	revocations := make([]services.TicketRevocation, 0, len(s.revocations))
+	for id, revocation := range s.revocations {
+		if now.After(revocation.ExpiresAt) {
+			delete(s.revocations, id)
+			continue
+		}
+		revocations = append(revocations, revocation)
+	}
+	return revocations, nil
+}
+
+func (s *memoryTicketStore) ClaimCheckIn(ctx context.Context, attendeeID uuid.UUID, at, until time.Time) (bool, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweepCheckIns(now)
+	if first, ok := s.checkIns[attendeeID]; ok && now.Before(first.expiresAt) {
+		return false, first.at, nil
+	}
+	s.checkIns[attendeeID] = memoryCheckIn{at: at, expiresAt: now.Add(checkInTTL(until))}
+	return true, at, nil
+}
+
+func (s *memoryTicketStore) CheckInTime(ctx context.Context, attendeeID uuid.UUID) (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkIn, ok := s.checkIns[attendeeID]
+	if !ok || !time.Now().Before(checkIn.expiresAt) {
+		return nil, nil
+	}
+	return &checkIn.at, nil
+}
+
+// sweepCheckIns drops expired check-in claims, at most once a minute. The caller holds the lock.
+func (s *memoryTicketStore) sweepCheckIns(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for attendeeID, checkIn := range s.checkIns {
+		if !now.Before(checkIn.expiresAt) {
+			delete(s.checkIns, attendeeID)
+		}
+	}
+}
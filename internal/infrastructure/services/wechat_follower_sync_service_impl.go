@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WeChatFollowerSyncServiceImpl implements the WeChatFollowerSyncService interface
+type WeChatFollowerSyncServiceImpl struct {
+	syncRepo  repositories.WeChatFollowerSyncRepository
+	userRepo  repositories.WeChatUserRepository
+	followers services.WeChatFollowerSource
+	queue     services.WeChatFollowerSyncQueue
+	running   atomic.Bool
+	logger    *zap.Logger
+}
+
+// NewWeChatFollowerSyncService creates a new WeChat follower sync service implementation. Without
+// a queue syncs run in the background of the current process.
+func NewWeChatFollowerSyncService(
+	syncRepo repositories.WeChatFollowerSyncRepository,
+	userRepo repositories.WeChatUserRepository,
+	followers services.WeChatFollowerSource,
+	queue services.WeChatFollowerSyncQueue,
+	logger *zap.Logger,
+) services.WeChatFollowerSyncService {
+	return &WeChatFollowerSyncServiceImpl{
+		syncRepo:  syncRepo,
+		userRepo:  userRepo,
+		followers: followers,
+		queue:     queue,
+		logger:    logger,
+	}
+}
+
+// StartSync queues the running sync, so an interrupted sync resumes from its checkpoint, or a new
+// sync when none is running
+func (s *WeChatFollowerSyncServiceImpl) StartSync(ctx context.Context) (*entities.WeChatFollowerSync, error) {
+	followerSync, err := s.syncRepo.FindRunning(ctx)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+		followerSync = &entities.WeChatFollowerSync{
+			ID:        uuid.New(),
+			Status:    entities.WeChatFollowerSyncStatusRunning,
+			StartedAt: time.Now(),
+		}
+		if err := s.syncRepo.Create(ctx, followerSync); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.queue == nil {
+		go s.runInBackground(followerSync.ID)
+		return followerSync, nil
+	}
+	if err := s.queue.EnqueueWeChatFollowerSync(ctx, followerSync.ID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat follower sync queued",
+		zap.String("syncId", followerSync.ID.String()),
+		zap.Int("processed", followerSync.Processed))
+	return followerSync, nil
+}
+
+// RunSync walks the follower list from the checkpoint of a sync, upserting the profile of each
+// follower and checkpointing after each batch. Once the list is walked, the users still marked
+// as subscribed that the sync did not update are marked as unfollowed. A failed run keeps the
+// sync running with the error recorded, so running it again resumes from the checkpoint.
+func (s *WeChatFollowerSyncServiceImpl) RunSync(ctx context.Context, id uuid.UUID) error {
+	if !s.running.CompareAndSwap(false, true) {
+		s.logger.Info("WeChat follower sync already running, skipping", zap.String("syncId", id.String()))
+		return nil
+	}
+	defer s.running.Store(false)
+
+	followerSync, err := s.syncRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !followerSync.IsRunning() {
+		return nil
+	}
+
+	s.logger.Info("Syncing WeChat followers",
+		zap.String("syncId", followerSync.ID.String()),
+		zap.String("nextOpenId", followerSync.NextOpenID),
+		zap.Int("processed", followerSync.Processed))
+
+	for {
+		page, err := s.followers.GetFollowers(ctx, followerSync.NextOpenID)
+		if err != nil {
+			return s.interrupt(ctx, followerSync, fmt.Errorf("failed to get WeChat followers: %w", err))
+		}
+		followerSync.Total = page.Total
+
+		openIDs := followerSync.Remaining(page.Data)
+		for start := 0; start < len(openIDs); start += wechat.MaxUserInfoBatch {
+			batch := openIDs[start:min(start+wechat.MaxUserInfoBatch, len(openIDs))]
+			created, updated, err := s.syncBatch(ctx, batch)
+			if err != nil {
+				return s.interrupt(ctx, followerSync, err)
+			}
+			followerSync.Created += created
+			followerSync.Updated += updated
+			followerSync.Checkpoint(batch[len(batch)-1], len(batch))
+			if err := s.syncRepo.Update(ctx, followerSync); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Data) == 0 || page.NextOpenID == "" {
+			break
+		}
+		followerSync.NextPage(page.NextOpenID)
+		if err := s.syncRepo.Update(ctx, followerSync); err != nil {
+			return err
+		}
+	}
+
+	unfollowed, err := s.userRepo.UnsubscribeNotUpdatedSince(ctx, followerSync.StartedAt)
+	if err != nil {
+		return s.interrupt(ctx, followerSync, fmt.Errorf("failed to mark unfollowed WeChat users: %w", err))
+	}
+	followerSync.Complete(int(unfollowed), time.Now())
+	if err := s.syncRepo.Update(ctx, followerSync); err != nil {
+		return err
+	}
+
+	s.logger.Info("WeChat follower sync completed",
+		zap.String("syncId", followerSync.ID.String()),
+		zap.Int("total", followerSync.Total),
+		zap.Int("created", followerSync.Created),
+		zap.Int("updated", followerSync.Updated),
+		zap.Int("unfollowed", followerSync.Unfollowed))
+	return nil
+}
+
+// GetSync retrieves a follower sync
+func (s *WeChatFollowerSyncServiceImpl) GetSync(ctx context.Context, id uuid.UUID) (*entities.WeChatFollowerSync, error) {
+	return s.syncRepo.FindByID(ctx, id)
+}
+
+// GetRecentSyncs lists the syncs started last, latest first
+func (s *WeChatFollowerSyncServiceImpl) GetRecentSyncs(ctx context.Context, limit int) ([]entities.WeChatFollowerSync, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.syncRepo.FindRecent(ctx, limit)
+}
+
+// syncBatch fetches the profiles of a batch of followers and upserts their records
+func (s *WeChatFollowerSyncServiceImpl) syncBatch(ctx context.Context, openIDs []string) (created, updated int, err error) {
+	infos, err := s.followers.BatchGetUserInfo(ctx, openIDs)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get WeChat user info: %w", err)
+	}
+
+	for _, info := range infos {
+		user, err := s.userRepo.GetByOpenID(ctx, info.OpenID)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return created, updated, fmt.Errorf("failed to get WeChat user: %w", err)
+			}
+			user = &entities.WeChatUser{OpenID: info.OpenID}
+			applyWeChatUserInfo(user, info)
+			if err := s.userRepo.Create(ctx, user); err != nil {
+				return created, updated, fmt.Errorf("failed to create WeChat user: %w", err)
+			}
+			created++
+			continue
+		}
+
+		applyWeChatUserInfo(user, info)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return created, updated, fmt.Errorf("failed to update WeChat user: %w", err)
+		}
+		updated++
+	}
+	return created, updated, nil
+}
+
+// interrupt records the error a run stopped on and returns it
+func (s *WeChatFollowerSyncServiceImpl) interrupt(ctx context.Context, followerSync *entities.WeChatFollowerSync, cause error) error {
+	followerSync.LastError = cause.Error()
+	if err := s.syncRepo.Update(ctx, followerSync); err != nil {
+		s.logger.Error("Failed to record WeChat follower sync error",
+			zap.String("syncId", followerSync.ID.String()),
+			zap.Error(err))
+	}
+	return cause
+}
+
+// runInBackground runs a sync outside of the request that started it
+func (s *WeChatFollowerSyncServiceImpl) runInBackground(id uuid.UUID) {
+	if err := s.RunSync(context.Background(), id); err != nil {
+		s.logger.Error("Failed to sync WeChat followers", zap.String("syncId", id.String()), zap.Error(err))
+	}
+}
+
+// applyWeChatUserInfo copies a follower profile onto the user record. WeChat only returns the
+// OpenID of users who unfollowed and no longer returns nicknames and avatars to most accounts, so
+// empty fields keep the values already recorded.
+func applyWeChatUserInfo(user *entities.WeChatUser, info services.WeChatUserInfo) {
+	user.Subscribe = info.Subscribe == 1
+	if !user.Subscribe {
+		return
+	}
+
+	if info.Nickname != "" {
+		user.NickName = info.Nickname
+	}
+	if info.SubscribeTime > 0 {
+		subscribedAt := time.Unix(info.SubscribeTime, 0)
+		user.SubscribeTime = &subscribedAt
+	}
+	if info.Sex != 0 {
+		user.Sex = info.Sex
+	}
+	setProfileField(&user.UnionID, info.UnionID)
+	setProfileField(&user.City, info.City)
+	setProfileField(&user.Country, info.Country)
+	setProfileField(&user.Province, info.Province)
+	setProfileField(&user.Language, info.Language)
+	setProfileField(&user.HeadImgUrl, info.HeadImgURL)
+	setProfileField(&user.Remark, info.Remark)
+}
+
+func setProfileField(field **string, value string) {
+	if value != "" {
+		*field = &value
+	}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *memoryWeChatUserRepository) GetByOpenID(ctx context.Context, openID string) (*entities.WeChatUser, error) {
+	for _, user := range r.users {
+		if user.OpenID == openID {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryWeChatUserRepository) Create(ctx context.Context, user *entities.WeChatUser) error {
+	now := time.Now()
+	user.ID = uuid.New()
+	user.CreatedAt = now
+	user.UpdatedAt = &now
+	copied := *user
+	r.users = append(r.users, &copied)
+	return nil
+}
+
+func (r *memoryWeChatUserRepository) Update(ctx context.Context, user *entities.WeChatUser) error {
+	now := time.Now()
+	user.UpdatedAt = &now
+	for i := range r.users {
+		if r.users[i].ID == user.ID {
+			copied := *user
+			r.users[i] = &copied
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryWeChatUserRepository) UnsubscribeNotUpdatedSince(ctx context.Context, since time.Time) (int64, error) {
+	var marked int64
+	for _, user := range r.users {
+		if user.Subscribe && (user.UpdatedAt == nil || user.UpdatedAt.Before(since)) {
+			user.Subscribe = false
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// memoryWeChatFollowerSyncRepository keeps follower syncs in memory in the order they started
+type memoryWeChatFollowerSyncRepository struct {
+	syncs []entities.WeChatFollowerSync
+}
+
+func (r *memoryWeChatFollowerSyncRepository) Create(ctx context.Context, followerSync *entities.WeChatFollowerSync) error {
+	r.syncs = append(r.syncs, *followerSync)
+	return nil
+}
+
+func (r *memoryWeChatFollowerSyncRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatFollowerSync, error) {
+	for _, followerSync := range r.syncs {
+		if followerSync.ID == id {
+			return &followerSync, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatFollowerSyncRepository) Update(ctx context.Context, followerSync *entities.WeChatFollowerSync) error {
+	for i := range r.syncs {
+		if r.syncs[i].ID == followerSync.ID {
+			r.syncs[i] = *followerSync
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memoryWeChatFollowerSyncRepository) FindRunning(ctx context.Context) (*entities.WeChatFollowerSync, error) {
+	for i := len(r.syncs) - 1; i >= 0; i-- {
+		if r.syncs[i].IsRunning() {
+			followerSync := r.syncs[i]
+			return &followerSync, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatFollowerSyncRepository) FindRecent(ctx context.Context, limit int) ([]entities.WeChatFollowerSync, error) {
+	var syncs []entities.WeChatFollowerSync
+	for i := len(r.syncs) - 1; i >= 0 && len(syncs) < limit; i-- {
+		syncs = append(syncs, r.syncs[i])
+	}
+	return syncs, nil
+}
+
+// fakeWeChatFollowerSyncQueue records the queued syncs
+type fakeWeChatFollowerSyncQueue struct {
+	queued []uuid.UUID
+}
+
+func (q *fakeWeChatFollowerSyncQueue) EnqueueWeChatFollowerSync(ctx context.Context, syncID uuid.UUID) error {
+	q.queued = append(q.queued, syncID)
+	return nil
+}
+
+// fakeWeChatUserAPI serves the WeChat follower list and profile APIs
+type fakeWeChatUserAPI struct {
+	mu         sync.Mutex
+	followers  []string
+	unfollowed map[string]bool // Listed followers whose profiles report they unfollowed
+	pageSize   int
+	batchCalls int
+	failBatch  int // Batch profile request answered with an error
+	fetched    map[string]int
+}
+
+func (f *fakeWeChatUserAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/cgi-bin/token" && r.URL.Query().Get("access_token") != "token" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 41001, "errmsg": "access_token missing"})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/cgi-bin/token":
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 7200})
+	case "/cgi-bin/user/get":
+		start := 0
+		if next := r.URL.Query().Get("next_openid"); next != "" {
+			start = slices.Index(f.followers, next) + 1
+		}
+		page := f.followers[start:min(start+f.pageSize, len(f.followers))]
+		response := map[string]interface{}{"total": len(f.followers), "count": len(page), "next_openid": ""}
+		if len(page) > 0 {
+			response["data"] = map[string]interface{}{"openid": page}
+			response["next_openid"] = page[len(page)-1]
+		}
+		json.NewEncoder(w).Encode(response)
+	case "/cgi-bin/user/info/batchget":
+		f.batchCalls++
+		if f.batchCalls == f.failBatch {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": -1, "errmsg": "system error"})
+			return
+		}
+
+		var request struct {
+			UserList []struct {
+				OpenID string `json:"openid"`
+			} `json:"user_list"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		var profiles []wechat.UserInfo
+		for _, user := range request.UserList {
+			f.fetched[user.OpenID]++
+			if f.unfollowed[user.OpenID] {
+				profiles = append(profiles, wechat.UserInfo{OpenID: user.OpenID})
+				continue
+			}
+			profiles = append(profiles, wechat.UserInfo{
+				Subscribe:     1,
+				OpenID:        user.OpenID,
+				Nickname:      "Nick " + user.OpenID,
+				UnionID:       "union-" + user.OpenID,
+				City:          "Shanghai",
+				SubscribeTime: 1700000000,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"user_info_list": profiles})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type WeChatFollowerSyncServiceTestSuite struct {
+	suite.Suite
+	api     *fakeWeChatUserAPI
+	server  *httptest.Server
+	users   *memoryWeChatUserRepository
+	syncs   *memoryWeChatFollowerSyncRepository
+	queue   *fakeWeChatFollowerSyncQueue
+	service *WeChatFollowerSyncServiceImpl
+	ctx     context.Context
+}
+
+func (suite *WeChatFollowerSyncServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.api = &fakeWeChatUserAPI{unfollowed: map[string]bool{}, pageSize: 200, fetched: map[string]int{}}
+	for i := 1; i <= 250; i++ {
+		suite.api.followers = append(suite.api.followers, fmt.Sprintf("openid-%03d", i))
+	}
+	suite.server = httptest.NewServer(suite.api)
+
+	apiService := wechat.NewService(&wechat.Config{AppID: "wx123", AppSecret: "secret", APIBaseURL: suite.server.URL}, nil, zap.NewNop())
+	suite.users = &memoryWeChatUserRepository{}
+	suite.syncs = &memoryWeChatFollowerSyncRepository{}
+	suite.queue = &fakeWeChatFollowerSyncQueue{}
+	suite.service = NewWeChatFollowerSyncService(
+		suite.syncs,
+		suite.users,
+		NewWeChatServiceImpl(apiService, suite.users, nil, zap.NewNop()),
+		suite.queue,
+		zap.NewNop(),
+	).(*WeChatFollowerSyncServiceImpl)
+}
+
+func (suite *WeChatFollowerSyncServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *WeChatFollowerSyncServiceTestSuite) user(openID string) *entities.WeChatUser {
+	user, err := suite.users.GetByOpenID(suite.ctx, openID)
+	suite.Require().NoError(err)
+	return user
+}
+
+func (suite *WeChatFollowerSyncServiceTestSuite) TestSyncResumesFromCheckpoint() {
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	realName := "Li Lei"
+	suite.users.users = []*entities.WeChatUser{
+		{ID: uuid.New(), OpenID: "openid-005", Subscribe: true, NickName: "Old", RealName: &realName, UpdatedAt: &lastWeek},
+		{ID: uuid.New(), OpenID: "openid-gone", Subscribe: true, UpdatedAt: &lastWeek},
+	}
+	suite.api.unfollowed["openid-007"] = true
+	suite.api.failBatch = 2
+
+	started, err := suite.service.StartSync(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]uuid.UUID{started.ID}, suite.queue.queued)
+
+	// The second batch of the first page fails after the first one was checkpointed
+	suite.Error(suite.service.RunSync(suite.ctx, started.ID))
+	interrupted, err := suite.service.GetSync(suite.ctx, started.ID)
+	suite.Require().NoError(err)
+	suite.True(interrupted.IsRunning())
+	suite.Equal(100, interrupted.Processed)
+	suite.Equal("openid-100", interrupted.LastOpenID)
+	suite.Contains(interrupted.LastError, "system error")
+
+	// A follower of the synced part of the page unfollows, shifting the rest of the page
+	suite.api.followers = slices.Delete(suite.api.followers, 9, 10)
+
+	resumed, err := suite.service.StartSync(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(started.ID, resumed.ID)
+	suite.Require().NoError(suite.service.RunSync(suite.ctx, started.ID))
+
+	completed, err := suite.service.GetSync(suite.ctx, started.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatFollowerSyncStatusCompleted, completed.Status)
+	suite.NotNil(completed.FinishedAt)
+	suite.Empty(completed.LastError)
+	suite.Equal(249, completed.Total)
+	suite.Equal(250, completed.Processed, "the unfollower was synced before the interruption")
+	suite.Equal(249, completed.Created)
+	suite.Equal(1, completed.Updated)
+	suite.Equal(1, completed.Unfollowed)
+	for _, openID := range suite.api.followers {
+		suite.Equal(1, suite.api.fetched[openID], openID)
+	}
+
+	existing := suite.user("openid-005")
+	suite.True(existing.Subscribe)
+	suite.Equal("Nick openid-005", existing.NickName)
+	suite.Equal(&realName, existing.RealName)
+	suite.Equal("union-openid-005", *existing.UnionID)
+	suite.Equal(int64(1700000000), existing.SubscribeTime.Unix())
+	suite.False(suite.user("openid-007").Subscribe, "profiles report followers who unfollowed while syncing")
+	suite.False(suite.user("openid-gone").Subscribe)
+	suite.True(suite.user("openid-250").Subscribe)
+}
+
+func (suite *WeChatFollowerSyncServiceTestSuite) TestCompletedSyncsAreNotRunAgain() {
+	first, err := suite.service.StartSync(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.service.RunSync(suite.ctx, first.ID))
+	suite.Equal(3, suite.api.batchCalls)
+
+	suite.Require().NoError(suite.service.RunSync(suite.ctx, first.ID))
+	suite.Equal(3, suite.api.batchCalls)
+
+	second, err := suite.service.StartSync(suite.ctx)
+	suite.Require().NoError(err)
+	suite.NotEqual(first.ID, second.ID)
+	suite.Require().NoError(suite.service.RunSync(suite.ctx, second.ID))
+
+	syncs, err := suite.service.GetRecentSyncs(suite.ctx, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(syncs, 2)
+	suite.Equal(second.ID, syncs[0].ID)
+	suite.Equal(250, syncs[0].Updated)
+	suite.Equal(0, syncs[0].Unfollowed)
+
+	_, err = suite.service.GetSync(suite.ctx, uuid.New())
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func TestWeChatFollowerSyncServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeChatFollowerSyncServiceTestSuite))
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatMassMessageServiceImpl implements the WeChatMassMessageService interface
+type WeChatMassMessageServiceImpl struct {
+	messageRepo    repositories.WeChatMassMessageRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	sender         services.WeChatMassSender
+	logger         *zap.Logger
+}
+
+// NewWeChatMassMessageService creates a new WeChat mass message service implementation
+func NewWeChatMassMessageService(
+	messageRepo repositories.WeChatMassMessageRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	sender services.WeChatMassSender,
+	logger *zap.Logger,
+) *WeChatMassMessageServiceImpl {
+	return &WeChatMassMessageServiceImpl{
+		messageRepo:    messageRepo,
+		wechatUserRepo: wechatUserRepo,
+		sender:         sender,
+		logger:         logger,
+	}
+}
+
+// RegisterHandlers records the outcome of the mass messages WeChat reports as finished
+func (s *WeChatMassMessageServiceImpl) RegisterHandlers(router *wechat.MessageRouter) {
+	router.HandleEvent(message.EventMassSendJobFinish, s.handleMassSendJobFinish)
+}
+
+// CreateMessage validates a message and records it as a draft
+func (s *WeChatMassMessageServiceImpl) CreateMessage(ctx context.Context, msg *entities.WeChatMassMessage) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	if err := msg.SetOpenIDs(msg.OpenIDs); err != nil {
+		return err
+	}
+	msg.ID = uuid.New()
+	msg.Status = entities.WeChatMassMessageStatusDraft
+	return s.messageRepo.Create(ctx, msg)
+}
+
+// GetMessage gets a mass message
+func (s *WeChatMassMessageServiceImpl) GetMessage(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error) {
+	return s.messageRepo.FindByID(ctx, id)
+}
+
+// GetRecentMessages gets the messages created last
+func (s *WeChatMassMessageServiceImpl) GetRecentMessages(ctx context.Context, limit int) ([]entities.WeChatMassMessage, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.messageRepo.FindRecent(ctx, limit)
+}
+
+// Preview sends a message to each subscribed follower marked as a test user
+func (s *WeChatMassMessageServiceImpl) Preview(ctx context.Context, id uuid.UUID) (int, error) {
+	msg, err := s.messageRepo.FindByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	subscribed, allowTest := true, true
+	testUsers, err := s.wechatUserRepo.GetAll(ctx, repositories.WeChatUserFilter{Subscribe: &subscribed, AllowTest: &allowTest})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get WeChat test users: %w", err)
+	}
+	if len(testUsers) == 0 {
+		return 0, services.ErrNoWeChatTestUsers
+	}
+
+	content := toMassMessage(msg)
+	previewed := 0
+	for _, user := range testUsers {
+		if err := s.sender.PreviewMass(ctx, user.OpenID, content); err != nil {
+			return previewed, fmt.Errorf("failed to preview mass message to %s: %w", user.OpenID, err)
+		}
+		previewed++
+	}
+
+	now := time.Now()
+	msg.PreviewedAt = &now
+	if err := s.messageRepo.Update(ctx, msg); err != nil {
+		return previewed, err
+	}
+	return previewed, nil
+}
+
+// Send broadcasts a message to its tag or followers. The message is claimed before calling WeChat
+// so that concurrent requests send it once, and carries its ID as clientmsgid so that WeChat drops
+// a repeated broadcast. Messages WeChat rejects are recorded as failed, and may be sent again.
+func (s *WeChatMassMessageServiceImpl) Send(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error) {
+	msg, err := s.messageRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.CanSend() {
+		return nil, services.ErrWeChatMassMessageSent
+	}
+	claimed, err := s.messageRepo.ClaimForSending(ctx, msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, services.ErrWeChatMassMessageSent
+	}
+	msg.Status = entities.WeChatMassMessageStatusSending
+
+	content := toMassMessage(msg)
+	content.ClientMsgID = msg.ID.String()
+	var result *services.MassSendResult
+	if msg.TagID != nil {
+		result, err = s.sender.SendMassByTag(ctx, *msg.TagID, content)
+	} else {
+		result, err = s.sender.SendMassByOpenIDs(ctx, msg.OpenIDs, content)
+	}
+	if err != nil {
+		msg.Fail(err.Error(), time.Now())
+		if updateErr := s.messageRepo.Update(ctx, msg); updateErr != nil {
+			s.logger.Error("Failed to record failed WeChat mass message",
+				zap.String("messageId", msg.ID.String()),
+				zap.Error(updateErr))
+		}
+		return nil, err
+	}
+
+	msg.Sending(result.MsgID, result.MsgDataID, time.Now())
+	if err := s.messageRepo.Update(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat mass message sent",
+		zap.String("messageId", msg.ID.String()),
+		zap.Int64("msgId", msg.MsgID))
+	return msg, nil
+}
+
+// RefreshStatus polls WeChat for the status of a sent message, for when the MASSSENDJOBFINISH
+// event was missed
+func (s *WeChatMassMessageServiceImpl) RefreshStatus(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error) {
+	msg, err := s.messageRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.MsgID == 0 {
+		return nil, services.ErrWeChatMassMessageNotSent
+	}
+
+	status, err := s.sender.GetMassStatus(ctx, msg.MsgID)
+	if err != nil {
+		return nil, err
+	}
+	msg.ApplyPolledStatus(status, time.Now())
+	if err := s.messageRepo.Update(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// handleMassSendJobFinish records the delivery counts WeChat pushes when a mass message job
+// finishes. The msg_id of mass messages is pushed as MsgID, like that of template messages.
+func (s *WeChatMassMessageServiceImpl) handleMassSendJobFinish(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	massMsg, err := s.messageRepo.FindByMsgID(ctx, msg.TemplateMsgID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			s.logger.Warn("WeChat mass message job finished for unknown message", zap.Int64("msgId", msg.TemplateMsgID))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	massMsg.Finish(msg.Status, int(msg.TotalCount), int(msg.FilterCount), int(msg.SentCount), int(msg.ErrorCount), time.Now())
+	if err := s.messageRepo.Update(ctx, massMsg); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat mass message job finished",
+		zap.String("messageId", massMsg.ID.String()),
+		zap.String("status", msg.Status),
+		zap.Int64("sentCount", msg.SentCount),
+		zap.Int64("errorCount", msg.ErrorCount))
+	return nil, nil
+}
+
+// toMassMessage converts the content of a mass message for sending
+func toMassMessage(msg *entities.WeChatMassMessage) *services.MassMessage {
+	return &services.MassMessage{MsgType: msg.MsgType, MediaID: msg.MediaID, Content: msg.Content}
+}
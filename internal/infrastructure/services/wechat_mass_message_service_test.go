@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// memoryWeChatMassMessageRepository keeps mass messages in memory in creation order
+type memoryWeChatMassMessageRepository struct {
+	messages []entities.WeChatMassMessage
+}
+
+func (r *memoryWeChatMassMessageRepository) Create(ctx context.Context, msg *entities.WeChatMassMessage) error {
+	msg.ID = uuid.New()
+	r.messages = append(r.messages, *msg)
+	return nil
+}
+
+func (r *memoryWeChatMassMessageRepository) find(match func(*entities.WeChatMassMessage) bool) (*entities.WeChatMassMessage, error) {
+	for i := range r.messages {
+		if match(&r.messages[i]) {
+			msg := r.messages[i]
+			return &msg, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMassMessageRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatMassMessage, error) {
+	return r.find(func(msg *entities.WeChatMassMessage) bool { return msg.ID == id })
+}
+
+func (r *memoryWeChatMassMessageRepository) FindByMsgID(ctx context.Context, msgID int64) (*entities.WeChatMassMessage, error) {
+	return r.find(func(msg *entities.WeChatMassMessage) bool { return msg.MsgID == msgID })
+}
+
+func (r *memoryWeChatMassMessageRepository) Update(ctx context.Context, msg *entities.WeChatMassMessage) error {
+	for i := range r.messages {
+		if r.messages[i].ID == msg.ID {
+			r.messages[i] = *msg
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMassMessageRepository) ClaimForSending(ctx context.Context, id uuid.UUID) (bool, error) {
+	for i := range r.messages {
+		if r.messages[i].ID == id && r.messages[i].CanSend() {
+			r.messages[i].Status = entities.WeChatMassMessageStatusSending
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryWeChatMassMessageRepository) FindRecent(ctx context.Context, limit int) ([]entities.WeChatMassMessage, error) {
+	var messages []entities.WeChatMassMessage
+	for i := len(r.messages) - 1; i >= 0 && len(messages) < limit; i-- {
+		messages = append(messages, r.messages[i])
+	}
+	return messages, nil
+}
+
+// fakeWeChatMassAPI serves the WeChat mass message APIs
+type fakeWeChatMassAPI struct {
+	mu        sync.Mutex
+	sent      []map[string]interface{} // Bodies of the send requests
+	previewed []string
+	status    string
+	failSend  bool
+}
+
+func (f *fakeWeChatMassAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/cgi-bin/token" && r.URL.Query().Get("access_token") != "token" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 41001, "errmsg": "access_token missing"})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/cgi-bin/token":
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 7200})
+	case "/cgi-bin/message/mass/sendall", "/cgi-bin/message/mass/send":
+		if f.failSend {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 45065, "errmsg": "clientmsgid exist"})
+			return
+		}
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		request["path"] = r.URL.Path
+		f.sent = append(f.sent, request)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msg_id": 1000 + len(f.sent), "msg_data_id": 2000 + len(f.sent)})
+	case "/cgi-bin/message/mass/preview":
+		var request struct {
+			ToUser string `json:"touser"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		f.previewed = append(f.previewed, request.ToUser)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "errmsg": "preview success"})
+	case "/cgi-bin/message/mass/get":
+		json.NewEncoder(w).Encode(map[string]interface{}{"msg_id": 1001, "msg_status": f.status})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type WeChatMassMessageServiceTestSuite struct {
+	suite.Suite
+	api      *fakeWeChatMassAPI
+	server   *httptest.Server
+	users    *memoryWeChatUserRepository
+	messages *memoryWeChatMassMessageRepository
+	router   *wechat.MessageRouter
+	service  *WeChatMassMessageServiceImpl
+	ctx      context.Context
+}
+
+func (suite *WeChatMassMessageServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.api = &fakeWeChatMassAPI{}
+	suite.server = httptest.NewServer(suite.api)
+
+	apiService := wechat.NewService(&wechat.Config{AppID: "wx123", AppSecret: "secret", APIBaseURL: suite.server.URL}, nil, zap.NewNop())
+	suite.users = &memoryWeChatUserRepository{}
+	suite.messages = &memoryWeChatMassMessageRepository{}
+	suite.service = NewWeChatMassMessageService(
+		suite.messages,
+		suite.users,
+		NewWeChatServiceImpl(apiService, suite.users, nil, zap.NewNop()),
+		zap.NewNop(),
+	)
+	suite.router = wechat.NewMessageRouter()
+	suite.service.RegisterHandlers(suite.router)
+}
+
+func (suite *WeChatMassMessageServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *WeChatMassMessageServiceTestSuite) TestSendToTagRecordsJobOutcome() {
+	tagID := 100
+	msg := &entities.WeChatMassMessage{Title: "Conference recap", MsgType: entities.WeChatMassMessageTypeNews, MediaID: "news-1", TagID: &tagID}
+	suite.Require().NoError(suite.service.CreateMessage(suite.ctx, msg))
+	suite.Equal(entities.WeChatMassMessageStatusDraft, msg.Status)
+
+	sent, err := suite.service.Send(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMassMessageStatusSending, sent.Status)
+	suite.Equal(int64(1001), sent.MsgID)
+	suite.Require().Len(suite.api.sent, 1)
+	request := suite.api.sent[0]
+	suite.Equal("/cgi-bin/message/mass/sendall", request["path"])
+	suite.Equal(map[string]interface{}{"is_to_all": false, "tag_id": float64(100)}, request["filter"])
+	suite.Equal(map[string]interface{}{"media_id": "news-1"}, request["mpnews"])
+	suite.Equal(float64(0), request["send_ignore_reprint"])
+	suite.Equal(msg.ID.String(), request["clientmsgid"])
+
+	// A sent message is not sent again
+	_, err = suite.service.Send(suite.ctx, msg.ID)
+	suite.ErrorIs(err, services.ErrWeChatMassMessageSent)
+
+	// Only the request that claims a message sends it
+	other := &entities.WeChatMassMessage{Title: "Agenda", MsgType: entities.WeChatMassMessageTypeNews, MediaID: "news-2", TagID: &tagID}
+	suite.Require().NoError(suite.service.CreateMessage(suite.ctx, other))
+	claimed, err := suite.messages.ClaimForSending(suite.ctx, other.ID)
+	suite.Require().NoError(err)
+	suite.True(claimed)
+	_, err = suite.service.Send(suite.ctx, other.ID)
+	suite.ErrorIs(err, services.ErrWeChatMassMessageSent)
+	suite.Len(suite.api.sent, 1)
+
+	// WeChat pushes the outcome when the job finishes
+	finished := &message.MixMessage{Event: message.EventMassSendJobFinish, TemplateMsgID: 1001, Status: "send success",
+		TotalCount: 120, FilterCount: 118, SentCount: 117, ErrorCount: 1}
+	finished.MsgType = message.MsgTypeEvent
+	reply, err := suite.router.Route(suite.ctx, finished)
+	suite.Require().NoError(err)
+	suite.Nil(reply)
+
+	recorded, err := suite.service.GetMessage(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMassMessageStatusSent, recorded.Status)
+	suite.Equal(118, recorded.FilterCount)
+	suite.Equal(117, recorded.SentCount)
+	suite.Equal(1, recorded.ErrorCount)
+	suite.NotNil(recorded.FinishedAt)
+}
+
+func (suite *WeChatMassMessageServiceTestSuite) TestSendToFollowersAndRefreshStatus() {
+	msg := &entities.WeChatMassMessage{Title: "Reminder", MsgType: entities.WeChatMassMessageTypeText, Content: "See you tomorrow",
+		OpenIDs: []string{"openid-1", "openid-2"}}
+	suite.Require().NoError(suite.service.CreateMessage(suite.ctx, msg))
+
+	_, err := suite.service.RefreshStatus(suite.ctx, msg.ID)
+	suite.ErrorIs(err, services.ErrWeChatMassMessageNotSent)
+
+	// Messages WeChat rejects are recorded as failed and may be sent again
+	suite.api.failSend = true
+	_, err = suite.service.Send(suite.ctx, msg.ID)
+	var apiErr *wechat.APIError
+	suite.ErrorAs(err, &apiErr)
+	failed, err := suite.service.GetMessage(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMassMessageStatusFailed, failed.Status)
+	suite.Contains(failed.LastError, "clientmsgid exist")
+
+	suite.api.failSend = false
+	_, err = suite.service.Send(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	request := suite.api.sent[0]
+	suite.Equal("/cgi-bin/message/mass/send", request["path"])
+	suite.Equal([]interface{}{"openid-1", "openid-2"}, request["touser"])
+	suite.Equal(map[string]interface{}{"content": "See you tomorrow"}, request["text"])
+
+	suite.api.status = "SENDING"
+	refreshed, err := suite.service.RefreshStatus(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMassMessageStatusSending, refreshed.Status)
+
+	suite.api.status = "SEND_SUCCESS"
+	refreshed, err = suite.service.RefreshStatus(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMassMessageStatusSent, refreshed.Status)
+	suite.Equal("SEND_SUCCESS", refreshed.WeChatStatus)
+}
+
+func (suite *WeChatMassMessageServiceTestSuite) TestPreviewSendsToSubscribedTestUsers() {
+	tagID := 100
+	msg := &entities.WeChatMassMessage{Title: "Welcome", MsgType: entities.WeChatMassMessageTypeImage, MediaID: "image-1", TagID: &tagID}
+	suite.Require().NoError(suite.service.CreateMessage(suite.ctx, msg))
+
+	_, err := suite.service.Preview(suite.ctx, msg.ID)
+	suite.ErrorIs(err, services.ErrNoWeChatTestUsers)
+
+	suite.users.users = []*entities.WeChatUser{
+		{ID: uuid.New(), OpenID: "openid-tester", Subscribe: true, AllowTest: true},
+		{ID: uuid.New(), OpenID: "openid-unfollowed-tester", AllowTest: true},
+		{ID: uuid.New(), OpenID: "openid-follower", Subscribe: true},
+	}
+	previewed, err := suite.service.Preview(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.Equal(1, previewed)
+	suite.Equal([]string{"openid-tester"}, suite.api.previewed)
+
+	recorded, err := suite.service.GetMessage(suite.ctx, msg.ID)
+	suite.Require().NoError(err)
+	suite.NotNil(recorded.PreviewedAt)
+	suite.Equal(entities.WeChatMassMessageStatusDraft, recorded.Status)
+}
+
+func TestWeChatMassMessageServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeChatMassMessageServiceTestSuite))
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WeChatMediaServiceImpl implements the WeChatMediaService interface
+type WeChatMediaServiceImpl struct {
+	mediaRepo     repositories.WeChatMediaRepository
+	siteImageRepo repositories.SiteImageRepository
+	store         services.WeChatMediaStore
+	storage       storage.StorageProvider
+	logger        *zap.Logger
+}
+
+// NewWeChatMediaService creates a new WeChat media service implementation
+func NewWeChatMediaService(
+	mediaRepo repositories.WeChatMediaRepository,
+	siteImageRepo repositories.SiteImageRepository,
+	store services.WeChatMediaStore,
+	storageProvider storage.StorageProvider,
+	logger *zap.Logger,
+) *WeChatMediaServiceImpl {
+	return &WeChatMediaServiceImpl{
+		mediaRepo:     mediaRepo,
+		siteImageRepo: siteImageRepo,
+		store:         store,
+		storage:       storageProvider,
+		logger:        logger,
+	}
+}
+
+// Upload uploads a file unless media of the same type and content is still available on WeChat,
+// in which case that media is returned, mapped to the site image of the upload if it has one
+func (s *WeChatMediaServiceImpl) Upload(ctx context.Context, upload *services.WeChatMediaUpload) (*entities.WeChatMedia, error) {
+	media := &entities.WeChatMedia{
+		ID:          uuid.New(),
+		SiteImageID: upload.SiteImageID,
+		MediaType:   upload.MediaType,
+		Permanent:   upload.Permanent,
+		ContentHash: entities.HashWeChatMediaContent(upload.Data),
+		FileName:    filepath.Base(upload.FileName),
+		FileSize:    int64(len(upload.Data)),
+	}
+	if err := media.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	existing, err := s.mediaRepo.FindByContentHash(ctx, media.ContentHash, media.MediaType, media.Permanent)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && existing.IsAvailable(now) {
+		return s.reuse(ctx, existing, upload.SiteImageID)
+	}
+
+	if media.Permanent {
+		info, err := s.store.UploadPermanentMediaFromBytes(ctx, media.MediaType, media.FileName, upload.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload permanent WeChat media: %w", err)
+		}
+		media.Uploaded(info.MediaID, info.URL, now)
+	} else {
+		info, err := s.store.UploadMediaFromBytes(ctx, media.MediaType, media.FileName, upload.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload WeChat media: %w", err)
+		}
+		media.Uploaded(info.MediaID, "", now)
+	}
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat media uploaded",
+		zap.String("mediaId", media.MediaID),
+		zap.String("mediaType", media.MediaType),
+		zap.Bool("permanent", media.Permanent),
+		zap.String("fileName", media.FileName))
+	return media, nil
+}
+
+// UploadSiteImage uploads a site image as a permanent image unless media is already mapped to it
+// or to an image with the same content
+func (s *WeChatMediaServiceImpl) UploadSiteImage(ctx context.Context, siteImageID uuid.UUID) (*entities.WeChatMedia, error) {
+	media, err := s.mediaRepo.FindBySiteImage(ctx, siteImageID, entities.WeChatMediaTypeImage, true)
+	if err == nil {
+		return media, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	image, err := s.siteImageRepo.GetByID(ctx, siteImageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get site image: %w", err)
+	}
+	reader, err := s.storage.Download(ctx, image.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download site image: %w", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read site image: %w", err)
+	}
+
+	return s.Upload(ctx, &services.WeChatMediaUpload{
+		MediaType:   entities.WeChatMediaTypeImage,
+		Permanent:   true,
+		FileName:    image.Filename,
+		Data:        data,
+		SiteImageID: &siteImageID,
+	})
+}
+
+// UploadImage uploads an article image file as a permanent image unless the same image was
+// uploaded before, so the content preprocessor does not upload covers on every publish. Images
+// with a UUID are mapped to the media.
+func (s *WeChatMediaServiceImpl) UploadImage(ctx context.Context, imageID, filePath string) (*wechat.MaterialUploadResponse, error) {
+	var siteImageID *uuid.UUID
+	if id, err := uuid.Parse(imageID); err == nil {
+		siteImageID = &id
+		media, err := s.mediaRepo.FindBySiteImage(ctx, id, entities.WeChatMediaTypeImage, true)
+		if err == nil {
+			return toMaterialUploadResponse(media), nil
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image %s: %w", filePath, err)
+	}
+	media, err := s.Upload(ctx, &services.WeChatMediaUpload{
+		MediaType:   entities.WeChatMediaTypeImage,
+		Permanent:   true,
+		FileName:    filePath,
+		Data:        data,
+		SiteImageID: siteImageID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toMaterialUploadResponse(media), nil
+}
+
+// GetMedia downloads temporary media
+func (s *WeChatMediaServiceImpl) GetMedia(ctx context.Context, mediaID string) ([]byte, error) {
+	return s.store.GetMedia(ctx, mediaID)
+}
+
+// GetPermanentMediaList lists the permanent media of a type
+func (s *WeChatMediaServiceImpl) GetPermanentMediaList(ctx context.Context, mediaType string, offset, count int) (*services.WeChatMediaList, error) {
+	return s.store.GetPermanentMediaList(ctx, mediaType, offset, count)
+}
+
+// DeletePermanentMedia deletes permanent media from WeChat along with its records, so its content
+// is uploaded again next time
+func (s *WeChatMediaServiceImpl) DeletePermanentMedia(ctx context.Context, mediaID string) error {
+	if err := s.store.DeletePermanentMedia(ctx, mediaID); err != nil {
+		return err
+	}
+	return s.mediaRepo.DeleteByMediaID(ctx, mediaID)
+}
+
+// reuse returns media uploaded before, mapped to the site image first unless it already is
+func (s *WeChatMediaServiceImpl) reuse(ctx context.Context, media *entities.WeChatMedia, siteImageID *uuid.UUID) (*entities.WeChatMedia, error) {
+	if siteImageID == nil || (media.SiteImageID != nil && *media.SiteImageID == *siteImageID) {
+		return media, nil
+	}
+
+	mapped := media.MapTo(*siteImageID)
+	if err := s.mediaRepo.Create(ctx, mapped); err != nil {
+		return nil, err
+	}
+	return mapped, nil
+}
+
+func toMaterialUploadResponse(media *entities.WeChatMedia) *wechat.MaterialUploadResponse {
+	return &wechat.MaterialUploadResponse{
+		Type:    media.MediaType,
+		MediaID: media.MediaID,
+		URL:     media.URL,
+	}
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeWeChatMediaAPI serves the WeChat media endpoints, keeping uploads in memory
+type fakeWeChatMediaAPI struct {
+	mu        sync.Mutex
+	uploads   int
+	media     map[string][]byte
+	permanent map[string]string // Media ID -> file name
+	videoDesc string
+}
+
+func (f *fakeWeChatMediaAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/cgi-bin/token" && r.URL.Query().Get("access_token") != "token" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 41001, "errmsg": "access_token missing"})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/cgi-bin/token":
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 7200})
+	case "/cgi-bin/media/upload", "/cgi-bin/material/add_material":
+		file, header, err := r.FormFile("media")
+		if err != nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 41005, "errmsg": "media data missing"})
+			return
+		}
+		data, _ := io.ReadAll(file)
+		f.uploads++
+		mediaID := fmt.Sprintf("media-%d", f.uploads)
+		f.media[mediaID] = data
+
+		if r.URL.Path == "/cgi-bin/media/upload" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"type": r.URL.Query().Get("type"), "media_id": mediaID, "created_at": 1737100000})
+			return
+		}
+		f.permanent[mediaID] = header.Filename
+		f.videoDesc = r.FormValue("description")
+		json.NewEncoder(w).Encode(map[string]interface{}{"media_id": mediaID, "url": "https://mmbiz.qpic.cn/" + mediaID})
+	case "/cgi-bin/media/get":
+		data, ok := f.media[r.URL.Query().Get("media_id")]
+		if !ok {
+			w.Header().Set("Content-Type", "text/plain")
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 40007, "errmsg": "invalid media_id"})
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	case "/cgi-bin/material/batchget_material":
+		var items []map[string]interface{}
+		for mediaID, name := range f.permanent {
+			items = append(items, map[string]interface{}{"media_id": mediaID, "name": name, "update_time": 1737100000, "url": "https://mmbiz.qpic.cn/" + mediaID})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": len(items), "item_count": len(items), "item": items})
+	case "/cgi-bin/material/del_material":
+		var request struct {
+			MediaID string `json:"media_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if _, ok := f.permanent[request.MediaID]; !ok {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 40007, "errmsg": "invalid media_id"})
+			return
+		}
+		delete(f.permanent, request.MediaID)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// memoryWeChatMediaRepository keeps media records in memory in the order they were created
+type memoryWeChatMediaRepository struct {
+	media []entities.WeChatMedia
+}
+
+func (r *memoryWeChatMediaRepository) Create(ctx context.Context, media *entities.WeChatMedia) error {
+	r.media = append(r.media, *media)
+	return nil
+}
+
+func (r *memoryWeChatMediaRepository) FindByContentHash(ctx context.Context, contentHash, mediaType string, permanent bool) (*entities.WeChatMedia, error) {
+	for i := len(r.media) - 1; i >= 0; i-- {
+		media := r.media[i]
+		if media.ContentHash == contentHash && media.MediaType == mediaType && media.Permanent == permanent {
+			return &media, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMediaRepository) FindBySiteImage(ctx context.Context, siteImageID uuid.UUID, mediaType string, permanent bool) (*entities.WeChatMedia, error) {
+	for i := len(r.media) - 1; i >= 0; i-- {
+		media := r.media[i]
+		if media.SiteImageID != nil && *media.SiteImageID == siteImageID && media.MediaType == mediaType && media.Permanent == permanent {
+			return &media, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMediaRepository) DeleteByMediaID(ctx context.Context, mediaID string) error {
+	kept := r.media[:0]
+	for _, media := range r.media {
+		if media.MediaID != mediaID {
+			kept = append(kept, media)
+		}
+	}
+	r.media = kept
+	return nil
+}
+
+// memorySiteImageRepository finds site images by ID; the other methods are not used
+type memorySiteImageRepository struct {
+	repositories.SiteImageRepository
+	images map[uuid.UUID]*entities.SiteImage
+}
+
+func (r *memorySiteImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.SiteImage, error) {
+	if image, ok := r.images[id]; ok {
+		return image, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type WeChatMediaServiceTestSuite struct {
+	suite.Suite
+	api        *fakeWeChatMediaAPI
+	server     *httptest.Server
+	media      *memoryWeChatMediaRepository
+	siteImages *memorySiteImageRepository
+	storageDir string
+	service    *WeChatMediaServiceImpl
+	ctx        context.Context
+}
+
+func (suite *WeChatMediaServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.api = &fakeWeChatMediaAPI{media: map[string][]byte{}, permanent: map[string]string{}}
+	suite.server = httptest.NewServer(suite.api)
+
+	apiService := wechat.NewService(&wechat.Config{AppID: "wx123", AppSecret: "secret", APIBaseURL: suite.server.URL}, nil, zap.NewNop())
+	wechatService := NewWeChatServiceImpl(apiService, &memoryWeChatUserRepository{}, nil, zap.NewNop())
+	suite.media = &memoryWeChatMediaRepository{}
+	suite.siteImages = &memorySiteImageRepository{images: map[uuid.UUID]*entities.SiteImage{}}
+	suite.storageDir = suite.T().TempDir()
+	suite.service = NewWeChatMediaService(suite.media, suite.siteImages, wechatService,
+		storage.NewLocalStorage(suite.storageDir, "/uploads"), zap.NewNop())
+}
+
+func (suite *WeChatMediaServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *WeChatMediaServiceTestSuite) upload(data string, permanent bool, siteImageID *uuid.UUID) *entities.WeChatMedia {
+	media, err := suite.service.Upload(suite.ctx, &services.WeChatMediaUpload{
+		MediaType:   entities.WeChatMediaTypeImage,
+		Permanent:   permanent,
+		FileName:    "cover.png",
+		Data:        []byte(data),
+		SiteImageID: siteImageID,
+	})
+	suite.Require().NoError(err)
+	return media
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestSameContentIsUploadedOnce() {
+	first := suite.upload("cover", true, nil)
+	suite.Equal("media-1", first.MediaID)
+	suite.Equal("https://mmbiz.qpic.cn/media-1", first.URL)
+	suite.Nil(first.ExpiresAt)
+
+	// The same content is mapped to a site image without uploading it again
+	siteImageID := uuid.New()
+	mapped := suite.upload("cover", true, &siteImageID)
+	suite.Equal("media-1", mapped.MediaID)
+	suite.Equal(siteImageID, *mapped.SiteImageID)
+	suite.Equal(1, suite.api.uploads)
+
+	// Temporary and permanent media are kept apart
+	temporary := suite.upload("cover", false, nil)
+	suite.Equal("media-2", temporary.MediaID)
+	suite.Require().NotNil(temporary.ExpiresAt)
+	suite.upload("cover", false, nil)
+	suite.Equal(2, suite.api.uploads)
+
+	content, err := suite.service.GetMedia(suite.ctx, temporary.MediaID)
+	suite.Require().NoError(err)
+	suite.Equal("cover", string(content))
+
+	_, err = suite.service.GetMedia(suite.ctx, "unknown")
+	var apiErr *wechat.APIError
+	suite.Require().ErrorAs(err, &apiErr)
+	suite.Equal(40007, apiErr.Code)
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestExpiringTemporaryMediaIsUploadedAgain() {
+	temporary := suite.upload("poster", false, nil)
+
+	// The stored record gets close to expiring
+	expiresAt := time.Now().Add(30 * time.Minute)
+	suite.media.media[0].ExpiresAt = &expiresAt
+
+	renewed := suite.upload("poster", false, nil)
+	suite.NotEqual(temporary.MediaID, renewed.MediaID)
+	suite.Equal(2, suite.api.uploads)
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestUploadSiteImage() {
+	siteImageID := uuid.New()
+	suite.Require().NoError(os.MkdirAll(filepath.Join(suite.storageDir, "images"), 0755))
+	suite.Require().NoError(os.WriteFile(filepath.Join(suite.storageDir, "images", "hall.png"), []byte("hall"), 0644))
+	suite.siteImages.images[siteImageID] = &entities.SiteImage{ID: siteImageID, Filename: "hall.png", StoragePath: "images/hall.png"}
+
+	media, err := suite.service.UploadSiteImage(suite.ctx, siteImageID)
+	suite.Require().NoError(err)
+	suite.Equal(siteImageID, *media.SiteImageID)
+	suite.Equal("hall.png", suite.api.permanent[media.MediaID])
+
+	// The mapping is reused without reading the image again
+	suite.Require().NoError(os.Remove(filepath.Join(suite.storageDir, "images", "hall.png")))
+	again, err := suite.service.UploadSiteImage(suite.ctx, siteImageID)
+	suite.Require().NoError(err)
+	suite.Equal(media.MediaID, again.MediaID)
+	suite.Equal(1, suite.api.uploads)
+
+	_, err = suite.service.UploadSiteImage(suite.ctx, uuid.New())
+	suite.ErrorIs(err, repositories.ErrNotFound)
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestUploadImageForPreprocessor() {
+	dir := suite.T().TempDir()
+	cover := filepath.Join(dir, "cover.png")
+	copied := filepath.Join(dir, "cover-copy.png")
+	suite.Require().NoError(os.WriteFile(cover, []byte("cover"), 0644))
+	suite.Require().NoError(os.WriteFile(copied, []byte("cover"), 0644))
+
+	imageID := uuid.New().String()
+	first, err := suite.service.UploadImage(suite.ctx, imageID, cover)
+	suite.Require().NoError(err)
+	suite.Equal("media-1", first.MediaID)
+	suite.Equal("https://mmbiz.qpic.cn/media-1", first.URL)
+
+	// Legacy image IDs that are not UUIDs and copies of an uploaded file reuse the media by content
+	second, err := suite.service.UploadImage(suite.ctx, "legacy-42", copied)
+	suite.Require().NoError(err)
+	suite.Equal(first.MediaID, second.MediaID)
+	third, err := suite.service.UploadImage(suite.ctx, imageID, filepath.Join(dir, "missing.png"))
+	suite.Require().NoError(err)
+	suite.Equal(first.MediaID, third.MediaID)
+	suite.Equal(1, suite.api.uploads)
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestListAndDeletePermanentMedia() {
+	media := suite.upload("banner", true, nil)
+	siteImageID := uuid.New()
+	suite.upload("banner", true, &siteImageID)
+
+	list, err := suite.service.GetPermanentMediaList(suite.ctx, entities.WeChatMediaTypeImage, 0, 50)
+	suite.Require().NoError(err)
+	suite.Equal(1, list.TotalCount)
+	suite.Require().Len(list.Items, 1)
+	suite.Equal(media.MediaID, list.Items[0].MediaID)
+
+	// Deleting forgets every record of the media, so its content is uploaded again next time
+	suite.Require().NoError(suite.service.DeletePermanentMedia(suite.ctx, media.MediaID))
+	suite.Empty(suite.media.media)
+	suite.NotEqual(media.MediaID, suite.upload("banner", true, nil).MediaID)
+
+	var apiErr *wechat.APIError
+	suite.ErrorAs(suite.service.DeletePermanentMedia(suite.ctx, media.MediaID), &apiErr)
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestInvalidMediaIsNotUploaded() {
+	_, err := suite.service.Upload(suite.ctx, &services.WeChatMediaUpload{
+		MediaType: entities.WeChatMediaTypeThumb,
+		FileName:  "thumb.jpg",
+		Data:      []byte(strings.Repeat("a", 65<<10)),
+	})
+	suite.ErrorIs(err, entities.ErrInvalidWeChatMedia)
+	suite.Zero(suite.api.uploads)
+}
+
+func (suite *WeChatMediaServiceTestSuite) TestPermanentVideosAreDescribed() {
+	media, err := suite.service.Upload(suite.ctx, &services.WeChatMediaUpload{
+		MediaType: entities.WeChatMediaTypeVideo,
+		Permanent: true,
+		FileName:  "keynote.mp4",
+		Data:      []byte("video"),
+	})
+	suite.Require().NoError(err)
+	suite.Equal("media-1", media.MediaID)
+	suite.JSONEq(`{"title":"keynote","introduction":""}`, suite.api.videoDesc)
+}
+
+func TestWeChatMediaServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeChatMediaServiceTestSuite))
+}
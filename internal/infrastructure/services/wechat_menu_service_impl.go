@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatMenuServiceImpl implements the WeChatMenuService interface
+type WeChatMenuServiceImpl struct {
+	menuRepo  repositories.WeChatMenuRepository
+	publisher services.WeChatMenuPublisher
+	logger    *zap.Logger
+}
+
+// NewWeChatMenuService creates a new WeChat menu service implementation
+func NewWeChatMenuService(
+	menuRepo repositories.WeChatMenuRepository,
+	publisher services.WeChatMenuPublisher,
+	logger *zap.Logger,
+) *WeChatMenuServiceImpl {
+	return &WeChatMenuServiceImpl{
+		menuRepo:  menuRepo,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// RegisterHandlers replies to clicks on the CLICK buttons of the published menus. Keys with a
+// handler of their own registered on the router keep it.
+func (s *WeChatMenuServiceImpl) RegisterHandlers(router *wechat.MessageRouter) {
+	router.HandleEvent(message.EventClick, s.handleClick)
+}
+
+// SaveMenu validates the buttons of a menu and records them as the next draft version of the menu
+func (s *WeChatMenuServiceImpl) SaveMenu(ctx context.Context, menu *entities.WeChatMenu) error {
+	menu.TagID = strings.TrimSpace(menu.TagID)
+	if err := menu.Validate(); err != nil {
+		return err
+	}
+
+	versions, err := s.menuRepo.FindByTagID(ctx, menu.TagID)
+	if err != nil {
+		return err
+	}
+	menu.ID = uuid.New()
+	menu.Version = 1
+	if len(versions) > 0 {
+		menu.Version = versions[0].Version + 1
+	}
+	menu.Status = entities.WeChatMenuStatusDraft
+	menu.WeChatMenuID = ""
+	menu.PublishedAt = nil
+	menu.PublishedBy = nil
+	if err := menu.SetButtons(menu.Buttons); err != nil {
+		return err
+	}
+	if err := s.menuRepo.Create(ctx, menu); err != nil {
+		return err
+	}
+
+	s.logger.Info("WeChat menu saved",
+		zap.String("menuId", menu.ID.String()),
+		zap.String("tagId", menu.TagID),
+		zap.Int("version", menu.Version))
+	return nil
+}
+
+// GetMenu retrieves a menu version with its buttons
+func (s *WeChatMenuServiceImpl) GetMenu(ctx context.Context, id uuid.UUID) (*entities.WeChatMenu, error) {
+	menu, err := s.menuRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadButtons(menu); err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+// GetMenuVersions lists the versions of the menu of a tag, latest first
+func (s *WeChatMenuServiceImpl) GetMenuVersions(ctx context.Context, tagID string) ([]entities.WeChatMenu, error) {
+	menus, err := s.menuRepo.FindByTagID(ctx, strings.TrimSpace(tagID))
+	if err != nil {
+		return nil, err
+	}
+	for i := range menus {
+		if err := s.loadButtons(&menus[i]); err != nil {
+			return nil, err
+		}
+	}
+	return menus, nil
+}
+
+// GetPublishedMenus lists the menus followers see, the default menu first
+func (s *WeChatMenuServiceImpl) GetPublishedMenus(ctx context.Context) ([]entities.WeChatMenu, error) {
+	menus, err := s.menuRepo.FindPublished(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range menus {
+		if err := s.loadButtons(&menus[i]); err != nil {
+			return nil, err
+		}
+	}
+	return menus, nil
+}
+
+// DeleteMenu deletes a version that is not published
+func (s *WeChatMenuServiceImpl) DeleteMenu(ctx context.Context, id uuid.UUID) error {
+	menu, err := s.menuRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if menu.IsPublished() {
+		return services.ErrWeChatMenuPublished
+	}
+	return s.menuRepo.Delete(ctx, id)
+}
+
+// PublishMenu pushes a version to WeChat and archives the version of the same menu published
+// before. Conditional menus are created before the one they replace is deleted, so their
+// followers keep a menu if WeChat rejects the new one.
+func (s *WeChatMenuServiceImpl) PublishMenu(ctx context.Context, id uuid.UUID, publishedBy *uuid.UUID) (*entities.WeChatMenu, error) {
+	menu, err := s.GetMenu(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := menu.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.publishedVersion(ctx, menu.TagID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	wechatMenu := toMenu(menu.Buttons)
+	if menu.IsConditional() {
+		if _, err := s.publishedVersion(ctx, ""); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return nil, services.ErrNoDefaultWeChatMenu
+			}
+			return nil, err
+		}
+		wechatMenu.MatchRule = &services.MenuMatchRule{TagID: menu.TagID}
+		menuID, err := s.publisher.AddConditionalMenu(ctx, wechatMenu)
+		if err != nil {
+			return nil, err
+		}
+		menu.WeChatMenuID = menuID
+	} else if err := s.publisher.CreateMenu(ctx, wechatMenu); err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		if current.IsConditional() && current.WeChatMenuID != "" {
+			if err := s.publisher.DeleteConditionalMenu(ctx, current.WeChatMenuID); err != nil {
+				s.logger.Warn("Failed to delete replaced WeChat conditional menu",
+					zap.String("menuId", current.ID.String()),
+					zap.String("wechatMenuId", current.WeChatMenuID),
+					zap.Error(err))
+			}
+		}
+		if current.ID != menu.ID {
+			if err := s.archive(ctx, current); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	now := time.Now()
+	menu.Status = entities.WeChatMenuStatusPublished
+	menu.PublishedAt = &now
+	menu.PublishedBy = publishedBy
+	if err := s.menuRepo.Update(ctx, menu); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat menu published",
+		zap.String("menuId", menu.ID.String()),
+		zap.String("tagId", menu.TagID),
+		zap.Int("version", menu.Version))
+	return menu, nil
+}
+
+// RollbackMenu publishes again the version of a menu published before the current one, or the
+// version published last when the menu was unpublished
+func (s *WeChatMenuServiceImpl) RollbackMenu(ctx context.Context, tagID string, publishedBy *uuid.UUID) (*entities.WeChatMenu, error) {
+	versions, err := s.menuRepo.FindByTagID(ctx, strings.TrimSpace(tagID))
+	if err != nil {
+		return nil, err
+	}
+
+	var current, previous *entities.WeChatMenu
+	for i := range versions {
+		if versions[i].IsPublished() {
+			current = &versions[i]
+		}
+	}
+	for i := range versions {
+		version := &versions[i]
+		if version.Status != entities.WeChatMenuStatusArchived || version.PublishedAt == nil {
+			continue
+		}
+		if current != nil && current.PublishedAt != nil && !version.PublishedAt.Before(*current.PublishedAt) {
+			continue
+		}
+		if previous == nil || version.PublishedAt.After(*previous.PublishedAt) {
+			previous = version
+		}
+	}
+	if previous == nil {
+		return nil, services.ErrNoWeChatMenuToRollback
+	}
+
+	s.logger.Info("Rolling back WeChat menu",
+		zap.String("tagId", previous.TagID),
+		zap.Int("version", previous.Version))
+	return s.PublishMenu(ctx, previous.ID, publishedBy)
+}
+
+// UnpublishMenu removes a menu from WeChat. WeChat deletes the conditional menus together with the
+// default menu, so unpublishing the default menu archives every published menu.
+func (s *WeChatMenuServiceImpl) UnpublishMenu(ctx context.Context, tagID string) error {
+	tagID = strings.TrimSpace(tagID)
+	if tagID == "" {
+		if err := s.publisher.DeleteMenu(ctx); err != nil {
+			return err
+		}
+		published, err := s.menuRepo.FindPublished(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range published {
+			if err := s.archive(ctx, &published[i]); err != nil {
+				return err
+			}
+		}
+		s.logger.Info("WeChat menus unpublished", zap.Int("menus", len(published)))
+		return nil
+	}
+
+	current, err := s.publishedVersion(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	if current.WeChatMenuID != "" {
+		if err := s.publisher.DeleteConditionalMenu(ctx, current.WeChatMenuID); err != nil {
+			return err
+		}
+	}
+	if err := s.archive(ctx, current); err != nil {
+		return err
+	}
+
+	s.logger.Info("WeChat menu unpublished",
+		zap.String("tagId", tagID),
+		zap.Int("version", current.Version))
+	return nil
+}
+
+// handleClick replies to CLICK buttons with the reply set in the published menus. A key shared by
+// several menus gets the reply of the default menu, then of the conditional menu published first.
+func (s *WeChatMenuServiceImpl) handleClick(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	menus, err := s.GetPublishedMenus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range menus {
+		reply, ok := menus[i].ClickReplies()[msg.EventKey]
+		if !ok {
+			continue
+		}
+		if reply.Type == entities.WeChatMenuReplyNews {
+			return wechat.NewsReply(reply.Title, reply.Description, reply.PicURL, reply.URL), nil
+		}
+		return wechat.TextReply(reply.Content), nil
+	}
+
+	s.logger.Warn("Unhandled WeChat menu click",
+		zap.String("openId", msg.GetOpenID()),
+		zap.String("eventKey", msg.EventKey))
+	return nil, nil
+}
+
+// publishedVersion finds the published version of the menu of a tag
+func (s *WeChatMenuServiceImpl) publishedVersion(ctx context.Context, tagID string) (*entities.WeChatMenu, error) {
+	versions, err := s.menuRepo.FindByTagID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if versions[i].IsPublished() {
+			return &versions[i], nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// archive marks a published version as replaced
+func (s *WeChatMenuServiceImpl) archive(ctx context.Context, menu *entities.WeChatMenu) error {
+	menu.Status = entities.WeChatMenuStatusArchived
+	menu.WeChatMenuID = ""
+	return s.menuRepo.Update(ctx, menu)
+}
+
+func (s *WeChatMenuServiceImpl) loadButtons(menu *entities.WeChatMenu) error {
+	buttons, err := menu.GetButtons()
+	if err != nil {
+		return err
+	}
+	menu.Buttons = buttons
+	return nil
+}
+
+// toMenu converts menu buttons to the WeChat menu structure
+func toMenu(buttons []entities.WeChatMenuButton) *services.Menu {
+	return &services.Menu{Buttons: toMenuButtons(buttons)}
+}
+
+func toMenuButtons(buttons []entities.WeChatMenuButton) []services.MenuButton {
+	converted := make([]services.MenuButton, len(buttons))
+	for i, button := range buttons {
+		converted[i] = services.MenuButton{
+			Type:      button.Type,
+			Name:      button.Name,
+			Key:       button.Key,
+			URL:       button.URL,
+			AppID:     button.AppID,
+			PagePath:  button.PagePath,
+			ArticleID: button.ArticleID,
+		}
+		if len(button.SubButtons) > 0 {
+			converted[i] = services.MenuButton{Name: button.Name, SubButton: toMenuButtons(button.SubButtons)}
+		}
+	}
+	return converted
+}
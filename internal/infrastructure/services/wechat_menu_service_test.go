@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// memoryWeChatMenuRepository keeps menu versions in memory in the order they were saved
+type memoryWeChatMenuRepository struct {
+	menus []entities.WeChatMenu
+}
+
+func (r *memoryWeChatMenuRepository) Create(ctx context.Context, menu *entities.WeChatMenu) error {
+	r.menus = append(r.menus, *menu)
+	return nil
+}
+
+func (r *memoryWeChatMenuRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatMenu, error) {
+	for _, menu := range r.menus {
+		if menu.ID == id {
+			menu.Buttons = nil
+			return &menu, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMenuRepository) Update(ctx context.Context, menu *entities.WeChatMenu) error {
+	for i := range r.menus {
+		if r.menus[i].ID == menu.ID {
+			r.menus[i] = *menu
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMenuRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	for i := range r.menus {
+		if r.menus[i].ID == id {
+			r.menus = append(r.menus[:i], r.menus[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memoryWeChatMenuRepository) FindByTagID(ctx context.Context, tagID string) ([]entities.WeChatMenu, error) {
+	var menus []entities.WeChatMenu
+	for i := len(r.menus) - 1; i >= 0; i-- {
+		if r.menus[i].TagID == tagID {
+			menus = append(menus, r.menus[i])
+		}
+	}
+	return menus, nil
+}
+
+func (r *memoryWeChatMenuRepository) FindPublished(ctx context.Context) ([]entities.WeChatMenu, error) {
+	var menus []entities.WeChatMenu
+	for _, menu := range r.menus {
+		if menu.IsPublished() && !menu.IsConditional() {
+			menus = append(menus, menu)
+		}
+	}
+	for _, menu := range r.menus {
+		if menu.IsPublished() && menu.IsConditional() {
+			menus = append(menus, menu)
+		}
+	}
+	return menus, nil
+}
+
+// fakeWeChatMenuPublisher records the menus pushed to WeChat
+type fakeWeChatMenuPublisher struct {
+	defaultMenu      *services.Menu
+	conditionalMenus map[string]*services.Menu
+	nextMenuID       int
+	err              error
+}
+
+func (p *fakeWeChatMenuPublisher) CreateMenu(ctx context.Context, menu *services.Menu) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.defaultMenu = menu
+	return nil
+}
+
+func (p *fakeWeChatMenuPublisher) DeleteMenu(ctx context.Context) error {
+	p.defaultMenu = nil
+	p.conditionalMenus = map[string]*services.Menu{}
+	return nil
+}
+
+func (p *fakeWeChatMenuPublisher) AddConditionalMenu(ctx context.Context, menu *services.Menu) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.nextMenuID++
+	menuID := fmt.Sprint(p.nextMenuID)
+	p.conditionalMenus[menuID] = menu
+	return menuID, nil
+}
+
+func (p *fakeWeChatMenuPublisher) DeleteConditionalMenu(ctx context.Context, menuID string) error {
+	delete(p.conditionalMenus, menuID)
+	return nil
+}
+
+type WeChatMenuServiceTestSuite struct {
+	suite.Suite
+	menus     *memoryWeChatMenuRepository
+	publisher *fakeWeChatMenuPublisher
+	router    *wechat.MessageRouter
+	service   *WeChatMenuServiceImpl
+	ctx       context.Context
+}
+
+func (suite *WeChatMenuServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.menus = &memoryWeChatMenuRepository{}
+	suite.publisher = &fakeWeChatMenuPublisher{conditionalMenus: map[string]*services.Menu{}}
+	suite.router = wechat.NewMessageRouter()
+	suite.service = NewWeChatMenuService(suite.menus, suite.publisher, zap.NewNop())
+	suite.service.RegisterHandlers(suite.router)
+}
+
+func (suite *WeChatMenuServiceTestSuite) save(tagID, agendaReply string) *entities.WeChatMenu {
+	menu := &entities.WeChatMenu{
+		Name:  "Summit",
+		TagID: tagID,
+		Buttons: []entities.WeChatMenuButton{
+			{Name: "Event", SubButtons: []entities.WeChatMenuButton{
+				{Type: entities.WeChatMenuButtonClick, Name: "Agenda", Key: "agenda",
+					Reply: &entities.WeChatMenuReply{Type: entities.WeChatMenuReplyText, Content: agendaReply}},
+				{Type: entities.WeChatMenuButtonView, Name: "Register", URL: "https://example.com/register"},
+			}},
+		},
+	}
+	suite.Require().NoError(suite.service.SaveMenu(suite.ctx, menu))
+	return menu
+}
+
+func (suite *WeChatMenuServiceTestSuite) click(key string) *message.Reply {
+	msg := &message.MixMessage{}
+	msg.MsgType = message.MsgTypeEvent
+	msg.Event = message.EventClick
+	msg.EventKey = key
+	reply, err := suite.router.Route(suite.ctx, msg)
+	suite.Require().NoError(err)
+	return reply
+}
+
+func (suite *WeChatMenuServiceTestSuite) TestPublishAndRollbackDefaultMenu() {
+	first := suite.save("", "Doors open at 9:00")
+	second := suite.save("", "Doors open at 10:00")
+	suite.Equal(1, first.Version)
+	suite.Equal(2, second.Version)
+	suite.Nil(suite.click("agenda"), "drafts are not answered")
+
+	_, err := suite.service.PublishMenu(suite.ctx, first.ID, nil)
+	suite.Require().NoError(err)
+	published, err := suite.service.PublishMenu(suite.ctx, second.ID, nil)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMenuStatusPublished, published.Status)
+	suite.Require().NotNil(suite.publisher.defaultMenu)
+	suite.Equal("agenda", suite.publisher.defaultMenu.Buttons[0].SubButton[0].Key)
+	suite.Equal(wechat.TextReply("Doors open at 10:00"), suite.click("agenda"))
+	suite.Nil(suite.click("unknown"))
+
+	// Rolling back publishes the first version again, and rolling back once more undoes that
+	rolledBack, err := suite.service.RollbackMenu(suite.ctx, "", nil)
+	suite.Require().NoError(err)
+	suite.Equal(first.ID, rolledBack.ID)
+	suite.Equal(wechat.TextReply("Doors open at 9:00"), suite.click("agenda"))
+	rolledBack, err = suite.service.RollbackMenu(suite.ctx, "", nil)
+	suite.Require().NoError(err)
+	suite.Equal(second.ID, rolledBack.ID)
+
+	versions, err := suite.service.GetMenuVersions(suite.ctx, "")
+	suite.Require().NoError(err)
+	suite.Require().Len(versions, 2)
+	suite.Equal(entities.WeChatMenuStatusPublished, versions[0].Status)
+	suite.Equal(entities.WeChatMenuStatusArchived, versions[1].Status)
+	suite.Len(versions[1].Buttons, 1)
+
+	suite.ErrorIs(suite.service.DeleteMenu(suite.ctx, second.ID), services.ErrWeChatMenuPublished)
+	suite.NoError(suite.service.DeleteMenu(suite.ctx, first.ID))
+}
+
+func (suite *WeChatMenuServiceTestSuite) TestConditionalMenus() {
+	vip := suite.save("101", "VIP lounge opens at 8:30")
+	_, err := suite.service.PublishMenu(suite.ctx, vip.ID, nil)
+	suite.ErrorIs(err, services.ErrNoDefaultWeChatMenu)
+
+	base := suite.save("", "Doors open at 9:00")
+	_, err = suite.service.PublishMenu(suite.ctx, base.ID, nil)
+	suite.Require().NoError(err)
+	published, err := suite.service.PublishMenu(suite.ctx, vip.ID, nil)
+	suite.Require().NoError(err)
+	suite.Equal("1", published.WeChatMenuID)
+	suite.Require().Contains(suite.publisher.conditionalMenus, "1")
+	suite.Equal("101", suite.publisher.conditionalMenus["1"].MatchRule.TagID)
+
+	// A new version replaces the conditional menu on WeChat
+	next := suite.save("101", "VIP lounge opens at 8:00")
+	_, err = suite.service.PublishMenu(suite.ctx, next.ID, nil)
+	suite.Require().NoError(err)
+	suite.Len(suite.publisher.conditionalMenus, 1)
+	suite.Contains(suite.publisher.conditionalMenus, "2")
+
+	menus, err := suite.service.GetPublishedMenus(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(menus, 2)
+	suite.Equal("", menus[0].TagID)
+
+	suite.NoError(suite.service.UnpublishMenu(suite.ctx, "101"))
+	suite.Empty(suite.publisher.conditionalMenus)
+	suite.ErrorIs(suite.service.UnpublishMenu(suite.ctx, "101"), repositories.ErrNotFound)
+
+	// Unpublishing the default menu removes every menu
+	_, err = suite.service.RollbackMenu(suite.ctx, "101", nil)
+	suite.Require().NoError(err)
+	suite.NoError(suite.service.UnpublishMenu(suite.ctx, ""))
+	menus, err = suite.service.GetPublishedMenus(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Empty(menus)
+	suite.Nil(suite.click("agenda"))
+}
+
+func (suite *WeChatMenuServiceTestSuite) TestInvalidMenusAreRejected() {
+	err := suite.service.SaveMenu(suite.ctx, &entities.WeChatMenu{
+		Name:    "Summit",
+		Buttons: []entities.WeChatMenuButton{{Type: entities.WeChatMenuButtonClick, Name: "Agenda"}},
+	})
+	suite.ErrorIs(err, entities.ErrInvalidWeChatMenu)
+	suite.Empty(suite.menus.menus)
+
+	_, err = suite.service.RollbackMenu(suite.ctx, "", nil)
+	suite.ErrorIs(err, services.ErrNoWeChatMenuToRollback)
+
+	// Versions WeChat rejects stay drafts
+	menu := suite.save("", "Doors open at 9:00")
+	suite.publisher.err = &wechat.APIError{Operation: "create menu", Code: 40016, Message: "invalid button size"}
+	_, err = suite.service.PublishMenu(suite.ctx, menu.ID, nil)
+	suite.Error(err)
+	stored, err := suite.service.GetMenu(suite.ctx, menu.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatMenuStatusDraft, stored.Status)
+}
+
+func TestWeChatMenuServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeChatMenuServiceTestSuite))
+}
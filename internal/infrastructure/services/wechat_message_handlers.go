@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultWelcomeMessage = "感谢关注！回复“帮助”查看可用功能。"
+	defaultHelpMessage    = "回复“帮助”查看本说明；扫描活动二维码即可完成签到。"
+)
+
+// replyToken is implemented by every passive reply type through the embedded message.CommonToken
+type replyToken interface {
+	SetToUserName(toUserName message.CDATA)
+	SetFromUserName(fromUserName message.CDATA)
+	SetCreateTime(createTime int64)
+	SetMsgType(msgType message.MsgType)
+}
+
+// Router returns the message router so callers can plug in additional handlers
+func (w *WeChatServiceImpl) Router() *wechat.MessageRouter {
+	return w.router
+}
+
+// SetWelcomeMessage overrides the reply sent to new followers
+func (w *WeChatServiceImpl) SetWelcomeMessage(welcomeMessage string) {
+	if welcomeMessage != "" {
+		w.welcomeMessage = welcomeMessage
+	}
+}
+
+// ProcessMessage persists an inbound message, routes it to the matching handler and builds the reply
+func (w *WeChatServiceImpl) ProcessMessage(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	record := w.recordMessage(ctx, msg)
+
+	reply, err := w.router.Route(ctx, msg)
+	if err != nil {
+		w.logger.Error("Failed to handle WeChat message",
+			zap.String("openId", msg.GetOpenID()),
+			zap.String("msgType", string(msg.MsgType)),
+			zap.String("event", string(msg.Event)),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to handle WeChat message: %w", err)
+	}
+
+	if reply != nil {
+		if err := w.finalizeReply(msg, reply); err != nil {
+			return nil, err
+		}
+	}
+
+	w.markMessageProcessed(ctx, record, reply)
+	return reply, nil
+}
+
+// registerDefaultHandlers wires the built-in handlers for common messages and events
+func (w *WeChatServiceImpl) registerDefaultHandlers() {
+	w.router.HandleEvent(message.EventSubscribe, w.handleSubscribe)
+	w.router.HandleEvent(message.EventUnsubscribe, w.handleUnsubscribe)
+	w.router.HandleEvent(message.EventScan, w.handleScan)
+	w.router.HandleEvent(message.EventClick, w.handleClick)
+	w.router.HandleEvent(message.EventView, w.handleView)
+
+	help := func(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+		return wechat.TextReply(defaultHelpMessage), nil
+	}
+	w.router.HandleKeyword("帮助", true, help)
+	w.router.HandleKeyword("help", true, help)
+}
+
+// handleSubscribe marks the follower as subscribed and sends the welcome message.
+// Follows triggered by a parametric QR code are handed to the matching scene handler first.
+func (w *WeChatServiceImpl) handleSubscribe(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	if _, err := w.upsertSubscription(ctx, msg.GetOpenID(), true); err != nil {
+		return nil, err
+	}
+
+	reply, err := w.router.RouteScene(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if reply != nil {
+		return reply, nil
+	}
+	return wechat.TextReply(w.welcomeMessage), nil
+}
+
+// handleUnsubscribe marks the follower as unsubscribed; WeChat ignores replies to this event
+func (w *WeChatServiceImpl) handleUnsubscribe(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	if _, err := w.upsertSubscription(ctx, msg.GetOpenID(), false); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleScan logs scans of parametric QR codes that no scene handler claimed
+func (w *WeChatServiceImpl) handleScan(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	w.logger.Info("WeChat QR code scanned",
+		zap.String("openId", msg.GetOpenID()),
+		zap.String("eventKey", msg.EventKey))
+	return nil, nil
+}
+
+// handleClick handles menu clicks whose key has no dedicated handler
+func (w *WeChatServiceImpl) handleClick(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	w.logger.Warn("Unhandled WeChat menu click",
+		zap.String("openId", msg.GetOpenID()),
+		zap.String("eventKey", msg.EventKey))
+	return nil, nil
+}
+
+// handleView records menu link clicks
+func (w *WeChatServiceImpl) handleView(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	w.logger.Debug("WeChat menu link opened",
+		zap.String("openId", msg.GetOpenID()),
+		zap.String("url", msg.EventKey))
+	return nil, nil
+}
+
+// upsertSubscription creates or updates the follower record with the given subscription state
+func (w *WeChatServiceImpl) upsertSubscription(ctx context.Context, openID string, subscribed bool) (*entities.WeChatUser, error) {
+	user, err := w.wechatUserRepo.GetByOpenID(ctx, openID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get WeChat user: %w", err)
+		}
+		user = &entities.WeChatUser{OpenID: openID, Subscribe: subscribed}
+		if err := w.wechatUserRepo.Create(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to create WeChat user: %w", err)
+		}
+		return user, nil
+	}
+
+	user.Subscribe = subscribed
+	if subscribed {
+		now := time.Now()
+		user.SubscribeTime = &now
+	}
+	if err := w.wechatUserRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update WeChat user: %w", err)
+	}
+	return user, nil
+}
+
+// finalizeReply fills in the envelope fields WeChat expects on a passive reply
+func (w *WeChatServiceImpl) finalizeReply(msg *message.MixMessage, reply *message.Reply) error {
+	token, ok := reply.MsgData.(replyToken)
+	if !ok {
+		return message.ErrUnsupportReply
+	}
+	token.SetToUserName(msg.FromUserName)
+	token.SetFromUserName(msg.ToUserName)
+	token.SetCreateTime(time.Now().Unix())
+	token.SetMsgType(reply.MsgType)
+	return nil
+}
+
+// recordMessage persists the inbound message; failures are logged but never block the reply
+func (w *WeChatServiceImpl) recordMessage(ctx context.Context, msg *message.MixMessage) *entities.WeChatMessage {
+	record := &entities.WeChatMessage{
+		FromUser:    string(msg.FromUserName),
+		ToUser:      string(msg.ToUserName),
+		MessageType: string(msg.MsgType),
+		Content:     msg.Content,
+		MediaID:     msg.MediaID,
+		EventType:   string(msg.Event),
+		EventKey:    msg.EventKey,
+	}
+
+	if err := w.db.WithContext(ctx).Create(record).Error; err != nil {
+		w.logger.Error("Failed to save WeChat message",
+			zap.String("openId", record.FromUser),
+			zap.Error(err))
+		return nil
+	}
+	return record
+}
+
+// markMessageProcessed stores the outcome of routing on the persisted message
+func (w *WeChatServiceImpl) markMessageProcessed(ctx context.Context, record *entities.WeChatMessage, reply *message.Reply) {
+	if record == nil {
+		return
+	}
+
+	updates := map[string]interface{}{"Processed": true}
+	if reply != nil {
+		if data, err := xml.Marshal(reply.MsgData); err == nil {
+			updates["Response"] = string(data)
+		}
+	}
+
+	if err := w.db.WithContext(ctx).Model(record).Updates(updates).Error; err != nil {
+		w.logger.Error("Failed to update WeChat message",
+			zap.String("messageId", record.ID.String()),
+			zap.Error(err))
+	}
+}
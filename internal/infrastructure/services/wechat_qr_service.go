@@ -63,7 +63,7 @@ func (s *WeChatQRService) GenerateArticleQRCode(ctx context.Context, articleId u
 	}
 
 	// Generate scene string for the article
-	sceneStr := fmt.Sprintf("article_%s", articleId.String()[:8])
+	sceneStr := entities.BuildSceneStr("article", articleId)
 
 	// Create QR code entity
 	qrCode := &entities.WeChatQrCode{
@@ -142,7 +142,7 @@ func (s *WeChatQRService) GenerateSurveyQRCode(ctx context.Context, surveyId uui
 	}
 
 	// Generate scene string for the survey
-	sceneStr := fmt.Sprintf("survey_%s", surveyId.String()[:8])
+	sceneStr := entities.BuildSceneStr("survey", surveyId)
 
 	// Create QR code entity
 	qrCode := &entities.WeChatQrCode{
@@ -153,6 +153,69 @@ func (s *WeChatQRService) GenerateSurveyQRCode(ctx context.Context, surveyId uui
 		ExpireSeconds: s.config.DefaultExpireSeconds,
 	}
 
+	if err := s.createWeChatQRCode(ctx, qrCode, sceneStr, qrType); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Successfully generated survey QR code",
+		zap.String("qrCodeId", qrCode.ID.String()),
+		zap.String("ticket", qrCode.Ticket))
+
+	return qrCode, nil
+}
+
+// GenerateSurveyCollectorQRCode generates a QR code that opens the link of a survey collector, so
+// responses from scans are attributed to the collector
+func (s *WeChatQRService) GenerateSurveyCollectorQRCode(ctx context.Context, collector *entities.SurveyCollector, qrType entities.QRCodeType) (*entities.WeChatQrCode, error) {
+	s.logger.Info("Generating QR code for survey collector",
+		zap.String("surveyId", collector.SurveyID.String()),
+		zap.String("collectorId", collector.ID.String()),
+		zap.String("type", string(qrType)))
+
+	qrCode := &entities.WeChatQrCode{
+		ParamsValue:   collector.ID.String(),
+		ParamKey:      entities.QRResourceCollector,
+		ParamUrl:      collector.URL,
+		UserFor:       5, // Survey preview type
+		ExpireSeconds: s.config.DefaultExpireSeconds,
+	}
+	if err := s.createWeChatQRCode(ctx, qrCode, entities.BuildSceneStr(entities.QRResourceCollector, collector.ID), qrType); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Successfully generated survey collector QR code",
+		zap.String("qrCodeId", qrCode.ID.String()),
+		zap.String("collectorId", collector.ID.String()))
+
+	return qrCode, nil
+}
+
+// GenerateEventCheckInQRCode generates a QR code that checks the follower scanning it in to an event
+func (s *WeChatQRService) GenerateEventCheckInQRCode(ctx context.Context, eventID uuid.UUID, qrType entities.QRCodeType) (*entities.WeChatQrCode, error) {
+	s.logger.Info("Generating check-in QR code for event",
+		zap.String("eventId", eventID.String()),
+		zap.String("type", string(qrType)))
+
+	qrCode := &entities.WeChatQrCode{
+		ParamsValue:   eventID.String(),
+		ParamKey:      entities.QRResourceEvent,
+		ParamUrl:      fmt.Sprintf("%s/events/%s", s.config.BaseURL, eventID.String()),
+		ExpireSeconds: s.config.DefaultExpireSeconds,
+		EventId:       &eventID,
+	}
+	if err := s.createWeChatQRCode(ctx, qrCode, entities.BuildSceneStr(entities.QRResourceEvent, eventID), qrType); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Successfully generated event check-in QR code",
+		zap.String("qrCodeId", qrCode.ID.String()),
+		zap.String("eventId", eventID.String()))
+
+	return qrCode, nil
+}
+
+// createWeChatQRCode creates the WeChat QR code of a scene string and saves it with its image
+func (s *WeChatQRService) createWeChatQRCode(ctx context.Context, qrCode *entities.WeChatQrCode, sceneStr string, qrType entities.QRCodeType) error {
 	// Set expiration for temporary QR codes
 	if qrType == entities.QRCodeTypeTemporary {
 		expireTime := time.Now().Add(time.Duration(s.config.DefaultExpireSeconds) * time.Second)
@@ -170,7 +233,7 @@ func (s *WeChatQRService) GenerateSurveyQRCode(ctx context.Context, surveyId uui
 	}
 
 	if err2 != nil {
-		return nil, fmt.Errorf("failed to create WeChat QR code: %w", err2)
+		return fmt.Errorf("failed to create WeChat QR code: %w", err2)
 	}
 
 	// Update QR code with WeChat response
@@ -187,14 +250,10 @@ func (s *WeChatQRService) GenerateSurveyQRCode(ctx context.Context, surveyId uui
 
 	// Save to database
 	if err := s.qrCodeRepo.Create(ctx, qrCode); err != nil {
-		return nil, fmt.Errorf("failed to save QR code: %w", err)
+		return fmt.Errorf("failed to save QR code: %w", err)
 	}
 
-	s.logger.Info("Successfully generated survey QR code",
-		zap.String("qrCodeId", qrCode.ID.String()),
-		zap.String("ticket", qrCode.Ticket))
-
-	return qrCode, nil
+	return nil
 }
 
 // GetSurveyQRCode retrieves the active QR code for a survey
@@ -3,25 +3,44 @@ package services
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
 	"github.com/zenteam/nextevent-go/internal/domain/services"
 	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // WeChatServiceImpl implements the domain WeChatService interface
 type WeChatServiceImpl struct {
-	wechatService *wechat.Service
-	logger        *zap.Logger
+	wechatService  *wechat.Service
+	router         *wechat.MessageRouter
+	wechatUserRepo repositories.WeChatUserRepository
+	db             *gorm.DB
+	welcomeMessage string
+	logger         *zap.Logger
 }
 
 // NewWeChatServiceImpl creates a new WeChat service implementation
-func NewWeChatServiceImpl(wechatService *wechat.Service, logger *zap.Logger) *WeChatServiceImpl {
-	return &WeChatServiceImpl{
-		wechatService: wechatService,
-		logger:        logger,
+func NewWeChatServiceImpl(
+	wechatService *wechat.Service,
+	wechatUserRepo repositories.WeChatUserRepository,
+	db *gorm.DB,
+	logger *zap.Logger,
+) *WeChatServiceImpl {
+	w := &WeChatServiceImpl{
+		wechatService:  wechatService,
+		router:         wechat.NewMessageRouter(),
+		wechatUserRepo: wechatUserRepo,
+		db:             db,
+		welcomeMessage: defaultWelcomeMessage,
+		logger:         logger,
 	}
+	w.registerDefaultHandlers()
+	return w
 }
 
 // GetAccessToken gets the WeChat access token
@@ -71,6 +90,13 @@ func (w *WeChatServiceImpl) SendTextMessage(ctx context.Context, openID, content
 
 // SendTemplateMessage sends a template message to a user
 func (w *WeChatServiceImpl) SendTemplateMessage(ctx context.Context, openID string, templateMsg *services.TemplateMessage) error {
+	_, err := w.SendTrackedTemplateMessage(ctx, openID, templateMsg)
+	return err
+}
+
+// SendTrackedTemplateMessage sends a template message to a user and returns the msgid WeChat
+// reports its delivery with
+func (w *WeChatServiceImpl) SendTrackedTemplateMessage(ctx context.Context, openID string, templateMsg *services.TemplateMessage) (int64, error) {
 	// Convert domain template message to infrastructure type
 	infraTemplateMsg := &wechat.TemplateMessage{
 		ToUser:     openID,
@@ -82,9 +108,14 @@ func (w *WeChatServiceImpl) SendTemplateMessage(ctx context.Context, openID stri
 	return w.wechatService.SendTemplateMessage(ctx, infraTemplateMsg)
 }
 
-// GetUserList gets the list of WeChat users
+// GetUserList gets the first page of the WeChat followers
 func (w *WeChatServiceImpl) GetUserList(ctx context.Context) (*services.WeChatUserList, error) {
-	resp, err := w.wechatService.GetUserList(ctx, "")
+	return w.GetFollowers(ctx, "")
+}
+
+// GetFollowers gets the page of the WeChat followers that starts after nextOpenID
+func (w *WeChatServiceImpl) GetFollowers(ctx context.Context, nextOpenID string) (*services.WeChatUserList, error) {
+	resp, err := w.wechatService.GetUserList(ctx, nextOpenID)
 	if err != nil {
 		return nil, err
 	}
@@ -97,63 +128,346 @@ func (w *WeChatServiceImpl) GetUserList(ctx context.Context) (*services.WeChatUs
 	}
 
 	return &services.WeChatUserList{
-		Total: resp.Total,
-		Count: resp.Count,
-		Data:  userData,
+		Total:      resp.Total,
+		Count:      resp.Count,
+		Data:       userData,
+		NextOpenID: resp.NextOpenID,
 	}, nil
 }
 
-// Placeholder implementations for other interface methods
-// These would need to be implemented based on actual requirements
-
-func (w *WeChatServiceImpl) ProcessMessage(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
-	return nil, fmt.Errorf("ProcessMessage not implemented")
+// GetUserInfo gets the profile of a follower
+func (w *WeChatServiceImpl) GetUserInfo(ctx context.Context, openID string) (*services.WeChatUserInfo, error) {
+	infos, err := w.BatchGetUserInfo(ctx, []string{openID})
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("WeChat user %s not found", openID)
+	}
+	return &infos[0], nil
 }
 
-func (w *WeChatServiceImpl) GetUserInfo(ctx context.Context, openID string) (*services.WeChatUserInfo, error) {
-	return nil, fmt.Errorf("GetUserInfo not implemented")
+// BatchGetUserInfo gets the profiles of up to wechat.MaxUserInfoBatch followers
+func (w *WeChatServiceImpl) BatchGetUserInfo(ctx context.Context, openIDs []string) ([]services.WeChatUserInfo, error) {
+	users, err := w.wechatService.BatchGetUserInfo(ctx, openIDs, "zh_CN")
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]services.WeChatUserInfo, len(users))
+	for i, user := range users {
+		infos[i] = services.WeChatUserInfo{
+			OpenID:        user.OpenID,
+			UnionID:       user.UnionID,
+			Nickname:      user.Nickname,
+			Sex:           user.Sex,
+			City:          user.City,
+			Country:       user.Country,
+			Province:      user.Province,
+			Language:      user.Language,
+			HeadImgURL:    user.HeadImgURL,
+			Subscribe:     user.Subscribe,
+			SubscribeTime: user.SubscribeTime,
+			Remark:        user.Remark,
+			TagIDs:        user.TagIDList,
+		}
+	}
+	return infos, nil
 }
 
+// CreateMenu replaces the default custom menu
 func (w *WeChatServiceImpl) CreateMenu(ctx context.Context, menu *services.Menu) error {
-	return fmt.Errorf("CreateMenu not implemented")
+	return w.wechatService.CreateMenu(ctx, toWeChatMenu(menu))
 }
 
+// GetMenu gets the default custom menu
 func (w *WeChatServiceImpl) GetMenu(ctx context.Context) (*services.Menu, error) {
-	return nil, fmt.Errorf("GetMenu not implemented")
+	resp, err := w.wechatService.GetMenu(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromWeChatMenu(&resp.Menu), nil
 }
 
+// DeleteMenu deletes the default custom menu together with the conditional menus
 func (w *WeChatServiceImpl) DeleteMenu(ctx context.Context) error {
-	return fmt.Errorf("DeleteMenu not implemented")
+	return w.wechatService.DeleteMenu(ctx)
+}
+
+// AddConditionalMenu creates a conditional custom menu and returns the menu ID WeChat assigned to it
+func (w *WeChatServiceImpl) AddConditionalMenu(ctx context.Context, menu *services.Menu) (string, error) {
+	return w.wechatService.AddConditionalMenu(ctx, toWeChatMenu(menu))
+}
+
+// DeleteConditionalMenu deletes a conditional custom menu
+func (w *WeChatServiceImpl) DeleteConditionalMenu(ctx context.Context, menuID string) error {
+	return w.wechatService.DeleteConditionalMenu(ctx, menuID)
+}
+
+// CreateTag creates a user tag
+func (w *WeChatServiceImpl) CreateTag(ctx context.Context, name string) (*services.WeChatTag, error) {
+	tag, err := w.wechatService.CreateTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &services.WeChatTag{ID: tag.ID, Name: tag.Name, Count: tag.Count}, nil
 }
 
+// GetTags lists the user tags
+func (w *WeChatServiceImpl) GetTags(ctx context.Context) ([]services.WeChatTag, error) {
+	tags, err := w.wechatService.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]services.WeChatTag, len(tags))
+	for i, tag := range tags {
+		result[i] = services.WeChatTag{ID: tag.ID, Name: tag.Name, Count: tag.Count}
+	}
+	return result, nil
+}
+
+// UpdateTag renames a user tag
+func (w *WeChatServiceImpl) UpdateTag(ctx context.Context, tagID int, name string) error {
+	return w.wechatService.UpdateTag(ctx, tagID, name)
+}
+
+// DeleteTag deletes a user tag
+func (w *WeChatServiceImpl) DeleteTag(ctx context.Context, tagID int) error {
+	return w.wechatService.DeleteTag(ctx, tagID)
+}
+
+// BatchTagUsers tags up to wechat.MaxTagBatch followers
+func (w *WeChatServiceImpl) BatchTagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	return w.wechatService.BatchTagUsers(ctx, tagID, openIDs)
+}
+
+// BatchUntagUsers removes a tag from up to wechat.MaxTagBatch followers
+func (w *WeChatServiceImpl) BatchUntagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	return w.wechatService.BatchUntagUsers(ctx, tagID, openIDs)
+}
+
+// GetTagFollowers gets a page of the followers carrying a tag
+func (w *WeChatServiceImpl) GetTagFollowers(ctx context.Context, tagID int, nextOpenID string) (*services.WeChatUserList, error) {
+	resp, err := w.wechatService.GetTagFollowers(ctx, tagID, nextOpenID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.WeChatUserList{
+		Total:      resp.Total,
+		Count:      resp.Count,
+		Data:       resp.Data.OpenIDs,
+		NextOpenID: resp.NextOpenID,
+	}, nil
+}
+
+// SendMassByTag broadcasts a mass message to the followers carrying a tag
+func (w *WeChatServiceImpl) SendMassByTag(ctx context.Context, tagID int, msg *services.MassMessage) (*services.MassSendResult, error) {
+	resp, err := w.wechatService.SendMassByTag(ctx, tagID, toWeChatMassMessage(msg))
+	if err != nil {
+		return nil, err
+	}
+	return &services.MassSendResult{MsgID: resp.MsgID, MsgDataID: resp.MsgDataID}, nil
+}
+
+// SendMassByOpenIDs broadcasts a mass message to a list of followers
+func (w *WeChatServiceImpl) SendMassByOpenIDs(ctx context.Context, openIDs []string, msg *services.MassMessage) (*services.MassSendResult, error) {
+	resp, err := w.wechatService.SendMassByOpenIDs(ctx, openIDs, toWeChatMassMessage(msg))
+	if err != nil {
+		return nil, err
+	}
+	return &services.MassSendResult{MsgID: resp.MsgID, MsgDataID: resp.MsgDataID}, nil
+}
+
+// PreviewMass sends a mass message to a single follower
+func (w *WeChatServiceImpl) PreviewMass(ctx context.Context, openID string, msg *services.MassMessage) error {
+	return w.wechatService.PreviewMass(ctx, openID, toWeChatMassMessage(msg))
+}
+
+// GetMassStatus returns the status WeChat reports for a mass message
+func (w *WeChatServiceImpl) GetMassStatus(ctx context.Context, msgID int64) (string, error) {
+	return w.wechatService.GetMassStatus(ctx, msgID)
+}
+
+// toWeChatMassMessage converts a domain mass message to the infrastructure type
+func toWeChatMassMessage(msg *services.MassMessage) *wechat.MassMessage {
+	return &wechat.MassMessage{MsgType: msg.MsgType, MediaID: msg.MediaID, Content: msg.Content, ClientMsgID: msg.ClientMsgID}
+}
+
+// toWeChatMenu converts a domain menu to the infrastructure type
+func toWeChatMenu(menu *services.Menu) *wechat.Menu {
+	converted := &wechat.Menu{Buttons: toWeChatMenuButtons(menu.Buttons), MenuID: menu.MenuID}
+	if menu.MatchRule != nil {
+		rule := wechat.MenuMatchRule(*menu.MatchRule)
+		converted.MatchRule = &rule
+	}
+	return converted
+}
+
+func toWeChatMenuButtons(buttons []services.MenuButton) []wechat.MenuButton {
+	if buttons == nil {
+		return nil
+	}
+	converted := make([]wechat.MenuButton, len(buttons))
+	for i, button := range buttons {
+		converted[i] = wechat.MenuButton{
+			Type:      button.Type,
+			Name:      button.Name,
+			Key:       button.Key,
+			URL:       button.URL,
+			AppID:     button.AppID,
+			PagePath:  button.PagePath,
+			MediaID:   button.MediaID,
+			ArticleID: button.ArticleID,
+			SubButton: toWeChatMenuButtons(button.SubButton),
+		}
+	}
+	return converted
+}
+
+// fromWeChatMenu converts an infrastructure menu to the domain type
+func fromWeChatMenu(menu *wechat.Menu) *services.Menu {
+	converted := &services.Menu{Buttons: fromWeChatMenuButtons(menu.Buttons), MenuID: menu.MenuID}
+	if menu.MatchRule != nil {
+		rule := services.MenuMatchRule(*menu.MatchRule)
+		converted.MatchRule = &rule
+	}
+	return converted
+}
+
+func fromWeChatMenuButtons(buttons []wechat.MenuButton) []services.MenuButton {
+	if buttons == nil {
+		return nil
+	}
+	converted := make([]services.MenuButton, len(buttons))
+	for i, button := range buttons {
+		converted[i] = services.MenuButton{
+			Type:      button.Type,
+			Name:      button.Name,
+			Key:       button.Key,
+			URL:       button.URL,
+			AppID:     button.AppID,
+			PagePath:  button.PagePath,
+			MediaID:   button.MediaID,
+			ArticleID: button.ArticleID,
+			SubButton: fromWeChatMenuButtons(button.SubButton),
+		}
+	}
+	return converted
+}
+
+// UploadMedia uploads a file as temporary media, which WeChat keeps for three days
 func (w *WeChatServiceImpl) UploadMedia(ctx context.Context, mediaType string, filePath string) (*services.WeChatMediaInfo, error) {
-	return nil, fmt.Errorf("UploadMedia not implemented")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media file %s: %w", filePath, err)
+	}
+	return w.UploadMediaFromBytes(ctx, mediaType, filepath.Base(filePath), data)
 }
 
+// UploadMediaFromBytes uploads content as temporary media, which WeChat keeps for three days
 func (w *WeChatServiceImpl) UploadMediaFromBytes(ctx context.Context, mediaType string, filename string, data []byte) (*services.WeChatMediaInfo, error) {
-	return nil, fmt.Errorf("UploadMediaFromBytes not implemented")
+	resp, err := w.wechatService.UploadTempMedia(ctx, mediaType, filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.WeChatMediaInfo{
+		Type:      mediaType,
+		MediaID:   resp.MediaID,
+		CreatedAt: resp.CreatedAt,
+	}, nil
 }
 
+// GetMedia downloads temporary media. Videos are not downloaded, GetMediaURL links them instead.
 func (w *WeChatServiceImpl) GetMedia(ctx context.Context, mediaID string) ([]byte, error) {
-	return nil, fmt.Errorf("GetMedia not implemented")
+	content, err := w.wechatService.GetTempMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if content.VideoURL != "" {
+		return nil, services.ErrWeChatMediaIsVideo
+	}
+	return content.Data, nil
 }
 
+// GetMediaURL returns the URL of a temporary video. Permanent images get their URL on upload.
 func (w *WeChatServiceImpl) GetMediaURL(ctx context.Context, mediaID string) (string, error) {
-	return "", fmt.Errorf("GetMediaURL not implemented")
+	content, err := w.wechatService.GetTempMedia(ctx, mediaID)
+	if err != nil {
+		return "", err
+	}
+	if content.VideoURL == "" {
+		return "", services.ErrWeChatMediaWithoutURL
+	}
+	return content.VideoURL, nil
 }
 
+// UploadPermanentMedia uploads a file as permanent media
 func (w *WeChatServiceImpl) UploadPermanentMedia(ctx context.Context, mediaType string, filePath string) (*services.WeChatPermanentMediaInfo, error) {
-	return nil, fmt.Errorf("UploadPermanentMedia not implemented")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media file %s: %w", filePath, err)
+	}
+	return w.UploadPermanentMediaFromBytes(ctx, mediaType, filepath.Base(filePath), data)
 }
 
+// UploadPermanentMediaFromBytes uploads content as permanent media. Videos are titled after their
+// file name, as WeChat requires videos to be described.
+func (w *WeChatServiceImpl) UploadPermanentMediaFromBytes(ctx context.Context, mediaType string, filename string, data []byte) (*services.WeChatPermanentMediaInfo, error) {
+	var description *wechat.VideoDescription
+	if mediaType == services.MediaTypeVideo {
+		description = &wechat.VideoDescription{Title: strings.TrimSuffix(filename, filepath.Ext(filename))}
+	}
+
+	resp, err := w.wechatService.AddMaterial(ctx, mediaType, filename, data, description)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.WeChatPermanentMediaInfo{
+		MediaID: resp.MediaID,
+		URL:     resp.URL,
+	}, nil
+}
+
+// GetPermanentMediaList lists the permanent media of a type, at most 20 from offset
 func (w *WeChatServiceImpl) GetPermanentMediaList(ctx context.Context, mediaType string, offset, count int) (*services.WeChatMediaList, error) {
-	return nil, fmt.Errorf("GetPermanentMediaList not implemented")
+	if count <= 0 || count > wechat.MaxMaterialBatch {
+		count = wechat.MaxMaterialBatch
+	}
+
+	resp, err := w.wechatService.BatchGetMaterial(ctx, mediaType, max(offset, 0), count)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]services.WeChatMediaItem, len(resp.Items))
+	for i, item := range resp.Items {
+		items[i] = services.WeChatMediaItem{
+			MediaID:    item.MediaID,
+			Name:       item.Name,
+			UpdateTime: item.UpdateTime,
+			URL:        item.URL,
+		}
+	}
+	return &services.WeChatMediaList{
+		Type:       mediaType,
+		TotalCount: resp.TotalCount,
+		ItemCount:  resp.ItemCount,
+		Items:      items,
+	}, nil
 }
 
+// DeletePermanentMedia deletes permanent media
 func (w *WeChatServiceImpl) DeletePermanentMedia(ctx context.Context, mediaID string) error {
-	return fmt.Errorf("DeletePermanentMedia not implemented")
+	return w.wechatService.DeleteMaterial(ctx, mediaID)
 }
 
+// Placeholder implementations for other interface methods
+// These would need to be implemented based on actual requirements
+
 func (w *WeChatServiceImpl) GetMiniProgramSession(ctx context.Context, code string) (*services.MiniProgramSession, error) {
 	return nil, fmt.Errorf("GetMiniProgramSession not implemented")
 }
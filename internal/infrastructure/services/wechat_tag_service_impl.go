@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxWeChatTagName is the longest tag name WeChat accepts, in characters
+	maxWeChatTagName = 30
+	// eventAttendeeTagPage is how many checked-in followers are loaded at a time when tagging an event
+	eventAttendeeTagPage = 500
+)
+
+// WeChatTagServiceImpl implements the WeChatTagService interface
+type WeChatTagServiceImpl struct {
+	tagManager     services.WeChatTagManager
+	eventRepo      repositories.SiteEventRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	logger         *zap.Logger
+}
+
+// NewWeChatTagService creates a new WeChat tag service implementation
+func NewWeChatTagService(
+	tagManager services.WeChatTagManager,
+	eventRepo repositories.SiteEventRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	logger *zap.Logger,
+) *WeChatTagServiceImpl {
+	return &WeChatTagServiceImpl{
+		tagManager:     tagManager,
+		eventRepo:      eventRepo,
+		wechatUserRepo: wechatUserRepo,
+		logger:         logger,
+	}
+}
+
+// GetTags lists the user tags of the official account
+func (s *WeChatTagServiceImpl) GetTags(ctx context.Context) ([]services.WeChatTag, error) {
+	return s.tagManager.GetTags(ctx)
+}
+
+// CreateTag creates a user tag
+func (s *WeChatTagServiceImpl) CreateTag(ctx context.Context, name string) (*services.WeChatTag, error) {
+	name, err := validTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.tagManager.CreateTag(ctx, name)
+}
+
+// UpdateTag renames a user tag
+func (s *WeChatTagServiceImpl) UpdateTag(ctx context.Context, tagID int, name string) error {
+	name, err := validTagName(name)
+	if err != nil {
+		return err
+	}
+	return s.tagManager.UpdateTag(ctx, tagID, name)
+}
+
+// DeleteTag deletes a user tag
+func (s *WeChatTagServiceImpl) DeleteTag(ctx context.Context, tagID int) error {
+	return s.tagManager.DeleteTag(ctx, tagID)
+}
+
+// TagUsers tags followers in batches of the most WeChat accepts per request
+func (s *WeChatTagServiceImpl) TagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	for start := 0; start < len(openIDs); start += wechat.MaxTagBatch {
+		end := min(start+wechat.MaxTagBatch, len(openIDs))
+		if err := s.tagManager.BatchTagUsers(ctx, tagID, openIDs[start:end]); err != nil {
+			return fmt.Errorf("failed to tag followers %d to %d: %w", start+1, end, err)
+		}
+	}
+	return nil
+}
+
+// UntagUsers removes a tag from followers in batches of the most WeChat accepts per request
+func (s *WeChatTagServiceImpl) UntagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	for start := 0; start < len(openIDs); start += wechat.MaxTagBatch {
+		end := min(start+wechat.MaxTagBatch, len(openIDs))
+		if err := s.tagManager.BatchUntagUsers(ctx, tagID, openIDs[start:end]); err != nil {
+			return fmt.Errorf("failed to untag followers %d to %d: %w", start+1, end, err)
+		}
+	}
+	return nil
+}
+
+// TagEventAttendees tags the subscribed followers who checked in to an event. Events without a
+// tag get one named after their tag name, or their title, which is saved on the event.
+func (s *WeChatTagServiceImpl) TagEventAttendees(ctx context.Context, eventID uuid.UUID) (*services.WeChatEventTagging, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if event.UserTagID == 0 {
+		name := strings.TrimSpace(event.TagName)
+		if name == "" {
+			name = truncateRunes(strings.TrimSpace(event.EventTitle), maxWeChatTagName)
+		}
+		tag, err := s.CreateTag(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		event.UserTagID = tag.ID
+		event.TagName = tag.Name
+		if err := s.eventRepo.Update(ctx, event); err != nil {
+			return nil, fmt.Errorf("failed to save tag of event: %w", err)
+		}
+	}
+
+	subscribed := true
+	filter := repositories.WeChatUserFilter{
+		Subscribe:        &subscribed,
+		CheckedInEventID: &eventID,
+		Limit:            eventAttendeeTagPage,
+	}
+	tagging := &services.WeChatEventTagging{EventID: eventID, TagID: event.UserTagID, TagName: event.TagName}
+	for {
+		users, err := s.wechatUserRepo.GetAll(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checked-in followers: %w", err)
+		}
+
+		openIDs := make([]string, 0, len(users))
+		for _, user := range users {
+			if user.OpenID != "" {
+				openIDs = append(openIDs, user.OpenID)
+			}
+		}
+		if err := s.TagUsers(ctx, event.UserTagID, openIDs); err != nil {
+			return nil, err
+		}
+		tagging.Tagged += len(openIDs)
+
+		if len(users) < filter.Limit {
+			break
+		}
+		filter.Offset += filter.Limit
+	}
+
+	s.logger.Info("Tagged WeChat followers who checked in to event",
+		zap.String("eventId", eventID.String()),
+		zap.Int("tagId", tagging.TagID),
+		zap.Int("tagged", tagging.Tagged))
+	return tagging, nil
+}
+
+// validTagName trims a tag name and checks its length
+func validTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxWeChatTagName {
+		return "", services.ErrInvalidWeChatTagName
+	}
+	return name, nil
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
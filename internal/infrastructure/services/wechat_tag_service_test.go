@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+func (r *memoryEventRepository) Update(ctx context.Context, event *entities.SiteEvent) error {
+	copied := *event
+	r.events[event.ID] = &copied
+	return nil
+}
+
+// fakeWeChatTagAPI serves the WeChat tag APIs
+type fakeWeChatTagAPI struct {
+	mu      sync.Mutex
+	tags    []wechat.Tag
+	tagged  map[int][]string
+	batches []int // Followers tagged per request
+}
+
+func (f *fakeWeChatTagAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/cgi-bin/token" && r.URL.Query().Get("access_token") != "token" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 41001, "errmsg": "access_token missing"})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/cgi-bin/token":
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 7200})
+	case "/cgi-bin/tags/create":
+		var request struct {
+			Tag wechat.Tag `json:"tag"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		tag := wechat.Tag{ID: 100 + len(f.tags), Name: request.Tag.Name}
+		f.tags = append(f.tags, tag)
+		json.NewEncoder(w).Encode(map[string]interface{}{"tag": tag})
+	case "/cgi-bin/tags/get":
+		json.NewEncoder(w).Encode(map[string]interface{}{"tags": f.tags})
+	case "/cgi-bin/tags/members/batchtagging":
+		var request struct {
+			TagID      int      `json:"tagid"`
+			OpenIDList []string `json:"openid_list"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if len(request.OpenIDList) > wechat.MaxTagBatch {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 45059, "errmsg": "too many openids"})
+			return
+		}
+		f.batches = append(f.batches, len(request.OpenIDList))
+		f.tagged[request.TagID] = append(f.tagged[request.TagID], request.OpenIDList...)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type WeChatTagServiceTestSuite struct {
+	suite.Suite
+	api     *fakeWeChatTagAPI
+	server  *httptest.Server
+	events  *memoryEventRepository
+	users   *memoryWeChatUserRepository
+	service *WeChatTagServiceImpl
+	ctx     context.Context
+}
+
+func (suite *WeChatTagServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.api = &fakeWeChatTagAPI{tagged: map[int][]string{}}
+	suite.server = httptest.NewServer(suite.api)
+
+	apiService := wechat.NewService(&wechat.Config{AppID: "wx123", AppSecret: "secret", APIBaseURL: suite.server.URL}, nil, zap.NewNop())
+	suite.events = &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{}}
+	suite.users = &memoryWeChatUserRepository{checkIns: map[uuid.UUID][]uuid.UUID{}}
+	suite.service = NewWeChatTagService(
+		NewWeChatServiceImpl(apiService, suite.users, nil, zap.NewNop()),
+		suite.events,
+		suite.users,
+		zap.NewNop(),
+	)
+}
+
+func (suite *WeChatTagServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *WeChatTagServiceTestSuite) TestTagEventAttendeesCreatesEventTag() {
+	event := &entities.SiteEvent{ID: uuid.New(), EventTitle: strings.Repeat("Developer Conference ", 3)}
+	suite.events.events[event.ID] = event
+
+	var expected []string
+	for i := 1; i <= 120; i++ {
+		user := &entities.WeChatUser{ID: uuid.New(), OpenID: fmt.Sprintf("openid-%03d", i), Subscribe: true}
+		suite.users.users = append(suite.users.users, user)
+		suite.users.checkIns[event.ID] = append(suite.users.checkIns[event.ID], user.ID)
+		expected = append(expected, user.OpenID)
+	}
+	unfollowed := &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-unfollowed"}
+	absent := &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-absent", Subscribe: true}
+	suite.users.users = append(suite.users.users, unfollowed, absent)
+	suite.users.checkIns[event.ID] = append(suite.users.checkIns[event.ID], unfollowed.ID)
+
+	tagging, err := suite.service.TagEventAttendees(suite.ctx, event.ID)
+	suite.Require().NoError(err)
+	suite.Equal(120, tagging.Tagged)
+	suite.Equal(100, tagging.TagID)
+	suite.Equal([]int{50, 50, 20}, suite.api.batches)
+	suite.Equal(expected, suite.api.tagged[100])
+
+	// The tag is named after the title, cut to the length WeChat accepts, and saved on the event
+	suite.Equal("Developer Conference Developer", tagging.TagName)
+	saved, err := suite.events.GetByID(suite.ctx, event.ID)
+	suite.Require().NoError(err)
+	suite.Equal(100, saved.UserTagID)
+	suite.Equal(tagging.TagName, saved.TagName)
+
+	// Tagging again reuses the event tag
+	_, err = suite.service.TagEventAttendees(suite.ctx, event.ID)
+	suite.Require().NoError(err)
+	suite.Len(suite.api.tags, 1)
+}
+
+func (suite *WeChatTagServiceTestSuite) TestCreateTagValidatesName() {
+	_, err := suite.service.CreateTag(suite.ctx, "   ")
+	suite.ErrorIs(err, services.ErrInvalidWeChatTagName)
+	_, err = suite.service.CreateTag(suite.ctx, strings.Repeat("标签", 16))
+	suite.ErrorIs(err, services.ErrInvalidWeChatTagName)
+
+	tag, err := suite.service.CreateTag(suite.ctx, " VIP ")
+	suite.Require().NoError(err)
+	suite.Equal("VIP", tag.Name)
+
+	tags, err := suite.service.GetTags(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]services.WeChatTag{{ID: tag.ID, Name: "VIP"}}, tags)
+}
+
+func TestWeChatTagServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeChatTagServiceTestSuite))
+}
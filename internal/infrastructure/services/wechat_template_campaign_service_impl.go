@@ -0,0 +1,587 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+)
+
+// WeChat errors of template messages that decide how a delivery is retried
+const (
+	wechatErrInvalidOpenID     = 40003
+	wechatErrInvalidTemplateID = 40037 // Fails every delivery of the campaign
+	wechatErrNotSubscribed     = 43004
+	wechatErrUserRefused       = 43101 // The follower refused messages of the template
+	wechatErrQuotaExceeded     = 45009 // Daily quota of the template or of the API
+)
+
+const (
+	// campaignAttendeePageSize is the page size used to read the attendees of an event
+	campaignAttendeePageSize = 500
+	// campaignDeliveryBatchSize bounds the deliveries created by one insert
+	campaignDeliveryBatchSize = 500
+	// campaignStalledBatchSize bounds the stalled campaigns resumed at once
+	campaignStalledBatchSize = 100
+)
+
+// WeChatTemplateCampaignConfig holds configuration for WeChat template campaigns
+type WeChatTemplateCampaignConfig struct {
+	RatePerSecond float64       // Template messages sent per second by each process
+	BatchSize     int           // Deliveries sent by one run before the next run is queued
+	MaxAttempts   int           // Attempts of a delivery failing with temporary errors
+	RetryDelay    time.Duration // Wait before the run retrying deliveries that failed with temporary errors
+	QuotaDelay    time.Duration // Wait before resuming a campaign that exhausted the daily quota
+	StallTimeout  time.Duration // Inactivity after which a sending campaign is queued again, longer than QuotaDelay
+}
+
+// DefaultWeChatTemplateCampaignConfig returns the campaign configuration used when none is given
+func DefaultWeChatTemplateCampaignConfig() *WeChatTemplateCampaignConfig {
+	return &WeChatTemplateCampaignConfig{
+		RatePerSecond: 20,
+		BatchSize:     500,
+		MaxAttempts:   3,
+		RetryDelay:    time.Minute,
+		QuotaDelay:    time.Hour,
+		StallTimeout:  3 * time.Hour,
+	}
+}
+
+// WeChatTemplateCampaignServiceImpl implements the WeChatTemplateCampaignService interface
+type WeChatTemplateCampaignServiceImpl struct {
+	campaignRepo   repositories.WeChatTemplateCampaignRepository
+	deliveryRepo   repositories.WeChatTemplateDeliveryRepository
+	attendeeRepo   repositories.EventAttendeeRepository
+	eventRepo      repositories.SiteEventRepository
+	surveyRepo     repositories.SurveyRepository
+	invitationRepo repositories.SurveyInvitationRepository
+	wechatUserRepo repositories.WeChatUserRepository
+	sender         services.WeChatTemplateCampaignSender
+	queue          services.WeChatTemplateCampaignQueue
+	config         *WeChatTemplateCampaignConfig
+	limiter        *rate.Limiter
+	logger         *zap.Logger
+}
+
+// NewWeChatTemplateCampaignService creates a new WeChat template campaign service implementation.
+// Without a queue campaigns run in the background of the current process.
+func NewWeChatTemplateCampaignService(
+	campaignRepo repositories.WeChatTemplateCampaignRepository,
+	deliveryRepo repositories.WeChatTemplateDeliveryRepository,
+	attendeeRepo repositories.EventAttendeeRepository,
+	eventRepo repositories.SiteEventRepository,
+	surveyRepo repositories.SurveyRepository,
+	invitationRepo repositories.SurveyInvitationRepository,
+	wechatUserRepo repositories.WeChatUserRepository,
+	sender services.WeChatTemplateCampaignSender,
+	queue services.WeChatTemplateCampaignQueue,
+	config *WeChatTemplateCampaignConfig,
+	logger *zap.Logger,
+) *WeChatTemplateCampaignServiceImpl {
+	if config == nil {
+		config = DefaultWeChatTemplateCampaignConfig()
+	}
+	return &WeChatTemplateCampaignServiceImpl{
+		campaignRepo:   campaignRepo,
+		deliveryRepo:   deliveryRepo,
+		attendeeRepo:   attendeeRepo,
+		eventRepo:      eventRepo,
+		surveyRepo:     surveyRepo,
+		invitationRepo: invitationRepo,
+		wechatUserRepo: wechatUserRepo,
+		sender:         sender,
+		queue:          queue,
+		config:         config,
+		limiter:        rate.NewLimiter(rate.Limit(config.RatePerSecond), max(1, int(config.RatePerSecond))),
+		logger:         logger,
+	}
+}
+
+// RegisterHandlers records the deliveries WeChat reports in TEMPLATESENDJOBFINISH events
+func (s *WeChatTemplateCampaignServiceImpl) RegisterHandlers(router *wechat.MessageRouter) {
+	router.HandleEvent(message.EventTemplateSendJobFinish, s.handleTemplateSendJobFinish)
+}
+
+// CreateCampaign validates a campaign and records it as a draft
+func (s *WeChatTemplateCampaignServiceImpl) CreateCampaign(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error {
+	if err := campaign.Validate(); err != nil {
+		return err
+	}
+	if campaign.EventID != nil {
+		if _, err := s.getEvent(ctx, *campaign.EventID); err != nil {
+			return err
+		}
+	}
+	if campaign.SurveyID != nil {
+		if _, err := s.surveyRepo.FindByID(ctx, *campaign.SurveyID); err != nil {
+			return err
+		}
+	}
+	if err := campaign.SetFields(campaign.FieldMappings); err != nil {
+		return err
+	}
+	campaign.ID = uuid.New()
+	campaign.Status = entities.WeChatTemplateCampaignStatusDraft
+	return s.campaignRepo.Create(ctx, campaign)
+}
+
+// GetCampaign gets a campaign with its deliveries counted by status
+func (s *WeChatTemplateCampaignServiceImpl) GetCampaign(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.deliveryRepo.CountByStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Stats = stats
+	return campaign, nil
+}
+
+// GetRecentCampaigns gets the campaigns created last
+func (s *WeChatTemplateCampaignServiceImpl) GetRecentCampaigns(ctx context.Context, limit int) ([]entities.WeChatTemplateCampaign, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.campaignRepo.FindRecent(ctx, limit)
+}
+
+// GetDeliveries lists the deliveries of a campaign with a status, or all of them for ""
+func (s *WeChatTemplateCampaignServiceImpl) GetDeliveries(ctx context.Context, campaignID uuid.UUID, status string, offset, limit int) ([]entities.WeChatTemplateDelivery, int64, error) {
+	if _, err := s.campaignRepo.FindByID(ctx, campaignID); err != nil {
+		return nil, 0, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.deliveryRepo.FindByCampaign(ctx, campaignID, status, offset, limit)
+}
+
+// StartCampaign starts sending a draft campaign
+func (s *WeChatTemplateCampaignServiceImpl) StartCampaign(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != entities.WeChatTemplateCampaignStatusDraft {
+		return nil, services.ErrWeChatTemplateCampaignStarted
+	}
+
+	campaign.Start(time.Now())
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		return nil, err
+	}
+	if err := s.schedule(ctx, campaign.ID, 0); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat template campaign started",
+		zap.String("campaignId", campaign.ID.String()),
+		zap.String("audience", campaign.Audience))
+	return campaign, nil
+}
+
+// CancelCampaign stops a campaign, cancelling the deliveries not sent yet. Messages already sent
+// keep being tracked.
+func (s *WeChatTemplateCampaignServiceImpl) CancelCampaign(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.IsFinished() {
+		return nil, services.ErrWeChatTemplateCampaignFinished
+	}
+
+	campaign.Finish(entities.WeChatTemplateCampaignStatusCancelled, "", time.Now())
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		return nil, err
+	}
+	cancelled, err := s.deliveryRepo.CancelPending(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("WeChat template campaign cancelled",
+		zap.String("campaignId", campaign.ID.String()),
+		zap.Int64("cancelledDeliveries", cancelled))
+	return campaign, nil
+}
+
+// RunCampaign resolves the recipients of a started campaign on its first run, then sends a batch
+// of its pending deliveries at the configured rate. The next run is queued while deliveries are
+// pending: right away, after RetryDelay when deliveries failed with temporary errors, or after
+// QuotaDelay when WeChat reports the daily quota as exhausted. A campaign without pending
+// deliveries is completed.
+func (s *WeChatTemplateCampaignServiceImpl) RunCampaign(ctx context.Context, id uuid.UUID) error {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if campaign.Status != entities.WeChatTemplateCampaignStatusSending {
+		return nil
+	}
+
+	if campaign.ResolvedAt == nil {
+		if err := s.resolveRecipients(ctx, campaign); err != nil {
+			return err
+		}
+	}
+
+	deliveries, err := s.deliveryRepo.FindPending(ctx, campaign.ID, s.config.BatchSize)
+	if err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		return s.finish(ctx, campaign, entities.WeChatTemplateCampaignStatusCompleted, "")
+	}
+
+	var delay time.Duration
+	for i := range deliveries {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		stopErr, err := s.deliver(ctx, campaign, &deliveries[i])
+		if err != nil {
+			return err
+		}
+		var apiErr *wechat.APIError
+		if errors.As(stopErr, &apiErr) && apiErr.Code == wechatErrQuotaExceeded {
+			s.logger.Warn("WeChat template quota exhausted, pausing campaign",
+				zap.String("campaignId", campaign.ID.String()),
+				zap.Duration("delay", s.config.QuotaDelay))
+			campaign.LastError = stopErr.Error()
+			if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+				return err
+			}
+			return s.schedule(ctx, campaign.ID, s.config.QuotaDelay)
+		}
+		if stopErr != nil {
+			if _, err := s.deliveryRepo.CancelPending(ctx, campaign.ID); err != nil {
+				return err
+			}
+			return s.finish(ctx, campaign, entities.WeChatTemplateCampaignStatusFailed, stopErr.Error())
+		}
+		if deliveries[i].Status == entities.WeChatTemplateDeliveryStatusPending {
+			delay = s.config.RetryDelay
+		}
+	}
+
+	return s.schedule(ctx, campaign.ID, delay)
+}
+
+// ResumeStalledCampaigns queues a run of the sending campaigns neither they nor their deliveries
+// were updated for StallTimeout, such as campaigns whose queued run was dropped because no worker
+// handled it. StallTimeout outlasts QuotaDelay, so a campaign waiting for its quota is left alone.
+func (s *WeChatTemplateCampaignServiceImpl) ResumeStalledCampaigns(ctx context.Context) (int, error) {
+	campaigns, err := s.campaignRepo.FindStalled(ctx, time.Now().Add(-s.config.StallTimeout), campaignStalledBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	for _, campaign := range campaigns {
+		if err := s.schedule(ctx, campaign.ID, 0); err != nil {
+			s.logger.Error("Failed to resume stalled WeChat template campaign",
+				zap.String("campaignId", campaign.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		s.logger.Warn("Resumed stalled WeChat template campaign",
+			zap.String("campaignId", campaign.ID.String()),
+			zap.Time("updatedAt", campaign.UpdatedAt))
+		resumed++
+	}
+	return resumed, nil
+}
+
+// deliver sends the message of a delivery and records the outcome. Errors stopping the whole
+// campaign, an exhausted daily quota or an invalid template, are returned as stopErr without
+// recording an attempt, as the delivery is not at fault.
+func (s *WeChatTemplateCampaignServiceImpl) deliver(ctx context.Context, campaign *entities.WeChatTemplateCampaign, delivery *entities.WeChatTemplateDelivery) (stopErr, err error) {
+	data, err := delivery.GetData()
+	if err != nil {
+		delivery.RecordFailure(err.Error(), true, s.config.MaxAttempts, time.Now())
+		return nil, s.deliveryRepo.Update(ctx, delivery)
+	}
+
+	msgID, sendErr := s.sender.SendTrackedTemplateMessage(ctx, delivery.OpenID, &services.TemplateMessage{
+		TemplateID: campaign.TemplateID,
+		URL:        delivery.URL,
+		Data:       data,
+	})
+	if sendErr == nil {
+		delivery.Sent(msgID, time.Now())
+		return nil, s.deliveryRepo.Update(ctx, delivery)
+	}
+
+	permanent := false
+	var apiErr *wechat.APIError
+	if errors.As(sendErr, &apiErr) {
+		switch apiErr.Code {
+		case wechatErrQuotaExceeded, wechatErrInvalidTemplateID:
+			return sendErr, nil
+		case wechatErrInvalidOpenID, wechatErrNotSubscribed, wechatErrUserRefused:
+			permanent = true
+		}
+	}
+
+	s.logger.Warn("Failed to send WeChat template campaign message",
+		zap.String("campaignId", campaign.ID.String()),
+		zap.String("openId", delivery.OpenID),
+		zap.Int("attempts", delivery.Attempts+1),
+		zap.Error(sendErr))
+	delivery.RecordFailure(sendErr.Error(), permanent, s.config.MaxAttempts, time.Now())
+	return nil, s.deliveryRepo.Update(ctx, delivery)
+}
+
+// resolveRecipients creates a pending delivery, rendered from the follower and attendee data,
+// for each recipient of the campaign audience
+func (s *WeChatTemplateCampaignServiceImpl) resolveRecipients(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error {
+	var event *entities.SiteEvent
+	if campaign.EventID != nil {
+		var err error
+		if event, err = s.getEvent(ctx, *campaign.EventID); err != nil {
+			return err
+		}
+	}
+	var survey *entities.Survey
+	if campaign.SurveyID != nil {
+		var err error
+		if survey, err = s.surveyRepo.FindByID(ctx, *campaign.SurveyID); err != nil {
+			return err
+		}
+	}
+
+	var recipients []entities.WeChatTemplateRecipient
+	var err error
+	switch campaign.Audience {
+	case entities.WeChatCampaignAudienceEventAttendees:
+		recipients, err = s.eventRecipients(ctx, campaign)
+	case entities.WeChatCampaignAudienceSurveyNonResponders:
+		recipients, err = s.nonResponderRecipients(ctx, campaign)
+	case entities.WeChatCampaignAudienceTag:
+		recipients, err = s.tagRecipients(ctx, campaign)
+	default:
+		err = fmt.Errorf("%w: unsupported audience %q", entities.ErrInvalidWeChatTemplateCampaign, campaign.Audience)
+	}
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]entities.WeChatTemplateDelivery, 0, len(recipients))
+	for i := range recipients {
+		delivery, err := campaign.NewDelivery(&recipients[i], recipients[i].Values(event, survey))
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	for start := 0; start < len(deliveries); start += campaignDeliveryBatchSize {
+		if err := s.deliveryRepo.CreateBatch(ctx, deliveries[start:min(start+campaignDeliveryBatchSize, len(deliveries))]); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	campaign.Total = len(deliveries)
+	campaign.ResolvedAt = &now
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		return err
+	}
+
+	s.logger.Info("WeChat template campaign recipients resolved",
+		zap.String("campaignId", campaign.ID.String()),
+		zap.Int("recipients", campaign.Total))
+	return nil
+}
+
+// eventRecipients finds the registered attendees of the event who follow the account, or those
+// who checked in when the campaign is limited to them. Waitlisted and cancelled attendees are left out.
+func (s *WeChatTemplateCampaignServiceImpl) eventRecipients(ctx context.Context, campaign *entities.WeChatTemplateCampaign) ([]entities.WeChatTemplateRecipient, error) {
+	var recipients []entities.WeChatTemplateRecipient
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += campaignAttendeePageSize {
+		attendees, err := s.attendeeRepo.GetByEvent(ctx, *campaign.EventID, offset, campaignAttendeePageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event attendees: %w", err)
+		}
+		for _, attendee := range attendees {
+			if attendee.IsDeleted || !attendee.IsRegistered() || (campaign.CheckedInOnly && !attendee.OnSiteScanned) {
+				continue
+			}
+			user := attendee.User
+			if user == nil || !user.Subscribe || user.OpenID == "" || seen[user.OpenID] {
+				continue
+			}
+			seen[user.OpenID] = true
+			recipients = append(recipients, entities.WeChatTemplateRecipient{OpenID: user.OpenID, User: user, Attendee: attendee})
+		}
+		if len(attendees) < campaignAttendeePageSize {
+			return recipients, nil
+		}
+	}
+}
+
+// nonResponderRecipients finds the followers invited to the survey through WeChat whose
+// invitation is still open
+func (s *WeChatTemplateCampaignServiceImpl) nonResponderRecipients(ctx context.Context, campaign *entities.WeChatTemplateCampaign) ([]entities.WeChatTemplateRecipient, error) {
+	invitations, err := s.invitationRepo.FindBySurveyID(ctx, *campaign.SurveyID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get survey invitations: %w", err)
+	}
+
+	now := time.Now()
+	var recipients []entities.WeChatTemplateRecipient
+	seen := make(map[string]bool)
+	for i := range invitations {
+		invitation := &invitations[i]
+		if invitation.Channel != entities.SurveyInvitationChannelWeChat || invitation.OpenID == "" || seen[invitation.OpenID] {
+			continue
+		}
+		switch invitation.Status {
+		case entities.SurveyInvitationStatusSent, entities.SurveyInvitationStatusOpened, entities.SurveyInvitationStatusFailed:
+		default:
+			continue
+		}
+		if invitation.RespondedAt != nil || (invitation.ExpiresAt != nil && !now.Before(*invitation.ExpiresAt)) {
+			continue
+		}
+
+		user, err := s.findUser(ctx, invitation.OpenID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && !user.Subscribe {
+			continue
+		}
+		seen[invitation.OpenID] = true
+		recipients = append(recipients, entities.WeChatTemplateRecipient{OpenID: invitation.OpenID, User: user, Invitation: invitation})
+	}
+	return recipients, nil
+}
+
+// tagRecipients pages through the followers carrying the tag
+func (s *WeChatTemplateCampaignServiceImpl) tagRecipients(ctx context.Context, campaign *entities.WeChatTemplateCampaign) ([]entities.WeChatTemplateRecipient, error) {
+	var recipients []entities.WeChatTemplateRecipient
+	seen := make(map[string]bool)
+	nextOpenID := ""
+	for {
+		page, err := s.sender.GetTagFollowers(ctx, *campaign.TagID, nextOpenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tag followers: %w", err)
+		}
+		for _, openID := range page.Data {
+			if seen[openID] {
+				continue
+			}
+			seen[openID] = true
+			user, err := s.findUser(ctx, openID)
+			if err != nil {
+				return nil, err
+			}
+			recipients = append(recipients, entities.WeChatTemplateRecipient{OpenID: openID, User: user})
+		}
+		if len(page.Data) == 0 || page.NextOpenID == "" || page.NextOpenID == nextOpenID {
+			return recipients, nil
+		}
+		nextOpenID = page.NextOpenID
+	}
+}
+
+// handleTemplateSendJobFinish records the delivery WeChat reports for a template message
+func (s *WeChatTemplateCampaignServiceImpl) handleTemplateSendJobFinish(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	delivery, err := s.deliveryRepo.FindByMsgID(ctx, msg.TemplateMsgID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			// Template messages sent outside of campaigns are not tracked
+			s.logger.Debug("WeChat template job finished for untracked message", zap.Int64("msgId", msg.TemplateMsgID))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	delivery.Finish(msg.Status, time.Now())
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	if delivery.Status != entities.WeChatTemplateDeliveryStatusDelivered {
+		s.logger.Info("WeChat template campaign message not delivered",
+			zap.String("campaignId", delivery.CampaignID.String()),
+			zap.String("openId", delivery.OpenID),
+			zap.String("status", msg.Status))
+	}
+	return nil, nil
+}
+
+// finish records the final status of a campaign
+func (s *WeChatTemplateCampaignServiceImpl) finish(ctx context.Context, campaign *entities.WeChatTemplateCampaign, status, reason string) error {
+	campaign.Finish(status, reason, time.Now())
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		return err
+	}
+
+	s.logger.Info("WeChat template campaign finished",
+		zap.String("campaignId", campaign.ID.String()),
+		zap.String("status", status),
+		zap.String("reason", reason))
+	return nil
+}
+
+// schedule queues the next run of a campaign, or runs it in the background without a queue
+func (s *WeChatTemplateCampaignServiceImpl) schedule(ctx context.Context, id uuid.UUID, delay time.Duration) error {
+	if s.queue == nil {
+		go s.runInBackground(id, delay)
+		return nil
+	}
+	return s.queue.EnqueueWeChatTemplateCampaign(ctx, id, delay)
+}
+
+// runInBackground runs a campaign outside of the request or job that scheduled it
+func (s *WeChatTemplateCampaignServiceImpl) runInBackground(id uuid.UUID, delay time.Duration) {
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if err := s.RunCampaign(context.Background(), id); err != nil {
+		s.logger.Error("Failed to run WeChat template campaign", zap.String("campaignId", id.String()), zap.Error(err))
+	}
+}
+
+// getEvent gets an event, mapping a missing event to ErrNotFound
+func (s *WeChatTemplateCampaignServiceImpl) getEvent(ctx context.Context, id uuid.UUID) (*entities.SiteEvent, error) {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return event, nil
+}
+
+// findUser gets the WeChatUser record of a follower, nil for followers not synced yet
+func (s *WeChatTemplateCampaignServiceImpl) findUser(ctx context.Context, openID string) (*entities.WeChatUser, error) {
+	user, err := s.wechatUserRepo.GetByOpenID(ctx, openID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get WeChat user: %w", err)
+	}
+	return user, nil
+}
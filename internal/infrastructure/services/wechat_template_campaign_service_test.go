@@ -0,0 +1,509 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/suite"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// memoryWeChatTemplateCampaignRepository keeps template campaigns in memory in creation order
+type memoryWeChatTemplateCampaignRepository struct {
+	campaigns []entities.WeChatTemplateCampaign
+}
+
+func (r *memoryWeChatTemplateCampaignRepository) Create(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error {
+	campaign.ID = uuid.New()
+	r.campaigns = append(r.campaigns, *campaign)
+	return nil
+}
+
+func (r *memoryWeChatTemplateCampaignRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WeChatTemplateCampaign, error) {
+	for i := range r.campaigns {
+		if r.campaigns[i].ID == id {
+			campaign := r.campaigns[i]
+			return &campaign, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatTemplateCampaignRepository) Update(ctx context.Context, campaign *entities.WeChatTemplateCampaign) error {
+	for i := range r.campaigns {
+		if r.campaigns[i].ID == campaign.ID {
+			r.campaigns[i] = *campaign
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memoryWeChatTemplateCampaignRepository) FindRecent(ctx context.Context, limit int) ([]entities.WeChatTemplateCampaign, error) {
+	var campaigns []entities.WeChatTemplateCampaign
+	for i := len(r.campaigns) - 1; i >= 0 && len(campaigns) < limit; i-- {
+		campaigns = append(campaigns, r.campaigns[i])
+	}
+	return campaigns, nil
+}
+
+func (r *memoryWeChatTemplateCampaignRepository) FindStalled(ctx context.Context, idleSince time.Time, limit int) ([]entities.WeChatTemplateCampaign, error) {
+	var campaigns []entities.WeChatTemplateCampaign
+	for _, campaign := range r.campaigns {
+		if campaign.Status == entities.WeChatTemplateCampaignStatusSending && campaign.UpdatedAt.Before(idleSince) && len(campaigns) < limit {
+			campaigns = append(campaigns, campaign)
+		}
+	}
+	return campaigns, nil
+}
+
+// memoryWeChatTemplateDeliveryRepository keeps template deliveries in memory in creation order
+type memoryWeChatTemplateDeliveryRepository struct {
+	deliveries []entities.WeChatTemplateDelivery
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) CreateBatch(ctx context.Context, deliveries []entities.WeChatTemplateDelivery) error {
+	for _, delivery := range deliveries {
+		duplicate := false
+		for _, existing := range r.deliveries {
+			duplicate = duplicate || (existing.CampaignID == delivery.CampaignID && existing.OpenID == delivery.OpenID)
+		}
+		if !duplicate {
+			delivery.ID = uuid.New()
+			r.deliveries = append(r.deliveries, delivery)
+		}
+	}
+	return nil
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) FindPending(ctx context.Context, campaignID uuid.UUID, limit int) ([]entities.WeChatTemplateDelivery, error) {
+	deliveries, _, err := r.FindByCampaign(ctx, campaignID, entities.WeChatTemplateDeliveryStatusPending, 0, limit)
+	return deliveries, err
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) FindByMsgID(ctx context.Context, msgID int64) (*entities.WeChatTemplateDelivery, error) {
+	for i := range r.deliveries {
+		if r.deliveries[i].MsgID == msgID {
+			delivery := r.deliveries[i]
+			return &delivery, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) FindByCampaign(ctx context.Context, campaignID uuid.UUID, status string, offset, limit int) ([]entities.WeChatTemplateDelivery, int64, error) {
+	var matching []entities.WeChatTemplateDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.CampaignID == campaignID && (status == "" || delivery.Status == status) {
+			matching = append(matching, delivery)
+		}
+	}
+	total := int64(len(matching))
+	if offset >= len(matching) {
+		return nil, total, nil
+	}
+	matching = matching[offset:]
+	if len(matching) > limit {
+		matching = matching[:limit]
+	}
+	return matching, total, nil
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) Update(ctx context.Context, delivery *entities.WeChatTemplateDelivery) error {
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			r.deliveries[i] = *delivery
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) CountByStatus(ctx context.Context, campaignID uuid.UUID) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, delivery := range r.deliveries {
+		if delivery.CampaignID == campaignID {
+			counts[delivery.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryWeChatTemplateDeliveryRepository) CancelPending(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	var cancelled int64
+	for i := range r.deliveries {
+		if r.deliveries[i].CampaignID == campaignID && r.deliveries[i].Status == entities.WeChatTemplateDeliveryStatusPending {
+			r.deliveries[i].Status = entities.WeChatTemplateDeliveryStatusCancelled
+			cancelled++
+		}
+	}
+	return cancelled, nil
+}
+
+// fakeWeChatTemplateCampaignQueue records the campaign runs queued
+type fakeWeChatTemplateCampaignQueue struct {
+	delays []time.Duration
+}
+
+func (q *fakeWeChatTemplateCampaignQueue) EnqueueWeChatTemplateCampaign(ctx context.Context, campaignID uuid.UUID, delay time.Duration) error {
+	q.delays = append(q.delays, delay)
+	return nil
+}
+
+// fakeWeChatTemplateAPI serves the WeChat template message and tag follower APIs
+type fakeWeChatTemplateAPI struct {
+	mu        sync.Mutex
+	sent      []map[string]interface{} // Bodies of the accepted send requests
+	errors    map[string]int           // Error codes returned for followers, by OpenID
+	quota     int                      // Messages accepted before the daily quota is exhausted, unlimited when 0
+	followers map[int][]string
+}
+
+func (f *fakeWeChatTemplateAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/cgi-bin/token" && r.URL.Query().Get("access_token") != "token" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 41001, "errmsg": "access_token missing"})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/cgi-bin/token":
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 7200})
+	case "/cgi-bin/message/template/send":
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		if code, ok := f.errors[request["touser"].(string)]; ok {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": code, "errmsg": "rejected"})
+			return
+		}
+		if f.quota > 0 && len(f.sent) >= f.quota {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 45009, "errmsg": "reach max api daily quota limit"})
+			return
+		}
+		f.sent = append(f.sent, request)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "errmsg": "ok", "msgid": 5000 + len(f.sent)})
+	case "/cgi-bin/user/tag/get":
+		var request struct {
+			TagID      int    `json:"tagid"`
+			NextOpenID string `json:"next_openid"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		openIDs := f.followers[request.TagID]
+		if request.NextOpenID != "" {
+			openIDs = nil
+		}
+		next := ""
+		if len(openIDs) > 0 {
+			next = openIDs[len(openIDs)-1]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": len(openIDs), "data": map[string]interface{}{"openid": openIDs}, "next_openid": next})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type WeChatTemplateCampaignServiceTestSuite struct {
+	suite.Suite
+	api         *fakeWeChatTemplateAPI
+	server      *httptest.Server
+	events      *memoryEventRepository
+	attendees   *memoryAttendeeRepository
+	surveys     *memorySurveyRepository
+	invitations *memorySurveyInvitationRepository
+	users       *memoryWeChatUserRepository
+	campaigns   *memoryWeChatTemplateCampaignRepository
+	deliveries  *memoryWeChatTemplateDeliveryRepository
+	queue       *fakeWeChatTemplateCampaignQueue
+	router      *wechat.MessageRouter
+	service     *WeChatTemplateCampaignServiceImpl
+	ctx         context.Context
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.api = &fakeWeChatTemplateAPI{errors: map[string]int{}, followers: map[int][]string{}}
+	suite.server = httptest.NewServer(suite.api)
+
+	apiService := wechat.NewService(&wechat.Config{AppID: "wx123", AppSecret: "secret", APIBaseURL: suite.server.URL}, nil, zap.NewNop())
+	suite.events = &memoryEventRepository{events: map[uuid.UUID]*entities.SiteEvent{}}
+	suite.attendees = &memoryAttendeeRepository{}
+	suite.surveys = &memorySurveyRepository{surveys: map[uuid.UUID]*entities.Survey{}}
+	suite.invitations = &memorySurveyInvitationRepository{}
+	suite.users = &memoryWeChatUserRepository{}
+	suite.campaigns = &memoryWeChatTemplateCampaignRepository{}
+	suite.deliveries = &memoryWeChatTemplateDeliveryRepository{}
+	suite.queue = &fakeWeChatTemplateCampaignQueue{}
+
+	config := DefaultWeChatTemplateCampaignConfig()
+	config.RatePerSecond = 1000
+	config.BatchSize = 10
+	suite.service = NewWeChatTemplateCampaignService(
+		suite.campaigns,
+		suite.deliveries,
+		suite.attendees,
+		suite.events,
+		suite.surveys,
+		suite.invitations,
+		suite.users,
+		NewWeChatServiceImpl(apiService, suite.users, nil, zap.NewNop()),
+		suite.queue,
+		config,
+		zap.NewNop(),
+	)
+	suite.router = wechat.NewMessageRouter()
+	suite.service.RegisterHandlers(suite.router)
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// addAttendee registers a follower to an event
+func (suite *WeChatTemplateCampaignServiceTestSuite) addAttendee(eventID uuid.UUID, user *entities.WeChatUser, attendee entities.EventAttendee) {
+	suite.users.users = append(suite.users.users, user)
+	attendee.ID = uuid.New()
+	attendee.EventID = eventID
+	attendee.UserID = &user.ID
+	attendee.User = user
+	suite.attendees.attendees = append(suite.attendees.attendees, &attendee)
+}
+
+// finishJob routes the TEMPLATESENDJOBFINISH event WeChat pushes for a message
+func (suite *WeChatTemplateCampaignServiceTestSuite) finishJob(msgID int64, status string) {
+	finished := &message.MixMessage{Event: message.EventTemplateSendJobFinish, TemplateMsgID: msgID, Status: status}
+	finished.MsgType = message.MsgTypeEvent
+	reply, err := suite.router.Route(suite.ctx, finished)
+	suite.Require().NoError(err)
+	suite.Nil(reply)
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) TestEventCampaignRendersAndTracksDeliveries() {
+	event := &entities.SiteEvent{ID: uuid.New(), EventTitle: "DevCon", EventStartDate: time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)}
+	suite.events.events[event.ID] = event
+	suite.addAttendee(event.ID, &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-1", NickName: "li", Subscribe: true},
+		entities.EventAttendee{Name: "Li Lei", Company: "Acme", Status: entities.AttendeeStatusRegistered})
+	suite.addAttendee(event.ID, &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-2", NickName: "han", Subscribe: true},
+		entities.EventAttendee{Status: entities.AttendeeStatusRegistered})
+	suite.addAttendee(event.ID, &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-cancelled", Subscribe: true},
+		entities.EventAttendee{Status: entities.AttendeeStatusCancelled})
+	suite.addAttendee(event.ID, &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-waitlisted", Subscribe: true},
+		entities.EventAttendee{Status: entities.AttendeeStatusWaitlisted})
+	suite.addAttendee(event.ID, &entities.WeChatUser{ID: uuid.New(), OpenID: "openid-unfollowed"},
+		entities.EventAttendee{Status: entities.AttendeeStatusRegistered})
+
+	campaign := &entities.WeChatTemplateCampaign{
+		Name:       "Event reminder",
+		TemplateID: "tpl-reminder",
+		URL:        "https://example.com/events/checkin?openid={openId}",
+		FieldMappings: []entities.WeChatTemplateField{
+			{Key: "first", Value: "Dear {name}", Color: "#173177"},
+			{Key: "keyword1", Value: "{event}"},
+			{Key: "keyword2", Value: "{eventStart}"},
+		},
+		Audience: entities.WeChatCampaignAudienceEventAttendees,
+		EventID:  &event.ID,
+	}
+	suite.Require().NoError(suite.service.CreateCampaign(suite.ctx, campaign))
+	suite.Equal(entities.WeChatTemplateCampaignStatusDraft, campaign.Status)
+
+	started, err := suite.service.StartCampaign(suite.ctx, campaign.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatTemplateCampaignStatusSending, started.Status)
+	suite.Equal([]time.Duration{0}, suite.queue.delays)
+	_, err = suite.service.StartCampaign(suite.ctx, campaign.ID)
+	suite.ErrorIs(err, services.ErrWeChatTemplateCampaignStarted)
+
+	// The first run resolves the subscribed, registered attendees and sends their messages
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+	suite.Require().Len(suite.api.sent, 2)
+	request := suite.api.sent[0]
+	suite.Equal("openid-1", request["touser"])
+	suite.Equal("tpl-reminder", request["template_id"])
+	suite.Equal("https://example.com/events/checkin?openid=openid-1", request["url"])
+	suite.Equal(map[string]interface{}{
+		"first":    map[string]interface{}{"value": "Dear Li Lei", "color": "#173177"},
+		"keyword1": map[string]interface{}{"value": "DevCon"},
+		"keyword2": map[string]interface{}{"value": "2025-03-01 09:30"},
+	}, request["data"])
+	suite.Equal(map[string]interface{}{"value": "Dear han", "color": "#173177"}, suite.api.sent[1]["data"].(map[string]interface{})["first"])
+	suite.Equal([]time.Duration{0, 0}, suite.queue.delays)
+
+	// The next run finds no pending deliveries and completes the campaign
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+	suite.Len(suite.api.sent, 2)
+
+	// WeChat reports the deliveries when the sending jobs finish
+	suite.finishJob(5001, entities.WeChatTemplateJobSuccess)
+	suite.finishJob(5002, "failed:user block")
+	suite.finishJob(9999, entities.WeChatTemplateJobSuccess)
+
+	recorded, err := suite.service.GetCampaign(suite.ctx, campaign.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatTemplateCampaignStatusCompleted, recorded.Status)
+	suite.Equal(2, recorded.Total)
+	suite.NotNil(recorded.CompletedAt)
+	suite.Equal(map[string]int{entities.WeChatTemplateDeliveryStatusDelivered: 1, entities.WeChatTemplateDeliveryStatusFailed: 1}, recorded.Stats)
+
+	failed, total, err := suite.service.GetDeliveries(suite.ctx, campaign.ID, entities.WeChatTemplateDeliveryStatusFailed, 0, 10)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), total)
+	suite.Equal("openid-2", failed[0].OpenID)
+	suite.Equal("failed:user block", failed[0].LastError)
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) TestTagCampaignThrottlesOnQuotaAndRetries() {
+	tagID := 100
+	suite.api.followers[tagID] = []string{"openid-1", "openid-2", "openid-blocked", "openid-busy", "openid-3"}
+	suite.api.errors["openid-blocked"] = 43004
+	suite.api.errors["openid-busy"] = -1
+	suite.api.quota = 2
+	suite.users.users = []*entities.WeChatUser{{ID: uuid.New(), OpenID: "openid-1", NickName: "li", Subscribe: true}}
+
+	campaign := &entities.WeChatTemplateCampaign{
+		Name:          "Tag reminder",
+		TemplateID:    "tpl-reminder",
+		FieldMappings: []entities.WeChatTemplateField{{Key: "first", Value: "Hi {nickname}"}},
+		Audience:      entities.WeChatCampaignAudienceTag,
+		TagID:         &tagID,
+	}
+	suite.Require().NoError(suite.service.CreateCampaign(suite.ctx, campaign))
+	_, err := suite.service.StartCampaign(suite.ctx, campaign.ID)
+	suite.Require().NoError(err)
+
+	// The quota runs out on the fifth follower; the run pauses until the quota is reset
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+	suite.Len(suite.api.sent, 2)
+	suite.Equal("Hi li", suite.api.sent[0]["data"].(map[string]interface{})["first"].(map[string]interface{})["value"])
+	suite.Equal([]time.Duration{0, time.Hour}, suite.queue.delays)
+
+	running, err := suite.service.GetCampaign(suite.ctx, campaign.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatTemplateCampaignStatusSending, running.Status)
+	suite.Contains(running.LastError, "45009")
+	suite.Equal(map[string]int{
+		entities.WeChatTemplateDeliveryStatusSent:    2,
+		entities.WeChatTemplateDeliveryStatusFailed:  1, // Unsubscribed follower
+		entities.WeChatTemplateDeliveryStatusPending: 2, // Busy follower, retried, and the follower the quota stopped
+	}, running.Stats)
+
+	// Once the quota is reset, temporary errors are retried later until the attempts run out
+	suite.api.quota = 0
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+	suite.Len(suite.api.sent, 3)
+	suite.Equal(time.Minute, suite.queue.delays[len(suite.queue.delays)-1])
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+
+	completed, err := suite.service.GetCampaign(suite.ctx, campaign.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatTemplateCampaignStatusCompleted, completed.Status)
+	suite.Equal(map[string]int{entities.WeChatTemplateDeliveryStatusSent: 3, entities.WeChatTemplateDeliveryStatusFailed: 2}, completed.Stats)
+	busy, _, err := suite.service.GetDeliveries(suite.ctx, campaign.ID, entities.WeChatTemplateDeliveryStatusFailed, 0, 10)
+	suite.Require().NoError(err)
+	suite.Equal(3, busy[1].Attempts)
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) TestSurveyNonRespondersAndInvalidTemplate() {
+	survey := &entities.Survey{ID: uuid.New(), Title: "Satisfaction survey"}
+	suite.surveys.surveys[survey.ID] = survey
+	respondedAt := time.Now()
+	suite.invitations.invitations = []*entities.SurveyInvitation{
+		{ID: uuid.New(), SurveyID: survey.ID, Channel: entities.SurveyInvitationChannelWeChat, OpenID: "openid-1", Name: "Li", Token: "token-1", Status: entities.SurveyInvitationStatusOpened},
+		{ID: uuid.New(), SurveyID: survey.ID, Channel: entities.SurveyInvitationChannelWeChat, OpenID: "openid-2", Token: "token-2", Status: entities.SurveyInvitationStatusResponded, RespondedAt: &respondedAt},
+		{ID: uuid.New(), SurveyID: survey.ID, Channel: entities.SurveyInvitationChannelWeChat, OpenID: "openid-3", Token: "token-3", Status: entities.SurveyInvitationStatusExpired},
+		{ID: uuid.New(), SurveyID: survey.ID, Channel: entities.SurveyInvitationChannelEmail, Email: "han@example.com", Token: "token-4", Status: entities.SurveyInvitationStatusSent},
+		{ID: uuid.New(), SurveyID: survey.ID, Channel: entities.SurveyInvitationChannelWeChat, OpenID: "openid-5", Token: "token-5", Status: entities.SurveyInvitationStatusSent},
+	}
+
+	campaign := &entities.WeChatTemplateCampaign{
+		Name:          "Survey reminder",
+		TemplateID:    "tpl-survey",
+		URL:           "https://example.com/s/{invitationToken}",
+		FieldMappings: []entities.WeChatTemplateField{{Key: "first", Value: "{name}, please answer {survey}"}},
+		Audience:      entities.WeChatCampaignAudienceSurveyNonResponders,
+		SurveyID:      &survey.ID,
+	}
+	suite.Require().NoError(suite.service.CreateCampaign(suite.ctx, campaign))
+	_, err := suite.service.StartCampaign(suite.ctx, campaign.ID)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, campaign.ID))
+	suite.Require().Len(suite.api.sent, 2)
+	suite.Equal("https://example.com/s/token-1", suite.api.sent[0]["url"])
+	suite.Equal("Li, please answer Satisfaction survey", suite.api.sent[0]["data"].(map[string]interface{})["first"].(map[string]interface{})["value"])
+	suite.Equal("openid-5", suite.api.sent[1]["touser"])
+
+	// An invalid template fails the campaign instead of each delivery
+	other := &entities.WeChatTemplateCampaign{
+		Name:          "Survey reminder",
+		TemplateID:    "tpl-deleted",
+		FieldMappings: []entities.WeChatTemplateField{{Key: "first", Value: "{survey}"}},
+		Audience:      entities.WeChatCampaignAudienceSurveyNonResponders,
+		SurveyID:      &survey.ID,
+	}
+	suite.Require().NoError(suite.service.CreateCampaign(suite.ctx, other))
+	_, err = suite.service.StartCampaign(suite.ctx, other.ID)
+	suite.Require().NoError(err)
+	suite.api.errors["openid-1"] = 40037
+	suite.Require().NoError(suite.service.RunCampaign(suite.ctx, other.ID))
+
+	failed, err := suite.service.GetCampaign(suite.ctx, other.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.WeChatTemplateCampaignStatusFailed, failed.Status)
+	suite.Contains(failed.LastError, "40037")
+	suite.Equal(map[string]int{entities.WeChatTemplateDeliveryStatusCancelled: 2}, failed.Stats)
+
+	_, err = suite.service.CancelCampaign(suite.ctx, other.ID)
+	suite.ErrorIs(err, services.ErrWeChatTemplateCampaignFinished)
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) TestCreateCampaignValidates() {
+	missing := uuid.New()
+	err := suite.service.CreateCampaign(suite.ctx, &entities.WeChatTemplateCampaign{
+		Name:          "Reminder",
+		TemplateID:    "tpl-reminder",
+		FieldMappings: []entities.WeChatTemplateField{{Key: "first", Value: "Hi"}},
+		Audience:      entities.WeChatCampaignAudienceEventAttendees,
+	})
+	suite.ErrorIs(err, entities.ErrInvalidWeChatTemplateCampaign)
+
+	err = suite.service.CreateCampaign(suite.ctx, &entities.WeChatTemplateCampaign{
+		Name:          "Reminder",
+		TemplateID:    "tpl-reminder",
+		FieldMappings: []entities.WeChatTemplateField{{Key: "first", Value: "Hi"}},
+		Audience:      entities.WeChatCampaignAudienceEventAttendees,
+		EventID:       &missing,
+	})
+	suite.ErrorIs(err, repositories.ErrNotFound)
+	suite.Empty(suite.campaigns.campaigns)
+}
+
+func (suite *WeChatTemplateCampaignServiceTestSuite) TestStalledCampaignIsQueuedAgain() {
+	idle := time.Now().Add(-4 * time.Hour)
+	stalled := entities.WeChatTemplateCampaign{ID: uuid.New(), Status: entities.WeChatTemplateCampaignStatusSending, UpdatedAt: idle}
+	paused := entities.WeChatTemplateCampaign{ID: uuid.New(), Status: entities.WeChatTemplateCampaignStatusSending, UpdatedAt: time.Now().Add(-time.Hour)}
+	completed := entities.WeChatTemplateCampaign{ID: uuid.New(), Status: entities.WeChatTemplateCampaignStatusCompleted, UpdatedAt: idle}
+	suite.campaigns.campaigns = append(suite.campaigns.campaigns, stalled, paused, completed)
+
+	resumed, err := suite.service.ResumeStalledCampaigns(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, resumed)
+	suite.Equal([]time.Duration{0}, suite.queue.delays)
+}
+
+func TestWeChatTemplateCampaignServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeChatTemplateCampaignServiceTestSuite))
+}
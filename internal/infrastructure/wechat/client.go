@@ -6,17 +6,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+// DefaultAPIBaseURL is the address of the WeChat official account API
+const DefaultAPIBaseURL = "https://api.weixin.qq.com"
+
 // WeChatAPIClient handles direct WeChat API calls
 type WeChatAPIClient struct {
+	baseURL     string
 	appID       string
 	appSecret   string
 	accessToken string
@@ -28,6 +31,7 @@ type WeChatAPIClient struct {
 // NewWeChatAPIClient creates a new WeChat API client
 func NewWeChatAPIClient(appID, appSecret string, logger *zap.Logger) *WeChatAPIClient {
 	return &WeChatAPIClient{
+		baseURL:   DefaultAPIBaseURL,
 		appID:     appID,
 		appSecret: appSecret,
 		httpClient: &http.Client{
@@ -37,6 +41,11 @@ func NewWeChatAPIClient(appID, appSecret string, logger *zap.Logger) *WeChatAPIC
 	}
 }
 
+// SetBaseURL points the client at another WeChat API address, such as a proxy or a fake server in tests
+func (c *WeChatAPIClient) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimRight(baseURL, "/")
+}
+
 // AccessTokenResponse represents WeChat access token response
 type AccessTokenResponse struct {
 	AccessToken string `json:"access_token"`
@@ -53,8 +62,7 @@ func (c *WeChatAPIClient) GetAccessToken(ctx context.Context) (string, error) {
 	}
 
 	// Request new access token
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s",
-		c.appID, c.appSecret)
+	url := fmt.Sprintf("%s/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s", c.baseURL, c.appID, c.appSecret)
 
 	resp, err := c.httpClient.Get(url)
 	if err != nil {
@@ -82,6 +90,106 @@ func (c *WeChatAPIClient) GetAccessToken(ctx context.Context) (string, error) {
 	return c.accessToken, nil
 }
 
+// APIError is an error code returned by the WeChat API
+type APIError struct {
+	Operation string
+	Code      int
+	Message   string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("WeChat %s error: %d - %s", e.Operation, e.Code, e.Message)
+}
+
+// apiStatus carries the error code and message included in every WeChat API response
+type apiStatus struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// callAPI calls a WeChat API path with the access token and decodes the JSON response into result,
+// which may be nil. Requests without a body are sent as GET, others are POSTed as JSON.
+func (c *WeChatAPIClient) callAPI(ctx context.Context, operation, path string, body, result interface{}) error {
+	var contentType string
+	var payload io.Reader
+	if body != nil {
+		requestBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s request: %w", operation, err)
+		}
+		contentType = "application/json"
+		payload = bytes.NewReader(requestBody)
+	}
+
+	data, _, err := c.send(ctx, operation, path, contentType, payload)
+	if err != nil {
+		return err
+	}
+	return decodeAPIResponse(operation, data, result)
+}
+
+// send sends a request with the access token to a WeChat API path and returns the response body
+// with its content type. Requests without a payload are sent as GET, others are POSTed.
+func (c *WeChatAPIClient) send(ctx context.Context, operation, path, contentType string, payload io.Reader) ([]byte, string, error) {
+	token, err := c.GetAccessToken(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	url := fmt.Sprintf("%s%s%saccess_token=%s", c.baseURL, path, querySeparator(path), token)
+	method := http.MethodGet
+	if payload != nil {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to %s: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s response: %w", operation, err)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// decodeAPIResponse checks the error code of a JSON response and decodes it into result, which
+// may be nil
+func decodeAPIResponse(operation string, data []byte, result interface{}) error {
+	var status apiStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", operation, err)
+	}
+	if status.ErrCode != 0 {
+		return &APIError{Operation: operation, Code: status.ErrCode, Message: status.ErrMsg}
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", operation, err)
+		}
+	}
+	return nil
+}
+
+// querySeparator returns the character that appends a parameter to the query of path
+func querySeparator(path string) string {
+	if strings.Contains(path, "?") {
+		return "&"
+	}
+	return "?"
+}
+
 // DraftArticle represents a WeChat draft article
 type DraftArticle struct {
 	Title              string `json:"title"`
@@ -114,7 +222,7 @@ func (c *WeChatAPIClient) CreateDraft(ctx context.Context, articles []DraftArtic
 		return "", fmt.Errorf("failed to get access token: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/draft/add?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/draft/add?access_token=%s", c.baseURL, token)
 
 	request := DraftCreateRequest{Articles: articles}
 	requestBody, err := json.Marshal(request)
@@ -164,7 +272,7 @@ func (c *WeChatAPIClient) PublishDraft(ctx context.Context, mediaID string) (str
 		return "", "", fmt.Errorf("failed to get access token: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/freepublish/submit?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/freepublish/submit?access_token=%s", c.baseURL, token)
 
 	request := PublishRequest{MediaID: mediaID}
 	requestBody, err := json.Marshal(request)
@@ -205,7 +313,7 @@ func (c *WeChatAPIClient) DeleteDraft(ctx context.Context, mediaID string) error
 		return fmt.Errorf("failed to get access token: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/draft/delete?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/draft/delete?access_token=%s", c.baseURL, token)
 
 	request := map[string]string{"media_id": mediaID}
 	requestBody, err := json.Marshal(request)
@@ -240,7 +348,7 @@ func (c *WeChatAPIClient) SendTextMessage(ctx context.Context, openID, content s
 		return fmt.Errorf("failed to get access token: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/message/custom/send?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/message/custom/send?access_token=%s", c.baseURL, token)
 
 	message := map[string]interface{}{
 		"touser":  openID,
@@ -286,41 +394,32 @@ type TemplateMessage struct {
 	Data       map[string]interface{} `json:"data"`
 }
 
-// SendTemplateMessage sends a template message to a user
-func (c *WeChatAPIClient) SendTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) error {
-	token, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get access token: %w", err)
-	}
-
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/message/template/send?access_token=%s", token)
-
-	requestBody, err := json.Marshal(templateMsg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal template message: %w", err)
-	}
-
-	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(requestBody))
-	if err != nil {
-		return fmt.Errorf("failed to send template message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return fmt.Errorf("failed to decode template response: %w", err)
+// SendTemplateMessage sends a template message to a user and returns the msgid WeChat reports its
+// delivery with in the TEMPLATESENDJOBFINISH event
+func (c *WeChatAPIClient) SendTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) (int64, error) {
+	var sendResp struct {
+		MsgID int64 `json:"msgid"`
 	}
-
-	if errCode, ok := result["errcode"].(float64); ok && errCode != 0 {
-		errMsg, _ := result["errmsg"].(string)
-		return fmt.Errorf("WeChat template message error: %.0f - %s", errCode, errMsg)
+	if err := c.callAPI(ctx, "send template message", "/cgi-bin/message/template/send", templateMsg, &sendResp); err != nil {
+		return 0, err
 	}
 
 	c.logger.Info("WeChat template message sent successfully",
 		zap.String("toUser", templateMsg.ToUser),
-		zap.String("templateID", templateMsg.TemplateID))
+		zap.String("templateID", templateMsg.TemplateID),
+		zap.Int64("msgID", sendResp.MsgID))
 
-	return nil
+	return sendResp.MsgID, nil
+}
+
+// GetTagFollowers gets a page of up to 10,000 followers carrying a tag, after nextOpenID
+func (c *WeChatAPIClient) GetTagFollowers(ctx context.Context, tagID int, nextOpenID string) (*UserListResponse, error) {
+	request := map[string]interface{}{"tagid": tagID, "next_openid": nextOpenID}
+	var listResp UserListResponse
+	if err := c.callAPI(ctx, "get tag followers", "/cgi-bin/user/tag/get", request, &listResp); err != nil {
+		return nil, err
+	}
+	return &listResp, nil
 }
 
 // GetUserList gets the list of WeChat followers
@@ -330,7 +429,7 @@ func (c *WeChatAPIClient) GetUserList(ctx context.Context, nextOpenID string) (*
 		return nil, fmt.Errorf("failed to get access token: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/user/get?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/user/get?access_token=%s", c.baseURL, token)
 	if nextOpenID != "" {
 		url += "&next_openid=" + nextOpenID
 	}
@@ -380,71 +479,14 @@ type MaterialUploadResponse struct {
 
 // UploadMaterial uploads an image to WeChat and returns MediaID and URL
 func (c *WeChatAPIClient) UploadMaterial(ctx context.Context, filePath string) (*MaterialUploadResponse, error) {
-	token, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get access token: %w", err)
-	}
-
-	// Open the file
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
 	}
-	defer file.Close()
-
-	// Create a buffer to write our multipart form data
-	var requestBody bytes.Buffer
-	writer := multipart.NewWriter(&requestBody)
-
-	// Add the file field
-	part, err := writer.CreateFormFile("media", filepath.Base(filePath))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	// Copy file content to the form field
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
-	}
-
-	// Add the type field
-	err = writer.WriteField("type", "image")
-	if err != nil {
-		return nil, fmt.Errorf("failed to write type field: %w", err)
-	}
-
-	// Close the writer to finalize the form
-	err = writer.Close()
-	if err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
-	}
-
-	// Create the request
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/material/add_material?access_token=%s&type=image", token)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, &requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set the content type with boundary
-	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	// Send the request
-	resp, err := c.httpClient.Do(req)
+	uploadResp, err := c.AddMaterial(ctx, MediaTypeImage, filePath, data, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload material: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Parse the response
-	var uploadResp MaterialUploadResponse
-	if err := json.NewDecoder(resp.Body).Decode(&uploadResp); err != nil {
-		return nil, fmt.Errorf("failed to decode upload response: %w", err)
-	}
-
-	if uploadResp.ErrCode != 0 {
-		return nil, fmt.Errorf("WeChat material upload error: %d - %s", uploadResp.ErrCode, uploadResp.ErrMsg)
+		return nil, err
 	}
 
 	c.logger.Info("WeChat material uploaded successfully",
@@ -452,7 +494,7 @@ func (c *WeChatAPIClient) UploadMaterial(ctx context.Context, filePath string) (
 		zap.String("mediaID", uploadResp.MediaID),
 		zap.String("url", uploadResp.URL))
 
-	return &uploadResp, nil
+	return uploadResp, nil
 }
 
 // QRCodeCreateRequest represents WeChat QR code creation request
@@ -506,7 +548,7 @@ func (c *WeChatAPIClient) CreateQRCode(ctx context.Context, sceneStr string, exp
 	}
 
 	// Make API call
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/qrcode/create?access_token=%s", c.baseURL, token)
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create QR code: %w", err)
@@ -553,7 +595,7 @@ func (c *WeChatAPIClient) CreatePermanentQRCode(ctx context.Context, sceneStr st
 	}
 
 	// Make API call
-	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token=%s", token)
+	url := fmt.Sprintf("%s/cgi-bin/qrcode/create?access_token=%s", c.baseURL, token)
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create permanent QR code: %w", err)
@@ -0,0 +1,263 @@
+package wechat
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// pkcs7BlockSize is the padding block size mandated by WeChat (not the AES block size)
+const pkcs7BlockSize = 32
+
+var (
+	// ErrInvalidSignature is returned when a webhook signature does not match
+	ErrInvalidSignature = errors.New("invalid WeChat signature")
+	// ErrAppIDMismatch is returned when a decrypted message belongs to another account
+	ErrAppIDMismatch = errors.New("WeChat message appid mismatch")
+	// ErrEncryptionDisabled is returned when safe mode is used without an EncodingAESKey
+	ErrEncryptionDisabled = errors.New("WeChat message encryption is not configured")
+)
+
+// EncryptedMessage is the envelope WeChat posts in safe mode
+type EncryptedMessage struct {
+	XMLName    xml.Name `xml:"xml"`
+	ToUserName string   `xml:"ToUserName"`
+	Encrypt    string   `xml:"Encrypt"`
+}
+
+// EncryptedReply is the envelope we return to WeChat in safe mode
+type EncryptedReply struct {
+	XMLName      xml.Name `xml:"xml"`
+	Encrypt      CDATA    `xml:"Encrypt"`
+	MsgSignature CDATA    `xml:"MsgSignature"`
+	TimeStamp    string   `xml:"TimeStamp"`
+	Nonce        CDATA    `xml:"Nonce"`
+}
+
+// CDATA marshals a string as an XML CDATA section
+type CDATA string
+
+// MarshalXML writes the value wrapped in CDATA
+func (c CDATA) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Value string `xml:",cdata"`
+	}{string(c)}, start)
+}
+
+// Signature computes the WeChat SHA1 signature over the sorted, concatenated parts
+func Signature(parts ...string) string {
+	sorted := append([]string(nil), parts...)
+	sort.Strings(sorted)
+	sum := sha1.Sum([]byte(strings.Join(sorted, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+// signatureEqual compares signatures in constant time
+func signatureEqual(expected, actual string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(actual))) == 1
+}
+
+// MessageCrypter verifies webhook signatures and handles safe-mode (AES-CBC) payloads
+type MessageCrypter struct {
+	token           string
+	appID           string
+	aesKey          []byte
+	verifySignature bool
+}
+
+// NewMessageCrypter creates a crypter from the WeChat configuration.
+// Encryption is only available when EncodingAESKey is set.
+func NewMessageCrypter(config *Config) (*MessageCrypter, error) {
+	crypter := &MessageCrypter{
+		token:           config.Token,
+		appID:           config.AppID,
+		verifySignature: config.VerifySignature,
+	}
+
+	if config.EncodingAESKey != "" {
+		if len(config.EncodingAESKey) != 43 {
+			return nil, fmt.Errorf("invalid EncodingAESKey: expected 43 characters, got %d", len(config.EncodingAESKey))
+		}
+		key, err := base64.StdEncoding.DecodeString(config.EncodingAESKey + "=")
+		if err != nil {
+			return nil, fmt.Errorf("invalid EncodingAESKey: %w", err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid EncodingAESKey: decoded key must be 32 bytes")
+		}
+		crypter.aesKey = key
+	}
+
+	return crypter, nil
+}
+
+// EncryptionEnabled reports whether safe-mode messages can be handled
+func (m *MessageCrypter) EncryptionEnabled() bool {
+	return len(m.aesKey) == 32
+}
+
+// VerifySignature checks the plain `signature` query parameter sent on every webhook request
+func (m *MessageCrypter) VerifySignature(signature, timestamp, nonce string) bool {
+	if !m.verifySignature {
+		return true
+	}
+	if signature == "" || timestamp == "" || nonce == "" {
+		return false
+	}
+	return signatureEqual(Signature(m.token, timestamp, nonce), signature)
+}
+
+// VerifyMsgSignature checks the `msg_signature` query parameter sent with encrypted messages
+func (m *MessageCrypter) VerifyMsgSignature(msgSignature, timestamp, nonce, encrypted string) bool {
+	if !m.verifySignature {
+		return true
+	}
+	if msgSignature == "" {
+		return false
+	}
+	return signatureEqual(Signature(m.token, timestamp, nonce, encrypted), msgSignature)
+}
+
+// DecryptMessage verifies and decrypts a safe-mode request body, returning the plain message XML
+func (m *MessageCrypter) DecryptMessage(body []byte, msgSignature, timestamp, nonce string) ([]byte, error) {
+	if !m.EncryptionEnabled() {
+		return nil, ErrEncryptionDisabled
+	}
+
+	var envelope EncryptedMessage
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted message: %w", err)
+	}
+	if envelope.Encrypt == "" {
+		return nil, fmt.Errorf("encrypted message has no Encrypt field")
+	}
+
+	if !m.VerifyMsgSignature(msgSignature, timestamp, nonce, envelope.Encrypt) {
+		return nil, ErrInvalidSignature
+	}
+
+	return m.Decrypt(envelope.Encrypt)
+}
+
+// EncryptReply encrypts a plain reply XML and wraps it in a signed envelope
+func (m *MessageCrypter) EncryptReply(replyXML []byte, timestamp, nonce string) (*EncryptedReply, error) {
+	encrypted, err := m.Encrypt(replyXML)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptedReply{
+		Encrypt:      CDATA(encrypted),
+		MsgSignature: CDATA(Signature(m.token, timestamp, nonce, encrypted)),
+		TimeStamp:    timestamp,
+		Nonce:        CDATA(nonce),
+	}, nil
+}
+
+// Encrypt encrypts a message as base64(AES-CBC(random(16) + msgLen(4) + msg + appID))
+func (m *MessageCrypter) Encrypt(plain []byte) (string, error) {
+	if !m.EncryptionEnabled() {
+		return "", ErrEncryptionDisabled
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate random prefix: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(random)
+	lengthPrefix := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthPrefix, uint32(len(plain)))
+	buf.Write(lengthPrefix)
+	buf.Write(plain)
+	buf.WriteString(m.appID)
+
+	padded := pkcs7Pad(buf.Bytes(), pkcs7BlockSize)
+
+	block, err := aes.NewCipher(m.aesKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, m.aesKey[:aes.BlockSize]).CryptBlocks(ciphertext, padded)
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt reverses Encrypt and checks that the embedded appid matches our account
+func (m *MessageCrypter) Decrypt(encrypted string) ([]byte, error) {
+	if !m.EncryptionEnabled() {
+		return nil, ErrEncryptionDisabled
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted message: %w", err)
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted message has invalid length %d", len(ciphertext))
+	}
+
+	block, err := aes.NewCipher(m.aesKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, m.aesKey[:aes.BlockSize]).CryptBlocks(plaintext, ciphertext)
+
+	plaintext, err = pkcs7Unpad(plaintext, pkcs7BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(plaintext) < 20 {
+		return nil, fmt.Errorf("decrypted message is too short")
+	}
+
+	msgLen := int(binary.BigEndian.Uint32(plaintext[16:20]))
+	if msgLen < 0 || 20+msgLen > len(plaintext) {
+		return nil, fmt.Errorf("decrypted message has invalid length prefix")
+	}
+
+	msg := plaintext[20 : 20+msgLen]
+	appID := string(plaintext[20+msgLen:])
+	if subtle.ConstantTimeCompare([]byte(appID), []byte(m.appID)) != 1 {
+		return nil, ErrAppIDMismatch
+	}
+
+	return msg, nil
+}
+
+// pkcs7Pad pads data to a multiple of blockSize
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+// pkcs7Unpad strips PKCS#7 padding
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("cannot unpad empty message")
+	}
+	padding := int(data[len(data)-1])
+	if padding < 1 || padding > blockSize || padding > len(data) {
+		return nil, fmt.Errorf("invalid message padding")
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("invalid message padding")
+		}
+	}
+	return data[:len(data)-padding], nil
+}
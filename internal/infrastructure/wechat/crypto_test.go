@@ -0,0 +1,109 @@
+package wechat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testToken  = "nexteventtoken"
+	testAppID  = "wx0123456789abcdef"
+	testAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+type MessageCrypterTestSuite struct {
+	suite.Suite
+	crypter *MessageCrypter
+}
+
+func (suite *MessageCrypterTestSuite) SetupTest() {
+	crypter, err := NewMessageCrypter(&Config{
+		AppID:           testAppID,
+		Token:           testToken,
+		EncodingAESKey:  testAESKey,
+		VerifySignature: true,
+	})
+	suite.Require().NoError(err)
+	suite.crypter = crypter
+}
+
+func (suite *MessageCrypterTestSuite) TestVerifySignature() {
+	signature := Signature(testToken, "1700000000", "nonce")
+
+	suite.True(suite.crypter.VerifySignature(signature, "1700000000", "nonce"))
+	suite.False(suite.crypter.VerifySignature(signature, "1700000001", "nonce"))
+	suite.False(suite.crypter.VerifySignature("", "1700000000", "nonce"))
+}
+
+func (suite *MessageCrypterTestSuite) TestVerifySignatureDisabled() {
+	crypter, err := NewMessageCrypter(&Config{Token: testToken})
+	suite.Require().NoError(err)
+
+	suite.True(crypter.VerifySignature("anything", "1", "2"))
+	suite.False(crypter.EncryptionEnabled())
+}
+
+func (suite *MessageCrypterTestSuite) TestEncryptDecryptRoundTrip() {
+	plain := []byte("<xml><ToUserName><![CDATA[gh_123]]></ToUserName><Content><![CDATA[你好]]></Content></xml>")
+
+	encrypted, err := suite.crypter.Encrypt(plain)
+	suite.Require().NoError(err)
+
+	decrypted, err := suite.crypter.Decrypt(encrypted)
+	suite.Require().NoError(err)
+	suite.Equal(plain, decrypted)
+}
+
+func (suite *MessageCrypterTestSuite) TestDecryptRejectsOtherAppID() {
+	other, err := NewMessageCrypter(&Config{AppID: "wxother", Token: testToken, EncodingAESKey: testAESKey})
+	suite.Require().NoError(err)
+
+	encrypted, err := other.Encrypt([]byte("<xml/>"))
+	suite.Require().NoError(err)
+
+	_, err = suite.crypter.Decrypt(encrypted)
+	suite.ErrorIs(err, ErrAppIDMismatch)
+}
+
+func (suite *MessageCrypterTestSuite) TestDecryptMessage() {
+	plain := []byte("<xml><MsgType><![CDATA[text]]></MsgType></xml>")
+	encrypted, err := suite.crypter.Encrypt(plain)
+	suite.Require().NoError(err)
+
+	body := []byte(fmt.Sprintf("<xml><ToUserName><![CDATA[gh_123]]></ToUserName><Encrypt><![CDATA[%s]]></Encrypt></xml>", encrypted))
+	msgSignature := Signature(testToken, "1700000000", "nonce", encrypted)
+
+	decrypted, err := suite.crypter.DecryptMessage(body, msgSignature, "1700000000", "nonce")
+	suite.Require().NoError(err)
+	suite.Equal(plain, decrypted)
+
+	_, err = suite.crypter.DecryptMessage(body, "bad", "1700000000", "nonce")
+	suite.ErrorIs(err, ErrInvalidSignature)
+}
+
+func (suite *MessageCrypterTestSuite) TestEncryptReply() {
+	reply, err := suite.crypter.EncryptReply([]byte("<xml/>"), "1700000000", "nonce")
+	suite.Require().NoError(err)
+
+	suite.Equal(Signature(testToken, "1700000000", "nonce", string(reply.Encrypt)), string(reply.MsgSignature))
+
+	data, err := xml.Marshal(reply)
+	suite.Require().NoError(err)
+	suite.Contains(string(data), "<Encrypt><![CDATA[")
+
+	decrypted, err := suite.crypter.Decrypt(string(reply.Encrypt))
+	suite.Require().NoError(err)
+	suite.Equal([]byte("<xml/>"), decrypted)
+}
+
+func (suite *MessageCrypterTestSuite) TestInvalidAESKey() {
+	_, err := NewMessageCrypter(&Config{EncodingAESKey: "short"})
+	suite.Error(err)
+}
+
+func TestMessageCrypterTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageCrypterTestSuite))
+}
@@ -0,0 +1,102 @@
+package wechat
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Mass message types
+const (
+	MassMsgTypeNews  = "mpnews"
+	MassMsgTypeText  = "text"
+	MassMsgTypeImage = "image"
+)
+
+// MassMessage is the content of a mass broadcast: the media ID of published news or an image,
+// or the content of a text. WeChat rejects a second broadcast with the same ClientMsgID, which
+// makes retrying a send safe.
+type MassMessage struct {
+	MsgType     string
+	MediaID     string
+	Content     string
+	ClientMsgID string
+}
+
+// MassSendResponse identifies a mass broadcast. WeChat reports its outcome with the
+// MASSSENDJOBFINISH event, or through GetMassStatus.
+type MassSendResponse struct {
+	MsgID     int64 `json:"msg_id"`
+	MsgDataID int64 `json:"msg_data_id"`
+}
+
+// SendMassByTag broadcasts a message to the followers carrying a tag
+func (c *WeChatAPIClient) SendMassByTag(ctx context.Context, tagID int, msg *MassMessage) (*MassSendResponse, error) {
+	request := massRequest(msg)
+	request["filter"] = map[string]interface{}{"is_to_all": false, "tag_id": tagID}
+
+	var sendResp MassSendResponse
+	if err := c.callAPI(ctx, "send mass message", "/cgi-bin/message/mass/sendall", request, &sendResp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("WeChat mass message sent to tag",
+		zap.Int("tagID", tagID),
+		zap.String("msgType", msg.MsgType),
+		zap.Int64("msgID", sendResp.MsgID))
+	return &sendResp, nil
+}
+
+// SendMassByOpenIDs broadcasts a message to a list of 2 to 10,000 followers
+func (c *WeChatAPIClient) SendMassByOpenIDs(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResponse, error) {
+	request := massRequest(msg)
+	request["touser"] = openIDs
+
+	var sendResp MassSendResponse
+	if err := c.callAPI(ctx, "send mass message", "/cgi-bin/message/mass/send", request, &sendResp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("WeChat mass message sent to followers",
+		zap.Int("recipients", len(openIDs)),
+		zap.String("msgType", msg.MsgType),
+		zap.Int64("msgID", sendResp.MsgID))
+	return &sendResp, nil
+}
+
+// PreviewMass sends a mass message to a single follower to check how it looks
+func (c *WeChatAPIClient) PreviewMass(ctx context.Context, openID string, msg *MassMessage) error {
+	request := massRequest(msg)
+	request["touser"] = openID
+	return c.callAPI(ctx, "preview mass message", "/cgi-bin/message/mass/preview", request, nil)
+}
+
+// GetMassStatus returns the status of a mass broadcast: SEND_SUCCESS, SENDING, SEND_FAIL or DELETE
+func (c *WeChatAPIClient) GetMassStatus(ctx context.Context, msgID int64) (string, error) {
+	var statusResp struct {
+		MsgStatus string `json:"msg_status"`
+	}
+	if err := c.callAPI(ctx, "get mass message status", "/cgi-bin/message/mass/get", map[string]int64{"msg_id": msgID}, &statusResp); err != nil {
+		return "", err
+	}
+	return statusResp.MsgStatus, nil
+}
+
+// massRequest builds the message part shared by the mass message requests
+func massRequest(msg *MassMessage) map[string]interface{} {
+	request := map[string]interface{}{"msgtype": msg.MsgType}
+	if msg.ClientMsgID != "" {
+		request["clientmsgid"] = msg.ClientMsgID
+	}
+	switch msg.MsgType {
+	case MassMsgTypeText:
+		request[msg.MsgType] = map[string]string{"content": msg.Content}
+	case MassMsgTypeNews:
+		request[msg.MsgType] = map[string]string{"media_id": msg.MediaID}
+		// Stop rather than send news WeChat judges to be a reprint
+		request["send_ignore_reprint"] = 0
+	default:
+		request[msg.MsgType] = map[string]string{"media_id": msg.MediaID}
+	}
+	return request
+}
@@ -0,0 +1,176 @@
+package wechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Media types accepted by the WeChat media APIs
+const (
+	MediaTypeImage = "image"
+	MediaTypeVoice = "voice"
+	MediaTypeVideo = "video"
+	MediaTypeThumb = "thumb"
+)
+
+// MaxMaterialBatch is the most permanent materials WeChat lists per request
+const MaxMaterialBatch = 20
+
+// MediaUploadResponse represents WeChat temporary media upload response
+type MediaUploadResponse struct {
+	Type         string `json:"type"`
+	MediaID      string `json:"media_id"`
+	ThumbMediaID string `json:"thumb_media_id"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// VideoDescription describes a permanent video, which WeChat requires on upload
+type VideoDescription struct {
+	Title        string `json:"title"`
+	Introduction string `json:"introduction"`
+}
+
+// MediaContent is temporary media downloaded from WeChat. Videos are not downloaded and only carry
+// their URL.
+type MediaContent struct {
+	Data        []byte
+	ContentType string
+	VideoURL    string
+}
+
+// MaterialItem is a permanent material of a list
+type MaterialItem struct {
+	MediaID    string `json:"media_id"`
+	Name       string `json:"name"`
+	UpdateTime int64  `json:"update_time"`
+	URL        string `json:"url"`
+}
+
+// MaterialListResponse represents WeChat permanent material list response
+type MaterialListResponse struct {
+	TotalCount int            `json:"total_count"`
+	ItemCount  int            `json:"item_count"`
+	Items      []MaterialItem `json:"item"`
+}
+
+// UploadTempMedia uploads media that WeChat keeps for three days
+func (c *WeChatAPIClient) UploadTempMedia(ctx context.Context, mediaType, filename string, data []byte) (*MediaUploadResponse, error) {
+	var result MediaUploadResponse
+	path := "/cgi-bin/media/upload?type=" + url.QueryEscape(mediaType)
+	if err := c.uploadMedia(ctx, "upload media", path, filename, data, nil, &result); err != nil {
+		return nil, err
+	}
+	// Thumbnails are returned under their own key
+	if result.MediaID == "" {
+		result.MediaID = result.ThumbMediaID
+	}
+	return &result, nil
+}
+
+// AddMaterial uploads permanent material. Videos must be described, other media take a nil
+// description.
+func (c *WeChatAPIClient) AddMaterial(ctx context.Context, mediaType, filename string, data []byte, description *VideoDescription) (*MaterialUploadResponse, error) {
+	var fields map[string]string
+	if description != nil {
+		encoded, err := json.Marshal(description)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal video description: %w", err)
+		}
+		fields = map[string]string{"description": string(encoded)}
+	}
+
+	var result MaterialUploadResponse
+	path := "/cgi-bin/material/add_material?type=" + url.QueryEscape(mediaType)
+	if err := c.uploadMedia(ctx, "add material", path, filename, data, fields, &result); err != nil {
+		return nil, err
+	}
+	result.Type = mediaType
+	return &result, nil
+}
+
+// GetTempMedia downloads temporary media. WeChat answers videos with their URL instead of their
+// content.
+func (c *WeChatAPIClient) GetTempMedia(ctx context.Context, mediaID string) (*MediaContent, error) {
+	data, contentType, err := c.send(ctx, "get media", "/cgi-bin/media/get?media_id="+url.QueryEscape(mediaID), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if !isJSONResponse(contentType, data) {
+		return &MediaContent{Data: data, ContentType: contentType}, nil
+	}
+
+	var result struct {
+		VideoURL string `json:"video_url"`
+	}
+	if err := decodeAPIResponse("get media", data, &result); err != nil {
+		return nil, err
+	}
+	return &MediaContent{VideoURL: result.VideoURL}, nil
+}
+
+// BatchGetMaterial lists the permanent materials of a type, at most MaxMaterialBatch from offset
+func (c *WeChatAPIClient) BatchGetMaterial(ctx context.Context, mediaType string, offset, count int) (*MaterialListResponse, error) {
+	body := map[string]interface{}{
+		"type":   mediaType,
+		"offset": offset,
+		"count":  count,
+	}
+	var result MaterialListResponse
+	if err := c.callAPI(ctx, "list materials", "/cgi-bin/material/batchget_material", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteMaterial deletes permanent material
+func (c *WeChatAPIClient) DeleteMaterial(ctx context.Context, mediaID string) error {
+	body := map[string]string{"media_id": mediaID}
+	return c.callAPI(ctx, "delete material", "/cgi-bin/material/del_material", body, nil)
+}
+
+// uploadMedia POSTs a file as the media field of a multipart form along with fields and decodes
+// the JSON response into result
+func (c *WeChatAPIClient) uploadMedia(ctx context.Context, operation, path, filename string, data []byte, fields map[string]string, result interface{}) error {
+	var requestBody bytes.Buffer
+	writer := multipart.NewWriter(&requestBody)
+
+	part, err := writer.CreateFormFile("media", filepath.Base(filename))
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to write form file: %w", err)
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return fmt.Errorf("failed to write %s field: %w", name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	response, _, err := c.send(ctx, operation, path, writer.FormDataContentType(), &requestBody)
+	if err != nil {
+		return err
+	}
+	return decodeAPIResponse(operation, response, result)
+}
+
+// isJSONResponse tells whether a media download returned a JSON document rather than content.
+// WeChat labels some of its JSON errors as plain text.
+func isJSONResponse(contentType string, data []byte) bool {
+	if strings.HasPrefix(contentType, "application/json") {
+		return true
+	}
+	if strings.HasPrefix(contentType, "text/plain") {
+		return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	}
+	return false
+}
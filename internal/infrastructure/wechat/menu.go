@@ -0,0 +1,117 @@
+package wechat
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// errCodeMenuNotExist is returned when the official account has no custom menu
+const errCodeMenuNotExist = 46003
+
+// Menu represents a WeChat custom menu. Conditional menus carry the rule selecting their
+// followers and, once created, the menu ID WeChat assigned to them.
+type Menu struct {
+	Buttons   []MenuButton   `json:"button"`
+	MatchRule *MenuMatchRule `json:"matchrule,omitempty"`
+	MenuID    string         `json:"menuid,omitempty"`
+}
+
+// MenuButton represents a menu button or a sub menu holding up to five buttons
+type MenuButton struct {
+	Type      string       `json:"type,omitempty"`
+	Name      string       `json:"name"`
+	Key       string       `json:"key,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	AppID     string       `json:"appid,omitempty"`
+	PagePath  string       `json:"pagepath,omitempty"`
+	MediaID   string       `json:"media_id,omitempty"`
+	ArticleID string       `json:"article_id,omitempty"`
+	SubButton []MenuButton `json:"sub_button,omitempty"`
+}
+
+// MenuMatchRule selects the followers who see a conditional menu
+type MenuMatchRule struct {
+	TagID              string `json:"tag_id,omitempty"`
+	Sex                string `json:"sex,omitempty"`
+	Country            string `json:"country,omitempty"`
+	Province           string `json:"province,omitempty"`
+	City               string `json:"city,omitempty"`
+	ClientPlatformType string `json:"client_platform_type,omitempty"`
+	Language           string `json:"language,omitempty"`
+}
+
+// MenuGetResponse represents WeChat menu query response
+type MenuGetResponse struct {
+	Menu             Menu   `json:"menu"`
+	ConditionalMenus []Menu `json:"conditionalmenu,omitempty"`
+}
+
+// CreateMenu replaces the default custom menu
+func (c *WeChatAPIClient) CreateMenu(ctx context.Context, menu *Menu) error {
+	request := Menu{Buttons: menu.Buttons}
+	if err := c.callAPI(ctx, "create menu", "/cgi-bin/menu/create", request, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("WeChat menu created successfully", zap.Int("buttonCount", len(menu.Buttons)))
+	return nil
+}
+
+// GetMenu gets the default menu and the conditional menus. It returns an empty response when
+// the official account has no menu.
+func (c *WeChatAPIClient) GetMenu(ctx context.Context) (*MenuGetResponse, error) {
+	var menuResp MenuGetResponse
+	if err := c.callAPI(ctx, "get menu", "/cgi-bin/menu/get", nil, &menuResp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == errCodeMenuNotExist {
+			return &MenuGetResponse{}, nil
+		}
+		return nil, err
+	}
+	return &menuResp, nil
+}
+
+// DeleteMenu deletes the default menu, which also deletes every conditional menu
+func (c *WeChatAPIClient) DeleteMenu(ctx context.Context) error {
+	if err := c.callAPI(ctx, "delete menu", "/cgi-bin/menu/delete", nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("WeChat menu deleted successfully")
+	return nil
+}
+
+// AddConditionalMenu creates a conditional menu and returns its menu ID. WeChat only shows
+// conditional menus while a default menu exists.
+func (c *WeChatAPIClient) AddConditionalMenu(ctx context.Context, menu *Menu) (string, error) {
+	if menu.MatchRule == nil {
+		return "", errors.New("conditional menu requires a match rule")
+	}
+
+	request := Menu{Buttons: menu.Buttons, MatchRule: menu.MatchRule}
+	var menuResp struct {
+		MenuID string `json:"menuid"`
+	}
+	if err := c.callAPI(ctx, "add conditional menu", "/cgi-bin/menu/addconditional", request, &menuResp); err != nil {
+		return "", err
+	}
+
+	c.logger.Info("WeChat conditional menu created successfully",
+		zap.String("menuID", menuResp.MenuID),
+		zap.String("tagID", menu.MatchRule.TagID))
+
+	return menuResp.MenuID, nil
+}
+
+// DeleteConditionalMenu deletes a conditional menu by its menu ID
+func (c *WeChatAPIClient) DeleteConditionalMenu(ctx context.Context, menuID string) error {
+	request := map[string]string{"menuid": menuID}
+	if err := c.callAPI(ctx, "delete conditional menu", "/cgi-bin/menu/delconditional", request, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("WeChat conditional menu deleted successfully", zap.String("menuID", menuID))
+	return nil
+}
@@ -15,9 +15,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// MediaLibrary uploads article images to WeChat, reusing the media of images uploaded before
+type MediaLibrary interface {
+	UploadImage(ctx context.Context, imageID, filePath string) (*MaterialUploadResponse, error)
+}
+
 // ContentPreprocessor handles article content preprocessing for WeChat
 type ContentPreprocessor struct {
 	wechatService *Service
+	mediaLibrary  MediaLibrary
 	db            *gorm.DB
 	logger        *zap.Logger
 	hostURL       string
@@ -39,6 +45,12 @@ func NewContentPreprocessor(wechatService *Service, db *gorm.DB, hostURL, tempDi
 	}
 }
 
+// SetMediaLibrary makes the preprocessor upload images through a media library, so the same
+// image is not uploaded on every publish
+func (p *ContentPreprocessor) SetMediaLibrary(mediaLibrary MediaLibrary) {
+	p.mediaLibrary = mediaLibrary
+}
+
 // SiteImage represents the SiteImages table structure
 type SiteImage struct {
 	ID        string `gorm:"column:Id;primaryKey"`
@@ -204,7 +216,7 @@ func (p *ContentPreprocessor) processExternalImage(ctx context.Context, imageURL
 	defer os.Remove(tempFilePath) // Clean up temp file
 
 	// Upload to WeChat
-	uploadResp, err := p.wechatService.UploadMaterial(ctx, tempFilePath)
+	uploadResp, err := p.uploadImage(ctx, "", tempFilePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload external image to WeChat: %w", err)
 	}
@@ -218,10 +230,12 @@ func (p *ContentPreprocessor) uploadImageToWeChat(ctx context.Context, siteImage
 	fullPath := filepath.Join(p.tempDir, "..", "wwwroot", siteImage.SiteURL)
 
 	// Upload to WeChat
-	uploadResp, err := p.wechatService.UploadMaterial(ctx, fullPath)
+	uploadResp, err := p.uploadImage(ctx, siteImage.ID, fullPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload image to WeChat: %w", err)
 	}
+	siteImage.MediaID = uploadResp.MediaID
+	siteImage.URL = uploadResp.URL
 
 	// Update database with MediaID and URL
 	err = p.db.Model(siteImage).Updates(map[string]interface{}{
@@ -235,6 +249,14 @@ func (p *ContentPreprocessor) uploadImageToWeChat(ctx context.Context, siteImage
 	return uploadResp.URL, nil
 }
 
+// uploadImage uploads an image file through the media library when there is one
+func (p *ContentPreprocessor) uploadImage(ctx context.Context, imageID, filePath string) (*MaterialUploadResponse, error) {
+	if p.mediaLibrary != nil {
+		return p.mediaLibrary.UploadImage(ctx, imageID, filePath)
+	}
+	return p.wechatService.UploadMaterial(ctx, filePath)
+}
+
 // downloadImage downloads an image from URL to temp file
 func (p *ContentPreprocessor) downloadImage(ctx context.Context, imageURL string) (string, error) {
 	// Create temp file
@@ -262,10 +262,16 @@ func (c *RetryableWeChatClient) SendTextMessage(ctx context.Context, openID, con
 }
 
 // SendTemplateMessage sends a template message with retry logic
-func (c *RetryableWeChatClient) SendTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) error {
-	return c.retrier.Execute(ctx, "SendTemplateMessage", func(ctx context.Context) error {
-		return c.client.SendTemplateMessage(ctx, templateMsg)
+func (c *RetryableWeChatClient) SendTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) (int64, error) {
+	var msgID int64
+
+	err := c.retrier.Execute(ctx, "SendTemplateMessage", func(ctx context.Context) error {
+		var err error
+		msgID, err = c.client.SendTemplateMessage(ctx, templateMsg)
+		return err
 	})
+
+	return msgID, err
 }
 
 // GetUserList gets user list with retry logic
@@ -281,6 +287,19 @@ func (c *RetryableWeChatClient) GetUserList(ctx context.Context, nextOpenID stri
 	return response, err
 }
 
+// BatchGetUserInfo gets follower profiles with retry logic
+func (c *RetryableWeChatClient) BatchGetUserInfo(ctx context.Context, openIDs []string, lang string) ([]UserInfo, error) {
+	var users []UserInfo
+
+	err := c.retrier.Execute(ctx, "BatchGetUserInfo", func(ctx context.Context) error {
+		var err error
+		users, err = c.client.BatchGetUserInfo(ctx, openIDs, lang)
+		return err
+	})
+
+	return users, err
+}
+
 // CreateQRCode creates a QR code with retry logic
 func (c *RetryableWeChatClient) CreateQRCode(ctx context.Context, sceneStr string, expireSeconds int) (*QRCodeCreateResponse, error) {
 	var response *QRCodeCreateResponse
@@ -306,3 +325,222 @@ func (c *RetryableWeChatClient) CreatePermanentQRCode(ctx context.Context, scene
 
 	return response, err
 }
+
+// CreateMenu creates the default menu with retry logic
+func (c *RetryableWeChatClient) CreateMenu(ctx context.Context, menu *Menu) error {
+	return c.retrier.Execute(ctx, "CreateMenu", func(ctx context.Context) error {
+		return c.client.CreateMenu(ctx, menu)
+	})
+}
+
+// GetMenu gets the menus with retry logic
+func (c *RetryableWeChatClient) GetMenu(ctx context.Context) (*MenuGetResponse, error) {
+	var response *MenuGetResponse
+
+	err := c.retrier.Execute(ctx, "GetMenu", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.GetMenu(ctx)
+		return err
+	})
+
+	return response, err
+}
+
+// DeleteMenu deletes the menus with retry logic
+func (c *RetryableWeChatClient) DeleteMenu(ctx context.Context) error {
+	return c.retrier.Execute(ctx, "DeleteMenu", func(ctx context.Context) error {
+		return c.client.DeleteMenu(ctx)
+	})
+}
+
+// AddConditionalMenu creates a conditional menu with retry logic
+func (c *RetryableWeChatClient) AddConditionalMenu(ctx context.Context, menu *Menu) (string, error) {
+	var menuID string
+
+	err := c.retrier.Execute(ctx, "AddConditionalMenu", func(ctx context.Context) error {
+		var err error
+		menuID, err = c.client.AddConditionalMenu(ctx, menu)
+		return err
+	})
+
+	return menuID, err
+}
+
+// DeleteConditionalMenu deletes a conditional menu with retry logic
+func (c *RetryableWeChatClient) DeleteConditionalMenu(ctx context.Context, menuID string) error {
+	return c.retrier.Execute(ctx, "DeleteConditionalMenu", func(ctx context.Context) error {
+		return c.client.DeleteConditionalMenu(ctx, menuID)
+	})
+}
+
+// UploadTempMedia uploads temporary media with retry logic
+func (c *RetryableWeChatClient) UploadTempMedia(ctx context.Context, mediaType, filename string, data []byte) (*MediaUploadResponse, error) {
+	var response *MediaUploadResponse
+
+	err := c.retrier.Execute(ctx, "UploadTempMedia", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.UploadTempMedia(ctx, mediaType, filename, data)
+		return err
+	})
+
+	return response, err
+}
+
+// AddMaterial uploads permanent material with retry logic
+func (c *RetryableWeChatClient) AddMaterial(ctx context.Context, mediaType, filename string, data []byte, description *VideoDescription) (*MaterialUploadResponse, error) {
+	var response *MaterialUploadResponse
+
+	err := c.retrier.Execute(ctx, "AddMaterial", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.AddMaterial(ctx, mediaType, filename, data, description)
+		return err
+	})
+
+	return response, err
+}
+
+// GetTempMedia downloads temporary media with retry logic
+func (c *RetryableWeChatClient) GetTempMedia(ctx context.Context, mediaID string) (*MediaContent, error) {
+	var content *MediaContent
+
+	err := c.retrier.Execute(ctx, "GetTempMedia", func(ctx context.Context) error {
+		var err error
+		content, err = c.client.GetTempMedia(ctx, mediaID)
+		return err
+	})
+
+	return content, err
+}
+
+// BatchGetMaterial lists permanent materials with retry logic
+func (c *RetryableWeChatClient) BatchGetMaterial(ctx context.Context, mediaType string, offset, count int) (*MaterialListResponse, error) {
+	var response *MaterialListResponse
+
+	err := c.retrier.Execute(ctx, "BatchGetMaterial", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.BatchGetMaterial(ctx, mediaType, offset, count)
+		return err
+	})
+
+	return response, err
+}
+
+// DeleteMaterial deletes permanent material with retry logic
+func (c *RetryableWeChatClient) DeleteMaterial(ctx context.Context, mediaID string) error {
+	return c.retrier.Execute(ctx, "DeleteMaterial", func(ctx context.Context) error {
+		return c.client.DeleteMaterial(ctx, mediaID)
+	})
+}
+
+// CreateTag creates a user tag with retry logic
+func (c *RetryableWeChatClient) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	var tag *Tag
+
+	err := c.retrier.Execute(ctx, "CreateTag", func(ctx context.Context) error {
+		var err error
+		tag, err = c.client.CreateTag(ctx, name)
+		return err
+	})
+
+	return tag, err
+}
+
+// GetTags lists the user tags with retry logic
+func (c *RetryableWeChatClient) GetTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+
+	err := c.retrier.Execute(ctx, "GetTags", func(ctx context.Context) error {
+		var err error
+		tags, err = c.client.GetTags(ctx)
+		return err
+	})
+
+	return tags, err
+}
+
+// UpdateTag renames a user tag with retry logic
+func (c *RetryableWeChatClient) UpdateTag(ctx context.Context, tagID int, name string) error {
+	return c.retrier.Execute(ctx, "UpdateTag", func(ctx context.Context) error {
+		return c.client.UpdateTag(ctx, tagID, name)
+	})
+}
+
+// DeleteTag deletes a user tag with retry logic
+func (c *RetryableWeChatClient) DeleteTag(ctx context.Context, tagID int) error {
+	return c.retrier.Execute(ctx, "DeleteTag", func(ctx context.Context) error {
+		return c.client.DeleteTag(ctx, tagID)
+	})
+}
+
+// BatchTagUsers tags followers with retry logic
+func (c *RetryableWeChatClient) BatchTagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	return c.retrier.Execute(ctx, "BatchTagUsers", func(ctx context.Context) error {
+		return c.client.BatchTagUsers(ctx, tagID, openIDs)
+	})
+}
+
+// BatchUntagUsers untags followers with retry logic
+func (c *RetryableWeChatClient) BatchUntagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	return c.retrier.Execute(ctx, "BatchUntagUsers", func(ctx context.Context) error {
+		return c.client.BatchUntagUsers(ctx, tagID, openIDs)
+	})
+}
+
+// SendMassByTag broadcasts a message to a tag with retry logic
+func (c *RetryableWeChatClient) SendMassByTag(ctx context.Context, tagID int, msg *MassMessage) (*MassSendResponse, error) {
+	var response *MassSendResponse
+
+	err := c.retrier.Execute(ctx, "SendMassByTag", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.SendMassByTag(ctx, tagID, msg)
+		return err
+	})
+
+	return response, err
+}
+
+// SendMassByOpenIDs broadcasts a message to followers with retry logic
+func (c *RetryableWeChatClient) SendMassByOpenIDs(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResponse, error) {
+	var response *MassSendResponse
+
+	err := c.retrier.Execute(ctx, "SendMassByOpenIDs", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.SendMassByOpenIDs(ctx, openIDs, msg)
+		return err
+	})
+
+	return response, err
+}
+
+// PreviewMass previews a mass message with retry logic
+func (c *RetryableWeChatClient) PreviewMass(ctx context.Context, openID string, msg *MassMessage) error {
+	return c.retrier.Execute(ctx, "PreviewMass", func(ctx context.Context) error {
+		return c.client.PreviewMass(ctx, openID, msg)
+	})
+}
+
+// GetMassStatus gets the status of a mass broadcast with retry logic
+func (c *RetryableWeChatClient) GetMassStatus(ctx context.Context, msgID int64) (string, error) {
+	var status string
+
+	err := c.retrier.Execute(ctx, "GetMassStatus", func(ctx context.Context) error {
+		var err error
+		status, err = c.client.GetMassStatus(ctx, msgID)
+		return err
+	})
+
+	return status, err
+}
+
+// GetTagFollowers gets a page of the followers carrying a tag with retry logic
+func (c *RetryableWeChatClient) GetTagFollowers(ctx context.Context, tagID int, nextOpenID string) (*UserListResponse, error) {
+	var response *UserListResponse
+
+	err := c.retrier.Execute(ctx, "GetTagFollowers", func(ctx context.Context) error {
+		var err error
+		response, err = c.client.GetTagFollowers(ctx, tagID, nextOpenID)
+		return err
+	})
+
+	return response, err
+}
@@ -0,0 +1,232 @@
+package wechat
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+)
+
+// MessageHandler handles an inbound WeChat message or event and optionally returns a passive reply
+type MessageHandler func(ctx context.Context, msg *message.MixMessage) (*message.Reply, error)
+
+// subscribeScenePrefix prefixes the EventKey of subscribe events triggered by a parametric QR code
+const subscribeScenePrefix = "qrscene_"
+
+// keywordRoute binds a text keyword to a handler
+type keywordRoute struct {
+	keyword string
+	exact   bool
+	handler MessageHandler
+}
+
+// sceneRoute binds a QR scene string prefix to a handler
+type sceneRoute struct {
+	prefix  string
+	handler MessageHandler
+}
+
+// MessageRouter dispatches inbound WeChat messages by MsgType, Event, event key, QR scene and text keyword.
+// Handlers can be registered at any time; the most specific match wins.
+type MessageRouter struct {
+	mu               sync.RWMutex
+	msgTypeHandlers  map[message.MsgType]MessageHandler
+	eventHandlers    map[message.EventType]MessageHandler
+	eventKeyHandlers map[message.EventType]map[string]MessageHandler
+	sceneRoutes      []sceneRoute
+	keywordRoutes    []keywordRoute
+	defaultHandler   MessageHandler
+}
+
+// NewMessageRouter creates an empty message router
+func NewMessageRouter() *MessageRouter {
+	return &MessageRouter{
+		msgTypeHandlers:  make(map[message.MsgType]MessageHandler),
+		eventHandlers:    make(map[message.EventType]MessageHandler),
+		eventKeyHandlers: make(map[message.EventType]map[string]MessageHandler),
+	}
+}
+
+// HandleMsgType registers a handler for a message type (text, image, voice, ...)
+func (r *MessageRouter) HandleMsgType(msgType message.MsgType, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgTypeHandlers[msgType] = handler
+}
+
+// HandleEvent registers a handler for an event type (subscribe, SCAN, CLICK, ...)
+func (r *MessageRouter) HandleEvent(event message.EventType, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventHandlers[event] = handler
+}
+
+// HandleEventKey registers a handler for a specific event key, e.g. a CLICK menu key or a VIEW URL
+func (r *MessageRouter) HandleEventKey(event message.EventType, key string, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.eventKeyHandlers[event] == nil {
+		r.eventKeyHandlers[event] = make(map[string]MessageHandler)
+	}
+	r.eventKeyHandlers[event][key] = handler
+}
+
+// HandleClick registers a handler for a CLICK menu key
+func (r *MessageRouter) HandleClick(key string, handler MessageHandler) {
+	r.HandleEventKey(message.EventClick, key, handler)
+}
+
+// RemoveEventKey removes a handler registered for an event key
+func (r *MessageRouter) RemoveEventKey(event message.EventType, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.eventKeyHandlers[event], key)
+}
+
+// HandleScene registers a handler for parametric QR code scans whose scene string starts with prefix.
+// It applies to SCAN events directly; subscribe events reach it through RouteScene.
+func (r *MessageRouter) HandleScene(prefix string, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sceneRoutes = append(r.sceneRoutes, sceneRoute{prefix: prefix, handler: handler})
+}
+
+// HandleKeyword registers a handler for text messages. Exact keywords must match the whole
+// (trimmed, case-insensitive) message; otherwise the keyword only needs to be contained in it.
+func (r *MessageRouter) HandleKeyword(keyword string, exact bool, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keywordRoutes = append(r.keywordRoutes, keywordRoute{
+		keyword: strings.ToLower(strings.TrimSpace(keyword)),
+		exact:   exact,
+		handler: handler,
+	})
+}
+
+// HandleDefault registers the fallback handler used when nothing else matches
+func (r *MessageRouter) HandleDefault(handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultHandler = handler
+}
+
+// Route dispatches a message to the matching handler. It returns a nil reply when no handler matches.
+func (r *MessageRouter) Route(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	handler := r.match(msg)
+	if handler == nil {
+		return nil, nil
+	}
+	return handler(ctx, msg)
+}
+
+// RouteScene dispatches a message to the handler registered for its QR scene string.
+// It returns a nil reply when the message carries no scene or no handler matches.
+func (r *MessageRouter) RouteScene(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+	r.mu.RLock()
+	handler := r.matchScene(SceneStr(msg))
+	r.mu.RUnlock()
+
+	if handler == nil {
+		return nil, nil
+	}
+	return handler(ctx, msg)
+}
+
+// match finds the most specific handler for a message
+func (r *MessageRouter) match(msg *message.MixMessage) MessageHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	switch msg.MsgType {
+	case message.MsgTypeEvent:
+		if keyHandlers, ok := r.eventKeyHandlers[msg.Event]; ok {
+			if handler, ok := keyHandlers[msg.EventKey]; ok {
+				return handler
+			}
+		}
+		if msg.Event == message.EventScan {
+			if handler := r.matchScene(SceneStr(msg)); handler != nil {
+				return handler
+			}
+		}
+		if handler, ok := r.eventHandlers[msg.Event]; ok {
+			return handler
+		}
+	case message.MsgTypeText:
+		if handler := r.matchKeyword(msg.Content); handler != nil {
+			return handler
+		}
+		if handler, ok := r.msgTypeHandlers[msg.MsgType]; ok {
+			return handler
+		}
+	default:
+		if handler, ok := r.msgTypeHandlers[msg.MsgType]; ok {
+			return handler
+		}
+	}
+
+	return r.defaultHandler
+}
+
+// matchScene finds the handler with the longest prefix matching the scene string
+func (r *MessageRouter) matchScene(sceneStr string) MessageHandler {
+	if sceneStr == "" {
+		return nil
+	}
+
+	var matched *sceneRoute
+	for i, route := range r.sceneRoutes {
+		if strings.HasPrefix(sceneStr, route.prefix) && (matched == nil || len(route.prefix) > len(matched.prefix)) {
+			matched = &r.sceneRoutes[i]
+		}
+	}
+	if matched == nil {
+		return nil
+	}
+	return matched.handler
+}
+
+// matchKeyword prefers exact keyword matches over contains matches
+func (r *MessageRouter) matchKeyword(content string) MessageHandler {
+	text := strings.ToLower(strings.TrimSpace(content))
+	if text == "" {
+		return nil
+	}
+
+	for _, route := range r.keywordRoutes {
+		if route.exact && route.keyword == text {
+			return route.handler
+		}
+	}
+	for _, route := range r.keywordRoutes {
+		if !route.exact && strings.Contains(text, route.keyword) {
+			return route.handler
+		}
+	}
+	return nil
+}
+
+// SceneStr returns the QR scene string carried by a SCAN or subscribe event, or "" if there is none
+func SceneStr(msg *message.MixMessage) string {
+	switch msg.Event {
+	case message.EventScan:
+		return msg.EventKey
+	case message.EventSubscribe:
+		if strings.HasPrefix(msg.EventKey, subscribeScenePrefix) {
+			return strings.TrimPrefix(msg.EventKey, subscribeScenePrefix)
+		}
+	}
+	return ""
+}
+
+// TextReply builds a passive text reply
+func TextReply(content string) *message.Reply {
+	return &message.Reply{MsgType: message.MsgTypeText, MsgData: message.NewText(content)}
+}
+
+// NewsReply builds a passive single-article news reply
+func NewsReply(title, description, picURL, url string) *message.Reply {
+	article := message.NewArticle(title, description, picURL, url)
+	return &message.Reply{MsgType: message.MsgTypeNews, MsgData: message.NewNews([]*message.Article{article})}
+}
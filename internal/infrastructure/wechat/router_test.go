@@ -0,0 +1,113 @@
+package wechat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/suite"
+)
+
+type MessageRouterTestSuite struct {
+	suite.Suite
+	router *MessageRouter
+}
+
+func (suite *MessageRouterTestSuite) SetupTest() {
+	suite.router = NewMessageRouter()
+}
+
+// replyWith returns a handler that replies with the given text
+func replyWith(text string) MessageHandler {
+	return func(ctx context.Context, msg *message.MixMessage) (*message.Reply, error) {
+		return TextReply(text), nil
+	}
+}
+
+// replyText extracts the text content of a reply
+func (suite *MessageRouterTestSuite) replyText(msg *message.MixMessage) string {
+	reply, err := suite.router.Route(context.Background(), msg)
+	suite.Require().NoError(err)
+	if reply == nil {
+		return ""
+	}
+	text, ok := reply.MsgData.(*message.Text)
+	suite.Require().True(ok)
+	return string(text.Content)
+}
+
+func (suite *MessageRouterTestSuite) TestEventDispatch() {
+	suite.router.HandleEvent(message.EventSubscribe, replyWith("welcome"))
+	suite.router.HandleEvent(message.EventClick, replyWith("click"))
+	suite.router.HandleClick("MENU_AGENDA", replyWith("agenda"))
+
+	subscribe := &message.MixMessage{Event: message.EventSubscribe}
+	subscribe.MsgType = message.MsgTypeEvent
+	suite.Equal("welcome", suite.replyText(subscribe))
+
+	click := &message.MixMessage{Event: message.EventClick, EventKey: "MENU_AGENDA"}
+	click.MsgType = message.MsgTypeEvent
+	suite.Equal("agenda", suite.replyText(click))
+
+	click.EventKey = "MENU_UNKNOWN"
+	suite.Equal("click", suite.replyText(click))
+}
+
+func (suite *MessageRouterTestSuite) TestKeywordDispatch() {
+	suite.router.HandleKeyword("help", true, replyWith("exact"))
+	suite.router.HandleKeyword("ticket", false, replyWith("contains"))
+	suite.router.HandleMsgType(message.MsgTypeText, replyWith("text"))
+
+	msg := &message.MixMessage{Content: "  HELP "}
+	msg.MsgType = message.MsgTypeText
+	suite.Equal("exact", suite.replyText(msg))
+
+	msg.Content = "where is my ticket?"
+	suite.Equal("contains", suite.replyText(msg))
+
+	msg.Content = "hello"
+	suite.Equal("text", suite.replyText(msg))
+}
+
+func (suite *MessageRouterTestSuite) TestSceneDispatch() {
+	suite.router.HandleEvent(message.EventScan, replyWith("scan"))
+	suite.router.HandleScene("event_", replyWith("event"))
+	suite.router.HandleScene("event_vip_", replyWith("vip"))
+
+	scan := &message.MixMessage{Event: message.EventScan, EventKey: "event_123"}
+	scan.MsgType = message.MsgTypeEvent
+	suite.Equal("event", suite.replyText(scan))
+
+	scan.EventKey = "event_vip_123"
+	suite.Equal("vip", suite.replyText(scan))
+
+	scan.EventKey = "survey_123"
+	suite.Equal("scan", suite.replyText(scan))
+
+	subscribe := &message.MixMessage{Event: message.EventSubscribe, EventKey: "qrscene_event_123"}
+	subscribe.MsgType = message.MsgTypeEvent
+	suite.Equal("event_123", SceneStr(subscribe))
+
+	reply, err := suite.router.RouteScene(context.Background(), subscribe)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(reply)
+	suite.Equal("event", string(reply.MsgData.(*message.Text).Content))
+
+	subscribe.EventKey = ""
+	reply, err = suite.router.RouteScene(context.Background(), subscribe)
+	suite.Require().NoError(err)
+	suite.Nil(reply)
+}
+
+func (suite *MessageRouterTestSuite) TestDefaultHandler() {
+	image := &message.MixMessage{}
+	image.MsgType = message.MsgTypeImage
+	suite.Equal("", suite.replyText(image))
+
+	suite.router.HandleDefault(replyWith("fallback"))
+	suite.Equal("fallback", suite.replyText(image))
+}
+
+func TestMessageRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageRouterTestSuite))
+}
@@ -19,6 +19,7 @@ type Config struct {
 	CacheAccessToken      bool          `yaml:"cache_access_token"`
 	AccessTokenCacheKey   string        `yaml:"access_token_cache_key"`
 	AccessTokenExpireTime time.Duration `yaml:"access_token_expire_time"`
+	APIBaseURL            string        `yaml:"api_base_url"` // Defaults to DefaultAPIBaseURL
 }
 
 // Service provides high-level WeChat functionality with caching
@@ -33,6 +34,9 @@ type Service struct {
 // NewService creates a new WeChat service with Redis caching
 func NewService(config *Config, redisClient *redis.Client, logger *zap.Logger) *Service {
 	client := NewWeChatAPIClient(config.AppID, config.AppSecret, logger)
+	if config.APIBaseURL != "" {
+		client.SetBaseURL(config.APIBaseURL)
+	}
 	retryableClient := NewRetryableWeChatClient(client, DefaultRetryConfig(), logger)
 
 	return &Service{
@@ -102,13 +106,38 @@ func (s *Service) UploadMaterial(ctx context.Context, filePath string) (*Materia
 	return s.retryableClient.UploadMaterial(ctx, filePath)
 }
 
+// UploadTempMedia uploads media that WeChat keeps for three days
+func (s *Service) UploadTempMedia(ctx context.Context, mediaType, filename string, data []byte) (*MediaUploadResponse, error) {
+	return s.retryableClient.UploadTempMedia(ctx, mediaType, filename, data)
+}
+
+// AddMaterial uploads permanent material to WeChat
+func (s *Service) AddMaterial(ctx context.Context, mediaType, filename string, data []byte, description *VideoDescription) (*MaterialUploadResponse, error) {
+	return s.retryableClient.AddMaterial(ctx, mediaType, filename, data, description)
+}
+
+// GetTempMedia downloads temporary media
+func (s *Service) GetTempMedia(ctx context.Context, mediaID string) (*MediaContent, error) {
+	return s.retryableClient.GetTempMedia(ctx, mediaID)
+}
+
+// BatchGetMaterial lists the permanent materials of a type
+func (s *Service) BatchGetMaterial(ctx context.Context, mediaType string, offset, count int) (*MaterialListResponse, error) {
+	return s.retryableClient.BatchGetMaterial(ctx, mediaType, offset, count)
+}
+
+// DeleteMaterial deletes permanent material
+func (s *Service) DeleteMaterial(ctx context.Context, mediaID string) error {
+	return s.retryableClient.DeleteMaterial(ctx, mediaID)
+}
+
 // SendTextMessage sends a text message
 func (s *Service) SendTextMessage(ctx context.Context, openID, content string) error {
 	return s.retryableClient.SendTextMessage(ctx, openID, content)
 }
 
 // SendTemplateMessage sends a template message
-func (s *Service) SendTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) error {
+func (s *Service) SendTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) (int64, error) {
 	return s.retryableClient.SendTemplateMessage(ctx, templateMsg)
 }
 
@@ -117,6 +146,66 @@ func (s *Service) GetUserList(ctx context.Context, nextOpenID string) (*UserList
 	return s.retryableClient.GetUserList(ctx, nextOpenID)
 }
 
+// BatchGetUserInfo gets the profiles of up to MaxUserInfoBatch followers
+func (s *Service) BatchGetUserInfo(ctx context.Context, openIDs []string, lang string) ([]UserInfo, error) {
+	return s.retryableClient.BatchGetUserInfo(ctx, openIDs, lang)
+}
+
+// CreateTag creates a user tag
+func (s *Service) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	return s.retryableClient.CreateTag(ctx, name)
+}
+
+// GetTags lists the user tags
+func (s *Service) GetTags(ctx context.Context) ([]Tag, error) {
+	return s.retryableClient.GetTags(ctx)
+}
+
+// UpdateTag renames a user tag
+func (s *Service) UpdateTag(ctx context.Context, tagID int, name string) error {
+	return s.retryableClient.UpdateTag(ctx, tagID, name)
+}
+
+// DeleteTag deletes a user tag
+func (s *Service) DeleteTag(ctx context.Context, tagID int) error {
+	return s.retryableClient.DeleteTag(ctx, tagID)
+}
+
+// BatchTagUsers tags up to MaxTagBatch followers
+func (s *Service) BatchTagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	return s.retryableClient.BatchTagUsers(ctx, tagID, openIDs)
+}
+
+// BatchUntagUsers removes a tag from up to MaxTagBatch followers
+func (s *Service) BatchUntagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	return s.retryableClient.BatchUntagUsers(ctx, tagID, openIDs)
+}
+
+// GetTagFollowers gets a page of the followers carrying a tag
+func (s *Service) GetTagFollowers(ctx context.Context, tagID int, nextOpenID string) (*UserListResponse, error) {
+	return s.retryableClient.GetTagFollowers(ctx, tagID, nextOpenID)
+}
+
+// SendMassByTag broadcasts a message to the followers carrying a tag
+func (s *Service) SendMassByTag(ctx context.Context, tagID int, msg *MassMessage) (*MassSendResponse, error) {
+	return s.retryableClient.SendMassByTag(ctx, tagID, msg)
+}
+
+// SendMassByOpenIDs broadcasts a message to a list of followers
+func (s *Service) SendMassByOpenIDs(ctx context.Context, openIDs []string, msg *MassMessage) (*MassSendResponse, error) {
+	return s.retryableClient.SendMassByOpenIDs(ctx, openIDs, msg)
+}
+
+// PreviewMass sends a mass message to a single follower
+func (s *Service) PreviewMass(ctx context.Context, openID string, msg *MassMessage) error {
+	return s.retryableClient.PreviewMass(ctx, openID, msg)
+}
+
+// GetMassStatus returns the status of a mass broadcast
+func (s *Service) GetMassStatus(ctx context.Context, msgID int64) (string, error) {
+	return s.retryableClient.GetMassStatus(ctx, msgID)
+}
+
 // CreateQRCode creates a temporary QR code
 func (s *Service) CreateQRCode(ctx context.Context, sceneStr string, expireSeconds int) (*QRCodeCreateResponse, error) {
 	return s.retryableClient.CreateQRCode(ctx, sceneStr, expireSeconds)
@@ -127,8 +216,37 @@ func (s *Service) CreatePermanentQRCode(ctx context.Context, sceneStr string) (*
 	return s.retryableClient.CreatePermanentQRCode(ctx, sceneStr)
 }
 
+// CreateMenu replaces the default custom menu
+func (s *Service) CreateMenu(ctx context.Context, menu *Menu) error {
+	return s.retryableClient.CreateMenu(ctx, menu)
+}
+
+// GetMenu gets the default and conditional custom menus
+func (s *Service) GetMenu(ctx context.Context) (*MenuGetResponse, error) {
+	return s.retryableClient.GetMenu(ctx)
+}
+
+// DeleteMenu deletes all custom menus
+func (s *Service) DeleteMenu(ctx context.Context) error {
+	return s.retryableClient.DeleteMenu(ctx)
+}
+
+// AddConditionalMenu creates a conditional custom menu
+func (s *Service) AddConditionalMenu(ctx context.Context, menu *Menu) (string, error) {
+	return s.retryableClient.AddConditionalMenu(ctx, menu)
+}
+
+// DeleteConditionalMenu deletes a conditional custom menu
+func (s *Service) DeleteConditionalMenu(ctx context.Context, menuID string) error {
+	return s.retryableClient.DeleteConditionalMenu(ctx, menuID)
+}
+
 // ClearAccessTokenCache clears the cached access token
 func (s *Service) ClearAccessTokenCache(ctx context.Context) error {
+	if s.redisClient == nil {
+		return nil
+	}
+
 	cacheKey := s.config.AccessTokenCacheKey
 	if cacheKey == "" {
 		cacheKey = "wechat:access_token"
@@ -0,0 +1,71 @@
+package wechat
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// MaxTagBatch is the most followers WeChat tags or untags per request
+const MaxTagBatch = 50
+
+// Tag represents a WeChat user tag
+type Tag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"` // Followers carrying the tag
+}
+
+// tagRequest is the body of the tag management requests
+type tagRequest struct {
+	Tag Tag `json:"tag"`
+}
+
+// CreateTag creates a user tag
+func (c *WeChatAPIClient) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	var tagResp tagRequest
+	if err := c.callAPI(ctx, "create tag", "/cgi-bin/tags/create", tagRequest{Tag: Tag{Name: name}}, &tagResp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("WeChat tag created successfully", zap.Int("tagID", tagResp.Tag.ID), zap.String("name", name))
+	return &tagResp.Tag, nil
+}
+
+// GetTags lists the user tags of the official account
+func (c *WeChatAPIClient) GetTags(ctx context.Context) ([]Tag, error) {
+	var tagsResp struct {
+		Tags []Tag `json:"tags"`
+	}
+	if err := c.callAPI(ctx, "get tags", "/cgi-bin/tags/get", nil, &tagsResp); err != nil {
+		return nil, err
+	}
+	return tagsResp.Tags, nil
+}
+
+// UpdateTag renames a user tag
+func (c *WeChatAPIClient) UpdateTag(ctx context.Context, tagID int, name string) error {
+	return c.callAPI(ctx, "update tag", "/cgi-bin/tags/update", tagRequest{Tag: Tag{ID: tagID, Name: name}}, nil)
+}
+
+// DeleteTag deletes a user tag, removing it from every follower
+func (c *WeChatAPIClient) DeleteTag(ctx context.Context, tagID int) error {
+	if err := c.callAPI(ctx, "delete tag", "/cgi-bin/tags/delete", tagRequest{Tag: Tag{ID: tagID}}, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("WeChat tag deleted successfully", zap.Int("tagID", tagID))
+	return nil
+}
+
+// BatchTagUsers tags up to MaxTagBatch followers
+func (c *WeChatAPIClient) BatchTagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	request := map[string]interface{}{"tagid": tagID, "openid_list": openIDs}
+	return c.callAPI(ctx, "tag users", "/cgi-bin/tags/members/batchtagging", request, nil)
+}
+
+// BatchUntagUsers removes a tag from up to MaxTagBatch followers
+func (c *WeChatAPIClient) BatchUntagUsers(ctx context.Context, tagID int, openIDs []string) error {
+	request := map[string]interface{}{"tagid": tagID, "openid_list": openIDs}
+	return c.callAPI(ctx, "untag users", "/cgi-bin/tags/members/batchuntagging", request, nil)
+}
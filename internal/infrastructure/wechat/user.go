@@ -0,0 +1,67 @@
+package wechat
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxUserInfoBatch is the number of followers WeChat returns the profiles of in one batch request
+const MaxUserInfoBatch = 100
+
+// UserInfo represents the profile of a follower. Followers who unfollowed only have Subscribe
+// and OpenID set.
+type UserInfo struct {
+	Subscribe      int    `json:"subscribe"`
+	OpenID         string `json:"openid"`
+	Nickname       string `json:"nickname"`
+	Sex            int    `json:"sex"`
+	City           string `json:"city"`
+	Country        string `json:"country"`
+	Province       string `json:"province"`
+	Language       string `json:"language"`
+	HeadImgURL     string `json:"headimgurl"`
+	SubscribeTime  int64  `json:"subscribe_time"`
+	UnionID        string `json:"unionid"`
+	Remark         string `json:"remark"`
+	GroupID        int    `json:"groupid"`
+	TagIDList      []int  `json:"tagid_list"`
+	SubscribeScene string `json:"subscribe_scene"`
+	QRScene        int    `json:"qr_scene"`
+	QRSceneStr     string `json:"qr_scene_str"`
+}
+
+// userInfoBatchRequest represents WeChat batch user info request
+type userInfoBatchRequest struct {
+	UserList []userInfoBatchItem `json:"user_list"`
+}
+
+type userInfoBatchItem struct {
+	OpenID string `json:"openid"`
+	Lang   string `json:"lang,omitempty"`
+}
+
+// userInfoBatchResponse represents WeChat batch user info response
+type userInfoBatchResponse struct {
+	UserInfoList []UserInfo `json:"user_info_list"`
+}
+
+// BatchGetUserInfo gets the profiles of up to MaxUserInfoBatch followers
+func (c *WeChatAPIClient) BatchGetUserInfo(ctx context.Context, openIDs []string, lang string) ([]UserInfo, error) {
+	if len(openIDs) == 0 {
+		return nil, nil
+	}
+	if len(openIDs) > MaxUserInfoBatch {
+		return nil, fmt.Errorf("cannot get more than %d user profiles at once, got %d", MaxUserInfoBatch, len(openIDs))
+	}
+
+	request := userInfoBatchRequest{UserList: make([]userInfoBatchItem, len(openIDs))}
+	for i, openID := range openIDs {
+		request.UserList[i] = userInfoBatchItem{OpenID: openID, Lang: lang}
+	}
+
+	var response userInfoBatchResponse
+	if err := c.callAPI(ctx, "batch get user info", "/cgi-bin/user/info/batchget", request, &response); err != nil {
+		return nil, err
+	}
+	return response.UserInfoList, nil
+}
@@ -65,6 +65,11 @@ func NewWorkflow(db *gorm.DB, redisClient *redis.Client, config *WorkflowConfig,
 	}, nil
 }
 
+// SetMediaLibrary makes the content preprocessor upload images through a media library
+func (w *Workflow) SetMediaLibrary(mediaLibrary MediaLibrary) {
+	w.preprocessor.SetMediaLibrary(mediaLibrary)
+}
+
 // GetWeChatService returns the WeChat service
 func (w *Workflow) GetWeChatService() *Service {
 	return w.wechatService
@@ -264,7 +269,8 @@ func (w *Workflow) SendWeChatMessage(ctx context.Context, openID, content string
 
 // SendWeChatTemplateMessage sends a template message via WeChat
 func (w *Workflow) SendWeChatTemplateMessage(ctx context.Context, templateMsg *TemplateMessage) error {
-	return w.wechatService.SendTemplateMessage(ctx, templateMsg)
+	_, err := w.wechatService.SendTemplateMessage(ctx, templateMsg)
+	return err
 }
 
 // GetWeChatUserList gets the list of WeChat followers
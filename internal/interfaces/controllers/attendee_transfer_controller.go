@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxAttendeeFileSize limits uploaded attendee lists to 10 MB
+const maxAttendeeFileSize = 10 << 20
+
+// attendeeFileContentTypes maps export formats to their content types
+var attendeeFileContentTypes = map[string]string{
+	services.AttendeeFileFormatCSV:  "text/csv; charset=utf-8",
+	services.AttendeeFileFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// AttendeeTransferController handles bulk attendee import and attendance export
+type AttendeeTransferController struct {
+	transferService services.AttendeeTransferService
+	logger          *zap.Logger
+}
+
+// NewAttendeeTransferController creates a new attendee import/export controller
+func NewAttendeeTransferController(transferService services.AttendeeTransferService, logger *zap.Logger) *AttendeeTransferController {
+	return &AttendeeTransferController{
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// ImportAttendees handles POST /events/:id/attendees/import. The attendee list is uploaded as the
+// "file" form field; ?dryRun=true previews the outcome of every row without registering anyone.
+func (c *AttendeeTransferController) ImportAttendees(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dryRun", "false"))
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No attendee file provided"})
+		return
+	}
+	if header.Size > maxAttendeeFileSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Attendee file must be at most 10 MB"})
+		return
+	}
+
+	format := ctx.Query("format")
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read attendee file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := c.transferService.ImportAttendees(ctx.Request.Context(), eventID, file, format, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		case errors.Is(err, services.ErrUnsupportedFileFormat):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Attendee file must be CSV or XLSX"})
+		case errors.Is(err, services.ErrInvalidAttendeeFile):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.logger.Error("Failed to import attendees", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import attendees"})
+		}
+		return
+	}
+
+	rows := make([]gin.H, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		rows = append(rows, gin.H{
+			"row":            row.Row,
+			"mobile":         row.Mobile,
+			"name":           row.Name,
+			"company":        row.Company,
+			"outcome":        row.Outcome,
+			"attendeeStatus": row.AttendeeStatus,
+			"userId":         row.UserID,
+			"errors":         row.Errors,
+		})
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, gin.H{
+		"data": gin.H{
+			"eventId":    result.EventID,
+			"dryRun":     result.DryRun,
+			"totalRows":  result.TotalRows,
+			"created":    result.Created,
+			"duplicates": result.Duplicates,
+			"invalid":    result.Invalid,
+			"waitlisted": result.Waitlisted,
+			"matched":    result.Matched,
+			"rows":       rows,
+		},
+	})
+}
+
+// ExportAttendance handles GET /events/:id/attendees/export?format=csv|xlsx
+func (c *AttendeeTransferController) ExportAttendance(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	format := strings.ToLower(ctx.DefaultQuery("format", services.AttendeeFileFormatCSV))
+	contentType, ok := attendeeFileContentTypes[format]
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Export format must be csv or xlsx"})
+		return
+	}
+
+	// Buffer the file so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	if err := c.transferService.ExportAttendance(ctx.Request.Context(), eventID, format, &buf); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.logger.Error("Failed to export attendance", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export attendance"})
+		return
+	}
+
+	filename := fmt.Sprintf("attendance-%s-%s.%s", eventID.String()[:8], time.Now().Format("20060102"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// EventCheckInController issues the WeChat QR codes followers scan to check in to events
+type EventCheckInController struct {
+	checkInService services.EventCheckInService
+	logger         *zap.Logger
+}
+
+// NewEventCheckInController creates a new event check-in controller
+func NewEventCheckInController(checkInService services.EventCheckInService, logger *zap.Logger) *EventCheckInController {
+	return &EventCheckInController{
+		checkInService: checkInService,
+		logger:         logger,
+	}
+}
+
+// GenerateEventWeChatQRCode handles POST /api/v1/events/:id/wechat/qrcode?type=permanent|temporary
+func (c *EventCheckInController) GenerateEventWeChatQRCode(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid event ID"})
+		return
+	}
+
+	qrType := entities.QRCodeType(ctx.DefaultQuery("type", string(entities.QRCodeTypeTemporary)))
+	if qrType != entities.QRCodeTypePermanent && qrType != entities.QRCodeTypeTemporary {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid QR code type. Must be 'permanent' or 'temporary'"})
+		return
+	}
+
+	qrCode, err := c.checkInService.GenerateEventQRCode(ctx.Request.Context(), eventID, qrType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Event not found"})
+			return
+		}
+		c.logger.Error("Failed to generate event check-in QR code", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to generate event check-in QR code"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"id":         qrCode.ID,
+		"url":        qrCode.Url,
+		"expireTime": qrCode.ExpireTime,
+	}})
+}
@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"encoding/base64"
+	"errors"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/zenteam/nextevent-go/internal/domain/entities"
 	"github.com/zenteam/nextevent-go/internal/domain/services"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // Helper function to safely get time value from pointer
@@ -60,6 +64,7 @@ func (c *EventController) CreateEvent(ctx *gin.Context) {
 		InteractionCode: req.InteractionCode,
 		ScanMessage:     req.ScanMessage,
 		IsCurrent:       req.IsCurrent,
+		Capacity:        req.Capacity,
 	}
 
 	if err := c.eventService.CreateEvent(ctx.Request.Context(), event); err != nil {
@@ -79,6 +84,7 @@ func (c *EventController) CreateEvent(ctx *gin.Context) {
 			InteractionCode: event.InteractionCode,
 			ScanMessage:     event.ScanMessage,
 			IsCurrent:       event.IsCurrent,
+			Capacity:        event.Capacity,
 			CreatedAt:       event.CreatedAt,
 			UpdatedAt:       getTimeValue(event.UpdatedAt),
 		},
@@ -111,6 +117,7 @@ func (c *EventController) GetEvent(ctx *gin.Context) {
 			InteractionCode: event.InteractionCode,
 			ScanMessage:     event.ScanMessage,
 			IsCurrent:       event.IsCurrent,
+			Capacity:        event.Capacity,
 			CreatedAt:       event.CreatedAt,
 			UpdatedAt:       getTimeValue(event.UpdatedAt),
 		},
@@ -136,6 +143,7 @@ func (c *EventController) GetCurrentEvent(ctx *gin.Context) {
 			InteractionCode: event.InteractionCode,
 			ScanMessage:     event.ScanMessage,
 			IsCurrent:       event.IsCurrent,
+			Capacity:        event.Capacity,
 			CreatedAt:       event.CreatedAt,
 			UpdatedAt:       getTimeValue(event.UpdatedAt),
 		},
@@ -166,6 +174,7 @@ func (c *EventController) GetEvents(ctx *gin.Context) {
 			InteractionCode: event.InteractionCode,
 			ScanMessage:     event.ScanMessage,
 			IsCurrent:       event.IsCurrent,
+			Capacity:        event.Capacity,
 			CreatedAt:       event.CreatedAt,
 			UpdatedAt:       getTimeValue(event.UpdatedAt),
 		})
@@ -225,6 +234,10 @@ func (c *EventController) UpdateEvent(ctx *gin.Context) {
 	if req.IsCurrent != nil {
 		event.IsCurrent = *req.IsCurrent
 	}
+	capacityChanged := req.Capacity != nil && *req.Capacity != event.Capacity
+	if req.Capacity != nil {
+		event.Capacity = *req.Capacity
+	}
 
 	if err := c.eventService.UpdateEvent(ctx.Request.Context(), event); err != nil {
 		c.logger.Error("Failed to update event", zap.Error(err))
@@ -232,6 +245,13 @@ func (c *EventController) UpdateEvent(ctx *gin.Context) {
 		return
 	}
 
+	// A larger (or removed) capacity frees places for waitlisted attendees
+	if capacityChanged && c.attendeeService != nil {
+		if err := c.attendeeService.PromoteWaitlist(ctx.Request.Context(), event.ID); err != nil {
+			c.logger.Error("Failed to promote waitlisted attendees", zap.Error(err))
+		}
+	}
+
 	ctx.JSON(http.StatusOK, EventResponse{
 		ID:              event.ID,
 		EventTitle:      event.EventTitle,
@@ -242,6 +262,7 @@ func (c *EventController) UpdateEvent(ctx *gin.Context) {
 		InteractionCode: event.InteractionCode,
 		ScanMessage:     event.ScanMessage,
 		IsCurrent:       event.IsCurrent,
+		Capacity:        event.Capacity,
 		CreatedAt:       event.CreatedAt,
 		UpdatedAt:       getTimeValue(event.UpdatedAt),
 	})
@@ -283,7 +304,8 @@ func (c *EventController) SetCurrentEvent(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Current event set successfully"})
 }
 
-// GetEventStatistics retrieves event statistics
+// GetEventStatistics handles GET /events/:id/statistics?interval=hour|day with registration and
+// check-in trends, conversion by registration source and the live checked-in count
 func (c *EventController) GetEventStatistics(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -292,14 +314,588 @@ func (c *EventController) GetEventStatistics(ctx *gin.Context) {
 		return
 	}
 
-	stats, err := c.eventService.GetEventStatistics(ctx.Request.Context(), id)
+	interval := ctx.DefaultQuery("interval", services.StatisticsIntervalDay)
+	stats, err := c.eventService.GetEventStatistics(ctx.Request.Context(), id, interval)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatisticsInterval) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Interval must be hour or day"})
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
 		c.logger.Error("Failed to get event statistics", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve statistics"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, stats)
+	registrationTrend := make([]gin.H, 0, len(stats.RegistrationTrend))
+	for _, point := range stats.RegistrationTrend {
+		registrationTrend = append(registrationTrend, gin.H{"time": point.Date, "count": point.Count, "cumulative": point.Cumulative})
+	}
+	checkInTrend := make([]gin.H, 0, len(stats.CheckInTrend))
+	for _, point := range stats.CheckInTrend {
+		checkInTrend = append(checkInTrend, gin.H{"time": point.Date, "count": point.Count, "cumulative": point.Cumulative})
+	}
+	sources := make([]gin.H, 0, len(stats.TopSources))
+	for _, source := range stats.TopSources {
+		sources = append(sources, gin.H{
+			"source":         source.Source,
+			"registrations":  source.Count,
+			"percentage":     source.Percentage,
+			"checkedIn":      source.CheckedIn,
+			"conversionRate": source.ConversionRate,
+		})
+	}
+	methods := make([]gin.H, 0, len(stats.CheckInMethods))
+	for _, method := range stats.CheckInMethods {
+		methods = append(methods, gin.H{"method": method.Source, "count": method.Count, "percentage": method.Percentage})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"eventId":         stats.EventID,
+			"interval":        stats.Interval,
+			"totalRegistered": stats.TotalRegistered,
+			"totalWaitlisted": stats.TotalWaitlisted,
+			"totalCancelled":  stats.TotalCancelled,
+			"checkInRate":     stats.CheckInRate,
+			"checkedIn": gin.H{
+				"current":       stats.TotalCheckedIn,
+				"lastHour":      stats.CheckInsLastHour,
+				"lastCheckInAt": stats.LastCheckInAt,
+				"asOf":          stats.GeneratedAt,
+			},
+			"registrationTrend": registrationTrend,
+			"checkInTrend":      checkInTrend,
+			"sources":           sources,
+			"checkInMethods":    methods,
+		},
+	})
+}
+
+// GetEventAttendees retrieves the attendees of an event with pagination
+func (c *EventController) GetEventAttendees(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+
+	attendees, err := c.attendeeService.GetAttendeesByEvent(ctx.Request.Context(), eventID, offset, limit)
+	if err != nil {
+		c.logger.Error("Failed to get attendees", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attendees"})
+		return
+	}
+
+	response := make([]AttendeeResponse, 0, len(attendees))
+	for _, attendee := range attendees {
+		response = append(response, newAttendeeResponse(attendee))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"attendees": response,
+			"pagination": gin.H{
+				"offset": offset,
+				"limit":  limit,
+				"count":  len(response),
+			},
+		},
+	})
+}
+
+// RegisterAttendee registers a user for an event; the user is waitlisted when the event is full
+func (c *EventController) RegisterAttendee(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req RegisterAttendeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	source := req.Source
+	if source == "" {
+		source = "web"
+	}
+	registration := &services.AttendeeRegistration{
+		EventID:    eventID,
+		UserID:     req.UserID,
+		Notes:      req.Notes,
+		Source:     source,
+		SourceData: map[string]interface{}{"mobile": req.Mobile},
+	}
+
+	attendee, err := c.attendeeService.RegisterAttendee(ctx.Request.Context(), registration)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAlreadyRegistered):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "User is already registered for this event"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		default:
+			c.logger.Error("Failed to register attendee", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register attendee"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": newAttendeeResponse(attendee)})
+}
+
+// GetAttendeeCounts retrieves registration, waitlist and check-in counts for an event
+func (c *EventController) GetAttendeeCounts(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	counts, err := c.attendeeService.GetAttendeeCounts(ctx.Request.Context(), eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.logger.Error("Failed to get attendee counts", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attendee counts"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"eventId":    counts.EventID,
+			"capacity":   counts.Capacity,
+			"registered": counts.Registered,
+			"waitlisted": counts.Waitlisted,
+			"cancelled":  counts.Cancelled,
+			"checkedIn":  counts.CheckedIn,
+		},
+	})
+}
+
+// GetCheckInStatus retrieves a user's registration and check-in status for an event
+func (c *EventController) GetCheckInStatus(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+	userID, err := uuid.Parse(ctx.Query("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	status, err := c.attendeeService.GetCheckInStatus(ctx.Request.Context(), eventID, userID)
+	if err != nil {
+		c.logger.Error("Failed to get check-in status", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve check-in status"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"isRegistered": status.IsRegistered,
+			"isCheckedIn":  status.IsCheckedIn,
+			"checkInTime":  status.CheckInTime,
+			"qrCode":       status.QRCode,
+			"status":       status.Status,
+		},
+	})
+}
+
+// CheckInByQRCode checks in the attendee encoded in a scanned attendee QR code
+func (c *EventController) CheckInByQRCode(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	result, err := c.attendeeService.CheckInByQRCode(ctx.Request.Context(), req.QRCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Attendee not found"})
+			return
+		}
+		c.logger.Warn("Failed to check in by QR code", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid QR code"})
+		return
+	}
+	if result.Success && result.EventID != eventID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "QR code belongs to another event"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"success":          result.Success,
+			"attendeeId":       result.AttendeeID,
+			"eventId":          result.EventID,
+			"checkInTime":      result.CheckInTime,
+			"alreadyCheckedIn": result.AlreadyCheckedIn,
+			"message":          result.Message,
+		},
+	})
+}
+
+// CheckInAttendee checks in an attendee by ID
+func (c *EventController) CheckInAttendee(ctx *gin.Context) {
+	attendee, ok := c.getEventAttendee(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.attendeeService.CheckInAttendee(ctx.Request.Context(), attendee.ID); err != nil {
+		if errors.Is(err, services.ErrAttendeeNotRegistered) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Attendee does not hold a confirmed place"})
+			return
+		}
+		c.logger.Error("Failed to check in attendee", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in attendee"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Attendee checked in successfully"})
+}
+
+// CancelRegistration cancels an attendee's registration and promotes the next waitlisted attendee
+func (c *EventController) CancelRegistration(ctx *gin.Context) {
+	attendee, ok := c.getEventAttendee(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.attendeeService.CancelRegistration(ctx.Request.Context(), attendee.ID); err != nil {
+		c.logger.Error("Failed to cancel registration", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel registration"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Registration cancelled successfully"})
+}
+
+// UpdateAttendeeStatus changes an attendee's registration status
+func (c *EventController) UpdateAttendeeStatus(ctx *gin.Context) {
+	attendee, ok := c.getEventAttendee(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateAttendeeStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	if err := c.attendeeService.UpdateAttendeeStatus(ctx.Request.Context(), attendee.ID, req.Status); err != nil {
+		if errors.Is(err, services.ErrInvalidAttendeeStatus) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendee status"})
+			return
+		}
+		c.logger.Error("Failed to update attendee status", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update attendee status"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Attendee status updated successfully"})
+}
+
+// GetAttendeeTicket issues a signed ticket for an attendee, as JSON with an embedded PNG or as a
+// plain PNG with ?format=png
+func (c *EventController) GetAttendeeTicket(ctx *gin.Context) {
+	attendee, ok := c.getEventAttendee(ctx)
+	if !ok {
+		return
+	}
+
+	ticket, err := c.qrCodeService.GenerateAttendeeQRCode(ctx.Request.Context(), attendee.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAttendeeNotRegistered):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Attendee does not hold a confirmed place"})
+		case errors.Is(err, services.ErrEventEnded):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Event has already ended"})
+		case errors.Is(err, services.ErrTicketSigningDisabled):
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Attendee tickets are disabled"})
+		default:
+			c.logger.Error("Failed to generate ticket", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate ticket"})
+		}
+		return
+	}
+
+	size, _ := strconv.Atoi(ctx.Query("size"))
+	png, err := c.qrCodeService.GetQRCodeImage(ctx.Request.Context(), ticket.Code, size)
+	if err != nil {
+		c.logger.Error("Failed to render ticket", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate ticket"})
+		return
+	}
+
+	if ctx.Query("format") == "png" {
+		ctx.Data(http.StatusOK, "image/png", png)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"code":       ticket.Code,
+			"attendeeId": ticket.EntityID,
+			"issuedAt":   ticket.CreatedAt,
+			"expiresAt":  ticket.ExpiresAt,
+			"image":      "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		},
+	})
+}
+
+// ValidateTicket checks a ticket without admitting its holder
+func (c *EventController) ValidateTicket(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	result, err := c.qrCodeService.ValidateQRCode(ctx.Request.Context(), req.QRCode)
+	if err != nil {
+		c.logger.Error("Failed to validate ticket", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate ticket"})
+		return
+	}
+
+	isValid, message := result.IsValid, result.Message
+	if result.EventID != nil && *result.EventID != eventID {
+		isValid, message = false, "QR code belongs to another event"
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"isValid":   isValid,
+			"type":      result.Type,
+			"entityId":  result.EntityID,
+			"expiresAt": result.ExpiresAt,
+			"message":   message,
+		},
+	})
+}
+
+// ScanTicket admits the holder of a scanned ticket at the door. Each attendee is admitted once;
+// the scan is verified without a database lookup.
+func (c *EventController) ScanTicket(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req TicketScanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	response, err := c.scanTicket(ctx, eventID, req)
+	if err != nil {
+		c.logger.Error("Failed to process ticket scan", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process ticket scan"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// SyncTicketScans uploads scans a door scanner admitted while offline. Scans are replayed in order
+// with their original scan times; the first scan of an attendee across all scanners wins.
+func (c *EventController) SyncTicketScans(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req TicketScanSyncRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	sort.SliceStable(req.Scans, func(i, j int) bool {
+		return req.Scans[i].ScannedAt.Before(req.Scans[j].ScannedAt)
+	})
+
+	results := make([]gin.H, 0, len(req.Scans))
+	for _, scan := range req.Scans {
+		response, err := c.scanTicket(ctx, eventID, scan)
+		if err != nil {
+			c.logger.Error("Failed to process ticket scan", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process ticket scans"})
+			return
+		}
+		results = append(results, response)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": gin.H{"results": results}})
+}
+
+// RevokeTicket revokes a ticket, or every ticket of an attendee when given an attendee QR code or ID
+func (c *EventController) RevokeTicket(ctx *gin.Context) {
+	var req CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	if err := c.qrCodeService.DeactivateQRCode(ctx.Request.Context(), req.QRCode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Attendee not found"})
+			return
+		}
+		if errors.Is(err, services.ErrTicketSigningDisabled) {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Attendee tickets are disabled"})
+			return
+		}
+		c.logger.Warn("Failed to revoke ticket", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid QR code"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Ticket revoked successfully"})
+}
+
+// GetTicketVerificationKey returns what door scanners cache to verify tickets while offline:
+// the public key and the current revocation list
+func (c *EventController) GetTicketVerificationKey(ctx *gin.Context) {
+	key, err := c.qrCodeService.GetTicketVerificationKey(ctx.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrTicketSigningDisabled) {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Attendee tickets are disabled"})
+			return
+		}
+		c.logger.Error("Failed to get ticket verification key", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve verification key"})
+		return
+	}
+
+	revocations := make([]gin.H, 0, len(key.Revocations))
+	for _, revocation := range key.Revocations {
+		revocations = append(revocations, gin.H{
+			"id":        revocation.ID,
+			"revokedAt": revocation.RevokedAt.Unix(),
+			"expiresAt": revocation.ExpiresAt.Unix(),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"algorithm":   key.Algorithm,
+			"keyId":       key.KeyID,
+			"publicKey":   base64.StdEncoding.EncodeToString(key.PublicKey),
+			"revocations": revocations,
+			"generatedAt": key.GeneratedAt,
+		},
+	})
+}
+
+// scanTicket processes one ticket scan, refusing tickets of other events before they are consumed
+func (c *EventController) scanTicket(ctx *gin.Context, eventID uuid.UUID, req TicketScanRequest) (gin.H, error) {
+	validation, err := c.qrCodeService.ValidateQRCode(ctx.Request.Context(), req.QRCode)
+	if err != nil {
+		return nil, err
+	}
+	if validation.EventID != nil && *validation.EventID != eventID {
+		return gin.H{"qrCode": req.QRCode, "success": false, "message": "QR code belongs to another event"}, nil
+	}
+
+	result, err := c.qrCodeService.ProcessQRCodeScan(ctx.Request.Context(), req.QRCode, &services.QRScannerInfo{
+		Source:    "scanner",
+		Timestamp: req.ScannedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := gin.H{
+		"qrCode":     req.QRCode,
+		"success":    result.Success,
+		"type":       result.Type,
+		"eventId":    result.EventID,
+		"attendeeId": result.AttendeeID,
+		"action":     result.Action,
+		"message":    result.Message,
+	}
+	for key, value := range result.Data {
+		response[key] = value
+	}
+	return response, nil
+}
+
+// getEventAttendee loads the attendee from the path and checks it belongs to the event in the path
+func (c *EventController) getEventAttendee(ctx *gin.Context) (*entities.EventAttendee, bool) {
+	eventID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, false
+	}
+	attendeeID, err := uuid.Parse(ctx.Param("attendeeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendee ID"})
+		return nil, false
+	}
+
+	attendee, err := c.attendeeService.GetAttendeeByID(ctx.Request.Context(), attendeeID)
+	if err != nil || attendee.EventID != eventID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Attendee not found"})
+		return nil, false
+	}
+	return attendee, true
+}
+
+// newAttendeeResponse converts an attendee entity to its API representation
+func newAttendeeResponse(attendee *entities.EventAttendee) AttendeeResponse {
+	status := attendee.Status
+	if status == "" {
+		status = entities.AttendeeStatusRegistered
+	}
+	return AttendeeResponse{
+		ID:            attendee.ID,
+		EventID:       attendee.EventID,
+		UserID:        attendee.UserID,
+		Mobile:        attendee.Mobile,
+		Name:          attendee.Name,
+		Company:       attendee.Company,
+		Status:        status,
+		Source:        attendee.Source,
+		Notes:         attendee.Notes,
+		OnSiteScanned: attendee.OnSiteScanned,
+		CheckInDate:   attendee.CheckInDate,
+		CheckInMethod: attendee.CheckInMethod,
+		CancelledAt:   attendee.CancelledAt,
+		CreatedAt:     attendee.CreatedAt,
+	}
 }
 
 // Request/Response DTOs
@@ -312,6 +908,7 @@ type CreateEventRequest struct {
 	InteractionCode string    `json:"interactionCode"`
 	ScanMessage     string    `json:"scanMessage"`
 	IsCurrent       bool      `json:"isCurrent"`
+	Capacity        int       `json:"capacity" binding:"min=0"`
 }
 
 type UpdateEventRequest struct {
@@ -321,6 +918,7 @@ type UpdateEventRequest struct {
 	TagName        *string    `json:"tagName"`
 	ScanMessage    *string    `json:"scanMessage"`
 	IsCurrent      *bool      `json:"isCurrent"`
+	Capacity       *int       `json:"capacity" binding:"omitempty,min=0"`
 }
 
 type EventResponse struct {
@@ -333,6 +931,48 @@ type EventResponse struct {
 	InteractionCode string    `json:"interactionCode"`
 	ScanMessage     string    `json:"scanMessage"`
 	IsCurrent       bool      `json:"isCurrent"`
+	Capacity        int       `json:"capacity"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 }
+
+type RegisterAttendeeRequest struct {
+	UserID uuid.UUID `json:"userId" binding:"required"`
+	Mobile string    `json:"mobile"`
+	Notes  string    `json:"notes"`
+	Source string    `json:"source" binding:"omitempty,oneof=wechat web api"`
+}
+
+type UpdateAttendeeStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type CheckInRequest struct {
+	QRCode string `json:"qrCode" binding:"required"`
+}
+
+type TicketScanRequest struct {
+	QRCode    string    `json:"qrCode" binding:"required"`
+	ScannedAt time.Time `json:"scannedAt"` // Set by scanners replaying offline scans
+}
+
+type TicketScanSyncRequest struct {
+	Scans []TicketScanRequest `json:"scans" binding:"required,dive"`
+}
+
+type AttendeeResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	EventID       uuid.UUID  `json:"eventId"`
+	UserID        *uuid.UUID `json:"userId,omitempty"`
+	Mobile        string     `json:"mobile"`
+	Name          string     `json:"name,omitempty"`
+	Company       string     `json:"company,omitempty"`
+	Status        string     `json:"status"`
+	Source        string     `json:"source"`
+	Notes         string     `json:"notes"`
+	OnSiteScanned bool       `json:"onSiteScanned"`
+	CheckInDate   *time.Time `json:"checkInDate,omitempty"`
+	CheckInMethod string     `json:"checkInMethod,omitempty"`
+	CancelledAt   *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"html"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -8,19 +11,24 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/zenteam/nextevent-go/internal/application/services"
+	domainServices "github.com/zenteam/nextevent-go/internal/domain/services"
 )
 
 // MobileController handles mobile preview requests
 type MobileController struct {
-	surveyService *services.SurveyService
-	logger        *zap.Logger
+	surveyService   *services.SurveyService
+	pipingService   domainServices.SurveyPipingService
+	livePollService domainServices.SurveyLivePollService
+	logger          *zap.Logger
 }
 
 // NewMobileController creates a new mobile controller
-func NewMobileController(surveyService *services.SurveyService, logger *zap.Logger) *MobileController {
+func NewMobileController(surveyService *services.SurveyService, pipingService domainServices.SurveyPipingService, livePollService domainServices.SurveyLivePollService, logger *zap.Logger) *MobileController {
 	return &MobileController{
-		surveyService: surveyService,
-		logger:        logger,
+		surveyService:   surveyService,
+		pipingService:   pipingService,
+		livePollService: livePollService,
+		logger:          logger,
 	}
 }
 
@@ -287,6 +295,26 @@ func (c *MobileController) GetSurveyParticipate(ctx *gin.Context) {
 		return
 	}
 
+	// Render the questions with the answers given so far (?answers=) piped into them
+	questionsJSON := []byte("[]")
+	if questions, err := c.surveyService.GetQuestionsBySurvey(ctx.Request.Context(), id); err != nil {
+		c.logger.Error("Failed to get survey questions", zap.Error(err), zap.String("surveyId", id.String()))
+	} else {
+		if c.pipingService != nil {
+			answers, err := parsePipingAnswers(ctx.Query("answers"))
+			if err != nil {
+				c.logger.Warn("Ignoring invalid survey answers", zap.Error(err), zap.String("surveyId", id.String()))
+			} else if piped, err := pipeQuestionResponses(ctx.Request.Context(), c.pipingService, id, questions, answers); err != nil {
+				c.logger.Error("Failed to pipe survey answers", zap.Error(err), zap.String("surveyId", id.String()))
+			} else {
+				questions = piped
+			}
+		}
+		if data, err := json.Marshal(questions); err == nil {
+			questionsJSON = data
+		}
+	}
+
 	// Serve participation page (will be handled by React app)
 	ctx.Header("Content-Type", "text/html")
 	ctx.String(http.StatusOK, `
@@ -303,6 +331,7 @@ func (c *MobileController) GetSurveyParticipate(ctx *gin.Context) {
         <p>%s</p>
     </div>
     <script>
+        window.__SURVEY_QUESTIONS__ = %s;
         // Redirect to React app for survey participation
         window.location.href = '/mobile/surveys/%s/participate%s';
     </script>
@@ -310,6 +339,7 @@ func (c *MobileController) GetSurveyParticipate(ctx *gin.Context) {
 </html>`, 
 		survey.SurveyTitle,
 		survey.SurveyTitle,
+		questionsJSON,
 		id.String(),
 		func() string {
 			if qrCodeId != "" {
@@ -318,3 +348,151 @@ func (c *MobileController) GetSurveyParticipate(ctx *gin.Context) {
 			return ""
 		}())
 }
+
+// GetLivePoll handles GET /mobile/live/:code, the page attendees open with the interaction code of
+// an event to vote on its live polls. The page follows the results stream to pick up each question
+// the presenter opens.
+func (c *MobileController) GetLivePoll(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	poll, err := c.livePollService.GetCurrentPoll(ctx.Request.Context(), code)
+	if err != nil && !errors.Is(err, domainServices.ErrNoLivePoll) {
+		c.logger.Warn("Failed to get live poll", zap.String("code", code), zap.Error(err))
+		ctx.Header("Content-Type", "text/html")
+		ctx.String(http.StatusNotFound, `
+<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>互动码无效</title>
+</head>
+<body>
+    <div style="text-align: center; padding: 50px;">
+        <h2>互动码无效</h2>
+        <p>请检查互动码 %s 是否正确</p>
+    </div>
+</body>
+</html>`, html.EscapeString(code))
+		return
+	}
+
+	pollJSON, err := json.Marshal(poll)
+	if err != nil {
+		pollJSON = []byte("null")
+	}
+	codeJSON, _ := json.Marshal(code)
+	// Issue the voter cookie before the first vote
+	liveVoterID(ctx)
+
+	ctx.Header("Content-Type", "text/html")
+	ctx.String(http.StatusOK, `
+<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>现场投票</title>
+    <style>
+        body { margin: 0; padding: 20px; font-family: -apple-system, BlinkMacSystemFont, sans-serif; }
+        .waiting, .voted { text-align: center; padding: 50px 0; color: #666; }
+        .option { display: block; width: 100%%; padding: 14px; margin: 10px 0; font-size: 16px; border: 1px solid #007bff; border-radius: 6px; background: white; color: #007bff; }
+        .option.selected { background: #007bff; color: white; }
+        .submit { display: block; width: 100%%; padding: 14px; font-size: 16px; border: none; border-radius: 6px; background: #28a745; color: white; }
+        .error { color: #e74c3c; }
+    </style>
+</head>
+<body>
+    <div id="poll"></div>
+    <script>
+        window.__LIVE_POLL__ = %s;
+        var code = %s;
+        var api = '/api/v1/public/live-polls/' + encodeURIComponent(code);
+        var root = document.getElementById('poll');
+        var poll = window.__LIVE_POLL__;
+        var selected = [];
+
+        function text(tag, className, value) {
+            var el = document.createElement(tag);
+            if (className) el.className = className;
+            el.textContent = value;
+            return el;
+        }
+
+        function choices(question) {
+            if (question.questionType !== 'rating' && question.questionType !== 'scale') return question.options || [];
+            var bounds = question.options && question.options.length >= 2 ? question.options : ['1', '5'];
+            var values = [];
+            for (var v = Math.ceil(Number(bounds[0])); v <= Number(bounds[1]); v++) values.push(String(v));
+            return values;
+        }
+
+        function render(message) {
+            root.innerHTML = '';
+            selected = [];
+            if (!poll || poll.status !== 'open') {
+                root.appendChild(text('div', 'waiting', '等待主持人开始投票...'));
+                return;
+            }
+            root.appendChild(text('h2', '', poll.question.questionText));
+            if (localStorage.getItem('livePollVoted:' + poll.id)) {
+                root.appendChild(text('div', 'voted', '感谢参与，请查看大屏幕上的结果'));
+                return;
+            }
+            var multiple = poll.question.questionType === 'checkbox';
+            choices(poll.question).forEach(function (option) {
+                var button = text('button', 'option', option);
+                button.onclick = function () {
+                    if (!multiple) return vote([option]);
+                    var i = selected.indexOf(option);
+                    if (i >= 0) selected.splice(i, 1); else selected.push(option);
+                    button.classList.toggle('selected', i < 0);
+                };
+                root.appendChild(button);
+            });
+            if (multiple) {
+                var submit = text('button', 'submit', '提交');
+                submit.onclick = function () { if (selected.length) vote(selected.slice()); };
+                root.appendChild(submit);
+            }
+            if (message) root.appendChild(text('p', 'error', message));
+        }
+
+        function vote(options) {
+            fetch(api + '/votes', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ pollId: poll.id, options: options })
+            }).then(function (res) {
+                return res.json().then(function (body) {
+                    if (res.ok || res.status === 409) {
+                        localStorage.setItem('livePollVoted:' + poll.id, '1');
+                        return refresh();
+                    }
+                    render(body.error || '投票失败，请重试');
+                });
+            }).catch(function () { render('网络错误，请重试'); });
+        }
+
+        function refresh() {
+            return fetch(api).then(function (res) { return res.json(); }).then(function (body) {
+                poll = body.success ? body.data : null;
+                render();
+            });
+        }
+
+        // Follow the big screen: a new poll or a closed one changes the results
+        var shown = poll ? poll.id + poll.status : '';
+        new EventSource(api + '/stream').addEventListener('results', function (event) {
+            var results = JSON.parse(event.data);
+            var current = results ? results.pollId + results.status : '';
+            if (current !== shown) {
+                shown = current;
+                refresh();
+            }
+        });
+        render();
+    </script>
+</body>
+</html>`, pollJSON, codeJSON)
+}
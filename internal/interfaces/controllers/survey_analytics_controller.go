@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyAnalyticsController handles survey result analytics
+type SurveyAnalyticsController struct {
+	analyticsService services.SurveyAnalyticsService
+	logger           *zap.Logger
+}
+
+// NewSurveyAnalyticsController creates a new survey analytics controller
+func NewSurveyAnalyticsController(analyticsService services.SurveyAnalyticsService, logger *zap.Logger) *SurveyAnalyticsController {
+	return &SurveyAnalyticsController{
+		analyticsService: analyticsService,
+		logger:           logger,
+	}
+}
+
+// GetSurveyAnalytics handles GET /api/v1/surveys/:surveyId/analytics. Analytics are refreshed
+// periodically; pass refresh=true to recalculate them right away, or collectorId to get the
+// analytics of the responses collected by one collector.
+func (c *SurveyAnalyticsController) GetSurveyAnalytics(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var analytics *entities.SurveyAnalytics
+	if value := ctx.Query("collectorId"); value != "" {
+		collectorID, parseErr := uuid.Parse(value)
+		if parseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid collector ID"})
+			return
+		}
+		analytics, err = c.analyticsService.GetCollectorAnalytics(ctx.Request.Context(), surveyID, collectorID)
+	} else if ctx.Query("refresh") == "true" {
+		analytics, err = c.analyticsService.RefreshAnalytics(ctx.Request.Context(), surveyID)
+	} else {
+		analytics, err = c.analyticsService.GetAnalytics(ctx.Request.Context(), surveyID)
+	}
+	if err != nil {
+		c.handleAnalyticsError(ctx, err, "Failed to get survey analytics")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": analytics})
+}
+
+// GetSurveyCrossTab handles GET /api/v1/surveys/:surveyId/analytics/crosstab?row=:questionId&column=:questionId
+func (c *SurveyAnalyticsController) GetSurveyCrossTab(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+	rowQuestionID, err := uuid.Parse(ctx.Query("row"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid row question ID"})
+		return
+	}
+	columnQuestionID, err := uuid.Parse(ctx.Query("column"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid column question ID"})
+		return
+	}
+
+	crossTab, err := c.analyticsService.GetCrossTab(ctx.Request.Context(), surveyID, rowQuestionID, columnQuestionID)
+	if err != nil {
+		c.handleAnalyticsError(ctx, err, "Failed to cross-tabulate survey answers")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": crossTab})
+}
+
+// handleAnalyticsError maps analytics service errors to responses
+func (c *SurveyAnalyticsController) handleAnalyticsError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey not found"})
+	case errors.Is(err, services.ErrInvalidSurveyCrossTab):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyCollectorController handles the collectors through which a survey is distributed
+type SurveyCollectorController struct {
+	collectorService services.SurveyCollectorService
+	logger           *zap.Logger
+}
+
+// NewSurveyCollectorController creates a new survey collector controller
+func NewSurveyCollectorController(collectorService services.SurveyCollectorService, logger *zap.Logger) *SurveyCollectorController {
+	return &SurveyCollectorController{
+		collectorService: collectorService,
+		logger:           logger,
+	}
+}
+
+// SurveyCollectorRequest is the body of collector create and update requests
+type SurveyCollectorRequest struct {
+	Name         string          `json:"name" binding:"required"`
+	Type         string          `json:"type" binding:"required"`
+	OpensAt      *time.Time      `json:"opensAt"`
+	ClosesAt     *time.Time      `json:"closesAt"`
+	MaxResponses *int            `json:"maxResponses"`
+	Settings     json.RawMessage `json:"settings"`
+	IsActive     *bool           `json:"isActive"`
+}
+
+// GetSurveyCollectors handles GET /api/v1/surveys/:surveyId/collectors
+func (c *SurveyCollectorController) GetSurveyCollectors(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	collectors, err := c.collectorService.GetSurveyCollectors(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.logger.Error("Failed to get survey collectors", zap.Error(err), zap.String("surveyId", surveyID.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey collectors"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(collectors))
+	for i := range collectors {
+		data = append(data, newSurveyCollectorResponse(&collectors[i]))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// CreateSurveyCollector handles POST /api/v1/surveys/:surveyId/collectors
+func (c *SurveyCollectorController) CreateSurveyCollector(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyCollectorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	collector := &entities.SurveyCollector{ID: uuid.New(), SurveyID: surveyID, IsActive: true}
+	if userID := currentUserID(ctx); userID != nil {
+		collector.CreatedBy = *userID
+	}
+	req.apply(collector)
+	if err := c.collectorService.CreateCollector(ctx.Request.Context(), collector); err != nil {
+		c.handleCollectorError(ctx, err, "Failed to create survey collector")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": newSurveyCollectorResponse(collector)})
+}
+
+// UpdateSurveyCollector handles PUT /api/v1/surveys/collectors/:collectorId
+func (c *SurveyCollectorController) UpdateSurveyCollector(ctx *gin.Context) {
+	collectorID, err := uuid.Parse(ctx.Param("collectorId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid collector ID"})
+		return
+	}
+
+	var req SurveyCollectorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	collector, err := c.collectorService.GetCollector(ctx.Request.Context(), collectorID)
+	if err != nil {
+		c.handleCollectorError(ctx, err, "Failed to get survey collector")
+		return
+	}
+	req.apply(collector)
+	if err := c.collectorService.UpdateCollector(ctx.Request.Context(), collector); err != nil {
+		c.handleCollectorError(ctx, err, "Failed to update survey collector")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyCollectorResponse(collector)})
+}
+
+// DeleteSurveyCollector handles DELETE /api/v1/surveys/collectors/:collectorId
+func (c *SurveyCollectorController) DeleteSurveyCollector(ctx *gin.Context) {
+	collectorID, err := uuid.Parse(ctx.Param("collectorId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid collector ID"})
+		return
+	}
+
+	if err := c.collectorService.DeleteCollector(ctx.Request.Context(), collectorID); err != nil {
+		c.handleCollectorError(ctx, err, "Failed to delete survey collector")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Survey collector deleted successfully"})
+}
+
+// OpenSurveyCollector handles POST /api/v1/surveys/collectors/:collectorId/open
+func (c *SurveyCollectorController) OpenSurveyCollector(ctx *gin.Context) {
+	c.setCollectorActive(ctx, true)
+}
+
+// CloseSurveyCollector handles POST /api/v1/surveys/collectors/:collectorId/close
+func (c *SurveyCollectorController) CloseSurveyCollector(ctx *gin.Context) {
+	c.setCollectorActive(ctx, false)
+}
+
+// GenerateSurveyCollectorQRCode handles POST /api/v1/surveys/collectors/:collectorId/qrcode
+func (c *SurveyCollectorController) GenerateSurveyCollectorQRCode(ctx *gin.Context) {
+	collectorID, err := uuid.Parse(ctx.Param("collectorId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid collector ID"})
+		return
+	}
+
+	qrCode, err := c.collectorService.GenerateQRCode(ctx.Request.Context(), collectorID)
+	if err != nil {
+		c.handleCollectorError(ctx, err, "Failed to generate survey collector QR code")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"id":         qrCode.ID,
+		"url":        qrCode.Url,
+		"expireTime": qrCode.ExpireTime,
+	}})
+}
+
+// setCollectorActive opens or closes the collector of the request
+func (c *SurveyCollectorController) setCollectorActive(ctx *gin.Context, active bool) {
+	collectorID, err := uuid.Parse(ctx.Param("collectorId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid collector ID"})
+		return
+	}
+
+	collector, err := c.collectorService.SetCollectorActive(ctx.Request.Context(), collectorID, active)
+	if err != nil {
+		c.handleCollectorError(ctx, err, "Failed to update survey collector")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyCollectorResponse(collector)})
+}
+
+// handleCollectorError maps collector service errors to responses
+func (c *SurveyCollectorController) handleCollectorError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey collector not found"})
+	case errors.Is(err, entities.ErrInvalidCollector):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// apply copies the request onto a collector
+func (r *SurveyCollectorRequest) apply(collector *entities.SurveyCollector) {
+	collector.Name = r.Name
+	collector.Type = r.Type
+	collector.OpensAt = r.OpensAt
+	collector.ClosesAt = r.ClosesAt
+	collector.MaxResponses = r.MaxResponses
+	collector.Settings = "{}"
+	if len(r.Settings) > 0 {
+		collector.Settings = string(r.Settings)
+	}
+	if r.IsActive != nil {
+		collector.IsActive = *r.IsActive
+	}
+}
+
+// newSurveyCollectorResponse converts a collector to JSON, including whether it accepts responses
+func newSurveyCollectorResponse(collector *entities.SurveyCollector) gin.H {
+	return gin.H{
+		"id":               collector.ID,
+		"surveyId":         collector.SurveyID,
+		"name":             collector.Name,
+		"type":             collector.Type,
+		"url":              collector.URL,
+		"embedCode":        collector.EmbedCode,
+		"qrCodeUrl":        collector.QRCodeURL,
+		"opensAt":          collector.OpensAt,
+		"closesAt":         collector.ClosesAt,
+		"maxResponses":     collector.MaxResponses,
+		"responseCount":    collector.ResponseCount,
+		"isFull":           collector.IsFull(),
+		"isActive":         collector.IsActive,
+		"acceptsResponses": collector.AcceptsResponses(time.Now()),
+		"settings":         json.RawMessage(collector.Settings),
+		"createdBy":        collector.CreatedBy,
+		"createdAt":        collector.CreatedAt,
+		"updatedAt":        collector.UpdatedAt,
+	}
+}
@@ -9,19 +9,22 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/zenteam/nextevent-go/internal/application/services"
+	domainServices "github.com/zenteam/nextevent-go/internal/domain/services"
 	"github.com/zenteam/nextevent-go/internal/interfaces/dto"
 )
 
 // SurveyController handles survey-related HTTP requests
 type SurveyController struct {
 	surveyService *services.SurveyService
+	pipingService domainServices.SurveyPipingService
 	logger        *zap.Logger
 }
 
 // NewSurveyController creates a new survey controller
-func NewSurveyController(surveyService *services.SurveyService, logger *zap.Logger) *SurveyController {
+func NewSurveyController(surveyService *services.SurveyService, pipingService domainServices.SurveyPipingService, logger *zap.Logger) *SurveyController {
 	return &SurveyController{
 		surveyService: surveyService,
+		pipingService: pipingService,
 		logger:        logger,
 	}
 }
@@ -273,7 +276,9 @@ func (c *SurveyController) DeleteSurvey(ctx *gin.Context) {
 	})
 }
 
-// GetPublicSurvey handles GET /api/v1/public/surveys/:id (for public access)
+// GetPublicSurvey handles GET /api/v1/public/surveys/:id (for public access).
+// The answers given so far can be passed as ?answers={"<questionId>":"value"} to pipe them
+// into later questions.
 func (c *SurveyController) GetPublicSurvey(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -285,6 +290,15 @@ func (c *SurveyController) GetPublicSurvey(ctx *gin.Context) {
 		return
 	}
 
+	answers, err := parsePipingAnswers(ctx.Query("answers"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid answers: " + err.Error(),
+		})
+		return
+	}
+
 	response, err := c.surveyService.GetSurveyWithQuestions(ctx.Request.Context(), id)
 	if err != nil {
 		c.logger.Error("Failed to get public survey", zap.Error(err), zap.String("surveyId", idStr))
@@ -311,6 +325,20 @@ func (c *SurveyController) GetPublicSurvey(ctx *gin.Context) {
 		return
 	}
 
+	// Substitute earlier answers into later questions
+	if c.pipingService != nil {
+		questions, err := pipeQuestionResponses(ctx.Request.Context(), c.pipingService, id, response.Questions, answers)
+		if err != nil {
+			c.logger.Error("Failed to pipe survey answers", zap.Error(err), zap.String("surveyId", idStr))
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Failed to get survey",
+			})
+			return
+		}
+		response.Questions = questions
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    response,
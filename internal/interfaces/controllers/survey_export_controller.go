@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyExportController handles asynchronous survey result exports
+type SurveyExportController struct {
+	exportService services.SurveyExportService
+	logger        *zap.Logger
+}
+
+// NewSurveyExportController creates a new survey export controller
+func NewSurveyExportController(exportService services.SurveyExportService, logger *zap.Logger) *SurveyExportController {
+	return &SurveyExportController{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// SurveyExportRequest is the body of export requests
+type SurveyExportRequest struct {
+	ExportType        string                  `json:"exportType" binding:"required"` // csv, excel, json or spss
+	StartDate         *time.Time              `json:"startDate"`
+	EndDate           *time.Time              `json:"endDate"`
+	Status            entities.ResponseStatus `json:"status"`
+	IncludeIncomplete bool                    `json:"includeIncomplete"`
+}
+
+// CreateSurveyExport handles POST /api/v1/surveys/:surveyId/exports. The export runs in the
+// background; poll it until it is completed, then download it.
+func (c *SurveyExportController) CreateSurveyExport(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyExportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	export := &entities.SurveyExport{ID: uuid.New(), SurveyID: surveyID, ExportType: req.ExportType}
+	if userID := currentUserID(ctx); userID != nil {
+		export.RequestedBy = *userID
+	}
+	err = export.SetFilters(&entities.SurveyExportFilters{
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+		Status:            req.Status,
+		IncludeIncomplete: req.IncludeIncomplete,
+	})
+	if err == nil {
+		err = c.exportService.RequestExport(ctx.Request.Context(), export)
+	}
+	if err != nil {
+		c.handleExportError(ctx, err, "Failed to request survey export")
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"success": true, "data": export})
+}
+
+// GetSurveyExports handles GET /api/v1/surveys/:surveyId/exports
+func (c *SurveyExportController) GetSurveyExports(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	exports, err := c.exportService.GetSurveyExports(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.logger.Error("Failed to get survey exports", zap.Error(err), zap.String("surveyId", surveyID.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey exports"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": exports})
+}
+
+// GetSurveyExport handles GET /api/v1/surveys/exports/:exportId
+func (c *SurveyExportController) GetSurveyExport(ctx *gin.Context) {
+	exportID, err := uuid.Parse(ctx.Param("exportId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid export ID"})
+		return
+	}
+
+	export, err := c.exportService.GetExport(ctx.Request.Context(), exportID)
+	if err != nil {
+		c.handleExportError(ctx, err, "Failed to get survey export")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": export})
+}
+
+// DownloadSurveyExport handles GET /api/v1/surveys/exports/:exportId/download by streaming the
+// export file, which is not reachable any other way
+func (c *SurveyExportController) DownloadSurveyExport(ctx *gin.Context) {
+	exportID, err := uuid.Parse(ctx.Param("exportId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid export ID"})
+		return
+	}
+
+	export, file, err := c.exportService.OpenExportFile(ctx.Request.Context(), exportID)
+	if err != nil {
+		c.handleExportError(ctx, err, "Failed to open survey export")
+		return
+	}
+	defer file.Close()
+
+	filename := fmt.Sprintf("survey-%s-export-%s.%s", export.SurveyID, export.ID, export.FileExtension())
+	ctx.DataFromReader(http.StatusOK, export.FileSize, surveyExportContentType(export), file, map[string]string{
+		"Content-Disposition":    fmt.Sprintf(`attachment; filename="%s"`, filename),
+		"Cache-Control":          "no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// surveyExportContentType returns the media type of an export file
+func surveyExportContentType(export *entities.SurveyExport) string {
+	switch export.ExportType {
+	case entities.SurveyExportTypeXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case entities.SurveyExportTypeJSON:
+		return "application/json"
+	case entities.SurveyExportTypeSPSS:
+		return "application/zip"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// handleExportError maps export service errors to responses
+func (c *SurveyExportController) handleExportError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey or export not found"})
+	case errors.Is(err, entities.ErrInvalidExport):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyExportNotReady):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyExportExpired):
+		ctx.JSON(http.StatusGone, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
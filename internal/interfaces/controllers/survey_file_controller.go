@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyFileController serves the files respondents uploaded for file questions
+type SurveyFileController struct {
+	fileService services.SurveyFileService
+	logger      *zap.Logger
+}
+
+// NewSurveyFileController creates a new survey file controller
+func NewSurveyFileController(fileService services.SurveyFileService, logger *zap.Logger) *SurveyFileController {
+	return &SurveyFileController{
+		fileService: fileService,
+		logger:      logger,
+	}
+}
+
+// DownloadSurveyFile handles GET /api/v1/surveys/:surveyId/files/:questionId/:responseId/:fileName.
+// Files are always sent as attachments, so uploaded HTML or SVG is never rendered by the browser.
+func (c *SurveyFileController) DownloadSurveyFile(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+	questionID, err := uuid.Parse(ctx.Param("questionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid question ID"})
+		return
+	}
+	responseID, err := uuid.Parse(ctx.Param("responseId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid response ID"})
+		return
+	}
+	// Uploads are stored under a random UUID and the extension of the original name
+	fileName := ctx.Param("fileName")
+	if _, err := uuid.Parse(strings.TrimSuffix(fileName, path.Ext(fileName))); err != nil || path.Base(fileName) != fileName {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid file name"})
+		return
+	}
+
+	key := entities.SurveyFileKeyPrefix(surveyID, questionID) + responseID.String() + "/" + fileName
+	file, err := c.fileService.OpenFile(ctx.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "File not found"})
+			return
+		}
+		c.logger.Error("Failed to open survey file", zap.Error(err), zap.String("key", key))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to open survey file"})
+		return
+	}
+	defer file.Close()
+
+	ctx.DataFromReader(http.StatusOK, -1, "application/octet-stream", file, map[string]string{
+		"Content-Disposition":    `attachment; filename="` + fileName + `"`,
+		"Cache-Control":          "private, no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// maxInviteeUploadSize bounds uploaded invitee lists
+const maxInviteeUploadSize = 5 << 20
+
+// SurveyInvitationController handles survey invitations and their links
+type SurveyInvitationController struct {
+	invitationService services.SurveyInvitationService
+	logger            *zap.Logger
+}
+
+// NewSurveyInvitationController creates a new survey invitation controller
+func NewSurveyInvitationController(invitationService services.SurveyInvitationService, logger *zap.Logger) *SurveyInvitationController {
+	return &SurveyInvitationController{
+		invitationService: invitationService,
+		logger:            logger,
+	}
+}
+
+// SurveyInvitationRequest is the body of a bulk invitation request
+type SurveyInvitationRequest struct {
+	Channel   string                         `json:"channel" binding:"required,oneof=email wechat"`
+	Invitees  []entities.SurveyInvitee       `json:"invitees"`
+	Segment   *services.SurveyInviteeSegment `json:"segment"` // Subscribed WeChat followers to invite
+	Message   string                         `json:"message"`
+	ExpiresAt *time.Time                     `json:"expiresAt"`
+}
+
+// CreateSurveyInvitations handles POST /api/v1/surveys/:surveyId/invitations
+func (c *SurveyInvitationController) CreateSurveyInvitations(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	c.createInvitations(ctx, &services.SurveyInvitationRequest{
+		SurveyID:  surveyID,
+		Channel:   req.Channel,
+		Invitees:  req.Invitees,
+		Segment:   req.Segment,
+		Message:   req.Message,
+		ExpiresAt: req.ExpiresAt,
+	})
+}
+
+// UploadSurveyInvitations handles POST /api/v1/surveys/:surveyId/invitations/upload. The form has
+// the invitee list as a CSV file and the channel, message and expiresAt (RFC 3339) fields.
+func (c *SurveyInvitationController) UploadSurveyInvitations(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "An invitee list file is required"})
+		return
+	}
+	if fileHeader.Size > maxInviteeUploadSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invitee list is too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Failed to read invitee list"})
+		return
+	}
+	defer file.Close()
+
+	invitees, err := entities.ParseSurveyInviteeCSV(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	req := &services.SurveyInvitationRequest{
+		SurveyID: surveyID,
+		Channel:  ctx.DefaultPostForm("channel", entities.SurveyInvitationChannelEmail),
+		Invitees: invitees,
+		Message:  ctx.PostForm("message"),
+	}
+	if value := ctx.PostForm("expiresAt"); value != "" {
+		expiresAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid expiresAt, use RFC 3339"})
+			return
+		}
+		req.ExpiresAt = &expiresAt
+	}
+	c.createInvitations(ctx, req)
+}
+
+// GetSurveyInvitations handles GET /api/v1/surveys/:surveyId/invitations?status=
+func (c *SurveyInvitationController) GetSurveyInvitations(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	invitations, err := c.invitationService.GetInvitations(ctx.Request.Context(), surveyID, ctx.Query("status"))
+	if err != nil {
+		c.handleInvitationError(ctx, err, "Failed to get survey invitations")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": invitations})
+}
+
+// RemindSurveyInvitees handles POST /api/v1/surveys/:surveyId/invitations/remind. Only invitees
+// whose reminder is due are reminded.
+func (c *SurveyInvitationController) RemindSurveyInvitees(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	reminded, err := c.invitationService.RemindSurvey(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.handleInvitationError(ctx, err, "Failed to remind survey invitees")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"reminded": reminded}})
+}
+
+// OpenSurveyInvitation handles GET /api/v1/public/survey-invitations/:token. The survey page calls
+// it when opened from an invitation link, then starts the response with the token.
+func (c *SurveyInvitationController) OpenSurveyInvitation(ctx *gin.Context) {
+	invitation, err := c.invitationService.OpenInvitation(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		c.handleInvitationError(ctx, err, "Failed to open survey invitation")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"surveyId":  invitation.SurveyID,
+		"name":      invitation.Name,
+		"status":    invitation.Status,
+		"expiresAt": invitation.ExpiresAt,
+	}})
+}
+
+// createInvitations creates a batch of invitations and reports the outcome
+func (c *SurveyInvitationController) createInvitations(ctx *gin.Context, req *services.SurveyInvitationRequest) {
+	result, err := c.invitationService.CreateInvitations(ctx.Request.Context(), req)
+	if err != nil {
+		c.handleInvitationError(ctx, err, "Failed to create survey invitations")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": result})
+}
+
+// handleInvitationError maps invitation service errors to responses
+func (c *SurveyInvitationController) handleInvitationError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey or invitation not found"})
+	case errors.Is(err, entities.ErrInvalidInvitation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyNotOpen),
+		errors.Is(err, services.ErrSurveyClosed):
+		ctx.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyInvitationExpired):
+		ctx.JSON(http.StatusGone, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyInvitationUsed):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// liveResultsInterval is how often the results stream checks for new votes
+const liveResultsInterval = time.Second
+
+// liveVoterCookie holds the random voter ID issued to voters who are not signed in
+const liveVoterCookie = "live_poll_voter"
+
+// SurveyLivePollController handles live polls: the presenter endpoints that open and close
+// questions, and the audience endpoints reached with the interaction code of the event
+type SurveyLivePollController struct {
+	livePollService services.SurveyLivePollService
+	logger          *zap.Logger
+}
+
+// NewSurveyLivePollController creates a new survey live poll controller
+func NewSurveyLivePollController(livePollService services.SurveyLivePollService, logger *zap.Logger) *SurveyLivePollController {
+	return &SurveyLivePollController{
+		livePollService: livePollService,
+		logger:          logger,
+	}
+}
+
+// SurveyLivePollRequest is the body of requests opening a live poll
+type SurveyLivePollRequest struct {
+	EventID    uuid.UUID `json:"eventId" binding:"required"`
+	QuestionID uuid.UUID `json:"questionId" binding:"required"`
+}
+
+// OpenLivePoll handles POST /api/v1/surveys/:surveyId/live-polls
+func (c *SurveyLivePollController) OpenLivePoll(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyLivePollRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	poll, err := c.livePollService.OpenPoll(ctx.Request.Context(), surveyID, &services.SurveyLivePollRequest{
+		EventID:    req.EventID,
+		QuestionID: req.QuestionID,
+		OpenedBy:   currentUserID(ctx),
+	})
+	if err != nil {
+		c.handleLivePollError(ctx, err, "Failed to open live poll")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": poll})
+}
+
+// GetSurveyLivePolls handles GET /api/v1/surveys/:surveyId/live-polls
+func (c *SurveyLivePollController) GetSurveyLivePolls(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	polls, err := c.livePollService.GetSurveyPolls(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.handleLivePollError(ctx, err, "Failed to get live polls")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": polls})
+}
+
+// CloseLivePoll handles POST /api/v1/surveys/live-polls/:pollId/close
+func (c *SurveyLivePollController) CloseLivePoll(ctx *gin.Context) {
+	pollID, err := uuid.Parse(ctx.Param("pollId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid live poll ID"})
+		return
+	}
+
+	poll, err := c.livePollService.ClosePoll(ctx.Request.Context(), pollID)
+	if err != nil {
+		c.handleLivePollError(ctx, err, "Failed to close live poll")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": poll})
+}
+
+// GetLivePollResults handles GET /api/v1/surveys/live-polls/:pollId/results
+func (c *SurveyLivePollController) GetLivePollResults(ctx *gin.Context) {
+	pollID, err := uuid.Parse(ctx.Param("pollId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid live poll ID"})
+		return
+	}
+
+	results, err := c.livePollService.GetResults(ctx.Request.Context(), pollID)
+	if err != nil {
+		c.handleLivePollError(ctx, err, "Failed to get live poll results")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": results})
+}
+
+// GetCurrentLivePoll handles GET /api/v1/public/live-polls/:code
+func (c *SurveyLivePollController) GetCurrentLivePoll(ctx *gin.Context) {
+	poll, err := c.livePollService.GetCurrentPoll(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		c.handleLivePollError(ctx, err, "Failed to get live poll")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": poll})
+}
+
+// SubmitLivePollVote handles POST /api/v1/public/live-polls/:code/votes
+func (c *SurveyLivePollController) SubmitLivePollVote(ctx *gin.Context) {
+	var vote entities.SurveyLivePollVote
+	if err := ctx.ShouldBindJSON(&vote); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+	vote.VoterID = liveVoterID(ctx)
+
+	if err := c.livePollService.SubmitVote(ctx.Request.Context(), ctx.Param("code"), &vote); err != nil {
+		c.handleLivePollError(ctx, err, "Failed to submit live poll vote")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true})
+}
+
+// StreamLivePollResults handles GET /api/v1/public/live-polls/:code/stream. It sends a "results"
+// server-sent event with the results of the current poll of the event whenever they change, with
+// null results until the first poll is opened.
+func (c *SurveyLivePollController) StreamLivePollResults(ctx *gin.Context) {
+	code := ctx.Param("code")
+	if _, err := c.livePollService.GetCurrentResults(ctx.Request.Context(), code); err != nil && !errors.Is(err, services.ErrNoLivePoll) {
+		c.handleLivePollError(ctx, err, "Failed to get live poll results")
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(liveResultsInterval)
+	defer ticker.Stop()
+	var last []byte
+	send := func() bool {
+		results, err := c.livePollService.GetCurrentResults(ctx.Request.Context(), code)
+		if err != nil && !errors.Is(err, services.ErrNoLivePoll) {
+			c.logger.Warn("Failed to get live poll results", zap.String("code", code), zap.Error(err))
+			return true
+		}
+		data, err := json.Marshal(results)
+		if err != nil || (last != nil && string(data) == string(last)) {
+			return true
+		}
+		last = data
+		ctx.SSEvent("results", string(data))
+		return true
+	}
+
+	send()
+	ctx.Writer.Flush()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			return send()
+		}
+	})
+}
+
+// liveVoterID identifies the voter by the signed-in user, or else by the voter cookie, which is
+// issued on the first request without one
+func liveVoterID(ctx *gin.Context) string {
+	if userID := currentUserID(ctx); userID != nil {
+		return "user:" + userID.String()
+	}
+	if cookie, err := ctx.Cookie(liveVoterCookie); err == nil {
+		if voterID, err := uuid.Parse(cookie); err == nil {
+			return "cookie:" + voterID.String()
+		}
+	}
+
+	voterID := uuid.New()
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(liveVoterCookie, voterID.String(), int((30 * 24 * time.Hour).Seconds()), "/", "", ctx.Request.TLS != nil, true)
+	return "cookie:" + voterID.String()
+}
+
+// handleLivePollError maps survey live poll service errors to responses
+func (c *SurveyLivePollController) handleLivePollError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey, question, event or live poll not found"})
+	case errors.Is(err, services.ErrNoLivePoll):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrLivePollNotSupported), errors.Is(err, services.ErrInvalidLivePollVote):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyNotOpen), errors.Is(err, services.ErrLivePollClosed),
+		errors.Is(err, services.ErrLivePollAlreadyVoted):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyLogicController handles survey branching logic rules and navigation
+type SurveyLogicController struct {
+	logicService services.SurveyLogicService
+	logger       *zap.Logger
+}
+
+// NewSurveyLogicController creates a new survey logic controller
+func NewSurveyLogicController(logicService services.SurveyLogicService, logger *zap.Logger) *SurveyLogicController {
+	return &SurveyLogicController{
+		logicService: logicService,
+		logger:       logger,
+	}
+}
+
+// SurveyLogicRequest is the body of logic rule create and update requests
+type SurveyLogicRequest struct {
+	QuestionID uuid.UUID       `json:"questionId" binding:"required"`
+	LogicType  string          `json:"logicType" binding:"required"`
+	Conditions json.RawMessage `json:"conditions" binding:"required"`
+	Actions    json.RawMessage `json:"actions"`
+	IsActive   *bool           `json:"isActive"`
+}
+
+// SurveyAnswerInput is one answer of a (partial) survey response
+type SurveyAnswerInput struct {
+	QuestionID   uuid.UUID       `json:"questionId" binding:"required"`
+	AnswerText   string          `json:"answerText"`
+	AnswerNumber *float64        `json:"answerNumber"`
+	AnswerDate   *time.Time      `json:"answerDate"`
+	AnswerBool   *bool           `json:"answerBool"`
+	AnswerArray  []string        `json:"answerArray"`
+	AnswerJSON   json.RawMessage `json:"answerJson"`
+	IsSkipped    bool            `json:"isSkipped"`
+}
+
+// SurveyNavigationRequest carries the answers given so far
+type SurveyNavigationRequest struct {
+	Answers []SurveyAnswerInput `json:"answers" binding:"dive"`
+}
+
+// GetSurveyLogic handles GET /api/v1/surveys/:surveyId/logic
+func (c *SurveyLogicController) GetSurveyLogic(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	logic, err := c.logicService.GetSurveyLogic(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.logger.Error("Failed to get survey logic", zap.Error(err), zap.String("surveyId", surveyID.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey logic"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": logic})
+}
+
+// CreateSurveyLogic handles POST /api/v1/surveys/:surveyId/logic
+func (c *SurveyLogicController) CreateSurveyLogic(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyLogicRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	logic := &entities.SurveyLogic{ID: uuid.New(), SurveyID: surveyID, IsActive: true}
+	req.apply(logic)
+	if err := c.logicService.CreateLogic(ctx.Request.Context(), logic); err != nil {
+		c.handleLogicError(ctx, err, "Failed to create survey logic")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": logic})
+}
+
+// UpdateSurveyLogic handles PUT /api/v1/surveys/logic/:logicId
+func (c *SurveyLogicController) UpdateSurveyLogic(ctx *gin.Context) {
+	logicID, err := uuid.Parse(ctx.Param("logicId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid logic ID"})
+		return
+	}
+
+	var req SurveyLogicRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	logic, err := c.logicService.GetLogic(ctx.Request.Context(), logicID)
+	if err != nil {
+		c.handleLogicError(ctx, err, "Failed to get survey logic")
+		return
+	}
+	req.apply(logic)
+	if err := c.logicService.UpdateLogic(ctx.Request.Context(), logic); err != nil {
+		c.handleLogicError(ctx, err, "Failed to update survey logic")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": logic})
+}
+
+// DeleteSurveyLogic handles DELETE /api/v1/surveys/logic/:logicId
+func (c *SurveyLogicController) DeleteSurveyLogic(ctx *gin.Context) {
+	logicID, err := uuid.Parse(ctx.Param("logicId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid logic ID"})
+		return
+	}
+
+	if err := c.logicService.DeleteLogic(ctx.Request.Context(), logicID); err != nil {
+		c.handleLogicError(ctx, err, "Failed to delete survey logic")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Survey logic deleted successfully"})
+}
+
+// GetSurveyNavigation handles POST /api/v1/public/surveys/:id/navigation. Given the answers so far
+// it returns the visible questions and the next question and page to show.
+func (c *SurveyLogicController) GetSurveyNavigation(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyNavigationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	navigation, err := c.logicService.Evaluate(ctx.Request.Context(), surveyID, surveyAnswersFromInput(req.Answers))
+	if err != nil {
+		c.handleLogicError(ctx, err, "Failed to evaluate survey logic")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyNavigationResponse(navigation)})
+}
+
+// handleLogicError maps logic service errors to responses
+func (c *SurveyLogicController) handleLogicError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey logic not found"})
+	case errors.Is(err, entities.ErrInvalidLogicType),
+		errors.Is(err, entities.ErrInvalidLogicCondition),
+		errors.Is(err, entities.ErrInvalidLogicAction):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// apply copies the request onto a logic rule
+func (r *SurveyLogicRequest) apply(logic *entities.SurveyLogic) {
+	logic.QuestionID = r.QuestionID
+	logic.LogicType = r.LogicType
+	logic.Conditions = string(r.Conditions)
+	logic.Actions = string(r.Actions)
+	if len(r.Actions) == 0 {
+		logic.Actions = "{}"
+	}
+	if r.IsActive != nil {
+		logic.IsActive = *r.IsActive
+	}
+}
+
+// surveyAnswersFromInput converts submitted answers to entities
+func surveyAnswersFromInput(inputs []SurveyAnswerInput) []entities.SurveyAnswer {
+	answers := make([]entities.SurveyAnswer, 0, len(inputs))
+	for _, input := range inputs {
+		answer := entities.SurveyAnswer{
+			QuestionID:   input.QuestionID,
+			AnswerText:   input.AnswerText,
+			AnswerNumber: input.AnswerNumber,
+			AnswerDate:   input.AnswerDate,
+			AnswerBool:   input.AnswerBool,
+			AnswerArray:  pq.StringArray(input.AnswerArray),
+			IsSkipped:    input.IsSkipped,
+		}
+		if len(input.AnswerJSON) > 0 && string(input.AnswerJSON) != "null" {
+			answer.AnswerJSON = string(input.AnswerJSON)
+		}
+		answers = append(answers, answer)
+	}
+	return answers
+}
+
+// newSurveyNavigationResponse converts a survey navigation to JSON
+func newSurveyNavigationResponse(navigation *entities.SurveyNavigation) gin.H {
+	return gin.H{
+		"visibleQuestionIds": navigation.VisibleQuestionIDs,
+		"hiddenQuestionIds":  navigation.HiddenQuestionIDs,
+		"nextQuestionId":     navigation.NextQuestionID,
+		"nextPage":           navigation.NextPage,
+		"pages":              navigation.Pages,
+		"ended":              navigation.Ended,
+		"endMessage":         navigation.EndMessage,
+		"complete":           navigation.Complete,
+	}
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyNotificationController handles survey response notifications and their delivery log
+type SurveyNotificationController struct {
+	notificationService services.SurveyNotificationService
+	logger              *zap.Logger
+}
+
+// NewSurveyNotificationController creates a new survey notification controller
+func NewSurveyNotificationController(notificationService services.SurveyNotificationService, logger *zap.Logger) *SurveyNotificationController {
+	return &SurveyNotificationController{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// SurveyNotificationRequest is the body of notification create and update requests
+type SurveyNotificationRequest struct {
+	EventType  string          `json:"eventType" binding:"required"`
+	Channel    string          `json:"channel"`
+	Recipients []string        `json:"recipients" binding:"required"`
+	Conditions json.RawMessage `json:"conditions"`
+	Subject    string          `json:"subject"`
+	Message    string          `json:"message"`
+	IsActive   *bool           `json:"isActive"`
+}
+
+// GetSurveyNotifications handles GET /api/v1/surveys/:surveyId/notifications
+func (c *SurveyNotificationController) GetSurveyNotifications(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	notifications, err := c.notificationService.GetSurveyNotifications(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.logger.Error("Failed to get survey notifications", zap.Error(err), zap.String("surveyId", surveyID.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey notifications"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(notifications))
+	for i := range notifications {
+		data = append(data, newSurveyNotificationResponse(&notifications[i]))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// CreateSurveyNotification handles POST /api/v1/surveys/:surveyId/notifications
+func (c *SurveyNotificationController) CreateSurveyNotification(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyNotificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	notification := &entities.SurveyNotification{
+		ID:       uuid.New(),
+		SurveyID: surveyID,
+		Channel:  entities.SurveyNotificationChannelEmail,
+		IsActive: true,
+	}
+	req.apply(notification)
+	if err := c.notificationService.CreateNotification(ctx.Request.Context(), notification); err != nil {
+		c.handleNotificationError(ctx, err, "Failed to create survey notification")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": newSurveyNotificationResponse(notification)})
+}
+
+// UpdateSurveyNotification handles PUT /api/v1/surveys/notifications/:notificationId
+func (c *SurveyNotificationController) UpdateSurveyNotification(ctx *gin.Context) {
+	notificationID, err := uuid.Parse(ctx.Param("notificationId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid notification ID"})
+		return
+	}
+
+	var req SurveyNotificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	notification, err := c.notificationService.GetNotification(ctx.Request.Context(), notificationID)
+	if err != nil {
+		c.handleNotificationError(ctx, err, "Failed to get survey notification")
+		return
+	}
+	req.apply(notification)
+	if err := c.notificationService.UpdateNotification(ctx.Request.Context(), notification); err != nil {
+		c.handleNotificationError(ctx, err, "Failed to update survey notification")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyNotificationResponse(notification)})
+}
+
+// DeleteSurveyNotification handles DELETE /api/v1/surveys/notifications/:notificationId
+func (c *SurveyNotificationController) DeleteSurveyNotification(ctx *gin.Context) {
+	notificationID, err := uuid.Parse(ctx.Param("notificationId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid notification ID"})
+		return
+	}
+
+	if err := c.notificationService.DeleteNotification(ctx.Request.Context(), notificationID); err != nil {
+		c.handleNotificationError(ctx, err, "Failed to delete survey notification")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Survey notification deleted successfully"})
+}
+
+// GetSurveyNotificationDeliveries handles GET /api/v1/surveys/notifications/:notificationId/deliveries
+func (c *SurveyNotificationController) GetSurveyNotificationDeliveries(ctx *gin.Context) {
+	notificationID, err := uuid.Parse(ctx.Param("notificationId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid notification ID"})
+		return
+	}
+
+	deliveries, err := c.notificationService.GetDeliveries(ctx.Request.Context(), notificationID)
+	if err != nil {
+		c.handleNotificationError(ctx, err, "Failed to get survey notification deliveries")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": deliveries})
+}
+
+// handleNotificationError maps notification service errors to responses
+func (c *SurveyNotificationController) handleNotificationError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey notification not found"})
+	case errors.Is(err, entities.ErrInvalidNotification):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// apply copies the request onto a notification
+func (r *SurveyNotificationRequest) apply(notification *entities.SurveyNotification) {
+	notification.EventType = r.EventType
+	if r.Channel != "" {
+		notification.Channel = r.Channel
+	}
+	notification.Recipients = r.Recipients
+	notification.Conditions = "{}"
+	if len(r.Conditions) > 0 && string(r.Conditions) != "null" {
+		notification.Conditions = string(r.Conditions)
+	}
+	notification.Subject = r.Subject
+	notification.Message = r.Message
+	if r.IsActive != nil {
+		notification.IsActive = *r.IsActive
+	}
+}
+
+// newSurveyNotificationResponse converts a notification to JSON
+func newSurveyNotificationResponse(notification *entities.SurveyNotification) gin.H {
+	return gin.H{
+		"id":         notification.ID,
+		"surveyId":   notification.SurveyID,
+		"eventType":  notification.EventType,
+		"channel":    notification.Channel,
+		"recipients": notification.Recipients,
+		"conditions": json.RawMessage(notification.Conditions),
+		"subject":    notification.Subject,
+		"message":    notification.Message,
+		"isActive":   notification.IsActive,
+		"lastSent":   notification.LastSent,
+		"sendCount":  notification.SendCount,
+		"createdAt":  notification.CreatedAt,
+		"updatedAt":  notification.UpdatedAt,
+	}
+}
@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	appServices "github.com/zenteam/nextevent-go/internal/application/services"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/interfaces/dto"
+	"go.uber.org/zap"
+)
+
+// choiceSeparator separates the choices of a question, e.g. ||Choice1||Choice2
+const choiceSeparator = "||"
+
+// SurveyPipingController handles answer piping rules between survey questions
+type SurveyPipingController struct {
+	pipingService services.SurveyPipingService
+	surveyService *appServices.SurveyService
+	logger        *zap.Logger
+}
+
+// NewSurveyPipingController creates a new survey piping controller
+func NewSurveyPipingController(pipingService services.SurveyPipingService, surveyService *appServices.SurveyService, logger *zap.Logger) *SurveyPipingController {
+	return &SurveyPipingController{
+		pipingService: pipingService,
+		surveyService: surveyService,
+		logger:        logger,
+	}
+}
+
+// SurveyPipingRequest is the body of piping rule create and update requests
+type SurveyPipingRequest struct {
+	SourceQuestionID uuid.UUID       `json:"sourceQuestionId" binding:"required"`
+	TargetQuestionID uuid.UUID       `json:"targetQuestionId" binding:"required"`
+	PipeType         string          `json:"pipeType" binding:"required"`
+	PipeRule         json.RawMessage `json:"pipeRule"`
+	IsActive         *bool           `json:"isActive"`
+}
+
+// GetSurveyPiping handles GET /api/v1/surveys/:surveyId/piping
+func (c *SurveyPipingController) GetSurveyPiping(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	piping, err := c.pipingService.GetSurveyPiping(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.logger.Error("Failed to get survey piping", zap.Error(err), zap.String("surveyId", surveyID.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey piping"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": piping})
+}
+
+// CreateSurveyPiping handles POST /api/v1/surveys/:surveyId/piping
+func (c *SurveyPipingController) CreateSurveyPiping(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyPipingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	piping := &entities.SurveyPiping{ID: uuid.New(), SurveyID: surveyID, IsActive: true}
+	req.apply(piping)
+	if err := c.checkQuestions(ctx.Request.Context(), piping); err != nil {
+		c.handlePipingError(ctx, err, "Failed to create survey piping")
+		return
+	}
+	if err := c.pipingService.CreatePiping(ctx.Request.Context(), piping); err != nil {
+		c.handlePipingError(ctx, err, "Failed to create survey piping")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": piping})
+}
+
+// UpdateSurveyPiping handles PUT /api/v1/surveys/piping/:pipingId
+func (c *SurveyPipingController) UpdateSurveyPiping(ctx *gin.Context) {
+	pipingID, err := uuid.Parse(ctx.Param("pipingId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid piping ID"})
+		return
+	}
+
+	var req SurveyPipingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	piping, err := c.pipingService.GetPiping(ctx.Request.Context(), pipingID)
+	if err != nil {
+		c.handlePipingError(ctx, err, "Failed to get survey piping")
+		return
+	}
+	req.apply(piping)
+	if err := c.checkQuestions(ctx.Request.Context(), piping); err != nil {
+		c.handlePipingError(ctx, err, "Failed to update survey piping")
+		return
+	}
+	if err := c.pipingService.UpdatePiping(ctx.Request.Context(), piping); err != nil {
+		c.handlePipingError(ctx, err, "Failed to update survey piping")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": piping})
+}
+
+// DeleteSurveyPiping handles DELETE /api/v1/surveys/piping/:pipingId
+func (c *SurveyPipingController) DeleteSurveyPiping(ctx *gin.Context) {
+	pipingID, err := uuid.Parse(ctx.Param("pipingId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid piping ID"})
+		return
+	}
+
+	if err := c.pipingService.DeletePiping(ctx.Request.Context(), pipingID); err != nil {
+		c.handlePipingError(ctx, err, "Failed to delete survey piping")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Survey piping deleted successfully"})
+}
+
+// checkQuestions checks that both questions of a piping rule belong to its survey and that the
+// source is asked before the target
+func (c *SurveyPipingController) checkQuestions(ctx context.Context, piping *entities.SurveyPiping) error {
+	questions, err := c.surveyService.GetQuestionsBySurvey(ctx, piping.SurveyID)
+	if err != nil {
+		return err
+	}
+	order := make(map[uuid.UUID]int, len(questions))
+	for _, question := range questions {
+		order[question.ID] = question.OrderNumber
+	}
+
+	sourceOrder, ok := order[piping.SourceQuestionID]
+	if !ok {
+		return fmt.Errorf("%w: source question %s is not part of the survey", entities.ErrInvalidPipeRule, piping.SourceQuestionID)
+	}
+	targetOrder, ok := order[piping.TargetQuestionID]
+	if !ok {
+		return fmt.Errorf("%w: target question %s is not part of the survey", entities.ErrInvalidPipeRule, piping.TargetQuestionID)
+	}
+	if sourceOrder >= targetOrder {
+		return fmt.Errorf("%w: the source question must come before the target question", entities.ErrInvalidPipeRule)
+	}
+	return nil
+}
+
+// handlePipingError maps piping service errors to responses
+func (c *SurveyPipingController) handlePipingError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey piping not found"})
+	case errors.Is(err, entities.ErrInvalidPipeType), errors.Is(err, entities.ErrInvalidPipeRule):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// apply copies the request onto a piping rule
+func (r *SurveyPipingRequest) apply(piping *entities.SurveyPiping) {
+	piping.SourceQuestionID = r.SourceQuestionID
+	piping.TargetQuestionID = r.TargetQuestionID
+	piping.PipeType = r.PipeType
+	piping.PipeRule = string(r.PipeRule)
+	if len(r.PipeRule) == 0 || string(r.PipeRule) == "null" {
+		piping.PipeRule = "{}"
+	}
+	if r.IsActive != nil {
+		piping.IsActive = *r.IsActive
+	}
+}
+
+// parsePipingAnswers reads the answers given so far from the answers query parameter, a JSON object
+// of question ID to an answer or a list of selections
+func parsePipingAnswers(raw string) (map[uuid.UUID][]string, error) {
+	answers := make(map[uuid.UUID][]string)
+	if strings.TrimSpace(raw) == "" {
+		return answers, nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, fmt.Errorf("answers must be a JSON object: %w", err)
+	}
+	for key, value := range values {
+		questionID, err := uuid.Parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid question ID %q", key)
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			list = []interface{}{value}
+		}
+		for _, item := range list {
+			switch v := item.(type) {
+			case nil:
+			case string:
+				if v != "" {
+					answers[questionID] = append(answers[questionID], v)
+				}
+			case float64:
+				answers[questionID] = append(answers[questionID], strconv.FormatFloat(v, 'f', -1, 64))
+			case bool:
+				answers[questionID] = append(answers[questionID], strconv.FormatBool(v))
+			default:
+				return nil, fmt.Errorf("unsupported answer for question %s", questionID)
+			}
+		}
+	}
+	return answers, nil
+}
+
+// pipeQuestionResponses substitutes the answers given so far into the question titles and choices
+func pipeQuestionResponses(ctx context.Context, pipingService services.SurveyPipingService, surveyID uuid.UUID, questions []dto.QuestionResponse, answers map[uuid.UUID][]string) ([]dto.QuestionResponse, error) {
+	pipingQuestions := make([]entities.PipingQuestion, len(questions))
+	for i, question := range questions {
+		pipingQuestions[i] = entities.PipingQuestion{
+			ID:      question.ID,
+			Order:   question.OrderNumber,
+			Text:    question.QuestionTitle,
+			Options: splitChoices(question.Choices),
+		}
+	}
+
+	piped, err := pipingService.PipeQuestions(ctx, surveyID, pipingQuestions, answers)
+	if err != nil {
+		return nil, err
+	}
+	pipedByID := make(map[uuid.UUID]entities.PipingQuestion, len(piped))
+	for _, question := range piped {
+		pipedByID[question.ID] = question
+	}
+
+	result := make([]dto.QuestionResponse, len(questions))
+	for i, question := range questions {
+		result[i] = question
+		pipedQuestion, ok := pipedByID[question.ID]
+		if !ok {
+			continue
+		}
+		result[i].QuestionTitle = pipedQuestion.Text
+		if question.Choices != nil || len(pipedQuestion.Options) > 0 {
+			choices := joinChoices(pipedQuestion.Options)
+			result[i].Choices = &choices
+		}
+	}
+	return result, nil
+}
+
+// splitChoices splits ||Choice1||Choice2 into its choices
+func splitChoices(choices *string) []string {
+	if choices == nil {
+		return nil
+	}
+	var options []string
+	for _, choice := range strings.Split(*choices, choiceSeparator) {
+		if choice != "" {
+			options = append(options, choice)
+		}
+	}
+	return options
+}
+
+// joinChoices joins choices back into the ||Choice1||Choice2 format
+func joinChoices(options []string) string {
+	if len(options) == 0 {
+		return ""
+	}
+	return choiceSeparator + strings.Join(options, choiceSeparator)
+}
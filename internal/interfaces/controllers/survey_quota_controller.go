@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyQuotaController handles survey response quotas
+type SurveyQuotaController struct {
+	quotaService services.SurveyQuotaService
+	logger       *zap.Logger
+}
+
+// NewSurveyQuotaController creates a new survey quota controller
+func NewSurveyQuotaController(quotaService services.SurveyQuotaService, logger *zap.Logger) *SurveyQuotaController {
+	return &SurveyQuotaController{
+		quotaService: quotaService,
+		logger:       logger,
+	}
+}
+
+// SurveyQuotaRequest is the body of quota create and update requests
+type SurveyQuotaRequest struct {
+	Name             string          `json:"name" binding:"required"`
+	Description      string          `json:"description"`
+	Conditions       json.RawMessage `json:"conditions" binding:"required"`
+	MaxResponses     int             `json:"maxResponses" binding:"required"`
+	ScreenOutMessage string          `json:"screenOutMessage"`
+	IsActive         *bool           `json:"isActive"`
+}
+
+// GetSurveyQuotas handles GET /api/v1/surveys/:surveyId/quotas
+func (c *SurveyQuotaController) GetSurveyQuotas(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	quotas, err := c.quotaService.GetSurveyQuotas(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.logger.Error("Failed to get survey quotas", zap.Error(err), zap.String("surveyId", surveyID.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey quotas"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(quotas))
+	for i := range quotas {
+		data = append(data, newSurveyQuotaResponse(&quotas[i]))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// CreateSurveyQuota handles POST /api/v1/surveys/:surveyId/quotas
+func (c *SurveyQuotaController) CreateSurveyQuota(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyQuotaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	quota := &entities.SurveyQuota{ID: uuid.New(), SurveyID: surveyID, IsActive: true}
+	req.apply(quota)
+	if err := c.quotaService.CreateQuota(ctx.Request.Context(), quota); err != nil {
+		c.handleQuotaError(ctx, err, "Failed to create survey quota")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": newSurveyQuotaResponse(quota)})
+}
+
+// UpdateSurveyQuota handles PUT /api/v1/surveys/quotas/:quotaId
+func (c *SurveyQuotaController) UpdateSurveyQuota(ctx *gin.Context) {
+	quotaID, err := uuid.Parse(ctx.Param("quotaId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid quota ID"})
+		return
+	}
+
+	var req SurveyQuotaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	quota, err := c.quotaService.GetQuota(ctx.Request.Context(), quotaID)
+	if err != nil {
+		c.handleQuotaError(ctx, err, "Failed to get survey quota")
+		return
+	}
+	req.apply(quota)
+	if err := c.quotaService.UpdateQuota(ctx.Request.Context(), quota); err != nil {
+		c.handleQuotaError(ctx, err, "Failed to update survey quota")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyQuotaResponse(quota)})
+}
+
+// DeleteSurveyQuota handles DELETE /api/v1/surveys/quotas/:quotaId
+func (c *SurveyQuotaController) DeleteSurveyQuota(ctx *gin.Context) {
+	quotaID, err := uuid.Parse(ctx.Param("quotaId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid quota ID"})
+		return
+	}
+
+	if err := c.quotaService.DeleteQuota(ctx.Request.Context(), quotaID); err != nil {
+		c.handleQuotaError(ctx, err, "Failed to delete survey quota")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Survey quota deleted successfully"})
+}
+
+// handleQuotaError maps quota service errors to responses
+func (c *SurveyQuotaController) handleQuotaError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey quota not found"})
+	case errors.Is(err, entities.ErrInvalidQuota):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// apply copies the request onto a quota
+func (r *SurveyQuotaRequest) apply(quota *entities.SurveyQuota) {
+	quota.Name = r.Name
+	quota.Description = r.Description
+	quota.Conditions = string(r.Conditions)
+	quota.MaxResponses = r.MaxResponses
+	quota.ScreenOutMessage = r.ScreenOutMessage
+	if r.IsActive != nil {
+		quota.IsActive = *r.IsActive
+	}
+}
+
+// newSurveyQuotaResponse converts a quota to JSON, including whether it is full
+func newSurveyQuotaResponse(quota *entities.SurveyQuota) gin.H {
+	return gin.H{
+		"id":               quota.ID,
+		"surveyId":         quota.SurveyID,
+		"name":             quota.Name,
+		"description":      quota.Description,
+		"conditions":       json.RawMessage(quota.Conditions),
+		"maxResponses":     quota.MaxResponses,
+		"currentCount":     quota.CurrentCount,
+		"isFull":           quota.IsQuotaFull(),
+		"isActive":         quota.IsActive,
+		"screenOutMessage": quota.ScreenOutMessage,
+		"createdAt":        quota.CreatedAt,
+		"updatedAt":        quota.UpdatedAt,
+	}
+}
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyResponseController handles answering surveys: starting, resuming, saving and submitting responses
+type SurveyResponseController struct {
+	responseService services.SurveyResponseService
+	logger          *zap.Logger
+}
+
+// NewSurveyResponseController creates a new survey response controller
+func NewSurveyResponseController(responseService services.SurveyResponseService, logger *zap.Logger) *SurveyResponseController {
+	return &SurveyResponseController{
+		responseService: responseService,
+		logger:          logger,
+	}
+}
+
+// StartSurveyResponseRequest is the body of a start request
+type StartSurveyResponseRequest struct {
+	SessionID       string `json:"sessionId"`       // Client session of anonymous respondents; X-Session-ID is used when empty
+	InvitationToken string `json:"invitationToken"` // Token of the invitation link; the invitation query parameter is used when empty
+	CollectorID     string `json:"collectorId"`     // Collector the survey was opened from; the collector query parameter is used when empty
+}
+
+// SaveSurveyProgressRequest is the body of a save progress request
+type SaveSurveyProgressRequest struct {
+	Answers     []SurveyAnswerInput `json:"answers" binding:"dive"`
+	CurrentPage int                 `json:"currentPage"`
+}
+
+// SubmitSurveyResponseRequest is the body of a submit request
+type SubmitSurveyResponseRequest struct {
+	Answers []SurveyAnswerInput `json:"answers" binding:"dive"`
+}
+
+// StartSurveyResponse handles POST /api/v1/public/surveys/:id/responses. It resumes the
+// respondent's response in progress if there is one.
+func (c *SurveyResponseController) StartSurveyResponse(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req StartSurveyResponseRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+	if req.SessionID == "" {
+		req.SessionID = ctx.GetHeader("X-Session-ID")
+	}
+	if req.InvitationToken == "" {
+		req.InvitationToken = ctx.Query("invitation")
+	}
+	if req.CollectorID == "" {
+		req.CollectorID = ctx.Query("collector")
+	}
+	var collectorID *uuid.UUID
+	if req.CollectorID != "" {
+		id, err := uuid.Parse(req.CollectorID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid collector ID"})
+			return
+		}
+		collectorID = &id
+	}
+
+	respondent := services.SurveyRespondent{
+		RespondentID:    currentUserID(ctx),
+		SessionID:       req.SessionID,
+		IPAddress:       ctx.ClientIP(),
+		UserAgent:       ctx.GetHeader("User-Agent"),
+		InvitationToken: req.InvitationToken,
+		CollectorID:     collectorID,
+	}
+	progress, err := c.responseService.StartResponse(ctx.Request.Context(), surveyID, respondent)
+	if err != nil {
+		c.handleResponseError(ctx, err, "Failed to start survey response")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyProgressResponse(progress)})
+}
+
+// GetSurveySession handles GET /api/v1/public/survey-sessions/:sessionId
+func (c *SurveyResponseController) GetSurveySession(ctx *gin.Context) {
+	progress, err := c.responseService.GetProgress(ctx.Request.Context(), ctx.Param("sessionId"))
+	if err != nil {
+		c.handleResponseError(ctx, err, "Failed to get survey session")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyProgressResponse(progress)})
+}
+
+// SaveSurveyProgress handles PUT /api/v1/public/survey-sessions/:sessionId
+func (c *SurveyResponseController) SaveSurveyProgress(ctx *gin.Context) {
+	var req SaveSurveyProgressRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	progress, err := c.responseService.SaveProgress(ctx.Request.Context(), ctx.Param("sessionId"), surveyAnswersFromInput(req.Answers), req.CurrentPage)
+	if err != nil {
+		c.handleResponseError(ctx, err, "Failed to save survey progress")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyProgressResponse(progress)})
+}
+
+// SubmitSurveyResponse handles POST /api/v1/public/survey-sessions/:sessionId/submit
+func (c *SurveyResponseController) SubmitSurveyResponse(ctx *gin.Context) {
+	var req SubmitSurveyResponseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	progress, err := c.responseService.SubmitResponse(ctx.Request.Context(), ctx.Param("sessionId"), surveyAnswersFromInput(req.Answers))
+	if err != nil {
+		c.handleResponseError(ctx, err, "Failed to submit survey response")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyProgressResponse(progress)})
+}
+
+// UploadSurveyFile handles POST /api/v1/public/survey-sessions/:sessionId/files. The form has the
+// questionId of a file question and the file; the returned file goes in the answer's answerJson.
+func (c *SurveyResponseController) UploadSurveyFile(ctx *gin.Context) {
+	questionID, err := uuid.Parse(ctx.PostForm("questionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid question ID"})
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "A file is required"})
+		return
+	}
+	if fileHeader.Size > entities.SurveyFileMaxSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "error": "File is too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	uploaded, err := c.responseService.UploadFile(ctx.Request.Context(), ctx.Param("sessionId"), questionID, &services.SurveyFileUpload{
+		Name:        fileHeader.Filename,
+		ContentType: fileHeader.Header.Get("Content-Type"),
+		Size:        fileHeader.Size,
+		Content:     file,
+	})
+	if err != nil {
+		c.handleResponseError(ctx, err, "Failed to upload survey file")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": uploaded})
+}
+
+// handleResponseError maps survey response service errors to responses
+func (c *SurveyResponseController) handleResponseError(ctx *gin.Context, err error, message string) {
+	var validationErr *services.SurveyResponseValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		errs := make(map[string]string, len(validationErr.Errors))
+		for questionID, message := range validationErr.Errors {
+			errs[questionID.String()] = message
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": "Invalid answers", "errors": errs})
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey or session not found"})
+	case errors.Is(err, entities.ErrInvalidSurveyFile):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyLoginRequired):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyTimeLimitExceeded),
+		errors.Is(err, services.ErrSurveyInvitationExpired):
+		ctx.JSON(http.StatusGone, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyNotOpen),
+		errors.Is(err, services.ErrSurveyClosed),
+		errors.Is(err, services.ErrSurveyCollectorClosed):
+		ctx.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyFull),
+		errors.Is(err, services.ErrSurveyAlreadyResponded),
+		errors.Is(err, services.ErrSurveyResponseSubmitted),
+		errors.Is(err, services.ErrSurveyInvitationUsed):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// currentUserID returns the ID of the authenticated user, if any
+func currentUserID(ctx *gin.Context) *uuid.UUID {
+	value, exists := ctx.Get("user_id")
+	if !exists {
+		return nil
+	}
+	userID, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// newSurveyProgressResponse converts survey progress to JSON
+func newSurveyProgressResponse(progress *services.SurveyProgress) gin.H {
+	return gin.H{
+		"sessionId":        progress.Session.SessionID,
+		"responseId":       progress.Response.ID,
+		"status":           progress.Response.Status,
+		"startedAt":        progress.Response.StartedAt,
+		"submittedAt":      progress.Response.SubmittedAt,
+		"expiresAt":        progress.ExpiresAt,
+		"currentPage":      progress.Session.CurrentPage,
+		"totalPages":       progress.Session.TotalPages,
+		"progress":         progress.Session.Progress,
+		"answers":          progress.Answers,
+		"navigation":       newSurveyNavigationResponse(progress.Navigation),
+		"screenOutMessage": progress.ScreenOutMessage,
+	}
+}
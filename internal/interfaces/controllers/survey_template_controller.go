@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyTemplateController handles survey templates and creating surveys from them
+type SurveyTemplateController struct {
+	templateService services.SurveyTemplateService
+	logger          *zap.Logger
+}
+
+// NewSurveyTemplateController creates a new survey template controller
+func NewSurveyTemplateController(templateService services.SurveyTemplateService, logger *zap.Logger) *SurveyTemplateController {
+	return &SurveyTemplateController{
+		templateService: templateService,
+		logger:          logger,
+	}
+}
+
+// SurveyTemplateRequest is the body of template create and update requests
+type SurveyTemplateRequest struct {
+	Name        string                          `json:"name" binding:"required"`
+	Description string                          `json:"description"`
+	Category    string                          `json:"category"`
+	Tags        []string                        `json:"tags"`
+	IsPublic    bool                            `json:"isPublic"`
+	Questions   []SurveyTemplateQuestionRequest `json:"questions" binding:"required,dive"`
+	Settings    *entities.SurveyTemplateData    `json:"settings"` // Survey settings and logic; logic refers to the question IDs
+}
+
+// SurveyTemplateQuestionRequest is one question of a template
+type SurveyTemplateQuestionRequest struct {
+	ID           *uuid.UUID            `json:"id"` // Keep IDs stable across updates so the template logic can refer to them
+	QuestionText string                `json:"questionText" binding:"required"`
+	QuestionType entities.QuestionType `json:"questionType" binding:"required"`
+	IsRequired   bool                  `json:"isRequired"`
+	Order        int                   `json:"order"`
+	Options      []string              `json:"options"`
+	Validation   json.RawMessage       `json:"validation"`
+	Metadata     json.RawMessage       `json:"metadata"`
+}
+
+// SurveyFromTemplateRequest is the body of requests creating a survey from a template
+type SurveyFromTemplateRequest struct {
+	Title string `json:"title"` // The template's survey title by default
+}
+
+// SaveSurveyAsTemplateRequest is the body of requests saving a survey as a template
+type SaveSurveyAsTemplateRequest struct {
+	Name        string   `json:"name"` // The survey title by default
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	IsPublic    bool     `json:"isPublic"`
+}
+
+// GetSurveyTemplates handles GET /api/v1/survey-templates?category=
+func (c *SurveyTemplateController) GetSurveyTemplates(ctx *gin.Context) {
+	templates, err := c.templateService.GetTemplates(ctx.Request.Context(), ctx.Query("category"))
+	if err != nil {
+		c.logger.Error("Failed to get survey templates", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get survey templates"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(templates))
+	for i := range templates {
+		data = append(data, newSurveyTemplateResponse(&templates[i]))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// GetSurveyTemplate handles GET /api/v1/survey-templates/:templateId
+func (c *SurveyTemplateController) GetSurveyTemplate(ctx *gin.Context) {
+	templateID, err := uuid.Parse(ctx.Param("templateId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid template ID"})
+		return
+	}
+
+	template, err := c.templateService.GetTemplate(ctx.Request.Context(), templateID)
+	if err != nil {
+		c.handleTemplateError(ctx, err, "Failed to get survey template")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyTemplateResponse(template)})
+}
+
+// CreateSurveyTemplate handles POST /api/v1/survey-templates
+func (c *SurveyTemplateController) CreateSurveyTemplate(ctx *gin.Context) {
+	var req SurveyTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	template := &entities.SurveyTemplate{}
+	if userID := currentUserID(ctx); userID != nil {
+		template.CreatedBy = *userID
+	}
+	err := req.apply(template)
+	if err == nil {
+		err = c.templateService.CreateTemplate(ctx.Request.Context(), template)
+	}
+	if err != nil {
+		c.handleTemplateError(ctx, err, "Failed to create survey template")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": newSurveyTemplateResponse(template)})
+}
+
+// UpdateSurveyTemplate handles PUT /api/v1/survey-templates/:templateId
+func (c *SurveyTemplateController) UpdateSurveyTemplate(ctx *gin.Context) {
+	templateID, err := uuid.Parse(ctx.Param("templateId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid template ID"})
+		return
+	}
+
+	var req SurveyTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	template := &entities.SurveyTemplate{ID: templateID}
+	err = req.apply(template)
+	if err == nil {
+		err = c.templateService.UpdateTemplate(ctx.Request.Context(), template)
+	}
+	if err != nil {
+		c.handleTemplateError(ctx, err, "Failed to update survey template")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": newSurveyTemplateResponse(template)})
+}
+
+// DeleteSurveyTemplate handles DELETE /api/v1/survey-templates/:templateId
+func (c *SurveyTemplateController) DeleteSurveyTemplate(ctx *gin.Context) {
+	templateID, err := uuid.Parse(ctx.Param("templateId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid template ID"})
+		return
+	}
+
+	if err := c.templateService.DeleteTemplate(ctx.Request.Context(), templateID); err != nil {
+		c.handleTemplateError(ctx, err, "Failed to delete survey template")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "message": "Survey template deleted successfully"})
+}
+
+// CreateSurveyFromTemplate handles POST /api/v1/survey-templates/:templateId/surveys
+func (c *SurveyTemplateController) CreateSurveyFromTemplate(ctx *gin.Context) {
+	templateID, err := uuid.Parse(ctx.Param("templateId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid template ID"})
+		return
+	}
+
+	var req SurveyFromTemplateRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	var createdBy uuid.UUID
+	if userID := currentUserID(ctx); userID != nil {
+		createdBy = *userID
+	}
+	survey, err := c.templateService.CreateSurvey(ctx.Request.Context(), templateID, req.Title, createdBy)
+	if err != nil {
+		c.handleTemplateError(ctx, err, "Failed to create survey from template")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": survey})
+}
+
+// SaveSurveyAsTemplate handles POST /api/v1/surveys/:surveyId/template
+func (c *SurveyTemplateController) SaveSurveyAsTemplate(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SaveSurveyAsTemplateRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	template := &entities.SurveyTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+		Category:    req.Category,
+		Tags:        pq.StringArray(req.Tags),
+		IsPublic:    req.IsPublic,
+	}
+	if userID := currentUserID(ctx); userID != nil {
+		template.CreatedBy = *userID
+	}
+	if err := c.templateService.SaveSurveyAsTemplate(ctx.Request.Context(), surveyID, template); err != nil {
+		c.handleTemplateError(ctx, err, "Failed to save survey as template")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": newSurveyTemplateResponse(template)})
+}
+
+// handleTemplateError maps template service errors to responses
+func (c *SurveyTemplateController) handleTemplateError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey or template not found"})
+	case errors.Is(err, entities.ErrInvalidTemplate),
+		errors.Is(err, entities.ErrSurveyTitleRequired),
+		errors.Is(err, entities.ErrSurveyTitleTooLong):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyTemplateBuiltIn):
+		ctx.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// apply copies the request onto a template
+func (r *SurveyTemplateRequest) apply(template *entities.SurveyTemplate) error {
+	template.Name = r.Name
+	template.Description = r.Description
+	template.Category = r.Category
+	template.Tags = pq.StringArray(r.Tags)
+	template.IsPublic = r.IsPublic
+
+	template.Questions = make([]entities.SurveyTemplateQuestion, 0, len(r.Questions))
+	for i, question := range r.Questions {
+		order := question.Order
+		if order <= 0 {
+			order = i + 1
+		}
+		templateQuestion := entities.SurveyTemplateQuestion{
+			QuestionText: question.QuestionText,
+			QuestionType: question.QuestionType,
+			IsRequired:   question.IsRequired,
+			Order:        order,
+			Options:      pq.StringArray(question.Options),
+			Validation:   string(question.Validation),
+			Metadata:     string(question.Metadata),
+		}
+		if question.ID != nil {
+			templateQuestion.ID = *question.ID
+		}
+		template.Questions = append(template.Questions, templateQuestion)
+	}
+
+	if r.Settings == nil {
+		return nil
+	}
+	return template.SetData(r.Settings)
+}
+
+// newSurveyTemplateResponse converts a template to JSON, with its settings decoded
+func newSurveyTemplateResponse(template *entities.SurveyTemplate) gin.H {
+	_, builtIn := entities.FindBuiltInSurveyTemplate(template.ID)
+	settings := json.RawMessage(template.TemplateData)
+	if len(settings) == 0 {
+		settings = nil
+	}
+	return gin.H{
+		"id":          template.ID,
+		"name":        template.Name,
+		"description": template.Description,
+		"category":    template.Category,
+		"tags":        template.Tags,
+		"isPublic":    template.IsPublic,
+		"isBuiltIn":   builtIn,
+		"usageCount":  template.UsageCount,
+		"rating":      template.Rating,
+		"createdBy":   template.CreatedBy,
+		"createdAt":   template.CreatedAt,
+		"updatedAt":   template.UpdatedAt,
+		"settings":    settings,
+		"questions":   template.Questions,
+	}
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// SurveyVersionController handles survey revisions and the versions they create
+type SurveyVersionController struct {
+	versionService services.SurveyVersionService
+	logger         *zap.Logger
+}
+
+// NewSurveyVersionController creates a new survey version controller
+func NewSurveyVersionController(versionService services.SurveyVersionService, logger *zap.Logger) *SurveyVersionController {
+	return &SurveyVersionController{
+		versionService: versionService,
+		logger:         logger,
+	}
+}
+
+// SurveyRevisionRequest is the body of survey revision requests. Questions are listed in their
+// new order; existing questions keep their ID, new ones omit it and omitted questions are removed.
+type SurveyRevisionRequest struct {
+	Title        string                          `json:"title" binding:"required"`
+	Description  string                          `json:"description"`
+	Instructions string                          `json:"instructions"`
+	Questions    []SurveyRevisionQuestionRequest `json:"questions"`
+	ChangeNote   string                          `json:"changeNote"`
+}
+
+// SurveyRevisionQuestionRequest is a question of a survey revision
+type SurveyRevisionQuestionRequest struct {
+	ID           *uuid.UUID            `json:"id"`
+	QuestionText string                `json:"questionText" binding:"required"`
+	QuestionType entities.QuestionType `json:"questionType" binding:"required"`
+	IsRequired   bool                  `json:"isRequired"`
+	Options      []string              `json:"options"`
+	Validation   json.RawMessage       `json:"validation"`
+	Metadata     json.RawMessage       `json:"metadata"`
+}
+
+// ReviseSurvey handles POST /api/v1/surveys/:surveyId/revisions
+func (c *SurveyVersionController) ReviseSurvey(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	var req SurveyRevisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	survey, err := c.versionService.ReviseSurvey(ctx.Request.Context(), surveyID, req.revision(currentUserID(ctx)))
+	if err != nil {
+		c.handleVersionError(ctx, err, "Failed to revise survey")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": survey})
+}
+
+// GetSurveyVersions handles GET /api/v1/surveys/:surveyId/versions
+func (c *SurveyVersionController) GetSurveyVersions(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+
+	versions, err := c.versionService.GetVersions(ctx.Request.Context(), surveyID)
+	if err != nil {
+		c.handleVersionError(ctx, err, "Failed to get survey versions")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": versions})
+}
+
+// GetSurveyVersion handles GET /api/v1/surveys/:surveyId/versions/:version
+func (c *SurveyVersionController) GetSurveyVersion(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey version"})
+		return
+	}
+
+	surveyVersion, err := c.versionService.GetVersion(ctx.Request.Context(), surveyID, version)
+	if err != nil {
+		c.handleVersionError(ctx, err, "Failed to get survey version")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": surveyVersion})
+}
+
+// DiffSurveyVersions handles GET /api/v1/surveys/:surveyId/versions/diff?from=1&to=2
+func (c *SurveyVersionController) DiffSurveyVersions(ctx *gin.Context) {
+	surveyID, err := uuid.Parse(ctx.Param("surveyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid survey ID"})
+		return
+	}
+	from, fromErr := strconv.Atoi(ctx.Query("from"))
+	to, toErr := strconv.Atoi(ctx.Query("to"))
+	if fromErr != nil || toErr != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "from and to must be survey versions"})
+		return
+	}
+
+	diff, err := c.versionService.DiffVersions(ctx.Request.Context(), surveyID, from, to)
+	if err != nil {
+		c.handleVersionError(ctx, err, "Failed to compare survey versions")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": diff})
+}
+
+// handleVersionError maps survey version service errors to responses
+func (c *SurveyVersionController) handleVersionError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Survey or version not found"})
+	case errors.Is(err, services.ErrInvalidSurveyRevision):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrSurveyNotRevisable):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
+
+// revision converts the request to a survey revision
+func (r *SurveyRevisionRequest) revision(editedBy *uuid.UUID) *services.SurveyRevision {
+	revision := &services.SurveyRevision{
+		Title:        r.Title,
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Questions:    make([]entities.SurveyQuestion, 0, len(r.Questions)),
+		ChangeNote:   r.ChangeNote,
+		EditedBy:     editedBy,
+	}
+	for _, question := range r.Questions {
+		revised := entities.SurveyQuestion{
+			QuestionText: question.QuestionText,
+			QuestionType: question.QuestionType,
+			IsRequired:   question.IsRequired,
+			Options:      pq.StringArray(question.Options),
+			Validation:   string(question.Validation),
+			Metadata:     string(question.Metadata),
+		}
+		if question.ID != nil {
+			revised.ID = *question.ID
+		}
+		revision.Questions = append(revision.Questions, revised)
+	}
+	return revision
+}
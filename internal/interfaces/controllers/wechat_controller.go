@@ -1,36 +1,72 @@
 package controllers
 
 import (
+	"encoding/xml"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/silenceper/wechat/v2/officialaccount/message"
 	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
 	"go.uber.org/zap"
 )
 
 // WeChatController handles WeChat webhook requests
 type WeChatController struct {
 	wechatService services.WeChatService
+	crypter       *wechat.MessageCrypter
 	logger        *zap.Logger
 }
 
 // NewWeChatController creates a new WeChat controller
-func NewWeChatController(wechatService services.WeChatService, logger *zap.Logger) *WeChatController {
+func NewWeChatController(wechatService services.WeChatService, crypter *wechat.MessageCrypter, logger *zap.Logger) *WeChatController {
 	return &WeChatController{
 		wechatService: wechatService,
+		crypter:       crypter,
 		logger:        logger,
 	}
 }
 
-// HandleWebhook handles WeChat webhook requests
+// HandleWebhook handles WeChat webhook requests in plain and safe (AES) mode
 func (w *WeChatController) HandleWebhook(c *gin.Context) {
 	startTime := time.Now()
-	
+
+	timestamp := c.Query("timestamp")
+	nonce := c.Query("nonce")
+	if !w.crypter.VerifySignature(c.Query("signature"), timestamp, nonce) {
+		w.logger.Warn("Rejected WeChat webhook with invalid signature",
+			zap.String("clientIP", c.ClientIP()))
+		c.String(http.StatusForbidden, "Invalid signature")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		w.logger.Error("Failed to read WeChat message", zap.Error(err))
+		c.String(http.StatusBadRequest, "Invalid message format")
+		return
+	}
+
+	encrypted := c.Query("encrypt_type") == "aes"
+	if encrypted {
+		body, err = w.crypter.DecryptMessage(body, c.Query("msg_signature"), timestamp, nonce)
+		if err != nil {
+			w.logger.Warn("Failed to decrypt WeChat message", zap.Error(err))
+			if errors.Is(err, wechat.ErrInvalidSignature) {
+				c.String(http.StatusForbidden, "Invalid signature")
+				return
+			}
+			c.String(http.StatusBadRequest, "Invalid message format")
+			return
+		}
+	}
+
 	// Parse the incoming message
 	var msg message.MixMessage
-	if err := c.ShouldBindXML(&msg); err != nil {
+	if err := xml.Unmarshal(body, &msg); err != nil {
 		w.logger.Error("Failed to parse WeChat message", zap.Error(err))
 		c.String(http.StatusBadRequest, "Invalid message format")
 		return
@@ -40,7 +76,8 @@ func (w *WeChatController) HandleWebhook(c *gin.Context) {
 		zap.String("from_user", string(msg.FromUserName)),
 		zap.String("to_user", string(msg.ToUserName)),
 		zap.String("msg_type", string(msg.MsgType)),
-		zap.String("event", string(msg.Event)))
+		zap.String("event", string(msg.Event)),
+		zap.Bool("encrypted", encrypted))
 
 	// Process the message
 	reply, err := w.wechatService.ProcessMessage(c.Request.Context(), &msg)
@@ -57,11 +94,29 @@ func (w *WeChatController) HandleWebhook(c *gin.Context) {
 		zap.Bool("has_reply", reply != nil))
 
 	// Send reply if available
-	if reply != nil {
-		c.XML(http.StatusOK, reply)
-	} else {
+	if reply == nil {
 		c.String(http.StatusOK, "success")
+		return
 	}
+
+	if !encrypted {
+		c.XML(http.StatusOK, reply.MsgData)
+		return
+	}
+
+	replyXML, err := xml.Marshal(reply.MsgData)
+	if err != nil {
+		w.logger.Error("Failed to marshal WeChat reply", zap.Error(err))
+		c.String(http.StatusOK, "success")
+		return
+	}
+	encryptedReply, err := w.crypter.EncryptReply(replyXML, timestamp, nonce)
+	if err != nil {
+		w.logger.Error("Failed to encrypt WeChat reply", zap.Error(err))
+		c.String(http.StatusOK, "success")
+		return
+	}
+	c.XML(http.StatusOK, encryptedReply)
 }
 
 // VerifyWebhook handles WeChat webhook verification
@@ -74,17 +129,21 @@ func (w *WeChatController) VerifyWebhook(c *gin.Context) {
 	w.logger.Info("WeChat webhook verification request",
 		zap.String("signature", signature),
 		zap.String("timestamp", timestamp),
-		zap.String("nonce", nonce),
-		zap.String("echostr", echostr))
+		zap.String("nonce", nonce))
+
+	if !w.crypter.VerifySignature(signature, timestamp, nonce) {
+		w.logger.Warn("WeChat webhook verification failed",
+			zap.String("clientIP", c.ClientIP()))
+		c.String(http.StatusForbidden, "Invalid signature")
+		return
+	}
 
-	// For now, just return the echostr for verification
-	// In production, you would verify the signature
 	c.String(http.StatusOK, echostr)
 }
 
-// GetAccessToken returns the current access token
+// GetAccessToken reports whether an access token is available, without exposing the token itself
 func (w *WeChatController) GetAccessToken(c *gin.Context) {
-	token, err := w.wechatService.GetAccessToken(c.Request.Context())
+	_, err := w.wechatService.GetAccessToken(c.Request.Context())
 	if err != nil {
 		w.logger.Error("Failed to get access token", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -94,14 +153,14 @@ func (w *WeChatController) GetAccessToken(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"access_token": token,
-		"timestamp":    time.Now().Unix(),
+		"available": true,
+		"timestamp": time.Now().Unix(),
 	})
 }
 
-// RefreshAccessToken refreshes the access token
+// RefreshAccessToken refreshes the access token, without exposing the new token
 func (w *WeChatController) RefreshAccessToken(c *gin.Context) {
-	token, err := w.wechatService.RefreshAccessToken(c.Request.Context())
+	_, err := w.wechatService.RefreshAccessToken(c.Request.Context())
 	if err != nil {
 		w.logger.Error("Failed to refresh access token", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -111,9 +170,8 @@ func (w *WeChatController) RefreshAccessToken(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"access_token": token,
-		"timestamp":    time.Now().Unix(),
-		"refreshed":    true,
+		"timestamp": time.Now().Unix(),
+		"refreshed": true,
 	})
 }
 
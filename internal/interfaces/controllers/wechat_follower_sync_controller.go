@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"go.uber.org/zap"
+)
+
+// WeChatFollowerSyncController handles the reconciliation of the WeChat followers
+type WeChatFollowerSyncController struct {
+	syncService services.WeChatFollowerSyncService
+	logger      *zap.Logger
+}
+
+// NewWeChatFollowerSyncController creates a new WeChat follower sync controller
+func NewWeChatFollowerSyncController(syncService services.WeChatFollowerSyncService, logger *zap.Logger) *WeChatFollowerSyncController {
+	return &WeChatFollowerSyncController{
+		syncService: syncService,
+		logger:      logger,
+	}
+}
+
+// StartSync handles POST /api/v1/wechat/followers/sync, resuming the running sync if there is one
+func (c *WeChatFollowerSyncController) StartSync(ctx *gin.Context) {
+	followerSync, err := c.syncService.StartSync(ctx.Request.Context())
+	if err != nil {
+		c.handleSyncError(ctx, err, "Failed to start WeChat follower sync")
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"success": true, "data": followerSync})
+}
+
+// GetRecentSyncs handles GET /api/v1/wechat/followers/syncs?limit=
+func (c *WeChatFollowerSyncController) GetRecentSyncs(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	syncs, err := c.syncService.GetRecentSyncs(ctx.Request.Context(), limit)
+	if err != nil {
+		c.handleSyncError(ctx, err, "Failed to get WeChat follower syncs")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": syncs})
+}
+
+// GetSync handles GET /api/v1/wechat/followers/syncs/:syncId
+func (c *WeChatFollowerSyncController) GetSync(ctx *gin.Context) {
+	syncID, err := uuid.Parse(ctx.Param("syncId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid sync ID"})
+		return
+	}
+
+	followerSync, err := c.syncService.GetSync(ctx.Request.Context(), syncID)
+	if err != nil {
+		c.handleSyncError(ctx, err, "Failed to get WeChat follower sync")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": followerSync})
+}
+
+// handleSyncError maps WeChat follower sync service errors to responses
+func (c *WeChatFollowerSyncController) handleSyncError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "WeChat follower sync not found"})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
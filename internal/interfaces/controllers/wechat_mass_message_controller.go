@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatMassMessageController handles WeChat mass messages
+type WeChatMassMessageController struct {
+	massService services.WeChatMassMessageService
+	logger      *zap.Logger
+}
+
+// NewWeChatMassMessageController creates a new WeChat mass message controller
+func NewWeChatMassMessageController(massService services.WeChatMassMessageService, logger *zap.Logger) *WeChatMassMessageController {
+	return &WeChatMassMessageController{
+		massService: massService,
+		logger:      logger,
+	}
+}
+
+// WeChatMassMessageRequest is the body of the request creating a mass message, sent to a tag or
+// to a list of followers
+type WeChatMassMessageRequest struct {
+	Title   string   `json:"title" binding:"required"`
+	MsgType string   `json:"msgType" binding:"required"`
+	Content string   `json:"content"`
+	MediaID string   `json:"mediaId"`
+	TagID   *int     `json:"tagId"`
+	OpenIDs []string `json:"openIds"`
+}
+
+// CreateMessage handles POST /api/v1/wechat/mass-messages
+func (c *WeChatMassMessageController) CreateMessage(ctx *gin.Context) {
+	var req WeChatMassMessageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	msg := &entities.WeChatMassMessage{
+		Title:     req.Title,
+		MsgType:   req.MsgType,
+		Content:   req.Content,
+		MediaID:   req.MediaID,
+		TagID:     req.TagID,
+		OpenIDs:   req.OpenIDs,
+		CreatedBy: currentUserID(ctx),
+	}
+	if err := c.massService.CreateMessage(ctx.Request.Context(), msg); err != nil {
+		c.handleMassError(ctx, err, "Failed to create WeChat mass message")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": msg})
+}
+
+// GetRecentMessages handles GET /api/v1/wechat/mass-messages?limit=
+func (c *WeChatMassMessageController) GetRecentMessages(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	messages, err := c.massService.GetRecentMessages(ctx.Request.Context(), limit)
+	if err != nil {
+		c.handleMassError(ctx, err, "Failed to get WeChat mass messages")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": messages})
+}
+
+// GetMessage handles GET /api/v1/wechat/mass-messages/:messageId
+func (c *WeChatMassMessageController) GetMessage(ctx *gin.Context) {
+	messageID, ok := c.parseMessageID(ctx)
+	if !ok {
+		return
+	}
+
+	msg, err := c.massService.GetMessage(ctx.Request.Context(), messageID)
+	if err != nil {
+		c.handleMassError(ctx, err, "Failed to get WeChat mass message")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": msg})
+}
+
+// PreviewMessage handles POST /api/v1/wechat/mass-messages/:messageId/preview, sending the
+// message to the test users
+func (c *WeChatMassMessageController) PreviewMessage(ctx *gin.Context) {
+	messageID, ok := c.parseMessageID(ctx)
+	if !ok {
+		return
+	}
+
+	previewed, err := c.massService.Preview(ctx.Request.Context(), messageID)
+	if err != nil {
+		c.handleMassError(ctx, err, "Failed to preview WeChat mass message")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"previewed": previewed}})
+}
+
+// SendMessage handles POST /api/v1/wechat/mass-messages/:messageId/send
+func (c *WeChatMassMessageController) SendMessage(ctx *gin.Context) {
+	messageID, ok := c.parseMessageID(ctx)
+	if !ok {
+		return
+	}
+
+	msg, err := c.massService.Send(ctx.Request.Context(), messageID)
+	if err != nil {
+		c.handleMassError(ctx, err, "Failed to send WeChat mass message")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": msg})
+}
+
+// RefreshStatus handles POST /api/v1/wechat/mass-messages/:messageId/refresh
+func (c *WeChatMassMessageController) RefreshStatus(ctx *gin.Context) {
+	messageID, ok := c.parseMessageID(ctx)
+	if !ok {
+		return
+	}
+
+	msg, err := c.massService.RefreshStatus(ctx.Request.Context(), messageID)
+	if err != nil {
+		c.handleMassError(ctx, err, "Failed to refresh WeChat mass message status")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": msg})
+}
+
+// parseMessageID parses the message ID path parameter, responding with an error when it is invalid
+func (c *WeChatMassMessageController) parseMessageID(ctx *gin.Context) (uuid.UUID, bool) {
+	messageID, err := uuid.Parse(ctx.Param("messageId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid message ID"})
+		return uuid.Nil, false
+	}
+	return messageID, true
+}
+
+// handleMassError maps WeChat mass message service errors to responses
+func (c *WeChatMassMessageController) handleMassError(ctx *gin.Context, err error, message string) {
+	var apiErr *wechat.APIError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Mass message not found"})
+	case errors.Is(err, entities.ErrInvalidWeChatMassMessage):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrWeChatMassMessageSent),
+		errors.Is(err, services.ErrWeChatMassMessageNotSent),
+		errors.Is(err, services.ErrNoWeChatTestUsers):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	case errors.As(err, &apiErr):
+		c.logger.Warn(message, zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
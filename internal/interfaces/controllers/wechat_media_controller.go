@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatMediaController handles the media uploaded to WeChat
+type WeChatMediaController struct {
+	mediaService services.WeChatMediaService
+	logger       *zap.Logger
+}
+
+// NewWeChatMediaController creates a new WeChat media controller
+func NewWeChatMediaController(mediaService services.WeChatMediaService, logger *zap.Logger) *WeChatMediaController {
+	return &WeChatMediaController{
+		mediaService: mediaService,
+		logger:       logger,
+	}
+}
+
+// UploadMedia handles POST /api/v1/wechat/media. The form has the media type, permanent=true for
+// permanent media, an optional siteImageId the file belongs to and the file. Media with the same
+// content still available on WeChat is returned instead of uploading the file again.
+func (c *WeChatMediaController) UploadMedia(ctx *gin.Context) {
+	var siteImageID *uuid.UUID
+	if value := ctx.PostForm("siteImageId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid site image ID"})
+			return
+		}
+		siteImageID = &id
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "A file is required"})
+		return
+	}
+	if fileHeader.Size > entities.WeChatMediaMaxSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "error": "File is too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Failed to read file"})
+		return
+	}
+
+	media, err := c.mediaService.Upload(ctx.Request.Context(), &services.WeChatMediaUpload{
+		MediaType:   ctx.DefaultPostForm("type", entities.WeChatMediaTypeImage),
+		Permanent:   ctx.PostForm("permanent") == "true",
+		FileName:    fileHeader.Filename,
+		Data:        data,
+		SiteImageID: siteImageID,
+	})
+	if err != nil {
+		c.handleMediaError(ctx, err, "Failed to upload WeChat media")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": media})
+}
+
+// UploadSiteImage handles POST /api/v1/wechat/media/site-images/:imageId, uploading a site image
+// as a permanent image unless it was uploaded before
+func (c *WeChatMediaController) UploadSiteImage(ctx *gin.Context) {
+	imageID, err := uuid.Parse(ctx.Param("imageId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid site image ID"})
+		return
+	}
+
+	media, err := c.mediaService.UploadSiteImage(ctx.Request.Context(), imageID)
+	if err != nil {
+		c.handleMediaError(ctx, err, "Failed to upload site image to WeChat")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": media})
+}
+
+// GetTemporaryMedia handles GET /api/v1/wechat/media/temporary/:mediaId, returning the content
+// of temporary media
+func (c *WeChatMediaController) GetTemporaryMedia(ctx *gin.Context) {
+	data, err := c.mediaService.GetMedia(ctx.Request.Context(), ctx.Param("mediaId"))
+	if err != nil {
+		c.handleMediaError(ctx, err, "Failed to get WeChat media")
+		return
+	}
+
+	ctx.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+// GetPermanentMedia handles GET /api/v1/wechat/media/permanent?type=image&offset=&count=
+func (c *WeChatMediaController) GetPermanentMedia(ctx *gin.Context) {
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	count, _ := strconv.Atoi(ctx.DefaultQuery("count", "20"))
+	list, err := c.mediaService.GetPermanentMediaList(ctx.Request.Context(), ctx.DefaultQuery("type", entities.WeChatMediaTypeImage), offset, count)
+	if err != nil {
+		c.handleMediaError(ctx, err, "Failed to list WeChat media")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": list})
+}
+
+// DeletePermanentMedia handles DELETE /api/v1/wechat/media/permanent/:mediaId
+func (c *WeChatMediaController) DeletePermanentMedia(ctx *gin.Context) {
+	if err := c.mediaService.DeletePermanentMedia(ctx.Request.Context(), ctx.Param("mediaId")); err != nil {
+		c.handleMediaError(ctx, err, "Failed to delete WeChat media")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// handleMediaError maps WeChat media service errors to responses
+func (c *WeChatMediaController) handleMediaError(ctx *gin.Context, err error, message string) {
+	var apiErr *wechat.APIError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Site image not found"})
+	case errors.Is(err, entities.ErrInvalidWeChatMedia):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrWeChatMediaIsVideo):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	case errors.As(err, &apiErr):
+		c.logger.Warn(message, zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
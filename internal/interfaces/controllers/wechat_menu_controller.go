@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatMenuController handles the versioned WeChat custom menus
+type WeChatMenuController struct {
+	menuService services.WeChatMenuService
+	logger      *zap.Logger
+}
+
+// NewWeChatMenuController creates a new WeChat menu controller
+func NewWeChatMenuController(menuService services.WeChatMenuService, logger *zap.Logger) *WeChatMenuController {
+	return &WeChatMenuController{
+		menuService: menuService,
+		logger:      logger,
+	}
+}
+
+// WeChatMenuRequest is the body of requests saving a menu version
+type WeChatMenuRequest struct {
+	Name       string                      `json:"name" binding:"required"`
+	TagID      string                      `json:"tagId"`
+	Buttons    []entities.WeChatMenuButton `json:"buttons" binding:"required"`
+	ChangeNote string                      `json:"changeNote"`
+}
+
+// WeChatMenuTagRequest is the body of requests rolling back or unpublishing the menu of a tag
+type WeChatMenuTagRequest struct {
+	TagID string `json:"tagId"`
+}
+
+// GetPublishedMenus handles GET /api/v1/wechat/menus
+func (c *WeChatMenuController) GetPublishedMenus(ctx *gin.Context) {
+	menus, err := c.menuService.GetPublishedMenus(ctx.Request.Context())
+	if err != nil {
+		c.handleMenuError(ctx, err, "Failed to get WeChat menus")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": menus})
+}
+
+// GetMenuVersions handles GET /api/v1/wechat/menus/versions?tagId=, listing the versions of the
+// default menu without a tag
+func (c *WeChatMenuController) GetMenuVersions(ctx *gin.Context) {
+	menus, err := c.menuService.GetMenuVersions(ctx.Request.Context(), ctx.Query("tagId"))
+	if err != nil {
+		c.handleMenuError(ctx, err, "Failed to get WeChat menu versions")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": menus})
+}
+
+// SaveMenu handles POST /api/v1/wechat/menus
+func (c *WeChatMenuController) SaveMenu(ctx *gin.Context) {
+	var req WeChatMenuRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	menu := &entities.WeChatMenu{
+		Name:       req.Name,
+		TagID:      req.TagID,
+		Buttons:    req.Buttons,
+		ChangeNote: req.ChangeNote,
+		CreatedBy:  currentUserID(ctx),
+	}
+	if err := c.menuService.SaveMenu(ctx.Request.Context(), menu); err != nil {
+		c.handleMenuError(ctx, err, "Failed to save WeChat menu")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": menu})
+}
+
+// GetMenu handles GET /api/v1/wechat/menus/:menuId
+func (c *WeChatMenuController) GetMenu(ctx *gin.Context) {
+	menuID, err := uuid.Parse(ctx.Param("menuId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid menu ID"})
+		return
+	}
+
+	menu, err := c.menuService.GetMenu(ctx.Request.Context(), menuID)
+	if err != nil {
+		c.handleMenuError(ctx, err, "Failed to get WeChat menu")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": menu})
+}
+
+// DeleteMenu handles DELETE /api/v1/wechat/menus/:menuId
+func (c *WeChatMenuController) DeleteMenu(ctx *gin.Context) {
+	menuID, err := uuid.Parse(ctx.Param("menuId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid menu ID"})
+		return
+	}
+
+	if err := c.menuService.DeleteMenu(ctx.Request.Context(), menuID); err != nil {
+		c.handleMenuError(ctx, err, "Failed to delete WeChat menu")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// PublishMenu handles POST /api/v1/wechat/menus/:menuId/publish
+func (c *WeChatMenuController) PublishMenu(ctx *gin.Context) {
+	menuID, err := uuid.Parse(ctx.Param("menuId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid menu ID"})
+		return
+	}
+
+	menu, err := c.menuService.PublishMenu(ctx.Request.Context(), menuID, currentUserID(ctx))
+	if err != nil {
+		c.handleMenuError(ctx, err, "Failed to publish WeChat menu")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": menu})
+}
+
+// RollbackMenu handles POST /api/v1/wechat/menus/rollback
+func (c *WeChatMenuController) RollbackMenu(ctx *gin.Context) {
+	var req WeChatMenuTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	menu, err := c.menuService.RollbackMenu(ctx.Request.Context(), req.TagID, currentUserID(ctx))
+	if err != nil {
+		c.handleMenuError(ctx, err, "Failed to roll back WeChat menu")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": menu})
+}
+
+// UnpublishMenu handles POST /api/v1/wechat/menus/unpublish
+func (c *WeChatMenuController) UnpublishMenu(ctx *gin.Context) {
+	var req WeChatMenuTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := c.menuService.UnpublishMenu(ctx.Request.Context(), req.TagID); err != nil {
+		c.handleMenuError(ctx, err, "Failed to unpublish WeChat menu")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// handleMenuError maps WeChat menu service errors to responses
+func (c *WeChatMenuController) handleMenuError(ctx *gin.Context, err error, message string) {
+	var apiErr *wechat.APIError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "WeChat menu not found"})
+	case errors.Is(err, entities.ErrInvalidWeChatMenu):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrWeChatMenuPublished), errors.Is(err, services.ErrNoDefaultWeChatMenu),
+		errors.Is(err, services.ErrNoWeChatMenuToRollback):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	case errors.As(err, &apiErr):
+		c.logger.Warn(message, zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatTagController handles the WeChat user tags and the followers carrying them
+type WeChatTagController struct {
+	tagService services.WeChatTagService
+	logger     *zap.Logger
+}
+
+// NewWeChatTagController creates a new WeChat tag controller
+func NewWeChatTagController(tagService services.WeChatTagService, logger *zap.Logger) *WeChatTagController {
+	return &WeChatTagController{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// WeChatTagRequest is the body of the requests creating and renaming tags
+type WeChatTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// WeChatTagUsersRequest is the body of the requests tagging and untagging followers
+type WeChatTagUsersRequest struct {
+	OpenIDs []string `json:"openIds" binding:"required,min=1"`
+}
+
+// GetTags handles GET /api/v1/wechat/tags
+func (c *WeChatTagController) GetTags(ctx *gin.Context) {
+	tags, err := c.tagService.GetTags(ctx.Request.Context())
+	if err != nil {
+		c.handleTagError(ctx, err, "Failed to get WeChat tags")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+// CreateTag handles POST /api/v1/wechat/tags
+func (c *WeChatTagController) CreateTag(ctx *gin.Context) {
+	var req WeChatTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	tag, err := c.tagService.CreateTag(ctx.Request.Context(), req.Name)
+	if err != nil {
+		c.handleTagError(ctx, err, "Failed to create WeChat tag")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": tag})
+}
+
+// UpdateTag handles PUT /api/v1/wechat/tags/:tagId
+func (c *WeChatTagController) UpdateTag(ctx *gin.Context) {
+	tagID, ok := c.parseTagID(ctx)
+	if !ok {
+		return
+	}
+	var req WeChatTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := c.tagService.UpdateTag(ctx.Request.Context(), tagID, req.Name); err != nil {
+		c.handleTagError(ctx, err, "Failed to update WeChat tag")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// DeleteTag handles DELETE /api/v1/wechat/tags/:tagId
+func (c *WeChatTagController) DeleteTag(ctx *gin.Context) {
+	tagID, ok := c.parseTagID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.tagService.DeleteTag(ctx.Request.Context(), tagID); err != nil {
+		c.handleTagError(ctx, err, "Failed to delete WeChat tag")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// TagUsers handles POST /api/v1/wechat/tags/:tagId/users
+func (c *WeChatTagController) TagUsers(ctx *gin.Context) {
+	tagID, ok := c.parseTagID(ctx)
+	if !ok {
+		return
+	}
+	var req WeChatTagUsersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := c.tagService.TagUsers(ctx.Request.Context(), tagID, req.OpenIDs); err != nil {
+		c.handleTagError(ctx, err, "Failed to tag WeChat followers")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// UntagUsers handles DELETE /api/v1/wechat/tags/:tagId/users
+func (c *WeChatTagController) UntagUsers(ctx *gin.Context) {
+	tagID, ok := c.parseTagID(ctx)
+	if !ok {
+		return
+	}
+	var req WeChatTagUsersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := c.tagService.UntagUsers(ctx.Request.Context(), tagID, req.OpenIDs); err != nil {
+		c.handleTagError(ctx, err, "Failed to untag WeChat followers")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// TagEventAttendees handles POST /api/v1/wechat/tags/events/:eventId, tagging the followers who
+// checked in to the event
+func (c *WeChatTagController) TagEventAttendees(ctx *gin.Context) {
+	eventID, err := uuid.Parse(ctx.Param("eventId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid event ID"})
+		return
+	}
+
+	tagging, err := c.tagService.TagEventAttendees(ctx.Request.Context(), eventID)
+	if err != nil {
+		c.handleTagError(ctx, err, "Failed to tag event attendees")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": tagging})
+}
+
+// parseTagID parses the tag ID path parameter, responding with an error when it is invalid
+func (c *WeChatTagController) parseTagID(ctx *gin.Context) (int, bool) {
+	tagID, err := strconv.Atoi(ctx.Param("tagId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid tag ID"})
+		return 0, false
+	}
+	return tagID, true
+}
+
+// handleTagError maps WeChat tag service errors to responses
+func (c *WeChatTagController) handleTagError(ctx *gin.Context, err error, message string) {
+	var apiErr *wechat.APIError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Event not found"})
+	case errors.Is(err, services.ErrInvalidWeChatTagName):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.As(err, &apiErr):
+		c.logger.Warn(message, zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
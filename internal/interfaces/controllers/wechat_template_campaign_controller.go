@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zenteam/nextevent-go/internal/domain/entities"
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	"github.com/zenteam/nextevent-go/internal/domain/services"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
+	"go.uber.org/zap"
+)
+
+// WeChatTemplateCampaignController handles WeChat template message campaigns and their deliveries
+type WeChatTemplateCampaignController struct {
+	campaignService services.WeChatTemplateCampaignService
+	logger          *zap.Logger
+}
+
+// NewWeChatTemplateCampaignController creates a new WeChat template campaign controller
+func NewWeChatTemplateCampaignController(campaignService services.WeChatTemplateCampaignService, logger *zap.Logger) *WeChatTemplateCampaignController {
+	return &WeChatTemplateCampaignController{
+		campaignService: campaignService,
+		logger:          logger,
+	}
+}
+
+// WeChatTemplateCampaignRequest is the body of the request creating a campaign. Field values and
+// the URL may use the {name}, {nickname}, {company}, {position}, {mobile}, {email}, {city},
+// {openId}, {event}, {eventStart}, {eventEnd}, {survey} and {invitationToken} placeholders.
+type WeChatTemplateCampaignRequest struct {
+	Name          string                         `json:"name" binding:"required"`
+	TemplateID    string                         `json:"templateId" binding:"required"`
+	URL           string                         `json:"url"`
+	Fields        []entities.WeChatTemplateField `json:"fields" binding:"required,min=1"`
+	Audience      string                         `json:"audience" binding:"required"`
+	EventID       *uuid.UUID                     `json:"eventId"`
+	SurveyID      *uuid.UUID                     `json:"surveyId"`
+	TagID         *int                           `json:"tagId"`
+	CheckedInOnly bool                           `json:"checkedInOnly"`
+}
+
+// CreateCampaign handles POST /api/v1/wechat/template-campaigns
+func (c *WeChatTemplateCampaignController) CreateCampaign(ctx *gin.Context) {
+	var req WeChatTemplateCampaignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	campaign := &entities.WeChatTemplateCampaign{
+		Name:          req.Name,
+		TemplateID:    req.TemplateID,
+		URL:           req.URL,
+		FieldMappings: req.Fields,
+		Audience:      req.Audience,
+		EventID:       req.EventID,
+		SurveyID:      req.SurveyID,
+		TagID:         req.TagID,
+		CheckedInOnly: req.CheckedInOnly,
+		CreatedBy:     currentUserID(ctx),
+	}
+	if err := c.campaignService.CreateCampaign(ctx.Request.Context(), campaign); err != nil {
+		c.handleCampaignError(ctx, err, "Failed to create WeChat template campaign")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"success": true, "data": campaign})
+}
+
+// GetRecentCampaigns handles GET /api/v1/wechat/template-campaigns?limit=
+func (c *WeChatTemplateCampaignController) GetRecentCampaigns(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	campaigns, err := c.campaignService.GetRecentCampaigns(ctx.Request.Context(), limit)
+	if err != nil {
+		c.handleCampaignError(ctx, err, "Failed to get WeChat template campaigns")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": campaigns})
+}
+
+// GetCampaign handles GET /api/v1/wechat/template-campaigns/:campaignId, with the deliveries
+// counted by status
+func (c *WeChatTemplateCampaignController) GetCampaign(ctx *gin.Context) {
+	campaignID, ok := c.parseCampaignID(ctx)
+	if !ok {
+		return
+	}
+
+	campaign, err := c.campaignService.GetCampaign(ctx.Request.Context(), campaignID)
+	if err != nil {
+		c.handleCampaignError(ctx, err, "Failed to get WeChat template campaign")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": campaign})
+}
+
+// GetDeliveries handles GET /api/v1/wechat/template-campaigns/:campaignId/deliveries?status=&offset=&limit=
+func (c *WeChatTemplateCampaignController) GetDeliveries(ctx *gin.Context) {
+	campaignID, ok := c.parseCampaignID(ctx)
+	if !ok {
+		return
+	}
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "100"))
+
+	deliveries, total, err := c.campaignService.GetDeliveries(ctx.Request.Context(), campaignID, ctx.Query("status"), offset, limit)
+	if err != nil {
+		c.handleCampaignError(ctx, err, "Failed to get WeChat template deliveries")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"deliveries": deliveries, "total": total}})
+}
+
+// StartCampaign handles POST /api/v1/wechat/template-campaigns/:campaignId/start
+func (c *WeChatTemplateCampaignController) StartCampaign(ctx *gin.Context) {
+	campaignID, ok := c.parseCampaignID(ctx)
+	if !ok {
+		return
+	}
+
+	campaign, err := c.campaignService.StartCampaign(ctx.Request.Context(), campaignID)
+	if err != nil {
+		c.handleCampaignError(ctx, err, "Failed to start WeChat template campaign")
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"success": true, "data": campaign})
+}
+
+// CancelCampaign handles POST /api/v1/wechat/template-campaigns/:campaignId/cancel
+func (c *WeChatTemplateCampaignController) CancelCampaign(ctx *gin.Context) {
+	campaignID, ok := c.parseCampaignID(ctx)
+	if !ok {
+		return
+	}
+
+	campaign, err := c.campaignService.CancelCampaign(ctx.Request.Context(), campaignID)
+	if err != nil {
+		c.handleCampaignError(ctx, err, "Failed to cancel WeChat template campaign")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": campaign})
+}
+
+// parseCampaignID parses the campaign ID path parameter, responding with an error when it is invalid
+func (c *WeChatTemplateCampaignController) parseCampaignID(ctx *gin.Context) (uuid.UUID, bool) {
+	campaignID, err := uuid.Parse(ctx.Param("campaignId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid campaign ID"})
+		return uuid.Nil, false
+	}
+	return campaignID, true
+}
+
+// handleCampaignError maps WeChat template campaign service errors to responses
+func (c *WeChatTemplateCampaignController) handleCampaignError(ctx *gin.Context, err error, message string) {
+	var apiErr *wechat.APIError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Campaign, event or survey not found"})
+	case errors.Is(err, entities.ErrInvalidWeChatTemplateCampaign):
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	case errors.Is(err, services.ErrWeChatTemplateCampaignStarted),
+		errors.Is(err, services.ErrWeChatTemplateCampaignFinished):
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": err.Error()})
+	case errors.As(err, &apiErr):
+		c.logger.Warn(message, zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"success": false, "error": err.Error()})
+	default:
+		c.logger.Error(message, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": message})
+	}
+}
@@ -5,12 +5,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zenteam/nextevent-go/internal/application/services"
+	domainServices "github.com/zenteam/nextevent-go/internal/domain/services"
 	"github.com/zenteam/nextevent-go/internal/infrastructure"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/email"
 	"github.com/zenteam/nextevent-go/internal/infrastructure/repositories"
+	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
 	infraServices "github.com/zenteam/nextevent-go/internal/infrastructure/services"
 	"github.com/zenteam/nextevent-go/internal/interfaces/controllers"
 	"github.com/zenteam/nextevent-go/internal/interfaces/middleware"
 	"github.com/zenteam/nextevent-go/internal/simple"
+	"github.com/zenteam/nextevent-go/pkg/storage"
+	"go.uber.org/zap"
 )
 
 func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
@@ -26,35 +31,274 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 	videoRepo := repositories.NewGormVideoRepository(infra.DB)
 	categoryRepo := repositories.NewGormArticleCategoryRepository(infra.DB)
 	wechatUserRepo := repositories.NewGormWeChatUserRepository(infra.DB)
+	qrCodeRepo := repositories.NewGormWeChatQrCodeRepository(infra.DB)
+	newsRepo := repositories.NewGormNewsRepository(infra.DB)
 
 	// Initialize services
 	eventService := infraServices.NewEventService(eventRepo, userRepo, attendeeRepo, infra.Logger, infra.DB)
 	siteEventService := services.NewSiteEventService(eventRepo, articleRepo, surveyRepo, videoRepo, categoryRepo, infra.Logger)
-	// attendeeService := services.NewAttendeeService(attendeeRepo, eventRepo, userRepo, infra.Logger, infra.DB)
-	// qrCodeService := services.NewQRCodeService(eventRepo, attendeeRepo, infra.RedisClient, infra.Logger)
-
-	// Initialize WeChat service with event integration
-	// wechatService, err := services.NewWeChatServiceSimple(infra.Config, infra.Logger, infra.RedisClient, eventService, nil)
-	// if err != nil {
-	//	infra.Logger.Fatal("Failed to initialize WeChat service", zap.Error(err))
-	// }
+	ticketSigner, err := infraServices.NewTicketSignerFromConfig(infra.Config.QRCode.TicketSigningKey)
+	if err != nil {
+		infra.Logger.Fatal("Failed to initialize ticket signer", zap.Error(err))
+	}
+	if ticketSigner == nil {
+		infra.Logger.Warn("QR_CODE_TICKET_SIGNING_KEY is not set, attendee tickets are disabled")
+	}
+	qrCodeService := infraServices.NewQRCodeService(eventRepo, attendeeRepo, ticketSigner, infra.RedisClient, &infraServices.QRCodeServiceConfig{
+		DefaultSize:        infra.Config.QRCode.DefaultSize,
+		MaxSize:            infra.Config.QRCode.MaxSize,
+		DefaultExpireHours: infra.Config.QRCode.DefaultExpiration,
+		TicketGraceHours:   infra.Config.QRCode.TicketGraceHours,
+	}, infra.Logger)
+	attendeeService := infraServices.NewAttendeeService(attendeeRepo, eventRepo, wechatUserRepo, qrCodeService, infra.Logger)
+
+	// Initialize WeChat service with message routing
+	publicAccount := infra.Config.WeChat.PublicAccount
+	wechatConfig := &wechat.Config{
+		AppID:            publicAccount.AppID,
+		AppSecret:        publicAccount.AppSecret,
+		Token:            publicAccount.Token,
+		EncodingAESKey:   publicAccount.AESKey,
+		VerifySignature:  true,
+		CacheAccessToken: infra.RedisClient != nil,
+		APIBaseURL:       publicAccount.APIBaseURL,
+	}
+	wechatAPIService := wechat.NewService(wechatConfig, infra.RedisClient, infra.Logger)
+	wechatCrypter, err := wechat.NewMessageCrypter(wechatConfig)
+	if err != nil {
+		infra.Logger.Fatal("Failed to initialize WeChat message crypter", zap.Error(err))
+	}
+	wechatService := infraServices.NewWeChatServiceImpl(wechatAPIService, wechatUserRepo, infra.DB, infra.Logger)
+	wechatService.SetWelcomeMessage(publicAccount.WelcomeMessage)
+	wechatQRService := infraServices.NewWeChatQRService(qrCodeRepo, wechatService, infra.Logger, infra.DB, &infraServices.WeChatQRServiceConfig{
+		DefaultExpireSeconds: 2592000,
+		BaseURL:              infra.Config.Server.BaseURL,
+	})
+	checkInService := infraServices.NewEventCheckInService(qrCodeRepo, wechatQRService, eventRepo, attendeeRepo, wechatUserRepo, newsRepo, infra.Logger)
+	checkInService.RegisterHandlers(wechatService.Router())
+	eventCheckInController := controllers.NewEventCheckInController(checkInService, infra.Logger)
+
+	// Initialize versioned WeChat custom menus, whose CLICK buttons are answered by the router
+	wechatMenuService := infraServices.NewWeChatMenuService(
+		repositories.NewGormWeChatMenuRepository(infra.DB),
+		wechatService,
+		infra.Logger,
+	)
+	wechatMenuService.RegisterHandlers(wechatService.Router())
+	wechatMenuController := controllers.NewWeChatMenuController(wechatMenuService, infra.Logger)
+
+	// Initialize the WeChat follower sync; without Redis it runs in the API process instead of the worker
+	var followerSyncQueue domainServices.WeChatFollowerSyncQueue
+	if infra.JobScheduler != nil {
+		followerSyncQueue = infra.JobScheduler
+	}
+	followerSyncService := infraServices.NewWeChatFollowerSyncService(
+		repositories.NewGormWeChatFollowerSyncRepository(infra.DB),
+		wechatUserRepo,
+		wechatService,
+		followerSyncQueue,
+		infra.Logger,
+	)
+	followerSyncController := controllers.NewWeChatFollowerSyncController(followerSyncService, infra.Logger)
+
+	// Initialize WeChat media, uploaded once per content
+	wechatMediaService := infraServices.NewWeChatMediaService(
+		repositories.NewGormWeChatMediaRepository(infra.DB),
+		repositories.NewGormSiteImageRepository(infra.DB),
+		wechatService,
+		storage.NewLocalStorage("./uploads", "/uploads"),
+		infra.Logger,
+	)
+	wechatMediaController := controllers.NewWeChatMediaController(wechatMediaService, infra.Logger)
+
+	// Initialize WeChat user tags and the mass messages sent to them
+	wechatTagService := infraServices.NewWeChatTagService(wechatService, eventRepo, wechatUserRepo, infra.Logger)
+	wechatTagController := controllers.NewWeChatTagController(wechatTagService, infra.Logger)
+	wechatMassMessageService := infraServices.NewWeChatMassMessageService(
+		repositories.NewGormWeChatMassMessageRepository(infra.DB),
+		wechatUserRepo,
+		wechatService,
+		infra.Logger,
+	)
+	wechatMassMessageService.RegisterHandlers(wechatService.Router())
+	wechatMassMessageController := controllers.NewWeChatMassMessageController(wechatMassMessageService, infra.Logger)
+
+	// Initialize WeChat template campaigns, whose deliveries are confirmed by TEMPLATESENDJOBFINISH
+	// events; without Redis they are sent by the API process instead of the worker
+	var templateCampaignQueue domainServices.WeChatTemplateCampaignQueue
+	if infra.JobScheduler != nil {
+		templateCampaignQueue = infra.JobScheduler
+	}
+	wechatTemplateCampaignService := infraServices.NewWeChatTemplateCampaignService(
+		repositories.NewGormWeChatTemplateCampaignRepository(infra.DB),
+		repositories.NewGormWeChatTemplateDeliveryRepository(infra.DB),
+		attendeeRepo,
+		eventRepo,
+		surveyRepo,
+		repositories.NewGormSurveyInvitationRepository(infra.DB),
+		wechatUserRepo,
+		wechatService,
+		templateCampaignQueue,
+		infraServices.DefaultWeChatTemplateCampaignConfig(),
+		infra.Logger,
+	)
+	wechatTemplateCampaignService.RegisterHandlers(wechatService.Router())
+	wechatTemplateCampaignController := controllers.NewWeChatTemplateCampaignController(wechatTemplateCampaignService, infra.Logger)
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(infra.Config, infra.Logger)
-	// wechatController := controllers.NewWeChatController(wechatService, infra.Logger)
+	wechatController := controllers.NewWeChatController(wechatService, wechatCrypter, infra.Logger)
 	wechatUsersController := controllers.NewWeChatUsersController(wechatUserRepo, infra.Logger)
-	eventController := controllers.NewEventController(eventService, nil, nil, infra.Logger)
+	eventController := controllers.NewEventController(eventService, attendeeService, qrCodeService, infra.Logger)
+	attendeeTransferController := controllers.NewAttendeeTransferController(
+		infraServices.NewAttendeeTransferService(eventService, attendeeRepo, wechatUserRepo, infra.Logger), infra.Logger)
 	siteEventController := controllers.NewSiteEventController(siteEventService, infra.Logger)
-	simpleAttendeeController := controllers.NewSimpleAttendeeController(infra.DB, infra.Logger)
 	// imageController := controllers.NewImageController(imageService, imageCategoryService, infra.Logger)
 	// imageCategoryController := controllers.NewImageCategoryController(imageCategoryService, infra.Logger)
 
 	// Initialize survey service and controller
 	surveyService := services.NewSurveyService(infra.DB)
-	surveyController := controllers.NewSurveyController(surveyService, infra.Logger)
+	surveyPipingRepo := repositories.NewGormSurveyPipingRepository(infra.DB)
+	surveyPipingService := infraServices.NewSurveyPipingService(surveyPipingRepo, infra.Logger)
+	surveyController := controllers.NewSurveyController(surveyService, surveyPipingService, infra.Logger)
+	surveyPipingController := controllers.NewSurveyPipingController(surveyPipingService, surveyService, infra.Logger)
+
+	// Initialize survey branching logic
+	surveyQuestionRepo := repositories.NewGormSurveyQuestionRepository(infra.DB)
+	surveyLogicRepo := repositories.NewGormSurveyLogicRepository(infra.DB)
+	surveyLogicService := infraServices.NewSurveyLogicService(surveyLogicRepo, surveyQuestionRepo, infra.Logger)
+	surveyLogicController := controllers.NewSurveyLogicController(surveyLogicService, infra.Logger)
+
+	// Initialize survey quotas
+	surveyQuotaRepo := repositories.NewGormSurveyQuotaRepository(infra.DB)
+	surveyQuotaService := infraServices.NewSurveyQuotaService(surveyQuotaRepo, surveyQuestionRepo, infra.Logger)
+	surveyQuotaController := controllers.NewSurveyQuotaController(surveyQuotaService, infra.Logger)
+
+	// Initialize survey versions, created when a survey is revised after responses are collected
+	surveyVersionRepo := repositories.NewGormSurveyVersionRepository(infra.DB)
+	surveyVersionService := infraServices.NewSurveyVersionService(
+		repositories.NewGormSurveyRepository(infra.DB),
+		surveyQuestionRepo,
+		surveyVersionRepo,
+		infra.Logger,
+	)
+	surveyVersionController := controllers.NewSurveyVersionController(surveyVersionService, infra.Logger)
+
+	// Initialize survey collectors, each with its own link, WeChat QR code and limits
+	surveyCollectorService := infraServices.NewSurveyCollectorService(
+		repositories.NewGormSurveyCollectorRepository(infra.DB),
+		repositories.NewGormSurveyRepository(infra.DB),
+		wechatQRService,
+		infra.Config.Server.BaseURL,
+		infra.Logger,
+	)
+	surveyCollectorService.RegisterHandlers(wechatService.Router())
+	surveyCollectorController := controllers.NewSurveyCollectorController(surveyCollectorService, infra.Logger)
+
+	// Initialize survey invitations, delivered as WeChat template messages or by email
+	emailSender := email.NewSMTPSender(infra.Config.Email, infra.Logger)
+	surveyInvitationService := infraServices.NewSurveyInvitationService(
+		repositories.NewGormSurveyInvitationRepository(infra.DB),
+		repositories.NewGormSurveyRepository(infra.DB),
+		wechatUserRepo,
+		wechatService,
+		emailSender,
+		infraServices.DefaultSurveyInvitationConfig(infra.Config.Server.BaseURL, publicAccount.SurveyInvitationTemplateID),
+		infra.Logger,
+	)
+	surveyInvitationController := controllers.NewSurveyInvitationController(surveyInvitationService, infra.Logger)
+
+	// Initialize survey notifications; without Redis they are delivered by the API process instead of the worker
+	var surveyNotificationQueue domainServices.SurveyNotificationQueue
+	if infra.JobScheduler != nil {
+		surveyNotificationQueue = infra.JobScheduler
+	}
+	surveyNotificationService := infraServices.NewSurveyNotificationService(
+		repositories.NewGormSurveyNotificationRepository(infra.DB),
+		repositories.NewGormSurveyNotificationDeliveryRepository(infra.DB),
+		repositories.NewGormSurveyRepository(infra.DB),
+		repositories.NewGormSurveyResponseRepository(infra.DB),
+		infraServices.NewSurveyNotificationChannels(emailSender, wechatService, publicAccount.SurveyNotificationTemplateID),
+		surveyNotificationQueue,
+		infra.Logger,
+	)
+	surveyNotificationController := controllers.NewSurveyNotificationController(surveyNotificationService, infra.Logger)
+
+	// Initialize survey response submission and resumable sessions. Respondent uploads are kept out
+	// of the public uploads directory and served to organizers by the survey file controller.
+	surveyFileStorage := storage.NewLocalStorage(infra.Config.Server.PrivateStoragePath, "")
+	surveyResponseService := infraServices.NewSurveyResponseService(
+		repositories.NewGormSurveyRepository(infra.DB),
+		repositories.NewGormSurveyResponseRepository(infra.DB),
+		repositories.NewGormSurveySessionRepository(infra.DB),
+		surveyLogicService,
+		surveyQuotaService,
+		surveyInvitationService,
+		surveyNotificationService,
+		surveyCollectorService,
+		surveyFileStorage,
+		infra.Logger,
+	)
+	surveyResponseController := controllers.NewSurveyResponseController(surveyResponseService, infra.Logger)
+	surveyFileController := controllers.NewSurveyFileController(
+		infraServices.NewSurveyFileService(
+			repositories.NewGormSurveyResponseRepository(infra.DB),
+			repositories.NewGormSurveySessionRepository(infra.DB),
+			surveyFileStorage,
+			infra.Logger,
+		),
+		infra.Logger,
+	)
+
+	// Initialize survey exports; without Redis they run in the API process instead of the worker
+	var surveyExportQueue domainServices.SurveyExportQueue
+	if infra.JobScheduler != nil {
+		surveyExportQueue = infra.JobScheduler
+	}
+	surveyExportService := infraServices.NewSurveyExportService(
+		repositories.NewGormSurveyRepository(infra.DB),
+		surveyQuestionRepo,
+		surveyVersionRepo,
+		repositories.NewGormSurveyResponseRepository(infra.DB),
+		repositories.NewGormSurveyExportRepository(infra.DB),
+		storage.NewLocalStorage(infra.Config.Server.PrivateStoragePath, ""),
+		surveyExportQueue,
+		infra.Logger,
+	)
+	surveyExportController := controllers.NewSurveyExportController(surveyExportService, infra.Logger)
+
+	// Initialize survey analytics
+	surveyAnalyticsService := infraServices.NewSurveyAnalyticsService(
+		repositories.NewGormSurveyRepository(infra.DB),
+		repositories.NewGormSurveyResponseRepository(infra.DB),
+		repositories.NewGormSurveyAnalyticsRepository(infra.DB),
+		surveyVersionRepo,
+		infra.Logger,
+	)
+	surveyAnalyticsController := controllers.NewSurveyAnalyticsController(surveyAnalyticsService, infra.Logger)
+
+	// Initialize survey templates
+	surveyTemplateService := infraServices.NewSurveyTemplateService(
+		repositories.NewGormSurveyTemplateRepository(infra.DB),
+		repositories.NewGormSurveyRepository(infra.DB),
+		surveyLogicRepo,
+		infra.Logger,
+	)
+	surveyTemplateController := controllers.NewSurveyTemplateController(surveyTemplateService, infra.Logger)
+
+	// Initialize survey live polls; votes are counted in Redis, or in memory without it
+	surveyLivePollService := infraServices.NewSurveyLivePollService(
+		repositories.NewGormSurveyLivePollRepository(infra.DB),
+		repositories.NewGormSurveyRepository(infra.DB),
+		surveyQuestionRepo,
+		eventRepo,
+		infra.RedisClient,
+		infra.Logger,
+	)
+	surveyLivePollController := controllers.NewSurveyLivePollController(surveyLivePollService, infra.Logger)
 
 	// Initialize mobile controller
-	mobileController := controllers.NewMobileController(surveyService, infra.Logger)
+	mobileController := controllers.NewMobileController(surveyService, surveyPipingService, surveyLivePollService, infra.Logger)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -234,21 +478,26 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 		})
 	})
 
-	// WeChat webhook endpoints (commented out due to missing service)
-	// wechatGroup := router.Group("/wechat")
-	// {
-	//	// Webhook verification and message handling
-	//	wechatGroup.GET("/webhook", wechatController.VerifyWebhook)
-	//	wechatGroup.POST("/webhook", wechatController.HandleWebhook)
-	//
-	//	// WeChat API endpoints
-	//	wechatGroup.GET("/token", wechatController.GetAccessToken)
-	//	wechatGroup.POST("/token/refresh", wechatController.RefreshAccessToken)
-	//	wechatGroup.POST("/message/send", wechatController.SendMessage)
-	//	wechatGroup.GET("/user/:openid", wechatController.GetUserInfo)
-	//	wechatGroup.POST("/qrcode", wechatController.CreateQRCode)
-	//	wechatGroup.GET("/health", wechatController.HealthCheck)
-	// }
+	// WeChat webhook endpoints
+	wechatGroup := router.Group("/wechat")
+	{
+		// Webhook verification and message handling
+		wechatGroup.GET("/webhook", wechatController.VerifyWebhook)
+		wechatGroup.POST("/webhook", wechatController.HandleWebhook)
+
+		wechatGroup.GET("/health", wechatController.HealthCheck)
+
+		// WeChat API endpoints act as the official account, so they are restricted to admins
+		wechatAdmin := wechatGroup.Group("")
+		wechatAdmin.Use(middleware.AuthMiddleware(infra.Config, infra.Logger), middleware.RequireRole("admin"))
+		{
+			wechatAdmin.GET("/token", wechatController.GetAccessToken)
+			wechatAdmin.POST("/token/refresh", wechatController.RefreshAccessToken)
+			wechatAdmin.POST("/message/send", wechatController.SendMessage)
+			wechatAdmin.GET("/user/:openid", wechatController.GetUserInfo)
+			wechatAdmin.POST("/qrcode", wechatController.CreateQRCode)
+		}
+	}
 
 	// Authentication routes (public)
 	auth := router.Group("/api/v1/auth")
@@ -276,6 +525,7 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 			events.POST("/", eventController.CreateEvent)
 			events.GET("/", eventController.GetEvents)
 			events.GET("/current", eventController.GetCurrentEvent)
+			events.GET("/tickets/verification-key", eventController.GetTicketVerificationKey)
 			events.GET("/:id", eventController.GetEvent)
 			events.PUT("/:id", eventController.UpdateEvent)
 			events.DELETE("/:id", eventController.DeleteEvent)
@@ -283,7 +533,24 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 			events.GET("/:id/statistics", eventController.GetEventStatistics)
 
 			// Event attendees
-			events.GET("/:id/attendees", simpleAttendeeController.GetEventAttendees)
+			events.GET("/:id/attendees", eventController.GetEventAttendees)
+			events.POST("/:id/attendees", eventController.RegisterAttendee)
+			events.GET("/:id/attendees/counts", eventController.GetAttendeeCounts)
+			events.POST("/:id/attendees/import", attendeeTransferController.ImportAttendees)
+			events.GET("/:id/attendees/export", attendeeTransferController.ExportAttendance)
+			events.POST("/:id/attendees/:attendeeId/check-in", eventController.CheckInAttendee)
+			events.POST("/:id/attendees/:attendeeId/cancel", eventController.CancelRegistration)
+			events.PUT("/:id/attendees/:attendeeId/status", eventController.UpdateAttendeeStatus)
+			events.GET("/:id/check-in-status", eventController.GetCheckInStatus)
+			events.POST("/:id/check-in", eventController.CheckInByQRCode)
+			events.POST("/:id/wechat/qrcode", eventCheckInController.GenerateEventWeChatQRCode)
+
+			// Signed attendee tickets and door scanners
+			events.GET("/:id/attendees/:attendeeId/ticket", eventController.GetAttendeeTicket)
+			events.POST("/:id/tickets/validate", eventController.ValidateTicket)
+			events.POST("/:id/tickets/scan", eventController.ScanTicket)
+			events.POST("/:id/tickets/sync", eventController.SyncTicketScans)
+			events.POST("/:id/tickets/revoke", eventController.RevokeTicket)
 		}
 
 		// Site Events endpoints (protected) - v1 compatibility
@@ -327,6 +594,86 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 			surveys.GET("/:surveyId/wechat/qrcodes", surveyController.GetSurveyQRCodes)
 			surveys.GET("/:surveyId/wechat/share-info", surveyController.GetSurveyWeChatShareInfo)
 			surveys.POST("/wechat/qrcodes/:qrCodeId/revoke", surveyController.RevokeSurveyQRCode)
+
+			// Branching logic (skip/show/jump/end rules)
+			surveys.GET("/:surveyId/logic", surveyLogicController.GetSurveyLogic)
+			surveys.POST("/:surveyId/logic", surveyLogicController.CreateSurveyLogic)
+			surveys.PUT("/logic/:logicId", surveyLogicController.UpdateSurveyLogic)
+			surveys.DELETE("/logic/:logicId", surveyLogicController.DeleteSurveyLogic)
+
+			// Answer piping between questions
+			surveys.GET("/:surveyId/piping", surveyPipingController.GetSurveyPiping)
+			surveys.POST("/:surveyId/piping", surveyPipingController.CreateSurveyPiping)
+			surveys.PUT("/piping/:pipingId", surveyPipingController.UpdateSurveyPiping)
+			surveys.DELETE("/piping/:pipingId", surveyPipingController.DeleteSurveyPiping)
+
+			// Response quotas
+			surveys.GET("/:surveyId/quotas", surveyQuotaController.GetSurveyQuotas)
+			surveys.POST("/:surveyId/quotas", surveyQuotaController.CreateSurveyQuota)
+			surveys.PUT("/quotas/:quotaId", surveyQuotaController.UpdateSurveyQuota)
+			surveys.DELETE("/quotas/:quotaId", surveyQuotaController.DeleteSurveyQuota)
+
+			// Response collectors
+			surveys.GET("/:surveyId/collectors", surveyCollectorController.GetSurveyCollectors)
+			surveys.POST("/:surveyId/collectors", surveyCollectorController.CreateSurveyCollector)
+			surveys.PUT("/collectors/:collectorId", surveyCollectorController.UpdateSurveyCollector)
+			surveys.DELETE("/collectors/:collectorId", surveyCollectorController.DeleteSurveyCollector)
+			surveys.POST("/collectors/:collectorId/open", surveyCollectorController.OpenSurveyCollector)
+			surveys.POST("/collectors/:collectorId/close", surveyCollectorController.CloseSurveyCollector)
+			surveys.POST("/collectors/:collectorId/qrcode", surveyCollectorController.GenerateSurveyCollectorQRCode)
+
+			// Response notifications
+			surveys.GET("/:surveyId/notifications", surveyNotificationController.GetSurveyNotifications)
+			surveys.POST("/:surveyId/notifications", surveyNotificationController.CreateSurveyNotification)
+			surveys.PUT("/notifications/:notificationId", surveyNotificationController.UpdateSurveyNotification)
+			surveys.DELETE("/notifications/:notificationId", surveyNotificationController.DeleteSurveyNotification)
+			surveys.GET("/notifications/:notificationId/deliveries", surveyNotificationController.GetSurveyNotificationDeliveries)
+
+			// Result exports
+			surveys.GET("/:surveyId/exports", surveyExportController.GetSurveyExports)
+			surveys.POST("/:surveyId/exports", surveyExportController.CreateSurveyExport)
+			surveys.GET("/exports/:exportId", surveyExportController.GetSurveyExport)
+			surveys.GET("/exports/:exportId/download", surveyExportController.DownloadSurveyExport)
+
+			// Files uploaded by respondents
+			surveys.GET("/:surveyId/files/:questionId/:responseId/:fileName", surveyFileController.DownloadSurveyFile)
+
+			// Result analytics
+			surveys.GET("/:surveyId/analytics", surveyAnalyticsController.GetSurveyAnalytics)
+			surveys.GET("/:surveyId/analytics/crosstab", surveyAnalyticsController.GetSurveyCrossTab)
+
+			// Invitations
+			surveys.GET("/:surveyId/invitations", surveyInvitationController.GetSurveyInvitations)
+			surveys.POST("/:surveyId/invitations", surveyInvitationController.CreateSurveyInvitations)
+			surveys.POST("/:surveyId/invitations/upload", surveyInvitationController.UploadSurveyInvitations)
+			surveys.POST("/:surveyId/invitations/remind", surveyInvitationController.RemindSurveyInvitees)
+
+			// Save as template
+			surveys.POST("/:surveyId/template", surveyTemplateController.SaveSurveyAsTemplate)
+
+			// Survey versions
+			surveys.POST("/:surveyId/revisions", surveyVersionController.ReviseSurvey)
+			surveys.GET("/:surveyId/versions", surveyVersionController.GetSurveyVersions)
+			surveys.GET("/:surveyId/versions/diff", surveyVersionController.DiffSurveyVersions)
+			surveys.GET("/:surveyId/versions/:version", surveyVersionController.GetSurveyVersion)
+
+			// Survey live polls, run by the presenter during sessions
+			surveys.GET("/:surveyId/live-polls", surveyLivePollController.GetSurveyLivePolls)
+			surveys.POST("/:surveyId/live-polls", surveyLivePollController.OpenLivePoll)
+			surveys.POST("/live-polls/:pollId/close", surveyLivePollController.CloseLivePoll)
+			surveys.GET("/live-polls/:pollId/results", surveyLivePollController.GetLivePollResults)
+		}
+
+		// Survey template endpoints (protected)
+		surveyTemplates := v1.Group("/survey-templates")
+		surveyTemplates.Use(middleware.AuthMiddleware(infra.Config, infra.Logger))
+		{
+			surveyTemplates.GET("/", surveyTemplateController.GetSurveyTemplates)
+			surveyTemplates.POST("/", surveyTemplateController.CreateSurveyTemplate)
+			surveyTemplates.GET("/:templateId", surveyTemplateController.GetSurveyTemplate)
+			surveyTemplates.PUT("/:templateId", surveyTemplateController.UpdateSurveyTemplate)
+			surveyTemplates.DELETE("/:templateId", surveyTemplateController.DeleteSurveyTemplate)
+			surveyTemplates.POST("/:templateId/surveys", surveyTemplateController.CreateSurveyFromTemplate)
 		}
 
 		// Question management endpoints (protected)
@@ -342,6 +689,28 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 		publicSurveys := v1.Group("/public/surveys")
 		{
 			publicSurveys.GET("/:id", surveyController.GetPublicSurvey)
+			publicSurveys.POST("/:id/navigation", surveyLogicController.GetSurveyNavigation)
+			publicSurveys.POST("/:id/responses", middleware.OptionalAuthMiddleware(infra.Config, infra.Logger), surveyResponseController.StartSurveyResponse)
+		}
+
+		// Survey sessions: resume, save progress and submit (no authentication required)
+		surveySessions := v1.Group("/public/survey-sessions")
+		{
+			surveySessions.GET("/:sessionId", surveyResponseController.GetSurveySession)
+			surveySessions.PUT("/:sessionId", surveyResponseController.SaveSurveyProgress)
+			surveySessions.POST("/:sessionId/submit", surveyResponseController.SubmitSurveyResponse)
+			surveySessions.POST("/:sessionId/files", surveyResponseController.UploadSurveyFile)
+		}
+
+		// Survey invitation links (no authentication required)
+		v1.GET("/public/survey-invitations/:token", surveyInvitationController.OpenSurveyInvitation)
+
+		// Live polls joined with the interaction code of an event (no authentication required)
+		livePolls := v1.Group("/public/live-polls")
+		{
+			livePolls.GET("/:code", surveyLivePollController.GetCurrentLivePoll)
+			livePolls.POST("/:code/votes", middleware.OptionalAuthMiddleware(infra.Config, infra.Logger), surveyLivePollController.SubmitLivePollVote)
+			livePolls.GET("/:code/stream", surveyLivePollController.StreamLivePollResults)
 		}
 
 		// Mobile preview endpoints (no authentication required)
@@ -350,6 +719,7 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 			mobile.GET("/articles/:id", mobileController.GetArticlePreview)
 			mobile.GET("/surveys/:id", mobileController.GetSurveyPreview)
 			mobile.GET("/surveys/:id/participate", mobileController.GetSurveyParticipate)
+			mobile.GET("/live/:code", mobileController.GetLivePoll)
 		}
 
 		// Attendee management endpoints (protected) - Temporarily disabled
@@ -377,6 +747,77 @@ func SetupRoutes(router *gin.Engine, infra *infrastructure.Infrastructure) {
 			users.DELETE("/:openId", wechatUsersController.DeleteWeChatUser)
 		}
 
+		// WeChat custom menu endpoints (protected)
+		wechatMenus := v1.Group("/wechat/menus")
+		wechatMenus.Use(middleware.AuthMiddleware(infra.Config, infra.Logger), middleware.RequireRole("admin"))
+		{
+			wechatMenus.GET("/", wechatMenuController.GetPublishedMenus)
+			wechatMenus.POST("/", wechatMenuController.SaveMenu)
+			wechatMenus.GET("/versions", wechatMenuController.GetMenuVersions)
+			wechatMenus.POST("/rollback", wechatMenuController.RollbackMenu)
+			wechatMenus.POST("/unpublish", wechatMenuController.UnpublishMenu)
+			wechatMenus.GET("/:menuId", wechatMenuController.GetMenu)
+			wechatMenus.DELETE("/:menuId", wechatMenuController.DeleteMenu)
+			wechatMenus.POST("/:menuId/publish", wechatMenuController.PublishMenu)
+		}
+
+		// WeChat follower sync endpoints (protected)
+		wechatFollowers := v1.Group("/wechat/followers")
+		wechatFollowers.Use(middleware.AuthMiddleware(infra.Config, infra.Logger))
+		{
+			wechatFollowers.POST("/sync", followerSyncController.StartSync)
+			wechatFollowers.GET("/syncs", followerSyncController.GetRecentSyncs)
+			wechatFollowers.GET("/syncs/:syncId", followerSyncController.GetSync)
+		}
+
+		// WeChat media endpoints (protected)
+		wechatMedia := v1.Group("/wechat/media")
+		wechatMedia.Use(middleware.AuthMiddleware(infra.Config, infra.Logger), middleware.RequireRole("admin"))
+		{
+			wechatMedia.POST("/", wechatMediaController.UploadMedia)
+			wechatMedia.POST("/site-images/:imageId", wechatMediaController.UploadSiteImage)
+			wechatMedia.GET("/temporary/:mediaId", wechatMediaController.GetTemporaryMedia)
+			wechatMedia.GET("/permanent", wechatMediaController.GetPermanentMedia)
+			wechatMedia.DELETE("/permanent/:mediaId", wechatMediaController.DeletePermanentMedia)
+		}
+
+		// WeChat user tag endpoints (protected)
+		wechatTags := v1.Group("/wechat/tags")
+		wechatTags.Use(middleware.AuthMiddleware(infra.Config, infra.Logger), middleware.RequireRole("admin"))
+		{
+			wechatTags.GET("/", wechatTagController.GetTags)
+			wechatTags.POST("/", wechatTagController.CreateTag)
+			wechatTags.POST("/events/:eventId", wechatTagController.TagEventAttendees)
+			wechatTags.PUT("/:tagId", wechatTagController.UpdateTag)
+			wechatTags.DELETE("/:tagId", wechatTagController.DeleteTag)
+			wechatTags.POST("/:tagId/users", wechatTagController.TagUsers)
+			wechatTags.DELETE("/:tagId/users", wechatTagController.UntagUsers)
+		}
+
+		// WeChat mass message endpoints (protected)
+		wechatMassMessages := v1.Group("/wechat/mass-messages")
+		wechatMassMessages.Use(middleware.AuthMiddleware(infra.Config, infra.Logger), middleware.RequireRole("admin"))
+		{
+			wechatMassMessages.GET("/", wechatMassMessageController.GetRecentMessages)
+			wechatMassMessages.POST("/", wechatMassMessageController.CreateMessage)
+			wechatMassMessages.GET("/:messageId", wechatMassMessageController.GetMessage)
+			wechatMassMessages.POST("/:messageId/preview", wechatMassMessageController.PreviewMessage)
+			wechatMassMessages.POST("/:messageId/send", wechatMassMessageController.SendMessage)
+			wechatMassMessages.POST("/:messageId/refresh", wechatMassMessageController.RefreshStatus)
+		}
+
+		// WeChat template campaign endpoints (protected)
+		wechatTemplateCampaigns := v1.Group("/wechat/template-campaigns")
+		wechatTemplateCampaigns.Use(middleware.AuthMiddleware(infra.Config, infra.Logger), middleware.RequireRole("admin"))
+		{
+			wechatTemplateCampaigns.GET("/", wechatTemplateCampaignController.GetRecentCampaigns)
+			wechatTemplateCampaigns.POST("/", wechatTemplateCampaignController.CreateCampaign)
+			wechatTemplateCampaigns.GET("/:campaignId", wechatTemplateCampaignController.GetCampaign)
+			wechatTemplateCampaigns.GET("/:campaignId/deliveries", wechatTemplateCampaignController.GetDeliveries)
+			wechatTemplateCampaigns.POST("/:campaignId/start", wechatTemplateCampaignController.StartCampaign)
+			wechatTemplateCampaigns.POST("/:campaignId/cancel", wechatTemplateCampaignController.CancelCampaign)
+		}
+
 		// Image management endpoints (commented out due to missing controllers)
 		// images := v1.Group("/images")
 		// images.Use(middleware.AuthMiddleware(infra.Config, infra.Logger))
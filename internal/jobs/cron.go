@@ -8,16 +8,24 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	domainServices "github.com/zenteam/nextevent-go/internal/domain/services"
 	"github.com/zenteam/nextevent-go/internal/infrastructure/wechat"
 )
 
 // CronScheduler manages periodic tasks using cron
 type CronScheduler struct {
-	cron          *cron.Cron
-	scheduler     JobScheduler
-	newsRepo      repositories.NewsRepository
-	newsPublisher *wechat.NewsPublisher
-	logger        *zap.Logger
+	cron            *cron.Cron
+	scheduler       JobScheduler
+	newsRepo        repositories.NewsRepository
+	newsPublisher   *wechat.NewsPublisher
+	surveyAnalytics domainServices.SurveyAnalyticsService
+	invitations     domainServices.SurveyInvitationService
+	notifications   domainServices.SurveyNotificationService
+	exports         domainServices.SurveyExportService
+	surveyFiles     domainServices.SurveyFileService
+	followerSync    domainServices.WeChatFollowerSyncService
+	campaigns       domainServices.WeChatTemplateCampaignService
+	logger          *zap.Logger
 }
 
 // NewCronScheduler creates a new cron scheduler
@@ -25,6 +33,13 @@ func NewCronScheduler(
 	scheduler JobScheduler,
 	newsRepo repositories.NewsRepository,
 	newsPublisher *wechat.NewsPublisher,
+	surveyAnalytics domainServices.SurveyAnalyticsService,
+	invitations domainServices.SurveyInvitationService,
+	notifications domainServices.SurveyNotificationService,
+	exports domainServices.SurveyExportService,
+	surveyFiles domainServices.SurveyFileService,
+	followerSync domainServices.WeChatFollowerSyncService,
+	campaigns domainServices.WeChatTemplateCampaignService,
 	logger *zap.Logger,
 ) *CronScheduler {
 	// Create cron with second precision and logging
@@ -38,11 +53,18 @@ func NewCronScheduler(
 	)
 
 	return &CronScheduler{
-		cron:          c,
-		scheduler:     scheduler,
-		newsRepo:      newsRepo,
-		newsPublisher: newsPublisher,
-		logger:        logger,
+		cron:            c,
+		scheduler:       scheduler,
+		newsRepo:        newsRepo,
+		newsPublisher:   newsPublisher,
+		surveyAnalytics: surveyAnalytics,
+		invitations:     invitations,
+		notifications:   notifications,
+		exports:         exports,
+		surveyFiles:     surveyFiles,
+		followerSync:    followerSync,
+		campaigns:       campaigns,
+		logger:          logger,
 	}
 }
 
@@ -96,11 +118,46 @@ func (cs *CronScheduler) registerJobs() {
 		cs.processNewsAnalytics()
 	})
 
+	// Refresh survey analytics every 10 minutes
+	cs.cron.AddFunc("0 */10 * * * *", func() {
+		cs.refreshSurveyAnalytics()
+	})
+
+	// Remind survey invitees who have not responded every 15 minutes
+	cs.cron.AddFunc("0 */15 * * * *", func() {
+		cs.sendSurveyInvitationReminders()
+	})
+
+	// Notify closed surveys and retry failed survey notifications every minute
+	cs.cron.AddFunc("0 * * * * *", func() {
+		cs.processSurveyNotifications()
+	})
+
+	// Delete the files of expired survey exports every hour
+	cs.cron.AddFunc("0 30 * * * *", func() {
+		cs.deleteExpiredSurveyExports()
+	})
+
+	// Delete the files uploaded for abandoned survey responses every day at 4 AM
+	cs.cron.AddFunc("0 0 4 * * *", func() {
+		cs.deleteAbandonedSurveyFiles()
+	})
+
 	// Health check every 30 seconds
 	cs.cron.AddFunc("*/30 * * * * *", func() {
 		cs.healthCheck()
 	})
 
+	// WeChat: Reconcile followers every day at 3 AM, resuming an interrupted sync
+	cs.cron.AddFunc("0 0 3 * * *", func() {
+		cs.syncWeChatFollowers()
+	})
+
+	// WeChat: Resume template campaigns whose runs stopped being handled every 15 minutes
+	cs.cron.AddFunc("0 */15 * * * *", func() {
+		cs.resumeStalledWeChatTemplateCampaigns()
+	})
+
 	// Cleanup old completed jobs every day at 2 AM
 	cs.cron.AddFunc("0 0 2 * * *", func() {
 		cs.cleanupOldJobs()
@@ -210,6 +267,123 @@ func (cs *CronScheduler) processNewsAnalytics() {
 	// 4. Update recommendation algorithms
 }
 
+// refreshSurveyAnalytics recalculates the analytics of the surveys open for responses
+func (cs *CronScheduler) refreshSurveyAnalytics() {
+	if cs.surveyAnalytics == nil {
+		cs.logger.Debug("Survey analytics service not configured, skipping")
+		return
+	}
+
+	refreshed, err := cs.surveyAnalytics.RefreshActiveSurveys(context.Background())
+	if err != nil {
+		cs.logger.Error("Failed to refresh survey analytics", zap.Error(err))
+		return
+	}
+
+	cs.logger.Debug("Survey analytics refreshed", zap.Int("surveys", refreshed))
+}
+
+// sendSurveyInvitationReminders expires overdue survey invitations and reminds non-responders
+func (cs *CronScheduler) sendSurveyInvitationReminders() {
+	if cs.invitations == nil {
+		cs.logger.Debug("Survey invitation service not configured, skipping")
+		return
+	}
+
+	reminded, err := cs.invitations.SendReminders(context.Background())
+	if err != nil {
+		cs.logger.Error("Failed to send survey invitation reminders", zap.Error(err))
+		return
+	}
+
+	cs.logger.Debug("Survey invitation reminders sent", zap.Int("reminded", reminded))
+}
+
+// processSurveyNotifications dispatches the notifications of surveys that closed and retries failed deliveries
+func (cs *CronScheduler) processSurveyNotifications() {
+	if cs.notifications == nil {
+		cs.logger.Debug("Survey notification service not configured, skipping")
+		return
+	}
+
+	ctx := context.Background()
+	closed, err := cs.notifications.NotifyClosedSurveys(ctx)
+	if err != nil {
+		cs.logger.Error("Failed to notify closed surveys", zap.Error(err))
+	}
+	sent, err := cs.notifications.RetryDeliveries(ctx)
+	if err != nil {
+		cs.logger.Error("Failed to retry survey notifications", zap.Error(err))
+		return
+	}
+
+	cs.logger.Debug("Survey notifications processed", zap.Int("closedSurveys", closed), zap.Int("retriesSent", sent))
+}
+
+// deleteExpiredSurveyExports deletes the files of survey exports that can no longer be downloaded
+func (cs *CronScheduler) deleteExpiredSurveyExports() {
+	if cs.exports == nil {
+		cs.logger.Debug("Survey export service not configured, skipping")
+		return
+	}
+
+	deleted, err := cs.exports.DeleteExpiredExportFiles(context.Background())
+	if err != nil {
+		cs.logger.Error("Failed to delete expired survey export files", zap.Error(err))
+		return
+	}
+
+	cs.logger.Debug("Expired survey export files deleted", zap.Int("deleted", deleted))
+}
+
+// deleteAbandonedSurveyFiles deletes the files uploaded for survey responses that will not be submitted
+func (cs *CronScheduler) deleteAbandonedSurveyFiles() {
+	if cs.surveyFiles == nil {
+		cs.logger.Debug("Survey file service not configured, skipping")
+		return
+	}
+
+	deleted, err := cs.surveyFiles.DeleteAbandonedFiles(context.Background())
+	if err != nil {
+		cs.logger.Error("Failed to delete abandoned survey files", zap.Error(err))
+		return
+	}
+
+	cs.logger.Debug("Abandoned survey files deleted", zap.Int("deleted", deleted))
+}
+
+// syncWeChatFollowers queues the WeChat follower sync
+func (cs *CronScheduler) syncWeChatFollowers() {
+	if cs.followerSync == nil {
+		cs.logger.Debug("WeChat follower sync service not configured, skipping")
+		return
+	}
+
+	followerSync, err := cs.followerSync.StartSync(context.Background())
+	if err != nil {
+		cs.logger.Error("Failed to start WeChat follower sync", zap.Error(err))
+		return
+	}
+
+	cs.logger.Info("WeChat follower sync started", zap.String("syncID", followerSync.ID.String()))
+}
+
+// resumeStalledWeChatTemplateCampaigns queues the runs of WeChat template campaigns left sending
+func (cs *CronScheduler) resumeStalledWeChatTemplateCampaigns() {
+	if cs.campaigns == nil {
+		cs.logger.Debug("WeChat template campaign service not configured, skipping")
+		return
+	}
+
+	resumed, err := cs.campaigns.ResumeStalledCampaigns(context.Background())
+	if err != nil {
+		cs.logger.Error("Failed to resume stalled WeChat template campaigns", zap.Error(err))
+		return
+	}
+
+	cs.logger.Debug("Stalled WeChat template campaigns resumed", zap.Int("resumed", resumed))
+}
+
 // healthCheck performs health checks on the job system
 func (cs *CronScheduler) healthCheck() {
 	ctx := context.Background()
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -217,6 +218,86 @@ func (s *AsynqScheduler) EnqueueNewsAnalytics(ctx context.Context, newsID string
 	return s.Enqueue(ctx, task, opts...)
 }
 
+// EnqueueSurveyExport enqueues survey export processing
+func (s *AsynqScheduler) EnqueueSurveyExport(ctx context.Context, exportID uuid.UUID) error {
+	// Create task
+	task, err := NewSurveyExportTask(exportID)
+	if err != nil {
+		return fmt.Errorf("failed to create survey export task: %w", err)
+	}
+
+	// Enqueue the task; large surveys take a while to export
+	opts := []asynq.Option{
+		asynq.Queue("exports"),
+		asynq.MaxRetry(2),
+		asynq.Timeout(30 * time.Minute),
+	}
+
+	return s.Enqueue(ctx, task, opts...)
+}
+
+// EnqueueSurveyNotificationDelivery enqueues a survey notification delivery
+func (s *AsynqScheduler) EnqueueSurveyNotificationDelivery(ctx context.Context, deliveryID uuid.UUID) error {
+	// Create task
+	task, err := NewSurveyNotificationTask(deliveryID)
+	if err != nil {
+		return fmt.Errorf("failed to create survey notification task: %w", err)
+	}
+
+	// Enqueue the task; failed deliveries are retried by the notification service with its own backoff
+	opts := []asynq.Option{
+		asynq.Queue("notifications"),
+		asynq.MaxRetry(0),
+		asynq.Timeout(1 * time.Minute),
+	}
+
+	return s.Enqueue(ctx, task, opts...)
+}
+
+// EnqueueWeChatFollowerSync enqueues a WeChat follower sync, unless the same sync is already queued
+func (s *AsynqScheduler) EnqueueWeChatFollowerSync(ctx context.Context, syncID uuid.UUID) error {
+	// Create task
+	task, err := NewWeChatFollowerSyncTask(syncID)
+	if err != nil {
+		return fmt.Errorf("failed to create WeChat follower sync task: %w", err)
+	}
+
+	// Enqueue the task; a retry resumes the sync from its last checkpoint
+	opts := []asynq.Option{
+		asynq.Queue("wechat"),
+		asynq.MaxRetry(5),
+		asynq.Timeout(2 * time.Hour),
+		asynq.Unique(2 * time.Hour),
+	}
+
+	if err := s.Enqueue(ctx, task, opts...); err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		return err
+	}
+	return nil
+}
+
+// EnqueueWeChatTemplateCampaign enqueues the next run of a WeChat template campaign, after delay
+// when positive. Runs are not unique, as each run queues the next one before it finishes.
+func (s *AsynqScheduler) EnqueueWeChatTemplateCampaign(ctx context.Context, campaignID uuid.UUID, delay time.Duration) error {
+	// Create task
+	task, err := NewWeChatTemplateCampaignTask(campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to create WeChat template campaign task: %w", err)
+	}
+
+	// Schedule the task; a retry sends the deliveries still pending
+	opts := []asynq.Option{
+		asynq.Queue("wechat"),
+		asynq.MaxRetry(5),
+		asynq.Timeout(30 * time.Minute),
+	}
+
+	if delay > 0 {
+		return s.ScheduleIn(ctx, task, delay, opts...)
+	}
+	return s.Enqueue(ctx, task, opts...)
+}
+
 // Helper function to parse UUID
 func parseUUID(s string) (uuid.UUID, error) {
 	return uuid.Parse(s)
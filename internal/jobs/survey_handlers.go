@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	domainServices "github.com/zenteam/nextevent-go/internal/domain/services"
+)
+
+// SurveyJobHandler handles survey background jobs
+type SurveyJobHandler struct {
+	exportService       domainServices.SurveyExportService
+	notificationService domainServices.SurveyNotificationService
+	logger              *zap.Logger
+}
+
+// NewSurveyJobHandler creates a new survey job handler
+func NewSurveyJobHandler(exportService domainServices.SurveyExportService, notificationService domainServices.SurveyNotificationService, logger *zap.Logger) *SurveyJobHandler {
+	return &SurveyJobHandler{
+		exportService:       exportService,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// Register registers the survey job handlers with the worker server
+func (h *SurveyJobHandler) Register(worker *WorkerServer) {
+	worker.HandleFunc(TypeSurveyExport, h.HandleSurveyExport)
+	worker.HandleFunc(TypeSurveyNotification, h.HandleSurveyNotification)
+}
+
+// HandleSurveyExport handles survey export processing
+func (h *SurveyJobHandler) HandleSurveyExport(ctx context.Context, task *asynq.Task) error {
+	var payload SurveyExportPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		h.logger.Error("Failed to unmarshal survey export payload", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	h.logger.Info("Processing survey export",
+		zap.String("exportID", payload.ExportID.String()))
+
+	if err := h.exportService.ProcessExport(ctx, payload.ExportID); err != nil {
+		h.logger.Error("Failed to process survey export",
+			zap.String("exportID", payload.ExportID.String()),
+			zap.Error(err))
+
+		// The export was deleted; retrying will not bring it back
+		if errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("survey export not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to process survey export: %w", err)
+	}
+
+	h.logger.Info("Successfully processed survey export",
+		zap.String("exportID", payload.ExportID.String()))
+
+	return nil
+}
+
+// HandleSurveyNotification handles survey notification delivery. Failed deliveries are recorded
+// and retried by the notification service, so only storage errors fail the task.
+func (h *SurveyJobHandler) HandleSurveyNotification(ctx context.Context, task *asynq.Task) error {
+	var payload SurveyNotificationPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		h.logger.Error("Failed to unmarshal survey notification payload", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	if err := h.notificationService.ProcessDelivery(ctx, payload.DeliveryID); err != nil {
+		h.logger.Error("Failed to process survey notification delivery",
+			zap.String("deliveryID", payload.DeliveryID.String()),
+			zap.Error(err))
+
+		// The notification was deleted together with its deliveries
+		if errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("survey notification delivery not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to process survey notification delivery: %w", err)
+	}
+
+	return nil
+}
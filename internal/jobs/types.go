@@ -16,6 +16,10 @@ const (
 	TypeWeChatDraftCreation    = "wechat:draft_creation"
 	TypeWeChatPublishing       = "wechat:publishing"
 	TypeNewsAnalytics          = "news:analytics"
+	TypeSurveyExport           = "survey:export"
+	TypeSurveyNotification     = "survey:notification"
+	TypeWeChatFollowerSync     = "wechat:follower_sync"
+	TypeWeChatTemplateCampaign = "wechat:template_campaign"
 )
 
 // Job queue names
@@ -60,6 +64,26 @@ type NewsAnalyticsPayload struct {
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// SurveyExportPayload represents the payload for survey export processing
+type SurveyExportPayload struct {
+	ExportID uuid.UUID `json:"exportId"`
+}
+
+// SurveyNotificationPayload represents the payload for survey notification delivery
+type SurveyNotificationPayload struct {
+	DeliveryID uuid.UUID `json:"deliveryId"`
+}
+
+// WeChatFollowerSyncPayload represents the payload for WeChat follower syncs
+type WeChatFollowerSyncPayload struct {
+	SyncID uuid.UUID `json:"syncId"`
+}
+
+// WeChatTemplateCampaignPayload represents the payload for WeChat template campaign runs
+type WeChatTemplateCampaignPayload struct {
+	CampaignID uuid.UUID `json:"campaignId"`
+}
+
 // JobScheduler interface for scheduling jobs
 type JobScheduler interface {
 	// Schedule a job to run at a specific time
@@ -164,3 +188,59 @@ func NewNewsAnalyticsTask(newsID uuid.UUID, eventType string, userID *uuid.UUID,
 
 	return asynq.NewTask(TypeNewsAnalytics, data), nil
 }
+
+// NewSurveyExportTask creates a new survey export task
+func NewSurveyExportTask(exportID uuid.UUID) (*asynq.Task, error) {
+	payload := SurveyExportPayload{
+		ExportID: exportID,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSurveyExport, data), nil
+}
+
+// NewSurveyNotificationTask creates a new survey notification delivery task
+func NewSurveyNotificationTask(deliveryID uuid.UUID) (*asynq.Task, error) {
+	payload := SurveyNotificationPayload{
+		DeliveryID: deliveryID,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeSurveyNotification, data), nil
+}
+
+// NewWeChatFollowerSyncTask creates a new WeChat follower sync task
+func NewWeChatFollowerSyncTask(syncID uuid.UUID) (*asynq.Task, error) {
+	payload := WeChatFollowerSyncPayload{
+		SyncID: syncID,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeWeChatFollowerSync, data), nil
+}
+
+// NewWeChatTemplateCampaignTask creates a new WeChat template campaign task
+func NewWeChatTemplateCampaignTask(campaignID uuid.UUID) (*asynq.Task, error) {
+	payload := WeChatTemplateCampaignPayload{
+		CampaignID: campaignID,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeWeChatTemplateCampaign, data), nil
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	"github.com/zenteam/nextevent-go/internal/domain/repositories"
+	domainServices "github.com/zenteam/nextevent-go/internal/domain/services"
+)
+
+// WeChatJobHandler handles WeChat follower and template campaign background jobs
+type WeChatJobHandler struct {
+	followerSyncService domainServices.WeChatFollowerSyncService
+	campaignService     domainServices.WeChatTemplateCampaignService
+	logger              *zap.Logger
+}
+
+// NewWeChatJobHandler creates a new WeChat job handler
+func NewWeChatJobHandler(
+	followerSyncService domainServices.WeChatFollowerSyncService,
+	campaignService domainServices.WeChatTemplateCampaignService,
+	logger *zap.Logger,
+) *WeChatJobHandler {
+	return &WeChatJobHandler{
+		followerSyncService: followerSyncService,
+		campaignService:     campaignService,
+		logger:              logger,
+	}
+}
+
+// Register registers the WeChat job handlers with the worker server
+func (h *WeChatJobHandler) Register(worker *WorkerServer) {
+	worker.HandleFunc(TypeWeChatFollowerSync, h.HandleFollowerSync)
+	worker.HandleFunc(TypeWeChatTemplateCampaign, h.HandleTemplateCampaign)
+}
+
+// HandleFollowerSync handles WeChat follower syncs. A failed sync keeps its checkpoint, so the
+// retries resume where it stopped.
+func (h *WeChatJobHandler) HandleFollowerSync(ctx context.Context, task *asynq.Task) error {
+	var payload WeChatFollowerSyncPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		h.logger.Error("Failed to unmarshal WeChat follower sync payload", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	if err := h.followerSyncService.RunSync(ctx, payload.SyncID); err != nil {
+		h.logger.Error("Failed to sync WeChat followers",
+			zap.String("syncID", payload.SyncID.String()),
+			zap.Error(err))
+
+		if errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("WeChat follower sync not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to sync WeChat followers: %w", err)
+	}
+
+	return nil
+}
+
+// HandleTemplateCampaign handles WeChat template campaign runs. Deliveries stay pending until
+// sent, so the retries of a failed run resume the campaign.
+func (h *WeChatJobHandler) HandleTemplateCampaign(ctx context.Context, task *asynq.Task) error {
+	var payload WeChatTemplateCampaignPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		h.logger.Error("Failed to unmarshal WeChat template campaign payload", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	if err := h.campaignService.RunCampaign(ctx, payload.CampaignID); err != nil {
+		h.logger.Error("Failed to run WeChat template campaign",
+			zap.String("campaignID", payload.CampaignID.String()),
+			zap.Error(err))
+
+		if errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("WeChat template campaign not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to run WeChat template campaign: %w", err)
+	}
+
+	return nil
+}
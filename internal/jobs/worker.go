@@ -24,11 +24,13 @@ func NewWorkerServer(redisClient *redis.Client, handler JobHandler, logger *zap.
 	cfg := asynq.Config{
 		Concurrency: 10, // Number of concurrent workers
 		Queues: map[string]int{
-			"critical":  6, // High priority queue
-			"news":      3, // News-related jobs
-			"wechat":    2, // WeChat-related jobs
-			"analytics": 1, // Analytics jobs
-			"default":   1, // Default queue
+			"critical":      6, // High priority queue
+			"news":          3, // News-related jobs
+			"wechat":        2, // WeChat-related jobs
+			"analytics":     1, // Analytics jobs
+			"exports":       1, // Survey exports
+			"notifications": 3, // Survey notifications, sent as events happen
+			"default":       1, // Default queue
 		},
 		// Retry configuration
 		RetryDelayFunc: func(n int, e error, t *asynq.Task) time.Duration {
@@ -84,6 +86,11 @@ func (w *WorkerServer) RegisterHandlers() {
 	w.logger.Info("All job handlers registered successfully")
 }
 
+// HandleFunc registers a handler for a task type that is not part of JobHandler
+func (w *WorkerServer) HandleFunc(taskType string, handler func(context.Context, *asynq.Task) error) {
+	w.mux.HandleFunc(taskType, handler)
+}
+
 // Start starts the worker server
 func (w *WorkerServer) Start() error {
 	w.logger.Info("Starting worker server...")
@@ -0,0 +1,4 @@
+-- Rollback: Remove screen_out_message column from survey_quotas table
+
+ALTER TABLE survey_quotas
+DROP COLUMN IF EXISTS screen_out_message;
@@ -0,0 +1,5 @@
+-- Add screen_out_message column to survey_quotas table
+-- Shown to respondents screened out because the quota they fall into is full
+
+ALTER TABLE survey_quotas
+ADD COLUMN IF NOT EXISTS screen_out_message TEXT;
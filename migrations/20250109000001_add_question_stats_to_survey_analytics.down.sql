@@ -0,0 +1,4 @@
+-- Rollback: Remove question_stats column from survey_analytics table
+
+ALTER TABLE survey_analytics
+DROP COLUMN IF EXISTS question_stats;
@@ -0,0 +1,5 @@
+-- Add question_stats column to survey_analytics table
+-- Holds the per-question answer distributions and dropoff calculated by the analytics refresh
+
+ALTER TABLE survey_analytics
+ADD COLUMN IF NOT EXISTS question_stats JSONB;
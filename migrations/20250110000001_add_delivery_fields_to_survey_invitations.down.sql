@@ -0,0 +1,10 @@
+-- Rollback: Remove delivery channel and reminder tracking columns from survey_invitations table
+
+DROP INDEX IF EXISTS idx_survey_invitations_open_id;
+
+ALTER TABLE survey_invitations
+DROP COLUMN IF EXISTS last_error,
+DROP COLUMN IF EXISTS reminded_at,
+DROP COLUMN IF EXISTS reminder_count,
+DROP COLUMN IF EXISTS open_id,
+DROP COLUMN IF EXISTS channel;
@@ -0,0 +1,11 @@
+-- Add delivery channel and reminder tracking columns to survey_invitations table
+-- Invitations are sent by email or as WeChat template messages, and non-responders are reminded
+
+ALTER TABLE survey_invitations
+ADD COLUMN IF NOT EXISTS channel VARCHAR(20) NOT NULL DEFAULT 'email',
+ADD COLUMN IF NOT EXISTS open_id VARCHAR(100),
+ADD COLUMN IF NOT EXISTS reminder_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS last_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_survey_invitations_open_id ON survey_invitations(open_id);
@@ -0,0 +1,9 @@
+-- Rollback: Drop the delivery log and remove delivery channel and conditions from survey_notifications
+
+DROP TABLE IF EXISTS survey_notification_deliveries;
+
+DROP INDEX IF EXISTS idx_survey_notifications_survey_event;
+
+ALTER TABLE survey_notifications
+DROP COLUMN IF EXISTS conditions,
+DROP COLUMN IF EXISTS channel;
@@ -0,0 +1,32 @@
+-- Add delivery channel and conditions to survey_notifications and create the delivery log
+-- Notifications are sent by email, as WeChat template messages or to webhooks, and failed deliveries are retried
+
+ALTER TABLE survey_notifications
+ADD COLUMN IF NOT EXISTS channel VARCHAR(20) NOT NULL DEFAULT 'email',
+ADD COLUMN IF NOT EXISTS conditions JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_survey_notifications_survey_event ON survey_notifications(survey_id, event_type);
+
+CREATE TABLE IF NOT EXISTS survey_notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    notification_id UUID NOT NULL REFERENCES survey_notifications(id) ON DELETE CASCADE,
+    survey_id UUID NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    response_id UUID,
+    event_type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(500) NOT NULL,
+    subject VARCHAR(255),
+    message TEXT,
+    payload JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_notification_deliveries_notification_id ON survey_notification_deliveries(notification_id);
+CREATE INDEX IF NOT EXISTS idx_survey_notification_deliveries_survey_id ON survey_notification_deliveries(survey_id);
+CREATE INDEX IF NOT EXISTS idx_survey_notification_deliveries_next_attempt_at ON survey_notification_deliveries(next_attempt_at);
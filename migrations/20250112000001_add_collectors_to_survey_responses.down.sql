@@ -0,0 +1,13 @@
+-- Rollback: Remove collector attribution from survey_responses and schedules and limits from survey_collectors
+
+DROP INDEX IF EXISTS idx_survey_responses_collector_id;
+
+ALTER TABLE survey_responses
+DROP COLUMN IF EXISTS collector_id;
+
+DROP INDEX IF EXISTS idx_survey_collectors_survey_id;
+
+ALTER TABLE survey_collectors
+DROP COLUMN IF EXISTS max_responses,
+DROP COLUMN IF EXISTS closes_at,
+DROP COLUMN IF EXISTS opens_at;
@@ -0,0 +1,14 @@
+-- Add schedules and response limits to survey_collectors and attribute responses to collectors
+-- Each collector has its own link or QR code, so results can be split by channel
+
+ALTER TABLE survey_collectors
+ADD COLUMN IF NOT EXISTS opens_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS closes_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS max_responses INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_survey_collectors_survey_id ON survey_collectors(survey_id);
+
+ALTER TABLE survey_responses
+ADD COLUMN IF NOT EXISTS collector_id UUID REFERENCES survey_collectors(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_survey_responses_collector_id ON survey_responses(collector_id);
@@ -0,0 +1,14 @@
+-- Rollback: Drop survey versions and the version columns of surveys, questions and responses
+
+DROP TABLE IF EXISTS survey_versions;
+
+ALTER TABLE survey_responses
+DROP COLUMN IF EXISTS survey_version;
+
+DROP INDEX IF EXISTS idx_survey_questions_removed_in_version;
+
+ALTER TABLE survey_questions
+DROP COLUMN IF EXISTS removed_in_version;
+
+ALTER TABLE surveys
+DROP COLUMN IF EXISTS version;
@@ -0,0 +1,28 @@
+-- Add survey versions and record the version each response was answered against
+-- Revising a published survey snapshots it as a new version; removed questions are kept with their answers
+
+ALTER TABLE surveys
+ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE survey_questions
+ADD COLUMN IF NOT EXISTS removed_in_version INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_survey_questions_removed_in_version ON survey_questions(removed_in_version);
+
+ALTER TABLE survey_responses
+ADD COLUMN IF NOT EXISTS survey_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS survey_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    survey_id UUID NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    instructions TEXT,
+    snapshot JSONB NOT NULL,
+    change_note TEXT,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_survey_versions_survey_version ON survey_versions(survey_id, version);
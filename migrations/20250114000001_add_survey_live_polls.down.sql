@@ -0,0 +1,3 @@
+-- Rollback: Drop survey live polls
+
+DROP TABLE IF EXISTS survey_live_polls;
@@ -0,0 +1,20 @@
+-- Add live polls, which put single survey questions to the audience of an event in real time
+-- Votes are counted in Redis while a poll is open; the final tally is stored when it closes
+
+CREATE TABLE IF NOT EXISTS survey_live_polls (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    survey_id UUID NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES survey_questions(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    vote_count INTEGER DEFAULT 0,
+    tally JSONB DEFAULT '{}',
+    opened_by UUID,
+    opened_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_live_polls_survey_id ON survey_live_polls(survey_id);
+CREATE INDEX IF NOT EXISTS idx_survey_live_polls_event_id ON survey_live_polls(event_id, opened_at);
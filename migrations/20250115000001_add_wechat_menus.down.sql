@@ -0,0 +1,3 @@
+-- Rollback: Drop WeChat menus
+
+DROP TABLE IF EXISTS wechat_menus;
@@ -0,0 +1,21 @@
+-- Add versioned WeChat custom menus; each save records a new version so a menu can be rolled back
+-- Menus without a tag are the default menu, menus with a tag are conditional menus for its followers
+
+CREATE TABLE IF NOT EXISTS wechat_menus (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    tag_id VARCHAR(50) NOT NULL DEFAULT '',
+    version INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    definition JSON NOT NULL,
+    change_note TEXT,
+    wechat_menu_id VARCHAR(50),
+    published_at DATETIME(6) NULL,
+    published_by CHAR(36),
+    created_by CHAR(36),
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+
+    UNIQUE INDEX idx_wechat_menus_tag_version (tag_id, version),
+    INDEX idx_wechat_menus_status (status)
+);
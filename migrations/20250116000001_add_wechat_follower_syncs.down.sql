@@ -0,0 +1,3 @@
+-- Rollback: Drop WeChat follower syncs
+
+DROP TABLE IF EXISTS wechat_follower_syncs;
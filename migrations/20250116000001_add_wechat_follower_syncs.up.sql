@@ -0,0 +1,21 @@
+-- Add WeChat follower syncs; each run checkpoints its position in the follower list so an
+-- interrupted run resumes where it stopped
+
+CREATE TABLE IF NOT EXISTS wechat_follower_syncs (
+    id CHAR(36) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    next_open_id VARCHAR(64),
+    last_open_id VARCHAR(64),
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    created INT NOT NULL DEFAULT 0,
+    updated INT NOT NULL DEFAULT 0,
+    unfollowed INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    started_at DATETIME(6) NOT NULL,
+    finished_at DATETIME(6) NULL,
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+
+    INDEX idx_wechat_follower_syncs_status (status, started_at)
+);
@@ -0,0 +1,3 @@
+-- Rollback: Drop WeChat media
+
+DROP TABLE IF EXISTS wechat_media;
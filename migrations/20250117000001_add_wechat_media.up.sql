@@ -0,0 +1,21 @@
+-- Add WeChat media; uploads are recorded by content hash so the same content is not uploaded again
+-- while WeChat keeps it, and media uploaded for a site image is mapped to it
+
+CREATE TABLE IF NOT EXISTS wechat_media (
+    id CHAR(36) PRIMARY KEY,
+    site_image_id CHAR(36),
+    media_type VARCHAR(20) NOT NULL,
+    permanent BOOLEAN NOT NULL DEFAULT FALSE,
+    media_id VARCHAR(128) NOT NULL,
+    url VARCHAR(500),
+    content_hash VARCHAR(64) NOT NULL,
+    file_name VARCHAR(255),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    expires_at DATETIME(6) NULL,
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+
+    INDEX idx_wechat_media_content_hash (content_hash, media_type, permanent, created_at),
+    INDEX idx_wechat_media_site_image (site_image_id, media_type, permanent, created_at),
+    INDEX idx_wechat_media_media_id (media_id)
+);
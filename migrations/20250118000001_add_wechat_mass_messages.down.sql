@@ -0,0 +1,3 @@
+-- Rollback: Drop WeChat mass messages
+
+DROP TABLE IF EXISTS wechat_mass_messages;
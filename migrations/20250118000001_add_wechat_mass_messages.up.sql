@@ -0,0 +1,30 @@
+-- Add WeChat mass messages; broadcasts to a user tag or a list of followers, with the outcome
+-- WeChat reports when the sending job finishes
+
+CREATE TABLE IF NOT EXISTS wechat_mass_messages (
+    id CHAR(36) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    msg_type VARCHAR(20) NOT NULL,
+    content TEXT,
+    media_id VARCHAR(128),
+    tag_id INT,
+    recipients JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    msg_id BIGINT NOT NULL DEFAULT 0,
+    msg_data_id BIGINT NOT NULL DEFAULT 0,
+    wechat_status VARCHAR(50),
+    total_count INT NOT NULL DEFAULT 0,
+    filter_count INT NOT NULL DEFAULT 0,
+    sent_count INT NOT NULL DEFAULT 0,
+    error_count INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    previewed_at DATETIME(6) NULL,
+    sent_at DATETIME(6) NULL,
+    finished_at DATETIME(6) NULL,
+    created_by CHAR(36),
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+
+    INDEX idx_wechat_mass_messages_msg_id (msg_id),
+    INDEX idx_wechat_mass_messages_created_at (created_at)
+);
@@ -0,0 +1,4 @@
+-- Rollback: Drop WeChat template campaigns
+
+DROP TABLE IF EXISTS wechat_template_deliveries;
+DROP TABLE IF EXISTS wechat_template_campaigns;
@@ -0,0 +1,50 @@
+-- Add WeChat template campaigns; a template message rendered for each follower of an audience,
+-- with one delivery per follower tracking the outcome WeChat reports
+
+CREATE TABLE IF NOT EXISTS wechat_template_campaigns (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    template_id VARCHAR(128) NOT NULL,
+    url VARCHAR(1000),
+    fields JSON NOT NULL,
+    audience VARCHAR(30) NOT NULL,
+    event_id CHAR(36),
+    survey_id CHAR(36),
+    tag_id INT,
+    checked_in_only BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    total INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    resolved_at DATETIME(6) NULL,
+    started_at DATETIME(6) NULL,
+    completed_at DATETIME(6) NULL,
+    created_by CHAR(36),
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+
+    INDEX idx_wechat_template_campaigns_event_id (event_id),
+    INDEX idx_wechat_template_campaigns_created_at (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS wechat_template_deliveries (
+    id CHAR(36) PRIMARY KEY,
+    campaign_id CHAR(36) NOT NULL,
+    open_id VARCHAR(64) NOT NULL,
+    wechat_user_id CHAR(36),
+    attendee_id CHAR(36),
+    data JSON NOT NULL,
+    url VARCHAR(1000),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    msg_id BIGINT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    sent_at DATETIME(6) NULL,
+    finished_at DATETIME(6) NULL,
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+
+    UNIQUE INDEX idx_wechat_template_delivery_recipient (campaign_id, open_id),
+    INDEX idx_wechat_template_deliveries_status (campaign_id, status),
+    INDEX idx_wechat_template_deliveries_msg_id (msg_id),
+    CONSTRAINT fk_wechat_template_deliveries_campaign FOREIGN KEY (campaign_id) REFERENCES wechat_template_campaigns(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Rollback: Remove registration fields from EventAttendances and capacity from SiteEvents
+
+ALTER TABLE SiteEvents
+DROP COLUMN Capacity;
+
+-- Drop index first
+DROP INDEX idx_eventattendances_event_status ON EventAttendances;
+
+ALTER TABLE EventAttendances
+DROP COLUMN CancelledAt,
+DROP COLUMN Notes,
+DROP COLUMN Source,
+DROP COLUMN Status;
@@ -0,0 +1,23 @@
+-- Add registration status, source, notes and cancellation time to EventAttendances and capacity to SiteEvents
+-- Registrations beyond an event's capacity are waitlisted and promoted when a place frees up
+
+ALTER TABLE EventAttendances
+ADD COLUMN Status VARCHAR(20) NOT NULL DEFAULT 'registered'
+COMMENT 'registered, waitlisted or cancelled',
+ADD COLUMN Source VARCHAR(20) NULL
+COMMENT 'Where the registration came from: wechat, web or api',
+ADD COLUMN Notes LONGTEXT NULL,
+ADD COLUMN CancelledAt DATETIME(6) NULL;
+
+-- Attendances recorded before registrations had a status hold a place
+UPDATE EventAttendances
+SET Status = 'registered'
+WHERE Status IS NULL OR Status = '';
+
+-- Add index for counting and listing the attendees of an event by status
+CREATE INDEX idx_eventattendances_event_status
+ON EventAttendances(EventId, Status);
+
+ALTER TABLE SiteEvents
+ADD COLUMN Capacity INT NOT NULL DEFAULT 0
+COMMENT 'Maximum registered attendees, 0 means unlimited';
@@ -0,0 +1,5 @@
+-- Rollback: Remove attendee name and company from EventAttendances
+
+ALTER TABLE EventAttendances
+DROP COLUMN Company,
+DROP COLUMN Name;
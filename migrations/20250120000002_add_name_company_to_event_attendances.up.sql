@@ -0,0 +1,8 @@
+-- Add attendee name and company to EventAttendances
+-- Imported attendees are identified by these before they are linked to a WeChat follower
+
+ALTER TABLE EventAttendances
+ADD COLUMN Name VARCHAR(100) NULL
+COMMENT 'Attendee name, e.g. from an import',
+ADD COLUMN Company VARCHAR(200) NULL
+COMMENT 'Attendee company, e.g. from an import';
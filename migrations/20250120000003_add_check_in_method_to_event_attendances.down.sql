@@ -0,0 +1,4 @@
+-- Rollback: Remove the check-in method from EventAttendances
+
+ALTER TABLE EventAttendances
+DROP COLUMN CheckInMethod;
@@ -0,0 +1,6 @@
+-- Add the check-in method to EventAttendances
+-- Check-in trends are split by how attendees were checked in
+
+ALTER TABLE EventAttendances
+ADD COLUMN CheckInMethod VARCHAR(20) NULL
+COMMENT 'wechat_scan, ticket_scan, qr_code or manual';